@@ -0,0 +1,153 @@
+// Package addressbook keeps the watch-only accounts of a node: addresses it
+// holds no keys for, labeled, such as treasury or bridge accounts. The book
+// is a file next to the keystore, read afresh on every call so the node and
+// the keys commands can both change it.
+package addressbook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	cmn "github.com/tendermint/tmlibs/common"
+)
+
+// File is the file, in the home directory of the node, the book is kept in.
+const File = "addressbook.json"
+
+// Entry is a watched address.
+type Entry struct {
+	Address common.Address `json:"address"`
+	Label   string         `json:"label"`
+	Added   time.Time      `json:"added"`
+}
+
+// Book is the address book kept in a file. It is safe for concurrent use.
+type Book struct {
+	mtx  sync.Mutex
+	file string
+}
+
+// Open returns the address book kept in file, which is created on the first
+// change.
+func Open(file string) *Book {
+	return &Book{file: file}
+}
+
+// Watch adds addr under label, relabeling it if it was watched already.
+// Labels are unique and can't look like an address.
+func (b *Book) Watch(addr common.Address, label string) (Entry, error) {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return Entry{}, fmt.Errorf("a label is required")
+	}
+	if common.IsHexAddress(label) {
+		return Entry{}, fmt.Errorf("label %q is an address", label)
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	entries, err := b.load()
+	if err != nil {
+		return Entry{}, err
+	}
+	e := Entry{Address: addr, Label: label, Added: time.Now().UTC()}
+	kept := entries[:0]
+	for _, old := range entries {
+		if old.Address == addr {
+			e.Added = old.Added
+			continue
+		}
+		if strings.EqualFold(old.Label, label) {
+			return Entry{}, fmt.Errorf("label %q is taken by %s", label, old.Address.Hex())
+		}
+		kept = append(kept, old)
+	}
+	return e, b.save(append(kept, e))
+}
+
+// Unwatch removes the address or the label target, false if it wasn't in
+// the book.
+func (b *Book) Unwatch(target string) (bool, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	entries, err := b.load()
+	if err != nil {
+		return false, err
+	}
+	kept := entries[:0]
+	for _, e := range entries {
+		if !e.matches(target) {
+			kept = append(kept, e)
+		}
+	}
+	if len(kept) == len(entries) {
+		return false, nil
+	}
+	return true, b.save(kept)
+}
+
+// List returns the watched addresses sorted by label.
+func (b *Book) List() ([]Entry, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	entries, err := b.load()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Label < entries[j].Label })
+	return entries, nil
+}
+
+// Lookup returns the entry of the address or the label target, false if it
+// isn't in the book.
+func (b *Book) Lookup(target string) (Entry, bool, error) {
+	entries, err := b.List()
+	if err != nil {
+		return Entry{}, false, err
+	}
+	for _, e := range entries {
+		if e.matches(target) {
+			return e, true, nil
+		}
+	}
+	return Entry{}, false, nil
+}
+
+func (e Entry) matches(target string) bool {
+	if common.IsHexAddress(target) {
+		return e.Address == common.HexToAddress(target)
+	}
+	return strings.EqualFold(e.Label, strings.TrimSpace(target))
+}
+
+// load reads the entries of the file, the lock must be held.
+func (b *Book) load() ([]Entry, error) {
+	data, err := ioutil.ReadFile(b.file)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("reading %s: %v", b.file, err)
+	}
+	return entries, nil
+}
+
+// save writes entries to the file, the lock must be held.
+func (b *Book) save(entries []Entry) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return cmn.WriteFileAtomic(b.file, data, 0600)
+}
@@ -0,0 +1,65 @@
+package addressbook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	treasury = common.HexToAddress("0x1000000000000000000000000000000000000001")
+	bridge   = common.HexToAddress("0x2000000000000000000000000000000000000002")
+)
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addressbook")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, File)
+
+	b := Open(file)
+	_, err = b.Watch(treasury, "")
+	assert.NotNil(t, err)
+	_, err = b.Watch(treasury, bridge.Hex())
+	assert.NotNil(t, err)
+	_, err = b.Watch(treasury, "treasury")
+	assert.Nil(t, err)
+	_, err = b.Watch(bridge, "Treasury")
+	assert.NotNil(t, err, "labels are unique")
+	_, err = b.Watch(bridge, "bridge")
+	assert.Nil(t, err)
+
+	// a second book on the file sees the changes
+	entries, err := Open(file).List()
+	require.Nil(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "bridge", entries[0].Label)
+	assert.Equal(t, treasury, entries[1].Address)
+
+	e, ok, err := b.Lookup("TREASURY")
+	require.Nil(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, treasury, e.Address)
+
+	_, err = b.Watch(treasury, "reserve")
+	assert.Nil(t, err)
+	_, ok, _ = b.Lookup("treasury")
+	assert.False(t, ok, "relabeled")
+
+	removed, err := b.Unwatch(bridge.Hex())
+	assert.Nil(t, err)
+	assert.True(t, removed)
+	removed, err = b.Unwatch("bridge")
+	assert.Nil(t, err)
+	assert.False(t, removed)
+
+	entries, err = b.List()
+	require.Nil(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "reserve", entries[0].Label)
+}
@@ -0,0 +1,151 @@
+// Package alerts posts the trouble of a node to webhooks, so operators
+// hear of it without watching logs: a chain that stopped making blocks, an
+// app hash the validators disagree with, a validator jailed, a disk nearly
+// full or too few peers. A Monitor checks the node every interval and
+// fires an alert when a condition starts; it fires again only after the
+// condition cleared.
+package alerts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// kinds of alerts
+const (
+	ConsensusStall  = "consensus_stall"
+	AppHashMismatch = "app_hash_mismatch"
+	ValidatorJailed = "validator_jailed"
+	DiskFull        = "disk_full"
+	DiskFilling     = "disk_filling"
+	LowPeers        = "low_peers"
+)
+
+// webhookTimeout is how long a webhook may take to answer
+const webhookTimeout = 10 * time.Second
+
+// Alert is a trouble of the node.
+type Alert struct {
+	Kind   string    `json:"kind"`
+	Node   string    `json:"node"` // moniker
+	Text   string    `json:"text"`
+	Height int64     `json:"height"` // of the latest block
+	Time   time.Time `json:"time"`
+}
+
+// Webhook delivers alerts to a service.
+type Webhook interface {
+	Post(Alert) error
+}
+
+// HTTPWebhook posts the alerts as json to URL.
+type HTTPWebhook struct {
+	URL string
+}
+
+// Post implements Webhook.
+func (w HTTPWebhook) Post(a Alert) error {
+	return postJSON(w.URL, a)
+}
+
+// SlackWebhook posts the alerts to the incoming webhook URL of a slack
+// channel.
+type SlackWebhook struct {
+	URL string
+}
+
+// Post implements Webhook.
+func (w SlackWebhook) Post(a Alert) error {
+	return postJSON(w.URL, map[string]string{
+		"text": fmt.Sprintf("*%s* on %s at block %d: %s", a.Kind, a.Node, a.Height, a.Text),
+	})
+}
+
+// PagerDutyURL is the events API v2 endpoint of pagerduty.
+var PagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+// PagerDutyWebhook triggers incidents of the events API v2 integration
+// with RoutingKey. Alerts of a kind on a node are grouped in one incident.
+type PagerDutyWebhook struct {
+	RoutingKey string
+}
+
+// Post implements Webhook.
+func (w PagerDutyWebhook) Post(a Alert) error {
+	return postJSON(PagerDutyURL, map[string]interface{}{
+		"routing_key":  w.RoutingKey,
+		"event_action": "trigger",
+		"dedup_key":    a.Node + "/" + a.Kind,
+		"payload": map[string]interface{}{
+			"summary":   fmt.Sprintf("%s: %s", a.Kind, a.Text),
+			"source":    a.Node,
+			"severity":  "critical",
+			"timestamp": a.Time.Format(time.RFC3339),
+			"custom_details": map[string]interface{}{
+				"height": a.Height,
+			},
+		},
+	})
+}
+
+func postJSON(url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	client := http.Client{Timeout: webhookTimeout}
+	res, err := client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	res.Body.Close() // nolint: errcheck
+	if res.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("webhook answered %s", res.Status)
+	}
+	return nil
+}
+
+// Notifier posts alerts to webhooks, in the background so a slow webhook
+// holds back neither the checks nor the others.
+type Notifier struct {
+	node  string
+	hooks []Webhook
+	wg    sync.WaitGroup
+}
+
+// NewNotifier creates a notifier posting the alerts of node to hooks.
+func NewNotifier(node string, hooks ...Webhook) *Notifier {
+	return &Notifier{node: node, hooks: hooks}
+}
+
+// Fire posts an alert of kind to the webhooks.
+func (n *Notifier) Fire(kind string, height int64, format string, args ...interface{}) {
+	a := Alert{
+		Kind:   kind,
+		Node:   n.node,
+		Text:   fmt.Sprintf(format, args...),
+		Height: height,
+		Time:   time.Now().UTC(),
+	}
+	log.Warn("Alert", "kind", a.Kind, "height", a.Height, "text", a.Text)
+	for _, hook := range n.hooks {
+		n.wg.Add(1)
+		go func(hook Webhook) {
+			defer n.wg.Done()
+			if err := hook.Post(a); err != nil {
+				log.Error("Failed to post alert", "kind", a.Kind, "err", err)
+			}
+		}(hook)
+	}
+}
+
+// Wait waits for the alerts being posted.
+func (n *Notifier) Wait() {
+	n.wg.Wait()
+}
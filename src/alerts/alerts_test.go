@@ -0,0 +1,105 @@
+package alerts
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNode struct {
+	height   int64
+	peers    int
+	mismatch bool
+}
+
+func (n *fakeNode) Height() int64         { return n.height }
+func (n *fakeNode) Peers() int            { return n.peers }
+func (n *fakeNode) AppHashMismatch() bool { return n.mismatch }
+
+// receiver collects the alerts posted to its generic webhook
+type receiver struct {
+	mtx    sync.Mutex
+	alerts []Alert
+}
+
+func (r *receiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var a Alert
+	if err := json.NewDecoder(req.Body).Decode(&a); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	r.mtx.Lock()
+	r.alerts = append(r.alerts, a)
+	r.mtx.Unlock()
+}
+
+func (r *receiver) kinds() []string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	kinds := []string{}
+	for _, a := range r.alerts {
+		kinds = append(kinds, a.Kind)
+	}
+	return kinds
+}
+
+func TestMonitor(t *testing.T) {
+	recv := &receiver{}
+	srv := httptest.NewServer(recv)
+	defer srv.Close()
+
+	node := &fakeNode{height: 10, peers: 3}
+	notifier := NewNotifier("node0", HTTPWebhook{URL: srv.URL})
+	m := NewMonitor(Config{StallTimeout: time.Minute, MinPeers: 2}, node, notifier)
+	start := time.Now()
+
+	m.Check(start)
+	node.height = 11
+	m.Check(start.Add(50 * time.Second))
+	notifier.Wait()
+	assert.Empty(t, recv.kinds())
+
+	// fires once while the chain stalls, and again after it recovered
+	m.Check(start.Add(110 * time.Second))
+	m.Check(start.Add(120 * time.Second))
+	node.height = 12
+	m.Check(start.Add(130 * time.Second))
+	m.Check(start.Add(190 * time.Second))
+	notifier.Wait()
+	assert.Equal(t, []string{ConsensusStall, ConsensusStall}, recv.kinds())
+
+	node.height, node.peers, node.mismatch = 13, 1, true
+	m.Check(start.Add(200 * time.Second))
+	notifier.Wait()
+	kinds := recv.kinds()
+	require.Len(t, kinds, 4)
+	sort.Strings(kinds[2:])
+	assert.Equal(t, []string{AppHashMismatch, LowPeers}, kinds[2:])
+
+	for _, a := range recv.alerts {
+		assert.Equal(t, "node0", a.Node)
+		assert.NotEmpty(t, a.Text)
+	}
+}
+
+func TestSlackWebhook(t *testing.T) {
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		json.NewDecoder(req.Body).Decode(&got) // nolint: errcheck
+	}))
+	defer srv.Close()
+
+	err := SlackWebhook{URL: srv.URL}.Post(Alert{Kind: LowPeers, Node: "node0", Height: 5, Text: "0 peers"})
+	require.NoError(t, err)
+	assert.Equal(t, "*low_peers* on node0 at block 5: 0 peers", got["text"])
+
+	srv.Close()
+	assert.Error(t, SlackWebhook{URL: srv.URL}.Post(Alert{}))
+}
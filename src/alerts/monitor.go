@@ -0,0 +1,115 @@
+package alerts
+
+import (
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Node is what the monitor reads of the node.
+type Node interface {
+	Height() int64 // of the latest block
+	Peers() int
+	// AppHashMismatch tells whether the block proposed for the next height
+	// carries another app hash than the one of this node
+	AppHashMismatch() bool
+}
+
+// Config sets when the monitor fires.
+type Config struct {
+	Interval     time.Duration // between checks
+	StallTimeout time.Duration // without a new block, 0 for no check
+	MinPeers     int           // 0 for no check
+	MinFreeDisk  uint64        // bytes free on the disk of Dir, 0 for no check
+	Dir          string
+}
+
+// Monitor checks the node every interval and fires the alerts of the
+// conditions starting.
+type Monitor struct {
+	conf     Config
+	node     Node
+	notifier *Notifier
+
+	height   int64
+	changed  time.Time // when height last changed
+	firing   map[string]bool
+	quit     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewMonitor creates a monitor of node posting to notifier.
+func NewMonitor(conf Config, node Node, notifier *Notifier) *Monitor {
+	return &Monitor{
+		conf:     conf,
+		node:     node,
+		notifier: notifier,
+		firing:   make(map[string]bool),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start checks the node in the background until Stop.
+func (m *Monitor) Start() {
+	go func() {
+		ticker := time.NewTicker(m.conf.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				m.Check(now)
+			case <-m.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the checks.
+func (m *Monitor) Stop() {
+	m.stopOnce.Do(func() { close(m.quit) })
+}
+
+// Check checks the node as of now. It is called by Start, and by tests.
+func (m *Monitor) Check(now time.Time) {
+	height := m.node.Height()
+	if height != m.height || m.changed.IsZero() {
+		m.height, m.changed = height, now
+	}
+
+	if m.conf.StallTimeout > 0 {
+		stalled := now.Sub(m.changed)
+		m.check(ConsensusStall, stalled >= m.conf.StallTimeout,
+			"no new block for %s", stalled.Round(time.Second))
+	}
+	m.check(AppHashMismatch, m.node.AppHashMismatch(),
+		"the block proposed after block %d carries another app hash than this node's, its state diverged", height)
+	if m.conf.MinPeers > 0 {
+		peers := m.node.Peers()
+		m.check(LowPeers, peers < m.conf.MinPeers,
+			"%d peers, fewer than %d", peers, m.conf.MinPeers)
+	}
+	if m.conf.MinFreeDisk > 0 {
+		if free, err := freeDisk(m.conf.Dir); err == nil {
+			m.check(DiskFull, free < m.conf.MinFreeDisk,
+				"%d MB free under %s", free>>20, m.conf.Dir)
+		}
+	}
+}
+
+// check fires the alert of kind if failing and it wasn't before
+func (m *Monitor) check(kind string, failing bool, format string, args ...interface{}) {
+	if failing && !m.firing[kind] {
+		m.notifier.Fire(kind, m.height, format, args...)
+	}
+	m.firing[kind] = failing
+}
+
+// freeDisk returns the bytes free for unprivileged users on the disk of dir
+func freeDisk(dir string) (uint64, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(dir, &fs); err != nil {
+		return 0, err
+	}
+	return fs.Bavail * uint64(fs.Bsize), nil
+}
@@ -4,19 +4,35 @@ import (
 	"fmt"
 	goerr "errors"
 	"math/big"
+	"sync"
 
 	"github.com/cosmos/cosmos-sdk"
 	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/dora/ultron/appwal"
 	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/chaos"
 	"github.com/dora/ultron/const"
 	ultronTypes "github.com/dora/ultron/errors"
+	"github.com/dora/ultron/modules/bank"
+	"github.com/dora/ultron/modules/beacon"
+	"github.com/dora/ultron/modules/community"
+	"github.com/dora/ultron/modules/emergency"
+	"github.com/dora/ultron/modules/names"
+	"github.com/dora/ultron/modules/oracle"
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/modules/recovery"
+	"github.com/dora/ultron/modules/rent"
 	"github.com/dora/ultron/modules/stake"
+	"github.com/dora/ultron/modules/upgrade"
+	"github.com/dora/ultron/modules/wasm"
+	"github.com/dora/ultron/ordering"
+	"github.com/dora/ultron/signer"
 	"github.com/dora/ultron/utils"
 	"github.com/ethereum/go-ethereum/common"
+	ethState "github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth"
 	abci "github.com/tendermint/abci/types"
-	tmTypes "github.com/tendermint/tendermint/types"
 	cmn "github.com/tendermint/tmlibs/common"
 )
 
@@ -26,10 +42,52 @@ type BaseApp struct {
 	EthApp              *EthermintApplication
 	txDispatcher        *TxDispatcher
 	checkedTx           map[common.Hash]*types.Transaction
+	mempoolTxs          *mempoolTxs
+	lanes               *lanes
 	ethereum            *eth.Ethereum
 	LastCommitInfo      abci.LastCommitInfo
 	ByzantineValidators []abci.Evidence
 	Random              *abci.VrfRandom
+
+	// contracts whose storage rent is due, see rent.EndBlock
+	rentTracker *rent.Tracker
+
+	// read by the precompiles while the app runs a block, see usePrecompiles
+	seeds              *beacon.Seeds
+	rates              *oracle.Rates
+	releasePrecompiles func()
+
+	// snapshots of dev chains, see Snapshot
+	snapshots      []*snapshot
+	lastSnapshotID uint64
+
+	// where the plan of an upgrade is written for the supervisor, see
+	// stopForUpgrade
+	upgradeInfoFile string
+
+	// refuses txs, see SetReplica
+	replica bool
+
+	// audits the order of the txs of blocks, see SetOrderingAuditor
+	auditor *ordering.Auditor
+
+	// minted for the block award by the mint module, nil when it is off
+	blockProvision *big.Int
+
+	// owner of the candidate proposing the block, nil if unknown
+	proposer *common.Address
+
+	// held from BeginBlock to the end of Commit, see PauseBlocks
+	blockMtx sync.Mutex
+
+	// write ahead log of the blocks, nil when off, see SetWAL
+	wal       *appwal.WAL
+	walTxs    int             // delivered in the block
+	walReplay []appwal.Record // of the block replayed, as the node wrote them before stopping
+
+	// decodes the txs of the next blocks ahead of them, nil when off, see
+	// SetBlockTxs
+	prefetcher *prefetcher
 }
 
 // BaseApp extends StoreApp, and dispatch tx to different modules via TxDispatcher
@@ -39,12 +97,47 @@ func NewBaseApp(store *StoreApp, ethApp *EthermintApplication, ethereum *eth.Eth
 		EthApp:       ethApp,
 		txDispatcher: NewTxDispatcher(),
 		checkedTx:    make(map[common.Hash]*types.Transaction),
+		mempoolTxs:   newMempoolTxs(),
+		lanes:        newLanes(),
 		ethereum:     ethereum,
+		rentTracker:  rent.NewTracker(),
+		seeds:        beacon.NewSeeds(),
+		rates:        oracle.NewRates(),
 	}
 
 	// register stake tx handler
 	app.txDispatcher.RegisterTxHandler(constant.ModuleNameStake, &stake.StakeTxHandler{})
-
+	// register oracle tx handler, contracts read its rates from the precompile
+	app.txDispatcher.RegisterTxHandler(constant.ModuleNameOracle, &oracle.OracleTxHandler{})
+	app.rates.Load(store.Append())
+	app.seeds.Load(store.Append(), store.CommittedHeight())
+	signer.EnableTypedData(upgrade.Done(store.Append(), signer.TypedDataUpgrade))
+	// register name registry tx handler
+	app.txDispatcher.RegisterTxHandler(constant.ModuleNameNames, &names.NamesTxHandler{})
+	// register native assets tx handler
+	app.txDispatcher.RegisterTxHandler(constant.ModuleNameBank, &bank.BankTxHandler{})
+	// register account recovery tx handler
+	app.txDispatcher.RegisterTxHandler(constant.ModuleNameRecovery, &recovery.RecoveryTxHandler{})
+	// register storage rent tx handler, for its genesis options
+	app.txDispatcher.RegisterTxHandler(constant.ModuleNameRent, &rent.RentTxHandler{})
+	// register wasm contracts tx handler
+	app.txDispatcher.RegisterTxHandler(constant.ModuleNameWasm, &wasm.WasmTxHandler{})
+	// register params tx handler, modules read the params from its cache
+	app.txDispatcher.RegisterTxHandler(constant.ModuleNameParams, &params.ParamsTxHandler{})
+	params.Load(store.Append())
+	// register emergency tx handler, see checkPaused
+	app.txDispatcher.RegisterTxHandler(constant.ModuleNameEmergency, &emergency.EmergencyTxHandler{})
+	// register upgrade tx handler, see BeginBlock
+	app.txDispatcher.RegisterTxHandler(constant.ModuleNameUpgrade, &upgrade.UpgradeTxHandler{})
+	// register community pool tx handler, see payCommunitySpends
+	app.txDispatcher.RegisterTxHandler(constant.ModuleNameCommunity, &community.CommunityTxHandler{})
+	// and the ones of external modules, see RegisterTxHandler
+	registerPlugins(app.txDispatcher)
+
+	// a block the node stopped committing is replayed by tendermint
+	if err := app.rewindUncommitted(); err != nil {
+		return nil, err
+	}
 	return app, nil
 }
 
@@ -56,23 +149,51 @@ func (app *BaseApp) DeliverPtx(txBytes []byte) abci.ResponseDeliverTx {
 		return errors.DeliverResult(err)
 	}
 
+	// the txs of a ptx run together, one that can't run refuses them all
+	txs := ptx.RawTxs()
+	decoded := make([]*types.Transaction, len(txs))
+	for i := 0; i < len(txs); i++ {
+		tx, ok := app.checkedTx[ethereum.TxHash(txs[i])]
+		if !ok {
+			if tx, err = decodeTx(txs[i]); err != nil {
+				app.logger.Error("DeliverTx: Received invalid transaction", "err", err)
+				return errors.DeliverResult(err)
+			}
+		}
+		if err := app.checkSigner(tx, false); err != nil {
+			return errors.DeliverResult(err)
+		}
+		if err := app.checkPaused(tx, false); err != nil {
+			return errors.DeliverResult(err)
+		}
+		if isEthTx(tx) {
+			if err := app.checkPtxFees(tx); err != nil {
+				app.logger.Debug("DeliverTx: Refused parallel transaction", "tx", tx.Hash(), "err", err)
+				return errors.DeliverResult(err)
+			}
+		}
+		decoded[i] = tx
+	}
+
 	//TODO: filter out non-txs (maybe for dpos vote)
 	response := app.EthApp.DeliverPtx(ptx)
 	if response.Code != abci.CodeTypeOK {
 		return response
 	}
 
-	txs := ptx.RawTxs()
-	for i := 0; i < len(txs); i++ {
-		tx, err := decodeTx(txs[i])
-		if err != nil {
-			app.logger.Error("DeliverTx: Received invalid transaction", "err", err)
-			return errors.DeliverResult(err)
-		}
+	for i, tx := range decoded {
+		app.lanes.remove(ethereum.TxHash(txs[i]))
+		app.txIncluded(tx)
 
-		if !isEthTx(tx) {
+		if isEthTx(tx) {
+			// the ptx ran as a whole, its contracts are all recounted
+			from, _ := txSender(tx)
+			app.rentTracker.Executed(tx, from)
+		} else {
 			app.logger.Debug("DeliverTx: Received stake transaction", "tx", tx)
+			release := app.useStakeDB()
 			response = app.txDispatcher.DeliverTx(app, tx)
+			release()
 			if response.Code != abci.CodeTypeOK {
 				break
 			}
@@ -84,33 +205,65 @@ func (app *BaseApp) DeliverPtx(txBytes []byte) abci.ResponseDeliverTx {
 
 // DeliverTx - ABCI
 func (app *BaseApp) DeliverTx(txBytes []byte) abci.ResponseDeliverTx {
+	res := app.deliverTx(txBytes)
+	app.walDeliver(txBytes, res)
+	return res
+}
+
+func (app *BaseApp) deliverTx(txBytes []byte) abci.ResponseDeliverTx {
 	if app.EthApp.IsPtxEnabled() {
 		return app.DeliverPtx(txBytes)
 	}
 
-	tx, err := decodeTx(txBytes)
-	if err != nil {
-		app.logger.Error("DeliverTx: Received invalid transaction", "err", err)
+	// a tx checked by this node was decoded then, it is found by the hash
+	// of its bytes
+	hash := ethereum.TxHash(txBytes)
+	tx, ok := app.checkedTx[hash]
+	if !ok {
+		var err error
+		if tx, err = app.deliveredTx(txBytes); err != nil {
+			app.logger.Error("DeliverTx: Received invalid transaction", "err", err)
+			return errors.DeliverResult(err)
+		}
+	}
+	app.lanes.remove(hash)
+	app.txIncluded(tx)
+	if err := app.checkSigner(tx, false); err != nil {
+		return errors.DeliverResult(err)
+	}
+	if err := app.checkPaused(tx, false); err != nil {
 		return errors.DeliverResult(err)
 	}
 
 	if isEthTx(tx) {
-		if checkedTx, ok := app.checkedTx[tx.Hash()]; ok {
-			tx = checkedTx
-		} else {
+		if !ok {
 			// FIXME: Need to modify ethereum backend to obtain chain ID
 			if _, err := tx.From(types.NewEIP155Signer(constant.ChainId), false); err != nil {
 				app.logger.Debug("DeliverTx: Received invalid transaction", "tx", tx, "err", err)
 				return errors.DeliverResult(err)
 			}
 		}
+		settle, err := app.swapFees(tx, false)
+		if err != nil {
+			app.logger.Debug("DeliverTx: Failed to pay gas in fee token", "tx", tx, "err", err)
+			return errors.DeliverResult(err)
+		}
 		resp := app.EthApp.DeliverTx(tx)
+		if err := settle(resp.Code == abci.CodeTypeOK); err != nil {
+			app.logger.Error("DeliverTx: Failed to settle gas in fee token", "tx", tx.Hash(), "err", err)
+		}
+		if resp.Code == abci.CodeTypeOK {
+			from, _ := txSender(tx)
+			app.rentTracker.Executed(tx, from)
+		}
 		// app.logger.Debug("EthApp DeliverTx response: %v\n", resp)
 		return resp
 	}
 
 	app.logger.Debug("DeliverTx: Received valid transaction", "tx", tx)
 
+	release := app.useStakeDB()
+	defer release()
 	return app.txDispatcher.DeliverTx(app, tx)
 }
 
@@ -149,15 +302,35 @@ func (app *BaseApp) CheckTx(txBytes []byte, local bool) abci.ResponseCheckTx {
 	// if true {
 	// 	return abci.ResponseCheckTx{Code: abci.CodeTypeOK}
 	// }
-	tx, err := decodeTx(txBytes)
+	if chaos.FailCheckTx() {
+		return errors.CheckResult(goerr.New("chaos: injected CheckTx failure"))
+	}
+	if app.replica {
+		return errors.CheckResult(errReplica)
+	}
+	// a tx signed on this node comes with its hash and sender
+	tx, err := ethereum.RecallTx(txBytes)
 	if err != nil {
 		app.logger.Error("CheckTx: Received invalid transaction", "err", err)
 		return errors.CheckResult(err)
 	}
 	app.logger.Debug("CheckTx: Received valid transaction", "tx", tx)
 	//fmt.Println("CheckTx: Received valid transaction", "tx", tx.Nonce())
+	if err := app.checkSigner(tx, true); err != nil {
+		return errors.CheckResult(err)
+	}
+	if err := app.checkPaused(tx, true); err != nil {
+		return errors.CheckResult(err)
+	}
 	hash := tx.Hash()
+	lane := laneOf(tx)
+	if err := app.lanes.check(hash, lane); err != nil {
+		return errors.CheckResult(err)
+	}
 	if isEthTx(tx) {
+		if _, err := app.swapFees(tx, true); err != nil {
+			return errors.CheckResult(err)
+		}
 		resp := app.EthApp.CheckTx(tx)
 		app.logger.Debug("EthApp CheckTx response: %v\n", resp)
 		if resp.IsErr() {
@@ -168,7 +341,10 @@ func (app *BaseApp) CheckTx(txBytes []byte, local bool) abci.ResponseCheckTx {
 		} else {
 			app.EthApp.backend.Ethereum().EventMux().Post(ethereum.TxPreEvent{Tx: tx, Local: local})
 		}
-		app.checkedTx[tx.Hash()] = tx
+		app.checkedTx[hash] = tx
+		app.mempoolTxs.add(hash, txBytes, app.WorkingHeight())
+		app.lanes.add(hash, lane, app.WorkingHeight())
+		app.txArrived(tx)
 		return abci.ResponseCheckTx{0, hash[:], "", 0, 0}
 		// return sdk.NewCheck(tx.Hash(), 0, "").ToABCI()
 	} else if tx != nil {
@@ -177,56 +353,87 @@ func (app *BaseApp) CheckTx(txBytes []byte, local bool) abci.ResponseCheckTx {
 
 	app.logger.Debug("CheckTx: Received valid transaction", "tx", tx)
 
+	release := app.useStakeDB()
 	resp := app.txDispatcher.CheckTx(app, tx)
+	release()
 	resp.Data = hash[:]
 	if !resp.IsErr() {
+		app.mempoolTxs.add(hash, txBytes, app.WorkingHeight())
+		app.lanes.add(hash, lane, app.WorkingHeight())
+		app.txArrived(tx)
 		//Also need post Non-eth transaction
 		app.EthApp.backend.Ethereum().EventMux().Post(ethereum.TxPreEvent{Tx: tx, Local: local})
 	}
 	return resp
 }
 
-// GetTx hash(request hash), from(from type), to(to type)
-// Response is the result, hash or tx: the result can be multi-hash or multi-tx
-func (app *BaseApp) GetTx(req abci.RequestGetTx) (res abci.ResponseGetTx) {
-	// hash := req.GetHash()
-	from := req.GetFrom()
-	to := req.GetTo()
-	if from == tmTypes.ParallelTxHash && to == tmTypes.RawTxHash {
-		// hash is the ptx contains tx hash and check if all tx exists
-		// if dont contain the tx, return the tx hash
-		// and tendermint would get the tx from remote peer
-		// Note: the result can be multi-tx
-	} else if from == tmTypes.RawTx && to == tmTypes.RawTx {
-		// hash is the raw tx and return the tx
-	} else if from == tmTypes.ParallelTxHash && to == tmTypes.ParallelTx {
-		// hash is ptx hash and return the ptx with tx
-	}
-	return abci.ResponseGetTx{Code: abci.CodeTypeOK, Response: nil}
-}
-
 // BeginBlock - ABCI
 func (app *BaseApp) BeginBlock(req abci.RequestBeginBlock) (res abci.ResponseBeginBlock) {
+	app.blockMtx.Lock()
+	app.releasePrecompiles = app.usePrecompiles()
+	app.walBegin(req)
+	if app.prefetcher != nil {
+		app.prefetcher.begin(req.Header.Height)
+	}
+	// run the upgrade scheduled at this block, or stop for a binary that has it
+	plan, err := upgrade.BeginBlock(app.Append(), app.WorkingHeight())
+	if err != nil {
+		panic(err)
+	}
+	if plan != nil {
+		app.stopForUpgrade(*plan)
+	}
+
 	app.EthApp.BeginBlock(req)
+	// the proposer gets the tips of the block on its account
+	tips := upgrade.Done(app.Append(), ProposerTipsUpgrade)
+	if tips {
+		app.EthApp.backend.PayTips()
+	}
+	app.proposer = nil
+	if owner, ok := app.proposerAccount(req.Header.Proposer); ok {
+		if tips {
+			app.EthApp.backend.SetCoinbase(owner)
+		}
+		app.proposer = &owner
+	}
+	app.beginOrdering(req.Header.Proposer)
+	app.beginBlockCall()
+	app.beginMint()
 	app.LastCommitInfo = req.LastCommitInfo
 	app.logger.Info("BeginBlock", "LastCommitInfo", app.LastCommitInfo)
 	app.ByzantineValidators = req.ByzantineValidators
 	app.Random = req.Header.Random
+	var seed []byte
+	if app.Random != nil {
+		seed = app.Random.Seed
+	}
+	app.seeds.Set(app.Append(), app.WorkingHeight(), seed)
+	app.rates.BeginBlock(app.Append())
+	signer.EnableTypedData(upgrade.Done(app.Append(), signer.TypedDataUpgrade))
 
 	return abci.ResponseBeginBlock{}
 }
 
 // EndBlock - ABCI
 func (app *BaseApp) EndBlock(req abci.RequestEndBlock) (res abci.ResponseEndBlock) {
+	app.auditOrdering()
+	app.endBlockCall()
+	if !upgrade.Done(app.Append(), ProposerTipsUpgrade) {
+		app.EthApp.backend.AccumulateRewards(app.EthApp.strategy)
+	}
 	app.EthApp.EndBlock(req)
 	totalUsedGasFee := app.EthApp.GetTotalUsedGasFee()
 
+	release := app.useStakeDB()
+	defer release()
+
 	// accumulate present validators
 	presentValidators := stake.Validators{}
 	for _, vote := range app.LastCommitInfo.Votes {
+		pk := fmt.Sprintf("%X", vote.Validator.PubKey[1:]) //skip type byte
+		candidate := stake.GetCandidateByPubKey(pk)
 		if vote.SignedLastBlock {
-			pk := fmt.Sprintf("%X", vote.Validator.PubKey[1:]) //skip type byte
-			candidate := stake.GetCandidateByPubKey(pk)
 			if candidate != nil {
 				validator := stake.Validator(*candidate)
 				presentValidators = append(presentValidators, validator)
@@ -235,10 +442,30 @@ func (app *BaseApp) EndBlock(req abci.RequestEndBlock) (res abci.ResponseEndBloc
 		} else {
 			app.logger.Debug(cmn.Fmt("absent validator: %v", vote.Validator))
 		}
+
+		// jail the validators missing too many blocks
+		if candidate == nil {
+			continue
+		}
+		stake.TrackSignature(app.Append(), candidate.OwnerAddress, app.WorkingHeight(), vote.SignedLastBlock)
+		if ev := stake.TrackDowntime(app.Append(), candidate, app.WorkingHeight(), vote.SignedLastBlock); ev != nil {
+			app.logger.Error("Jailed validator for downtime", "validator", ev.Validator.Hex(),
+				"missed", ev.MissedBlocks, "until", ev.JailedUntil)
+			app.AddValChange([]*abci.Validator{{PubKey: vote.Validator.PubKey, Power: 0}})
+			app.ethereum.EventMux().Post(*ev) // nolint: errcheck
+		}
 	}
 
-	// block award
-	stake.NewAwardCalculator(app.WorkingHeight(), presentValidators, totalUsedGasFee).AwardAll()
+	app.trackProposal()
+
+	// block award, less the community tax
+	fees, provision := app.communityTax(totalUsedGasFee)
+	stake.NewAwardCalculator(app.WorkingHeight(), presentValidators, fees, provision).AwardAll()
+
+	// pay out matured unbondings
+	for _, err := range stake.EndBlock(app.Append(), app.WorkingHeight(), app.EthApp.backend) {
+		app.logger.Error("Kept unbonding failing to pay out", "err", err)
+	}
 
 	// punish Byzantine validators
 	if len(app.ByzantineValidators) > 0 {
@@ -253,25 +480,76 @@ func (app *BaseApp) EndBlock(req abci.RequestEndBlock) (res abci.ResponseEndBloc
 		app.ByzantineValidators = app.ByzantineValidators[:0]
 	}
 
-	// obtain validator set changes
-	diff, err := stake.UpdateValidatorSet(app.Append(), app.Random.Seed)
-	if err != nil {
-		panic(err)
+	// obtain validator set changes, batched up to the end of the epoch.
+	// Jailed validators were taken out of the set above right away.
+	if stake.EpochEnds(app.Append(), app.WorkingHeight()) {
+		diff, err := stake.UpdateValidatorSet(app.Append(), app.Random.Seed)
+		if err != nil {
+			panic(err)
+		}
+		app.AddValChange(diff)
+	}
+
+	// pay out the community spends due
+	app.payCommunitySpends()
+
+	// close the voting window of the oracle
+	oracle.EndBlock(app.Append(), app.WorkingHeight(), app.rates)
+
+	// settle storage deposits and rent in the state the last block committed
+	db := ethState.NewDatabase(app.ethereum.ChainDb())
+	root := app.ethereum.BlockChain().CurrentBlock().Root()
+	for _, err := range rent.EndBlock(app.Append(), app.WorkingHeight(), app.rentTracker, db, root, app.EthApp.backend) {
+		app.logger.Error("Skipped contract in the rent settlement", "err", err)
 	}
-	app.AddValChange(diff)
 
-	// todo punish those validators who has been absent for up to 3 hours
 	app.EthApp.backend.UpdateProposer()
+	// keep the validator set for the history queries, in the stake db so
+	// the app state doesn't grow with it
+	stake.SaveValidatorChanges(app.WorkingHeight(), app.pending)
 	return app.StoreApp.EndBlock(req)
 }
 
 func (app *BaseApp) Commit() (res abci.ResponseCommit) {
+	defer app.blockMtx.Unlock()
+	defer app.releasePrecompiles()
+	chaos.DelayCommit()
 	app.checkedTx = make(map[common.Hash]*types.Transaction)
-	app.EthApp.Commit()
+	app.mempoolTxs.prune(app.WorkingHeight())
+	app.lanes.prune(app.WorkingHeight())
+	ethRes := app.EthApp.Commit()
+	app.commitBlockHash(ethRes.Data)
+	app.commitSupply()
+	app.walCommit(ethRes.Data)
 	res = app.StoreApp.Commit()
+	// the params changed by the block take effect from the next one
+	params.Load(app.Append())
+	app.markCommitted()
+	app.walCommitted(res.Data)
 	return
 }
 
+// usePrecompiles points the precompiles of the beacon and oracle modules
+// at the seeds and rates of this app until released. Nodes sharing a
+// process run their blocks one at a time.
+func (app *BaseApp) usePrecompiles() (release func()) {
+	releaseSeeds := beacon.Use(app.seeds)
+	releaseRates := oracle.Use(app.rates)
+	return func() {
+		releaseRates()
+		releaseSeeds()
+	}
+}
+
+// PauseBlocks runs f between two blocks, the next one starting once f
+// returned: the databases of the node then agree on the last block
+// committed, as backups copy them.
+func (app *BaseApp) PauseBlocks(f func() error) error {
+	app.blockMtx.Lock()
+	defer app.blockMtx.Unlock()
+	return f()
+}
+
 func (app *BaseApp) InitState(module, key, value string) error {
 	state := app.Append()
 	logger := app.Logger().With("module", module, "key", key)
@@ -286,7 +564,9 @@ func (app *BaseApp) InitState(module, key, value string) error {
 	}
 
 	app.StoreApp.Committed().Set([]byte(key), []byte(value))
+	release := app.useStakeDB()
 	err := app.txDispatcher.InitState(module, key, value, state)
+	release()
 	if err != nil {
 		logger.Error("Invalid genesis option", "err", err)
 	}
@@ -0,0 +1,33 @@
+package app
+
+import (
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// BlockHashesUpgrade is the upgrade from which on the app keeps the hashes
+// of the ethereum blocks in its store, see ethereum.BlockHashKey. It changes
+// the app hash, so the chain switches at the height it is scheduled at.
+const BlockHashesUpgrade = "block-hashes"
+
+func init() {
+	// nothing to migrate, the blocks before it aren't committed to
+	upgrade.RegisterHandler(BlockHashesUpgrade, func(state.SimpleDB) error { return nil })
+}
+
+// commitBlockHash keeps hash, of the ethereum block just committed, in the
+// working state once BlockHashesUpgrade is done.
+func (app *BaseApp) commitBlockHash(hash []byte) {
+	if len(hash) != common.HashLength {
+		return
+	}
+	store := app.Append()
+	if !upgrade.Done(store, BlockHashesUpgrade) {
+		return
+	}
+	number := app.EthApp.backend.Ethereum().BlockChain().CurrentBlock().NumberU64()
+	store.Set(ethereum.BlockHashKey(number), hash)
+}
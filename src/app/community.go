@@ -0,0 +1,34 @@
+package app
+
+import (
+	"math/big"
+
+	"github.com/dora/ultron/modules/community"
+	"github.com/dora/ultron/modules/stake"
+)
+
+// communityTax takes the community tax off the fees and the minted award of
+// the block, and returns what is left of them for the validators.
+func (app *BaseApp) communityTax(fees *big.Int) (*big.Int, *big.Int) {
+	provision := app.blockProvision
+	if provision == nil {
+		provision = stake.InflationAward(app.WorkingHeight())
+	}
+	store := app.Append()
+	return community.Tax(store, fees), community.Tax(store, provision)
+}
+
+// payCommunitySpends pays out the spends of the community pool due at the
+// block.
+func (app *BaseApp) payCommunitySpends() {
+	for _, ev := range community.EndBlock(app.Append(), app.WorkingHeight()) {
+		if ev.Paid {
+			app.logger.Info("Paid community spend", "module", "community", "id", ev.ID,
+				"recipient", ev.Recipient.Hex(), "amount", ev.Amount)
+		} else {
+			app.logger.Error("Dropped community spend, the pool is short of it", "module", "community",
+				"id", ev.ID, "amount", ev.Amount)
+		}
+		app.ethereum.EventMux().Post(ev) // nolint: errcheck
+	}
+}
@@ -0,0 +1,145 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	abci "github.com/tendermint/abci/types"
+	tmTypes "github.com/tendermint/tendermint/types"
+
+	ultronTypes "github.com/dora/ultron/errors"
+)
+
+// mempoolTxBlocks is how many blocks a checked tx is kept for compact
+// blocks, txs still pending after that are rechecked into the cache
+const mempoolTxBlocks = 100
+
+// mempoolTxsSize caps the bytes of the txs kept, the txs checked first are
+// dropped beyond it. It bounds the txs recheck evicted from the mempool,
+// which stay until pruned.
+const mempoolTxsSize = 64 * 1024 * 1024
+
+type mempoolTx struct {
+	raw    []byte
+	height int64
+}
+
+// mempoolTxs keeps the raw txs that passed CheckTx, so the compact blocks of
+// proposers, which only carry tx hashes, can be rebuilt locally. Only the
+// txs missing here are fetched from peers. Delivered txs are kept too until
+// pruned, peers behind may still rebuild the blocks that included them.
+type mempoolTxs struct {
+	mtx     sync.Mutex
+	txs     map[common.Hash]mempoolTx
+	size    int
+	maxSize int
+}
+
+func newMempoolTxs() *mempoolTxs {
+	return &mempoolTxs{txs: make(map[common.Hash]mempoolTx), maxSize: mempoolTxsSize}
+}
+
+func (m *mempoolTxs) add(hash common.Hash, raw []byte, height int64) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.size -= len(m.txs[hash].raw)
+	m.txs[hash] = mempoolTx{raw, height}
+	m.size += len(raw)
+	for m.size > m.maxSize {
+		m.dropOldest()
+	}
+}
+
+// dropOldest drops the txs checked at the lowest height
+func (m *mempoolTxs) dropOldest() {
+	oldest := int64(-1)
+	for _, tx := range m.txs {
+		if oldest < 0 || tx.height < oldest {
+			oldest = tx.height
+		}
+	}
+	for hash, tx := range m.txs {
+		if tx.height == oldest {
+			m.size -= len(tx.raw)
+			delete(m.txs, hash)
+		}
+	}
+}
+
+func (m *mempoolTxs) get(hash common.Hash) ([]byte, bool) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	tx, ok := m.txs[hash]
+	return tx.raw, ok
+}
+
+// prune drops the txs checked mempoolTxBlocks before height
+func (m *mempoolTxs) prune(height int64) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	for hash, tx := range m.txs {
+		if tx.height+mempoolTxBlocks < height {
+			m.size -= len(tx.raw)
+			delete(m.txs, hash)
+		}
+	}
+}
+
+// GetTx - ABCI
+//
+// Tendermint calls it to relay compact blocks, whose ptxs carry tx hashes:
+//   - ParallelTxHash to RawTxHash: req.Hash is a compact ptx, the response
+//     the rlp list of the hashes of its txs this node is missing
+//   - RawTx to RawTx: req.Hash holds 32 byte tx hashes, the response is the
+//     rlp list of their raw txs, to serve the peers missing them
+//   - ParallelTxHash to ParallelTx: req.Hash is a compact ptx, the response
+//     the ptx carrying its raw txs
+func (app *BaseApp) GetTx(req abci.RequestGetTx) (res abci.ResponseGetTx) {
+	from, to := req.GetFrom(), req.GetTo()
+	switch {
+	case from == tmTypes.ParallelTxHash && to == tmTypes.RawTxHash:
+		ptx, err := decodePtx(req.GetHash())
+		if err != nil {
+			return abci.ResponseGetTx{Code: ultronTypes.ErrorTypeEncodingErr}
+		}
+		_, missing := ptx.Expand(app.mempoolTxs.get)
+		return getTxResponse(missing)
+
+	case from == tmTypes.RawTx && to == tmTypes.RawTx:
+		hashes := req.GetHash()
+		if len(hashes)%common.HashLength != 0 {
+			return abci.ResponseGetTx{Code: ultronTypes.ErrorTypeEncodingErr}
+		}
+		txs := make([][]byte, 0, len(hashes)/common.HashLength)
+		for i := 0; i < len(hashes); i += common.HashLength {
+			raw, ok := app.mempoolTxs.get(common.BytesToHash(hashes[i : i+common.HashLength]))
+			if !ok {
+				return abci.ResponseGetTx{Code: ultronTypes.ErrorTypeUnknownRequest}
+			}
+			txs = append(txs, raw)
+		}
+		return getTxResponse(txs)
+
+	case from == tmTypes.ParallelTxHash && to == tmTypes.ParallelTx:
+		ptx, err := decodePtx(req.GetHash())
+		if err != nil {
+			return abci.ResponseGetTx{Code: ultronTypes.ErrorTypeEncodingErr}
+		}
+		full, missing := ptx.Expand(app.mempoolTxs.get)
+		if len(missing) > 0 {
+			app.logger.Debug("GetTx: Compact ptx misses txs", "ptx", ptx.Hash(), "missing", len(missing))
+			return abci.ResponseGetTx{Code: ultronTypes.ErrorTypeUnknownRequest}
+		}
+		return getTxResponse(full)
+	}
+	return abci.ResponseGetTx{Code: ultronTypes.ErrorTypeUnknownRequest}
+}
+
+func getTxResponse(v interface{}) abci.ResponseGetTx {
+	b, err := rlp.EncodeToBytes(v)
+	if err != nil {
+		return abci.ResponseGetTx{Code: ultronTypes.ErrorTypeEncodingErr}
+	}
+	return abci.ResponseGetTx{Code: abci.CodeTypeOK, Response: b}
+}
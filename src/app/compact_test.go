@@ -0,0 +1,84 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+	tmTypes "github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tmlibs/log"
+
+	"github.com/dora/ultron/backend/ethereum"
+	ultronTypes "github.com/dora/ultron/errors"
+)
+
+func compactPtx(t *testing.T, ids ...common.Hash) []byte {
+	b, err := rlp.EncodeToBytes(&ethereum.ParalleledTransactionData{TxIds: ids, Dag: &ethereum.Dag{}})
+	require.Nil(t, err)
+	return b
+}
+
+func TestMempoolTxs(t *testing.T) {
+	m := newMempoolTxs()
+	m.maxSize = 10
+	m.add(common.HexToHash("0x01"), []byte("aaaa"), 1)
+	m.add(common.HexToHash("0x02"), []byte("bbbb"), 2)
+	m.add(common.HexToHash("0x02"), []byte("bbbb"), 3) // rechecked
+	assert.Equal(t, 8, m.size)
+
+	// over the cap, the tx checked first goes
+	m.add(common.HexToHash("0x03"), []byte("cccc"), 3)
+	_, ok := m.get(common.HexToHash("0x01"))
+	assert.False(t, ok)
+	raw, ok := m.get(common.HexToHash("0x02"))
+	assert.True(t, ok)
+	assert.Equal(t, []byte("bbbb"), raw)
+	assert.Equal(t, 8, m.size)
+
+	m.prune(3 + mempoolTxBlocks)
+	_, ok = m.get(common.HexToHash("0x03"))
+	assert.True(t, ok)
+	m.prune(4 + mempoolTxBlocks)
+	assert.Empty(t, m.txs)
+	assert.Equal(t, 0, m.size)
+}
+
+func TestGetTx(t *testing.T) {
+	app := &BaseApp{StoreApp: &StoreApp{logger: log.NewNopLogger()}, mempoolTxs: newMempoolTxs()}
+	a, b, c := common.HexToHash("0x0a"), common.HexToHash("0x0b"), common.HexToHash("0x0c")
+	app.mempoolTxs.add(a, []byte("tx a"), 1)
+	app.mempoolTxs.add(b, []byte("tx b"), 1)
+
+	// the hashes missing from a compact ptx
+	res := app.GetTx(abci.RequestGetTx{From: tmTypes.ParallelTxHash, To: tmTypes.RawTxHash, Hash: compactPtx(t, a, c)})
+	require.Equal(t, abci.CodeTypeOK, res.Code)
+	var missing []common.Hash
+	require.Nil(t, rlp.DecodeBytes(res.Response, &missing))
+	assert.Equal(t, []common.Hash{c}, missing)
+
+	// raw txs served to peers
+	res = app.GetTx(abci.RequestGetTx{From: tmTypes.RawTx, To: tmTypes.RawTx, Hash: append(b.Bytes(), a.Bytes()...)})
+	require.Equal(t, abci.CodeTypeOK, res.Code)
+	var raws [][]byte
+	require.Nil(t, rlp.DecodeBytes(res.Response, &raws))
+	assert.Equal(t, [][]byte{[]byte("tx b"), []byte("tx a")}, raws)
+	res = app.GetTx(abci.RequestGetTx{From: tmTypes.RawTx, To: tmTypes.RawTx, Hash: append(a.Bytes(), c.Bytes()...)})
+	assert.Equal(t, ultronTypes.ErrorTypeUnknownRequest, res.Code)
+	res = app.GetTx(abci.RequestGetTx{From: tmTypes.RawTx, To: tmTypes.RawTx, Hash: a.Bytes()[1:]})
+	assert.Equal(t, ultronTypes.ErrorTypeEncodingErr, res.Code)
+
+	// the ptx expanded, only once no tx is missing
+	res = app.GetTx(abci.RequestGetTx{From: tmTypes.ParallelTxHash, To: tmTypes.ParallelTx, Hash: compactPtx(t, a, c)})
+	assert.Equal(t, ultronTypes.ErrorTypeUnknownRequest, res.Code)
+	res = app.GetTx(abci.RequestGetTx{From: tmTypes.ParallelTxHash, To: tmTypes.ParallelTx, Hash: compactPtx(t, a, b)})
+	require.Equal(t, abci.CodeTypeOK, res.Code)
+	ptx, err := decodePtx(res.Response)
+	require.Nil(t, err)
+	assert.Equal(t, [][]byte{[]byte("tx a"), []byte("tx b")}, ptx.RawTxs())
+
+	res = app.GetTx(abci.RequestGetTx{From: tmTypes.ParallelTxHash, To: tmTypes.ParallelTx, Hash: []byte("junk")})
+	assert.Equal(t, ultronTypes.ErrorTypeEncodingErr, res.Code)
+}
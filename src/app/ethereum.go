@@ -17,14 +17,10 @@ import (
 	"github.com/dora/ultron/backend"
 	emtTypes "github.com/dora/ultron/backend/types"
 	"github.com/dora/ultron/errors"
-	//"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/params"
 	emtConfig "github.com/dora/ultron/node/config"
 )
 
-const (
-	MinGasPrice = 2e9 // 2 Gwei
-)
-
 var checkNonce = true
 
 type FromTo struct {
@@ -223,13 +219,12 @@ func (app *EthermintApplication) BeginBlock(beginBlock abciTypes.RequestBeginBlo
 	return abciTypes.ResponseBeginBlock{}
 }
 
-// EndBlock accumulates rewards for the validators and updates them
+// EndBlock closes the block and returns the validator updates. The ethash
+// block reward is accumulated by BaseApp until ProposerTipsUpgrade.
 // #stable - 0.4.0
 func (app *EthermintApplication) EndBlock(endBlock abciTypes.RequestEndBlock) abciTypes.ResponseEndBlock {
 
 	app.logger.Debug("EndBlock", "height", endBlock.GetHeight()) // nolint: errcheck
-	app.backend.AccumulateRewards(app.strategy)
-
 	app.backend.EndBlock()
 
 	return app.GetUpdatedValidators()
@@ -266,6 +261,31 @@ func (app *EthermintApplication) Commit() abciTypes.ResponseCommit {
 	}
 }
 
+// Rewind drops the ethereum blocks after number, see Backend.Rewind
+func (app *EthermintApplication) Rewind(number uint64) error {
+	if err := app.backend.Rewind(number); err != nil {
+		return err
+	}
+	app.resetCheckState()
+	return nil
+}
+
+// RewindUncommitted drops the ethereum blocks after number, the last one
+// the app committed, see Backend.RewindUncommitted
+func (app *EthermintApplication) RewindUncommitted(number uint64) error {
+	if err := app.backend.RewindUncommitted(number); err != nil {
+		return err
+	}
+	app.resetCheckState()
+	return nil
+}
+
+func (app *EthermintApplication) resetCheckState() {
+	app.checkTxState = app.backend.ManagedState().StateDB
+	app.lowPriceTransactions = make(map[FromTo]*ethTypes.Transaction)
+	app.checkFailedCount = make(map[common.Address]uint64)
+}
+
 // Query queries the state of the EthermintApplication
 // #stable - 0.4.0
 func (app *EthermintApplication) Query(query abciTypes.RequestQuery) abciTypes.ResponseQuery {
@@ -288,10 +308,25 @@ func (app *EthermintApplication) Query(query abciTypes.RequestQuery) abciTypes.R
 	return abciTypes.ResponseQuery{Code: abciTypes.CodeTypeOK, Value: bytes}
 }
 
+// transferCheckState moves ether in the state CheckTx validates against
+func (app *EthermintApplication) transferCheckState(from, to common.Address, amount *big.Int) error {
+	if app.checkTxState.GetBalance(from).Cmp(amount) < 0 {
+		return core.ErrInsufficientFunds
+	}
+	app.checkTxState.SubBalance(from, amount)
+	app.checkTxState.AddBalance(to, amount)
+	return nil
+}
+
 func (app *EthermintApplication) GetTotalUsedGasFee() *big.Int {
 	return app.backend.GetTotalUsedGasFee()
 }
 
+// GetBlockGasUsage returns the gas used by the block ended, and its limit.
+func (app *EthermintApplication) GetBlockGasUsage() (used, limit *big.Int) {
+	return app.backend.GetBlockGasUsage()
+}
+
 //-------------------------------------------------------
 func (app *EthermintApplication) basicValidate(tx *ethTypes.Transaction) (*state.StateDB, common.Address, uint64, abciTypes.ResponseCheckTx) {
 	// Heuristic limit, reject transactions over 32KB to prevent DOS attacks
@@ -379,6 +414,12 @@ func (app *EthermintApplication) validateTx(tx *ethTypes.Transaction) abciTypes.
 				currentBalance, tx.Cost())}
 	}
 
+	if err := ethereum.CheckNewAccount(currentState, tx); err != nil {
+		return abciTypes.ResponseCheckTx{
+			Code: errors.ErrorTypeBaseInvalidInput,
+			Log:  err.Error()}
+	}
+
 	intrGas := core.IntrinsicGas(tx.Data(), tx.To() == nil, true) // homestead == true
 	if tx.Gas().Cmp(intrGas) < 0 {
 		return abciTypes.ResponseCheckTx{
@@ -398,14 +439,14 @@ func (app *EthermintApplication) validateTx(tx *ethTypes.Transaction) abciTypes.
 		to:   to,
 	}
 	if _, ok := app.lowPriceTransactions[ft]; ok {
-		if tx.GasPrice().Cmp(big.NewInt(MinGasPrice)) < 0 {
+		if tx.GasPrice().Cmp(params.BigInt(params.MinGasPrice)) < 0 {
 			// add failed count
 			// this map will keep growing because the nonce check will use it ongoing
 			app.checkFailedCount[from] = app.checkFailedCount[from] + 1
 			return abciTypes.ResponseCheckTx{Code: errors.ErrorTypeLowGasPriceErr, Log: "The gas price is too low for transaction"}
 		}
 	}
-	if tx.GasPrice().Cmp(big.NewInt(MinGasPrice)) < 0 {
+	if tx.GasPrice().Cmp(params.BigInt(params.MinGasPrice)) < 0 {
 		app.lowPriceTransactions[ft] = tx
 	}
 
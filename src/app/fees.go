@@ -0,0 +1,109 @@
+package app
+
+import (
+	goerr "errors"
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/bank"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var errPtxFeeToken = goerr.New("parallel txs must pay gas in ether, not in a fee token")
+
+// feeLedger moves the ether of the fee swaps, see backend.Backend
+type feeLedger interface {
+	Transfer(from, to common.Address, amount *big.Int) error
+	TxGasUsed(hash common.Hash) (*big.Int, bool)
+}
+
+// swapFees lets the sender of tx pay its gas in the fee token it chose, see
+// bank.TxFeeToken. The fee reserve lends the sender the ether of the gas
+// limit, which the EVM charges up front, and settle, called once the tx
+// ran, takes it back: the sender pays the gas used in tokens at the oracle
+// rate and gives the refund of the unused gas back to the reserve. If the
+// tx failed, only the loan is taken back. Parallel txs always pay in ether,
+// see checkPtxFees.
+//
+// CheckTx can't tell the gas used, it swaps the tokens worth the gas limit
+// in the check state and settle does nothing.
+func (app *BaseApp) swapFees(tx *types.Transaction, check bool) (settle func(ran bool) error, err error) {
+	settle = func(bool) error { return nil }
+	from, err := txSender(tx)
+	if err != nil {
+		return settle, err
+	}
+	if check {
+		return settle, swapCheckFees(app.Check(), app.EthApp.transferCheckState, tx, from)
+	}
+	return lendFees(app.Append(), app.EthApp.backend, tx, from)
+}
+
+// checkPtxFees refuses tx, of a ptx, if its sender pays gas in a fee token:
+// the txs of a ptx run in parallel, there is no running one between the loan
+// and the settlement of swapFees.
+func (app *BaseApp) checkPtxFees(tx *types.Transaction) error {
+	from, err := txSender(tx)
+	if err != nil {
+		return err
+	}
+	if bank.FeeDenom(app.Append(), from) != "" && new(big.Int).Mul(tx.Gas(), tx.GasPrice()).Sign() != 0 {
+		return errPtxFeeToken
+	}
+	return nil
+}
+
+func swapCheckFees(store state.SimpleDB, transfer func(from, to common.Address, amount *big.Int) error,
+	tx *types.Transaction, from common.Address) error {
+	denom := bank.FeeDenom(store, from)
+	cost := new(big.Int).Mul(tx.Gas(), tx.GasPrice())
+	if denom == "" || cost.Sign() == 0 {
+		return nil
+	}
+	if err := bank.CanPayFees(store, from, denom, cost); err != nil {
+		return err
+	}
+	if err := transfer(constant.FeeReserveAccount, from, cost); err != nil {
+		return err
+	}
+	return bank.PayFees(store, from, denom, cost)
+}
+
+// lendFees lends from the ether of the gas limit of tx, see swapFees
+func lendFees(store state.SimpleDB, ledger feeLedger, tx *types.Transaction, from common.Address) (
+	settle func(ran bool) error, err error) {
+	settle = func(bool) error { return nil }
+	denom := bank.FeeDenom(store, from)
+	cost := new(big.Int).Mul(tx.Gas(), tx.GasPrice())
+	if denom == "" || cost.Sign() == 0 {
+		return settle, nil
+	}
+	// the tokens must cover the whole gas limit before any is lent
+	if err := bank.CanPayFees(store, from, denom, cost); err != nil {
+		return settle, err
+	}
+	reserve := constant.FeeReserveAccount
+	if err := ledger.Transfer(reserve, from, cost); err != nil {
+		return settle, err
+	}
+
+	return func(ran bool) error {
+		used := new(big.Int)
+		if ran {
+			gas, ok := ledger.TxGasUsed(tx.Hash())
+			if !ok {
+				gas = tx.Gas()
+			}
+			used.Mul(gas, tx.GasPrice())
+		}
+		if err := ledger.Transfer(from, reserve, new(big.Int).Sub(cost, used)); err != nil {
+			return err
+		}
+		if used.Sign() == 0 {
+			return nil
+		}
+		return bank.PayFees(store, from, denom, used)
+	}, nil
+}
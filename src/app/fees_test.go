@@ -0,0 +1,123 @@
+package app
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/bank"
+	"github.com/dora/ultron/modules/oracle"
+	"github.com/dora/ultron/types"
+)
+
+// ledger keeps the ether balances and the gas used by the txs run
+type ledger struct {
+	balances map[common.Address]*big.Int
+	gasUsed  map[common.Hash]*big.Int
+}
+
+func (l *ledger) balance(addr common.Address) *big.Int {
+	if l.balances[addr] == nil {
+		l.balances[addr] = new(big.Int)
+	}
+	return l.balances[addr]
+}
+
+func (l *ledger) Transfer(from, to common.Address, amount *big.Int) error {
+	if l.balance(from).Cmp(amount) < 0 {
+		return fmt.Errorf("insufficient funds")
+	}
+	l.balance(from).Sub(l.balance(from), amount)
+	l.balance(to).Add(l.balance(to), amount)
+	return nil
+}
+
+func (l *ledger) TxGasUsed(hash common.Hash) (*big.Int, bool) {
+	gas, ok := l.gasUsed[hash]
+	return gas, ok
+}
+
+// run charges the gas limit of tx up front and refunds what it didn't use,
+// as the EVM does
+func (l *ledger) run(tx *ethTypes.Transaction, from common.Address, gas int64) {
+	l.balance(from).Sub(l.balance(from), new(big.Int).Mul(tx.Gas(), tx.GasPrice()))
+	l.balance(from).Add(l.balance(from), new(big.Int).Mul(big.NewInt(tx.Gas().Int64()-gas), tx.GasPrice()))
+	l.gasUsed[tx.Hash()] = big.NewInt(gas)
+}
+
+// setupFees gives sender 1000 gold to pay gas in, a gold token is worth a wei
+func setupFees(t *testing.T, sender common.Address) (state.SimpleDB, *ledger) {
+	store := state.NewMemKVStore()
+	h := &bank.BankTxHandler{}
+	require.Nil(t, h.InitState("fee_token", "gold", store))
+	ctx := types.NewContext("test", 1, nil)
+	ctx.WithSigners(sender)
+	_, err := h.DeliverTx(ctx, store, bank.NewTxIssue("gold", 0, "1000", false))
+	require.Nil(t, err)
+	_, err = h.DeliverTx(ctx, store, bank.NewTxFeeToken("gold"))
+	require.Nil(t, err)
+
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(oracle.RateDecimals), nil)
+	rate := oracle.Rate{Denom: "gold", Rate: unit.String(), Height: 1}
+	store.Set(append(append([]byte{}, oracle.RatePrefix...), "gold"...), wire.BinaryBytes(rate))
+
+	l := &ledger{balances: make(map[common.Address]*big.Int), gasUsed: make(map[common.Hash]*big.Int)}
+	l.balance(constant.FeeReserveAccount).SetInt64(1e6)
+	return store, l
+}
+
+func goldOf(store state.SimpleDB, addr common.Address) *big.Int {
+	return new(big.Int).SetBytes(store.Get(bank.BalanceKey("gold", addr)))
+}
+
+func TestLendFeesSettlesGasUsed(t *testing.T) {
+	sender := common.HexToAddress("0x01")
+	store, l := setupFees(t, sender)
+	// 100 gas at 2 wei, 60 used
+	tx := ethTypes.NewTransaction(0, common.HexToAddress("0x02"), new(big.Int), big.NewInt(100), big.NewInt(2), nil)
+
+	settle, err := lendFees(store, l, tx, sender)
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(200), l.balance(sender))
+	l.run(tx, sender, 60)
+	require.Nil(t, settle(true))
+
+	assert.Equal(t, 0, l.balance(sender).Sign())
+	assert.Equal(t, big.NewInt(1e6-120), l.balance(constant.FeeReserveAccount))
+	assert.Equal(t, big.NewInt(1000-120), goldOf(store, sender))
+	assert.Equal(t, big.NewInt(120), goldOf(store, constant.FeeReserveAccount))
+}
+
+func TestLendFeesRevertsFailedTx(t *testing.T) {
+	sender := common.HexToAddress("0x01")
+	store, l := setupFees(t, sender)
+	tx := ethTypes.NewTransaction(0, common.HexToAddress("0x02"), new(big.Int), big.NewInt(100), big.NewInt(2), nil)
+
+	settle, err := lendFees(store, l, tx, sender)
+	require.Nil(t, err)
+	require.Nil(t, settle(false))
+
+	assert.Equal(t, 0, l.balance(sender).Sign())
+	assert.Equal(t, big.NewInt(1e6), l.balance(constant.FeeReserveAccount))
+	assert.Equal(t, big.NewInt(1000), goldOf(store, sender))
+}
+
+func TestLendFeesInsufficientTokens(t *testing.T) {
+	sender := common.HexToAddress("0x01")
+	store, l := setupFees(t, sender)
+	// the gas limit is worth more than the tokens, nothing is lent
+	tx := ethTypes.NewTransaction(0, common.HexToAddress("0x02"), new(big.Int), big.NewInt(1000), big.NewInt(2), nil)
+
+	_, err := lendFees(store, l, tx, sender)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, l.balance(sender).Sign())
+	assert.Equal(t, big.NewInt(1000), goldOf(store, sender))
+}
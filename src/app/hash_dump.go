@@ -0,0 +1,74 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/hashdump"
+	"github.com/dora/ultron/substore"
+)
+
+// DumpBlock reports what this node computed for the last block it
+// committed, whose app hash the block proposed next, carrying proposed,
+// disagrees with. It is to be run between two blocks, see PauseBlocks.
+func (app *BaseApp) DumpBlock(proposed []byte) *hashdump.Report {
+	height := app.CommittedHeight()
+	r := &hashdump.Report{
+		Version:  hashdump.Version,
+		Created:  time.Now().UTC(),
+		Height:   height,
+		AppHash:  app.Hash(),
+		Proposed: proposed,
+	}
+
+	// the ethereum block the wal has for the height, the head otherwise
+	chain := app.ethereum.BlockChain()
+	block := chain.CurrentBlock()
+	if app.wal != nil {
+		records, err := app.wal.Read()
+		if err != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("wal: %v", err))
+		}
+		for _, rec := range records {
+			if rec.Height != height {
+				continue
+			}
+			r.WAL = append(r.WAL, rec)
+			if rec.BlockHash != nil && *rec.BlockHash != block.Hash() {
+				if b := chain.GetBlock(*rec.BlockHash, rec.Block); b != nil {
+					block = b
+				}
+			}
+		}
+	}
+	if err := ethereum.DumpBlock(app.ethereum, block, r); err != nil {
+		r.Errors = append(r.Errors, err.Error())
+	}
+
+	app.dumpStore(r)
+	return r
+}
+
+// dumpStore adds the keys of the app state set at the height of r, keys
+// removed by it aren't found
+func (app *BaseApp) dumpStore(r *hashdump.Report) {
+	tree := app.Committed()
+	defer func() {
+		if rec := recover(); rec != nil {
+			r.Errors = append(r.Errors, fmt.Sprintf("app state: %v", rec))
+		}
+	}()
+	tree.Tree.Iterate(func(key, value []byte) bool {
+		before, _, err := tree.GetVersionedWithProof(key, r.Height-1)
+		if err == nil && bytes.Equal(before, value) {
+			return false
+		}
+		module, _ := substore.Owner(key)
+		r.Store = append(r.Store, hashdump.StoreKey{Key: key, Module: module, ValueHash: crypto.Keccak256Hash(value), Size: len(value)})
+		return false
+	})
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"encoding/json"
+	goerr "errors"
+	"strings"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/dora/ultron/const"
+)
+
+// lanes of the mempool
+const (
+	LaneUser   = "user"
+	LaneSystem = "system"
+)
+
+var errUserLaneFull = goerr.New("mempool: user lane full, try again after the next block")
+
+// systemModules are the modules whose txs keep the chain running: staking,
+// governance and the oracle feeds
+var systemModules = map[string]bool{
+	constant.ModuleNameStake:     true,
+	constant.ModuleNameParams:    true,
+	constant.ModuleNameUpgrade:   true,
+	constant.ModuleNameEmergency: true,
+	constant.ModuleNameOracle:    true,
+	constant.ModuleNameCommunity: true,
+}
+
+// laneOf returns the lane of tx: eth txs and the txs of the other modules
+// are user txs
+func laneOf(tx *types.Transaction) string {
+	if isEthTx(tx) {
+		return LaneUser
+	}
+	var innerTx sdk.Tx
+	if err := json.Unmarshal(tx.Data(), &innerTx); err != nil {
+		return LaneUser // rejected by the tx dispatcher
+	}
+	kind, err := innerTx.GetKind()
+	if err != nil {
+		return LaneUser
+	}
+	if systemModules[strings.SplitN(kind, "/", 2)[0]] {
+		return LaneSystem
+	}
+	return LaneUser
+}
+
+type laneTx struct {
+	lane   string
+	height int64
+}
+
+// lanes admits the txs into the mempool by lane. Tendermint reaps the
+// mempool in the order txs came, so a system tx waits for every tx before
+// it: once the user txs pending fill a block but the reserve, new ones are
+// refused until blocks take some. However many user txs are sent, a system
+// tx is then in the next block, next to them.
+//
+// The txs the mempool drops are only seen by their recheck failing or not
+// coming, so txs not checked since the last block are forgotten at commit.
+// Without recheck pending txs are forgotten after a block, and the user
+// lane admits more.
+type lanes struct {
+	mtx     sync.Mutex
+	userCap int // user txs pending at most, 0 for no cap
+	users   int
+	txs     map[common.Hash]laneTx
+}
+
+func newLanes() *lanes {
+	return &lanes{txs: make(map[common.Hash]laneTx)}
+}
+
+// setCap keeps reserve percent of blocks of maxTxs txs for system txs
+func (l *lanes) setCap(maxTxs int, reserve uint) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if maxTxs <= 0 || reserve == 0 {
+		l.userCap = 0
+		return
+	}
+	if reserve > 100 {
+		reserve = 100
+	}
+	l.userCap = maxTxs * int(100-reserve) / 100
+}
+
+// check fails if tx can't enter lane. A tx already pending, as rechecked
+// after a block, always can.
+func (l *lanes) check(hash common.Hash, lane string) error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if _, ok := l.txs[hash]; ok {
+		return nil
+	}
+	if lane == LaneUser && l.userCap > 0 && l.users >= l.userCap {
+		return errUserLaneFull
+	}
+	return nil
+}
+
+// add counts tx as pending in lane, checked at height
+func (l *lanes) add(hash common.Hash, lane string, height int64) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if _, ok := l.txs[hash]; !ok && lane == LaneUser {
+		l.users++
+	}
+	l.txs[hash] = laneTx{lane, height}
+}
+
+func (l *lanes) remove(hash common.Hash) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.removeLocked(hash)
+}
+
+func (l *lanes) removeLocked(hash common.Hash) {
+	tx, ok := l.txs[hash]
+	if !ok {
+		return
+	}
+	if tx.lane == LaneUser {
+		l.users--
+	}
+	delete(l.txs, hash)
+}
+
+// prune forgets the txs not checked at height
+func (l *lanes) prune(height int64) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	for hash, tx := range l.txs {
+		if tx.height < height {
+			l.removeLocked(hash)
+		}
+	}
+}
+
+// SetLaneReserve keeps reserve percent of blocks of maxTxs txs for the txs
+// of staking, governance and the oracle, see lanes.
+func (app *BaseApp) SetLaneReserve(maxTxs int, reserve uint) {
+	app.lanes.setCap(maxTxs, reserve)
+}
@@ -0,0 +1,30 @@
+package app
+
+import (
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/mint"
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/modules/supply"
+)
+
+// beginMint moves the inflation of the mint module for the block, at the
+// ether bonded and the supply as the last block left them. The provision
+// is paid out with the fees by the block award. The supply leaves out the
+// ether minted and burnt before supply.TotalsUpgrade.
+func (app *BaseApp) beginMint() {
+	app.blockProvision = nil
+	if !params.Bool(mint.Enabled) {
+		return
+	}
+	committed, err := app.ethereum.BlockChain().State()
+	if err != nil {
+		panic(err)
+	}
+	genesis, err := app.EthApp.backend.GenesisSupply()
+	if err != nil {
+		panic(err)
+	}
+	store := app.Append()
+	total := supply.Total(store, genesis)
+	app.blockProvision = mint.BeginBlock(store, committed.GetBalance(constant.StakeAccount), total)
+}
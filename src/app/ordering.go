@@ -0,0 +1,52 @@
+package app
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/dora/ultron/ordering"
+)
+
+// SetOrderingAuditor has the blocks audited against the arrival of their
+// txs, see ordering.
+func (app *BaseApp) SetOrderingAuditor(auditor *ordering.Auditor) {
+	app.auditor = auditor
+}
+
+// txArrived records the arrival of a tx that passed CheckTx
+func (app *BaseApp) txArrived(tx *types.Transaction) {
+	if app.auditor != nil {
+		app.auditor.Arrived(tx.Hash(), time.Now(), app.WorkingHeight())
+	}
+}
+
+// beginOrdering starts the audit of the block proposed by proposer
+func (app *BaseApp) beginOrdering(proposer []byte) {
+	if app.auditor != nil {
+		app.auditor.BeginBlock(app.WorkingHeight(), proposer)
+	}
+}
+
+// txIncluded records the next tx of the block being delivered
+func (app *BaseApp) txIncluded(tx *types.Transaction) {
+	if app.auditor == nil {
+		return
+	}
+	sender, err := txSender(tx)
+	if err != nil {
+		return // the signer checks refuse it
+	}
+	app.auditor.Included(tx.Hash(), sender)
+}
+
+// auditOrdering audits the order of the block delivered
+func (app *BaseApp) auditOrdering() {
+	if app.auditor == nil {
+		return
+	}
+	for _, v := range app.auditor.EndBlock() {
+		app.logger.Info("Tx included after a later one", "module", "ordering", "height", v.Block,
+			"proposer", v.Proposer, "tx", v.Tx.Hex(), "ahead", v.Ahead.Hex(), "gap_ms", uint64(v.Gap))
+	}
+}
@@ -0,0 +1,15 @@
+package app
+
+import (
+	"github.com/dora/ultron/modules/stake"
+)
+
+// trackProposal counts the block for the validator proposing it, with the
+// gas it used, see stake.Performance.
+func (app *BaseApp) trackProposal() {
+	if app.proposer == nil {
+		return
+	}
+	used, limit := app.EthApp.GetBlockGasUsage()
+	stake.TrackProposal(app.Append(), *app.proposer, app.WorkingHeight(), used, limit)
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/types"
+)
+
+var (
+	pluginMtx sync.Mutex
+	plugins   = make(map[string]types.TxHandler)
+
+	// modules of the app itself, see NewBaseApp
+	builtinModules = map[string]bool{
+		constant.ModuleNameStake:     true,
+		constant.ModuleNameOracle:    true,
+		constant.ModuleNameNames:     true,
+		constant.ModuleNameBank:      true,
+		constant.ModuleNameRecovery:  true,
+		constant.ModuleNameRent:      true,
+		constant.ModuleNameWasm:      true,
+		constant.ModuleNameParams:    true,
+		constant.ModuleNameEmergency: true,
+		constant.ModuleNameUpgrade:   true,
+		constant.ModuleNameCommunity: true,
+	}
+)
+
+// RegisterTxHandler adds a module to every app created afterwards: handler
+// gets the txs whose kind is prefixed with name, e.g. "name/send", and the
+// genesis options of the module. The module registers its tx kinds with
+// sdk.TxMapper itself, so a blank import of its package in the node binary
+// is all it takes, much like database/sql drivers:
+//
+//	func init() {
+//		sdk.TxMapper.RegisterImplementation(TxSend{}, "mymodule/send", 0x90)
+//		app.RegisterTxHandler("mymodule", &MyTxHandler{})
+//	}
+//
+// It panics if the name is taken.
+func RegisterTxHandler(name string, handler types.TxHandler) {
+	pluginMtx.Lock()
+	defer pluginMtx.Unlock()
+
+	if builtinModules[name] || plugins[name] != nil {
+		panic(fmt.Sprintf("tx handler %s already registered", name))
+	}
+	if handler == nil {
+		panic(fmt.Sprintf("nil tx handler for %s", name))
+	}
+	plugins[name] = handler
+}
+
+// registerPlugins hands the registered modules to td
+func registerPlugins(td *TxDispatcher) {
+	pluginMtx.Lock()
+	defer pluginMtx.Unlock()
+
+	for name, handler := range plugins {
+		td.RegisterTxHandler(name, handler)
+	}
+}
@@ -0,0 +1,72 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/types"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(pluginTx{}, "testplugin/send", 0xf0)
+}
+
+type pluginTx struct{}
+
+func (tx pluginTx) ValidateBasic() error { return nil }
+func (tx pluginTx) Wrap() sdk.Tx         { return sdk.Tx{tx} }
+
+// pluginHandler takes the genesis options it is given
+type pluginHandler struct {
+	options map[string]string
+}
+
+func (h *pluginHandler) InitState(key, value string, store state.SimpleDB) error {
+	h.options[key] = value
+	return nil
+}
+
+func (h *pluginHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (sdk.CheckResult, error) {
+	return sdk.CheckResult{}, nil
+}
+
+func (h *pluginHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (sdk.DeliverResult, error) {
+	return sdk.DeliverResult{}, nil
+}
+
+func TestRegisterTxHandler(t *testing.T) {
+	h := &pluginHandler{options: make(map[string]string)}
+	RegisterTxHandler("testplugin", h)
+	defer func() {
+		pluginMtx.Lock()
+		delete(plugins, "testplugin")
+		pluginMtx.Unlock()
+	}()
+
+	assert.Panics(t, func() { RegisterTxHandler("testplugin", h) }, "taken")
+	assert.Panics(t, func() { RegisterTxHandler(constant.ModuleNameBank, h) }, "builtin")
+	assert.Panics(t, func() { RegisterTxHandler("other", nil) })
+
+	// the dispatchers created afterwards route to the plugin
+	td := NewTxDispatcher()
+	registerPlugins(td)
+	handler, err := td.getTxHandler(pluginTx{}.Wrap())
+	require.Nil(t, err)
+	assert.Equal(t, h, handler)
+	require.Nil(t, td.InitState("testplugin", "limit", "10", state.NewMemKVStore()))
+	assert.Equal(t, "10", h.options["limit"])
+
+	td.UnregisterTxHandler("testplugin")
+	_, err = td.getTxHandler(pluginTx{}.Wrap())
+	assert.NotNil(t, err, "unknown module")
+	// delivered still, only spending the nonce
+	handler, name, err := td.lookupTxHandler(pluginTx{}.Wrap())
+	require.Nil(t, err)
+	assert.Nil(t, handler)
+	assert.Equal(t, "testplugin", name)
+}
@@ -0,0 +1,151 @@
+package app
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/dora/ultron/backend/ethereum"
+)
+
+const (
+	prefetchBlocks = 16   // blocks read ahead of the one run
+	prefetchQueue  = 4096 // txs read and waiting for a worker
+)
+
+// BlockTxs returns the txs of the block at height, false if the node
+// doesn't have the block yet.
+type BlockTxs func(height int64) ([][]byte, bool)
+
+// SetBlockTxs has the txs of the blocks read from blockTxs as soon as the
+// node has them, decoded and their senders recovered on all the cores
+// while the blocks before them run. DeliverTx then takes them as they are.
+func (app *BaseApp) SetBlockTxs(blockTxs BlockTxs) {
+	app.prefetcher = newPrefetcher(blockTxs, runtime.NumCPU())
+}
+
+// deliveredTx returns the tx whose rlp is txBytes, the one prefetched if
+// any, else decoded
+func (app *BaseApp) deliveredTx(txBytes []byte) (*types.Transaction, error) {
+	if app.prefetcher != nil {
+		if tx, ok := app.prefetcher.tx(txBytes); ok {
+			return tx, nil
+		}
+	}
+	return decodeTx(txBytes)
+}
+
+// prefetcher runs the decoding and the signature checks of the txs of the
+// blocks ahead of their execution. A node catching up runs blocks back to
+// back: the blocks tendermint replays at start are all there to be read
+// ahead, those the blockchain reactor syncs are read one at a time as they
+// are saved, their txs checked in parallel while the first ones run.
+type prefetcher struct {
+	blockTxs BlockTxs
+	heights  chan int64       // of the blocks begun, the latest one wins
+	jobs     chan prefetchJob // the reader waits for the workers when full
+
+	mtx    sync.Mutex
+	txs    map[common.Hash]prefetchedTx // by the hash of their bytes
+	height int64                        // of the block run
+}
+
+type prefetchJob struct {
+	height int64
+	tx     []byte
+}
+
+type prefetchedTx struct {
+	height int64
+	tx     *types.Transaction
+}
+
+func newPrefetcher(blockTxs BlockTxs, workers int) *prefetcher {
+	p := &prefetcher{
+		blockTxs: blockTxs,
+		heights:  make(chan int64, 1),
+		jobs:     make(chan prefetchJob, prefetchQueue),
+		txs:      make(map[common.Hash]prefetchedTx),
+	}
+	go p.read()
+	for i := 0; i < workers; i++ {
+		go p.work()
+	}
+	return p
+}
+
+// begin has the blocks from height on read, and drops the txs of the
+// blocks before it. It is called by BeginBlock only.
+func (p *prefetcher) begin(height int64) {
+	p.mtx.Lock()
+	p.height = height
+	for hash, tx := range p.txs {
+		if tx.height < height {
+			delete(p.txs, hash)
+		}
+	}
+	p.mtx.Unlock()
+
+	select {
+	case <-p.heights:
+	default:
+	}
+	p.heights <- height
+}
+
+// tx returns the tx whose rlp is txBytes, false if it wasn't read yet
+func (p *prefetcher) tx(txBytes []byte) (*types.Transaction, bool) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	tx, ok := p.txs[ethereum.TxHash(txBytes)]
+	return tx.tx, ok
+}
+
+// read hands the txs of the blocks the node has, up to prefetchBlocks
+// ahead of the one run, to the workers
+func (p *prefetcher) read() {
+	next := int64(0)
+	for height := range p.heights {
+		// blocks rewound are run again
+		if next < height || next > height+prefetchBlocks+1 {
+			next = height
+		}
+		for ; next <= height+prefetchBlocks; next++ {
+			txs, ok := p.blockTxs(next)
+			if !ok {
+				break
+			}
+			for _, tx := range txs {
+				p.jobs <- prefetchJob{next, tx}
+			}
+		}
+	}
+}
+
+// work decodes the txs of the jobs and recovers their senders, which the
+// txs cache. Invalid txs are left to DeliverTx to report.
+func (p *prefetcher) work() {
+	for job := range p.jobs {
+		p.mtx.Lock()
+		stale := job.height < p.height
+		p.mtx.Unlock()
+		if stale {
+			continue
+		}
+		tx, err := decodeTx(job.tx)
+		if err != nil {
+			continue
+		}
+		tx.Hash()
+		if _, err := txSender(tx); err != nil {
+			continue
+		}
+		p.mtx.Lock()
+		if job.height >= p.height {
+			p.txs[ethereum.TxHash(job.tx)] = prefetchedTx{job.height, tx}
+		}
+		p.mtx.Unlock()
+	}
+}
@@ -0,0 +1,18 @@
+package app
+
+import (
+	"github.com/cosmos/cosmos-sdk/state"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// ProposerTipsUpgrade is the upgrade from which on the block proposer is the
+// coinbase of its blocks and keeps the tips of their txs, and the ethash
+// block reward is no longer minted. Before it, the receiver of the app is
+// the coinbase and gets the reward.
+const ProposerTipsUpgrade = "proposer-tips"
+
+func init() {
+	// nothing to migrate, only the blocks after it change
+	upgrade.RegisterHandler(ProposerTipsUpgrade, func(state.SimpleDB) error { return nil })
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// committedKey is where the chaindata keeps the last commit of the app,
+// see markCommitted
+var committedKey = []byte("ultron-app-committed")
+
+// appCommit is the ethereum block the app was at as it committed its store
+// at Height. The ethereum block is written before the store, so a node
+// stopping in between restarts with a block ahead of its store.
+type appCommit struct {
+	Height uint64
+	Number uint64
+	Hash   common.Hash
+}
+
+// markCommitted notes in the chaindata the commit of the app just done,
+// for rewindUncommitted
+func (app *BaseApp) markCommitted() {
+	head := app.ethereum.BlockChain().CurrentBlock()
+	b, err := rlp.EncodeToBytes(appCommit{uint64(app.CommittedHeight()), head.NumberU64(), head.Hash()})
+	if err == nil {
+		err = app.ethereum.ChainDb().Put(committedKey, b)
+	}
+	if err != nil {
+		app.logger.Error("Commit: Failed to note the commit in the chaindata", "err", err)
+	}
+}
+
+// rewindUncommitted rewinds the ethereum chain of a node stopped while
+// committing a block, after the ethereum block was written and before the
+// store was: the block is applied again to the ethereum state as tendermint
+// replays it, instead of on top of itself, which ended in an app hash
+// mismatch.
+func (app *BaseApp) rewindUncommitted() error {
+	chain := app.ethereum.BlockChain()
+	head := chain.CurrentBlock()
+	number, ok := app.lastCommittedBlock()
+	if !ok || head.NumberU64() <= number {
+		return nil
+	}
+	app.logger.Info("Replaying the block the app didn't commit", "height", app.WorkingHeight(),
+		"block", head.NumberU64(), "committed", number)
+	return app.EthApp.RewindUncommitted(number)
+}
+
+// lastCommittedBlock returns the ethereum block of the last commit of the
+// store, false if it is unknown or the head of the chain is the one
+func (app *BaseApp) lastCommittedBlock() (uint64, bool) {
+	db := app.ethereum.ChainDb()
+	if b, err := db.Get(committedKey); err == nil {
+		var c appCommit
+		if err := rlp.DecodeBytes(b, &c); err != nil {
+			app.logger.Error("Failed to read the last commit of the app", "err", err)
+			return 0, false
+		}
+		// the store committed after the note, the node stopped in between
+		if c.Height != uint64(app.CommittedHeight()) || core.GetCanonicalHash(db, c.Number) != c.Hash {
+			return 0, false
+		}
+		return c.Number, true
+	}
+
+	// nodes of before the note: the store keeps the hashes of the blocks
+	// once BlockHashesUpgrade is done, and the block written ahead of the
+	// store is the head
+	store := app.Committed()
+	if !upgrade.Done(store, BlockHashesUpgrade) {
+		return 0, false
+	}
+	head := app.ethereum.BlockChain().CurrentBlock()
+	if head.NumberU64() == 0 || store.Get(ethereum.BlockHashKey(head.NumberU64())) != nil {
+		return 0, false
+	}
+	number := head.NumberU64() - 1
+	stored := store.Get(ethereum.BlockHashKey(number))
+	if stored == nil || !bytes.Equal(stored, core.GetCanonicalHash(db, number).Bytes()) {
+		return 0, false
+	}
+	return number, true
+}
@@ -0,0 +1,13 @@
+package app
+
+import (
+	goerr "errors"
+)
+
+var errReplica = goerr.New("read-only replica: send txs to a full node")
+
+// SetReplica makes the app refuse every tx in CheckTx, replicas only follow
+// the blocks to serve queries.
+func (app *BaseApp) SetReplica(replica bool) {
+	app.replica = replica
+}
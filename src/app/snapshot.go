@@ -0,0 +1,94 @@
+package app
+
+import (
+	"bytes"
+	"sort"
+
+	sm "github.com/cosmos/cosmos-sdk/state"
+
+	"github.com/dora/ultron/dev"
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/modules/stake"
+	"github.com/dora/ultron/modules/upgrade"
+	"github.com/dora/ultron/signer"
+)
+
+var _ dev.Snapshotter = (*BaseApp)(nil)
+
+// snapshot is a copy of the chain state of a dev chain. The store keeps a
+// few versions only, so its content is copied instead of pinning a version.
+// The stake sqlite db is copied whole.
+type snapshot struct {
+	id      uint64
+	block   uint64 // ethereum head, its state root holds the evm state
+	store   []sm.Model
+	stakeDB []byte
+}
+
+// Snapshot implements dev.Snapshotter
+func (app *BaseApp) Snapshot() (uint64, error) {
+	release := app.useStakeDB()
+	stakeDB, err := stake.DumpDatabase()
+	release()
+	if err != nil {
+		return 0, err
+	}
+
+	app.lastSnapshotID++
+	app.snapshots = append(app.snapshots, &snapshot{
+		id:      app.lastSnapshotID,
+		block:   app.ethereum.BlockChain().CurrentBlock().NumberU64(),
+		store:   app.Append().List(nil, nil, 0),
+		stakeDB: stakeDB,
+	})
+	app.logger.Info("Took snapshot", "id", app.lastSnapshotID)
+	return app.lastSnapshotID, nil
+}
+
+// Revert implements dev.Snapshotter. The store goes back with the next
+// commit, the ethereum state and the stake db at once.
+func (app *BaseApp) Revert(id uint64) (bool, error) {
+	i := sort.Search(len(app.snapshots), func(i int) bool {
+		return app.snapshots[i].id >= id
+	})
+	if i == len(app.snapshots) || app.snapshots[i].id != id {
+		return false, nil
+	}
+	snap := app.snapshots[i]
+
+	if err := app.EthApp.Rewind(snap.block); err != nil {
+		return false, err
+	}
+	release := app.useStakeDB()
+	err := stake.LoadDatabase(snap.stakeDB)
+	release()
+	if err != nil {
+		return false, err
+	}
+
+	db := app.Append()
+	for _, m := range db.List(nil, nil, 0) {
+		if !hasKey(snap.store, m.Key) {
+			db.Remove(m.Key)
+		}
+	}
+	for _, m := range snap.store {
+		db.Set(m.Key, m.Value)
+	}
+	app.rates.Load(db)
+	app.seeds.Load(db, app.CommittedHeight())
+	signer.EnableTypedData(upgrade.Done(db, signer.TypedDataUpgrade))
+	params.Load(db)
+
+	app.snapshots = app.snapshots[:i]
+	app.logger.Info("Reverted to snapshot", "id", id, "block", snap.block)
+	return true, nil
+}
+
+// hasKey looks key up in models sorted by key
+func hasKey(models []sm.Model, key []byte) bool {
+	i := sort.Search(len(models), func(i int) bool {
+		return bytes.Compare(models[i].Key, key) >= 0
+	})
+	return i < len(models) && bytes.Equal(models[i].Key, key)
+}
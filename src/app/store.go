@@ -3,22 +3,32 @@ package app
 import (
 	"bytes"
 	"fmt"
+	"math"
 	"path"
 	"path/filepath"
 	"strings"
+	"sync"
 	"os"
 	"database/sql"
+	"encoding/binary"
+	"encoding/json"
 
 	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/ethereum/go-ethereum/common"
 	sm "github.com/cosmos/cosmos-sdk/state"
 	abci "github.com/tendermint/abci/types"
 	"github.com/tendermint/iavl"
 	cmn "github.com/tendermint/tmlibs/common"
 	dbm "github.com/tendermint/tmlibs/db"
+	"github.com/syndtr/goleveldb/leveldb/util"
 	"github.com/tendermint/tmlibs/log"
 	"github.com/spf13/viper"
 	"github.com/tendermint/tmlibs/cli"
 	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/community"
+	"github.com/dora/ultron/modules/mint"
+	"github.com/dora/ultron/modules/stake"
+	"github.com/dora/ultron/substore"
 
 	"encoding/hex"
 	_ "github.com/mattn/go-sqlite3"
@@ -27,6 +37,10 @@ import (
 // DefaultHistorySize is how many blocks of history to store for ABCI queries
 const DefaultHistorySize = 10
 
+// archiveHistorySize is the history of archive nodes, no version of the
+// state is ever pruned
+const archiveHistorySize = math.MaxInt64
+
 // StoreApp contains a data store and all info needed
 // to perform queries and handshakes.
 //
@@ -46,33 +60,141 @@ type StoreApp struct {
 	// height is last committed block, DeliverTx is the next one
 	height int64
 
+	// sqlite db of the stake module
+	stakeDB string
+
+	// leveldb of the state, nil when in memory
+	db *dbm.GoLevelDB
+
+	// keeps every version of the state, see NewArchiveStoreApp
+	archive bool
+
+	// no version of the state before it is left, see PruneVersions
+	prunedTo int64
+
+	// versions up to it are pruned or about to be, and refused to queries
+	pruneTo int64
+
+	// held by the queries reading past versions, and to delete them
+	versionsMtx sync.RWMutex
+
 	logger log.Logger
 }
 
 // NewStoreApp creates a data store to handle queries
 func NewStoreApp(appName, dbName string, cacheSize int, logger log.Logger) (*StoreApp, error) {
-	state, err := loadState(dbName, cacheSize, DefaultHistorySize)
+	return newStoreApp(appName, dbName, stakeDBPath(dbName), cacheSize, DefaultHistorySize, logger)
+}
+
+// NewStoreAppWithHistory creates a data store as NewStoreApp does, keeping
+// historySize versions of the state as it commits, all of them for 0 when
+// they are pruned apart, see PruneVersions.
+func NewStoreAppWithHistory(appName, dbName string, cacheSize int, historySize int64, logger log.Logger) (*StoreApp, error) {
+	if historySize == 0 {
+		historySize = archiveHistorySize
+	}
+	return newStoreApp(appName, dbName, stakeDBPath(dbName), cacheSize, historySize, logger)
+}
+
+// NewArchiveStoreApp creates a data store as NewStoreApp does, keeping
+// every version of the state for the queries of past heights. The versions
+// pruned before the node ran as an archive are gone.
+func NewArchiveStoreApp(appName, dbName string, cacheSize int, logger log.Logger) (*StoreApp, error) {
+	app, err := newStoreApp(appName, dbName, stakeDBPath(dbName), cacheSize, archiveHistorySize, logger)
+	if err != nil {
+		return nil, err
+	}
+	app.archive = true
+	return app, nil
+}
+
+// NewMemStoreApp creates a memory backed data store, only the stake db
+// is kept on disk under rootDir
+func NewMemStoreApp(appName, rootDir string, logger log.Logger) (*StoreApp, error) {
+	stakeDB := path.Join(rootDir, "data", constant.DatabaseName)
+	return newStoreApp(appName, "", stakeDB, 0, DefaultHistorySize, logger)
+}
+
+func newStoreApp(appName, dbName, stakeDB string, cacheSize int, historySize int64, logger log.Logger) (*StoreApp, error) {
+	state, db, err := loadState(dbName, cacheSize, historySize)
 	if err != nil {
 		return nil, err
 	}
 
-	err = initStakeDB()
+	err = initStakeDB(stakeDB)
 	if err != nil {
 		return nil, err
 	}
 
 	app := &StoreApp{
-		Name:   appName,
-		state:  state,
-		height: state.LatestHeight(),
-		info:   sm.NewChainState(),
-		logger: logger.With("module", "app"),
+		Name:    appName,
+		state:   state,
+		height:  state.LatestHeight(),
+		info:    sm.NewChainState(),
+		stakeDB: stakeDB,
+		db:      db,
+		logger:  logger.With("module", "app"),
 	}
 	return app, nil
 }
 
+// PruneVersions deletes up to max versions of the state older than the
+// retain latest ones, oldest first, and returns how many it deleted. It
+// must be called between blocks. The queries of the versions out of the
+// retention are refused from then on, the ones left go with the next call.
+func (app *StoreApp) PruneVersions(retain int64, max int) (int, error) {
+	if retain < 1 {
+		return 0, fmt.Errorf("at least the latest version must be kept, not %d", retain)
+	}
+	tree := app.Committed().Tree
+	app.versionsMtx.Lock()
+	if app.prunedTo == 0 {
+		app.prunedTo = 1
+	}
+	if to := app.height - retain; to > app.pruneTo {
+		app.pruneTo = to
+	}
+	app.versionsMtx.Unlock()
+
+	deleted := 0
+	for ; app.prunedTo <= app.height-retain && deleted < max; app.prunedTo++ {
+		ok, err := app.deleteVersion(tree, uint64(app.prunedTo))
+		if err != nil {
+			return deleted, err
+		}
+		if ok {
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// deleteVersion deletes a version of the state, no query reading one as it
+// does. It tells if there was the version.
+func (app *StoreApp) deleteVersion(tree *iavl.VersionedTree, version uint64) (bool, error) {
+	app.versionsMtx.Lock()
+	defer app.versionsMtx.Unlock()
+	if !tree.VersionExists(version) {
+		return false, nil
+	}
+	return true, tree.DeleteVersion(version)
+}
+
+// Archive tells whether the app keeps every version of its state.
+func (app *StoreApp) Archive() bool {
+	return app.archive
+}
+
+// useStakeDB routes stake module calls to this app's db until released
+func (app *StoreApp) useStakeDB() (release func()) {
+	return stake.UseDatabase(app.stakeDB)
+}
+
 // InitChain - ABCI
 func (app *StoreApp) InitChain(req abci.RequestInitChain) (res abci.ResponseInitChain) {
+	release := app.useStakeDB()
+	stake.SaveValidatorChanges(0, req.Validators)
+	release()
 	return
 }
 
@@ -91,6 +213,11 @@ func (app *StoreApp) Hash() []byte {
 	return app.state.LatestHash()
 }
 
+// PendingHash returns the hash the working state is to be committed with
+func (app *StoreApp) PendingHash() ([]byte, error) {
+	return app.state.Hash()
+}
+
 // Committed returns the committed state,
 // also exposing historical queries
 func (app *StoreApp) Committed() *sm.Bonsai {
@@ -149,6 +276,10 @@ func (app *StoreApp) Query(reqQuery abci.RequestQuery) (resQuery abci.ResponseQu
 		return
 	}
 
+	// no version is deleted as it is read
+	app.versionsMtx.RLock()
+	defer app.versionsMtx.RUnlock()
+
 	// set the query response height to current
 	tree := app.state.Committed()
 
@@ -160,7 +291,7 @@ func (app *StoreApp) Query(reqQuery abci.RequestQuery) (resQuery abci.ResponseQu
 		// is not yet in the blockchain
 
 		withProof := app.CommittedHeight() - 1
-		if tree.Tree.VersionExists(uint64(withProof)) {
+		if withProof > app.pruneTo && tree.Tree.VersionExists(uint64(withProof)) {
 			height = withProof
 		} else {
 			height = app.CommittedHeight()
@@ -168,7 +299,19 @@ func (app *StoreApp) Query(reqQuery abci.RequestQuery) (resQuery abci.ResponseQu
 	}
 	resQuery.Height = height
 
-	switch reqQuery.Path {
+	// the keys of a named sub-store are queried as the ones of the app
+	// state, and only them
+	path := reqQuery.Path
+	if named, ok := substore.OpenPath(tree, path); ok {
+		if !named.Contains(reqQuery.Data) {
+			resQuery.Code = errors.CodeTypeBaseInvalidInput
+			resQuery.Log = cmn.Fmt("Key %X is not of the %s sub-store", reqQuery.Data, named.Name())
+			return
+		}
+		path = "/key"
+	}
+
+	switch path {
 	case "/store", "/key": // Get by key
 		key := reqQuery.Data // Data holds the key bytes
 		resQuery.Key = key
@@ -177,6 +320,11 @@ func (app *StoreApp) Query(reqQuery abci.RequestQuery) (resQuery abci.ResponseQu
 		resQuery.Value = value
 
 		if reqQuery.Prove {
+			if height <= app.pruneTo {
+				resQuery.Code = errors.CodeTypeBaseInvalidInput
+				resQuery.Log = cmn.Fmt("Version %d is pruned", height)
+				break
+			}
 			value, proof, err := tree.GetVersionedWithProof(key, height)
 			if err != nil {
 				resQuery.Log = err.Error()
@@ -188,6 +336,32 @@ func (app *StoreApp) Query(reqQuery abci.RequestQuery) (resQuery abci.ResponseQu
 			value := tree.Get(key)
 			resQuery.Value = value
 		}
+	case "/stake/validators": // Validator set as of the 8 byte big endian height, the latest for 0
+		if len(reqQuery.Data) != 8 {
+			resQuery.Code = errors.CodeTypeEncodingErr
+			resQuery.Log = "Query data must be an 8 byte height"
+			break
+		}
+		at := int64(binary.BigEndian.Uint64(reqQuery.Data))
+		if at == 0 {
+			at = app.CommittedHeight()
+		}
+		release := app.useStakeDB()
+		set, ok := stake.ValidatorSetAt(at)
+		release()
+		if !ok {
+			resQuery.Code = errors.CodeTypeBaseInvalidInput
+			resQuery.Log = cmn.Fmt("No validator set at height %d", at)
+			break
+		}
+		resQuery.Value, _ = json.Marshal(set)
+	case "/stake/apr": // Projected return of the validator owned by address
+		resQuery.Value, resQuery.Log = app.projectedAPR(common.BytesToAddress(reqQuery.Data), height)
+		if resQuery.Log != "" {
+			resQuery.Code = errors.CodeTypeBaseUnknownAddress
+		}
+	case "/stake/performance": // Block production counters of the validators
+		resQuery.Value, _ = json.Marshal(app.performances())
 	default:
 		resQuery.Code = errors.CodeTypeUnknownRequest
 		resQuery.Log = cmn.Fmt("Unexpected Query path: %v", reqQuery.Path)
@@ -196,6 +370,41 @@ func (app *StoreApp) Query(reqQuery abci.RequestQuery) (resQuery abci.ResponseQu
 	return
 }
 
+func (app *StoreApp) projectedAPR(owner common.Address, height int64) ([]byte, string) {
+	release := app.useStakeDB()
+	defer release()
+
+	candidate := stake.GetCandidateByAddress(owner)
+	if candidate == nil {
+		return nil, fmt.Sprintf("no candidate owned by %s", owner.Hex())
+	}
+	provision := mint.BlockProvision(app.state.Check())
+	if provision == nil {
+		provision = stake.InflationAward(height)
+	}
+	value, err := json.Marshal(stake.ProjectedAPR(candidate, height, community.AfterTax(provision)))
+	if err != nil {
+		return nil, err.Error()
+	}
+	return value, ""
+}
+
+// performances reports the performance of the candidates that validated
+// a block.
+func (app *StoreApp) performances() []stake.PerformanceReport {
+	release := app.useStakeDB()
+	defer release()
+
+	reports := []stake.PerformanceReport{}
+	for _, candidate := range stake.GetCandidates() {
+		perf := stake.LoadPerformance(app.state.Check(), candidate.OwnerAddress)
+		if perf.Since != 0 {
+			reports = append(reports, stake.NewPerformanceReport(candidate.OwnerAddress, perf))
+		}
+	}
+	return reports
+}
+
 // Commit implements abci.Application
 func (app *StoreApp) Commit() (res abci.ResponseCommit) {
 	app.height++
@@ -251,17 +460,23 @@ func pubKeyIndex(val *abci.Validator, list []*abci.Validator) int {
 	return -1
 }
 
-func loadState(dbName string, cacheSize int, historySize int64) (*sm.State, error) {
+// LoadState opens the app state in the leveldb dbName as NewStoreApp does,
+// for the tools reading it while the node is stopped.
+func LoadState(dbName string, cacheSize int) (*sm.State, *dbm.GoLevelDB, error) {
+	return loadState(dbName, cacheSize, DefaultHistorySize)
+}
+
+func loadState(dbName string, cacheSize int, historySize int64) (*sm.State, *dbm.GoLevelDB, error) {
 	// memory backed case, just for testing
 	if dbName == "" {
 		tree := iavl.NewVersionedTree(0, dbm.NewMemDB())
-		return sm.NewState(tree, historySize), nil
+		return sm.NewState(tree, historySize), nil, nil
 	}
 
 	// Expand the path fully
 	dbPath, err := filepath.Abs(dbName)
 	if err != nil {
-		return nil, errors.ErrInternal("Invalid Database Name")
+		return nil, nil, errors.ErrInternal("Invalid Database Name")
 	}
 
 	// Some external calls accidently add a ".db", which is now removed
@@ -272,18 +487,39 @@ func loadState(dbName string, cacheSize int, historySize int64) (*sm.State, erro
 	name := path.Base(dbPath)
 
 	// Open database called "dir/name.db", if it doesn't exist it will be created
-	db := dbm.NewDB(name, dbm.LevelDBBackendStr, dir)
+	db, err := dbm.NewGoLevelDB(name, dir)
+	if err != nil {
+		return nil, nil, errors.ErrInternal("Opening database: " + err.Error())
+	}
 	tree := iavl.NewVersionedTree(cacheSize, db)
 	if err = tree.Load(); err != nil {
-		return nil, errors.ErrInternal("Loading tree: " + err.Error())
+		return nil, nil, errors.ErrInternal("Loading tree: " + err.Error())
 	}
 
-	return sm.NewState(tree, historySize), nil
+	return sm.NewState(tree, historySize), db, nil
+}
+
+// CompactDB compacts the leveldb of the state, giving back the space of
+// the versions pruned. It does nothing for the memory backed state. It must
+// be called between blocks.
+func (app *StoreApp) CompactDB() error {
+	if app.db == nil {
+		return nil
+	}
+	return app.db.DB().CompactRange(util.Range{})
 }
 
-func initStakeDB() error {
-	rootDir := viper.GetString(cli.HomeFlag)
-	stakeDbPath := path.Join(rootDir, "data", constant.DatabaseName)
+// stakeDBPath puts the stake db next to the merkleeyes db, or under the
+// home dir for the memory backed case
+func stakeDBPath(dbName string) string {
+	if dbName == "" {
+		rootDir := viper.GetString(cli.HomeFlag)
+		return path.Join(rootDir, "data", constant.DatabaseName)
+	}
+	return path.Join(path.Dir(dbName), constant.DatabaseName)
+}
+
+func initStakeDB(stakeDbPath string) error {
 	_, err := os.OpenFile(stakeDbPath, os.O_RDONLY, 0444)
 	if err != nil {
 		db, err := sql.Open("sqlite3", stakeDbPath)
@@ -310,5 +546,16 @@ func initStakeDB() error {
 		}
 	}
 
+	// added after the tables above, the dbs made before lack it
+	db, err := sql.Open("sqlite3", stakeDbPath)
+	if err != nil {
+		return errors.ErrInternal("Initializing stake database: " + err.Error())
+	}
+	defer db.Close()
+	_, err = db.Exec("create table if not exists validator_sets(height integer not null primary key, validators text not null)")
+	if err != nil {
+		return errors.ErrInternal("Initializing database: " + err.Error())
+	}
+
 	return nil
 }
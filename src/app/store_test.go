@@ -0,0 +1,61 @@
+package app
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+	"github.com/tendermint/tmlibs/log"
+
+	"github.com/dora/ultron/modules/params"
+)
+
+func TestQuery(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	app, err := newStoreApp("test", "", path.Join(dir, "stake.db"), 0, archiveHistorySize, log.NewNopLogger())
+	require.Nil(t, err)
+
+	key := []byte("key")
+	for i := 0; i < 6; i++ {
+		app.Append().Set(key, []byte{byte(i)})
+		app.Commit()
+	}
+	query := func(height int64) abci.ResponseQuery {
+		return app.Query(abci.RequestQuery{Path: "/key", Data: key, Height: height, Prove: true})
+	}
+	assert.Equal(t, abci.CodeTypeOK, query(2).Code)
+
+	// the versions out of the retention are refused, pruned or not yet
+	n, err := app.PruneVersions(2, 2)
+	require.Nil(t, err)
+	assert.Equal(t, 2, n)
+	for _, height := range []int64{1, 2, 3, 4} {
+		assert.EqualValues(t, errors.CodeTypeBaseInvalidInput, query(height).Code, "height %d", height)
+	}
+	res := query(5)
+	assert.Equal(t, abci.CodeTypeOK, res.Code)
+	assert.Equal(t, []byte{4}, res.Value)
+
+	// the keys of a named sub-store, at its own path
+	app.Append().Set(params.ValueKey("test.param"), []byte("1"))
+	app.Commit()
+	res = app.Query(abci.RequestQuery{Path: "/params/key", Data: params.ValueKey("test.param")})
+	assert.Equal(t, abci.CodeTypeOK, res.Code)
+	assert.NotNil(t, res.Value)
+	res = app.Query(abci.RequestQuery{Path: "/params/key", Data: key})
+	assert.EqualValues(t, errors.CodeTypeBaseInvalidInput, res.Code)
+
+	// the latest version is left when the one before it is pruned
+	_, err = app.PruneVersions(1, 10)
+	require.Nil(t, err)
+	res = query(0)
+	assert.Equal(t, abci.CodeTypeOK, res.Code)
+	assert.Equal(t, int64(7), res.Height)
+}
@@ -0,0 +1,12 @@
+package app
+
+import (
+	"github.com/dora/ultron/modules/supply"
+)
+
+// commitSupply adds the ether minted and burnt by the ethereum block just
+// committed to the totals in the working state, from supply.TotalsUpgrade on.
+func (app *BaseApp) commitSupply() {
+	change := app.EthApp.backend.LastSupplyChange()
+	supply.Add(app.Append(), change.Minted, change.Burned)
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"github.com/dora/ultron/backend/ethereum"
+)
+
+// beginBlockCall calls the begin block contract, if any, see
+// ethereum.EthState.SystemCall. A failed call doesn't stop the block.
+func (app *BaseApp) beginBlockCall() {
+	app.systemCall(ethereum.BeginBlockContract, ethereum.BeginBlockSelector)
+}
+
+// endBlockCall calls the end block contract, if any, after the txs of the
+// block and before the block award.
+func (app *BaseApp) endBlockCall() {
+	app.systemCall(ethereum.EndBlockContract, ethereum.EndBlockSelector)
+}
+
+func (app *BaseApp) systemCall(key string, selector []byte) {
+	used, err := app.EthApp.backend.SystemCall(key, selector)
+	if err != nil {
+		app.logger.Error("System call failed", "module", "system", "param", key, "err", err)
+		return
+	}
+	if used > 0 {
+		app.logger.Debug("System call", "module", "system", "param", key, "gas", used)
+	}
+}
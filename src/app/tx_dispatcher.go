@@ -3,11 +3,13 @@ package app
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"strings"
 	"github.com/dora/ultron/types"
 	"github.com/cosmos/cosmos-sdk"
 	"github.com/cosmos/cosmos-sdk/errors"
 	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/core"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	abci "github.com/tendermint/abci/types"
 )
@@ -62,12 +64,13 @@ func (td *TxDispatcher) CheckTx(app *BaseApp, tx *ethTypes.Transaction) abci.Res
 		return errors.CheckResult(err)
 	}
 
-	var res sdk.CheckResult
-	if handler != nil {
-		res, err = handler.CheckTx(ctx, app.Check(), innerTx)
-		if err != nil {
-			return errors.CheckResult(err)
-		}
+	if fee := maxFee(innerTx, tx); fee != nil && currentState.GetBalance(from).Cmp(fee) < 0 {
+		return errors.CheckResult(core.ErrInsufficientFunds)
+	}
+
+	res, err := handler.CheckTx(ctx, app.Check(), innerTx)
+	if err != nil {
+		return errors.CheckResult(err)
 	}
 
 	currentState.SetNonce(from, nonce + 1)
@@ -94,17 +97,48 @@ func (td *TxDispatcher) DeliverTx(app *BaseApp, tx *ethTypes.Transaction) abci.R
 	}
 	ctx.WithSigners(from)
 
-	handler, err := td.getTxHandler(innerTx)
+	// the txs of unknown modules only spend their nonce, as they always
+	// did, CheckTx keeps them out of the mempool
+	handler, _, err := td.lookupTxHandler(innerTx)
 	if err != nil {
 		return errors.DeliverResult(err)
 	}
+	if handler == nil {
+		app.EthApp.backend.AddNonce(from)
+		return sdk.DeliverResult{}.ToABCI()
+	}
 
-	var res sdk.DeliverResult
-	if handler != nil {
-		res, err = handler.DeliverTx(ctx, app.Append(), innerTx)
+	fee := maxFee(innerTx, tx)
+	if fee != nil && app.EthApp.backend.Balance(from).Cmp(fee) < 0 {
+		return errors.DeliverResult(core.ErrInsufficientFunds)
+	}
+
+	if fee != nil {
+		// metered txs run on a checkpoint of the store, written back once
+		// the gas they used is paid
+		store := app.Append()
+		cache := store.Checkpoint()
+		res, err := handler.DeliverTx(ctx, cache, innerTx)
+		// they pay the gas they used and spend their nonce, failed or not,
+		// so their runs can't be replayed for free
+		used := new(big.Int).Mul(new(big.Int).SetUint64(res.GasUsed), tx.GasPrice())
+		if feeErr := app.EthApp.backend.ChargeFee(from, used); feeErr != nil && err == nil {
+			err = feeErr
+		}
+		app.EthApp.backend.AddNonce(from)
+		if err == nil {
+			err = store.Commit(cache)
+		}
 		if err != nil {
+			cache.Discard()
 			return errors.DeliverResult(err)
 		}
+		return res.ToABCI()
+	}
+
+	res, err := handler.DeliverTx(ctx, app.Append(), innerTx)
+	if err != nil {
+		return errors.DeliverResult(err)
 	}
 
 	app.EthApp.backend.AddNonce(from)
@@ -112,12 +146,43 @@ func (td *TxDispatcher) DeliverTx(app *BaseApp, tx *ethTypes.Transaction) abci.R
 	return res.ToABCI()
 }
 
-func (td *TxDispatcher)getTxHandler(tx sdk.Tx) (types.TxHandler, error) {
-	kind, err := tx.GetKind()
+// gasLimiter is implemented by the inner txs that run metered code, such as
+// the wasm ones.
+type gasLimiter interface {
+	GasLimit() uint64
+}
+
+// maxFee returns the worth of the gas limit of innerTx at the gas price of
+// tx, nil if innerTx isn't metered.
+func maxFee(innerTx sdk.Tx, tx *ethTypes.Transaction) *big.Int {
+	metered, ok := innerTx.Unwrap().(gasLimiter)
+	if !ok {
+		return nil
+	}
+	return new(big.Int).Mul(new(big.Int).SetUint64(metered.GasLimit()), tx.GasPrice())
+}
+
+// getTxHandler returns the handler of the module of tx, an error if none
+// is registered.
+func (td *TxDispatcher) getTxHandler(tx sdk.Tx) (types.TxHandler, error) {
+	handler, name, err := td.lookupTxHandler(tx)
 	if err != nil {
 		return nil, err
 	}
+	if handler == nil {
+		return nil, errors.ErrUnknownModule(name)
+	}
+	return handler, nil
+}
+
+// lookupTxHandler returns the handler of the module of tx, nil if none is
+// registered, and the name of the module.
+func (td *TxDispatcher) lookupTxHandler(tx sdk.Tx) (types.TxHandler, string, error) {
+	kind, err := tx.GetKind()
+	if err != nil {
+		return nil, "", err
+	}
 
 	name := strings.SplitN(kind, "/", 2)[0]
-	return td.txHandlers[name], nil
+	return td.txHandlers[name], name, nil
 }
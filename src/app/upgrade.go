@@ -0,0 +1,25 @@
+package app
+
+import (
+	"os"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// SetUpgradeInfoFile sets the file the plan of an upgrade this binary lacks
+// is written to, the supervisor reads it to pick the next binary.
+func (app *BaseApp) SetUpgradeInfoFile(file string) {
+	app.upgradeInfoFile = file
+}
+
+// stopForUpgrade exits with upgrade.ExitCode before the block of plan is
+// run, tendermint replays it once the binary of the upgrade is started.
+func (app *BaseApp) stopForUpgrade(plan upgrade.Plan) {
+	app.logger.Error("Upgrade needed, stopping", "name", plan.Name, "height", plan.Height, "info", plan.Info)
+	if app.upgradeInfoFile != "" {
+		if err := upgrade.WriteInfoFile(app.upgradeInfoFile, plan); err != nil {
+			app.logger.Error("Failed to write the upgrade info", "file", app.upgradeInfoFile, "err", err)
+		}
+	}
+	os.Exit(upgrade.ExitCode)
+}
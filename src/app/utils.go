@@ -4,10 +4,15 @@ import (
 	"bytes"
 	"encoding/json"
 
+	"github.com/cosmos/cosmos-sdk"
 	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/emergency"
+	"github.com/dora/ultron/modules/recovery"
+	"github.com/dora/ultron/modules/stake"
+	"github.com/dora/ultron/modules/wasm"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/rlp"
 	abciTypes "github.com/tendermint/abci/types"
 )
 
@@ -20,19 +25,13 @@ type jsonRequest struct {
 
 // rlp decode an etherum transaction
 func decodeTx(txBytes []byte) (*types.Transaction, error) {
-	tx := new(types.Transaction)
-	rlpStream := rlp.NewStream(bytes.NewBuffer(txBytes), 0)
-	if err := tx.DecodeRLP(rlpStream); err != nil {
-		return nil, err
-	}
-	return tx, nil
+	return ethereum.DecodeTx(txBytes)
 }
 
 // rlp decode an etherum transaction
 func decodePtx(txBytes []byte) (*ethereum.ParalleledTransaction, error) {
 	ptx := new(ethereum.ParalleledTransaction)
-	rlpStream := rlp.NewStream(bytes.NewBuffer(txBytes), 0)
-	if err := ptx.DecodeRLP(rlpStream); err != nil {
+	if err := ethereum.DecodeRLP(txBytes, ptx); err != nil {
 		return nil, err
 	}
 	return ptx, nil
@@ -74,3 +73,76 @@ func (app *EthermintApplication) CollectTx(tx *types.Transaction) {
 		app.strategy.CollectTx(tx)
 	}
 }
+
+// checkSigner rejects the txs signed by a key rotated by account recovery
+func (app *BaseApp) checkSigner(tx *types.Transaction, check bool) error {
+	from, err := txSender(tx)
+	if err != nil {
+		return err
+	}
+	if check {
+		return recovery.CheckSigner(app.Check(), from)
+	}
+	return recovery.CheckSigner(app.Append(), from)
+}
+
+func init() {
+	emergency.RegisterClass(emergency.ClassDeploy, wasm.TypeTxDeploy)
+	emergency.RegisterClass(emergency.ClassWithdraw, stake.TypeTxWithdraw, stake.TypeTxWithdrawCandidacy)
+}
+
+// checkPaused rejects the txs of a class paused by the emergency council
+func (app *BaseApp) checkPaused(tx *types.Transaction, check bool) error {
+	store := app.Append()
+	if check {
+		store = app.Check()
+	}
+	return emergency.CheckClass(store, txClass(tx), app.WorkingHeight())
+}
+
+// txClass returns the emergency class of tx, "" if it has none
+func txClass(tx *types.Transaction) string {
+	if isEthTx(tx) {
+		if tx.To() == nil {
+			return emergency.ClassDeploy
+		}
+		return ""
+	}
+
+	var innerTx sdk.Tx
+	if err := json.Unmarshal(tx.Data(), &innerTx); err != nil {
+		return "" // rejected by the tx dispatcher
+	}
+	kind, err := innerTx.GetKind()
+	if err != nil {
+		return ""
+	}
+	return emergency.ClassOf(kind)
+}
+
+// proposerAccount returns the owner account of the candidate whose
+// validator address is proposer, if there is one.
+func (app *BaseApp) proposerAccount(proposer []byte) (common.Address, bool) {
+	release := app.useStakeDB()
+	defer release()
+
+	for _, candidate := range stake.GetCandidates() {
+		if bytes.Equal(candidate.PubKey.Address(), proposer) {
+			return candidate.OwnerAddress, true
+		}
+	}
+	return common.Address{}, false
+}
+
+// txSender returns the sender of tx, an error for the txs signed for
+// another chain.
+func txSender(tx *types.Transaction) (common.Address, error) {
+	var signer types.Signer = types.FrontierSigner{}
+	if tx.Protected() {
+		if tx.ChainId().Cmp(constant.ChainId) != 0 {
+			return common.Address{}, types.ErrInvalidChainId
+		}
+		signer = types.NewEIP155Signer(constant.ChainId)
+	}
+	return tx.From(signer, false)
+}
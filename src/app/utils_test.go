@@ -0,0 +1,40 @@
+package app
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dora/ultron/const"
+)
+
+func TestTxSender(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	addr := crypto.PubkeyToAddress(key.PublicKey)
+	tx := ethTypes.NewTransaction(0, common.Address{}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+
+	signed, err := ethTypes.SignTx(tx, ethTypes.NewEIP155Signer(constant.ChainId), key)
+	require.Nil(t, err)
+	from, err := txSender(signed)
+	require.Nil(t, err)
+	assert.Equal(t, addr, from)
+
+	// signed for another chain
+	signed, err = ethTypes.SignTx(tx, ethTypes.NewEIP155Signer(big.NewInt(1)), key)
+	require.Nil(t, err)
+	_, err = txSender(signed)
+	assert.Equal(t, ethTypes.ErrInvalidChainId, err)
+
+	// unprotected
+	signed, err = ethTypes.SignTx(tx, ethTypes.HomesteadSigner{}, key)
+	require.Nil(t, err)
+	from, err = txSender(signed)
+	require.Nil(t, err)
+	assert.Equal(t, addr, from)
+}
@@ -0,0 +1,94 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	abci "github.com/tendermint/abci/types"
+
+	"github.com/dora/ultron/appwal"
+	"github.com/dora/ultron/backend/ethereum"
+)
+
+// SetWAL has the blocks written to w as they run, see appwal. When the node
+// stopped in the block tendermint replays first, the replay is checked
+// against what w has of it.
+func (app *BaseApp) SetWAL(w *appwal.WAL) {
+	app.wal = w
+	records := w.Uncommitted()
+	if len(records) > 0 && records[0].Height == app.WorkingHeight() {
+		app.walReplay = records
+		app.logger.Info("Node stopped in the middle of a block, checking its replay against the wal",
+			"height", records[0].Height, "records", len(records))
+	}
+}
+
+// walWrite writes r to the wal, if there is one
+func (app *BaseApp) walWrite(r appwal.Record) {
+	if app.wal == nil {
+		return
+	}
+	if err := app.wal.Write(r); err != nil {
+		app.logger.Error("Failed to write the wal", "type", r.Type, "height", r.Height, "err", err)
+	}
+}
+
+// walReplayed returns the record the run the node stopped in wrote where
+// the replay wrote r, nil if there is none
+func (app *BaseApp) walReplayed(r appwal.Record) *appwal.Record {
+	for i := range app.walReplay {
+		old := &app.walReplay[i]
+		if old.Type == r.Type && old.Height == r.Height && old.Index == r.Index {
+			return old
+		}
+	}
+	return nil
+}
+
+func (app *BaseApp) walBegin(req abci.RequestBeginBlock) {
+	app.walTxs = 0
+	app.walWrite(appwal.Record{Type: appwal.BeginType, Height: app.WorkingHeight(), Time: req.Header.Time,
+		Proposer: req.Header.Proposer})
+}
+
+func (app *BaseApp) walDeliver(txBytes []byte, res abci.ResponseDeliverTx) {
+	hash := ethereum.TxHash(txBytes)
+	r := appwal.Record{Type: appwal.DeliverType, Height: app.WorkingHeight(), Index: app.walTxs, Tx: &hash,
+		Code: res.Code, Log: res.Log}
+	app.walTxs++
+	if old := app.walReplayed(r); old != nil && (old.Tx == nil || *old.Tx != hash || old.Code != r.Code) {
+		app.logger.Error("Replayed tx differs from the run the node stopped in", "height", r.Height,
+			"index", r.Index, "tx", hash.Hex(), "code", r.Code, "was", old.Code, "log", r.Log)
+	}
+	app.walWrite(r)
+}
+
+// walCommit writes what the block is about to be committed with, before
+// the store commits it
+func (app *BaseApp) walCommit(blockHash []byte) {
+	if app.wal == nil {
+		return
+	}
+	appHash, err := app.PendingHash()
+	if err != nil {
+		app.logger.Error("Failed to hash the working state for the wal", "err", err)
+	}
+	hash := common.BytesToHash(blockHash)
+	r := appwal.Record{Type: appwal.CommitType, Height: app.WorkingHeight(),
+		Block: app.ethereum.BlockChain().CurrentBlock().NumberU64(), BlockHash: &hash, AppHash: appHash}
+	if old := app.walReplayed(r); old != nil {
+		if !bytes.Equal(old.AppHash, r.AppHash) || old.BlockHash == nil || *old.BlockHash != hash {
+			app.logger.Error("Replayed block differs from the run the node stopped in", "height", r.Height,
+				"app_hash", fmt.Sprintf("%X", r.AppHash), "was", fmt.Sprintf("%X", old.AppHash), "block", hash.Hex())
+		} else {
+			app.logger.Info("Replayed block matches the run the node stopped in", "height", r.Height)
+		}
+	}
+	app.walWrite(r)
+}
+
+func (app *BaseApp) walCommitted(appHash []byte) {
+	app.walReplay = nil
+	app.walWrite(appwal.Record{Type: appwal.CommittedType, Height: app.CommittedHeight(), AppHash: appHash})
+}
@@ -0,0 +1,216 @@
+// Package appwal is the write ahead log of the app. The results of the txs
+// of a block are written as they are delivered, and the app hash the block
+// is about to be committed with is synced to disk before the commit, so a
+// node stopping in the middle of a block leaves what it did behind: as
+// tendermint replays the block at start, the app checks the replay against
+// it, and the log of a halted node tells what it computed for its last
+// blocks. The log is a file of json records, one per line; past a size it
+// is moved to <file>.1 at the end of a block, keeping the one before.
+package appwal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// File is the file, in the data directory of the node, of the log.
+const File = "app.wal"
+
+// DefaultMaxSize is the size the log is rotated at.
+const DefaultMaxSize = 64 << 20
+
+// Types of the records, a block writes a begin, a deliver per tx, a commit
+// and a committed record in this order
+const (
+	BeginType     = "begin"
+	DeliverType   = "deliver"
+	CommitType    = "commit"    // what is about to be committed, synced before the commit
+	CommittedType = "committed" // the store committed the block
+)
+
+// Record is a step of the app in a block.
+type Record struct {
+	Type   string `json:"type"`
+	Height int64  `json:"height"`
+
+	// begin
+	Time     int64         `json:"time,omitempty"` // of the block header
+	Proposer hexutil.Bytes `json:"proposer,omitempty"`
+
+	// deliver
+	Index int          `json:"index,omitempty"` // of the tx in the block
+	Tx    *common.Hash `json:"tx,omitempty"`    // hash of its bytes
+	Code  uint32       `json:"code,omitempty"`
+	Log   string       `json:"log,omitempty"`
+
+	// commit
+	Block     uint64       `json:"block,omitempty"` // the ethereum block
+	BlockHash *common.Hash `json:"blockHash,omitempty"`
+
+	// commit and committed
+	AppHash hexutil.Bytes `json:"appHash,omitempty"`
+}
+
+// WAL appends the records of the blocks to a file.
+type WAL struct {
+	file    string
+	maxSize int64
+
+	f    *os.File
+	w    *bufio.Writer
+	size int64
+
+	uncommitted []Record
+}
+
+// Open opens the log in file, rotated past maxSize. A record cut short by a
+// stop is dropped.
+func Open(file string, maxSize int64) (*WAL, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	records, valid, err := parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	if valid < len(data) {
+		if err := os.Truncate(file, int64(valid)); err != nil {
+			return nil, err
+		}
+	}
+	w := &WAL{file: file, maxSize: maxSize, size: int64(valid)}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	// the records from the last begin on, if that block wasn't committed
+	for i := len(records) - 1; i >= 0 && records[i].Type != CommittedType; i-- {
+		if records[i].Type == BeginType {
+			w.uncommitted = records[i:]
+			break
+		}
+	}
+	return w, nil
+}
+
+func (w *WAL) open() error {
+	f, err := os.OpenFile(w.file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return err
+	}
+	w.f, w.w = f, bufio.NewWriter(f)
+	return nil
+}
+
+// Uncommitted returns the records of the block the node stopped in, found
+// by Open, nil if it stopped between two blocks.
+func (w *WAL) Uncommitted() []Record {
+	return w.uncommitted
+}
+
+// Write appends r, synced to disk with the next commit record.
+func (w *WAL) Write(r Record) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	n, err := w.w.Write(append(b, '\n'))
+	w.size += int64(n)
+	if err != nil {
+		return err
+	}
+	switch r.Type {
+	case CommitType:
+		if err := w.w.Flush(); err != nil {
+			return err
+		}
+		return w.f.Sync()
+	case CommittedType:
+		if err := w.w.Flush(); err != nil {
+			return err
+		}
+		if w.size > w.maxSize {
+			return w.rotate()
+		}
+	}
+	return nil
+}
+
+// rotate moves the log to file.1, replacing the one there
+func (w *WAL) rotate() error {
+	if err := w.f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(w.file, w.file+".1"); err != nil {
+		return err
+	}
+	w.size = 0
+	return w.open()
+}
+
+// Close writes the records left and closes the file.
+func (w *WAL) Close() error {
+	err := w.w.Flush()
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Read returns the records written so far, see Read.
+func (w *WAL) Read() ([]Record, error) {
+	if err := w.w.Flush(); err != nil {
+		return nil, err
+	}
+	return Read(w.file)
+}
+
+// Read returns the records of the log in file, the rotated ones first.
+func Read(file string) ([]Record, error) {
+	var all []Record
+	for _, name := range []string{file + ".1", file} {
+		data, err := ioutil.ReadFile(name)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records, _, err := parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		all = append(all, records...)
+	}
+	return all, nil
+}
+
+// parse returns the records of data and the bytes they take, the last line
+// being left out when it isn't a whole record
+func parse(data []byte) ([]Record, int, error) {
+	var records []Record
+	valid := 0
+	for valid < len(data) {
+		end := bytes.IndexByte(data[valid:], '\n')
+		if end < 0 {
+			break
+		}
+		var r Record
+		if err := json.Unmarshal(data[valid:valid+end], &r); err != nil {
+			if valid+end+1 == len(data) {
+				break
+			}
+			return nil, 0, fmt.Errorf("record at byte %d: %v", valid, err)
+		}
+		records = append(records, r)
+		valid += end + 1
+	}
+	return records, valid, nil
+}
@@ -0,0 +1,85 @@
+package appwal
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWAL(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appwal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "app.wal")
+
+	tx, block := common.HexToHash("0x01"), common.HexToHash("0x02")
+	w, err := Open(file, 1<<20)
+	require.NoError(t, err)
+	assert.Nil(t, w.Uncommitted())
+	require.NoError(t, w.Write(Record{Type: BeginType, Height: 1, Time: 100}))
+	require.NoError(t, w.Write(Record{Type: DeliverType, Height: 1, Tx: &tx, Code: 3, Log: "out of gas"}))
+	require.NoError(t, w.Write(Record{Type: CommitType, Height: 1, Block: 1, BlockHash: &block, AppHash: []byte{0xaa}}))
+	require.NoError(t, w.Write(Record{Type: CommittedType, Height: 1, AppHash: []byte{0xaa}}))
+	require.NoError(t, w.Write(Record{Type: BeginType, Height: 2}))
+	require.NoError(t, w.Write(Record{Type: CommitType, Height: 2, AppHash: []byte{0xbb}}))
+	require.NoError(t, w.Close())
+
+	// stopped in the middle of a record, before the store committed
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"type":"committed","hei`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	w, err = Open(file, 1<<20)
+	require.NoError(t, err)
+	uncommitted := w.Uncommitted()
+	require.Len(t, uncommitted, 2)
+	assert.Equal(t, int64(2), uncommitted[0].Height)
+	assert.Equal(t, []byte{0xbb}, []byte(uncommitted[1].AppHash))
+	require.NoError(t, w.Write(Record{Type: CommittedType, Height: 2}))
+	require.NoError(t, w.Close())
+
+	records, err := Read(file)
+	require.NoError(t, err)
+	require.Len(t, records, 7)
+	assert.Equal(t, tx, *records[1].Tx)
+	assert.Equal(t, "out of gas", records[1].Log)
+	assert.Equal(t, CommittedType, records[6].Type)
+
+	w, err = Open(file, 1<<20)
+	require.NoError(t, err)
+	assert.Nil(t, w.Uncommitted())
+	require.NoError(t, w.Close())
+}
+
+func TestRotate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "appwal")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "app.wal")
+
+	w, err := Open(file, 100)
+	require.NoError(t, err)
+	for h := int64(1); h <= 6; h++ {
+		require.NoError(t, w.Write(Record{Type: BeginType, Height: h}))
+		require.NoError(t, w.Write(Record{Type: CommittedType, Height: h}))
+	}
+	require.NoError(t, w.Close())
+
+	records, err := Read(file)
+	require.NoError(t, err)
+	require.NotEmpty(t, records)
+	// the blocks before the last two logs are gone, the ones left follow
+	// each other
+	assert.True(t, records[0].Height > 1)
+	for i, r := range records {
+		assert.Equal(t, records[0].Height+int64(i/2), r.Height)
+	}
+	assert.Equal(t, int64(6), records[len(records)-1].Height)
+}
@@ -0,0 +1,182 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/dora/ultron/backend/ethereum"
+)
+
+// AccessTuple is an account a tx touches and the storage slots of it the
+// tx reads or writes.
+type AccessTuple struct {
+	Address     common.Address `json:"address"`
+	StorageKeys []common.Hash  `json:"storageKeys"`
+}
+
+// AccessListResult is what a tx touches and the gas it needs.
+type AccessListResult struct {
+	AccessList []AccessTuple  `json:"accessList"`
+	GasUsed    hexutil.Uint64 `json:"gasUsed"`
+	Gas        hexutil.Uint64 `json:"gas"` // lowest gas limit the tx runs with
+}
+
+// CreateAccessList runs the message of args on the state of the block
+// blockNr, the pending block if not given, and returns the accounts and
+// storage slots it touched, as the parallel executor traces them, with the
+// lowest gas limit it runs with. The gas of args, else the gas limit of
+// the block, bounds the search; the limits of eth_call apply.
+func (api *PublicChainAPI) CreateAccessList(ctx context.Context, args CallArgs, blockNr *BlockNumber) (*AccessListResult, error) {
+	number := rpc.PendingBlockNumber
+	if blockNr != nil {
+		number = rpc.BlockNumber(*blockNr)
+	}
+	st, header, err := api.b.ethereum.ApiBackend.StateAndHeaderByNumber(ctx, number)
+	if st == nil || err != nil {
+		return nil, err
+	}
+
+	limits := api.b.limits
+	var cancel context.CancelFunc
+	if limits.CallTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, limits.CallTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	hi := args.Gas.ToInt().Uint64()
+	if hi == 0 {
+		hi = header.GasLimit.Uint64()
+	}
+	if limits.CallGasCap > 0 && hi > limits.CallGasCap {
+		hi = limits.CallGasCap
+	}
+
+	trace := ethereum.NewContractTrace(nil)
+	used, err := api.b.simulate(ctx, st, header, args, hi, trace)
+	if err != nil {
+		return nil, err
+	}
+	if used >= hi {
+		return nil, fmt.Errorf("tx runs out of gas with %d gas", hi)
+	}
+
+	// the gas used may not do as the limit: refunds come off it, and calls
+	// keep a part of the gas left back. A tx running out of gas uses it all.
+	lo := used
+	if lo > 0 {
+		lo--
+	}
+	for lo+1 < hi {
+		mid := (lo + hi) / 2
+		midUsed, err := api.b.simulate(ctx, st, header, args, mid, nil)
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("estimation aborted after %v", limits.CallTimeout)
+		}
+		if err != nil || midUsed >= mid {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+
+	return &AccessListResult{
+		AccessList: accessList(trace, args),
+		GasUsed:    hexutil.Uint64(used),
+		Gas:        hexutil.Uint64(hi),
+	}, nil
+}
+
+// simulate runs the message of args with gas on a copy of st, tracing its
+// state accesses to trace if given, and returns the gas it used.
+func (b *Backend) simulate(ctx context.Context, st *state.StateDB, header *ethTypes.Header, args CallArgs, gas uint64, trace *ethereum.ContractTrace) (uint64, error) {
+	st = st.Copy()
+	if trace != nil {
+		st.SetStateTrace(trace)
+	}
+	msg := b.callMessage(args, new(big.Int).SetUint64(gas))
+	evm, vmError, err := b.ethereum.ApiBackend.GetEVM(ctx, msg, st, header, vm.Config{})
+	if err != nil {
+		return 0, err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			evm.Cancel()
+		case <-done:
+		}
+	}()
+
+	gp := new(core.GasPool).AddGas(math.MaxBig256)
+	_, used, err := core.ApplyMessage(evm, msg, gp)
+	if err := vmError(); err != nil {
+		return 0, err
+	}
+	if err != nil {
+		return 0, err
+	}
+	return used.Uint64(), ctx.Err()
+}
+
+// accessList returns the accounts and slots of trace, with the recipient of
+// args, sorted.
+func accessList(trace *ethereum.ContractTrace, args CallArgs) []AccessTuple {
+	slots := make(map[common.Address]map[common.Hash]bool)
+	touch := func(addr common.Address) map[common.Hash]bool {
+		if slots[addr] == nil {
+			slots[addr] = make(map[common.Hash]bool)
+		}
+		return slots[addr]
+	}
+	if args.To != nil {
+		touch(*args.To)
+	}
+	for addr := range trace.LoadedBalances() {
+		touch(addr)
+	}
+	for addr := range trace.ChangedBalances() {
+		touch(addr)
+	}
+	for addr := range trace.CreatedContracts() {
+		touch(addr)
+	}
+	for _, storages := range []map[common.Address]vm.Storage{trace.LoadedValues(), trace.ChangedValues()} {
+		for addr, storage := range storages {
+			keys := touch(addr)
+			for key := range storage {
+				keys[key] = true
+			}
+		}
+	}
+
+	list := make([]AccessTuple, 0, len(slots))
+	for addr, keys := range slots {
+		tuple := AccessTuple{Address: addr, StorageKeys: make([]common.Hash, 0, len(keys))}
+		for key := range keys {
+			tuple.StorageKeys = append(tuple.StorageKeys, key)
+		}
+		sort.Slice(tuple.StorageKeys, func(i, j int) bool {
+			return bytes.Compare(tuple.StorageKeys[i][:], tuple.StorageKeys[j][:]) < 0
+		})
+		list = append(list, tuple)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return bytes.Compare(list[i].Address[:], list[j].Address[:]) < 0
+	})
+	return list
+}
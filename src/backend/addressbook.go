@@ -0,0 +1,80 @@
+package backend
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/dora/ultron/addressbook"
+)
+
+var errNoAddressBook = errors.New("the address book isn't loaded")
+
+// SetAddressBook lets the personal API manage the watch-only accounts of bk.
+func (b *Backend) SetAddressBook(bk *addressbook.Book) {
+	b.addressBook = bk
+}
+
+// WatchedAccount is a watch-only account with its state at the latest block.
+type WatchedAccount struct {
+	addressbook.Entry
+	Balance  *hexutil.Big   `json:"balance"`
+	Nonce    hexutil.Uint64 `json:"nonce"` // txs it sent
+	Contract bool           `json:"contract"`
+}
+
+// PrivateWatchAPI manages the watch-only accounts in the personal namespace.
+type PrivateWatchAPI struct {
+	b *Backend
+}
+
+// NewPrivateWatchAPI creates the watch-only account API of b.
+func NewPrivateWatchAPI(b *Backend) *PrivateWatchAPI {
+	return &PrivateWatchAPI{b}
+}
+
+// WatchAddress adds addr to the address book under label, relabeling it if
+// it was watched already.
+func (api *PrivateWatchAPI) WatchAddress(addr common.Address, label string) (addressbook.Entry, error) {
+	if api.b.addressBook == nil {
+		return addressbook.Entry{}, errNoAddressBook
+	}
+	return api.b.addressBook.Watch(addr, label)
+}
+
+// UnwatchAddress removes the address or label target from the address book,
+// false if it wasn't in it.
+func (api *PrivateWatchAPI) UnwatchAddress(target string) (bool, error) {
+	if api.b.addressBook == nil {
+		return false, errNoAddressBook
+	}
+	return api.b.addressBook.Unwatch(target)
+}
+
+// WatchedAccounts returns the watch-only accounts with their balance and
+// nonce at the latest block.
+func (api *PrivateWatchAPI) WatchedAccounts() ([]WatchedAccount, error) {
+	if api.b.addressBook == nil {
+		return nil, errNoAddressBook
+	}
+	entries, err := api.b.addressBook.List()
+	if err != nil {
+		return nil, err
+	}
+	st, err := api.b.Ethereum().BlockChain().State()
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]WatchedAccount, len(entries))
+	for i, e := range entries {
+		accounts[i] = WatchedAccount{
+			Entry:    e,
+			Balance:  (*hexutil.Big)(st.GetBalance(e.Address)),
+			Nonce:    hexutil.Uint64(st.GetNonce(e.Address)),
+			Contract: st.GetCodeSize(e.Address) > 0,
+		}
+	}
+	return accounts, nil
+}
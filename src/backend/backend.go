@@ -2,6 +2,8 @@ package backend
 
 import (
 	"math/big"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -16,8 +18,15 @@ import (
 	tmn "github.com/tendermint/tendermint/node"
 	rpcClient "github.com/tendermint/tendermint/rpc/client"
 
+	"github.com/dora/ultron/addressbook"
 	"github.com/dora/ultron/backend/ethereum"
-	emtTypes "github.com/dora/ultron/backend/types"
+	"github.com/dora/ultron/backup"
+	"github.com/dora/ultron/banlist"
+	"github.com/dora/ultron/chaos"
+	"github.com/dora/ultron/dev"
+	"github.com/dora/ultron/ordering"
+	"github.com/dora/ultron/signer"
+	"github.com/dora/ultron/storeprune"
 )
 
 //----------------------------------------------------------------------
@@ -48,6 +57,37 @@ type Backend struct {
 
 	// moved from txpool.pendingState
 	managedState *state.ManagedState
+
+	// optional hook reporting how long each block commit takes
+	commitObserver func(time.Duration)
+
+	// holds block production on dev chains, nil when consensus runs freely
+	miner *dev.Miner
+	// shifts block times on dev chains, nil outside dev mode
+	clock *dev.Clock
+	// snapshots the chain state on dev chains, nil outside dev mode
+	snapshotter dev.Snapshotter
+	// peers refused by tendermint, set once the node started
+	banList *banlist.BanList
+	// filters of the eth filter API, see PublicFilterAPI
+	filterStore FilterStore
+	// bounds of the expensive RPC requests
+	limits QueryLimits
+	// watch-only accounts, see PrivateWatchAPI
+	addressBook *addressbook.Book
+	// rewind found at start, posted as tendermint replays the first block
+	recovery *ChainRewindEvent
+	// audits the order of the txs of blocks, nil when off
+	auditor *ordering.Auditor
+	// ether allocated by the genesis, summed at the first supply request
+	genesisSupply    *big.Int
+	genesisSupplyMtx sync.Mutex
+	// signs the txs of eth_sendTransaction, nil to use the keystore
+	remoteSigner *signer.Client
+	// takes the checkpoints of admin_checkpoint, nil until set
+	checkpoint func() (*backup.Checkpoint, error)
+	// deletes the old versions of the app state, nil when off
+	pruner *storeprune.Pruner
 }
 
 // NewBackend creates a new Backend
@@ -75,10 +115,12 @@ func NewBackend(ctx *node.ServiceContext, ethConfig *eth.Config,
 	ethereum.BlockChain().SetValidator(NullBlockProcessor{})
 
 	ethBackend := &Backend{
-		ethereum:  ethereum,
-		ethConfig: ethConfig,
-		es:        es,
-		client:    client,
+		ethereum:    ethereum,
+		ethConfig:   ethConfig,
+		es:          es,
+		client:      client,
+		filterStore: NewMemFilterStore(),
+		recovery:    recoveredRewind(ethereum.BlockChain()),
 	}
 	ethBackend.ResetState()
 	return ethBackend, nil
@@ -156,7 +198,23 @@ func (b *Backend) AccumulateRewards(strategy *emtTypes.Strategy) {
 // Commit finalises the current block
 // #unstable
 func (b *Backend) Commit(receiver common.Address) (common.Hash, error) {
-	return b.es.Commit(receiver)
+	if b.recovery != nil {
+		b.ethereum.EventMux().Post(*b.recovery) // nolint: errcheck
+		b.recovery = nil
+	}
+	start := time.Now()
+	hash, err := b.es.Commit(receiver)
+	if b.commitObserver != nil {
+		b.commitObserver(time.Since(start))
+	}
+	return hash, err
+}
+
+// SetCommitObserver registers a hook receiving the duration of every commit.
+// It should be set before the node starts producing blocks.
+// #unstable
+func (b *Backend) SetCommitObserver(fn func(time.Duration)) {
+	b.commitObserver = fn
 }
 
 func (b *Backend) EndBlock() {
@@ -167,6 +225,12 @@ func (b *Backend) GetTotalUsedGasFee() *big.Int {
 	return b.es.TotalUsedGasFee
 }
 
+// GetBlockGasUsage returns the gas the block ended last used, and its gas
+// limit.
+func (b *Backend) GetBlockGasUsage() (used, limit *big.Int) {
+	return b.es.TotalUsedGas, b.es.BlockGasLimit
+}
+
 // InitEthState initializes the EthState
 // #unstable
 func (b *Backend) InitEthState(receiver common.Address) error {
@@ -176,10 +240,51 @@ func (b *Backend) InitEthState(receiver common.Address) error {
 // UpdateHeaderWithTimeInfo uses the tendermint header to update the ethereum header
 // #unstable
 func (b *Backend) UpdateHeaderWithTimeInfo(tmHeader *abciTypes.Header) {
-	b.es.UpdateHeaderWithTimeInfo(b.ethereum.ApiBackend.ChainConfig(), uint64(tmHeader.Time),
+	blockTime := uint64(tmHeader.Time)
+	if b.clock != nil {
+		blockTime = b.clock.BlockTime(blockTime)
+	}
+	b.es.UpdateHeaderWithTimeInfo(b.ethereum.ApiBackend.ChainConfig(), blockTime,
 		uint64(tmHeader.GetNumTxs()), tmHeader.Proposer)
 }
 
+// Rewind drops the blocks after number and makes the next block build on it.
+// It must not race with block processing, dev chains call it between blocks.
+// #unstable
+func (b *Backend) Rewind(number uint64) error {
+	ev, err := b.rewind(number, RewindRevert)
+	if err != nil {
+		return err
+	}
+	if ev != nil {
+		b.ethereum.EventMux().Post(*ev) // nolint: errcheck
+	}
+	// let the tx pool drop what it learned from the dropped blocks
+	b.ethereum.EventMux().Post(core.ChainHeadEvent{b.ethereum.BlockChain().CurrentBlock()}) // nolint: vet, errcheck
+	return nil
+}
+
+// rewind sets the head of the chain back to number and resets the states
+// built on it, returning the rewind done, nil if the head didn't go back
+func (b *Backend) rewind(number uint64, reason string) (*ChainRewindEvent, error) {
+	chain := b.ethereum.BlockChain()
+	from := chain.CurrentBlock().NumberU64()
+	if err := chain.SetHead(number); err != nil {
+		return nil, err
+	}
+	if err := b.es.ResetWorkState(common.Address{}); err != nil {
+		return nil, err
+	}
+	if _, err := b.ResetState(); err != nil {
+		return nil, err
+	}
+	head := chain.CurrentBlock()
+	if head.NumberU64() >= from {
+		return nil, nil
+	}
+	return &ChainRewindEvent{From: from, To: head.NumberU64(), Hash: head.Hash(), Reason: reason}, nil
+}
+
 // GasLimit returns the maximum gas per block
 // #unstable
 func (b *Backend) GasLimit() big.Int {
@@ -191,6 +296,57 @@ func (b *Backend) AddNonce(addr common.Address) {
 	b.es.AddNonce(addr)
 }
 
+// SetCoinbase makes addr the coinbase of the block being built, which gets
+// the tips of its txs.
+func (b *Backend) SetCoinbase(addr common.Address) {
+	b.es.SetCoinbase(addr)
+}
+
+// PayTips makes the block being built pay the tips of its txs to the
+// coinbase and burn their base fees.
+func (b *Backend) PayTips() {
+	b.es.PayTips()
+}
+
+// Balance returns the balance of addr in the state of the block being run.
+func (b *Backend) Balance(addr common.Address) *big.Int {
+	return b.es.Balance(addr)
+}
+
+// HasCode tells if addr is a contract in the state of the block being run.
+func (b *Backend) HasCode(addr common.Address) bool {
+	return b.es.HasCode(addr)
+}
+
+// called by ultron tx only in deliver_tx
+func (b *Backend) Transfer(from, to common.Address, amount *big.Int) error {
+	return b.es.Transfer(from, to, amount)
+}
+
+// TxGasUsed returns the gas used by the tx of hash run in the block, false
+// if it wasn't.
+func (b *Backend) TxGasUsed(hash common.Hash) (*big.Int, bool) {
+	return b.es.TxGasUsed(hash)
+}
+
+// ChargeFee takes the fee of an ultron tx from from, see
+// ethereum.EthState.ChargeFee.
+func (b *Backend) ChargeFee(from common.Address, fee *big.Int) error {
+	return b.es.ChargeFee(from, fee)
+}
+
+// LastSupplyChange returns the ether minted and burnt by the block committed
+// last.
+func (b *Backend) LastSupplyChange() ethereum.SupplyChange {
+	return b.es.LastSupplyChange()
+}
+
+// SystemCall calls the contract of the system call parameter key, see
+// ethereum.EthState.SystemCall.
+func (b *Backend) SystemCall(key string, selector []byte) (uint64, error) {
+	return b.es.SystemCall(key, selector)
+}
+
 //----------------------------------------------------------------------
 // Implements: node.Service
 
@@ -212,9 +368,159 @@ func (b *Backend) APIs() []rpc.API {
 		}
 		retApis = append(retApis, v)
 	}
+	retApis = append(retApis, rpc.API{
+		// overrides the filter methods of go-ethereum registered above
+		Namespace: "eth",
+		Version:   "1.0",
+		Service:   NewPublicFilterAPI(b),
+		Public:    true,
+	}, rpc.API{
+		Namespace: "eth",
+		Version:   "1.0",
+		Service:   NewPublicCallAPI(b),
+		Public:    true,
+	}, rpc.API{
+		Namespace: "eth",
+		Version:   "1.0",
+		Service:   NewPublicStateAPI(b),
+		Public:    true,
+	}, rpc.API{
+		Namespace: "eth",
+		Version:   "1.0",
+		Service:   NewPublicReceiptAPI(b),
+		Public:    true,
+	})
+	if blocks := newPublicFinalizedBlockAPI(apis); blocks != nil {
+		retApis = append(retApis, rpc.API{
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   blocks,
+			Public:    true,
+		})
+	}
+	retApis = append(retApis, b.ultronAPIs()...)
+	retApis = append(retApis, rpc.API{
+		Namespace: "bank",
+		Version:   "1.0",
+		Service:   NewPublicBankAPI(b),
+		Public:    true,
+	}, rpc.API{
+		Namespace: "stake",
+		Version:   "1.0",
+		Service:   NewPublicStakeAPI(b),
+		Public:    true,
+	}, rpc.API{
+		Namespace: "params",
+		Version:   "1.0",
+		Service:   NewPublicParamsAPI(b),
+		Public:    true,
+	}, rpc.API{
+		Namespace: "emergency",
+		Version:   "1.0",
+		Service:   NewPublicEmergencyAPI(b),
+		Public:    true,
+	}, rpc.API{
+		Namespace: "community",
+		Version:   "1.0",
+		Service:   NewPublicCommunityAPI(b),
+		Public:    true,
+	}, rpc.API{
+		Namespace: "evm",
+		Version:   "1.0",
+		Service:   NewPublicEVMAPI(b),
+		Public:    true,
+	}, rpc.API{
+		Namespace: "debug",
+		Version:   "1.0",
+		Service:   NewPrivateDebugAPI(b),
+		Public:    false,
+	}, rpc.API{
+		Namespace: "admin",
+		Version:   "1.0",
+		Service:   NewPrivateBanAPI(b),
+		Public:    false,
+	}, rpc.API{
+		Namespace: "admin",
+		Version:   "1.0",
+		Service:   NewPrivateMempoolAPI(b),
+		Public:    false,
+	}, rpc.API{
+		Namespace: "admin",
+		Version:   "1.0",
+		Service:   NewPrivateBackupAPI(b),
+		Public:    false,
+	}, rpc.API{
+		Namespace: "personal",
+		Version:   "1.0",
+		Service:   NewPrivateWatchAPI(b),
+		Public:    false,
+	}, rpc.API{
+		Namespace: "personal",
+		Version:   "1.0",
+		Service:   NewPrivateTypedDataAPI(b),
+		Public:    false,
+	}, rpc.API{
+		Namespace: "personal",
+		Version:   "1.0",
+		Service:   NewPrivateSignTxAPI(b),
+		Public:    false,
+	})
+	if b.remoteSigner != nil {
+		retApis = append(retApis, rpc.API{
+			// overrides eth_accounts, eth_sendTransaction and
+			// eth_signTransaction of go-ethereum
+			Namespace: "eth",
+			Version:   "1.0",
+			Service:   NewPublicRemoteSignerAPI(b),
+			Public:    true,
+		})
+	}
+	if chaos.Enabled {
+		retApis = append(retApis, rpc.API{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   chaos.NewPrivateAdminAPI(),
+			Public:    false,
+		})
+	}
 	return retApis
 }
 
+// ultronAPIs returns the services of the ultron namespace, see OpenRPC.
+func (b *Backend) ultronAPIs() []rpc.API {
+	return []rpc.API{{
+		Namespace: "ultron",
+		Version:   "1.0",
+		Service:   NewPublicDevAPI(b),
+		Public:    true,
+	}, {
+		Namespace: "ultron",
+		Version:   "1.0",
+		Service:   NewPublicNameAPI(b),
+		Public:    true,
+	}, {
+		Namespace: "ultron",
+		Version:   "1.0",
+		Service:   NewPublicChainAPI(b),
+		Public:    true,
+	}, {
+		Namespace: "ultron",
+		Version:   "1.0",
+		Service:   NewPublicNodeAPI(b),
+		Public:    true,
+	}, {
+		Namespace: "ultron",
+		Version:   "1.0",
+		Service:   NewPublicBroadcastAPI(b),
+		Public:    true,
+	}, {
+		Namespace: "ultron",
+		Version:   "1.0",
+		Service:   NewPublicBlocksAPI(b),
+		Public:    true,
+	}}
+}
+
 // Start implements node.Service, starting all internal goroutines needed by the
 // Ethereum protocol implementation.
 // #stable
@@ -0,0 +1,36 @@
+package backend
+
+import (
+	"errors"
+
+	"github.com/dora/ultron/backup"
+)
+
+var errNoCheckpoint = errors.New("the node isn't started yet")
+
+// SetCheckpointer lets checkpoint take the checkpoints of
+// admin_checkpoint.
+func (b *Backend) SetCheckpointer(checkpoint func() (*backup.Checkpoint, error)) {
+	b.checkpoint = checkpoint
+}
+
+// PrivateBackupAPI checkpoints the databases of the node while it runs, in
+// the admin namespace, for ultron backup create to upload them.
+type PrivateBackupAPI struct {
+	b *Backend
+}
+
+// NewPrivateBackupAPI creates the backup API of b.
+func NewPrivateBackupAPI(b *Backend) *PrivateBackupAPI {
+	return &PrivateBackupAPI{b}
+}
+
+// Checkpoint copies the databases of the node, as of the last block
+// committed, into a new directory of its home, which the caller removes
+// once done with it.
+func (api *PrivateBackupAPI) Checkpoint() (*backup.Checkpoint, error) {
+	if api.b.checkpoint == nil {
+		return nil, errNoCheckpoint
+	}
+	return api.b.checkpoint()
+}
@@ -0,0 +1,42 @@
+package backend
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/dora/ultron/modules/bank"
+)
+
+// PublicBankAPI reads the native assets of the bank module.
+type PublicBankAPI struct {
+	b *Backend
+}
+
+// NewPublicBankAPI creates the bank namespace API of b.
+func NewPublicBankAPI(b *Backend) *PublicBankAPI {
+	return &PublicBankAPI{b}
+}
+
+// GetAsset returns the asset issued as denom.
+func (api *PublicBankAPI) GetAsset(denom string) (*bank.Asset, error) {
+	value, err := api.b.queryKey(bank.AssetKey(denom))
+	if err != nil {
+		return nil, err
+	}
+	if len(value) == 0 {
+		return nil, fmt.Errorf("asset %s is not issued", denom)
+	}
+	return bank.ParseAsset(value)
+}
+
+// GetBalance returns the balance of addr in denom, in base units.
+func (api *PublicBankAPI) GetBalance(denom string, addr common.Address) (*hexutil.Big, error) {
+	value, err := api.b.queryKey(bank.BalanceKey(denom, addr))
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(new(big.Int).SetBytes(value)), nil
+}
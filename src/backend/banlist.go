@@ -0,0 +1,58 @@
+package backend
+
+import (
+	"errors"
+	"time"
+
+	"github.com/dora/ultron/banlist"
+)
+
+var errNoBanList = errors.New("the ban list isn't loaded yet")
+
+// SetBanList lets the admin API manage the bans of l.
+func (b *Backend) SetBanList(l *banlist.BanList) {
+	b.banList = l
+}
+
+// PrivateBanAPI bans peers in the admin namespace.
+type PrivateBanAPI struct {
+	b *Backend
+}
+
+// NewPrivateBanAPI creates the ban API of b.
+func NewPrivateBanAPI(b *Backend) *PrivateBanAPI {
+	return &PrivateBanAPI{b}
+}
+
+// BanPeer bans target, a node ID or an IP, for the given seconds, for good
+// when they are left out. Connected peers it matches are dropped.
+func (api *PrivateBanAPI) BanPeer(target string, seconds *uint64, reason *string) (banlist.Entry, error) {
+	if api.b.banList == nil {
+		return banlist.Entry{}, errNoBanList
+	}
+	var d time.Duration
+	if seconds != nil {
+		d = time.Duration(*seconds) * time.Second
+	}
+	why := "banned by admin"
+	if reason != nil {
+		why = *reason
+	}
+	return api.b.banList.Ban(target, d, why)
+}
+
+// UnbanPeer lifts the ban of target, false if it wasn't banned.
+func (api *PrivateBanAPI) UnbanPeer(target string) (bool, error) {
+	if api.b.banList == nil {
+		return false, errNoBanList
+	}
+	return api.b.banList.Unban(target)
+}
+
+// BannedPeers returns the bans in effect.
+func (api *PrivateBanAPI) BannedPeers() ([]banlist.Entry, error) {
+	if api.b.banList == nil {
+		return nil, errNoBanList
+	}
+	return api.b.banList.List(), nil
+}
@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// fields of the blocks of ultron_getBlocks
+const (
+	BlockFieldsHeaders = "headers" // the headers alone
+	BlockFieldsHashes  = "hashes"  // the headers and the hashes of the txs
+	BlockFieldsFull    = "full"    // the headers and the txs
+)
+
+const (
+	defaultBlocksPerPage = 100
+	maxBlocksPerPage     = 1000
+)
+
+// PublicBlocksAPI returns ranges of blocks in one call, for the backfills of
+// explorers and the audit tools.
+type PublicBlocksAPI struct {
+	b *Backend
+}
+
+// NewPublicBlocksAPI creates the block range API of b.
+func NewPublicBlocksAPI(b *Backend) *PublicBlocksAPI {
+	return &PublicBlocksAPI{b}
+}
+
+// BlockFilter selects what ultron_getBlocks returns of the blocks.
+type BlockFilter struct {
+	Fields string `json:"fields"` // full when left out
+	Limit  int    `json:"limit"`  // blocks of a page, 100 when left out
+}
+
+// RangeBlock is a block of ultron_getBlocks.
+type RangeBlock struct {
+	Hash         common.Hash             `json:"hash"`
+	Header       *ethTypes.Header        `json:"header"`
+	TxHashes     []common.Hash           `json:"transactionHashes,omitempty"`
+	Transactions []*ethTypes.Transaction `json:"transactions,omitempty"`
+}
+
+// BlockRange is a page of the blocks of ultron_getBlocks.
+type BlockRange struct {
+	Blocks []*RangeBlock `json:"blocks"`
+	// the from of the next page, left out once the range is done
+	Next *hexutil.Uint64 `json:"next,omitempty"`
+}
+
+// GetBlocks returns the blocks from from to to, both included, a page at a
+// time: when a page ends before to, call again from its next. The blocks
+// past the latest one are left out.
+func (api *PublicBlocksAPI) GetBlocks(ctx context.Context, from, to BlockNumber, filter *BlockFilter) (*BlockRange, error) {
+	fields, limit := BlockFieldsFull, defaultBlocksPerPage
+	if filter != nil {
+		if filter.Fields != "" {
+			fields = filter.Fields
+		}
+		if filter.Limit != 0 {
+			limit = filter.Limit
+		}
+	}
+	switch fields {
+	case BlockFieldsHeaders, BlockFieldsHashes, BlockFieldsFull:
+	default:
+		return nil, fmt.Errorf("unknown fields %q, use %s, %s or %s", fields, BlockFieldsHeaders, BlockFieldsHashes, BlockFieldsFull)
+	}
+	if limit < 1 || limit > maxBlocksPerPage {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxBlocksPerPage)
+	}
+
+	chain := api.b.ethereum.BlockChain()
+	head := chain.CurrentBlock().NumberU64()
+	first, last := rangeEnd(from, head), rangeEnd(to, head)
+	if first > last {
+		return nil, fmt.Errorf("block range %d-%d is empty", first, last)
+	}
+
+	res := &BlockRange{Blocks: []*RangeBlock{}}
+	for n := first; n <= last && n <= head; n++ {
+		if len(res.Blocks) == limit {
+			next := hexutil.Uint64(n)
+			res.Next = &next
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := rangeBlock(chain, n, fields)
+		if err != nil {
+			return nil, err
+		}
+		res.Blocks = append(res.Blocks, block)
+	}
+	return res, nil
+}
+
+// rangeEnd is the number of the block n of a range, the latest one for the
+// tags
+func rangeEnd(n BlockNumber, head uint64) uint64 {
+	switch rpc.BlockNumber(n) {
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+		return head
+	}
+	return uint64(n)
+}
+
+// rangeBlock reads the fields of the block number, only loading the body
+// when the txs are asked for
+func rangeBlock(chain *core.BlockChain, number uint64, fields string) (*RangeBlock, error) {
+	if fields == BlockFieldsHeaders {
+		h := chain.GetHeaderByNumber(number)
+		if h == nil {
+			return nil, fmt.Errorf("block #%d not found", number)
+		}
+		return &RangeBlock{Hash: h.Hash(), Header: h}, nil
+	}
+
+	b := chain.GetBlockByNumber(number)
+	if b == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	res := &RangeBlock{Hash: b.Hash(), Header: b.Header()}
+	if fields == BlockFieldsFull {
+		res.Transactions = b.Transactions()
+		return res, nil
+	}
+	for _, tx := range b.Transactions() {
+		res.TxHashes = append(res.TxHashes, tx.Hash())
+	}
+	return res, nil
+}
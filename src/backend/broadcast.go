@@ -0,0 +1,140 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	tmTypes "github.com/tendermint/tendermint/types"
+)
+
+// modes of ultron_broadcastTransaction, those of the tendermint rpc
+const (
+	BroadcastAsync  = "async"  // returns once the tx is handed over, unchecked
+	BroadcastSync   = "sync"   // returns the result of CheckTx
+	BroadcastCommit = "commit" // waits for the block of the tx too
+)
+
+// PublicBroadcastAPI sends signed txs straight to tendermint, waiting for
+// as much of their fate as asked.
+type PublicBroadcastAPI struct {
+	b *Backend
+}
+
+// NewPublicBroadcastAPI creates the broadcast API of b.
+func NewPublicBroadcastAPI(b *Backend) *PublicBroadcastAPI {
+	return &PublicBroadcastAPI{b}
+}
+
+// BroadcastResult is what is known of a tx broadcast.
+type BroadcastResult struct {
+	Hash common.Hash `json:"hash"`
+
+	// of CheckTx, left out in async mode
+	Code *uint32 `json:"code,omitempty"`
+	Log  string  `json:"log,omitempty"`
+
+	// of the block of the tx in commit mode, committed is false when it
+	// wasn't included before the timeout
+	Committed   bool            `json:"committed"`
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"`
+	BlockHash   *common.Hash    `json:"blockHash,omitempty"`
+	GasUsed     *hexutil.Big    `json:"gasUsed,omitempty"`
+}
+
+// BroadcastTransaction sends the signed tx raw, as eth_sendRawTransaction
+// takes it, to the mempool of tendermint. mode is sync when left out. In
+// commit mode the call waits for the block of the tx for timeout seconds,
+// at most the rpc_commit_timeout of the node, which is the default.
+func (api *PublicBroadcastAPI) BroadcastTransaction(ctx context.Context, raw hexutil.Bytes, mode *string,
+	timeout *hexutil.Uint64) (*BroadcastResult, error) {
+	if api.b.localClient == nil {
+		return nil, errNoMempool
+	}
+	tx := new(ethTypes.Transaction)
+	if err := rlp.DecodeBytes(raw, tx); err != nil {
+		return nil, err
+	}
+	m := BroadcastSync
+	if mode != nil {
+		m = *mode
+	}
+	res := &BroadcastResult{Hash: tx.Hash()}
+
+	switch m {
+	case BroadcastAsync:
+		go func() {
+			if _, err := api.b.localClient.BroadcastTxSync(raw, tmTypes.RawTx); err != nil {
+				log.Warn("Failed to broadcast the tx", "hash", res.Hash, "err", err)
+			}
+		}()
+		return res, nil
+	case BroadcastSync, BroadcastCommit:
+	default:
+		return nil, fmt.Errorf("unknown mode %q, use %s, %s or %s", m, BroadcastAsync, BroadcastSync, BroadcastCommit)
+	}
+
+	// the block may come before CheckTx returns
+	heads := make(chan core.ChainHeadEvent, 16)
+	if m == BroadcastCommit {
+		sub := api.b.SubscribeChainHead(heads)
+		defer sub.Unsubscribe()
+	}
+	checked, err := api.b.localClient.BroadcastTxSync(raw, tmTypes.RawTx)
+	if err != nil {
+		return nil, err
+	}
+	res.Code, res.Log = &checked.Code, checked.Log
+	if m == BroadcastSync || checked.Code != 0 {
+		return res, nil
+	}
+
+	wait := api.b.limits.CommitTimeout
+	if timeout != nil {
+		if d := time.Duration(*timeout) * time.Second; wait == 0 || d < wait {
+			wait = d
+		}
+	}
+	var expired <-chan time.Time
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for !api.committed(res) {
+		select {
+		case <-heads:
+		case <-expired:
+			return res, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return res, nil
+}
+
+// committed fills res with the block and the receipt of its tx, false if
+// no block has it yet
+func (api *PublicBroadcastAPI) committed(res *BroadcastResult) bool {
+	db := api.b.ethereum.ChainDb()
+	tx, blockHash, number, index := core.GetTransaction(db, res.Hash)
+	if tx == nil {
+		return false
+	}
+	receipts := core.GetBlockReceipts(db, blockHash, number)
+	if int(index) >= len(receipts) {
+		return false
+	}
+	receipt := receipts[index]
+	res.Committed = true
+	res.BlockNumber = (*hexutil.Uint64)(&number)
+	res.BlockHash = &blockHash
+	res.GasUsed = (*hexutil.Big)(receipt.GasUsed)
+	return true
+}
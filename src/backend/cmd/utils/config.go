@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"fmt"
 	"math/big"
 	"os"
+	"path/filepath"
 
 	cli "gopkg.in/urfave/cli.v1"
 
@@ -13,6 +15,7 @@ import (
 
 	"github.com/dora/ultron/backend"
 	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/signer"
 	rpcClient "github.com/tendermint/tendermint/rpc/client"
 )
 
@@ -21,6 +24,9 @@ const (
 	clientIdentifier = "ultron"
 	// Environment variable for home dir
 	emHome = "EMHOME"
+
+	// IPCFile is the unix socket of the full RPC, in the home of the node.
+	IPCFile = "ultron.ipc"
 )
 
 var (
@@ -41,20 +47,47 @@ type gethConfig struct {
 
 // MakeFullNode creates a full go-ethereum node
 // #unstable
-func MakeFullNode(ctx *cli.Context) *ethereum.Node {
-	stack, cfg := makeConfigNode(ctx)
+func MakeFullNode(ctx *cli.Context) (*ethereum.Node, error) {
+	stack, cfg, err := makeConfigNode(ctx)
+	if err != nil {
+		return nil, err
+	}
 
 	tendermintLAddr := ctx.GlobalString(TendermintAddrFlag.Name)
+	filterDir := ctx.GlobalString(FilterDirFlag.Name)
+	signerURL, signerToken := ctx.GlobalString(SignerURLFlag.Name), ctx.GlobalString(SignerTokenFlag.Name)
+	limits := backend.QueryLimits{
+		LogsBlockRange: ctx.GlobalUint64(RPCLogsBlockRangeFlag.Name),
+		CallGasCap:     ctx.GlobalUint64(RPCCallGasCapFlag.Name),
+		CallTimeout:    ctx.GlobalDuration(RPCCallTimeoutFlag.Name),
+		DumpEntries:    ctx.GlobalUint64(RPCDumpEntriesFlag.Name),
+		CommitTimeout:  ctx.GlobalDuration(RPCCommitTimeoutFlag.Name),
+	}
 	if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
-		return backend.NewBackend(ctx, &cfg.Eth, rpcClient.NewHTTP(tendermintLAddr, "/websocket"))
+		b, err := backend.NewBackend(ctx, &cfg.Eth, rpcClient.NewHTTP(tendermintLAddr, "/websocket"))
+		if err != nil {
+			return nil, err
+		}
+		b.SetQueryLimits(limits)
+		if dir := filterDir; dir != "" {
+			store, err := backend.NewDirFilterStore(dir)
+			if err != nil {
+				return nil, err
+			}
+			b.SetFilterStore(store)
+		}
+		if signerURL != "" {
+			b.SetRemoteSigner(signer.NewClient(signerURL, signerToken))
+		}
+		return b, nil
 	}); err != nil {
-		ethUtils.Fatalf("Failed to register the ABCI application service: %v", err)
+		return nil, fmt.Errorf("Failed to register the ABCI application service: %v", err)
 	}
 
-	return stack
+	return stack, nil
 }
 
-func makeConfigNode(ctx *cli.Context) (*ethereum.Node, gethConfig) {
+func makeConfigNode(ctx *cli.Context) (*ethereum.Node, gethConfig, error) {
 	cfg := gethConfig{
 		Eth:  eth.DefaultConfig,
 		Node: DefaultNodeConfig(),
@@ -62,15 +95,28 @@ func makeConfigNode(ctx *cli.Context) (*ethereum.Node, gethConfig) {
 
 	ethUtils.SetNodeConfig(ctx, &cfg.Node)
 	SetEthermintNodeConfig(&cfg.Node)
+	memDB := ctx.GlobalBool(MemDBFlag.Name)
+	if memDB {
+		SetMemDBNodeConfig(&cfg.Node)
+	}
 	stack, err := ethereum.New(&cfg.Node)
 	if err != nil {
-		ethUtils.Fatalf("Failed to create the protocol stack: %v", err)
+		return nil, cfg, fmt.Errorf("Failed to create the protocol stack: %v", err)
 	}
 
 	ethUtils.SetEthConfig(ctx, &stack.Node, &cfg.Eth)
 	SetEthermintEthConfig(&cfg.Eth)
+	if memDB {
+		// nothing was written by init, start from the default genesis
+		genesis, err := ParseGenesisOrDefault("")
+		if err != nil {
+			return nil, cfg, fmt.Errorf("Failed to parse the default genesis: %v", err)
+		}
+		genesis.Config.ChainId = new(big.Int).SetUint64(cfg.Eth.NetworkId)
+		cfg.Eth.Genesis = genesis
+	}
 
-	return stack, cfg
+	return stack, cfg, nil
 }
 
 // DefaultNodeConfig returns the default configuration for a go-ethereum node
@@ -81,7 +127,7 @@ func DefaultNodeConfig() node.Config {
 	cfg.Version = params.Version
 	cfg.HTTPModules = append(cfg.HTTPModules, "eth")
 	cfg.WSModules = append(cfg.WSModules, "eth")
-	cfg.IPCPath = "geth.ipc"
+	cfg.IPCPath = IPCFile
 
 	emHome := os.Getenv(emHome)
 	if emHome != "" {
@@ -96,6 +142,20 @@ func DefaultNodeConfig() node.Config {
 func SetEthermintNodeConfig(cfg *node.Config) {
 	cfg.P2P.MaxPeers = 0
 	cfg.P2P.NoDiscovery = true
+	// the p2p server is stopped right after start, don't hold a port
+	cfg.P2P.ListenAddr = ""
+}
+
+// SetMemDBNodeConfig makes go-ethereum open its databases in memory, which
+// it does for a node without data directory. The keystore stays on disk and
+// IPC is disabled as its endpoint would be shared by all such nodes.
+// #unstable
+func SetMemDBNodeConfig(cfg *node.Config) {
+	if cfg.KeyStoreDir == "" && cfg.DataDir != "" {
+		cfg.KeyStoreDir = filepath.Join(cfg.DataDir, "keystore")
+	}
+	cfg.DataDir = ""
+	cfg.IPCPath = ""
 }
 
 // SetEthermintEthConfig takes a ethereum configuration and applies ethermint specific configuration
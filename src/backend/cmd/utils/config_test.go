@@ -3,6 +3,7 @@ package utils
 import (
 	"flag"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"gopkg.in/urfave/cli.v1"
@@ -19,7 +20,10 @@ func TestEMHome(t *testing.T) {
 	// context with empty flag set
 	context := getContextNoFlag()
 
-	_, config := makeConfigNode(context)
+	_, config, err := makeConfigNode(context)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if config.Node.DataDir != emHomedir {
 		t.Errorf("DataDir is wrong: %s", config.Node.DataDir)
@@ -35,13 +39,37 @@ func TestEmHomeDataDir(t *testing.T) {
 	dir := "/tmp/dir2"
 	context := getContextDataDirFlag(dir)
 
-	_, config := makeConfigNode(context)
+	_, config, err := makeConfigNode(context)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if config.Node.DataDir != dir {
 		t.Errorf("DataDir is wrong: %s", config.Node.DataDir)
 	}
 }
 
+// memdb drops the data directory but keeps the keystore on disk
+func TestMemDBDataDir(t *testing.T) {
+	dir := "/tmp/dir2"
+	context := getContextMemDBFlag(dir)
+
+	_, config, err := makeConfigNode(context)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if config.Node.DataDir != "" {
+		t.Errorf("DataDir is wrong: %s", config.Node.DataDir)
+	}
+	if config.Node.KeyStoreDir != filepath.Join(dir, "keystore") {
+		t.Errorf("KeyStoreDir is wrong: %s", config.Node.KeyStoreDir)
+	}
+	if config.Eth.Genesis == nil {
+		t.Error("Genesis is not set")
+	}
+}
+
 // init cli.context with empty flag set
 func getContextNoFlag() *cli.Context {
 	set := flag.NewFlagSet("test", 0)
@@ -66,3 +94,17 @@ func getContextDataDirFlag(dir string) *cli.Context {
 
 	return ctx
 }
+
+func getContextMemDBFlag(dir string) *cli.Context {
+	set := flag.NewFlagSet("test", 0)
+	globalSet := flag.NewFlagSet("test", 0)
+	globalSet.String("datadir", node.DefaultDataDir(), "doc")
+	globalSet.Bool(MemDBFlag.Name, false, "doc")
+
+	globalCtx := cli.NewContext(nil, globalSet, nil)
+	ctx := cli.NewContext(nil, set, globalCtx)
+
+	globalSet.Parse([]string{"--datadir", dir, "--" + MemDBFlag.Name}) // nolint: errcheck
+
+	return ctx
+}
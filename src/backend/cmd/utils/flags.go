@@ -2,6 +2,8 @@
 package utils
 
 import (
+	"time"
+
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -64,4 +66,78 @@ var (
 		Usage: "If set, it will invoke `tendermint init` and `tendermint node` " +
 			"when `ethermint init` and `ethermint` are invoked respectively",
 	}
+
+	// MemDBFlag keeps the chain data in memory
+	// #unstable
+	MemDBFlag = cli.BoolFlag{
+		Name:  "memdb",
+		Usage: "Keep the chain data in memory, it is lost when the node stops",
+	}
+
+	// FilterDirFlag shares the eth filters between RPC frontends
+	// #unstable
+	FilterDirFlag = cli.StringFlag{
+		Name:  "filter_dir",
+		Usage: "Keep the eth filters in this directory, shared by the RPC frontends behind a load balancer",
+	}
+
+	// RPCMetricsFlag counts the RPC requests by method
+	// #unstable
+	RPCMetricsFlag = cli.BoolFlag{
+		Name:  "rpc_metrics",
+		Usage: "Count the HTTP RPC requests by method, see debug_queryStats",
+	}
+
+	// RPCSlowQueryFlag logs the slow RPC requests
+	// #unstable
+	RPCSlowQueryFlag = cli.DurationFlag{
+		Name:  "rpc_slow_query",
+		Usage: "Log the HTTP RPC requests taking this long or longer, 0 to log none (needs rpc_metrics)",
+	}
+
+	// ValidatorMetricsAddrFlag serves the performance of the validators
+	// #unstable
+	ValidatorMetricsAddrFlag = cli.StringFlag{
+		Name:  "validator_metrics_addr",
+		Usage: "Serve the performance of the validators on /metrics of this address in the Prometheus format, empty for none",
+	}
+
+	// limits of the expensive RPC requests, 0 for none
+	// #unstable
+	RPCLogsBlockRangeFlag = cli.Uint64Flag{
+		Name:  "rpc_logs_block_range",
+		Value: 10000,
+		Usage: "Most blocks eth_getLogs and log filters may go through, 0 for no limit",
+	}
+	RPCCallGasCapFlag = cli.Uint64Flag{
+		Name:  "rpc_call_gas_cap",
+		Value: 50000000,
+		Usage: "Most gas eth_call may use, 0 to run calls unmetered",
+	}
+	RPCCallTimeoutFlag = cli.DurationFlag{
+		Name:  "rpc_call_timeout",
+		Value: 5 * time.Second,
+		Usage: "Longest eth_call may run, 0 for no limit",
+	}
+	RPCDumpEntriesFlag = cli.Uint64Flag{
+		Name:  "rpc_dump_entries",
+		Value: 10000,
+		Usage: "Most accounts and storage slots debug_dumpBlock may return, 0 for no limit",
+	}
+	RPCCommitTimeoutFlag = cli.DurationFlag{
+		Name:  "rpc_commit_timeout",
+		Value: 30 * time.Second,
+		Usage: "Longest ultron_broadcastTransaction waits for the block of a tx in commit mode, 0 for no limit",
+	}
+
+	// SignerURLFlag points eth_sendTransaction at a signing service
+	// #unstable
+	SignerURLFlag = cli.StringFlag{
+		Name:  "signer_url",
+		Usage: "Sign the txs of eth_sendTransaction with the signing service at this url (see ultron signer) instead of the keystore",
+	}
+	SignerTokenFlag = cli.StringFlag{
+		Name:  "signer_token",
+		Usage: "Token to authenticate with the signing service",
+	}
 )
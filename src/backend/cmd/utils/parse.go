@@ -3,11 +3,15 @@ package utils
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"reflect"
 
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	"github.com/dora/ultron/backend/ethereum"
 )
 
 // defaultGenesisBlob is the JSON representation of the default
@@ -65,5 +69,39 @@ func ParseGenesisOrDefault(genesisPath string) (*core.Genesis, error) {
 		return nil, errBlankGenesis
 	}
 
+	if genesis.Config != nil {
+		var forks struct {
+			Config ethereum.ForkBlocks `json:"config"`
+		}
+		if err := json.Unmarshal(genesisBlob, &forks); err != nil {
+			return nil, err
+		}
+		if err := ethereum.CheckForks(genesis.Config, forks.Config); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := checkAlloc(genesis.Alloc); err != nil {
+		return nil, err
+	}
 	return genesis, nil
 }
+
+// checkAlloc checks the contracts of alloc. Contracts can be allocated with
+// their runtime code and storage, so system contracts exist from the first
+// block without a deployment tx; debug_dumpBlock of a dev chain they were
+// deployed on gives both. They can't take the address of a precompile,
+// which would shadow them, and storage needs code to read it.
+func checkAlloc(alloc core.GenesisAlloc) error {
+	for addr, account := range alloc {
+		_, homestead := vm.PrecompiledContractsHomestead[addr]
+		_, byzantium := vm.PrecompiledContractsByzantium[addr]
+		if (homestead || byzantium) && len(account.Code) > 0 {
+			return fmt.Errorf("genesis alloc: %s is a precompile, it can't hold code", addr.Hex())
+		}
+		if len(account.Storage) > 0 && len(account.Code) == 0 {
+			return fmt.Errorf("genesis alloc: %s has storage but no code", addr.Hex())
+		}
+	}
+	return nil
+}
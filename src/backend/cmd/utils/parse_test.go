@@ -70,3 +70,21 @@ func TestParseGenesisOrDefault(t *testing.T) {
 		assert.Equal(t, gen, tt.want, "#%d: expected them to be the same", i)
 	}
 }
+
+func TestCheckAlloc(t *testing.T) {
+	contract := ethCommon.HexToAddress("0x1000000000000000000000000000000000000001")
+	slot := map[ethCommon.Hash]ethCommon.Hash{{}: ethCommon.BytesToHash([]byte{1})}
+
+	assert.NoError(t, checkAlloc(core.GenesisAlloc{
+		contract: {Code: []byte{0x60, 0x00}, Storage: slot, Balance: new(big.Int)},
+	}))
+	assert.Error(t, checkAlloc(core.GenesisAlloc{
+		contract: {Storage: slot, Balance: new(big.Int)},
+	}), "storage without code")
+	assert.Error(t, checkAlloc(core.GenesisAlloc{
+		ethCommon.BytesToAddress([]byte{1}): {Code: []byte{0x60, 0x00}, Balance: new(big.Int)},
+	}), "ecrecover")
+	assert.NoError(t, checkAlloc(core.GenesisAlloc{
+		ethCommon.BytesToAddress([]byte{1}): {Balance: big.NewInt(1)},
+	}), "precompiles can hold wei")
+}
@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"fmt"
 	"os"
 	"os/signal"
 	"os/user"
@@ -16,9 +17,9 @@ import (
 )
 
 // StartNode will start up the node.
-func StartNode(stack *ethereum.Node) {
+func StartNode(stack *ethereum.Node) error {
 	if err := stack.Start(); err != nil {
-		ethUtils.Fatalf("Error starting protocol stack: %v", err)
+		return fmt.Errorf("Error starting protocol stack: %v", err)
 	}
 	go func() {
 		sigc := make(chan os.Signal, 1)
@@ -34,6 +35,7 @@ func StartNode(stack *ethereum.Node) {
 			}
 		}
 	}()
+	return nil
 }
 
 // HomeDir returns the user's home most likely home directory
@@ -0,0 +1,41 @@
+package backend
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/dora/ultron/modules/community"
+)
+
+// PublicCommunityAPI reads the community pool.
+type PublicCommunityAPI struct {
+	b *Backend
+}
+
+// NewPublicCommunityAPI creates the community namespace API of b.
+func NewPublicCommunityAPI(b *Backend) *PublicCommunityAPI {
+	return &PublicCommunityAPI{b}
+}
+
+// Pool returns the wei in the community pool at the latest block.
+func (api *PublicCommunityAPI) Pool() (*hexutil.Big, error) {
+	value, err := api.b.queryKey(community.PoolKey)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(new(big.Int).SetBytes(value)), nil
+}
+
+// Spends returns the spends of the pool waiting for their height.
+func (api *PublicCommunityAPI) Spends() ([]community.Spend, error) {
+	value, err := api.b.queryKey(community.SpendsKey)
+	if err != nil {
+		return nil, err
+	}
+	spends, err := community.ParseSpends(value)
+	if spends == nil && err == nil {
+		spends = []community.Spend{}
+	}
+	return spends, err
+}
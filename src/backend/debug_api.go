@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+
+	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/rpcmetrics"
+)
+
+// StateCleanup sums up the storage self destructed contracts gave back.
+type StateCleanup struct {
+	Contracts      []ethereum.StorageCleanup `json:"contracts"`
+	Slots          int                       `json:"slots"`
+	ReclaimedBytes int                       `json:"reclaimedBytes"`
+
+	// Verified is set when the latest state still holds no storage of
+	// any contract.
+	Verified bool `json:"verified"`
+}
+
+// PrivateDebugAPI checks the state bookkeeping of the node.
+type PrivateDebugAPI struct {
+	b *Backend
+}
+
+// NewPrivateDebugAPI creates the debug namespace API of b.
+func NewPrivateDebugAPI(b *Backend) *PrivateDebugAPI {
+	return &PrivateDebugAPI{b}
+}
+
+// VerifyStateCleanup reports the storage the recently self destructed
+// contracts released at commit, only that of contract if it is given, and
+// checks the latest state still holds none of it.
+func (api *PrivateDebugAPI) VerifyStateCleanup(contract *common.Address) (*StateCleanup, error) {
+	db := state.NewDatabase(api.b.ethereum.ChainDb())
+	root := api.b.ethereum.BlockChain().CurrentBlock().Root()
+
+	res := &StateCleanup{Contracts: []ethereum.StorageCleanup{}, Verified: true}
+	for _, c := range api.b.es.StorageCleanups() {
+		if contract != nil && c.Address != *contract {
+			continue
+		}
+		kept, err := ethereum.StorageKept(db, root, c.Address)
+		if err != nil {
+			return nil, err
+		}
+		c.Released = c.Released && kept == 0
+		if c.Released {
+			res.Slots += c.Slots
+			res.ReclaimedBytes += c.Bytes
+		} else {
+			res.Verified = false
+		}
+		res.Contracts = append(res.Contracts, c)
+	}
+	return res, nil
+}
+
+// GasAudit returns the blocks the gas audit ran again and the txs whose gas
+// differed from the committed receipts. Start the node with gas_audit set
+// under [test] to run it.
+func (api *PrivateDebugAPI) GasAudit() ethereum.GasAuditReport {
+	return api.b.es.GasAudit()
+}
+
+// QueryStats returns the HTTP RPC requests by method, the slowest in total
+// first. Start the node with rpc_metrics set under [vm] to count them.
+func (api *PrivateDebugAPI) QueryStats() []rpcmetrics.MethodStats {
+	return rpcmetrics.Stats()
+}
+
+// ResetQueryStats clears the counts of QueryStats.
+func (api *PrivateDebugAPI) ResetQueryStats() {
+	rpcmetrics.Reset()
+}
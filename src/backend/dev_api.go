@@ -0,0 +1,116 @@
+package backend
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/dora/ultron/dev"
+)
+
+// mineTimeout bounds how long ultron_mineBlock waits for its blocks
+var mineTimeout = time.Minute
+
+var (
+	errManualMiningOff = errors.New("manual mining is off, start the node with --manual_mining")
+	errDevModeOff      = errors.New("dev mode is off, start the node with --dev")
+	errNoSnapshots     = errors.New("snapshots need a dev chain with manual mining, start the node with --dev --manual_mining")
+)
+
+// SetMiner hands block production over to miner, see dev.Miner.
+func (b *Backend) SetMiner(miner *dev.Miner) {
+	b.miner = miner
+}
+
+// SetClock lets clock decide the block times, see dev.Clock.
+func (b *Backend) SetClock(clock *dev.Clock) {
+	b.clock = clock
+}
+
+// SetSnapshotter lets s take the snapshots of evm_snapshot.
+func (b *Backend) SetSnapshotter(s dev.Snapshotter) {
+	b.snapshotter = s
+}
+
+// PublicDevAPI lets tests and dev consoles drive a dev chain.
+type PublicDevAPI struct {
+	b *Backend
+}
+
+// NewPublicDevAPI creates the ultron namespace API of b.
+func NewPublicDevAPI(b *Backend) *PublicDevAPI {
+	return &PublicDevAPI{b}
+}
+
+// MineBlock commits n blocks, one if n is 0, taking in the txs waiting in
+// the mempool. It returns the number of the last block.
+func (api *PublicDevAPI) MineBlock(n *hexutil.Uint64) (hexutil.Uint64, error) {
+	if api.b.miner == nil {
+		return 0, errManualMiningOff
+	}
+	blocks := uint64(1)
+	if n != nil && *n > 0 {
+		blocks = uint64(*n)
+	}
+	if err := api.b.miner.Mine(blocks, mineTimeout); err != nil {
+		return 0, err
+	}
+	return hexutil.Uint64(api.b.ethereum.BlockChain().CurrentBlock().NumberU64()), nil
+}
+
+// SetNextBlockTimestamp makes the next block carry the unix time ts, later
+// blocks go on from there.
+func (api *PublicDevAPI) SetNextBlockTimestamp(ts hexutil.Uint64) error {
+	if api.b.clock == nil {
+		return errDevModeOff
+	}
+	return api.b.clock.SetNextBlockTimestamp(uint64(ts))
+}
+
+// IncreaseTime moves the block time forward by seconds. It returns the total
+// shift from the consensus time in seconds.
+func (api *PublicDevAPI) IncreaseTime(seconds hexutil.Uint64) (int64, error) {
+	if api.b.clock == nil {
+		return 0, errDevModeOff
+	}
+	return api.b.clock.IncreaseTime(uint64(seconds)), nil
+}
+
+// PublicEVMAPI offers the Ganache style snapshots to dApp test suites, so
+// they can reset the chain between cases.
+type PublicEVMAPI struct {
+	b *Backend
+}
+
+// NewPublicEVMAPI creates the evm namespace API of b.
+func NewPublicEVMAPI(b *Backend) *PublicEVMAPI {
+	return &PublicEVMAPI{b}
+}
+
+// Snapshot records the chain state and returns the snapshot id.
+func (api *PublicEVMAPI) Snapshot() (hexutil.Uint64, error) {
+	if api.b.snapshotter == nil || api.b.miner == nil {
+		return 0, errNoSnapshots
+	}
+	var id uint64
+	err := api.b.miner.Hold(func() (err error) {
+		id, err = api.b.snapshotter.Snapshot()
+		return err
+	})
+	return hexutil.Uint64(id), err
+}
+
+// Revert restores the chain state of snapshot id, dropping it and the later
+// snapshots. It returns false for an unknown snapshot.
+func (api *PublicEVMAPI) Revert(id hexutil.Uint64) (bool, error) {
+	if api.b.snapshotter == nil || api.b.miner == nil {
+		return false, errNoSnapshots
+	}
+	var ok bool
+	err := api.b.miner.Hold(func() (err error) {
+		ok, err = api.b.snapshotter.Revert(uint64(id))
+		return err
+	})
+	return ok, err
+}
@@ -0,0 +1,85 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/dora/ultron/modules/emergency"
+)
+
+// PublicEmergencyAPI reads the tx classes paused by the emergency council.
+type PublicEmergencyAPI struct {
+	b *Backend
+}
+
+// NewPublicEmergencyAPI creates the emergency namespace API of b.
+func NewPublicEmergencyAPI(b *Backend) *PublicEmergencyAPI {
+	return &PublicEmergencyAPI{b}
+}
+
+// Pauses returns the classes of txs paused at the latest block.
+func (api *PublicEmergencyAPI) Pauses() ([]emergency.Pause, error) {
+	height := api.b.ethereum.BlockChain().CurrentBlock().Number().Int64()
+	pauses := []emergency.Pause{}
+	for _, class := range emergency.Classes() {
+		value, err := api.b.queryKey(emergency.PauseKey(class))
+		if err != nil {
+			return nil, err
+		}
+		pause, err := emergency.ParsePause(value)
+		if err != nil {
+			return nil, err
+		}
+		if pause != nil && height < pause.Until {
+			pauses = append(pauses, *pause)
+		}
+	}
+	return pauses, nil
+}
+
+// Proposals returns the pauses and resumes waiting for approvals.
+func (api *PublicEmergencyAPI) Proposals() ([]emergency.Proposal, error) {
+	proposals := []emergency.Proposal{}
+	for _, class := range emergency.Classes() {
+		value, err := api.b.queryKey(emergency.ProposalKey(class))
+		if err != nil {
+			return nil, err
+		}
+		proposal, err := emergency.ParseProposal(value)
+		if err != nil {
+			return nil, err
+		}
+		if proposal != nil {
+			proposals = append(proposals, *proposal)
+		}
+	}
+	return proposals, nil
+}
+
+// Events notifies the subscriber of the classes paused and resumed.
+func (api *PublicEmergencyAPI) Events(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		events := make(chan emergency.Event)
+		sub := api.b.SubscribeEmergencies(events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				notifier.Notify(rpcSub.ID, ev) // nolint: errcheck
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
@@ -0,0 +1,27 @@
+package ethereum
+
+import (
+	"encoding/binary"
+
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	BlockHashPrefix = []byte{0x22} // hashes of the ethereum blocks, by number
+)
+
+func init() {
+	substore.Register("blockhashes", BlockHashPrefix)
+}
+
+// BlockHashKey is the store key of the hash of the ethereum block number.
+// The app keeps each block hash in its store when it commits the block, so
+// the app hash signed by the validators commits to the ethereum blocks, and
+// through their headers to their txs and receipts.
+func BlockHashKey(number uint64) []byte {
+	key := make([]byte, len(BlockHashPrefix)+8)
+	copy(key, BlockHashPrefix)
+	binary.BigEndian.PutUint64(key[len(BlockHashPrefix):], number)
+	return key
+}
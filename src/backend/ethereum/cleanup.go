@@ -0,0 +1,213 @@
+package ethereum
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// maxStorageCleanups bounds the cleanups EthState keeps for
+// debug_verifyStateCleanup, older ones are dropped first.
+const maxStorageCleanups = 1024
+
+// cleanupQueue bounds the blocks waiting for their cleanups to be checked,
+// blocks coming in while it is full are skipped.
+const cleanupQueue = 64
+
+var (
+	emptyRoot     = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	emptyCodeHash = crypto.Keccak256(nil)
+)
+
+// StorageCleanup accounts for the storage a self destructed contract gave up
+// in a block.
+type StorageCleanup struct {
+	Address common.Address `json:"address"`
+	Block   uint64         `json:"block"`
+	Root    common.Hash    `json:"root"` // of the storage before the block
+	Slots   int            `json:"slots"`
+	Bytes   int            `json:"bytes"` // key and value bytes of the slots
+
+	// Released is set once the committed state of the block no longer
+	// holds the account or any storage of it.
+	Released bool `json:"released"`
+}
+
+// cleanupJob is a committed block to find the storage cleanups of
+type cleanupJob struct {
+	block        uint64
+	parent, root common.Hash
+	destroyed    []common.Address // called by the txs, gone from the state
+}
+
+// cleanupVerifier checks the storage cleanups of the committed blocks off
+// the commit path, walking the tries of their states.
+type cleanupVerifier struct {
+	db   state.Database
+	jobs chan cleanupJob
+
+	mtx      sync.Mutex
+	cleanups []StorageCleanup // of self destructed contracts, oldest first
+}
+
+func newCleanupVerifier(db ethdb.Database) *cleanupVerifier {
+	v := &cleanupVerifier{
+		db:   state.NewDatabase(db),
+		jobs: make(chan cleanupJob, cleanupQueue),
+	}
+	go v.loop()
+	return v
+}
+
+// StorageCleanups returns the cleanups of the latest blocks, oldest first.
+func (es *EthState) StorageCleanups() []StorageCleanup {
+	if es.cleanups == nil {
+		return nil
+	}
+	es.cleanups.mtx.Lock()
+	defer es.cleanups.mtx.Unlock()
+	return append([]StorageCleanup(nil), es.cleanups.cleanups...)
+}
+
+// queue hands job to the verifier, it does not wait.
+func (v *cleanupVerifier) queue(job cleanupJob) {
+	if len(job.destroyed) == 0 {
+		return
+	}
+	select {
+	case v.jobs <- job:
+	default:
+		log.Warn("Skipped storage cleanup check", "block", job.block)
+	}
+}
+
+func (v *cleanupVerifier) loop() {
+	for job := range v.jobs {
+		cleanups := v.verify(job)
+
+		v.mtx.Lock()
+		v.cleanups = append(v.cleanups, cleanups...)
+		if n := len(v.cleanups) - maxStorageCleanups; n > 0 {
+			v.cleanups = append([]StorageCleanup(nil), v.cleanups[n:]...)
+		}
+		v.mtx.Unlock()
+	}
+}
+
+// verify counts the storage the contracts of job had in the parent state,
+// under their storage root then, and checks the committed state holds none
+// of it.
+func (v *cleanupVerifier) verify(job cleanupJob) []StorageCleanup {
+	var cleanups []StorageCleanup
+	for _, addr := range job.destroyed {
+		account, err := loadAccount(v.db, job.parent, addr)
+		if err != nil {
+			log.Warn("Can't read parent state for storage cleanup", "contract", addr, "err", err)
+			continue
+		}
+		if account == nil || bytes.Equal(account.CodeHash, emptyCodeHash) {
+			continue
+		}
+		c := StorageCleanup{Address: addr, Block: job.block, Root: account.Root}
+		c.Slots, c.Bytes, err = storageSize(v.db, addr, account.Root)
+		if err != nil {
+			log.Warn("Can't read contract storage for storage cleanup", "contract", addr, "err", err)
+			continue
+		}
+
+		kept, err := StorageKept(v.db, job.root, addr)
+		if err != nil {
+			log.Warn("Can't read committed state for storage cleanup", "contract", addr, "err", err)
+			continue
+		}
+		c.Released = kept == 0
+		if c.Released {
+			log.Info("Released contract storage", "contract", addr, "slots", c.Slots, "bytes", c.Bytes)
+		} else {
+			log.Warn("Self destructed contract kept its storage", "contract", addr, "slots", kept)
+		}
+		cleanups = append(cleanups, c)
+	}
+	return cleanups
+}
+
+// destroyedContracts returns the contracts the txs of the block called and
+// the work state no longer holds, their storage is looked at once the block
+// is committed. Contracts self destructed through an inner call of another
+// contract are not covered.
+func (ws *workState) destroyedContracts() []common.Address {
+	var destroyed []common.Address
+	seen := make(map[common.Address]bool)
+	for _, tx := range ws.transactions {
+		if tx.To() == nil || seen[*tx.To()] {
+			continue
+		}
+		addr := *tx.To()
+		seen[addr] = true
+		if !ws.state.Exist(addr) {
+			destroyed = append(destroyed, addr)
+		}
+	}
+	return destroyed
+}
+
+// StorageKept returns the slots the state of root still holds for addr,
+// read through its storage trie, 0 if the account is gone.
+func StorageKept(db state.Database, root common.Hash, addr common.Address) (int, error) {
+	account, err := loadAccount(db, root, addr)
+	if err != nil || account == nil {
+		return 0, err
+	}
+	if account.Root == emptyRoot {
+		return 0, nil
+	}
+	slots, _, err := storageSize(db, addr, account.Root)
+	return slots, err
+}
+
+// loadAccount reads addr from the account trie of root, nil if it isn't
+// there.
+func loadAccount(db state.Database, root common.Hash, addr common.Address) (*state.Account, error) {
+	accounts, err := db.OpenTrie(root)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := accounts.TryGet(addr.Bytes())
+	if err != nil || blob == nil {
+		return nil, err
+	}
+	account := new(state.Account)
+	if err := rlp.DecodeBytes(blob, account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+// storageSize counts the slots of the storage trie of addr at root and the
+// bytes they take, the 32 byte key plus the value without leading zeros.
+func storageSize(db state.Database, addr common.Address, root common.Hash) (slots int, size int, err error) {
+	if root == emptyRoot {
+		return 0, 0, nil
+	}
+	storage, err := db.OpenStorageTrie(crypto.Keccak256Hash(addr.Bytes()), root)
+	if err != nil {
+		return 0, 0, err
+	}
+	it := trie.NewIterator(storage.NodeIterator(nil))
+	for it.Next() {
+		var value []byte
+		if _, content, _, err := rlp.Split(it.Value); err == nil {
+			value = content
+		}
+		slots++
+		size += common.HashLength + len(bytes.TrimLeft(value, "\x00"))
+	}
+	return slots, size, it.Err
+}
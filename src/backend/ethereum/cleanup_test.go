@@ -0,0 +1,50 @@
+package ethereum
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+func TestVerifyCleanup(t *testing.T) {
+	db, err := ethdb.NewMemDatabase()
+	require.Nil(t, err)
+	contract := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	kept := common.HexToAddress("0x1000000000000000000000000000000000000002")
+
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(db))
+	require.Nil(t, err)
+	for _, addr := range []common.Address{contract, kept} {
+		statedb.SetCode(addr, []byte{0x00})
+		statedb.SetState(addr, common.HexToHash("0x01"), common.HexToHash("0xff"))
+		statedb.SetState(addr, common.HexToHash("0x02"), common.HexToHash("0x0100"))
+	}
+	parent, err := statedb.CommitTo(db, false)
+	require.Nil(t, err)
+
+	statedb, err = state.New(parent, state.NewDatabase(db))
+	require.Nil(t, err)
+	statedb.Suicide(contract)
+	root, err := statedb.CommitTo(db, false)
+	require.Nil(t, err)
+
+	// the storage is counted under the root the contract had before
+	v := &cleanupVerifier{db: state.NewDatabase(db)}
+	cleanups := v.verify(cleanupJob{block: 2, parent: parent, root: root, destroyed: []common.Address{contract, kept}})
+	require.Len(t, cleanups, 2)
+	assert.Equal(t, contract, cleanups[0].Address)
+	assert.Equal(t, 2, cleanups[0].Slots)
+	assert.Equal(t, 2*common.HashLength+3, cleanups[0].Bytes)
+	assert.True(t, cleanups[0].Released)
+	assert.NotEqual(t, emptyRoot, cleanups[0].Root)
+	assert.False(t, cleanups[1].Released, "still in the state")
+
+	slots, err := StorageKept(v.db, root, kept)
+	require.Nil(t, err)
+	assert.Equal(t, 2, slots)
+}
@@ -285,6 +285,7 @@ func (l *ContractTrace) CaptureEnd(output []byte, gasUsed uint64, t time.Duratio
 // ContractTrace returns the captured changes.
 func (l *ContractTrace) LoadedValues() map[common.Address]vm.Storage  { return l.loadedValues }
 func (l *ContractTrace) ChangedValues() map[common.Address]vm.Storage { return l.changedValues }
+func (l *ContractTrace) LoadedBalances() map[common.Address]*big.Int  { return l.loadedBalances }
 func (l *ContractTrace) ChangedBalances() map[common.Address]*big.Int { return l.changedBalances }
 func (l *ContractTrace) CreatedContracts() map[common.Address][]byte  { return l.createdContracts }
 
@@ -39,6 +39,7 @@ import (
 
 type EthState struct {
 	ptxEnabled bool
+	gasAudit   bool
 
 	ethereum       *eth.Ethereum
 	ethConfig      *eth.Config
@@ -47,16 +48,24 @@ type EthState struct {
 
 	mtx  sync.Mutex
 	work workState // latest working state
+
+	cleanups *cleanupVerifier // of self destructed contracts, see StorageCleanups
+
+	auditor *GasAuditor // nil unless gas_audit is set
+
+	lastSupply SupplyChange // of the block committed last
 }
 
 // After NewEthState, call SetEthereum and SetEthConfig.
 func NewEthState() *EthState {
 	ptxEnabled := true
+	gasAudit := false
 	testConfig, _ := emtConfig.ParseConfig()
 	if testConfig != nil {
 		if testConfig.TestConfig.DisablePtx {
 			ptxEnabled = false
 		}
+		gasAudit = testConfig.TestConfig.GasAudit
 	}
 	var txExecutor *TransactionExecutor
 	if ptxEnabled {
@@ -66,6 +75,7 @@ func NewEthState() *EthState {
 		ethereum:   nil, // set with SetEthereum
 		ethConfig:  nil, // set with SetEthConfig
 		ptxEnabled: ptxEnabled,
+		gasAudit:   gasAudit,
 		txExecutor: txExecutor,
 	}
 }
@@ -82,6 +92,10 @@ func (es *EthState) SetConfig(ethereum *eth.Ethereum, ethConfig *eth.Config) {
 	}
 	es.stateProcessor = NewStateProcessor(ethereum.ApiBackend.ChainConfig(), ethereum.BlockChain(), ethereum.BlockChain().Engine())
 	ethereum.BlockChain().SetProcessor(es.stateProcessor)
+	if es.gasAudit {
+		es.auditor = NewGasAuditor(ethereum)
+	}
+	es.cleanups = newCleanupVerifier(ethereum.ChainDb())
 }
 
 func (es *EthState) UpdateProposer(isProposer bool) {
@@ -104,6 +118,9 @@ func (es *EthState) DeliverTx(tx *ethTypes.Transaction) abciTypes.ResponseDelive
 	blockchain := es.ethereum.BlockChain()
 	chainConfig := es.ethereum.ApiBackend.ChainConfig()
 	blockHash := common.Hash{}
+	if err := CheckNewAccount(es.work.state, tx); err != nil {
+		return abciTypes.ResponseDeliverTx{Code: errors.ErrorTypeBaseInvalidInput, Log: err.Error()}
+	}
 	return es.work.deliverTx(blockchain, es.ethConfig, chainConfig, blockHash, tx)
 }
 
@@ -141,6 +158,65 @@ func (es *EthState) AddNonce(addr common.Address) {
 	es.work.state.SetNonce(addr, es.work.state.GetNonce(addr)+1)
 }
 
+// Balance returns the balance of addr in the state of the block being run.
+func (es *EthState) Balance(addr common.Address) *big.Int {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+
+	return es.work.state.GetBalance(addr)
+}
+
+// HasCode tells if addr is a contract in the state of the block being run.
+func (es *EthState) HasCode(addr common.Address) bool {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+
+	return es.work.state.GetCodeSize(addr) > 0
+}
+
+// called by ultron tx only in deliver_tx
+func (es *EthState) Transfer(from, to common.Address, amount *big.Int) error {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+
+	if es.work.state.GetBalance(from).Cmp(amount) < 0 {
+		return core.ErrInsufficientFunds
+	}
+	es.work.state.SubBalance(from, amount)
+	es.work.state.AddBalance(to, amount)
+	return nil
+}
+
+// TxGasUsed returns the gas used by the tx of hash run in the block, false
+// if it wasn't.
+func (es *EthState) TxGasUsed(hash common.Hash) (*big.Int, bool) {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+
+	for i := len(es.work.receipts) - 1; i >= 0; i-- {
+		if receipt := es.work.receipts[i]; receipt.TxHash == hash {
+			return new(big.Int).Set(receipt.GasUsed), true
+		}
+	}
+	return nil, false
+}
+
+// ChargeFee takes fee from the balance of from, as the base fees of eth txs
+// are: it is burnt and goes to the block award. Called by ultron txs only
+// in deliver_tx.
+func (es *EthState) ChargeFee(from common.Address, fee *big.Int) error {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+
+	if es.work.state.GetBalance(from).Cmp(fee) < 0 {
+		return core.ErrInsufficientFunds
+	}
+	es.work.state.SubBalance(from, fee)
+	es.work.burn(fee)
+	es.work.totalUsedGasFee.Add(es.work.totalUsedGasFee, fee)
+	return nil
+}
+
 // Accumulate validator rewards.
 func (es *EthState) AccumulateRewards(strategy *emtTypes.Strategy) {
 	es.mtx.Lock()
@@ -155,11 +231,22 @@ func (es *EthState) Commit(receiver common.Address) (common.Hash, error) {
 	defer es.mtx.Unlock()
 	if es.IsPtxEnabled() {
 		es.work.state = es.txExecutor.commitState()
+		if es.work.tips {
+			es.work.burnBaseFee(es.work.unburntFee)
+		}
 	}
+	es.work.burnBurnAccount()
 	blockHash, err := es.work.commit(es.ethereum.BlockChain(), es.ethereum.ChainDb())
 	if err != nil {
 		return common.Hash{}, err
 	}
+	if es.cleanups != nil {
+		es.cleanups.queue(es.work.cleanup)
+	}
+	es.lastSupply = es.work.supply
+	if es.auditor != nil {
+		es.auditor.Audit(es.ethereum.BlockChain().CurrentBlock(), es.work.preStates)
+	}
 
 	err = es.resetWorkState(receiver)
 	if err != nil {
@@ -201,9 +288,11 @@ func (es *EthState) resetWorkState(receiver common.Address) error {
 		txIndex:         0,
 		totalUsedGas:    big.NewInt(0),
 		totalUsedGasFee: big.NewInt(0),
+		unburntFee:      big.NewInt(0),
 		txExecutor:      es.txExecutor,
 		stateProcessor:  es.stateProcessor,
 		gp:              new(core.GasPool).AddGas(ethHeader.GasLimit),
+		audit:           es.auditor != nil,
 	}
 	return nil
 }
@@ -263,14 +352,28 @@ type workState struct {
 
 	totalUsedGas    *big.Int
 	totalUsedGasFee *big.Int
+	unburntFee      *big.Int // base fees of parallel txs, burnt at commit
+	tips            bool     // split the fees, see PayTips
 	gp              *core.GasPool
+	systemGasUsed   uint64 // by the system calls, see SystemCall
+	supply          SupplyChange
+
+	cleanup cleanupJob // set by commit
+
+	// copies of the state each tx run by deliverTx started from, kept for
+	// the gas audit only
+	audit     bool
+	preStates []*state.StateDB
 }
 
+// accumulateRewards mints the ethash block reward to the coinbase. Only
+// the blocks before ProposerTipsUpgrade of the app get it.
 // nolint: unparam
 func (ws *workState) accumulateRewards(strategy *emtTypes.Strategy) {
 
+	before := new(big.Int).Set(ws.state.GetBalance(ws.header.Coinbase))
 	ethash.AccumulateRewards(ws.state, ws.header, []*ethTypes.Header{})
-	ws.header.GasUsed = ws.totalUsedGas
+	ws.mint(new(big.Int).Sub(ws.state.GetBalance(ws.header.Coinbase), before))
 }
 
 // Runs ApplyTransaction against the ethereum blockchain, fetches any logs,
@@ -280,6 +383,11 @@ func (ws *workState) deliverTx(blockchain *core.BlockChain, config *eth.Config,
 	tx *ethTypes.Transaction) abciTypes.ResponseDeliverTx {
 
 	ws.state.Prepare(tx.Hash(), blockHash, ws.txIndex)
+	preExecute(ws.state, ws.header, tx)
+	var preState *state.StateDB
+	if ws.audit {
+		preState = ws.state.Copy()
+	}
 	receipt, usedGas, err := core.ApplyTransaction(
 		chainConfig,
 		blockchain,
@@ -291,11 +399,12 @@ func (ws *workState) deliverTx(blockchain *core.BlockChain, config *eth.Config,
 		ws.totalUsedGas,
 		vm.Config{EnablePreimageRecording: config.EnablePreimageRecording},
 	)
+	postExecute(ws.state, ws.header, tx, receipt, err)
 	if err != nil {
 		return abciTypes.ResponseDeliverTx{Code: errors.ErrorTypeInternalErr, Log: err.Error()}
 	}
 
-	usedGasFee := big.NewInt(0).Mul(usedGas, tx.GasPrice())
+	usedGasFee := ws.settleFee(tx, usedGas)
 	ws.totalUsedGasFee.Add(ws.totalUsedGasFee, usedGasFee)
 
 	logs := ws.state.GetLogs(tx.Hash())
@@ -306,6 +415,9 @@ func (ws *workState) deliverTx(blockchain *core.BlockChain, config *eth.Config,
 	ws.transactions = append(ws.transactions, tx)
 	ws.receipts = append(ws.receipts, receipt)
 	ws.allLogs = append(ws.allLogs, logs...)
+	if preState != nil {
+		ws.preStates = append(ws.preStates, preState)
+	}
 
 	return abciTypes.ResponseDeliverTx{Code: abciTypes.CodeTypeOK}
 }
@@ -320,6 +432,10 @@ func (ws *workState) deliverPtx(blockchain *core.BlockChain, config *eth.Config,
 		}
 		tx := etx.tx
 		ws.totalUsedGas.Add(ws.totalUsedGas, etx.receipt.GasUsed)
+		if ws.tips {
+			base, _ := SplitFee(tx, etx.receipt.GasUsed)
+			ws.unburntFee.Add(ws.unburntFee, base)
+		}
 
 		//Assign CumulativeGasUsed
 		etx.receipt.CumulativeGasUsed = ws.totalUsedGas
@@ -330,6 +446,7 @@ func (ws *workState) deliverPtx(blockchain *core.BlockChain, config *eth.Config,
 			log.TxIndex = uint(ws.txIndex)
 		}
 		ws.allLogs = append(ws.allLogs, etx.receipt.Logs...)
+		postExecute(nil, ws.header, tx, etx.receipt, nil)
 
 		ws.txIndex++
 	}
@@ -340,11 +457,20 @@ func (ws *workState) deliverPtx(blockchain *core.BlockChain, config *eth.Config,
 // the ethereum blockchain. The application root hash is the hash of the
 // ethereum block.
 func (ws *workState) commit(blockchain *core.BlockChain, db ethdb.Database) (common.Hash, error) {
+	destroyed := ws.destroyedContracts()
+	ws.header.GasUsed = ws.totalUsedGas
+
 	// Commit ethereum state and update the header.
 	hashArray, err := ws.state.CommitTo(db, false) // XXX: ugh hardforks
 	if err != nil {
 		return common.Hash{}, err
 	}
+	ws.cleanup = cleanupJob{
+		block:     ws.header.Number.Uint64(),
+		parent:    ws.parent.Root(),
+		root:      hashArray,
+		destroyed: destroyed,
+	}
 
 	ws.header.Root = hashArray
 
@@ -11,12 +11,14 @@ import (
 type EthStateWrapper struct {
 	*EthState
 	TotalUsedGasFee *big.Int
+	TotalUsedGas    *big.Int // of the block ended, with its gas limit
+	BlockGasLimit   *big.Int
 }
 
 type Remittance struct {
 	From   common.Address
 	To     common.Address
-	Amount *big.Int
+	Amount *big.Int // nil for the whole balance of From
 }
 
 var (
@@ -27,6 +29,8 @@ func NewEthStateWrapper() *EthStateWrapper {
 	return &EthStateWrapper{
 		EthState: NewEthState(),
 		TotalUsedGasFee: big.NewInt(0),
+		TotalUsedGas: big.NewInt(0),
+		BlockGasLimit: big.NewInt(0),
 	}
 }
 
@@ -37,6 +41,8 @@ func SubmitRemittance(remittance Remittance) {
 func (es *EthStateWrapper) EndBlock() {
 	es.EthState.EndBlock()
 	es.TotalUsedGasFee = es.EthState.work.totalUsedGasFee
+	es.TotalUsedGas = es.EthState.work.totalUsedGas
+	es.BlockGasLimit = es.EthState.work.header.GasLimit
 }
 
 func (es *EthStateWrapper) Commit(receiver common.Address) (common.Hash, error) {
@@ -52,12 +58,16 @@ func (es *EthStateWrapper) Commit(receiver common.Address) (common.Hash, error)
 func (ws *workState) handleRemittanceLedger() {
 	for i := 0; i < len(remittanceLedger); i++ {
 		scObj := remittanceLedger[i]
+		if scObj.Amount == nil {
+			scObj.Amount = new(big.Int).Set(ws.state.GetBalance(scObj.From))
+		}
 		if bytes.Compare(scObj.From.Bytes(), constant.MintAccount.Bytes()) == 0 {
 			if bytes.Compare(scObj.To.Bytes(), constant.MintAccount.Bytes()) != 0 {
 				if (constant.DEBUG_STAKE) {
 					fmt.Printf("##### %s -> %s, %s\n", scObj.From.String(), scObj.To.String(), scObj.Amount.String())
 				}
 				ws.state.AddBalance(scObj.To, scObj.Amount)
+				ws.mint(scObj.Amount)
 			}
 		} else {
 			if ws.state.GetBalance(scObj.From).Cmp(scObj.Amount) >= 0 {
@@ -67,6 +77,8 @@ func (ws *workState) handleRemittanceLedger() {
 						fmt.Printf("##### %s -> %s, %s\n", scObj.From.String(), scObj.To.String(), scObj.Amount.String())
 					}
 					ws.state.AddBalance(scObj.To, scObj.Amount)
+				} else {
+					ws.burn(scObj.Amount)
 				}
 			} else {
 				fmt.Printf("ERROR: insufficient balance in %s", scObj.From.String())
@@ -0,0 +1,87 @@
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// LatestFork is the last hardfork the linked EVM implements. The default
+// genesis stops before it: the EVM reports no failure of a tx, so receipts
+// can't carry the status byzantium puts in place of the state root.
+const LatestFork = "byzantium"
+
+// Rules lists the hardforks active at a block. Blocks are executed with the
+// rules the chain config switches on at their number, see vm.NewEVM.
+type Rules struct {
+	ChainId *big.Int `json:"chainId"`
+	Number  *big.Int `json:"number"`
+
+	// the forks after Byzantium are refused by CheckForks, they get fields
+	// once the EVM runs them
+	Homestead bool `json:"homestead"`
+	EIP150    bool `json:"eip150"`
+	EIP155    bool `json:"eip155"`
+	EIP158    bool `json:"eip158"`
+	Byzantium bool `json:"byzantium"`
+}
+
+// RulesAt returns the rules config activates at block number.
+func RulesAt(config *params.ChainConfig, number *big.Int) Rules {
+	return Rules{
+		ChainId:   config.ChainId,
+		Number:    number,
+		Homestead: config.IsHomestead(number),
+		EIP150:    config.IsEIP150(number),
+		EIP155:    config.IsEIP155(number),
+		EIP158:    config.IsEIP158(number),
+		Byzantium: config.IsByzantium(number),
+	}
+}
+
+// ForkBlocks holds the switch blocks of the hardforks after Byzantium, which
+// params.ChainConfig has no fields for. They are read from the "config"
+// object of the genesis file.
+type ForkBlocks struct {
+	ConstantinopleBlock *big.Int `json:"constantinopleBlock,omitempty"`
+	PetersburgBlock     *big.Int `json:"petersburgBlock,omitempty"`
+	IstanbulBlock       *big.Int `json:"istanbulBlock,omitempty"`
+}
+
+type fork struct {
+	name  string
+	block *big.Int
+}
+
+// CheckForks makes sure the hardforks of config and later are scheduled in
+// order, and that none after Byzantium is, as the EVM can't run them.
+func CheckForks(config *params.ChainConfig, later ForkBlocks) error {
+	forks := []fork{
+		{"homesteadBlock", config.HomesteadBlock},
+		{"eip150Block", config.EIP150Block},
+		{"eip155Block", config.EIP155Block},
+		{"eip158Block", config.EIP158Block},
+		{"byzantiumBlock", config.ByzantiumBlock},
+		{"constantinopleBlock", later.ConstantinopleBlock},
+		{"petersburgBlock", later.PetersburgBlock},
+		{"istanbulBlock", later.IstanbulBlock},
+	}
+
+	var last fork
+	for _, cur := range forks {
+		if cur.block == nil {
+			continue
+		}
+		if last.block != nil && cur.block.Cmp(last.block) < 0 {
+			return fmt.Errorf("%s %v is before %s %v", cur.name, cur.block, last.name, last.block)
+		}
+		last = cur
+	}
+	for _, f := range forks[5:] {
+		if f.block != nil {
+			return fmt.Errorf("%s is set, but the EVM implements hardforks up to %s", f.name, LatestFork)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestRulesAt(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainId:        big.NewInt(15),
+		HomesteadBlock: big.NewInt(0),
+		EIP150Block:    big.NewInt(0),
+		EIP155Block:    big.NewInt(0),
+		EIP158Block:    big.NewInt(0),
+		ByzantiumBlock: big.NewInt(10),
+	}
+
+	rules := RulesAt(config, big.NewInt(9))
+	assert.True(t, rules.EIP158)
+	assert.False(t, rules.Byzantium)
+
+	rules = RulesAt(config, big.NewInt(10))
+	assert.True(t, rules.Byzantium)
+}
+
+func TestCheckForks(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainId:        big.NewInt(15),
+		HomesteadBlock: big.NewInt(0),
+		EIP155Block:    big.NewInt(5),
+		EIP158Block:    big.NewInt(5),
+		ByzantiumBlock: big.NewInt(10),
+	}
+	assert.Nil(t, CheckForks(config, ForkBlocks{}))
+
+	config.ByzantiumBlock = big.NewInt(1)
+	assert.NotNil(t, CheckForks(config, ForkBlocks{}), "byzantium before eip158")
+
+	config.ByzantiumBlock = big.NewInt(10)
+	assert.NotNil(t, CheckForks(config, ForkBlocks{IstanbulBlock: big.NewInt(20)}), "istanbul is not implemented")
+}
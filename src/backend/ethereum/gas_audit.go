@@ -0,0 +1,230 @@
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// gasAuditQueue bounds the blocks waiting for their audit, blocks coming in
+// while it is full are skipped.
+const gasAuditQueue = 64
+
+// maxGasDivergences bounds the divergences GasAuditor keeps, older ones are
+// dropped first.
+const maxGasDivergences = 1024
+
+// GasDivergence is a tx whose gas differs between the block and the audit.
+type GasDivergence struct {
+	Block     uint64      `json:"block"`
+	TxHash    common.Hash `json:"txHash"`
+	Committed *big.Int    `json:"committed"`
+	Audited   *big.Int    `json:"audited"`
+	Error     string      `json:"error,omitempty"` // of the audit run
+}
+
+// GasAuditReport sums up the audit so far.
+type GasAuditReport struct {
+	Enabled     bool            `json:"enabled"`
+	Audited     uint64          `json:"audited"` // blocks
+	Skipped     uint64          `json:"skipped"` // blocks
+	Divergences []GasDivergence `json:"divergences"`
+}
+
+// GasAuditor runs the txs of committed blocks again and compares their gas
+// with the receipts the block was committed with, while the chain goes on,
+// be it live or replaying. The gas is worked out by txGas, apart from the
+// state transition blocks are run with.
+//
+// A tx run serially is audited on a copy of the state taken right before
+// it ran, which holds the changes the app made ahead of it, like the ether
+// lent for fees paid in other tokens. The txs of a block run by the
+// parallel executor are run one after the other on the state of the parent
+// block, the app changes nothing ahead of them.
+type GasAuditor struct {
+	ethereum *eth.Ethereum
+	jobs     chan gasAuditJob
+
+	mtx         sync.Mutex
+	audited     uint64
+	skipped     uint64
+	divergences []GasDivergence
+}
+
+// NewGasAuditor starts auditing the blocks handed to Audit.
+func NewGasAuditor(ethereum *eth.Ethereum) *GasAuditor {
+	a := &GasAuditor{
+		ethereum: ethereum,
+		jobs:     make(chan gasAuditJob, gasAuditQueue),
+	}
+	go a.loop()
+	return a
+}
+
+// gasAuditJob is a block to audit and the states its txs ran on, nil if
+// they didn't run serially
+type gasAuditJob struct {
+	block     *ethTypes.Block
+	preStates []*state.StateDB
+}
+
+// Audit queues block for its audit, it does not wait. preStates are the
+// states its txs ran on, see workState.deliverTx.
+func (a *GasAuditor) Audit(block *ethTypes.Block, preStates []*state.StateDB) {
+	select {
+	case a.jobs <- gasAuditJob{block, preStates}:
+	default:
+		a.mtx.Lock()
+		a.skipped++
+		a.mtx.Unlock()
+	}
+}
+
+// Report returns the audit so far.
+func (a *GasAuditor) Report() GasAuditReport {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	return GasAuditReport{
+		Enabled:     true,
+		Audited:     a.audited,
+		Skipped:     a.skipped,
+		Divergences: append([]GasDivergence{}, a.divergences...),
+	}
+}
+
+// GasAudit returns the gas audit so far, an empty report if gas_audit is
+// off.
+func (es *EthState) GasAudit() GasAuditReport {
+	if es.auditor == nil {
+		return GasAuditReport{Divergences: []GasDivergence{}}
+	}
+	return es.auditor.Report()
+}
+
+func (a *GasAuditor) loop() {
+	for job := range a.jobs {
+		divergences := a.audit(job)
+
+		a.mtx.Lock()
+		a.audited++
+		a.divergences = append(a.divergences, divergences...)
+		if n := len(a.divergences) - maxGasDivergences; n > 0 {
+			a.divergences = append([]GasDivergence(nil), a.divergences[n:]...)
+		}
+		a.mtx.Unlock()
+	}
+}
+
+func (a *GasAuditor) audit(job gasAuditJob) []GasDivergence {
+	block := job.block
+	if len(block.Transactions()) == 0 {
+		return nil
+	}
+	blockchain := a.ethereum.BlockChain()
+	config := a.ethereum.ApiBackend.ChainConfig()
+	number := block.NumberU64()
+	diverged := func(tx *ethTypes.Transaction, committed, audited *big.Int, err error) GasDivergence {
+		d := GasDivergence{Block: number, TxHash: tx.Hash(), Committed: committed, Audited: audited}
+		if err != nil {
+			d.Error = err.Error()
+		}
+		log.Error("Gas accounting diverged", "block", number, "tx", d.TxHash, "committed", committed, "audited", audited, "err", err)
+		return d
+	}
+
+	serial := len(job.preStates) == len(block.Transactions())
+	var statedb *state.StateDB
+	if !serial {
+		parent := blockchain.GetBlock(block.ParentHash(), number-1)
+		if parent == nil {
+			log.Warn("Gas audit can't find parent block", "block", number)
+			return nil
+		}
+		var err error
+		if statedb, err = blockchain.StateAt(parent.Root()); err != nil {
+			log.Warn("Gas audit can't open parent state", "block", number, "err", err)
+			return nil
+		}
+	}
+	receipts := core.GetBlockReceipts(a.ethereum.ChainDb(), block.Hash(), number)
+
+	var divergences []GasDivergence
+	header := block.Header()
+	for i, tx := range block.Transactions() {
+		committed := big.NewInt(0)
+		if i < len(receipts) {
+			committed = receipts[i].GasUsed
+		}
+		if serial {
+			statedb = job.preStates[i]
+		}
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		gas, err := txGas(config, blockchain, header, statedb, tx)
+		if err != nil {
+			divergences = append(divergences, diverged(tx, committed, nil, err))
+			if !serial {
+				// the state of the later txs is off now
+				return divergences
+			}
+			continue
+		}
+		if gas.Cmp(committed) != 0 {
+			divergences = append(divergences, diverged(tx, committed, gas, nil))
+		}
+	}
+	return divergences
+}
+
+// txGas runs tx on statedb and returns the gas it used. It buys the gas,
+// runs the message on the EVM and takes the refund off by itself, so the
+// gas doesn't come from the core.StateTransition the block ran with. The
+// miner isn't paid, statedb only holds for the gas of the txs after tx.
+func txGas(config *params.ChainConfig, bc *core.BlockChain, header *ethTypes.Header, statedb *state.StateDB, tx *ethTypes.Transaction) (*big.Int, error) {
+	msg, err := tx.AsMessage(ethTypes.MakeSigner(config, header.Number))
+	if err != nil {
+		return nil, err
+	}
+	from, limit := msg.From(), msg.Gas()
+	if limit.BitLen() > 64 {
+		return nil, fmt.Errorf("gas limit %v overflows", limit)
+	}
+	intrinsic := core.IntrinsicGas(msg.Data(), msg.To() == nil, config.IsHomestead(header.Number))
+	if limit.Cmp(intrinsic) < 0 {
+		return nil, fmt.Errorf("gas limit %v is below the intrinsic gas %v", limit, intrinsic)
+	}
+	cost := new(big.Int).Mul(limit, msg.GasPrice())
+	if statedb.GetBalance(from).Cmp(cost) < 0 {
+		return nil, core.ErrInsufficientFunds
+	}
+	statedb.SubBalance(from, cost)
+
+	evm := vm.NewEVM(core.NewEVMContext(msg, header, bc, &header.Coinbase), statedb, config, vm.Config{})
+	gas := new(big.Int).Sub(limit, intrinsic).Uint64()
+	var left uint64
+	if msg.To() == nil {
+		_, _, left, err = evm.Create(vm.AccountRef(from), msg.Data(), gas, msg.Value())
+	} else {
+		statedb.SetNonce(from, statedb.GetNonce(from)+1)
+		_, left, err = evm.Call(vm.AccountRef(from), *msg.To(), msg.Data(), gas, msg.Value())
+	}
+	if err == vm.ErrInsufficientBalance {
+		return nil, err
+	}
+
+	used := new(big.Int).Sub(limit, new(big.Int).SetUint64(left))
+	used.Sub(used, math.BigMin(new(big.Int).Div(used, big.NewInt(2)), statedb.GetRefund()))
+	statedb.AddBalance(from, new(big.Int).Mul(new(big.Int).Sub(limit, used), msg.GasPrice()))
+	// clears the refund and the destroyed contracts for the next tx
+	statedb.IntermediateRoot(config.IsEIP158(header.Number))
+	return used, nil
+}
@@ -0,0 +1,68 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// txGas must agree with the state transition blocks run with
+func TestTxGas(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainId:        big.NewInt(15),
+		HomesteadBlock: big.NewInt(0),
+		EIP150Block:    big.NewInt(0),
+		EIP155Block:    big.NewInt(0),
+		EIP158Block:    big.NewInt(0),
+	}
+	header := &ethTypes.Header{Number: big.NewInt(1), GasLimit: big.NewInt(1e7), Difficulty: big.NewInt(1), Time: big.NewInt(1)}
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	sender := crypto.PubkeyToAddress(key.PublicKey)
+	signer := ethTypes.NewEIP155Signer(config.ChainId)
+
+	db, err := ethdb.NewMemDatabase()
+	require.Nil(t, err)
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(db))
+	require.Nil(t, err)
+	statedb.AddBalance(sender, big.NewInt(1e18))
+	// sets slot 0 and clears it, for a refund
+	contract := common.HexToAddress("0x1000")
+	statedb.SetCode(contract, common.FromHex("0x600160005560006000550000"))
+
+	txs := []*ethTypes.Transaction{
+		ethTypes.NewTransaction(0, common.HexToAddress("0x02"), big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil),
+		ethTypes.NewTransaction(1, contract, new(big.Int), big.NewInt(100000), big.NewInt(1), nil),
+		ethTypes.NewContractCreation(2, new(big.Int), big.NewInt(100000), big.NewInt(1), common.FromHex("0x600160005500")),
+	}
+	for _, tx := range txs {
+		tx, err := ethTypes.SignTx(tx, signer, key)
+		require.Nil(t, err)
+		applied := statedb.Copy()
+		receipt, _, err := core.ApplyTransaction(config, nil, &header.Coinbase, new(core.GasPool).AddGas(header.GasLimit),
+			applied, header, tx, new(big.Int), vm.Config{})
+		require.Nil(t, err)
+
+		gas, err := txGas(config, nil, header, statedb, tx)
+		require.Nil(t, err)
+		assert.Equal(t, receipt.GasUsed, gas, tx.Hash().Hex())
+		assert.Equal(t, applied.GetBalance(sender), statedb.GetBalance(sender))
+	}
+
+	// the gas can't be bought
+	tx, err := ethTypes.SignTx(ethTypes.NewTransaction(3, contract, new(big.Int), big.NewInt(1e18), big.NewInt(1), nil), signer, key)
+	require.Nil(t, err)
+	_, err = txGas(config, nil, header, statedb, tx)
+	assert.Equal(t, core.ErrInsufficientFunds, err)
+}
@@ -0,0 +1,82 @@
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth"
+
+	"github.com/dora/ultron/hashdump"
+)
+
+// DumpBlock runs the txs of block again, one after the other on the state
+// of its parent as the gas audit does, and returns the root of the state
+// after each of them along with the accounts the block changed.
+//
+// The roots are those of the ethereum state alone, the app state changes a
+// tx makes, like fees paid in other tokens, aren't run again.
+func DumpBlock(ethereum *eth.Ethereum, block *ethTypes.Block, r *hashdump.Report) error {
+	blockchain := ethereum.BlockChain()
+	config := ethereum.ApiBackend.ChainConfig()
+	number := block.NumberU64()
+	r.Block, r.BlockHash, r.Root = number, block.Hash(), block.Root()
+
+	parent := blockchain.GetBlock(block.ParentHash(), number-1)
+	if parent == nil {
+		return fmt.Errorf("no parent of block %d", number)
+	}
+	r.ParentRoot = parent.Root()
+	accounts, err := hashdump.ChangedAccounts(ethereum.ChainDb(), parent.Root(), block.Root())
+	if err != nil {
+		r.Errors = append(r.Errors, fmt.Sprintf("changed accounts: %v", err))
+	}
+	r.Accounts = accounts
+
+	statedb, err := blockchain.StateAt(parent.Root())
+	if err != nil {
+		return fmt.Errorf("state of the parent of block %d: %v", number, err)
+	}
+	header := block.Header()
+	gp := new(core.GasPool).AddGas(header.GasLimit)
+	usedGas := big.NewInt(0)
+	signer := ethTypes.MakeSigner(config, header.Number)
+	for i, tx := range block.Transactions() {
+		dump := hashdump.Tx{Index: i, Hash: tx.Hash()}
+		if msg, err := tx.AsMessage(signer); err == nil {
+			dump.Touched = append(dump.Touched, msg.From())
+		}
+		if to := tx.To(); to != nil {
+			dump.Touched = append(dump.Touched, *to)
+		}
+
+		statedb.Prepare(tx.Hash(), block.Hash(), i)
+		receipt, gas, err := ApplyTransaction(config, blockchain, nil, gp, statedb, header, tx, usedGas, vm.Config{})
+		if err != nil {
+			dump.Error = err.Error()
+		} else {
+			dump.Gas = gas
+			if receipt.ContractAddress != (common.Address{}) {
+				dump.Touched = append(dump.Touched, receipt.ContractAddress)
+			}
+			for _, l := range receipt.Logs {
+				dump.Touched = appendAddress(dump.Touched, l.Address)
+			}
+		}
+		dump.Root = statedb.IntermediateRoot(config.IsEIP158(header.Number))
+		r.Txs = append(r.Txs, dump)
+	}
+	return nil
+}
+
+func appendAddress(addrs []common.Address, addr common.Address) []common.Address {
+	for _, a := range addrs {
+		if a == addr {
+			return addrs
+		}
+	}
+	return append(addrs, addr)
+}
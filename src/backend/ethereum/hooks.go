@@ -0,0 +1,51 @@
+package ethereum
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// VMHook observes the ethereum txs executed for a block, for fee
+// distribution, tracing or indexing. Hooks run on the consensus goroutine
+// with the state of the block being built: they must be quick and must not
+// keep the state around.
+type VMHook interface {
+	// PreExecute is called before tx is applied to statedb.
+	PreExecute(statedb *state.StateDB, header *ethTypes.Header, tx *ethTypes.Transaction)
+	// PostExecute is called after tx, receipt is nil if it failed with err.
+	// With parallel execution the txs run ahead of the block, so only
+	// PostExecute is called, with a nil statedb.
+	PostExecute(statedb *state.StateDB, header *ethTypes.Header, tx *ethTypes.Transaction,
+		receipt *ethTypes.Receipt, err error)
+}
+
+var (
+	hookMtx sync.RWMutex
+	vmHooks []VMHook
+)
+
+// RegisterVMHook adds hook to the txs executed from now on.
+func RegisterVMHook(hook VMHook) {
+	hookMtx.Lock()
+	defer hookMtx.Unlock()
+	vmHooks = append(vmHooks, hook)
+}
+
+func preExecute(statedb *state.StateDB, header *ethTypes.Header, tx *ethTypes.Transaction) {
+	hookMtx.RLock()
+	defer hookMtx.RUnlock()
+	for _, hook := range vmHooks {
+		hook.PreExecute(statedb, header, tx)
+	}
+}
+
+func postExecute(statedb *state.StateDB, header *ethTypes.Header, tx *ethTypes.Transaction,
+	receipt *ethTypes.Receipt, err error) {
+	hookMtx.RLock()
+	defer hookMtx.RUnlock()
+	for _, hook := range vmHooks {
+		hook.PostExecute(statedb, header, tx, receipt, err)
+	}
+}
@@ -0,0 +1,46 @@
+package ethereum
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// hookLog records the calls of the hooks in the order they came
+type hookLog struct {
+	name  string
+	calls *[]string
+}
+
+func (h hookLog) PreExecute(statedb *state.StateDB, header *ethTypes.Header, tx *ethTypes.Transaction) {
+	*h.calls = append(*h.calls, fmt.Sprintf("%s pre %d", h.name, tx.Nonce()))
+}
+
+func (h hookLog) PostExecute(statedb *state.StateDB, header *ethTypes.Header, tx *ethTypes.Transaction,
+	receipt *ethTypes.Receipt, err error) {
+	*h.calls = append(*h.calls, fmt.Sprintf("%s post %d %v", h.name, tx.Nonce(), err))
+}
+
+func TestVMHooks(t *testing.T) {
+	defer func(hooks []VMHook) {
+		hookMtx.Lock()
+		vmHooks = hooks
+		hookMtx.Unlock()
+	}(vmHooks)
+
+	var calls []string
+	RegisterVMHook(hookLog{"a", &calls})
+	RegisterVMHook(hookLog{"b", &calls})
+
+	header := &ethTypes.Header{Number: big.NewInt(1)}
+	tx := ethTypes.NewTransaction(7, common.Address{}, new(big.Int), big.NewInt(21000), new(big.Int), nil)
+	preExecute(nil, header, tx)
+	postExecute(nil, header, tx, nil, fmt.Errorf("failed"))
+	assert.Equal(t, []string{"a pre 7", "b pre 7", "a post 7 failed", "b post 7 failed"}, calls)
+}
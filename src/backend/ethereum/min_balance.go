@@ -0,0 +1,26 @@
+package ethereum
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/dora/ultron/modules/params"
+)
+
+// CheckNewAccount rejects tx if it sends less than the minimum balance of
+// the chain, params.MinAccountBalance, to an account statedb does not hold,
+// so bench style account generation can't fill the trie with dust accounts.
+// Contract creations and the accounts contracts create are not covered.
+func CheckNewAccount(statedb *state.StateDB, tx *ethTypes.Transaction) error {
+	min := params.BigInt(params.MinAccountBalance)
+	if min.Sign() == 0 || tx.To() == nil {
+		return nil
+	}
+	if statedb.Exist(*tx.To()) || tx.Value().Cmp(min) >= 0 {
+		return nil
+	}
+	return fmt.Errorf("transfer of %v wei to new account %x is below the minimum balance of %v wei",
+		tx.Value(), *tx.To(), min)
+}
@@ -17,7 +17,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth"
 	"github.com/ethereum/go-ethereum/event"
-	//"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/log"
 	//"github.com/ethereum/go-ethereum/core/types"
 
 	emtConfig "github.com/dora/ultron/node/config"
@@ -98,6 +98,28 @@ func (tx *ParalleledTransaction) RawTxs() [][]byte {
 	return tx.data.Txs
 }
 
+// TxIds returns the hashes of the txs of a compact ptx.
+func (tx *ParalleledTransaction) TxIds() []common.Hash {
+	return tx.data.TxIds
+}
+
+// Expand returns a copy of the compact ptx carrying the raw txs lookup finds
+// for its tx ids, and the ids it doesn't find. The copy is only complete
+// when none are missing.
+func (tx *ParalleledTransaction) Expand(lookup func(common.Hash) ([]byte, bool)) (*ParalleledTransaction, []common.Hash) {
+	full := &ParalleledTransaction{data: ParalleledTransactionData{Dag: tx.data.Dag}}
+	var missing []common.Hash
+	for _, id := range tx.data.TxIds {
+		raw, ok := lookup(id)
+		if !ok {
+			missing = append(missing, id)
+			continue
+		}
+		full.data.Txs = append(full.data.Txs, raw)
+	}
+	return full, missing
+}
+
 func (tx *ParalleledTransaction) Dag() *Dag {
 	return tx.data.Dag
 }
@@ -136,16 +158,6 @@ func encodeTxRLP(tx *ethTypes.Transaction) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// rlp decode an etherum transaction
-func decodeTx(txBytes []byte) (*ethTypes.Transaction, error) {
-	tx := new(ethTypes.Transaction)
-	rlpStream := rlp.NewStream(bytes.NewBuffer(txBytes), 0)
-	if err := tx.DecodeRLP(rlpStream); err != nil {
-		return nil, err
-	}
-	return tx, nil
-}
-
 func (ptx *ParalleledTransaction) fillTxs(allTxs [][]*ExecutedTransaction) {
 	var allTxIds [][]common.Hash
 	for _, etxs := range allTxs {
@@ -289,20 +301,15 @@ func (te *TransactionExecutor) getExecutedTransactions(ptx *ParalleledTransactio
 			if etx, ok := te.executedTxs[txid]; ok {
 				etxs = append(etxs, etx)
 			} else {
-				fmt.Println("no find executed tx for ", txid)
+				log.Error("Executed tx of the ptx not found", "tx", txid)
 			}
 		}
 	} else {
 		for _, tx := range ptx.data.Txs {
-			ethTx, err := decodeTx(tx)
-			if err == nil {
-				if etx, ok := te.executedTxs[ethTx.Hash()]; ok {
-					etxs = append(etxs, etx)
-				} else {
-					fmt.Println("no find executed tx for ", tx)
-				}
+			if etx, ok := te.executedTxs[TxHash(tx)]; ok {
+				etxs = append(etxs, etx)
 			} else {
-				fmt.Println("decode tx fail ", tx)
+				log.Error("Executed tx of the ptx not found", "tx", TxHash(tx))
 			}
 		}
 	}
@@ -467,11 +474,11 @@ func (te *TransactionExecutor) applyTxsState(state *state.StateDB, etxs []*Execu
 }
 
 func (te *TransactionExecutor) applyTxState(state *state.StateDB, etx *ExecutedTransaction) {
-	if !isEthTx(etx.tx) {
+	if etx == nil {
+		log.Error("Executed tx of the ptx not found")
 		return
 	}
-	if etx == nil {
-		fmt.Println("Not found executed tx for tx,", etx.tx)
+	if !isEthTx(etx.tx) {
 		return
 	}
 	// fmt.Println("ptx selected tx,", etx.tx.Hash().Hex())
@@ -654,9 +661,9 @@ func (te *TransactionExecutor) dispatchLoop() {
 
 func (te *TransactionExecutor) cacheDagTxs(ptx *ParalleledTransaction) {
 	for _, txBytes := range ptx.data.Txs {
-		tx, err := decodeTx(txBytes)
+		tx, err := DecodeTx(txBytes)
 		if err == nil {
-			te.txCache[tx.Hash()] = tx
+			te.txCache[TxHash(txBytes)] = tx
 		}
 	}
 }
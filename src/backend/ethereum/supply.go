@@ -0,0 +1,53 @@
+package ethereum
+
+import (
+	"math/big"
+
+	"github.com/dora/ultron/const"
+)
+
+// SupplyChange is the ether a block minted and burnt.
+type SupplyChange struct {
+	Minted *big.Int
+	Burned *big.Int
+}
+
+func newSupplyChange() SupplyChange {
+	return SupplyChange{Minted: new(big.Int), Burned: new(big.Int)}
+}
+
+// LastSupplyChange returns the ether minted and burnt by the block committed
+// last, for the app to keep the totals, see supply.Add.
+func (es *EthState) LastSupplyChange() SupplyChange {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	if es.lastSupply.Minted == nil {
+		return newSupplyChange()
+	}
+	return es.lastSupply
+}
+
+func (ws *workState) mint(amount *big.Int) {
+	if ws.supply.Minted == nil {
+		ws.supply = newSupplyChange()
+	}
+	ws.supply.Minted.Add(ws.supply.Minted, amount)
+}
+
+func (ws *workState) burn(amount *big.Int) {
+	if ws.supply.Burned == nil {
+		ws.supply = newSupplyChange()
+	}
+	ws.supply.Burned.Add(ws.supply.Burned, amount)
+}
+
+// burnBurnAccount destroys the ether sent to constant.BurnAccount during
+// the block.
+func (ws *workState) burnBurnAccount() {
+	balance := ws.state.GetBalance(constant.BurnAccount)
+	if balance.Sign() > 0 {
+		balance = new(big.Int).Set(balance)
+		ws.state.SubBalance(constant.BurnAccount, balance)
+		ws.burn(balance)
+	}
+}
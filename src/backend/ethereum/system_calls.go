@@ -0,0 +1,99 @@
+package ethereum
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/params"
+)
+
+// Keys of the system call parameters
+const (
+	BeginBlockContract = "system.begin_block_contract"
+	EndBlockContract   = "system.end_block_contract"
+	SystemGasAllowance = "system.gas_allowance"
+)
+
+func init() {
+	params.Register(params.Param{
+		Key:     BeginBlockContract,
+		Type:    params.TypeAddress,
+		Default: common.Address{}.Hex(),
+		Doc:     "contract called with beginBlock(uint256 number) before the txs of every block, none if zero",
+	})
+	params.Register(params.Param{
+		Key:     EndBlockContract,
+		Type:    params.TypeAddress,
+		Default: common.Address{}.Hex(),
+		Doc:     "contract called with endBlock(uint256 number) after the txs of every block, before the block award, none if zero",
+	})
+	params.Register(params.Param{
+		Key:     SystemGasAllowance,
+		Type:    params.TypeInteger,
+		Default: "5000000",
+		Min:     "0",
+		Doc:     "gas per block the begin and end block calls share, outside the block gas limit and paid by no one",
+	})
+}
+
+// nolint
+var (
+	BeginBlockSelector = crypto.Keccak256([]byte("beginBlock(uint256)"))[:4]
+	EndBlockSelector   = crypto.Keccak256([]byte("endBlock(uint256)"))[:4]
+)
+
+var (
+	errNoSystemGas     = errors.New("system gas allowance of the block used up")
+	errSystemCallsPtxs = errors.New("system calls need parallel execution disabled")
+)
+
+// SystemCall calls the contract of the parameter key, BeginBlockContract or
+// EndBlockContract, with selector and the number of the block being built,
+// so protocol logic written in solidity, e.g. a rewards distributor, runs
+// at every block. The call is sent by constant.SystemAccount at gas price
+// zero; its gas comes from the SystemGasAllowance of the block, not from the
+// block gas limit. It leaves no tx or receipt behind. It returns the gas
+// used, zero with no contract set.
+func (es *EthState) SystemCall(key string, selector []byte) (uint64, error) {
+	to := params.Address(key)
+	if to == (common.Address{}) {
+		return 0, nil
+	}
+	if es.IsPtxEnabled() {
+		// the executor replaces the work state at commit
+		return 0, errSystemCallsPtxs
+	}
+
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	allowance := uint64(params.Int64(SystemGasAllowance))
+	if es.work.systemGasUsed >= allowance {
+		return 0, errNoSystemGas
+	}
+	input := append(append([]byte{}, selector...), common.BigToHash(es.work.header.Number).Bytes()...)
+	used, err := es.work.systemCall(es, to, input, allowance-es.work.systemGasUsed)
+	es.work.systemGasUsed += used
+	return used, err
+}
+
+func (ws *workState) systemCall(es *EthState, to common.Address, input []byte, gas uint64) (uint64, error) {
+	gasLimit := new(big.Int).SetUint64(gas)
+	msg := ethTypes.NewMessage(constant.SystemAccount, &to, 0, new(big.Int), gasLimit, new(big.Int), input, false)
+	context := core.NewEVMContext(msg, ws.header, es.ethereum.BlockChain(), nil)
+	evm := vm.NewEVM(context, ws.state, es.ethereum.ApiBackend.ChainConfig(), vm.Config{})
+
+	// keep the logs of the call apart from the ones of the last tx
+	ws.state.Prepare(common.Hash{}, common.Hash{}, ws.txIndex)
+	_, used, err := core.ApplyMessage(evm, msg, new(core.GasPool).AddGas(gasLimit))
+	if err != nil {
+		return 0, err
+	}
+	return used.Uint64(), nil
+}
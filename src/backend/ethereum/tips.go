@@ -0,0 +1,62 @@
+package ethereum
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/dora/ultron/modules/params"
+)
+
+// SplitFee splits the fee tx pays for gasUsed into the base fee, shared
+// among the validators, and the tip of the block proposer.
+func SplitFee(tx *ethTypes.Transaction, gasUsed *big.Int) (base, tip *big.Int) {
+	price := tx.GasPrice()
+	baseGasPrice := params.BigInt(params.MinGasPrice)
+	if price.Cmp(baseGasPrice) <= 0 {
+		return new(big.Int).Mul(gasUsed, price), big.NewInt(0)
+	}
+	base = new(big.Int).Mul(gasUsed, baseGasPrice)
+	tip = new(big.Int).Mul(gasUsed, new(big.Int).Sub(price, baseGasPrice))
+	return base, tip
+}
+
+// SetCoinbase makes addr the coinbase of the block being built. The EVM
+// pays the gas fees of the txs to the coinbase, which keeps the tips and
+// gives up the base fees, see burnBaseFee.
+func (es *EthState) SetCoinbase(addr common.Address) {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	es.work.header.Coinbase = addr
+}
+
+// PayTips makes the block being built split the fees of its txs, see
+// settleFee. The app calls it from ProposerTipsUpgrade on, the coinbase of
+// the blocks before keeps the whole fees.
+func (es *EthState) PayTips() {
+	es.mtx.Lock()
+	defer es.mtx.Unlock()
+	es.work.tips = true
+}
+
+// settleFee settles the fee tx paid the coinbase for gasUsed and returns
+// the part going to the block award: all of it, or the base fee burnt
+// from the coinbase once the block pays tips.
+func (ws *workState) settleFee(tx *ethTypes.Transaction, gasUsed *big.Int) *big.Int {
+	if !ws.tips {
+		return new(big.Int).Mul(gasUsed, tx.GasPrice())
+	}
+	base, _ := SplitFee(tx, gasUsed)
+	ws.burnBaseFee(base)
+	return base
+}
+
+// burnBaseFee takes the base fees back from the coinbase, the validators
+// get them with the block award.
+func (ws *workState) burnBaseFee(base *big.Int) {
+	if base.Sign() > 0 {
+		ws.state.SubBalance(ws.header.Coinbase, base)
+		ws.burn(base)
+	}
+}
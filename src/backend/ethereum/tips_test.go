@@ -0,0 +1,125 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestSplitFee(t *testing.T) {
+	gasUsed := big.NewInt(21000)
+	tx := func(price int64) *ethTypes.Transaction {
+		return ethTypes.NewTransaction(0, common.Address{}, big.NewInt(0), gasUsed, big.NewInt(price), nil)
+	}
+
+	base, tip := SplitFee(tx(5e9), gasUsed)
+	assert.Equal(t, big.NewInt(21000*2e9), base)
+	assert.Equal(t, big.NewInt(21000*3e9), tip)
+
+	base, tip = SplitFee(tx(1e9), gasUsed)
+	assert.Equal(t, big.NewInt(21000*1e9), base)
+	assert.Equal(t, 0, tip.Sign())
+}
+
+func TestTipsMintNothing(t *testing.T) {
+	ws, statedb, tx, supply := feeBlock(t, true)
+	from, _ := ethTypes.Sender(ethTypes.NewEIP155Signer(params.TestChainConfig.ChainId), tx)
+	to, proposer := *tx.To(), ws.header.Coinbase
+
+	// what deliverTx and commit do with the fees of the block
+	gp := new(core.GasPool).AddGas(ws.header.GasLimit)
+	_, used, err := ApplyTransaction(params.TestChainConfig, nil, &proposer, gp, statedb, ws.header, tx, ws.totalUsedGas, vm.Config{})
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(21000*2e9), ws.settleFee(tx, used))
+	ws.burnBurnAccount()
+
+	// the proposer gets the tip and nothing else, the base fee is burnt
+	assert.Equal(t, big.NewInt(21000*3e9), statedb.GetBalance(proposer))
+	assert.Equal(t, 0, ws.supply.Minted.Sign())
+	assert.Equal(t, big.NewInt(21000*2e9), ws.supply.Burned)
+	total := new(big.Int)
+	for _, addr := range []common.Address{from, to, proposer} {
+		total.Add(total, statedb.GetBalance(addr))
+	}
+	assert.Equal(t, new(big.Int).Sub(supply, ws.supply.Burned), total)
+}
+
+// the blocks before the proposers get the tips leave the whole fees to the
+// coinbase and the block award
+func TestFeesBeforeTips(t *testing.T) {
+	ws, statedb, tx, _ := feeBlock(t, false)
+	receiver := ws.header.Coinbase
+
+	gp := new(core.GasPool).AddGas(ws.header.GasLimit)
+	_, used, err := ApplyTransaction(params.TestChainConfig, nil, &receiver, gp, statedb, ws.header, tx, ws.totalUsedGas, vm.Config{})
+	require.Nil(t, err)
+	assert.Equal(t, big.NewInt(21000*5e9), ws.settleFee(tx, used))
+	ws.burnBurnAccount()
+
+	assert.Equal(t, big.NewInt(21000*5e9), statedb.GetBalance(receiver))
+	assert.Nil(t, ws.supply.Burned)
+}
+
+// feeBlock returns the work state of a block with coinbase, splitting the
+// fees or not, and a tx paying a 3 gwei tip.
+func feeBlock(t *testing.T, tips bool) (*workState, *state.StateDB, *ethTypes.Transaction, *big.Int) {
+	db, err := ethdb.NewMemDatabase()
+	require.Nil(t, err)
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(db))
+	require.Nil(t, err)
+
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	to := common.HexToAddress("0x1000000000000000000000000000000000000001")
+	proposer := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	supply := big.NewInt(1e18)
+	statedb.AddBalance(from, supply)
+
+	gasLimit := big.NewInt(1e6)
+	ws := &workState{
+		header: &ethTypes.Header{
+			Number:     big.NewInt(1),
+			GasLimit:   gasLimit,
+			Difficulty: big.NewInt(1),
+			Time:       big.NewInt(1),
+			Coinbase:   proposer,
+		},
+		state:        statedb,
+		totalUsedGas: new(big.Int),
+		tips:         tips,
+	}
+	tx := ethTypes.NewTransaction(0, to, big.NewInt(1000), big.NewInt(21000), big.NewInt(5e9), nil)
+	tx, err = ethTypes.SignTx(tx, ethTypes.NewEIP155Signer(params.TestChainConfig.ChainId), key)
+	require.Nil(t, err)
+	return ws, statedb, tx, supply
+}
+
+// the blocks before the proposers get the tips still mint the block reward
+func TestAccumulateRewardsMints(t *testing.T) {
+	db, err := ethdb.NewMemDatabase()
+	require.Nil(t, err)
+	statedb, err := state.New(common.Hash{}, state.NewDatabase(db))
+	require.Nil(t, err)
+
+	receiver := common.HexToAddress("0x1000000000000000000000000000000000000003")
+	ws := &workState{
+		header: &ethTypes.Header{Number: big.NewInt(1), Coinbase: receiver},
+		state:  statedb,
+	}
+	ws.accumulateRewards(nil)
+
+	assert.True(t, statedb.GetBalance(receiver).Sign() > 0)
+	assert.Equal(t, statedb.GetBalance(receiver), ws.supply.Minted)
+}
@@ -0,0 +1,107 @@
+package ethereum
+
+import (
+	"bytes"
+	"hash"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/sha3"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// txScratch is what decoding and hashing a tx takes besides the tx itself,
+// pooled so the thousands of txs of a block don't each allocate their own
+type txScratch struct {
+	r      bytes.Reader
+	s      rlp.Stream
+	hasher hash.Hash
+}
+
+var txScratchPool = sync.Pool{
+	New: func() interface{} {
+		return &txScratch{hasher: sha3.NewKeccak256()}
+	},
+}
+
+// DecodeRLP decodes the rlp of v from b. Unlike rlp.DecodeBytes it takes no
+// allocation of its own, and it ignores whatever follows the value.
+func DecodeRLP(b []byte, v rlp.Decoder) error {
+	sc := txScratchPool.Get().(*txScratch)
+	sc.r.Reset(b)
+	sc.s.Reset(&sc.r, 0)
+	err := v.DecodeRLP(&sc.s)
+	// holds on to b no longer than the call
+	sc.r.Reset(nil)
+	sc.s.Reset(&sc.r, 0)
+	txScratchPool.Put(sc)
+	return err
+}
+
+// DecodeTx decodes an ethereum tx from its rlp.
+func DecodeTx(txBytes []byte) (*ethTypes.Transaction, error) {
+	tx := new(ethTypes.Transaction)
+	if err := DecodeRLP(txBytes, tx); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// TxHash returns the hash of the tx whose rlp is txBytes without decoding
+// it. The rlp of a tx being canonical, it is tx.Hash() of the decoded tx,
+// sparing its encoding again: look up a known tx by it before decoding.
+func TxHash(txBytes []byte) (h common.Hash) {
+	if _, _, rest, err := rlp.Split(txBytes); err == nil {
+		txBytes = txBytes[:len(txBytes)-len(rest)]
+	}
+	sc := txScratchPool.Get().(*txScratch)
+	sc.hasher.Reset()
+	sc.hasher.Write(txBytes) // nolint: errcheck
+	sc.hasher.Sum(h[:0])
+	txScratchPool.Put(sc)
+	return h
+}
+
+// maxRememberedTxs bounds the txs remembered, in case some never reach
+// CheckTx
+const maxRememberedTxs = 1 << 16
+
+// remembered are the txs signed on this node on their way to CheckTx
+var remembered = struct {
+	sync.Mutex
+	txs map[common.Hash]*ethTypes.Transaction
+}{txs: make(map[common.Hash]*ethTypes.Transaction)}
+
+// RememberTx computes the hash and the sender of tx, which it caches, and
+// keeps it for RecallTx: the tx CheckTx decodes from the bytes broadcast is
+// then tx itself rather than a copy computing them again.
+func RememberTx(tx *ethTypes.Transaction) {
+	var signer ethTypes.Signer = ethTypes.FrontierSigner{}
+	if tx.Protected() {
+		signer = ethTypes.NewEIP155Signer(tx.ChainId())
+	}
+	tx.From(signer, false) // nolint: errcheck
+	hash := tx.Hash()
+
+	remembered.Lock()
+	if len(remembered.txs) >= maxRememberedTxs {
+		remembered.txs = make(map[common.Hash]*ethTypes.Transaction)
+	}
+	remembered.txs[hash] = tx
+	remembered.Unlock()
+}
+
+// RecallTx returns the tx whose rlp is txBytes, the one of RememberTx if
+// any, which is then forgotten, else decoded.
+func RecallTx(txBytes []byte) (*ethTypes.Transaction, error) {
+	hash := TxHash(txBytes)
+	remembered.Lock()
+	tx, ok := remembered.txs[hash]
+	delete(remembered.txs, hash)
+	remembered.Unlock()
+	if ok {
+		return tx, nil
+	}
+	return DecodeTx(txBytes)
+}
@@ -0,0 +1,75 @@
+package ethereum
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+func signedTxBytes(t testing.TB) (*ethTypes.Transaction, []byte) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx := ethTypes.NewTransaction(1, common.Address{1}, big.NewInt(1e18), big.NewInt(21000), big.NewInt(2e9), []byte("payload"))
+	tx, err = ethTypes.SignTx(tx, ethTypes.NewEIP155Signer(big.NewInt(188)), key)
+	require.NoError(t, err)
+	txBytes, err := rlp.EncodeToBytes(tx)
+	require.NoError(t, err)
+	return tx, txBytes
+}
+
+func TestDecodeTx(t *testing.T) {
+	tx, txBytes := signedTxBytes(t)
+
+	decoded, err := DecodeTx(txBytes)
+	require.NoError(t, err)
+	assert.Equal(t, tx.Hash(), decoded.Hash())
+	assert.Equal(t, []byte("payload"), decoded.Data())
+	assert.Equal(t, tx.Hash(), TxHash(txBytes))
+	// what follows the tx is no part of it
+	assert.Equal(t, tx.Hash(), TxHash(append(txBytes, 0xc0)))
+
+	_, err = DecodeTx(txBytes[:len(txBytes)-1])
+	assert.Error(t, err)
+}
+
+func TestRecallTx(t *testing.T) {
+	tx, txBytes := signedTxBytes(t)
+
+	RememberTx(tx)
+	recalled, err := RecallTx(txBytes)
+	require.NoError(t, err)
+	assert.True(t, recalled == tx)
+
+	// recalled once
+	recalled, err = RecallTx(txBytes)
+	require.NoError(t, err)
+	assert.False(t, recalled == tx)
+	assert.Equal(t, tx.Hash(), recalled.Hash())
+}
+
+func BenchmarkDecodeTx(b *testing.B) {
+	_, txBytes := signedTxBytes(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeTx(txBytes); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTxHash(b *testing.B) {
+	_, txBytes := signedTxBytes(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		TxHash(txBytes)
+	}
+}
@@ -0,0 +1,171 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dora/ultron/modules/emergency"
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/modules/stake"
+)
+
+// TxDroppedEvent is posted when the tx pool drops a tx tendermint rejected.
+type TxDroppedEvent struct {
+	Tx   *ethTypes.Transaction
+	Code uint32
+	Log  string
+}
+
+// reasons of a ChainRewindEvent
+const (
+	RewindRevert   = "revert"   // Backend.Rewind, as dev chains revert to snapshots
+	RewindRecovery = "recovery" // the node restarted without the state of its latest blocks
+	RewindReplay   = "replay"   // the node stopped while committing a block, see Backend.RewindUncommitted
+)
+
+// ChainRewindEvent is posted when the head of the ethereum chain went back
+// from block From to block To. Tendermint never reverts a committed block,
+// but the ethereum chain of a node can go back on its own: the blocks after
+// To are built again, with other hashes if their txs or times differ, and
+// what was read from them is to be read again.
+type ChainRewindEvent struct {
+	From   uint64      `json:"from"`
+	To     uint64      `json:"to"`
+	Hash   common.Hash `json:"hash"` // of block To
+	Reason string      `json:"reason"`
+}
+
+// SubscribeNewTxs delivers the txs entering the tx pool to ch.
+// #unstable
+func (b *Backend) SubscribeNewTxs(ch chan<- core.TxPreEvent) event.Subscription {
+	return b.subscribe(core.TxPreEvent{}, func(data interface{}, quit <-chan struct{}) {
+		select {
+		case ch <- data.(core.TxPreEvent):
+		case <-quit:
+		}
+	})
+}
+
+// SubscribeChainHead delivers every new head block to ch.
+// #unstable
+func (b *Backend) SubscribeChainHead(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.subscribe(core.ChainHeadEvent{}, func(data interface{}, quit <-chan struct{}) {
+		select {
+		case ch <- data.(core.ChainHeadEvent):
+		case <-quit:
+		}
+	})
+}
+
+// SubscribeDroppedTxs delivers the txs dropped from the tx pool to ch.
+// #unstable
+func (b *Backend) SubscribeDroppedTxs(ch chan<- TxDroppedEvent) event.Subscription {
+	return b.subscribe(TxDroppedEvent{}, func(data interface{}, quit <-chan struct{}) {
+		select {
+		case ch <- data.(TxDroppedEvent):
+		case <-quit:
+		}
+	})
+}
+
+// SubscribeJailings delivers the validators jailed for downtime to ch.
+// #unstable
+func (b *Backend) SubscribeJailings(ch chan<- stake.JailEvent) event.Subscription {
+	return b.subscribe(stake.JailEvent{}, func(data interface{}, quit <-chan struct{}) {
+		select {
+		case ch <- data.(stake.JailEvent):
+		case <-quit:
+		}
+	})
+}
+
+// SubscribeChainRewinds delivers the rewinds of the ethereum chain to ch.
+// #unstable
+func (b *Backend) SubscribeChainRewinds(ch chan<- ChainRewindEvent) event.Subscription {
+	return b.subscribe(ChainRewindEvent{}, func(data interface{}, quit <-chan struct{}) {
+		select {
+		case ch <- data.(ChainRewindEvent):
+		case <-quit:
+		}
+	})
+}
+
+// SubscribeParamChanges delivers the params changed on chain to ch.
+func (b *Backend) SubscribeParamChanges(ch chan<- params.Change) event.Subscription {
+	return b.subscribe(params.Change{}, func(data interface{}, quit <-chan struct{}) {
+		select {
+		case ch <- data.(params.Change):
+		case <-quit:
+		}
+	})
+}
+
+// SubscribeEmergencies delivers the tx classes paused and resumed by the
+// emergency council to ch.
+func (b *Backend) SubscribeEmergencies(ch chan<- emergency.Event) event.Subscription {
+	return b.subscribe(emergency.Event{}, func(data interface{}, quit <-chan struct{}) {
+		select {
+		case ch <- data.(emergency.Event):
+		case <-quit:
+		}
+	})
+}
+
+// subscribeBuffer is the number of events a subscription holds for a slow
+// subscriber, the events posted meanwhile past it are dropped.
+const subscribeBuffer = 256
+
+// subscribe hands the events of the type of ev posted on the event mux to
+// deliver, until the subscription is unsubscribed.
+func (b *Backend) subscribe(ev interface{}, deliver func(data interface{}, quit <-chan struct{})) event.Subscription {
+	return subscribeMux(b.ethereum.EventMux(), ev, deliver)
+}
+
+// subscribeMux drains the mux subscription into a buffer apart from
+// deliver, so a slow subscriber loses events instead of holding back the
+// posters, DeliverTx and Commit among them.
+func subscribeMux(mux *event.TypeMux, ev interface{}, deliver func(data interface{}, quit <-chan struct{})) event.Subscription {
+	sub := mux.Subscribe(ev)
+	buffer := make(chan interface{}, subscribeBuffer)
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		done := make(chan struct{})
+		defer sub.Unsubscribe()
+		defer close(done)
+
+		go func() {
+			defer close(buffer)
+			for {
+				select {
+				case obj, ok := <-sub.Chan():
+					if !ok {
+						return
+					}
+					select {
+					case buffer <- obj.Data:
+					default:
+						log.Warn("Dropped event, the subscriber is too slow", "type", fmt.Sprintf("%T", obj.Data))
+					}
+				case <-done:
+					return
+				}
+			}
+		}()
+
+		for {
+			select {
+			case data, ok := <-buffer:
+				if !ok {
+					return nil
+				}
+				deliver(data, quit)
+			case <-quit:
+				return nil
+			}
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribeMux(t *testing.T) {
+	mux := new(event.TypeMux)
+	ch := make(chan int)
+	sub := subscribeMux(mux, 0, func(data interface{}, quit <-chan struct{}) {
+		select {
+		case ch <- data.(int):
+		case <-quit:
+		}
+	})
+	defer sub.Unsubscribe()
+
+	// nobody reads ch, the posters go on anyway
+	posted := make(chan struct{})
+	go func() {
+		for i := 0; i < 2*subscribeBuffer; i++ {
+			mux.Post(i) // nolint: errcheck
+		}
+		close(posted)
+	}()
+	select {
+	case <-posted:
+	case <-time.After(time.Second):
+		t.Fatal("posters held by a slow subscriber")
+	}
+
+	// the first events come in order, the overflow is dropped
+	var got []int
+	for done := false; !done; {
+		select {
+		case i := <-ch:
+			got = append(got, i)
+		case <-time.After(50 * time.Millisecond):
+			done = true
+		}
+	}
+	require.True(t, len(got) == subscribeBuffer || len(got) == subscribeBuffer+1, "got %d events", len(got))
+	for i, v := range got {
+		assert.Equal(t, i, v)
+	}
+
+	// and the subscriber gets the events after it caught up
+	mux.Post(-1) // nolint: errcheck
+	select {
+	case v := <-ch:
+		assert.Equal(t, -1, v)
+	case <-time.After(time.Second):
+		t.Fatal("event lost after the overflow")
+	}
+
+	sub.Unsubscribe()
+	select {
+	case <-sub.Err():
+	case <-time.After(time.Second):
+		t.Fatal("subscription not closed")
+	}
+}
@@ -0,0 +1,174 @@
+package backend
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	cmn "github.com/tendermint/tmlibs/common"
+)
+
+// filterTimeout is how long a filter lives without being polled
+const filterTimeout = 5 * time.Minute
+
+// nolint
+const (
+	LogsFilter   = "logs"
+	BlocksFilter = "blocks"
+)
+
+// StoredFilter is a filter of the eth filter API with how far it was
+// polled, all a frontend needs to serve it.
+type StoredFilter struct {
+	Type      string           `json:"type"`
+	FromBlock int64            `json:"fromBlock"` // -1 for the latest block
+	ToBlock   int64            `json:"toBlock"`   // -1 for the latest block
+	Addresses []common.Address `json:"addresses,omitempty"`
+	Topics    [][]common.Hash  `json:"topics,omitempty"`
+	Polled    uint64           `json:"polled"` // last block the changes were returned of
+	Deadline  time.Time        `json:"deadline"`
+}
+
+// FilterStore keeps the filters of the eth filter API by id. RPC frontends
+// behind a load balancer share one, so a filter made on one frontend can be
+// polled on any other.
+type FilterStore interface {
+	// Get returns the filter id, nil if it is unknown or expired.
+	Get(id rpc.ID) (*StoredFilter, error)
+	Put(id rpc.ID, f *StoredFilter) error
+	Delete(id rpc.ID) error
+}
+
+// memFilterStore keeps the filters of a single frontend.
+type memFilterStore struct {
+	mtx     sync.Mutex
+	filters map[rpc.ID]*StoredFilter
+}
+
+// NewMemFilterStore returns a store keeping the filters in memory.
+func NewMemFilterStore() FilterStore {
+	return &memFilterStore{filters: make(map[rpc.ID]*StoredFilter)}
+}
+
+func (s *memFilterStore) Get(id rpc.ID) (*StoredFilter, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	f, ok := s.filters[id]
+	if !ok || time.Now().After(f.Deadline) {
+		delete(s.filters, id)
+		return nil, nil
+	}
+	copied := *f
+	return &copied, nil
+}
+
+func (s *memFilterStore) Put(id rpc.ID, f *StoredFilter) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	now := time.Now()
+	for id, f := range s.filters {
+		if now.After(f.Deadline) {
+			delete(s.filters, id)
+		}
+	}
+	copied := *f
+	s.filters[id] = &copied
+	return nil
+}
+
+func (s *memFilterStore) Delete(id rpc.ID) error {
+	s.mtx.Lock()
+	delete(s.filters, id)
+	s.mtx.Unlock()
+	return nil
+}
+
+// dirFilterStore keeps each filter in a file of a directory the frontends
+// share, e.g. over NFS.
+type dirFilterStore struct {
+	dir string
+
+	mtx    sync.Mutex
+	pruned time.Time
+}
+
+// NewDirFilterStore returns a store keeping the filters in dir.
+func NewDirFilterStore(dir string) (FilterStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &dirFilterStore{dir: dir, pruned: time.Now()}, nil
+}
+
+func (s *dirFilterStore) file(id rpc.ID) string {
+	// ids are hex, anything else can't name a filter
+	name := strings.TrimPrefix(string(id), "0x")
+	if name == "" || strings.TrimLeft(strings.ToLower(name), "0123456789abcdef") != "" {
+		name = "invalid"
+	}
+	return filepath.Join(s.dir, name+".json")
+}
+
+func (s *dirFilterStore) Get(id rpc.ID) (*StoredFilter, error) {
+	b, err := ioutil.ReadFile(s.file(id))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	f := new(StoredFilter)
+	if err := json.Unmarshal(b, f); err != nil {
+		return nil, err
+	}
+	if time.Now().After(f.Deadline) {
+		os.Remove(s.file(id)) // nolint: errcheck
+		return nil, nil
+	}
+	return f, nil
+}
+
+func (s *dirFilterStore) Put(id rpc.ID, f *StoredFilter) error {
+	s.prune()
+	b, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	return cmn.WriteFileAtomic(s.file(id), b, 0600)
+}
+
+func (s *dirFilterStore) Delete(id rpc.ID) error {
+	err := os.Remove(s.file(id))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// prune removes the files of expired filters, once every filterTimeout
+func (s *dirFilterStore) prune() {
+	s.mtx.Lock()
+	if time.Since(s.pruned) < filterTimeout {
+		s.mtx.Unlock()
+		return
+	}
+	s.pruned = time.Now()
+	s.mtx.Unlock()
+
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return
+	}
+	for _, fi := range files {
+		// polled filters are rewritten, so their files stay fresh
+		if time.Since(fi.ModTime()) > filterTimeout {
+			os.Remove(filepath.Join(s.dir, fi.Name())) // nolint: errcheck
+		}
+	}
+}
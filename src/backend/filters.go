@@ -0,0 +1,195 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxFilterBlocks bounds the blocks a poll of a filter goes through, the
+// next poll carries on
+const maxFilterBlocks = 10000
+
+var errPendingFilter = errors.New("pending tx filters are kept per frontend, subscribe to newPendingTransactions instead")
+
+// SetFilterStore sets where the filters of the eth filter API are kept, see
+// FilterStore.
+func (b *Backend) SetFilterStore(store FilterStore) {
+	b.filterStore = store
+}
+
+// PublicFilterAPI replaces the eth filter methods of go-ethereum, which keep
+// the filters in the memory of the node, with methods keeping them in the
+// FilterStore. Polling a filter then needs no sticky session. Subscriptions
+// are left to go-ethereum, they live as long as their connection anyway.
+type PublicFilterAPI struct {
+	b *Backend
+}
+
+// NewPublicFilterAPI creates the filter API of b.
+func NewPublicFilterAPI(b *Backend) *PublicFilterAPI {
+	return &PublicFilterAPI{b}
+}
+
+// NewFilter creates a filter of the logs of the blocks coming after the
+// latest one, poll it with GetFilterChanges.
+func (api *PublicFilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
+	f := &StoredFilter{
+		Type:      LogsFilter,
+		FromBlock: int64(rpc.LatestBlockNumber),
+		ToBlock:   int64(rpc.LatestBlockNumber),
+		Addresses: crit.Addresses,
+		Topics:    crit.Topics,
+	}
+	if crit.FromBlock != nil {
+		f.FromBlock = crit.FromBlock.Int64()
+	}
+	if crit.ToBlock != nil {
+		f.ToBlock = crit.ToBlock.Int64()
+	}
+	return api.install(f)
+}
+
+// NewBlockFilter creates a filter of the hashes of the blocks coming after
+// the latest one, poll it with GetFilterChanges.
+func (api *PublicFilterAPI) NewBlockFilter() (rpc.ID, error) {
+	return api.install(&StoredFilter{Type: BlocksFilter})
+}
+
+// NewPendingTransactionFilter fails, pending txs are only known to the node
+// they reached first.
+func (api *PublicFilterAPI) NewPendingTransactionFilter() (rpc.ID, error) {
+	return "", errPendingFilter
+}
+
+func (api *PublicFilterAPI) install(f *StoredFilter) (rpc.ID, error) {
+	f.Polled = api.head()
+	f.Deadline = time.Now().Add(filterTimeout)
+	id := rpc.NewID()
+	return id, api.b.filterStore.Put(id, f)
+}
+
+// UninstallFilter removes the filter id.
+func (api *PublicFilterAPI) UninstallFilter(id rpc.ID) (bool, error) {
+	f, err := api.b.filterStore.Get(id)
+	if err != nil || f == nil {
+		return false, err
+	}
+	return true, api.b.filterStore.Delete(id)
+}
+
+// GetFilterChanges returns what the filter id matched since it was last
+// polled: block hashes or logs.
+func (api *PublicFilterAPI) GetFilterChanges(ctx context.Context, id rpc.ID) (interface{}, error) {
+	f, err := api.b.filterStore.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil {
+		return nil, fmt.Errorf("filter not found")
+	}
+
+	from, to := f.Polled+1, api.head()
+	if to >= from+maxFilterBlocks {
+		to = from + maxFilterBlocks - 1
+	}
+
+	var changes interface{}
+	switch f.Type {
+	case BlocksFilter:
+		hashes := []common.Hash{}
+		for n := from; n <= to; n++ {
+			if header := api.b.ethereum.BlockChain().GetHeaderByNumber(n); header != nil {
+				hashes = append(hashes, header.Hash())
+			}
+		}
+		changes = hashes
+	case LogsFilter:
+		logs, err := api.logs(ctx, f, from, to)
+		if err != nil {
+			return nil, err
+		}
+		changes = logs
+	}
+
+	if to >= from {
+		f.Polled = to
+	}
+	f.Deadline = time.Now().Add(filterTimeout)
+	return changes, api.b.filterStore.Put(id, f)
+}
+
+// GetFilterLogs returns all the logs the log filter id matches.
+func (api *PublicFilterAPI) GetFilterLogs(ctx context.Context, id rpc.ID) ([]*ethTypes.Log, error) {
+	f, err := api.b.filterStore.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if f == nil || f.Type != LogsFilter {
+		return nil, fmt.Errorf("filter not found")
+	}
+	if err := api.b.checkBlockRange(f.FromBlock, f.ToBlock); err != nil {
+		return nil, err
+	}
+	logs, err := filters.New(api.b.ethereum.ApiBackend, f.FromBlock, f.ToBlock, f.Addresses, f.Topics).Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return returnLogs(logs), nil
+}
+
+// GetLogs returns the logs matching crit, within the block range limit.
+func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*ethTypes.Log, error) {
+	from, to := int64(rpc.LatestBlockNumber), int64(rpc.LatestBlockNumber)
+	if crit.FromBlock != nil {
+		from = crit.FromBlock.Int64()
+	}
+	if crit.ToBlock != nil {
+		to = crit.ToBlock.Int64()
+	}
+	if err := api.b.checkBlockRange(from, to); err != nil {
+		return nil, err
+	}
+	logs, err := filters.New(api.b.ethereum.ApiBackend, from, to, crit.Addresses, crit.Topics).Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return returnLogs(logs), nil
+}
+
+// logs returns the logs f matches in the blocks from to to
+func (api *PublicFilterAPI) logs(ctx context.Context, f *StoredFilter, from, to uint64) ([]*ethTypes.Log, error) {
+	begin, end := int64(from), int64(to)
+	if f.FromBlock >= 0 && f.FromBlock > begin {
+		begin = f.FromBlock
+	}
+	if f.ToBlock >= 0 && f.ToBlock < end {
+		end = f.ToBlock
+	}
+	if begin > end {
+		return []*ethTypes.Log{}, nil
+	}
+	logs, err := filters.New(api.b.ethereum.ApiBackend, begin, end, f.Addresses, f.Topics).Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return returnLogs(logs), nil
+}
+
+func (api *PublicFilterAPI) head() uint64 {
+	return api.b.ethereum.BlockChain().CurrentBlock().NumberU64()
+}
+
+// returnLogs returns no logs as an empty array rather than null
+func returnLogs(logs []*ethTypes.Log) []*ethTypes.Log {
+	if logs == nil {
+		return []*ethTypes.Log{}
+	}
+	return logs
+}
@@ -0,0 +1,171 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/eth/filters"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/dora/ultron/rpcschema"
+)
+
+// FinalizedTag names the latest block no fork can drop. Tendermint commits
+// a block once validators of 2/3 of the voting power signed it, and forks
+// never replace a committed block: every block the node has is final, so
+// "finalized" is the latest block and no confirmations are to be waited.
+const FinalizedTag = "finalized"
+
+// BlockNumber is a block number parameter, a number or one of the tags of
+// rpc.BlockNumber or FinalizedTag.
+type BlockNumber rpc.BlockNumber
+
+// UnmarshalJSON parses a block number, FinalizedTag as the latest block.
+func (n *BlockNumber) UnmarshalJSON(data []byte) error {
+	if string(data) == `"`+FinalizedTag+`"` {
+		*n = BlockNumber(rpc.LatestBlockNumber)
+		return nil
+	}
+	return (*rpc.BlockNumber)(n).UnmarshalJSON(data)
+}
+
+// JSONSchema implements rpcschema.Describer.
+func (BlockNumber) JSONSchema() rpcschema.Schema {
+	return rpcschema.Schema{"oneOf": []rpcschema.Schema{
+		rpcschema.Quantity,
+		{"type": "string", "enum": []string{"earliest", "latest", "pending", FinalizedTag}},
+	}}
+}
+
+// FilterCriteria are the criteria of eth_getLogs and eth_newFilter, taking
+// FinalizedTag as from and to block.
+type FilterCriteria filters.FilterCriteria
+
+// UnmarshalJSON parses the criteria as filters.FilterCriteria does.
+func (c *FilterCriteria) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	finalized := false
+	for _, name := range []string{"fromBlock", "toBlock"} {
+		if string(fields[name]) == `"`+FinalizedTag+`"` {
+			fields[name] = json.RawMessage(`"latest"`)
+			finalized = true
+		}
+	}
+	if finalized {
+		var err error
+		if data, err = json.Marshal(fields); err != nil {
+			return err
+		}
+	}
+	return (*filters.FilterCriteria)(c).UnmarshalJSON(data)
+}
+
+// FinalizedBlock is the latest final block.
+type FinalizedBlock struct {
+	Number     hexutil.Uint64 `json:"number"`
+	Hash       common.Hash    `json:"hash"`
+	ParentHash common.Hash    `json:"parentHash"`
+	Timestamp  *hexutil.Big   `json:"timestamp"`
+}
+
+// FinalizedBlock returns the latest final block, which is the latest block
+// as tendermint finalizes blocks as it commits them.
+func (api *PublicChainAPI) FinalizedBlock() *FinalizedBlock {
+	header := api.b.ethereum.BlockChain().CurrentBlock().Header()
+	return &FinalizedBlock{
+		Number:     hexutil.Uint64(header.Number.Uint64()),
+		Hash:       header.Hash(),
+		ParentHash: header.ParentHash,
+		Timestamp:  (*hexutil.Big)(header.Time),
+	}
+}
+
+//----------------------------------------------------------------------
+// eth methods taking a block number
+
+// PublicStateAPI replaces the state methods of go-ethereum with ones taking
+// FinalizedTag as block number.
+type PublicStateAPI struct {
+	b *Backend
+}
+
+// NewPublicStateAPI creates the state API of b.
+func NewPublicStateAPI(b *Backend) *PublicStateAPI {
+	return &PublicStateAPI{b}
+}
+
+// GetBalance returns the balance of address at block blockNr.
+func (api *PublicStateAPI) GetBalance(ctx context.Context, address common.Address, blockNr BlockNumber) (*hexutil.Big, error) {
+	st, _, err := api.b.ethereum.ApiBackend.StateAndHeaderByNumber(ctx, rpc.BlockNumber(blockNr))
+	if st == nil || err != nil {
+		return nil, err
+	}
+	balance := st.GetBalance(address)
+	return (*hexutil.Big)(balance), st.Error()
+}
+
+// GetCode returns the code of address at block blockNr.
+func (api *PublicStateAPI) GetCode(ctx context.Context, address common.Address, blockNr BlockNumber) (hexutil.Bytes, error) {
+	st, _, err := api.b.ethereum.ApiBackend.StateAndHeaderByNumber(ctx, rpc.BlockNumber(blockNr))
+	if st == nil || err != nil {
+		return nil, err
+	}
+	code := st.GetCode(address)
+	return code, st.Error()
+}
+
+// GetStorageAt returns the storage slot key of address at block blockNr.
+func (api *PublicStateAPI) GetStorageAt(ctx context.Context, address common.Address, key string, blockNr BlockNumber) (hexutil.Bytes, error) {
+	st, _, err := api.b.ethereum.ApiBackend.StateAndHeaderByNumber(ctx, rpc.BlockNumber(blockNr))
+	if st == nil || err != nil {
+		return nil, err
+	}
+	value := st.GetState(address, common.HexToHash(key))
+	return value[:], st.Error()
+}
+
+// GetTransactionCount returns the nonce of address at block blockNr.
+func (api *PublicStateAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNr BlockNumber) (*hexutil.Uint64, error) {
+	st, _, err := api.b.ethereum.ApiBackend.StateAndHeaderByNumber(ctx, rpc.BlockNumber(blockNr))
+	if st == nil || err != nil {
+		return nil, err
+	}
+	nonce := st.GetNonce(address)
+	return (*hexutil.Uint64)(&nonce), st.Error()
+}
+
+// blockGetter is the block method of the blockchain API of go-ethereum,
+// whose type is internal to it.
+type blockGetter interface {
+	GetBlockByNumber(ctx context.Context, blockNr rpc.BlockNumber, fullTx bool) (map[string]interface{}, error)
+}
+
+// PublicFinalizedBlockAPI replaces eth_getBlockByNumber of go-ethereum with
+// one taking FinalizedTag, the block is still encoded by go-ethereum. It
+// is served under "eth", apart from the block ranges of "ultron", see
+// PublicBlocksAPI.
+type PublicFinalizedBlockAPI struct {
+	blocks blockGetter
+}
+
+// newPublicFinalizedBlockAPI creates the block API over the one of
+// go-ethereum in apis, nil if there is none.
+func newPublicFinalizedBlockAPI(apis []rpc.API) *PublicFinalizedBlockAPI {
+	for _, api := range apis {
+		if blocks, ok := api.Service.(blockGetter); ok && api.Namespace == "eth" {
+			return &PublicFinalizedBlockAPI{blocks}
+		}
+	}
+	return nil
+}
+
+// GetBlockByNumber returns the block blockNr, with its txs in full if
+// fullTx is set or else their hashes.
+func (api *PublicFinalizedBlockAPI) GetBlockByNumber(ctx context.Context, blockNr BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	return api.blocks.GetBlockByNumber(ctx, rpc.BlockNumber(blockNr), fullTx)
+}
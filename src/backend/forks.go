@@ -0,0 +1,42 @@
+package backend
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/dora/ultron/backend/ethereum"
+)
+
+// PublicChainAPI tells which rules the chain runs blocks with and what
+// block proposers earn.
+type PublicChainAPI struct {
+	b *Backend
+}
+
+// NewPublicChainAPI creates the chain rules API of b.
+func NewPublicChainAPI(b *Backend) *PublicChainAPI {
+	return &PublicChainAPI{b}
+}
+
+// ChainRules returns the hardforks active at block number, the latest
+// block if number is not given and the block being built for "pending".
+func (api *PublicChainAPI) ChainRules(number *BlockNumber) ethereum.Rules {
+	return ethereum.RulesAt(api.b.ethereum.ApiBackend.ChainConfig(), api.blockNumber(number))
+}
+
+// blockNumber resolves number to a block number, the latest block if it is
+// not given.
+func (api *PublicChainAPI) blockNumber(number *BlockNumber) *big.Int {
+	head := api.b.ethereum.BlockChain().CurrentBlock().Number()
+	if number == nil {
+		return head
+	}
+	switch rpc.BlockNumber(*number) {
+	case rpc.LatestBlockNumber:
+		return head
+	case rpc.PendingBlockNumber:
+		return new(big.Int).Add(head, big.NewInt(1))
+	}
+	return big.NewInt(int64(*number))
+}
@@ -0,0 +1,237 @@
+package backend
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/ledger"
+)
+
+// Ledger returns the double-entry ledger of the block number: the fees of
+// its txs and the transfers of the ones that didn't fail, then one state
+// entry for the changes of the balances its txs don't explain, which
+// balances against ledger.ChainAccount. The state entry comes from the
+// accounts the block changed in the state trie, so the postings of an
+// account always add up to the change of its balance.
+func (api *PublicChainAPI) Ledger(number BlockNumber) ([]ledger.Posting, error) {
+	chain := api.b.ethereum.BlockChain()
+	block := chain.CurrentBlock()
+	if n := rpc.BlockNumber(number); n >= 0 {
+		block = chain.GetBlockByNumber(uint64(n))
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	var parentRoot common.Hash
+	if block.NumberU64() > 0 {
+		parent := chain.GetBlock(block.ParentHash(), block.NumberU64()-1)
+		if parent == nil {
+			return nil, fmt.Errorf("parent of block #%d not found", block.NumberU64())
+		}
+		parentRoot = parent.Root()
+	}
+
+	journal := ledger.NewJournal(block.NumberU64())
+	explained := make(map[common.Address]*big.Int)
+	line := func(addr common.Address, amount *big.Int) ledger.Line {
+		sum, ok := explained[addr]
+		if !ok {
+			sum = new(big.Int)
+			explained[addr] = sum
+		}
+		sum.Add(sum, amount)
+		return ledger.Line{Account: addr.Hex(), Amount: amount}
+	}
+
+	receipts := core.GetBlockReceipts(api.b.ethereum.ChainDb(), block.Hash(), block.NumberU64())
+	failed, err := api.b.failedTxs(block, parentRoot)
+	if err != nil {
+		return nil, err
+	}
+	signer := ethTypes.MakeSigner(api.b.ethereum.ApiBackend.ChainConfig(), block.Number())
+	for i, tx := range block.Transactions() {
+		if i >= len(receipts) {
+			break
+		}
+		receipt := receipts[i]
+		from, err := ethTypes.Sender(signer, tx)
+		if err != nil {
+			return nil, err
+		}
+		hash := tx.Hash().Hex()
+
+		base, tip := ethereum.SplitFee(tx, receipt.GasUsed)
+		err = journal.Add(ledger.KindFee, hash,
+			line(from, new(big.Int).Neg(new(big.Int).Add(base, tip))),
+			line(block.Coinbase(), tip),
+			ledger.Line{Account: ledger.FeesAccount, Amount: base})
+		if err != nil {
+			return nil, err
+		}
+
+		// the value of a failed tx never moved
+		if failed[i] {
+			continue
+		}
+		to := receipt.ContractAddress
+		if tx.To() != nil {
+			to = *tx.To()
+		}
+		value := tx.Value()
+		err = journal.Add(ledger.KindTransfer, hash, line(from, new(big.Int).Neg(value)), line(to, value))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := api.b.balanceChanges(parentRoot, block.Root())
+	if err != nil {
+		return nil, err
+	}
+	for addr := range explained {
+		if _, ok := changes[addr]; !ok {
+			changes[addr] = new(big.Int)
+		}
+	}
+	addrs := make([]common.Address, 0, len(changes))
+	for addr := range changes {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool { return addrs[i].Hex() < addrs[j].Hex() })
+
+	var lines []ledger.Line
+	issued := new(big.Int)
+	for _, addr := range addrs {
+		rest := new(big.Int).Set(changes[addr])
+		if sum, ok := explained[addr]; ok {
+			rest.Sub(rest, sum)
+		}
+		issued.Add(issued, rest)
+		lines = append(lines, ledger.Line{Account: addr.Hex(), Amount: rest})
+	}
+	lines = append(lines, ledger.Line{Account: ledger.ChainAccount, Amount: issued.Neg(issued)})
+	if err := journal.Add(ledger.KindState, "", lines...); err != nil {
+		return nil, err
+	}
+	return journal.Postings, nil
+}
+
+// failedTxs returns the indexes of the txs of block whose outermost call
+// failed, replaying them on the state of its parent, parentRoot. Receipts
+// can't tell, they have no status before byzantium, see
+// ethereum.LatestFork.
+func (b *Backend) failedTxs(block *ethTypes.Block, parentRoot common.Hash) (map[int]bool, error) {
+	failed := make(map[int]bool)
+	if len(block.Transactions()) == 0 {
+		return failed, nil
+	}
+	st, err := state.New(parentRoot, state.NewDatabase(b.ethereum.ChainDb()))
+	if err != nil {
+		return nil, err
+	}
+	chain := b.ethereum.BlockChain()
+	config := b.ethereum.ApiBackend.ChainConfig()
+	header := block.Header()
+	gp := new(core.GasPool).AddGas(header.GasLimit)
+	usedGas := new(big.Int)
+	for i, tx := range block.Transactions() {
+		trace := new(faultTrace)
+		st.Prepare(tx.Hash(), block.Hash(), i)
+		_, _, err := ethereum.ApplyTransaction(config, chain, nil, gp, st, header, tx, usedGas, vm.Config{Debug: true, Tracer: trace})
+		if err != nil {
+			return nil, fmt.Errorf("replaying tx %s: %v", tx.Hash().Hex(), err)
+		}
+		if trace.failed {
+			failed[i] = true
+		}
+	}
+	return failed, nil
+}
+
+// faultTrace notes whether the outermost call of a tx faulted, which
+// reverts all it did but the gas payment.
+type faultTrace struct {
+	failed bool
+}
+
+func (t *faultTrace) CaptureStart(from common.Address, to common.Address, create bool, input []byte, gas uint64, value *big.Int) error {
+	return nil
+}
+
+func (t *faultTrace) CaptureState(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if err != nil && depth == 1 {
+		t.failed = true
+	}
+	return nil
+}
+
+func (t *faultTrace) CaptureFault(env *vm.EVM, pc uint64, op vm.OpCode, gas, cost uint64, memory *vm.Memory, stack *vm.Stack, contract *vm.Contract, depth int, err error) error {
+	if depth == 1 {
+		t.failed = true
+	}
+	return nil
+}
+
+func (t *faultTrace) CaptureEnd(output []byte, gasUsed uint64, d time.Duration) error {
+	return nil
+}
+
+// balanceChanges returns the change of the balance of the accounts that
+// differ between the state roots before and after, created and deleted
+// ones included.
+func (b *Backend) balanceChanges(before, after common.Hash) (map[common.Address]*big.Int, error) {
+	db := state.NewDatabase(b.ethereum.ChainDb())
+	beforeTrie, err := db.OpenTrie(before)
+	if err != nil {
+		return nil, err
+	}
+	afterTrie, err := db.OpenTrie(after)
+	if err != nil {
+		return nil, err
+	}
+	beforeState, err := state.New(before, db)
+	if err != nil {
+		return nil, err
+	}
+	afterState, err := state.New(after, db)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[common.Address]*big.Int)
+	// the accounts after that aren't the same before, then the other way
+	// round for the deleted ones
+	for _, pair := range [][2]state.Trie{{beforeTrie, afterTrie}, {afterTrie, beforeTrie}} {
+		diff, _ := trie.NewDifferenceIterator(pair[0].NodeIterator(nil), pair[1].NodeIterator(nil))
+		it := trie.NewIterator(diff)
+		for it.Next() {
+			key := pair[1].GetKey(it.Key)
+			if key == nil {
+				return nil, fmt.Errorf("preimage of account %x missing", it.Key)
+			}
+			addr := common.BytesToAddress(key)
+			if _, ok := changes[addr]; ok {
+				continue
+			}
+			change := new(big.Int).Sub(afterState.GetBalance(addr), beforeState.GetBalance(addr))
+			if change.Sign() != 0 {
+				changes[addr] = change
+			}
+		}
+		if it.Err != nil {
+			return nil, it.Err
+		}
+	}
+	return changes, nil
+}
@@ -0,0 +1,217 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+
+	"github.com/dora/ultron/modules/params"
+)
+
+// defaultCallGas is the gas of calls not giving any, as in go-ethereum
+const defaultCallGas = 50000000
+
+// QueryLimits bound what a single RPC request may cost the node, 0 leaves
+// a limit out.
+type QueryLimits struct {
+	LogsBlockRange uint64        // blocks eth_getLogs and log filters go through
+	CallGasCap     uint64        // gas of eth_call, which is metered when set
+	CallTimeout    time.Duration // execution time of eth_call
+	DumpEntries    uint64        // accounts and storage slots of debug_dumpBlock
+	CommitTimeout  time.Duration // wait for the block of a tx of ultron_broadcastTransaction
+}
+
+// SetQueryLimits sets the limits of the RPC requests.
+func (b *Backend) SetQueryLimits(limits QueryLimits) {
+	b.limits = limits
+}
+
+// checkBlockRange fails when the blocks from to to exceed the limit of
+// eth_getLogs, -1 standing for the latest block.
+func (b *Backend) checkBlockRange(from, to int64) error {
+	max := b.limits.LogsBlockRange
+	if max == 0 {
+		return nil
+	}
+	head := b.ethereum.BlockChain().CurrentBlock().Number().Int64()
+	if from < 0 {
+		from = head
+	}
+	if to < 0 {
+		to = head
+	}
+	if to >= from && uint64(to-from) >= max {
+		return fmt.Errorf("block range %d-%d exceeds the limit of %d blocks", from, to, max)
+	}
+	return nil
+}
+
+//----------------------------------------------------------------------
+// eth_call
+
+// CallArgs are the arguments of eth_call.
+type CallArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Gas      hexutil.Big     `json:"gas"`
+	GasPrice hexutil.Big     `json:"gasPrice"`
+	Value    hexutil.Big     `json:"value"`
+	Data     hexutil.Bytes   `json:"data"`
+}
+
+// PublicCallAPI replaces eth_call of go-ethereum with one capping the gas
+// and the execution time of the call.
+type PublicCallAPI struct {
+	b *Backend
+}
+
+// NewPublicCallAPI creates the call API of b.
+func NewPublicCallAPI(b *Backend) *PublicCallAPI {
+	return &PublicCallAPI{b}
+}
+
+// Call executes the message of args on the state of the block blockNr,
+// without making a tx, and returns what it returned.
+func (api *PublicCallAPI) Call(ctx context.Context, args CallArgs, blockNr BlockNumber) (hexutil.Bytes, error) {
+	limits := api.b.limits
+	backend := api.b.ethereum.ApiBackend
+	st, header, err := backend.StateAndHeaderByNumber(ctx, rpc.BlockNumber(blockNr))
+	if st == nil || err != nil {
+		return nil, err
+	}
+
+	gas := args.Gas.ToInt()
+	if gas.Sign() == 0 {
+		gas = big.NewInt(defaultCallGas)
+	}
+	if limits.CallGasCap > 0 && gas.Cmp(new(big.Int).SetUint64(limits.CallGasCap)) > 0 {
+		gas = new(big.Int).SetUint64(limits.CallGasCap)
+	}
+	msg := api.b.callMessage(args, gas)
+
+	var cancel context.CancelFunc
+	if limits.CallTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, limits.CallTimeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	// without a gas cap calls aren't metered, the timeout bounds them
+	evm, vmError, err := backend.GetEVM(ctx, msg, st, header, vm.Config{DisableGasMetering: limits.CallGasCap == 0})
+	if err != nil {
+		return nil, err
+	}
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+
+	gp := new(core.GasPool).AddGas(math.MaxBig256)
+	res, _, err := core.ApplyMessage(evm, msg, gp)
+	if err := vmError(); err != nil {
+		return nil, err
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("execution aborted after %v", limits.CallTimeout)
+	}
+	return res, err
+}
+
+// callMessage returns the message of args with gas, sent by the first
+// account of the node and at the minimum gas price if args don't say.
+func (b *Backend) callMessage(args CallArgs, gas *big.Int) types.Message {
+	from := args.From
+	if from == (common.Address{}) {
+		if wallets := b.ethereum.AccountManager().Wallets(); len(wallets) > 0 {
+			if accounts := wallets[0].Accounts(); len(accounts) > 0 {
+				from = accounts[0].Address
+			}
+		}
+	}
+	gasPrice := args.GasPrice.ToInt()
+	if gasPrice.Sign() == 0 {
+		gasPrice = params.BigInt(params.MinGasPrice)
+	}
+	return types.NewMessage(from, args.To, 0, args.Value.ToInt(), gas, gasPrice, args.Data, false)
+}
+
+//----------------------------------------------------------------------
+// debug_dumpBlock
+
+// DumpBlock returns the state of the block blockNr like go-ethereum does,
+// failing once it holds more accounts and storage slots than the limit.
+func (api *PrivateDebugAPI) DumpBlock(blockNr rpc.BlockNumber) (state.Dump, error) {
+	chain := api.b.ethereum.BlockChain()
+	block := chain.CurrentBlock()
+	if blockNr >= 0 {
+		block = chain.GetBlockByNumber(uint64(blockNr))
+	}
+	if block == nil {
+		return state.Dump{}, fmt.Errorf("block #%d not found", blockNr)
+	}
+
+	db := state.NewDatabase(api.b.ethereum.ChainDb())
+	st, err := state.New(block.Root(), db)
+	if err != nil {
+		return state.Dump{}, err
+	}
+	accounts, err := db.OpenTrie(block.Root())
+	if err != nil {
+		return state.Dump{}, err
+	}
+
+	max := api.b.limits.DumpEntries
+	entries := uint64(0)
+	tooLarge := fmt.Errorf("state dump exceeds the limit of %d accounts and storage slots", max)
+
+	dump := state.Dump{
+		Root:     fmt.Sprintf("%x", block.Root()),
+		Accounts: make(map[string]state.DumpAccount),
+	}
+	it := trie.NewIterator(accounts.NodeIterator(nil))
+	for it.Next() {
+		if entries++; max > 0 && entries > max {
+			return state.Dump{}, tooLarge
+		}
+		var data state.Account
+		if err := rlp.DecodeBytes(it.Value, &data); err != nil {
+			return state.Dump{}, err
+		}
+		addr := common.BytesToAddress(accounts.GetKey(it.Key))
+		account := state.DumpAccount{
+			Balance:  data.Balance.String(),
+			Nonce:    data.Nonce,
+			Root:     common.Bytes2Hex(data.Root.Bytes()),
+			CodeHash: common.Bytes2Hex(data.CodeHash),
+			Code:     common.Bytes2Hex(st.GetCode(addr)),
+			Storage:  make(map[string]string),
+		}
+
+		storage, err := db.OpenStorageTrie(common.BytesToHash(it.Key), data.Root)
+		if err != nil {
+			return state.Dump{}, err
+		}
+		storageIt := trie.NewIterator(storage.NodeIterator(nil))
+		for storageIt.Next() {
+			if entries++; max > 0 && entries > max {
+				return state.Dump{}, tooLarge
+			}
+			account.Storage[common.Bytes2Hex(storage.GetKey(storageIt.Key))] = common.Bytes2Hex(storageIt.Value)
+		}
+		dump.Accounts[common.Bytes2Hex(addr.Bytes())] = account
+	}
+	return dump, nil
+}
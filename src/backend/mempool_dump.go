@@ -0,0 +1,100 @@
+package backend
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	tmTypes "github.com/tendermint/tendermint/types"
+)
+
+var errNoMempool = errors.New("tendermint isn't started yet")
+
+// PrivateMempoolAPI dumps the mempool to a file and checks a dump back in,
+// in the admin namespace, so the load a node reached in a long run can be
+// brought back in a fresh one.
+type PrivateMempoolAPI struct {
+	b *Backend
+}
+
+// NewPrivateMempoolAPI creates the mempool API of b.
+func NewPrivateMempoolAPI(b *Backend) *PrivateMempoolAPI {
+	return &PrivateMempoolAPI{b}
+}
+
+// MempoolLoad is what became of the txs of a dump.
+type MempoolLoad struct {
+	Loaded     int    `json:"loaded"`
+	Refused    int    `json:"refused"`
+	FirstError string `json:"firstError,omitempty"` // why the first refused tx was
+}
+
+// DumpMempool writes the raw txs of the mempool to file, in the order they
+// are reaped, as a stream of rlp strings like the tx journal of geth, and
+// returns how many it wrote.
+func (api *PrivateMempoolAPI) DumpMempool(file string) (int, error) {
+	if api.b.localClient == nil {
+		return 0, errNoMempool
+	}
+	res, err := api.b.localClient.UnconfirmedTxs()
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.Create(file)
+	if err != nil {
+		return 0, err
+	}
+	w := bufio.NewWriter(f)
+	for _, tx := range res.Txs {
+		if err := rlp.Encode(w, []byte(tx)); err != nil {
+			f.Close() // nolint: errcheck
+			return 0, err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close() // nolint: errcheck
+		return 0, err
+	}
+	return len(res.Txs), f.Close()
+}
+
+// LoadMempool checks the txs of a dump of DumpMempool into the mempool, in
+// order. Txs the state no longer takes, such as the ones included since the
+// dump, are refused and counted.
+func (api *PrivateMempoolAPI) LoadMempool(file string) (MempoolLoad, error) {
+	var load MempoolLoad
+	if api.b.localClient == nil {
+		return load, errNoMempool
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return load, err
+	}
+	defer f.Close() // nolint: errcheck
+
+	stream := rlp.NewStream(bufio.NewReader(f), 0)
+	for {
+		tx, err := stream.Bytes()
+		if err == io.EOF {
+			return load, nil
+		}
+		if err != nil {
+			return load, err
+		}
+		res, err := api.b.localClient.BroadcastTxSync(tx, tmTypes.RawTx)
+		switch {
+		case err != nil:
+			return load, err
+		case res.Code != 0:
+			load.Refused++
+			if load.FirstError == "" {
+				load.FirstError = res.Log
+			}
+		default:
+			load.Loaded++
+		}
+	}
+}
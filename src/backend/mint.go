@@ -0,0 +1,23 @@
+package backend
+
+import (
+	"errors"
+
+	"github.com/dora/ultron/modules/mint"
+)
+
+var errNoMinter = errors.New("no block minted along the inflation curve, see mint.enabled")
+
+// Minter returns the inflation of the mint module and the provisions it
+// mints at the latest block.
+func (api *PublicStakeAPI) Minter() (*mint.Minter, error) {
+	value, err := api.b.queryKey(mint.MinterKey)
+	if err != nil {
+		return nil, err
+	}
+	minter, err := mint.ParseMinter(value)
+	if err == nil && minter == nil {
+		return nil, errNoMinter
+	}
+	return minter, err
+}
@@ -0,0 +1,53 @@
+package backend
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/dora/ultron/modules/names"
+)
+
+// ResolveName returns the address name resolves to in the committed state
+// of the name registry.
+func (b *Backend) ResolveName(name string) (common.Address, error) {
+	if !names.IsName(name) {
+		return common.Address{}, fmt.Errorf("invalid name %q", name)
+	}
+	value, err := b.queryKey(names.NameKey(name))
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(value) == 0 {
+		return common.Address{}, fmt.Errorf("name %s is not registered", name)
+	}
+	record, err := names.ParseRecord(value)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return record.Target, nil
+}
+
+// ResolveAddress accepts either a hex address or a name, so transfers can
+// target "alice.ultron".
+func (b *Backend) ResolveAddress(to string) (common.Address, error) {
+	if common.IsHexAddress(to) {
+		return common.HexToAddress(to), nil
+	}
+	return b.ResolveName(to)
+}
+
+// PublicNameAPI resolves the names of the name registry.
+type PublicNameAPI struct {
+	b *Backend
+}
+
+// NewPublicNameAPI creates the name resolution API of b.
+func NewPublicNameAPI(b *Backend) *PublicNameAPI {
+	return &PublicNameAPI{b}
+}
+
+// ResolveName returns the address name resolves to.
+func (api *PublicNameAPI) ResolveName(name string) (common.Address, error) {
+	return api.b.ResolveName(name)
+}
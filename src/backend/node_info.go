@@ -0,0 +1,72 @@
+package backend
+
+import (
+	"errors"
+	"runtime"
+
+	"github.com/dora/ultron/peerversion"
+)
+
+var errNoNode = errors.New("tendermint isn't started yet")
+
+// PublicNodeAPI tells the versions of the node and of its peers.
+type PublicNodeAPI struct {
+	b *Backend
+}
+
+// NewPublicNodeAPI creates the node API of b.
+func NewPublicNodeAPI(b *Backend) *PublicNodeAPI {
+	return &PublicNodeAPI{b}
+}
+
+// NodeInfo is the version of the node and those its peers announced in the
+// handshake.
+type NodeInfo struct {
+	peerversion.Info
+	GoVersion  string     `json:"goVersion"`
+	Tendermint string     `json:"tendermint"`
+	ID         string     `json:"id"`
+	Moniker    string     `json:"moniker"`
+	Network    string     `json:"network"`
+	Peers      []PeerInfo `json:"peers"`
+}
+
+// PeerInfo is the version of a peer.
+type PeerInfo struct {
+	ID       string            `json:"id"`
+	Moniker  string            `json:"moniker"`
+	Outbound bool              `json:"outbound"`
+	Version  *peerversion.Info `json:"version"` // nil when the peer announced none
+}
+
+// NodeInfo returns the versions of the node and of its peers.
+func (api *PublicNodeAPI) NodeInfo() (*NodeInfo, error) {
+	if api.b.localClient == nil {
+		return nil, errNoNode
+	}
+	status, err := api.b.localClient.Status()
+	if err != nil {
+		return nil, err
+	}
+	net, err := api.b.localClient.NetInfo()
+	if err != nil {
+		return nil, err
+	}
+	info := &NodeInfo{
+		Info:       peerversion.Local(),
+		GoVersion:  runtime.Version(),
+		Tendermint: status.NodeInfo.Version,
+		ID:         string(status.NodeInfo.ID()),
+		Moniker:    status.NodeInfo.Moniker,
+		Network:    status.NodeInfo.Network,
+		Peers:      []PeerInfo{},
+	}
+	for _, peer := range net.Peers {
+		p := PeerInfo{ID: string(peer.NodeInfo.ID()), Moniker: peer.NodeInfo.Moniker, Outbound: peer.IsOutbound}
+		if version, ok := peerversion.FromNodeInfo(peer.NodeInfo); ok {
+			p.Version = &version
+		}
+		info.Peers = append(info.Peers, p)
+	}
+	return info, nil
+}
@@ -0,0 +1,30 @@
+package backend
+
+import (
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/dora/ultron/ordering"
+)
+
+var errNoOrderingAudit = errors.New("ordering audit is off, see ordering_audit in the mempool config")
+
+// SetOrderingAuditor lets the chain API serve the violations a finds.
+func (b *Backend) SetOrderingAuditor(a *ordering.Auditor) {
+	b.auditor = a
+}
+
+// OrderingViolations returns the txs of the blocks since from that were
+// included after a tx of another sender arriving here later than them, by
+// more than the tolerance. Only the last ordering.History are kept.
+func (api *PublicChainAPI) OrderingViolations(from hexutil.Uint64) ([]ordering.Violation, error) {
+	if api.b.auditor == nil {
+		return nil, errNoOrderingAudit
+	}
+	violations := api.b.auditor.Violations(uint64(from))
+	if violations == nil {
+		violations = []ordering.Violation{}
+	}
+	return violations, nil
+}
@@ -0,0 +1,92 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/dora/ultron/modules/params"
+)
+
+// PublicParamsAPI reads the chain params.
+type PublicParamsAPI struct {
+	b *Backend
+}
+
+// NewPublicParamsAPI creates the params namespace API of b.
+func NewPublicParamsAPI(b *Backend) *PublicParamsAPI {
+	return &PublicParamsAPI{b}
+}
+
+// ParamValue is a param with its current value.
+type ParamValue struct {
+	Key    string                 `json:"key"`
+	Value  string                 `json:"value"`
+	Local  bool                   `json:"local,omitempty"`
+	Schema map[string]interface{} `json:"schema"`
+}
+
+// Get returns the value of the param key at the latest block, the value of
+// this node for local params.
+func (api *PublicParamsAPI) Get(key string) (string, error) {
+	p, ok := params.Lookup(key)
+	if !ok {
+		return "", params.ErrUnknownParam(key)
+	}
+	return api.value(p)
+}
+
+// List returns all the params with their values and schemas.
+func (api *PublicParamsAPI) List() ([]ParamValue, error) {
+	all := params.All()
+	values := make([]ParamValue, 0, len(all))
+	for _, p := range all {
+		value, err := api.value(p)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, ParamValue{Key: p.Key, Value: value, Local: p.Local, Schema: p.Schema()})
+	}
+	return values, nil
+}
+
+func (api *PublicParamsAPI) value(p params.Param) (string, error) {
+	if p.Local {
+		return params.Get(p.Key), nil
+	}
+	value, err := api.b.queryKey(params.ValueKey(p.Key))
+	if err != nil {
+		return "", err
+	}
+	if value == nil {
+		return p.Default, nil
+	}
+	return string(value), nil
+}
+
+// Changes notifies the subscriber of the params changed on chain.
+func (api *PublicParamsAPI) Changes(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		changes := make(chan params.Change)
+		sub := api.b.SubscribeParamChanges(changes)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-changes:
+				notifier.Notify(rpcSub.ID, ev) // nolint: errcheck
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
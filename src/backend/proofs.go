@@ -0,0 +1,70 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/proofs"
+)
+
+// GetTransactionProof returns the proof linking the tx hash and its receipt
+// to the app hash signed by the validators, see package proofs. Only the
+// blocks committed after the block-hashes upgrade can be proven.
+func (api *PublicChainAPI) GetTransactionProof(hash common.Hash) (*proofs.TransactionProof, error) {
+	db := api.b.ethereum.ChainDb()
+	tx, blockHash, number, index := core.GetTransaction(db, hash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %s not found", hash.Hex())
+	}
+	block := api.b.ethereum.BlockChain().GetBlock(blockHash, number)
+	if block == nil {
+		return nil, fmt.Errorf("block %d not found", number)
+	}
+	receipts := core.GetBlockReceipts(db, blockHash, number)
+	if int(index) >= len(receipts) {
+		return nil, fmt.Errorf("receipts of block %d not found", number)
+	}
+
+	p := &proofs.TransactionProof{
+		BlockNumber: hexutil.Uint64(number),
+		BlockHash:   blockHash,
+		TxIndex:     hexutil.Uint(index),
+	}
+	var err error
+	if p.Header, err = rlp.EncodeToBytes(block.Header()); err != nil {
+		return nil, err
+	}
+	if p.Tx, p.TxProof, err = proofs.Prove(block.Transactions(), int(index)); err != nil {
+		return nil, err
+	}
+	if p.Receipt, p.ReceiptProof, err = proofs.Prove(receipts, int(index)); err != nil {
+		return nil, err
+	}
+
+	p.StoreKey = ethereum.BlockHashKey(number)
+	res, err := api.b.client.ABCIQuery("/key", p.StoreKey, true)
+	if err != nil {
+		return nil, err
+	}
+	if res.Response.IsErr() {
+		return nil, fmt.Errorf("query failed: %s", res.Response.Log)
+	}
+	if !bytes.Equal(res.Response.Value, blockHash[:]) {
+		return nil, fmt.Errorf("block %d isn't committed to by the app hash, it predates the block-hashes upgrade or isn't provable yet", number)
+	}
+	p.StoreProof = res.Response.Proof
+	p.StoreHeight = res.Response.Height
+
+	// the header carrying the app hash, nil until the next block is made
+	signed := p.StoreHeight + 1
+	if commit, err := api.b.client.Commit(&signed); err == nil {
+		p.Commit = commit
+	}
+	return p, nil
+}
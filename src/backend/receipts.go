@@ -0,0 +1,110 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxReceiptHashes is the most txs eth_getTransactionReceipts takes
+const maxReceiptHashes = 10000
+
+// PublicReceiptAPI returns the receipts of many txs in one call, in the eth
+// namespace, encoded as eth_getTransactionReceipt does.
+type PublicReceiptAPI struct {
+	b *Backend
+}
+
+// NewPublicReceiptAPI creates the receipt API of b.
+func NewPublicReceiptAPI(b *Backend) *PublicReceiptAPI {
+	return &PublicReceiptAPI{b}
+}
+
+// GetBlockReceipts returns the receipts of the txs of block blockNr, in
+// their order, nil if there is no such block.
+func (api *PublicReceiptAPI) GetBlockReceipts(ctx context.Context, blockNr BlockNumber) ([]map[string]interface{}, error) {
+	block, err := api.b.ethereum.ApiBackend.BlockByNumber(ctx, rpc.BlockNumber(blockNr))
+	if block == nil || err != nil {
+		return nil, err
+	}
+	receipts := core.GetBlockReceipts(api.b.ethereum.ChainDb(), block.Hash(), block.NumberU64())
+	txs := block.Transactions()
+	if len(receipts) != len(txs) {
+		return nil, fmt.Errorf("receipts of block %d not found", block.NumberU64())
+	}
+	fields := make([]map[string]interface{}, len(txs))
+	for i := range txs {
+		if fields[i], err = api.receiptFields(block, uint64(i), receipts[i]); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// GetTransactionReceipts returns the receipts of the txs of hashes, nil for
+// those not in a block.
+func (api *PublicReceiptAPI) GetTransactionReceipts(hashes []common.Hash) ([]map[string]interface{}, error) {
+	if len(hashes) > maxReceiptHashes {
+		return nil, fmt.Errorf("%d txs exceed the limit of %d", len(hashes), maxReceiptHashes)
+	}
+	db := api.b.ethereum.ChainDb()
+	chain := api.b.ethereum.BlockChain()
+	// txs of one block share its receipts
+	blocks := make(map[common.Hash]*ethTypes.Block)
+	receipts := make(map[common.Hash]ethTypes.Receipts)
+
+	fields := make([]map[string]interface{}, len(hashes))
+	for i, hash := range hashes {
+		tx, blockHash, number, index := core.GetTransaction(db, hash)
+		if tx == nil {
+			continue
+		}
+		block, ok := blocks[blockHash]
+		if !ok {
+			block = chain.GetBlock(blockHash, number)
+			blocks[blockHash] = block
+			receipts[blockHash] = core.GetBlockReceipts(db, blockHash, number)
+		}
+		if block == nil || int(index) >= len(receipts[blockHash]) {
+			continue
+		}
+		var err error
+		if fields[i], err = api.receiptFields(block, index, receipts[blockHash][index]); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
+
+// receiptFields encodes the receipt of the tx index of block as
+// eth_getTransactionReceipt does
+func (api *PublicReceiptAPI) receiptFields(block *ethTypes.Block, index uint64, receipt *ethTypes.Receipt) (map[string]interface{}, error) {
+	// the fields of the receipt itself, in the encoding of go-ethereum
+	data, err := json.Marshal(receipt)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	tx := block.Transactions()[index]
+	signer := ethTypes.MakeSigner(api.b.ethereum.ApiBackend.ChainConfig(), block.Number())
+	from, _ := ethTypes.Sender(signer, tx)
+	fields["blockHash"] = block.Hash()
+	fields["blockNumber"] = hexutil.Uint64(block.NumberU64())
+	fields["transactionIndex"] = hexutil.Uint64(index)
+	fields["from"] = from
+	fields["to"] = tx.To()
+	if tx.To() != nil {
+		fields["contractAddress"] = nil
+	}
+	return fields, nil
+}
@@ -0,0 +1,117 @@
+package backend
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/dora/ultron/signer"
+)
+
+// defaultTxGas is the gas of the txs sent without, as go-ethereum does
+const defaultTxGas = 90000
+
+// SetRemoteSigner signs the txs of eth_sendTransaction with the signing
+// service of client rather than the keystore of the node.
+func (b *Backend) SetRemoteSigner(client *signer.Client) {
+	b.remoteSigner = client
+}
+
+// PublicRemoteSignerAPI overrides the eth methods of go-ethereum using the
+// keys of the node when it is pointed at a signing service, see package
+// signer: the accounts and their signatures are those of the service.
+type PublicRemoteSignerAPI struct {
+	b *Backend
+}
+
+// NewPublicRemoteSignerAPI creates the remote signer API of b.
+func NewPublicRemoteSignerAPI(b *Backend) *PublicRemoteSignerAPI {
+	return &PublicRemoteSignerAPI{b}
+}
+
+// Accounts returns the accounts the signing service signs for.
+func (api *PublicRemoteSignerAPI) Accounts() ([]common.Address, error) {
+	return api.b.remoteSigner.Accounts()
+}
+
+// SendTransaction fills in what args leave out, has the tx signed by the
+// signing service and sends it, returning its hash.
+func (api *PublicRemoteSignerAPI) SendTransaction(ctx context.Context, args SendTxArgs) (common.Hash, error) {
+	tx, err := api.b.newTransaction(ctx, args)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	eth := api.b.ethereum.ApiBackend
+	signed, err := api.b.remoteSigner.SignTx(args.From, tx, eth.ChainConfig().ChainId)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := eth.SendTx(ctx, signed); err != nil {
+		return common.Hash{}, err
+	}
+	return signed.Hash(), nil
+}
+
+// SignTransaction has the tx of args signed by the signing service without
+// sending it, see PrivateSignTxAPI.
+func (api *PublicRemoteSignerAPI) SignTransaction(ctx context.Context, args SendTxArgs) (*SignTransactionResult, error) {
+	if args.Nonce == nil {
+		return nil, errNoNonce
+	}
+	tx, err := api.b.newTransaction(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := api.b.remoteSigner.SignTx(args.From, tx, api.b.ethereum.ApiBackend.ChainConfig().ChainId)
+	if err != nil {
+		return nil, err
+	}
+	return newSignTransactionResult(signed)
+}
+
+// newTransaction returns the tx of args, with the gas and the gas price
+// going to their defaults and the nonce to the next one of the pool of
+// from when left out
+func (b *Backend) newTransaction(ctx context.Context, args SendTxArgs) (*ethTypes.Transaction, error) {
+	eth := b.ethereum.ApiBackend
+	gas := big.NewInt(defaultTxGas)
+	if args.Gas != nil {
+		gas = args.Gas.ToInt()
+	}
+	price := new(big.Int)
+	if args.GasPrice != nil {
+		price = args.GasPrice.ToInt()
+	} else {
+		suggested, err := eth.SuggestPrice(ctx)
+		if err != nil {
+			return nil, err
+		}
+		price = suggested
+	}
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+	var nonce uint64
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
+	} else {
+		var err error
+		if nonce, err = eth.GetPoolNonce(ctx, args.From); err != nil {
+			return nil, err
+		}
+	}
+
+	if args.To == nil {
+		return ethTypes.NewContractCreation(nonce, value, gas, price, args.Data), nil
+	}
+	return ethTypes.NewTransaction(nonce, *args.To, value, gas, price, args.Data), nil
+}
+
+// SignTypedData has the EIP-712 typed data signed by from.
+func (api *PublicRemoteSignerAPI) SignTypedData(from common.Address, data signer.TypedData) (hexutil.Bytes, error) {
+	return api.b.remoteSigner.SignTypedData(from, data)
+}
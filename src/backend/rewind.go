@@ -0,0 +1,75 @@
+package backend
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// recoveredRewind returns the rewind of chain when it was loaded, nil if
+// there was none. A node stopping before the state of its latest blocks is
+// on disk restarts from the last block with its state, keeping the headers
+// of the blocks after it, and tendermint replays these blocks.
+func recoveredRewind(chain *core.BlockChain) *ChainRewindEvent {
+	header, head := chain.CurrentHeader(), chain.CurrentBlock()
+	if header.Number.Cmp(head.Number()) <= 0 {
+		return nil
+	}
+	log.Warn("Chain rewound to recover missing state, tendermint replays the blocks after it",
+		"from", header.Number, "to", head.Number())
+	return &ChainRewindEvent{
+		From:   header.Number.Uint64(),
+		To:     head.NumberU64(),
+		Hash:   head.Hash(),
+		Reason: RewindRecovery,
+	}
+}
+
+// RewindUncommitted drops the blocks after number, the last one the app
+// committed as a whole, when the node stopped while committing the block
+// after it: tendermint replays that block from the one the app has, and
+// builds the same ethereum block again. The rewind is posted as the first
+// block replayed commits. It is to be called at start, before any block.
+func (b *Backend) RewindUncommitted(number uint64) error {
+	ev, err := b.rewind(number, RewindReplay)
+	if err != nil || ev == nil {
+		return err
+	}
+	log.Warn("Chain rewound to the last block the app committed, tendermint replays the blocks after it",
+		"from", ev.From, "to", ev.To)
+	if b.recovery != nil && b.recovery.From > ev.From {
+		ev.From = b.recovery.From
+	}
+	b.recovery = ev
+	return nil
+}
+
+// Rewinds notifies the subscriber of the rewinds of the ethereum chain: the
+// blocks after the block rewound to are dropped and built again.
+func (api *PublicChainAPI) Rewinds(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		rewinds := make(chan ChainRewindEvent)
+		sub := api.b.SubscribeChainRewinds(rewinds)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-rewinds:
+				notifier.Notify(rpcSub.ID, ev) // nolint: errcheck
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
@@ -0,0 +1,18 @@
+package backend
+
+import (
+	"github.com/dora/ultron/rpcschema"
+	"github.com/dora/ultron/version"
+)
+
+// OpenRPC returns the OpenRPC document of the ultron_* methods, generated
+// from their Go types, to generate client libraries from.
+func OpenRPC() *rpcschema.Document {
+	// the services only keep the backend, none is needed for their types
+	return rpcschema.Generate("Ultron JSON-RPC", version.Version, (*Backend)(nil).ultronAPIs())
+}
+
+// Schema returns the OpenRPC document of the ultron_* methods.
+func (api *PublicChainAPI) Schema() *rpcschema.Document {
+	return OpenRPC()
+}
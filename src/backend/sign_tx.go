@@ -0,0 +1,69 @@
+package backend
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/dora/ultron/backend/ethereum"
+)
+
+// errNoNonce is returned signing a tx without its nonce: txs signed to be
+// sent later can't count on the nonce of the pool, which they don't move
+var errNoNonce = errors.New("the nonce of txs signed without sending them is needed")
+
+// SignTransactionResult is a tx signed and not sent, as eth_signTransaction
+// of go-ethereum returns it. Raw is what eth_sendRawTransaction takes.
+type SignTransactionResult struct {
+	Raw hexutil.Bytes         `json:"raw"`
+	Tx  *ethTypes.Transaction `json:"tx"`
+}
+
+func newSignTransactionResult(signed *ethTypes.Transaction) (*SignTransactionResult, error) {
+	raw, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	// sent to this node later, CheckTx finds its sender already recovered
+	ethereum.RememberTx(signed)
+	return &SignTransactionResult{Raw: raw, Tx: signed}, nil
+}
+
+// PrivateSignTxAPI signs txs with the keystore without sending them, for a
+// coordinator to batch them and send them later with
+// eth_sendRawTransaction.
+type PrivateSignTxAPI struct {
+	b *Backend
+}
+
+// NewPrivateSignTxAPI creates the tx signing API of b.
+func NewPrivateSignTxAPI(b *Backend) *PrivateSignTxAPI {
+	return &PrivateSignTxAPI{b}
+}
+
+// SignTransaction signs the tx of args with the key of args.From unlocked
+// with passwd. Its nonce must be given, the gas and the gas price default
+// as for eth_sendTransaction.
+func (api *PrivateSignTxAPI) SignTransaction(ctx context.Context, args SendTxArgs, passwd string) (*SignTransactionResult, error) {
+	if args.Nonce == nil {
+		return nil, errNoNonce
+	}
+	tx, err := api.b.newTransaction(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	account := accounts.Account{Address: args.From}
+	wallet, err := api.b.ethereum.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := wallet.SignTxWithPassphrase(account, passwd, tx, api.b.ethereum.ApiBackend.ChainConfig().ChainId)
+	if err != nil {
+		return nil, err
+	}
+	return newSignTransactionResult(signed)
+}
@@ -0,0 +1,203 @@
+package backend
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/dora/ultron/modules/stake"
+)
+
+const (
+	defaultValidatorsPerPage = 30
+	maxValidatorsPerPage     = 100
+)
+
+// PublicStakeAPI reads the validators of the stake module.
+type PublicStakeAPI struct {
+	b *Backend
+}
+
+// NewPublicStakeAPI creates the stake namespace API of b.
+func NewPublicStakeAPI(b *Backend) *PublicStakeAPI {
+	return &PublicStakeAPI{b}
+}
+
+// ProjectedAPR estimates the yearly return of delegating to the validator
+// owned by validator, at the comp rate it gives them, at the latest block.
+func (api *PublicStakeAPI) ProjectedAPR(validator common.Address) (*stake.Projection, error) {
+	value, err := api.b.query("/stake/apr", validator.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	var p stake.Projection
+	if err := json.Unmarshal(value, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// PendingUnbondings returns the withdrawals of delegator still waiting to
+// unbond, with the height they are paid out at.
+func (api *PublicStakeAPI) PendingUnbondings(delegator common.Address) ([]stake.Unbonding, error) {
+	value, err := api.b.queryKey(stake.UnbondingKey(delegator))
+	if err != nil {
+		return nil, err
+	}
+	return stake.ParseUnbondings(value)
+}
+
+// Redelegations returns the redelegations of delegator that haven't matured.
+func (api *PublicStakeAPI) Redelegations(delegator common.Address) ([]stake.Redelegation, error) {
+	value, err := api.b.queryKey(stake.RedelegationKey(delegator))
+	if err != nil {
+		return nil, err
+	}
+	return stake.ParseRedelegations(value)
+}
+
+// Downtime returns the missed block counters of the validator owned by
+// validator, and whether it is jailed.
+func (api *PublicStakeAPI) Downtime(validator common.Address) (*stake.Downtime, error) {
+	value, err := api.b.queryKey(stake.DowntimeKey(validator))
+	if err != nil {
+		return nil, err
+	}
+	downtime, err := stake.ParseDowntime(value)
+	if err != nil {
+		return nil, err
+	}
+	return &downtime, nil
+}
+
+// Performance returns the block production counters of the validators,
+// with their proposal and miss rates and the fullness of the blocks they
+// proposed, for validator rankings.
+func (api *PublicStakeAPI) Performance() ([]stake.PerformanceReport, error) {
+	value, err := api.b.query("/stake/performance", nil)
+	if err != nil {
+		return nil, err
+	}
+	var reports []stake.PerformanceReport
+	if err := json.Unmarshal(value, &reports); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// Jailings notifies the subscriber of the validators jailed for downtime.
+func (api *PublicStakeAPI) Jailings(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+	go func() {
+		jailings := make(chan stake.JailEvent)
+		sub := api.b.SubscribeJailings(jailings)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-jailings:
+				notifier.Notify(rpcSub.ID, ev) // nolint: errcheck
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+	return rpcSub, nil
+}
+
+// ValidatorPage is a page of a validator set.
+type ValidatorPage struct {
+	Height     int64                  `json:"height"` // block the set last changed at
+	Total      int                    `json:"total"`
+	Page       int                    `json:"page"`
+	PerPage    int                    `json:"perPage"`
+	Validators []stake.ValidatorPower `json:"validators"`
+}
+
+// Validators pages through the current validator set, biggest power first.
+// Pages start at 1, with 30 validators each unless perPage says otherwise.
+func (api *PublicStakeAPI) Validators(page, perPage *int) (*ValidatorPage, error) {
+	return api.ValidatorsAt(0, page, perPage)
+}
+
+// ValidatorsAt pages through the validator set as of the block at height,
+// the latest block for 0.
+func (api *PublicStakeAPI) ValidatorsAt(height int64, page, perPage *int) (*ValidatorPage, error) {
+	if height < 0 {
+		return nil, fmt.Errorf("invalid height %d", height)
+	}
+	p, n := 1, defaultValidatorsPerPage
+	if page != nil {
+		p = *page
+	}
+	if perPage != nil {
+		n = *perPage
+	}
+	if p < 1 || n < 1 || n > maxValidatorsPerPage {
+		return nil, fmt.Errorf("page must be at least 1 and perPage between 1 and %d", maxValidatorsPerPage)
+	}
+
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, uint64(height))
+	value, err := api.b.query("/stake/validators", key)
+	if err != nil {
+		return nil, err
+	}
+	var set stake.ValidatorSet
+	if err := json.Unmarshal(value, &set); err != nil {
+		return nil, err
+	}
+
+	res := &ValidatorPage{
+		Height:     set.Height,
+		Total:      len(set.Validators),
+		Page:       p,
+		PerPage:    n,
+		Validators: []stake.ValidatorPower{},
+	}
+	if start := (p - 1) * n; start < len(set.Validators) {
+		end := start + n
+		if end > len(set.Validators) {
+			end = len(set.Validators)
+		}
+		res.Validators = set.Validators[start:end]
+	}
+	return res, nil
+}
+
+// Epoch is the span of blocks the validator set stays the same for.
+type Epoch struct {
+	Blocks int64 `json:"blocks"` // 0 if the set may change after every block
+	Number int64 `json:"number"`
+	First  int64 `json:"first"`
+	Last   int64 `json:"last"` // the set changes at the end of it
+}
+
+// Epoch returns the epoch of the block at height, of the latest block if
+// height is not given.
+func (api *PublicStakeAPI) Epoch(height *int64) (*Epoch, error) {
+	value, err := api.b.queryKey(stake.EpochBlocksKey)
+	if err != nil {
+		return nil, err
+	}
+	blocks := stake.ParseEpochBlocks(value)
+
+	h := api.b.ethereum.BlockChain().CurrentBlock().Number().Int64()
+	if height != nil {
+		h = *height
+	}
+	e := &Epoch{Blocks: blocks}
+	e.Number, e.First, e.Last = stake.EpochOf(blocks, h)
+	return e, nil
+}
@@ -0,0 +1,22 @@
+package backend
+
+import (
+	"fmt"
+)
+
+// queryKey reads key in the committed state of the app store, nil if unset
+func (b *Backend) queryKey(key []byte) ([]byte, error) {
+	return b.query("/key", key)
+}
+
+// query runs the ABCI query path of the app with data
+func (b *Backend) query(path string, data []byte) ([]byte, error) {
+	res, err := b.client.ABCIQuery(path, data, false)
+	if err != nil {
+		return nil, err
+	}
+	if res.Response.IsErr() {
+		return nil, fmt.Errorf("query failed: %s", res.Response.Log)
+	}
+	return res.Response.Value, nil
+}
@@ -0,0 +1,25 @@
+package backend
+
+import (
+	"errors"
+
+	"github.com/dora/ultron/storeprune"
+)
+
+var errNoPruner = errors.New("the app state is pruned as the blocks commit, see prune_interval in the disk config")
+
+// SetStorePruner lets the debug API serve the stats of p.
+func (b *Backend) SetStorePruner(p *storeprune.Pruner) {
+	b.pruner = p
+}
+
+// StorePruning returns how many versions of the app state were deleted in
+// the background since the node started, and the space the compactions
+// that followed took back.
+func (api *PrivateDebugAPI) StorePruning() (*storeprune.Stats, error) {
+	if api.b.pruner == nil {
+		return nil, errNoPruner
+	}
+	stats := api.b.pruner.Stats()
+	return &stats, nil
+}
@@ -0,0 +1,76 @@
+package backend
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/supply"
+)
+
+// Supply is the ether supply at the latest block, in wei.
+type Supply struct {
+	Genesis     *hexutil.Big `json:"genesis"` // allocated by the genesis
+	Minted      *hexutil.Big `json:"minted"`
+	Burned      *hexutil.Big `json:"burned"`
+	Total       *hexutil.Big `json:"total"`       // genesis + minted - burned
+	Circulating *hexutil.Big `json:"circulating"` // total less the bonded stake and the storage deposits
+}
+
+// Supply returns the total and circulating ether supply, for market data
+// providers. Ether sent to the burn account leaves the supply at the end
+// of the block.
+func (api *PublicChainAPI) Supply() (*Supply, error) {
+	genesis, err := api.b.GenesisSupply()
+	if err != nil {
+		return nil, err
+	}
+	minted, err := api.b.queryKey(supply.MintedKey)
+	if err != nil {
+		return nil, err
+	}
+	burned, err := api.b.queryKey(supply.BurnedKey)
+	if err != nil {
+		return nil, err
+	}
+	statedb, err := api.b.ethereum.BlockChain().State()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Supply{
+		Genesis: (*hexutil.Big)(genesis),
+		Minted:  (*hexutil.Big)(new(big.Int).SetBytes(minted)),
+		Burned:  (*hexutil.Big)(new(big.Int).SetBytes(burned)),
+	}
+	total := new(big.Int).Add(genesis, s.Minted.ToInt())
+	total.Sub(total, s.Burned.ToInt())
+	circulating := new(big.Int).Sub(total, statedb.GetBalance(constant.StakeAccount))
+	circulating.Sub(circulating, statedb.GetBalance(constant.RentAccount))
+	s.Total, s.Circulating = (*hexutil.Big)(total), (*hexutil.Big)(circulating)
+	return s, nil
+}
+
+// GenesisSupply returns the ether the genesis allocated, summed from the
+// genesis state once.
+func (b *Backend) GenesisSupply() (*big.Int, error) {
+	b.genesisSupplyMtx.Lock()
+	defer b.genesisSupplyMtx.Unlock()
+	if b.genesisSupply != nil {
+		return b.genesisSupply, nil
+	}
+	chain := b.ethereum.BlockChain()
+	statedb, err := chain.StateAt(chain.Genesis().Root())
+	if err != nil {
+		return nil, err
+	}
+	total := new(big.Int)
+	for _, account := range statedb.RawDump().Accounts {
+		if balance, ok := new(big.Int).SetString(account.Balance, 10); ok {
+			total.Add(total, balance)
+		}
+	}
+	b.genesisSupply = total
+	return total, nil
+}
@@ -0,0 +1,65 @@
+package backend
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+
+	"github.com/dora/ultron/backend/ethereum"
+)
+
+// maxEarningsBlocks bounds the blocks one ultron_proposerEarnings scans
+const maxEarningsBlocks = 10000
+
+// ProposerEarnings is what an account earned proposing blocks
+type ProposerEarnings struct {
+	From   hexutil.Uint64 `json:"from"`
+	To     hexutil.Uint64 `json:"to"`
+	Blocks hexutil.Uint64 `json:"blocks"` // proposed by the account
+	Tips   *hexutil.Big   `json:"tips"`
+}
+
+// ProposerEarnings sums the tips account got for the blocks it proposed
+// from block from to block to. They default to the latest block and the
+// maxEarningsBlocks blocks before it.
+func (api *PublicChainAPI) ProposerEarnings(account common.Address, from, to *BlockNumber) (*ProposerEarnings, error) {
+	last := api.blockNumber(to).Uint64()
+	first := uint64(1)
+	if last > maxEarningsBlocks {
+		first = last - maxEarningsBlocks + 1
+	}
+	if from != nil {
+		first = api.blockNumber(from).Uint64()
+	}
+	if first > last || last-first >= maxEarningsBlocks {
+		return nil, fmt.Errorf("block range must hold 1 to %d blocks", maxEarningsBlocks)
+	}
+
+	chain := api.b.ethereum.BlockChain()
+	db := api.b.ethereum.ChainDb()
+	res := &ProposerEarnings{From: hexutil.Uint64(first), To: hexutil.Uint64(last)}
+	tips := new(big.Int)
+	for n := first; n <= last; n++ {
+		block := chain.GetBlockByNumber(n)
+		if block == nil {
+			break
+		}
+		if block.Coinbase() != account {
+			continue
+		}
+		res.Blocks++
+		receipts := core.GetBlockReceipts(db, block.Hash(), n)
+		for i, tx := range block.Transactions() {
+			if i >= len(receipts) {
+				break
+			}
+			_, tip := ethereum.SplitFee(tx, receipts[i].GasUsed)
+			tips.Add(tips, tip)
+		}
+	}
+	res.Tips = (*hexutil.Big)(tips)
+	return res, nil
+}
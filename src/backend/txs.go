@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/chaos"
 	"github.com/dora/ultron/errors"
 	"github.com/ethereum/go-ethereum/core"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
@@ -72,13 +73,17 @@ func (b *Backend) txBroadcastLoop() {
 			// event := ethereum.TxPreEvent{Tx:txObj.Data.(core.TxPreEvent).Tx, Local:true}
 			// b.ethereum.EventMux().Post(event)
 			event := txObj.Data.(core.TxPreEvent)
+			if chaos.DropGossip() {
+				log.Debug("chaos: dropped tx gossip", "hash", event.Tx.Hash())
+				continue
+			}
 			//fmt.Println("new tx", event.Tx.Nonce())
 			result, err := b.BroadcastTxSync(event.Tx)
 			if err != nil {
 				log.Error("Broadcast error", "err", err)
 			} else {
 				if result.Code != uint32(0) && result.Code != errors.ErrorTypeBadNonce {
-					go removeTx(b, event.Tx)
+					go removeTx(b, event.Tx, result)
 				} else {
 					// TODO: do something else?
 				}
@@ -86,6 +91,10 @@ func (b *Backend) txBroadcastLoop() {
 		case ptxObj := <-b.ptxSub.Chan():
 			//monitor tx
 			event := ptxObj.Data.(ethereum.PtxPreEvent)
+			if chaos.DropGossip() {
+				log.Debug("chaos: dropped ptx gossip", "hash", event.Ptx.Hash())
+				continue
+			}
 			fmt.Println("broadcast new ptx", event.Ptx.Hash().Hex())
 			//monitor ptx
 			//TODO:assign nil to avoid compile error
@@ -175,6 +184,7 @@ func waitForServer(c *rpcClient.HTTP) {
 	}
 }
 
-func removeTx(b *Backend, tx *ethTypes.Transaction) {
+func removeTx(b *Backend, tx *ethTypes.Transaction, result *ctypes.ResultBroadcastTx) {
 	b.Ethereum().TxPool().Remove(tx.Hash())
+	b.ethereum.EventMux().Post(TxDroppedEvent{Tx: tx, Code: result.Code, Log: result.Log}) // nolint: errcheck
 }
@@ -0,0 +1,48 @@
+package backend
+
+import (
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/dora/ultron/signer"
+)
+
+// PrivateTypedDataAPI signs EIP-712 typed data with the keystore and
+// recovers its signers, in the personal namespace, so dapps asking for
+// typed data approvals (signTypedData v4) work against the node.
+type PrivateTypedDataAPI struct {
+	b *Backend
+}
+
+// NewPrivateTypedDataAPI creates the typed data API of b.
+func NewPrivateTypedDataAPI(b *Backend) *PrivateTypedDataAPI {
+	return &PrivateTypedDataAPI{b}
+}
+
+// SignTypedData signs data with the key of addr unlocked with passwd, in
+// the order of the arguments of personal_sign. The signature is r ‖ s ‖ v
+// with v being 27 or 28.
+func (api *PrivateTypedDataAPI) SignTypedData(data signer.TypedData, addr common.Address, passwd string) (hexutil.Bytes, error) {
+	hash, err := data.Hash()
+	if err != nil {
+		return nil, err
+	}
+	account := accounts.Account{Address: addr}
+	wallet, err := api.b.ethereum.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := wallet.SignHashWithPassphrase(account, passwd, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// EcRecoverTypedData returns the account that signed data with sig, as
+// SignTypedData signs.
+func (api *PrivateTypedDataAPI) EcRecoverTypedData(data signer.TypedData, sig hexutil.Bytes) (common.Address, error) {
+	return data.Recover(sig)
+}
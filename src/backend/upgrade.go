@@ -0,0 +1,28 @@
+package backend
+
+import (
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// UpgradeStatus is the upgrade scheduled on chain, if any.
+type UpgradeStatus struct {
+	Plan *upgrade.Plan `json:"plan"`
+
+	// Needed is set when this binary lacks the upgrade, the node will stop
+	// at its height for a supervisor to swap the binary.
+	Needed bool `json:"needed"`
+}
+
+// Upgrade returns the upgrade scheduled at the latest block, and whether
+// this node needs another binary for it.
+func (api *PublicChainAPI) Upgrade() (*UpgradeStatus, error) {
+	value, err := api.b.queryKey(upgrade.PlanKey)
+	if err != nil {
+		return nil, err
+	}
+	plan, err := upgrade.ParsePlan(value)
+	if err != nil {
+		return nil, err
+	}
+	return &UpgradeStatus{Plan: plan, Needed: plan != nil && !upgrade.Known(plan.Name)}, nil
+}
@@ -0,0 +1,56 @@
+package backend
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/dora/ultron/modules/stake"
+)
+
+// validatorMetric is a metric of the validators in the prometheus text
+// format
+type validatorMetric struct {
+	name, kind, help string
+	value            func(r stake.PerformanceReport) interface{}
+}
+
+var validatorMetrics = []validatorMetric{
+	{"ultron_validator_signed_blocks", "counter", "Blocks the validator signed.",
+		func(r stake.PerformanceReport) interface{} { return r.Signed }},
+	{"ultron_validator_missed_blocks", "counter", "Blocks the validator didn't sign.",
+		func(r stake.PerformanceReport) interface{} { return r.Missed }},
+	{"ultron_validator_proposed_blocks", "counter", "Blocks the validator proposed.",
+		func(r stake.PerformanceReport) interface{} { return r.Proposed }},
+	{"ultron_validator_proposed_gas_used", "counter", "Gas used by the blocks the validator proposed.",
+		func(r stake.PerformanceReport) interface{} { return r.ProposedGasUsed }},
+	{"ultron_validator_proposal_rate", "gauge", "Share of the blocks it validated the validator proposed.",
+		func(r stake.PerformanceReport) interface{} { return r.ProposalRate }},
+	{"ultron_validator_miss_rate", "gauge", "Share of the blocks it validated the validator didn't sign.",
+		func(r stake.PerformanceReport) interface{} { return r.MissRate }},
+	{"ultron_validator_block_fullness", "gauge", "Gas used over the gas limit of the blocks the validator proposed.",
+		func(r stake.PerformanceReport) interface{} { return r.Fullness }},
+}
+
+// ValidatorMetrics serves the performance of the validators, see
+// PublicStakeAPI.Performance, in the prometheus text format for scrapers.
+func (b *Backend) ValidatorMetrics() http.Handler {
+	api := NewPublicStakeAPI(b)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reports, err := api.Performance()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		var buf bytes.Buffer
+		for _, m := range validatorMetrics {
+			fmt.Fprintf(&buf, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.kind)
+			for _, report := range reports {
+				fmt.Fprintf(&buf, "%s{validator=\"%s\"} %v\n", m.name, report.Validator.Hex(), m.value(report))
+			}
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		w.Write(buf.Bytes()) // nolint: errcheck
+	})
+}
@@ -0,0 +1,293 @@
+// Package backup backs the databases of a node up to a bucket and restores
+// them, for a node lost with its disk to come back without syncing the
+// chain from the start.
+//
+// A backup starts with a checkpoint of the home of the node, taken between
+// two blocks so the leveldbs of the chain and of the app state (the iavl
+// versions of merkleeyes), and the stake db, agree on the last block. The
+// files of the checkpoint are then stored by the sha256 of their content,
+// along with a manifest of the backup listing them: a file already stored
+// by an earlier backup, as most leveldb tables are, isn't uploaded again,
+// so all the backups but the first are incremental. The bucket holds
+//
+//	objects/<sha256>        the content of the files
+//	backups/<id>.json       the manifests, by the time they were taken
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ManifestVersion is the version of the manifests Create writes.
+const ManifestVersion = 1
+
+const (
+	objectsPrefix = "objects/"
+	backupsPrefix = "backups/"
+	idFormat      = "20060102T150405Z"
+)
+
+// Manifest lists the files of a backup.
+type Manifest struct {
+	Version int       `json:"version"`
+	ID      string    `json:"id"`
+	Height  int64     `json:"height"`
+	Created time.Time `json:"created"`
+	Files   []File    `json:"files"`
+}
+
+// File is a file of a backup, stored under objects/<SHA256>.
+type File struct {
+	Path   string `json:"path"` // slash separated, relative to the home
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Size returns the bytes of the files of m.
+func (m *Manifest) Size() (size int64) {
+	for _, f := range m.Files {
+		size += f.Size
+	}
+	return size
+}
+
+// Stats tells what a backup or a restore did.
+type Stats struct {
+	Files       int
+	Transferred int   // files uploaded or downloaded, the others being there already
+	Bytes       int64 // of the files transferred
+}
+
+// Create backs the checkpoint cp up to store, with parallel uploads at a
+// time, and returns its manifest.
+func Create(store Store, cp *Checkpoint, parallel int) (*Manifest, Stats, error) {
+	m := &Manifest{Version: ManifestVersion, ID: cp.Created.UTC().Format(idFormat), Height: cp.Height,
+		Created: cp.Created}
+	err := filepath.Walk(cp.Dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil || !info.Mode().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(cp.Dir, file)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, File{Path: filepath.ToSlash(rel), Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, Stats{}, err
+	}
+
+	var mtx sync.Mutex
+	stats := Stats{Files: len(m.Files)}
+	err = forEach(len(m.Files), parallel, func(i int) error {
+		f := &m.Files[i]
+		file := filepath.Join(cp.Dir, filepath.FromSlash(f.Path))
+		sum, err := hashFile(file)
+		if err != nil {
+			return err
+		}
+		f.SHA256 = sum
+		key := objectsPrefix + sum
+		if ok, err := store.Has(key); err != nil || ok {
+			return err
+		}
+		in, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer in.Close() // nolint: errcheck
+		if err := store.Put(key, in, f.Size); err != nil {
+			return err
+		}
+		mtx.Lock()
+		stats.Transferred++
+		stats.Bytes += f.Size
+		mtx.Unlock()
+		return nil
+	})
+	if err != nil {
+		return nil, stats, err
+	}
+
+	// the manifest goes last, a backup is listed once all its files are in
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, stats, err
+	}
+	if err := store.Put(backupsPrefix+m.ID+".json", strings.NewReader(string(data)), int64(len(data))); err != nil {
+		return nil, stats, err
+	}
+	return m, stats, nil
+}
+
+// List returns the ids of the backups of store, the oldest first.
+func List(store Store) ([]string, error) {
+	keys, err := store.List(backupsPrefix)
+	if err != nil {
+		return nil, err
+	}
+	var ids []string
+	for _, key := range keys {
+		if strings.HasSuffix(key, ".json") {
+			ids = append(ids, strings.TrimSuffix(strings.TrimPrefix(key, backupsPrefix), ".json"))
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Load returns the manifest of the backup id of store, the latest one when
+// id is "latest".
+func Load(store Store, id string) (*Manifest, error) {
+	if id == "latest" {
+		ids, err := List(store)
+		if err != nil {
+			return nil, err
+		}
+		if len(ids) == 0 {
+			return nil, fmt.Errorf("no backup")
+		}
+		id = ids[len(ids)-1]
+	}
+	r, err := store.Get(backupsPrefix + id + ".json")
+	if err == ErrNotFound {
+		return nil, fmt.Errorf("no backup %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close() // nolint: errcheck
+	var m Manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("backup %s: %v", id, err)
+	}
+	if m.Version == 0 || m.Version > ManifestVersion {
+		return nil, fmt.Errorf("backup %s: unknown version %d", id, m.Version)
+	}
+	return &m, nil
+}
+
+// Restore writes the files of m from store under dst, with parallel
+// downloads at a time. The files already under dst with the content of the
+// backup are kept, so a restore cut short can be run again.
+func Restore(store Store, m *Manifest, dst string, parallel int) (Stats, error) {
+	for _, f := range m.Files {
+		if clean := filepath.ToSlash(filepath.Clean(filepath.FromSlash(f.Path))); clean != f.Path ||
+			strings.HasPrefix(clean, "../") || filepath.IsAbs(f.Path) {
+			return Stats{}, fmt.Errorf("backup %s: invalid path %s", m.ID, f.Path)
+		}
+	}
+
+	var mtx sync.Mutex
+	stats := Stats{Files: len(m.Files)}
+	err := forEach(len(m.Files), parallel, func(i int) error {
+		f := m.Files[i]
+		file := filepath.Join(dst, filepath.FromSlash(f.Path))
+		if sum, err := hashFile(file); err == nil && sum == f.SHA256 {
+			return nil
+		}
+		if err := download(store, f, file); err != nil {
+			return fmt.Errorf("%s: %v", f.Path, err)
+		}
+		mtx.Lock()
+		stats.Transferred++
+		stats.Bytes += f.Size
+		mtx.Unlock()
+		return nil
+	})
+	return stats, err
+}
+
+// download writes the object of f into file, checking its hash
+func download(store Store, f File, file string) error {
+	r, err := store.Get(objectsPrefix + f.SHA256)
+	if err != nil {
+		return err
+	}
+	defer r.Close() // nolint: errcheck
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".restore")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if n != f.Size || hex.EncodeToString(h.Sum(nil)) != f.SHA256 {
+		return fmt.Errorf("corrupted object %s", f.SHA256)
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func hashFile(file string) (string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close() // nolint: errcheck
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// forEach runs do for 0 to n-1, parallel at a time, returning the first
+// error, after which no more runs start
+func forEach(n, parallel int, do func(i int) error) error {
+	if parallel < 1 {
+		parallel = 1
+	}
+	var (
+		wg    sync.WaitGroup
+		mtx   sync.Mutex
+		first error
+		next  int
+	)
+	for w := 0; w < parallel; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				mtx.Lock()
+				if first != nil || next == n {
+					mtx.Unlock()
+					return
+				}
+				i := next
+				next++
+				mtx.Unlock()
+
+				if err := do(i); err != nil {
+					mtx.Lock()
+					if first == nil {
+						first = err
+					}
+					mtx.Unlock()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	return first
+}
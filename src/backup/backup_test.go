@@ -0,0 +1,94 @@
+package backup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, root string, files map[string]string) {
+	for name, content := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, ioutil.WriteFile(file, []byte(content), 0644))
+	}
+}
+
+func TestBackup(t *testing.T) {
+	dir, err := ioutil.TempDir("", "backup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	home := filepath.Join(dir, "home")
+	writeFiles(t, home, map[string]string{
+		"data/merkleeyes.db/CURRENT":         "MANIFEST-000001\n",
+		"data/merkleeyes.db/MANIFEST-000001": "manifest",
+		"data/merkleeyes.db/000002.ldb":      "table",
+		"data/merkleeyes.db/000003.log":      "journal",
+		"data/merkleeyes.db/LOCK":            "",
+		"data/stake.db":                      "sqlite",
+		"data/priv_validator.json":           "key",
+		"config/config.toml":                 "config",
+	})
+
+	paused := false
+	c := &Checkpointer{
+		Root:   home,
+		Dirs:   []string{"data", "ultron/chaindata"},
+		Skip:   func(rel string) bool { return strings.Contains(rel, "priv_validator") },
+		Pause:  func(f func() error) error { paused = true; return f() },
+		Height: func() int64 { return 42 },
+	}
+	store := NewFileStore(filepath.Join(dir, "bucket"))
+
+	cp, err := c.Checkpoint(filepath.Join(dir, "cp1"))
+	require.NoError(t, err)
+	assert.True(t, paused)
+	assert.Equal(t, int64(42), cp.Height)
+	_, err = os.Stat(filepath.Join(cp.Dir, "data/merkleeyes.db/LOCK"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(filepath.Join(cp.Dir, "data/priv_validator.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	m, stats, err := Create(store, cp, 2)
+	require.NoError(t, err)
+	assert.Len(t, m.Files, 5)
+	assert.Equal(t, 5, stats.Transferred)
+
+	// the next backup only uploads what changed
+	writeFiles(t, home, map[string]string{"data/stake.db": "sqlite, later"})
+	cp, err = c.Checkpoint(filepath.Join(dir, "cp2"))
+	require.NoError(t, err)
+	cp.Created = cp.Created.Add(1e9)
+	m, stats, err = Create(store, cp, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats.Transferred)
+
+	ids, err := List(store)
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+	latest, err := Load(store, "latest")
+	require.NoError(t, err)
+	assert.Equal(t, m.ID, latest.ID)
+
+	restored := filepath.Join(dir, "restored")
+	stats, err = Restore(store, latest, restored, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 5, stats.Transferred)
+	content, err := ioutil.ReadFile(filepath.Join(restored, "data/stake.db"))
+	require.NoError(t, err)
+	assert.Equal(t, "sqlite, later", string(content))
+
+	// restoring again keeps what is there
+	stats, err = Restore(store, latest, restored, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats.Transferred)
+
+	latest.Files[0].Path = "../escape"
+	_, err = Restore(store, latest, restored, 2)
+	assert.Error(t, err)
+}
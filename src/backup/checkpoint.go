@@ -0,0 +1,225 @@
+package backup
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// levelDBAttempts is how many times a leveldb is copied again when it was
+// compacted during the copy
+const levelDBAttempts = 5
+
+// Checkpoint is a copy of the databases of a node, consistent as of the
+// last block committed before it was taken.
+type Checkpoint struct {
+	Dir     string    `json:"dir"`
+	Height  int64     `json:"height"`
+	Created time.Time `json:"created"`
+}
+
+// Checkpointer copies the directories of a node home, the leveldbs in them
+// included, into checkpoints.
+type Checkpointer struct {
+	Root string   // home of the node
+	Dirs []string // under Root, copied recursively
+	// Skip tells the files under Root not to copy, nil to copy them all
+	Skip func(rel string) bool
+	// Pause runs its func with the blocks of the node paused, nil when the
+	// node is stopped
+	Pause func(func() error) error
+	// Height returns the height of the last block committed, called paused
+	Height func() int64
+}
+
+// Checkpoint copies the directories into dst, which must not exist. The
+// files of the leveldb tables, which leveldb never changes, are hard
+// linked when dst is on the same file system as the home, so a checkpoint
+// takes little time and space.
+func (c *Checkpointer) Checkpoint(dst string) (*Checkpoint, error) {
+	if _, err := os.Stat(dst); !os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s already exists", dst)
+	}
+	cp := &Checkpoint{Dir: dst}
+	run := func() error {
+		if c.Height != nil {
+			cp.Height = c.Height()
+		}
+		cp.Created = time.Now().UTC()
+		for _, dir := range c.Dirs {
+			if err := c.copyDir(dir, dst); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	var err error
+	if c.Pause != nil {
+		err = c.Pause(run)
+	} else {
+		err = run()
+	}
+	if err != nil {
+		os.RemoveAll(dst) // nolint: errcheck
+		return nil, err
+	}
+	return cp, nil
+}
+
+func (c *Checkpointer) copyDir(rel, dst string) error {
+	return filepath.Walk(filepath.Join(c.Root, rel), func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && file == filepath.Join(c.Root, rel) {
+				return nil
+			}
+			return err
+		}
+		frel, err := filepath.Rel(c.Root, file)
+		if err != nil {
+			return err
+		}
+		if c.Skip != nil && c.Skip(filepath.ToSlash(frel)) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
+			if isLevelDB(file) {
+				if err := copyLevelDB(file, filepath.Join(dst, frel)); err != nil {
+					return fmt.Errorf("%s: %v", frel, err)
+				}
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(filepath.Join(dst, frel), 0755)
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		return copyFile(file, filepath.Join(dst, frel), info.Size())
+	})
+}
+
+func isLevelDB(dir string) bool {
+	_, err := os.Stat(filepath.Join(dir, "CURRENT"))
+	return err == nil
+}
+
+// levelDBFiles lists the files of the leveldb in dir that make its
+// content, with their sizes
+func levelDBFiles(dir string) (map[string]int64, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]int64)
+	for _, info := range infos {
+		switch name := info.Name(); {
+		case info.IsDir(), name == "LOCK", name == "LOG", name == "LOG.old":
+		default:
+			files[name] = info.Size()
+		}
+	}
+	return files, nil
+}
+
+// copyLevelDB copies the leveldb in src into dst. The manifest, telling the
+// tables the db is made of, is only valid along with the tables listed at
+// the same time: the copy is done again unless no flush or compaction
+// changed the files meanwhile. The journals may grow during the copy, the
+// records copied halfway are dropped opening the copy.
+func copyLevelDB(src, dst string) error {
+	for attempt := 0; attempt < levelDBAttempts; attempt++ {
+		before, err := levelDBFiles(src)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dst, 0755); err != nil {
+			return err
+		}
+		for name, size := range before {
+			if isTable(name) {
+				err = linkFile(filepath.Join(src, name), filepath.Join(dst, name), size)
+			} else {
+				err = copyFile(filepath.Join(src, name), filepath.Join(dst, name), size)
+			}
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+		}
+		after, err := levelDBFiles(src)
+		if err != nil {
+			return err
+		}
+		if sameVersion(before, after) {
+			return nil
+		}
+		if err := os.RemoveAll(dst); err != nil {
+			return err
+		}
+	}
+	return fmt.Errorf("compacted during each of %d copies", levelDBAttempts)
+}
+
+func isTable(name string) bool {
+	return strings.HasSuffix(name, ".ldb") || strings.HasSuffix(name, ".sst")
+}
+
+// sameVersion tells if the files of a leveldb before and after a copy hold
+// the same version of it: the same files, and the manifest and CURRENT
+// unchanged
+func sameVersion(before, after map[string]int64) bool {
+	if len(before) != len(after) {
+		return false
+	}
+	for name := range before {
+		if _, ok := after[name]; !ok {
+			return false
+		}
+	}
+	versions := func(files map[string]int64) map[string]int64 {
+		v := make(map[string]int64)
+		for name, size := range files {
+			if name == "CURRENT" || strings.HasPrefix(name, "MANIFEST-") {
+				v[name] = size
+			}
+		}
+		return v
+	}
+	return reflect.DeepEqual(versions(before), versions(after))
+}
+
+// linkFile hard links src as dst, copying it if it can't
+func linkFile(src, dst string, size int64) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+	return copyFile(src, dst, size)
+}
+
+// copyFile copies the size first bytes of src into dst, the ones there
+// when the copy was decided
+func copyFile(src, dst string, size int64) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close() // nolint: errcheck
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = io.CopyN(out, in, size)
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
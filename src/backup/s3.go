@@ -0,0 +1,311 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// unsignedPayload stands for the hash of the bodies, which S3 then leaves
+// unchecked: the restores check the hashes of the files themselves
+const unsignedPayload = "UNSIGNED-PAYLOAD"
+
+// S3Config is where an S3 compatible API is and who calls it.
+type S3Config struct {
+	Endpoint     string // scheme://host, empty for AWS
+	Region       string
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+}
+
+// S3ConfigFromEnv reads the config of AWS from the environment variables
+// of the AWS tools: AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+// AWS_SESSION_TOKEN, AWS_REGION and AWS_ENDPOINT_URL for other S3
+// compatible stores.
+func S3ConfigFromEnv() S3Config {
+	region := os.Getenv("AWS_REGION")
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if region == "" {
+		region = "us-east-1"
+	}
+	return S3Config{
+		Endpoint:     strings.TrimRight(os.Getenv("AWS_ENDPOINT_URL"), "/"),
+		Region:       region,
+		AccessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		SecretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		SessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+}
+
+// GCSConfigFromEnv reads the config of GCS, called with the HMAC key of
+// GCS_HMAC_ACCESS_ID and GCS_HMAC_SECRET.
+func GCSConfigFromEnv() S3Config {
+	return S3Config{
+		Endpoint:  "https://storage.googleapis.com",
+		Region:    "auto",
+		AccessKey: os.Getenv("GCS_HMAC_ACCESS_ID"),
+		SecretKey: os.Getenv("GCS_HMAC_SECRET"),
+	}
+}
+
+// s3Store keeps the objects in a bucket, under a prefix.
+type s3Store struct {
+	conf   S3Config
+	bucket string
+	prefix string
+	http   http.Client
+}
+
+// NewS3Store creates a store keeping the objects in bucket under prefix,
+// through the S3 API of conf.
+func NewS3Store(conf S3Config, bucket, prefix string) (Store, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("no bucket")
+	}
+	if conf.AccessKey == "" || conf.SecretKey == "" {
+		return nil, fmt.Errorf("no credentials for the bucket %s", bucket)
+	}
+	return &s3Store{conf: conf, bucket: bucket, prefix: prefix}, nil
+}
+
+// url returns the url of the object of key, the bucket itself when key
+// is empty. AWS has the buckets as hosts, other stores in the path.
+func (s *s3Store) url(key string, query url.Values) *url.URL {
+	u := &url.URL{Scheme: "https", Host: s.bucket + ".s3." + s.conf.Region + ".amazonaws.com", Path: "/"}
+	if s.conf.Endpoint != "" {
+		endpoint, err := url.Parse(s.conf.Endpoint)
+		if err == nil {
+			u.Scheme, u.Host, u.Path = endpoint.Scheme, endpoint.Host, "/"+s.bucket+"/"
+		}
+	}
+	if key != "" {
+		if s.prefix != "" {
+			key = s.prefix + "/" + key
+		}
+		u.Path += key
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	u.RawQuery = canonicalQuery(query)
+	return u
+}
+
+func (s *s3Store) do(method, key string, query url.Values, body io.Reader, size int64) (*http.Response, error) {
+	req, err := http.NewRequest(method, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL = s.url(key, query)
+	req.Host = req.URL.Host
+	if body != nil {
+		req.Body = ioutil.NopCloser(body)
+		if size == 0 {
+			req.Body = http.NoBody
+		}
+		req.ContentLength = size
+	}
+	signV4(req, s.conf, unsignedPayload, time.Now())
+	return s.http.Do(req)
+}
+
+// check returns the error of res unless it succeeded, closing it then
+func check(res *http.Response, what string) error {
+	if res.StatusCode/100 == 2 {
+		return nil
+	}
+	defer res.Body.Close() // nolint: errcheck
+	var e struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	body, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1<<16))
+	if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+		return fmt.Errorf("%s: %s: %s", what, e.Code, e.Message)
+	}
+	return fmt.Errorf("%s: %s", what, res.Status)
+}
+
+func (s *s3Store) Put(key string, r io.Reader, size int64) error {
+	res, err := s.do(http.MethodPut, key, nil, r, size)
+	if err != nil {
+		return err
+	}
+	if err := check(res, "put "+key); err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func (s *s3Store) Get(key string) (io.ReadCloser, error) {
+	res, err := s.do(http.MethodGet, key, nil, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close() // nolint: errcheck
+		return nil, ErrNotFound
+	}
+	if err := check(res, "get "+key); err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+func (s *s3Store) Has(key string) (bool, error) {
+	res, err := s.do(http.MethodHead, key, nil, nil, 0)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close() // nolint: errcheck
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	return res.StatusCode/100 == 2, check(res, "head "+key)
+}
+
+// List pages through the keys with the first version of ListObjects, the
+// one GCS has too.
+func (s *s3Store) List(prefix string) ([]string, error) {
+	full := prefix
+	if s.prefix != "" {
+		full = s.prefix + "/" + prefix
+	}
+	var keys []string
+	marker := ""
+	for {
+		query := url.Values{"prefix": {full}}
+		if marker != "" {
+			query.Set("marker", marker)
+		}
+		res, err := s.do(http.MethodGet, "", query, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		if err := check(res, "list "+prefix); err != nil {
+			return nil, err
+		}
+		var page struct {
+			IsTruncated bool `xml:"IsTruncated"`
+			Contents    []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+		}
+		err = xml.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close() // nolint: errcheck
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range page.Contents {
+			key := c.Key
+			if s.prefix != "" {
+				key = strings.TrimPrefix(key, s.prefix+"/")
+			}
+			keys = append(keys, key)
+		}
+		if !page.IsTruncated || len(page.Contents) == 0 {
+			break
+		}
+		marker = page.Contents[len(page.Contents)-1].Key
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// signV4 signs req for the S3 API of conf with the signature version 4 of
+// AWS, covering the host and all the headers of req. payload is the hex
+// sha256 of the body or unsignedPayload.
+func signV4(req *http.Request, conf S3Config, payload string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+	if conf.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", conf.SessionToken)
+	}
+
+	values := map[string]string{"host": req.URL.Host}
+	for name, v := range req.Header {
+		values[strings.ToLower(name)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var headers bytes.Buffer
+	for _, name := range names {
+		headers.WriteString(name + ":" + values[name] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{
+		req.Method, path, req.URL.RawQuery, headers.String(), signed, payload,
+	}, "\n")
+	scope := date + "/" + conf.Region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+conf.SecretKey), date)
+	for _, part := range []string{conf.Region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+conf.AccessKey+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data)) // nolint: errcheck
+	return mac.Sum(nil)
+}
+
+// canonicalQuery encodes query sorted by key, as signV4 signs it
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		for _, v := range query[k] {
+			parts = append(parts, uriEncode(k, true)+"="+uriEncode(v, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode escapes all but the unreserved characters of RFC 3986, and the
+// slashes unless slash
+func uriEncode(s string, slash bool) string {
+	var b bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !slash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
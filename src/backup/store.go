@@ -0,0 +1,130 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ErrNotFound is returned getting an object a store doesn't have.
+var ErrNotFound = errors.New("no such object")
+
+// Store keeps the objects of backups under keys made of slash separated
+// names.
+type Store interface {
+	// Put stores the size bytes of r under key, replacing the object there
+	Put(key string, r io.Reader, size int64) error
+	// Get reads the object of key, ErrNotFound if there is none
+	Get(key string) (io.ReadCloser, error)
+	// Has tells if there is an object under key
+	Has(key string) (bool, error)
+	// List returns the keys starting with prefix, sorted
+	List(prefix string) ([]string, error)
+}
+
+// Open returns the store of target:
+//
+//	s3://bucket/prefix  an S3 bucket, see NewS3Store
+//	gs://bucket/prefix  a GCS bucket through its S3 compatible API
+//	file:///dir         a local directory
+func Open(target string) (Store, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	prefix := strings.Trim(u.Path, "/")
+	switch u.Scheme {
+	case "s3":
+		return NewS3Store(S3ConfigFromEnv(), u.Host, prefix)
+	case "gs":
+		return NewS3Store(GCSConfigFromEnv(), u.Host, prefix)
+	case "file":
+		return NewFileStore(u.Path), nil
+	}
+	return nil, fmt.Errorf("unknown target %s, want s3://, gs:// or file://", target)
+}
+
+// fileStore keeps the objects in files of a directory.
+type fileStore struct {
+	dir string
+}
+
+// NewFileStore creates a store keeping the objects under dir.
+func NewFileStore(dir string) Store {
+	return fileStore{dir}
+}
+
+func (s fileStore) path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
+
+func (s fileStore) Put(key string, r io.Reader, size int64) error {
+	file := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return err
+	}
+	// written aside then renamed, so a key never holds half an object
+	tmp, err := ioutil.TempFile(filepath.Dir(file), ".put")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // nolint: errcheck
+	n, err := io.Copy(tmp, r)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("%s: %d bytes instead of %d", key, n, size)
+	}
+	return os.Rename(tmp.Name(), file)
+}
+
+func (s fileStore) Get(key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.path(key))
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s fileStore) Has(key string) (bool, error) {
+	_, err := os.Stat(s.path(key))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s fileStore) List(prefix string) ([]string, error) {
+	var keys []string
+	err := filepath.Walk(s.dir, func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && file == s.dir {
+				return nil
+			}
+			return err
+		}
+		if info.IsDir() || strings.HasPrefix(info.Name(), ".put") {
+			return nil
+		}
+		rel, err := filepath.Rel(s.dir, file)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(rel); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	sort.Strings(keys)
+	return keys, err
+}
@@ -0,0 +1,213 @@
+// Package banlist keeps the peers this node refuses to talk to, by node ID
+// or by IP. Bans are kept in a file of the node so they survive restarts,
+// and the tendermint switch checks every peer against them, see Attach.
+// Peers are banned over the admin RPC, see PrivateAdminAPI.
+package banlist
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tendermint/tendermint/p2p"
+	cmn "github.com/tendermint/tmlibs/common"
+)
+
+// File is the file, in the data directory of the node, the bans are kept in.
+const File = "banlist.json"
+
+// Entry is a banned node ID or IP.
+type Entry struct {
+	Target string    `json:"target"` // node ID or IP
+	Reason string    `json:"reason"`
+	Until  time.Time `json:"until,omitempty"` // zero for good
+}
+
+func (e Entry) expired(now time.Time) bool {
+	return !e.Until.IsZero() && !now.Before(e.Until)
+}
+
+// BanList is the ban list of a node. It is safe for concurrent use.
+type BanList struct {
+	mtx     sync.Mutex
+	file    string
+	entries map[string]Entry
+	sw      *p2p.Switch
+}
+
+// Load reads the ban list kept in file, an empty one if file is missing.
+func Load(file string) (*BanList, error) {
+	l := &BanList{
+		file:    file,
+		entries: make(map[string]Entry),
+	}
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, fmt.Errorf("reading %s: %v", file, err)
+	}
+	for _, e := range entries {
+		l.entries[e.Target] = e
+	}
+	return l, nil
+}
+
+// Attach makes sw refuse the banned peers, it must be called before sw
+// starts. Peers banned later are disconnected right away.
+func (l *BanList) Attach(sw *p2p.Switch) {
+	l.mtx.Lock()
+	l.sw = sw
+	l.mtx.Unlock()
+
+	sw.SetIDFilter(func(id p2p.ID) error {
+		if e, ok := l.banned(string(id)); ok {
+			return fmt.Errorf("peer %s is banned: %s", id, e.Reason)
+		}
+		return nil
+	})
+	sw.SetAddrFilter(func(addr net.Addr) error {
+		host, _, err := net.SplitHostPort(addr.String())
+		if err != nil {
+			return nil
+		}
+		if e, ok := l.banned(host); ok {
+			return fmt.Errorf("ip %s is banned: %s", host, e.Reason)
+		}
+		return nil
+	})
+}
+
+// Ban bans target, a node ID or an IP, for d, for good if d is 0.
+func (l *BanList) Ban(target string, d time.Duration, reason string) (Entry, error) {
+	target, err := parseTarget(target)
+	if err != nil {
+		return Entry{}, err
+	}
+	e := Entry{Target: target, Reason: reason}
+	if d > 0 {
+		e.Until = time.Now().Add(d)
+	}
+
+	l.mtx.Lock()
+	l.entries[target] = e
+	err = l.save()
+	sw := l.sw
+	l.mtx.Unlock()
+
+	if sw != nil {
+		disconnect(sw, target, reason)
+	}
+	return e, err
+}
+
+// Unban lifts the ban of target, false if it wasn't banned.
+func (l *BanList) Unban(target string) (bool, error) {
+	target, err := parseTarget(target)
+	if err != nil {
+		return false, err
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if _, ok := l.entries[target]; !ok {
+		return false, nil
+	}
+	delete(l.entries, target)
+	return true, l.save()
+}
+
+// List returns the bans in effect, sorted by target.
+func (l *BanList) List() []Entry {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	now := time.Now()
+	entries := make([]Entry, 0, len(l.entries))
+	for _, e := range l.entries {
+		if !e.expired(now) {
+			entries = append(entries, e)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Target < entries[j].Target })
+	return entries
+}
+
+// Banned returns the ban of target, a node ID or an IP, false if it isn't
+// banned.
+func (l *BanList) Banned(target string) (Entry, bool) {
+	target, err := parseTarget(target)
+	if err != nil {
+		return Entry{}, false
+	}
+	return l.banned(target)
+}
+
+func (l *BanList) banned(target string) (Entry, bool) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	e, ok := l.entries[strings.ToLower(target)]
+	if !ok || e.expired(time.Now()) {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// save writes the bans in effect to the file, the lock must be held.
+func (l *BanList) save() error {
+	now := time.Now()
+	entries := make([]Entry, 0, len(l.entries))
+	for target, e := range l.entries {
+		if e.expired(now) {
+			delete(l.entries, target)
+			continue
+		}
+		entries = append(entries, e)
+	}
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return cmn.WriteFileAtomic(l.file, b, 0600)
+}
+
+// disconnect stops the peers of sw matching target
+func disconnect(sw *p2p.Switch, target, reason string) {
+	for _, peer := range sw.Peers().List() {
+		if strings.ToLower(string(peer.ID())) == target || peerIP(peer) == target {
+			sw.StopPeerForError(peer, fmt.Errorf("banned: %s", reason))
+		}
+	}
+}
+
+func peerIP(peer p2p.Peer) string {
+	addr := peer.NodeInfo().NetAddress()
+	if addr == nil || addr.IP == nil {
+		return ""
+	}
+	return addr.IP.String()
+}
+
+// parseTarget normalizes a node ID or an IP
+func parseTarget(target string) (string, error) {
+	target = strings.ToLower(strings.TrimSpace(target))
+	if ip := net.ParseIP(target); ip != nil {
+		return ip.String(), nil
+	}
+	if b, err := hex.DecodeString(target); err == nil && len(b) == 20 {
+		return target, nil
+	}
+	return "", fmt.Errorf("%q is neither a node id nor an ip", target)
+}
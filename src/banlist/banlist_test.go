@@ -0,0 +1,42 @@
+package banlist
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const nodeID = "0123456789abcdef0123456789abcdef01234567"
+
+func TestBansPersist(t *testing.T) {
+	dir, err := ioutil.TempDir("", "banlist")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, File)
+
+	l, err := Load(file)
+	require.Nil(t, err)
+	_, err = l.Ban("not a peer", 0, "")
+	assert.NotNil(t, err)
+	_, err = l.Ban(nodeID, 0, "spam")
+	assert.Nil(t, err)
+	_, err = l.Ban("10.0.0.1", time.Hour, "spam")
+	assert.Nil(t, err)
+
+	l, err = Load(file)
+	require.Nil(t, err)
+	assert.Len(t, l.List(), 2)
+	_, ok := l.Banned("0123456789ABCDEF0123456789ABCDEF01234567")
+	assert.True(t, ok)
+
+	ok, err = l.Unban("10.0.0.1")
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	_, ok = l.Banned("10.0.0.1")
+	assert.False(t, ok)
+}
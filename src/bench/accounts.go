@@ -0,0 +1,54 @@
+package bench
+
+import (
+	"crypto/ecdsa"
+	"encoding/binary"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Accounts derives the keys of bench accounts from their index, so a load
+// of many senders needs no keystore: deriving a key is a hash and a scalar
+// multiplication, where a keystore account costs a scrypt run. The keys are
+// public to anyone knowing the seed, never fund them outside test chains.
+type Accounts struct {
+	seed []byte
+}
+
+// NewAccounts creates the accounts of seed, the same seed always gives the
+// same accounts.
+func NewAccounts(seed string) *Accounts {
+	return &Accounts{seed: []byte(seed)}
+}
+
+// Key returns the key of the i-th account: keccak256(seed, i), hashed again
+// in the unlikely case it is no valid key.
+func (a *Accounts) Key(i int) *ecdsa.PrivateKey {
+	var index [8]byte
+	binary.BigEndian.PutUint64(index[:], uint64(i))
+	d := crypto.Keccak256(a.seed, index[:])
+	for {
+		if key, err := crypto.ToECDSA(d); err == nil {
+			return key
+		}
+		d = crypto.Keccak256(d)
+	}
+}
+
+// Address returns the address of the i-th account.
+func (a *Accounts) Address(i int) common.Address {
+	return crypto.PubkeyToAddress(a.Key(i).PublicKey)
+}
+
+// Alloc returns the genesis allocation funding the first n accounts with
+// balance wei each.
+func (a *Accounts) Alloc(n int, balance *big.Int) core.GenesisAlloc {
+	alloc := make(core.GenesisAlloc, n)
+	for i := 0; i < n; i++ {
+		alloc[a.Address(i)] = core.GenesisAccount{Balance: new(big.Int).Set(balance)}
+	}
+	return alloc
+}
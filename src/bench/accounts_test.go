@@ -0,0 +1,20 @@
+package bench
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccounts(t *testing.T) {
+	a := NewAccounts("soak")
+	assert.Equal(t, crypto.FromECDSA(a.Key(7)), crypto.FromECDSA(NewAccounts("soak").Key(7)))
+	assert.NotEqual(t, a.Address(7), a.Address(8))
+	assert.NotEqual(t, a.Address(7), NewAccounts("other").Address(7))
+
+	alloc := a.Alloc(3, big.NewInt(100))
+	assert.Len(t, alloc, 3)
+	assert.Equal(t, big.NewInt(100), alloc[a.Address(2)].Balance)
+}
@@ -0,0 +1,128 @@
+package bench
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TxKind is the kind of tx a workload emits.
+type TxKind int
+
+const (
+	Transfer TxKind = iota
+	Deploy
+	Deposit
+	Withdraw
+)
+
+var kindNames = map[TxKind]string{
+	Transfer: "transfer",
+	Deploy:   "deploy",
+	Deposit:  "deposit",
+	Withdraw: "withdraw",
+}
+
+func (k TxKind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("TxKind(%d)", int(k))
+}
+
+// Profile weighs the tx kinds of a workload. Deploy, Deposit and Withdraw
+// drive the bundled CharityBank contract.
+type Profile struct {
+	Name    string
+	Weights map[TxKind]int
+}
+
+// Profiles are the built-in workloads selectable by name.
+var Profiles = map[string]Profile{
+	"transfer": {Name: "transfer", Weights: map[TxKind]int{Transfer: 1}},
+	"contract": {Name: "contract", Weights: map[TxKind]int{Deploy: 1, Deposit: 60, Withdraw: 39}},
+	"mixed":    {Name: "mixed", Weights: map[TxKind]int{Transfer: 50, Deploy: 2, Deposit: 24, Withdraw: 24}},
+}
+
+// ParseProfile looks up a built-in profile and applies the optional ratio
+// overrides, given as "deposit=3,withdraw=1". A kind set to 0 is dropped.
+func ParseProfile(name, ratios string) (Profile, error) {
+	base, ok := Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown workload profile %q", name)
+	}
+
+	p := Profile{Name: base.Name, Weights: map[TxKind]int{}}
+	for k, w := range base.Weights {
+		p.Weights[k] = w
+	}
+
+	for _, item := range strings.Split(ratios, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		parts := strings.SplitN(item, "=", 2)
+		if len(parts) != 2 {
+			return Profile{}, fmt.Errorf("invalid ratio %q, expect kind=weight", item)
+		}
+		kind, ok := parseKind(strings.TrimSpace(parts[0]))
+		if !ok {
+			return Profile{}, fmt.Errorf("unknown tx kind %q", parts[0])
+		}
+		w, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+		if err != nil || w < 0 {
+			return Profile{}, fmt.Errorf("invalid weight %q for %s", parts[1], kind)
+		}
+		p.Weights[kind] = w
+	}
+
+	if p.total() == 0 {
+		return Profile{}, fmt.Errorf("workload profile %q has no tx kinds left", name)
+	}
+	return p, nil
+}
+
+func parseKind(name string) (TxKind, bool) {
+	for k, n := range kindNames {
+		if n == name {
+			return k, true
+		}
+	}
+	return 0, false
+}
+
+func (p Profile) total() int {
+	total := 0
+	for _, w := range p.Weights {
+		total += w
+	}
+	return total
+}
+
+// Kind returns the kind of the i-th tx. Kinds are handed out round robin in
+// proportion to their weights, so every window of total() txs follows the
+// ratios exactly.
+func (p Profile) Kind(i int) TxKind {
+	kinds := make([]int, 0, len(p.Weights))
+	for k := range p.Weights {
+		kinds = append(kinds, int(k))
+	}
+	sort.Ints(kinds)
+
+	slot := i % p.total()
+	for _, k := range kinds {
+		w := p.Weights[TxKind(k)]
+		if slot < w {
+			return TxKind(k)
+		}
+		slot -= w
+	}
+	return Transfer
+}
+
+// NeedsContract reports whether the kind calls a deployed contract.
+func (k TxKind) NeedsContract() bool {
+	return k == Deposit || k == Withdraw
+}
@@ -0,0 +1,41 @@
+package bench
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfileKindFollowsRatios(t *testing.T) {
+	p, err := ParseProfile("contract", "deploy=1,deposit=3,withdraw=1")
+	require.Nil(t, err)
+
+	counts := map[TxKind]int{}
+	for i := 0; i < 50; i++ {
+		counts[p.Kind(i)]++
+	}
+	assert.Equal(t, 10, counts[Deploy])
+	assert.Equal(t, 30, counts[Deposit])
+	assert.Equal(t, 10, counts[Withdraw])
+	assert.Equal(t, 0, counts[Transfer])
+}
+
+func TestParseProfileErrors(t *testing.T) {
+	_, err := ParseProfile("unknown", "")
+	assert.NotNil(t, err)
+	_, err = ParseProfile("mixed", "burn=1")
+	assert.NotNil(t, err)
+	_, err = ParseProfile("mixed", "deposit")
+	assert.NotNil(t, err)
+	_, err = ParseProfile("transfer", "transfer=0")
+	assert.NotNil(t, err)
+
+	p, err := ParseProfile("transfer", "")
+	require.Nil(t, err)
+	assert.Equal(t, Transfer, p.Kind(7))
+	// overrides must not leak into the built-in table
+	_, err = ParseProfile("transfer", "deposit=5")
+	require.Nil(t, err)
+	assert.Equal(t, 1, len(Profiles["transfer"].Weights))
+}
@@ -0,0 +1,280 @@
+package bench
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Result is the machine-readable summary of one benchmark run.
+type Result struct {
+	Name         string    `json:"name"`
+	StartedAt    time.Time `json:"started_at"`
+	DurationMs   float64   `json:"duration_ms"`
+	Submitted    int       `json:"submitted"`
+	Included     int       `json:"included"`
+	Rejected     int       `json:"rejected"`
+	TPS          float64   `json:"tps"`
+	LatencyP50Ms float64   `json:"latency_p50_ms"`
+	LatencyP95Ms float64   `json:"latency_p95_ms"`
+	LatencyP99Ms float64   `json:"latency_p99_ms"`
+	RejectRate   float64   `json:"checktx_reject_rate"`
+	Commits      int       `json:"commits"`
+	CommitAvgMs  float64   `json:"commit_avg_ms"`
+	CommitMaxMs  float64   `json:"commit_max_ms"`
+}
+
+var csvHeader = []string{
+	"name", "started_at", "duration_ms", "submitted", "included", "rejected",
+	"tps", "latency_p50_ms", "latency_p95_ms", "latency_p99_ms",
+	"checktx_reject_rate", "commits", "commit_avg_ms", "commit_max_ms",
+}
+
+func (r *Result) csvRecord() []string {
+	f := func(v float64) string { return strconv.FormatFloat(v, 'f', 3, 64) }
+	return []string{
+		r.Name, r.StartedAt.UTC().Format(time.RFC3339), f(r.DurationMs),
+		strconv.Itoa(r.Submitted), strconv.Itoa(r.Included), strconv.Itoa(r.Rejected),
+		f(r.TPS), f(r.LatencyP50Ms), f(r.LatencyP95Ms), f(r.LatencyP99Ms),
+		f(r.RejectRate), strconv.Itoa(r.Commits), f(r.CommitAvgMs), f(r.CommitMaxMs),
+	}
+}
+
+// Recorder accumulates the raw samples of one benchmark run.
+// It is safe for concurrent use by the goroutines that submit txs.
+type Recorder struct {
+	mtx sync.Mutex
+
+	name      string
+	start     time.Time
+	end       time.Time
+	submitted int
+	rejected  int
+	latencies []time.Duration
+	commits   []time.Duration
+}
+
+// NewRecorder creates a recorder and starts its clock.
+func NewRecorder(name string) *Recorder {
+	return &Recorder{
+		name:  name,
+		start: time.Now(),
+	}
+}
+
+// Submitted records n txs handed to the node.
+func (r *Recorder) Submitted(n int) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.submitted += n
+}
+
+// Rejected records n txs refused by CheckTx.
+func (r *Recorder) Rejected(n int) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.rejected += n
+}
+
+// Included records the time between submitting a tx and seeing its receipt.
+func (r *Recorder) Included(latency time.Duration) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.latencies = append(r.latencies, latency)
+}
+
+// Committed records the time spent on a single block commit.
+func (r *Recorder) Committed(d time.Duration) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.commits = append(r.commits, d)
+}
+
+// Stop freezes the run duration. Result calls it implicitly if needed.
+func (r *Recorder) Stop() {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.end.IsZero() {
+		r.end = time.Now()
+	}
+}
+
+// Result summarises the samples collected so far.
+func (r *Recorder) Result() *Result {
+	r.Stop()
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	elapsed := r.end.Sub(r.start)
+	latencies := append([]time.Duration(nil), r.latencies...)
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+	res := &Result{
+		Name:         r.name,
+		StartedAt:    r.start,
+		DurationMs:   toMs(elapsed),
+		Submitted:    r.submitted,
+		Included:     len(latencies),
+		Rejected:     r.rejected,
+		LatencyP50Ms: toMs(Percentile(latencies, 50)),
+		LatencyP95Ms: toMs(Percentile(latencies, 95)),
+		LatencyP99Ms: toMs(Percentile(latencies, 99)),
+		Commits:      len(r.commits),
+	}
+	if elapsed > 0 {
+		res.TPS = float64(len(latencies)) / elapsed.Seconds()
+	}
+	if r.submitted > 0 {
+		res.RejectRate = float64(r.rejected) / float64(r.submitted)
+	}
+	if len(r.commits) > 0 {
+		var total, max time.Duration
+		for _, d := range r.commits {
+			total += d
+			if d > max {
+				max = d
+			}
+		}
+		res.CommitAvgMs = toMs(total / time.Duration(len(r.commits)))
+		res.CommitMaxMs = toMs(max)
+	}
+	return res
+}
+
+// Percentile returns the p-th percentile (nearest rank) of sorted samples.
+func Percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(p/100*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+func toMs(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+//----------------------------------------------------------------------
+// Output
+
+// WriteJSON writes the results as an indented JSON array.
+func WriteJSON(w io.Writer, results []*Result) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// WriteCSV writes the results as CSV with a header row.
+func WriteCSV(w io.Writer, results []*Result) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		if err := cw.Write(r.csvRecord()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteFile writes the results to path, picking CSV or JSON from the
+// file extension.
+func WriteFile(path string, results []*Result) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		err = WriteCSV(f, results)
+	case ".json", "":
+		err = WriteJSON(f, results)
+	default:
+		return fmt.Errorf("unsupported report format %q", ext)
+	}
+	if err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+//----------------------------------------------------------------------
+// Session
+
+// Session collects the results of consecutive benchmark rounds and keeps
+// the report file up to date after every round. An empty path disables
+// the file output.
+type Session struct {
+	mtx     sync.Mutex
+	path    string
+	current *Recorder
+	results []*Result
+}
+
+// NewSession creates a session reporting to path.
+func NewSession(path string) *Session {
+	return &Session{path: path}
+}
+
+// Begin starts a new round. A round left open is discarded.
+func (s *Session) Begin(name string) *Recorder {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.current = NewRecorder(name)
+	return s.current
+}
+
+// Committed forwards a commit duration to the current round, if any.
+func (s *Session) Committed(d time.Duration) {
+	s.mtx.Lock()
+	rec := s.current
+	s.mtx.Unlock()
+	if rec != nil {
+		rec.Committed(d)
+	}
+}
+
+// End closes the current round and rewrites the report file.
+func (s *Session) End() (*Result, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.current == nil {
+		return nil, fmt.Errorf("no benchmark round in progress")
+	}
+	res := s.current.Result()
+	s.current = nil
+	s.results = append(s.results, res)
+	if s.path == "" {
+		return res, nil
+	}
+	return res, WriteFile(s.path, s.results)
+}
+
+// Results returns the finished rounds.
+func (s *Session) Results() []*Result {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]*Result(nil), s.results...)
+}
@@ -0,0 +1,99 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentile(t *testing.T) {
+	samples := []time.Duration{}
+	for i := 1; i <= 100; i++ {
+		samples = append(samples, time.Duration(i)*time.Millisecond)
+	}
+
+	assert.Equal(t, time.Duration(0), Percentile(nil, 50))
+	assert.Equal(t, 50*time.Millisecond, Percentile(samples, 50))
+	assert.Equal(t, 95*time.Millisecond, Percentile(samples, 95))
+	assert.Equal(t, 99*time.Millisecond, Percentile(samples, 99))
+	assert.Equal(t, 100*time.Millisecond, Percentile(samples, 100))
+}
+
+func TestRecorderResult(t *testing.T) {
+	rec := NewRecorder("transfer")
+	rec.Submitted(4)
+	rec.Rejected(1)
+	rec.Included(10 * time.Millisecond)
+	rec.Included(30 * time.Millisecond)
+	rec.Included(20 * time.Millisecond)
+	rec.Committed(100 * time.Millisecond)
+	rec.Committed(300 * time.Millisecond)
+
+	res := rec.Result()
+	assert.Equal(t, "transfer", res.Name)
+	assert.Equal(t, 3, res.Included)
+	assert.Equal(t, 0.25, res.RejectRate)
+	assert.Equal(t, 20.0, res.LatencyP50Ms)
+	assert.Equal(t, 30.0, res.LatencyP99Ms)
+	assert.Equal(t, 200.0, res.CommitAvgMs)
+	assert.Equal(t, 300.0, res.CommitMaxMs)
+}
+
+func TestWriteReports(t *testing.T) {
+	rec := NewRecorder("transfer")
+	rec.Submitted(1)
+	rec.Included(time.Millisecond)
+	results := []*Result{rec.Result()}
+
+	buf := new(bytes.Buffer)
+	require.Nil(t, WriteJSON(buf, results))
+	decoded := []*Result{}
+	require.Nil(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, 1, len(decoded))
+	assert.Equal(t, 1, decoded[0].Included)
+
+	buf.Reset()
+	require.Nil(t, WriteCSV(buf, results))
+	records, err := csv.NewReader(buf).ReadAll()
+	require.Nil(t, err)
+	assert.Equal(t, 2, len(records))
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, "transfer", records[1][0])
+}
+
+func TestSessionWritesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bench")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	report := filepath.Join(dir, "report.csv")
+	s := NewSession(report)
+	_, err = s.End()
+	assert.NotNil(t, err)
+
+	for i := 0; i < 2; i++ {
+		rec := s.Begin("round")
+		rec.Submitted(1)
+		rec.Included(time.Millisecond)
+		s.Committed(time.Millisecond)
+		_, err = s.End()
+		require.Nil(t, err)
+	}
+	assert.Equal(t, 2, len(s.Results()))
+	assert.Equal(t, 1, s.Results()[1].Commits)
+
+	f, err := os.Open(report)
+	require.Nil(t, err)
+	defer f.Close()
+	records, err := csv.NewReader(f).ReadAll()
+	require.Nil(t, err)
+	assert.Equal(t, 3, len(records))
+}
@@ -0,0 +1,116 @@
+package bench
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// TxFileVersion is the version of the tx files WriteTxFile writes.
+const TxFileVersion = 1
+
+// TxFile is a file of signed txs to replay later, with what tells which
+// chain they were signed for: a replay checks it before sending the first
+// one, rather than having every tx fail CheckTx.
+type TxFile struct {
+	Version int       `json:"version"`
+	ChainID uint64    `json:"chainId"`
+	Created time.Time `json:"created"`
+	Txs     []FileTx  `json:"txs"`
+}
+
+// FileTx is a tx of a tx file.
+type FileTx struct {
+	Signer common.Address `json:"signer"`
+	Raw    hexutil.Bytes  `json:"raw"` // rlp of the signed tx
+}
+
+// WriteTxFile writes txs, signed for the chain chainID, to file.
+func WriteTxFile(file string, chainID uint64, txs types.Transactions) error {
+	signer := types.NewEIP155Signer(new(big.Int).SetUint64(chainID))
+	f := TxFile{Version: TxFileVersion, ChainID: chainID, Created: time.Now().UTC(), Txs: make([]FileTx, len(txs))}
+	for i, tx := range txs {
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return fmt.Errorf("tx %d: %v", i, err)
+		}
+		raw, err := rlp.EncodeToBytes(tx)
+		if err != nil {
+			return fmt.Errorf("tx %d: %v", i, err)
+		}
+		f.Txs[i] = FileTx{Signer: from, Raw: raw}
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+// ReadTxFile reads the txs of file, failing unless they all are signed, by
+// the signers the file tells, for the chain chainID.
+func ReadTxFile(file string, chainID uint64) (types.Transactions, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	if data = bytes.TrimSpace(data); len(data) > 0 && data[0] == '[' {
+		return nil, fmt.Errorf("%s is a bare list of txs of no known chain, write it again with WriteTxFile", file)
+	}
+	var f TxFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	txs, err := f.Check(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	return txs, nil
+}
+
+// Check returns the txs of f, failing unless they all are signed, by the
+// signers f tells, for the chain chainID.
+func (f *TxFile) Check(chainID uint64) (types.Transactions, error) {
+	switch {
+	case f.Version == 0:
+		return nil, fmt.Errorf("no version")
+	case f.Version > TxFileVersion:
+		return nil, fmt.Errorf("version %d, newer than %d", f.Version, TxFileVersion)
+	case f.ChainID == 0:
+		return nil, fmt.Errorf("no chain id")
+	case f.ChainID != chainID:
+		return nil, fmt.Errorf("txs of the chain %d, not of %d", f.ChainID, chainID)
+	case f.Created.IsZero():
+		return nil, fmt.Errorf("no creation time")
+	}
+
+	id := new(big.Int).SetUint64(chainID)
+	signer := types.NewEIP155Signer(id)
+	txs := make(types.Transactions, len(f.Txs))
+	for i, ftx := range f.Txs {
+		tx := new(types.Transaction)
+		if err := rlp.DecodeBytes(ftx.Raw, tx); err != nil {
+			return nil, fmt.Errorf("tx %d: %v", i, err)
+		}
+		if !tx.Protected() || tx.ChainId().Cmp(id) != 0 {
+			return nil, fmt.Errorf("tx %d isn't signed for the chain %d", i, chainID)
+		}
+		from, err := types.Sender(signer, tx)
+		if err != nil {
+			return nil, fmt.Errorf("tx %d: %v", i, err)
+		}
+		if from != ftx.Signer {
+			return nil, fmt.Errorf("tx %d signed by %s, not %s", i, from.Hex(), ftx.Signer.Hex())
+		}
+		txs[i] = tx
+	}
+	return txs, nil
+}
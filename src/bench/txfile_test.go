@@ -0,0 +1,49 @@
+package bench
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTxFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "txfile")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	file := filepath.Join(dir, "queued-txs.json")
+
+	a := NewAccounts("txfile")
+	signer := types.NewEIP155Signer(big.NewInt(188))
+	var txs types.Transactions
+	for i := 0; i < 3; i++ {
+		tx := types.NewTransaction(uint64(i), a.Address(1), big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+		signed, err := types.SignTx(tx, signer, a.Key(0))
+		require.NoError(t, err)
+		txs = append(txs, signed)
+	}
+	require.NoError(t, WriteTxFile(file, 188, txs))
+
+	read, err := ReadTxFile(file, 188)
+	require.NoError(t, err)
+	require.Len(t, read, 3)
+	for i := range txs {
+		assert.Equal(t, txs[i].Hash(), read[i].Hash())
+	}
+
+	_, err = ReadTxFile(file, 189)
+	assert.Error(t, err)
+
+	f := TxFile{Version: TxFileVersion, ChainID: 188}
+	_, err = f.Check(188)
+	assert.Error(t, err, "no creation time")
+
+	require.NoError(t, ioutil.WriteFile(file, []byte(`[{"nonce": "0x0"}]`), 0644))
+	_, err = ReadTxFile(file, 188)
+	assert.Error(t, err)
+}
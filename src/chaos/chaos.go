@@ -0,0 +1,137 @@
+// Package chaos injects faults into a running node for resilience testing.
+//
+// The hooks are compiled in only with the `chaos` build tag:
+//
+//	go build -tags chaos ./cmd/ultron
+//
+// Without the tag Enabled is false and every hook is a no-op the compiler
+// drops. Faults are switched on at runtime through the admin_chaos* RPC
+// methods, see PrivateAdminAPI.
+package chaos
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Faults is the set of faults currently injected.
+type Faults struct {
+	CommitDelay     time.Duration `json:"commitDelay"`
+	CheckTxFailRate float64       `json:"checkTxFailRate"`
+	GossipDropRate  float64       `json:"gossipDropRate"`
+}
+
+var (
+	mtx    sync.RWMutex
+	faults Faults
+)
+
+// Current returns the faults in effect.
+func Current() Faults {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	return faults
+}
+
+// Set replaces the faults in effect.
+func Set(f Faults) error {
+	if err := checkRate(f.CheckTxFailRate); err != nil {
+		return err
+	}
+	if err := checkRate(f.GossipDropRate); err != nil {
+		return err
+	}
+	if f.CommitDelay < 0 {
+		return fmt.Errorf("negative commit delay %v", f.CommitDelay)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	faults = f
+	return nil
+}
+
+func checkRate(rate float64) error {
+	if rate < 0 || rate > 1 {
+		return fmt.Errorf("rate %v out of [0, 1]", rate)
+	}
+	return nil
+}
+
+//----------------------------------------------------------------------
+// Hooks
+
+// DelayCommit sleeps for the configured commit delay.
+func DelayCommit() {
+	if !Enabled {
+		return
+	}
+	if d := Current().CommitDelay; d > 0 {
+		time.Sleep(d)
+	}
+}
+
+// FailCheckTx reports whether the current CheckTx should be failed.
+func FailCheckTx() bool {
+	if !Enabled {
+		return false
+	}
+	return hit(Current().CheckTxFailRate)
+}
+
+// DropGossip reports whether the tx being gossiped should be dropped.
+func DropGossip() bool {
+	if !Enabled {
+		return false
+	}
+	return hit(Current().GossipDropRate)
+}
+
+func hit(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
+
+//----------------------------------------------------------------------
+// RPC
+
+// PrivateAdminAPI exposes the fault switches in the admin namespace.
+type PrivateAdminAPI struct{}
+
+// NewPrivateAdminAPI creates the chaos admin API.
+func NewPrivateAdminAPI() *PrivateAdminAPI {
+	return &PrivateAdminAPI{}
+}
+
+// ChaosStatus returns the faults in effect.
+func (api *PrivateAdminAPI) ChaosStatus() Faults {
+	return Current()
+}
+
+// ChaosSetCommitDelay delays every Commit by ms milliseconds.
+func (api *PrivateAdminAPI) ChaosSetCommitDelay(ms uint64) (Faults, error) {
+	f := Current()
+	f.CommitDelay = time.Duration(ms) * time.Millisecond
+	return f, Set(f)
+}
+
+// ChaosSetCheckTxFailRate fails the given fraction of CheckTx calls.
+func (api *PrivateAdminAPI) ChaosSetCheckTxFailRate(rate float64) (Faults, error) {
+	f := Current()
+	f.CheckTxFailRate = rate
+	return f, Set(f)
+}
+
+// ChaosSetGossipDropRate drops the given fraction of gossiped txs.
+func (api *PrivateAdminAPI) ChaosSetGossipDropRate(rate float64) (Faults, error) {
+	f := Current()
+	f.GossipDropRate = rate
+	return f, Set(f)
+}
+
+// ChaosReset turns every fault off.
+func (api *PrivateAdminAPI) ChaosReset() Faults {
+	Set(Faults{})
+	return Current()
+}
@@ -0,0 +1,40 @@
+//go:build chaos
+// +build chaos
+
+package chaos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaultSwitches(t *testing.T) {
+	api := NewPrivateAdminAPI()
+	defer api.ChaosReset()
+
+	assert.False(t, FailCheckTx())
+	assert.False(t, DropGossip())
+
+	_, err := api.ChaosSetCheckTxFailRate(1)
+	assert.Nil(t, err)
+	_, err = api.ChaosSetGossipDropRate(1)
+	assert.Nil(t, err)
+	assert.True(t, FailCheckTx())
+	assert.True(t, DropGossip())
+
+	_, err = api.ChaosSetGossipDropRate(1.5)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1.0, api.ChaosStatus().GossipDropRate)
+
+	f, err := api.ChaosSetCommitDelay(20)
+	assert.Nil(t, err)
+	assert.Equal(t, 20*time.Millisecond, f.CommitDelay)
+	start := time.Now()
+	DelayCommit()
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+
+	assert.Equal(t, Faults{}, api.ChaosReset())
+	assert.False(t, FailCheckTx())
+}
@@ -0,0 +1,7 @@
+//go:build !chaos
+// +build !chaos
+
+package chaos
+
+// Enabled is true when the node is built with the chaos tag.
+const Enabled = false
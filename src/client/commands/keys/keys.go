@@ -0,0 +1,293 @@
+// Package keys has the commands moving accounts in and out of the keystore
+// of the node, as geth V3 keyfiles, raw private keys or armored keyfiles.
+package keys
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	txcmd "github.com/dora/ultron/client/commands/txs"
+	"github.com/dora/ultron/commons"
+)
+
+// nolint
+const (
+	FlagFormat = "format"
+	FlagOut    = "out"
+)
+
+// Formats of the keys read and written
+const (
+	FormatAuto  = "auto"
+	FormatGeth  = "geth"  // V3 keyfile json, encrypted
+	FormatHex   = "hex"   // raw private key, not encrypted
+	FormatArmor = "armor" // V3 keyfile json in a base64 armor, for copy and paste
+)
+
+const (
+	armorBegin = "-----BEGIN ULTRON KEYFILE-----"
+	armorEnd   = "-----END ULTRON KEYFILE-----"
+	armorWidth = 64
+)
+
+// nolint
+var (
+	RootCmd = &cobra.Command{
+		Use:   "keys",
+		Short: "Manage the accounts of the keystore",
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	ListCmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the accounts of the keystore and the watch-only ones",
+		Args:  cobra.NoArgs,
+		RunE:  cmdListKeys,
+	}
+
+	ImportCmd = &cobra.Command{
+		Use:   "import <file>",
+		Short: "Import a geth keyfile, a raw private key or an armored keyfile, - reads stdin",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdImportKey,
+	}
+
+	ExportCmd = &cobra.Command{
+		Use:   "export <address>",
+		Short: "Export an account as a geth keyfile, a raw private key or an armored keyfile",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdExportKey,
+	}
+)
+
+func init() {
+	ImportCmd.Flags().String(FlagFormat, FormatAuto, "format of the key (auto|geth|hex|armor)")
+	ExportCmd.Flags().String(FlagFormat, FormatGeth, "format of the key (geth|hex|armor)")
+	ExportCmd.Flags().String(FlagOut, "-", "file to write the key to")
+
+	RootCmd.AddCommand(ListCmd, ImportCmd, ExportCmd)
+}
+
+func cmdListKeys(cmd *cobra.Command, args []string) error {
+	ks, err := keyStore()
+	if err != nil {
+		return err
+	}
+	for _, a := range ks.Accounts() {
+		fmt.Printf("%s %s\n", a.Address.Hex(), a.URL.Path)
+	}
+
+	watched, err := commons.OpenAddressBook().List()
+	if err != nil {
+		return err
+	}
+	for _, e := range watched {
+		fmt.Printf("%s watch-only %s\n", e.Address.Hex(), e.Label)
+	}
+	return nil
+}
+
+func cmdImportKey(cmd *cobra.Command, args []string) error {
+	raw, err := readInput(args[0])
+	if err != nil {
+		return err
+	}
+	format := viper.GetString(FlagFormat)
+	if format == FormatAuto {
+		format = detectFormat(raw)
+	}
+
+	ks, err := keyStore()
+	if err != nil {
+		return err
+	}
+
+	var account accounts.Account
+	switch format {
+	case FormatHex:
+		key, err := crypto.HexToECDSA(strings.TrimPrefix(string(bytes.TrimSpace(raw)), "0x"))
+		if err != nil {
+			return errors.Wrap(err, "reading private key")
+		}
+		passphrase, err := newPassphrase()
+		if err != nil {
+			return err
+		}
+		if account, err = ks.ImportECDSA(key, passphrase); err != nil {
+			return err
+		}
+	case FormatGeth, FormatArmor:
+		keyJSON := raw
+		if format == FormatArmor {
+			if keyJSON, err = dearmor(raw); err != nil {
+				return err
+			}
+		}
+		passphrase, err := txcmd.GetPassword("Please enter passphrase of the keyfile: ")
+		if err != nil {
+			return err
+		}
+		// check the passphrase before asking for the new one
+		if _, err = keystore.DecryptKey(keyJSON, passphrase); err != nil {
+			return err
+		}
+		newPass, err := newPassphrase()
+		if err != nil {
+			return err
+		}
+		if account, err = ks.Import(keyJSON, passphrase, newPass); err != nil {
+			return err
+		}
+	default:
+		return errors.Errorf("unknown key format %s", format)
+	}
+
+	fmt.Printf("Imported %s\n", account.Address.Hex())
+	return nil
+}
+
+func cmdExportKey(cmd *cobra.Command, args []string) error {
+	if !common.IsHexAddress(args[0]) {
+		return errors.Errorf("invalid address %s", args[0])
+	}
+	account := accounts.Account{Address: common.HexToAddress(args[0])}
+	format := viper.GetString(FlagFormat)
+	if format != FormatGeth && format != FormatHex && format != FormatArmor {
+		return errors.Errorf("unknown key format %s", format)
+	}
+
+	ks, err := keyStore()
+	if err != nil {
+		return err
+	}
+	prompt := fmt.Sprintf("Please enter passphrase for %s: ", account.Address.Hex())
+	passphrase, err := txcmd.GetPassword(prompt)
+	if err != nil {
+		return err
+	}
+
+	var out []byte
+	switch format {
+	case FormatHex:
+		keyJSON, err := ks.Export(account, passphrase, passphrase)
+		if err != nil {
+			return err
+		}
+		key, err := keystore.DecryptKey(keyJSON, passphrase)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "WARNING: the private key is written unencrypted")
+		out = []byte(hex.EncodeToString(crypto.FromECDSA(key.PrivateKey)) + "\n")
+	default:
+		newPass, err := txcmd.GetPassword("Please enter passphrase of the exported key, empty keeps the current one: ")
+		if err != nil {
+			return err
+		}
+		if newPass == "" {
+			newPass = passphrase
+		}
+		keyJSON, err := ks.Export(account, passphrase, newPass)
+		if err != nil {
+			return err
+		}
+		out = append(keyJSON, '\n')
+		if format == FormatArmor {
+			out = armor(keyJSON)
+		}
+	}
+	return writeOutput(viper.GetString(FlagOut), out)
+}
+
+func keyStore() (*keystore.KeyStore, error) {
+	am, _, err := commons.MakeAccountManager()
+	if err != nil {
+		return nil, err
+	}
+	return commons.FetchKeystore(am), nil
+}
+
+func newPassphrase() (string, error) {
+	passphrase, err := txcmd.GetPassword("Please enter passphrase to keep the key with: ")
+	if err != nil {
+		return "", err
+	}
+	confirm, err := txcmd.GetPassword("Repeat the passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if passphrase != confirm {
+		return "", errors.New("passphrases don't match")
+	}
+	return passphrase, nil
+}
+
+func detectFormat(raw []byte) string {
+	raw = bytes.TrimSpace(raw)
+	switch {
+	case bytes.HasPrefix(raw, []byte(armorBegin)):
+		return FormatArmor
+	case bytes.HasPrefix(raw, []byte("{")):
+		return FormatGeth
+	default:
+		return FormatHex
+	}
+}
+
+// armor wraps the keyfile in base64 lines between a header and a footer.
+func armor(keyJSON []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(keyJSON)
+	var buf bytes.Buffer
+	buf.WriteString(armorBegin + "\n")
+	for len(encoded) > armorWidth {
+		buf.WriteString(encoded[:armorWidth] + "\n")
+		encoded = encoded[armorWidth:]
+	}
+	buf.WriteString(encoded + "\n")
+	buf.WriteString(armorEnd + "\n")
+	return buf.Bytes()
+}
+
+func dearmor(raw []byte) ([]byte, error) {
+	text := strings.TrimSpace(string(raw))
+	if !strings.HasPrefix(text, armorBegin) || !strings.HasSuffix(text, armorEnd) {
+		return nil, errors.New("missing armor header or footer")
+	}
+	text = strings.TrimSuffix(strings.TrimPrefix(text, armorBegin), armorEnd)
+	keyJSON, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+	return keyJSON, errors.Wrap(err, "decoding armor")
+}
+
+func readInput(file string) ([]byte, error) {
+	if file == "-" {
+		b, err := ioutil.ReadAll(os.Stdin)
+		return b, errors.WithStack(err)
+	}
+	b, err := ioutil.ReadFile(file)
+	return b, errors.WithStack(err)
+}
+
+func writeOutput(file string, d []byte) error {
+	if file == "-" {
+		_, err := os.Stdout.Write(d)
+		return errors.WithStack(err)
+	}
+	// keys are secrets, even encrypted
+	return errors.WithStack(ioutil.WriteFile(file, d, 0600))
+}
@@ -0,0 +1,27 @@
+package keys
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArmor(t *testing.T) {
+	keyJSON := []byte(`{"address":"f0e1c5b3b8d4a7e9","crypto":{"cipher":"aes-128-ctr","ciphertext":"00112233445566778899aabbccddeeff"},"version":3}`)
+
+	armored := armor(keyJSON)
+	assert.Equal(t, FormatArmor, detectFormat(armored))
+
+	decoded, err := dearmor(armored)
+	require.NoError(t, err)
+	assert.Equal(t, keyJSON, decoded)
+
+	_, err = dearmor(keyJSON)
+	assert.Error(t, err)
+}
+
+func TestDetectFormat(t *testing.T) {
+	assert.Equal(t, FormatGeth, detectFormat([]byte("  {\"version\":3}\n")))
+	assert.Equal(t, FormatHex, detectFormat([]byte("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318\n")))
+}
@@ -0,0 +1,170 @@
+package keys
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"runtime"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+
+	txcmd "github.com/dora/ultron/client/commands/txs"
+	"github.com/dora/ultron/commons"
+)
+
+// nolint
+const (
+	FlagAll      = "all"
+	FlagAccounts = "accounts"
+	FlagLightKDF = "light-kdf"
+)
+
+// nolint
+var PasswdCmd = &cobra.Command{
+	Use:   "passwd [address]",
+	Short: "Change the passphrase of an account, or of all of them with --all",
+	Long: `Change the passphrase of an account, or of all of them with --all.
+
+With --all every account of the keystore is re-encrypted with the new
+passphrase. The old one is asked once, or read by address from --accounts,
+a json list of {"address", "password"} such as the test account pools
+write. Accounts the old passphrase doesn't open are left as they are.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: cmdPasswd,
+}
+
+func init() {
+	PasswdCmd.Flags().Bool(FlagAll, false, "re-encrypt every account of the keystore")
+	PasswdCmd.Flags().String(FlagAccounts, "", "json file with the old passphrases by address, for --all")
+	PasswdCmd.Flags().Bool(FlagLightKDF, false, "re-encrypt with the light scrypt parameters, for test keystores")
+
+	RootCmd.AddCommand(PasswdCmd)
+}
+
+// accountPassword is an entry of the --accounts file.
+type accountPassword struct {
+	Address  common.Address `json:"address"`
+	Password string         `json:"password"`
+}
+
+func cmdPasswd(cmd *cobra.Command, args []string) error {
+	all := viper.GetBool(FlagAll)
+	if all == (len(args) == 1) {
+		return errors.New("give either an address or --all")
+	}
+	if len(args) == 1 && !common.IsHexAddress(args[0]) {
+		return errors.Errorf("invalid address %s", args[0])
+	}
+
+	ks, err := passwdKeyStore(viper.GetBool(FlagLightKDF))
+	if err != nil {
+		return err
+	}
+
+	// old passphrases by address, the empty address for all of them
+	old := make(map[common.Address]string)
+	if file := viper.GetString(FlagAccounts); file != "" && all {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		var entries []accountPassword
+		if err := json.Unmarshal(b, &entries); err != nil {
+			return errors.Wrap(err, "reading "+file)
+		}
+		for _, e := range entries {
+			old[e.Address] = e.Password
+		}
+	} else {
+		pass, err := txcmd.GetPassword("Please enter the current passphrase: ")
+		if err != nil {
+			return err
+		}
+		old[common.Address{}] = pass
+	}
+	passphrase, err := newPassphrase()
+	if err != nil {
+		return err
+	}
+
+	if !all {
+		account := accounts.Account{Address: common.HexToAddress(args[0])}
+		if err := ks.Update(account, old[common.Address{}], passphrase); err != nil {
+			return err
+		}
+		fmt.Printf("Changed the passphrase of %s\n", account.Address.Hex())
+		return nil
+	}
+
+	updated, failed := reencrypt(ks, old, passphrase)
+	for _, f := range failed {
+		fmt.Printf("Skipped %s: %v\n", f.account.Address.Hex(), f.err)
+	}
+	fmt.Printf("Re-encrypted %d of %d accounts\n", updated, updated+len(failed))
+	return nil
+}
+
+type updateFailure struct {
+	account accounts.Account
+	err     error
+}
+
+// reencrypt updates the accounts of ks in parallel, scrypt makes each of
+// them take a while.
+func reencrypt(ks *keystore.KeyStore, old map[common.Address]string, passphrase string) (int, []updateFailure) {
+	var (
+		mtx     sync.Mutex
+		wg      sync.WaitGroup
+		updated int
+		failed  []updateFailure
+	)
+	work := make(chan accounts.Account)
+	for i := 0; i < runtime.NumCPU(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for account := range work {
+				pass, ok := old[account.Address]
+				if !ok {
+					pass, ok = old[common.Address{}]
+				}
+				err := errors.New("no passphrase given")
+				if ok {
+					err = ks.Update(account, pass, passphrase)
+				}
+
+				mtx.Lock()
+				if err != nil {
+					failed = append(failed, updateFailure{account, err})
+				} else {
+					updated++
+				}
+				mtx.Unlock()
+			}
+		}()
+	}
+	for _, account := range ks.Accounts() {
+		work <- account
+	}
+	close(work)
+	wg.Wait()
+	return updated, failed
+}
+
+func passwdKeyStore(light bool) (*keystore.KeyStore, error) {
+	if !light {
+		return keyStore()
+	}
+	_, keydir, err := commons.MakeAccountManager()
+	if err != nil {
+		return nil, err
+	}
+	return keystore.NewKeyStore(keydir, keystore.LightScryptN, keystore.LightScryptP), nil
+}
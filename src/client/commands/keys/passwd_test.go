@@ -0,0 +1,41 @@
+package keys
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestReencrypt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "keystore")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ks := keystore.NewKeyStore(dir, keystore.LightScryptN, keystore.LightScryptP)
+	a, err := ks.NewAccount("0")
+	require.NoError(t, err)
+	b, err := ks.NewAccount("1")
+	require.NoError(t, err)
+	c, err := ks.NewAccount("other")
+	require.NoError(t, err)
+
+	old := map[common.Address]string{
+		a.Address:        "0",
+		b.Address:        "1",
+		common.Address{}: "wrong",
+	}
+	updated, failed := reencrypt(ks, old, "new")
+	assert.Equal(t, 2, updated)
+	require.Len(t, failed, 1)
+	assert.Equal(t, c.Address, failed[0].account.Address)
+
+	assert.NoError(t, ks.Unlock(a, "new"))
+	assert.NoError(t, ks.Unlock(b, "new"))
+	assert.NoError(t, ks.Unlock(c, "other"))
+}
@@ -0,0 +1,57 @@
+package keys
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/dora/ultron/commons"
+)
+
+// nolint
+var (
+	WatchCmd = &cobra.Command{
+		Use:   "watch <address> <label>",
+		Short: "Watch an address there is no key for, under a label",
+		Args:  cobra.ExactArgs(2),
+		RunE:  cmdWatch,
+	}
+
+	UnwatchCmd = &cobra.Command{
+		Use:   "unwatch <address|label>",
+		Short: "Stop watching an address",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdUnwatch,
+	}
+)
+
+func init() {
+	RootCmd.AddCommand(WatchCmd, UnwatchCmd)
+}
+
+func cmdWatch(cmd *cobra.Command, args []string) error {
+	if !common.IsHexAddress(args[0]) {
+		return errors.Errorf("invalid address %s", args[0])
+	}
+	e, err := commons.OpenAddressBook().Watch(common.HexToAddress(args[0]), args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Watching %s as %s\n", e.Address.Hex(), e.Label)
+	return nil
+}
+
+func cmdUnwatch(cmd *cobra.Command, args []string) error {
+	removed, err := commons.OpenAddressBook().Unwatch(args[0])
+	if err != nil {
+		return err
+	}
+	if !removed {
+		return errors.Errorf("%s isn't watched", args[0])
+	}
+	fmt.Printf("Stopped watching %s\n", args[0])
+	return nil
+}
@@ -0,0 +1,279 @@
+// Package query has the client commands that read chain state through the
+// ethereum rpc of the node, printing it as text or, with --output json, as
+// the rpc returned it.
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/tendermint/tmlibs/cli"
+
+	txcmd "github.com/dora/ultron/client/commands/txs"
+	"github.com/dora/ultron/commons"
+)
+
+// nolint
+const (
+	FlagHeight = "height"
+	FlagFull   = "full"
+)
+
+// nolint
+var (
+	CmdQueryAccount = &cobra.Command{
+		Use:   "account <address|label>",
+		Short: "Query the balance, nonce and code of an account, watch-only ones by label",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdQueryAccount,
+	}
+
+	CmdQueryBlock = &cobra.Command{
+		Use:   "block [number|hash]",
+		Short: "Query a block, the latest one by default",
+		Args:  cobra.MaximumNArgs(1),
+		RunE:  cmdQueryBlock,
+	}
+
+	CmdQueryTx = &cobra.Command{
+		Use:   "tx <hash>",
+		Short: "Query a transaction by hash",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdQueryTx,
+	}
+
+	CmdQueryReceipt = &cobra.Command{
+		Use:   "receipt <hash>",
+		Short: "Query the receipt of a transaction by hash",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdQueryReceipt,
+	}
+)
+
+func init() {
+	CmdQueryAccount.Flags().String(FlagHeight, "latest", "block number to read the account at")
+	CmdQueryBlock.Flags().Bool(FlagFull, false, "include the transactions, not only their hashes")
+}
+
+// quantities are the hex encoded numbers of the rpc results printed in
+// decimal as text.
+var quantities = map[string]bool{
+	"balance":           true,
+	"nonce":             true,
+	"number":            true,
+	"blockNumber":       true,
+	"transactionIndex":  true,
+	"gas":               true,
+	"gasPrice":          true,
+	"gasLimit":          true,
+	"gasUsed":           true,
+	"cumulativeGasUsed": true,
+	"value":             true,
+	"timestamp":         true,
+	"size":              true,
+	"difficulty":        true,
+	"totalDifficulty":   true,
+	"status":            true,
+}
+
+func cmdQueryAccount(cmd *cobra.Command, args []string) error {
+	// labels of the watch-only accounts stand for their address
+	entry, watched, err := commons.OpenAddressBook().Lookup(args[0])
+	if err != nil {
+		return err
+	}
+	address := entry.Address
+	if !watched {
+		if !common.IsHexAddress(args[0]) {
+			return errors.Errorf("%s is neither an address nor a watched label", args[0])
+		}
+		address = common.HexToAddress(args[0])
+	}
+	height, err := blockArg(viper.GetString(FlagHeight))
+	if err != nil {
+		return err
+	}
+
+	var balance, nonce hexutil.Big
+	var code hexutil.Bytes
+	calls := []struct {
+		method string
+		result interface{}
+	}{
+		{"eth_getBalance", &balance},
+		{"eth_getTransactionCount", &nonce},
+		{"eth_getCode", &code},
+	}
+	for _, c := range calls {
+		if err := call(c.result, c.method, address, height); err != nil {
+			return err
+		}
+	}
+
+	account := map[string]interface{}{
+		"address": address,
+		"balance": &balance,
+		"nonce":   &nonce,
+		"code":    code,
+	}
+	if watched {
+		account["label"] = entry.Label
+	}
+	return output(account)
+}
+
+func cmdQueryBlock(cmd *cobra.Command, args []string) error {
+	full := viper.GetBool(FlagFull)
+	var block map[string]interface{}
+	var err error
+	if len(args) == 1 && len(args[0]) == 2+2*common.HashLength {
+		err = call(&block, "eth_getBlockByHash", common.HexToHash(args[0]), full)
+	} else {
+		number := "latest"
+		if len(args) == 1 {
+			number = args[0]
+		}
+		var height string
+		if height, err = blockArg(number); err != nil {
+			return err
+		}
+		err = call(&block, "eth_getBlockByNumber", height, full)
+	}
+	if err != nil {
+		return err
+	}
+	if block == nil {
+		return errors.New("block not found")
+	}
+	return output(block)
+}
+
+func cmdQueryTx(cmd *cobra.Command, args []string) error {
+	var tx map[string]interface{}
+	if err := call(&tx, "eth_getTransactionByHash", common.HexToHash(args[0])); err != nil {
+		return err
+	}
+	if tx == nil {
+		return errors.New("transaction not found")
+	}
+	return output(tx)
+}
+
+func cmdQueryReceipt(cmd *cobra.Command, args []string) error {
+	var receipt map[string]interface{}
+	if err := call(&receipt, "eth_getTransactionReceipt", common.HexToHash(args[0])); err != nil {
+		return err
+	}
+	if receipt == nil {
+		return errors.New("receipt not found, the transaction may be pending")
+	}
+	return output(receipt)
+}
+
+// blockArg turns a decimal block number, or latest, earliest or pending,
+// into the block parameter of the rpc.
+func blockArg(number string) (string, error) {
+	switch number {
+	case "latest", "earliest", "pending":
+		return number, nil
+	}
+	n, ok := new(big.Int).SetString(number, 10)
+	if !ok || n.Sign() < 0 {
+		return "", errors.Errorf("invalid block number %s", number)
+	}
+	return hexutil.EncodeBig(n), nil
+}
+
+func call(result interface{}, method string, args ...interface{}) error {
+	client, err := rpc.Dial(txcmd.GetEthURL())
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	return client.CallContext(ctx, result, method, args...)
+}
+
+func output(v map[string]interface{}) error {
+	if viper.GetString(cli.OutputFlag) == "json" {
+		js, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(js))
+		return nil
+	}
+
+	// round trip through json so fields read from the rpc and set here
+	// print alike
+	js, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(js, &fields); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(fields))
+	width := 0
+	for k := range fields {
+		keys = append(keys, k)
+		if len(k) > width {
+			width = len(k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(os.Stdout, "%-*s  %s\n", width, k, text(k, fields[k]))
+	}
+	return nil
+}
+
+// text formats the field k for a human: quantities in decimal, lists one
+// item per line and objects as indented json.
+func text(k string, v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		if quantities[k] {
+			if n, err := hexutil.DecodeBig(v); err == nil {
+				return n.String()
+			}
+		}
+		return v
+	case []interface{}:
+		if len(v) == 0 {
+			return "-"
+		}
+		items := make([]string, len(v))
+		for i, item := range v {
+			if s, ok := item.(string); ok {
+				items[i] = "\n  " + s
+				continue
+			}
+			js, _ := json.MarshalIndent(item, "  ", "  ")
+			items[i] = "\n  " + string(js)
+		}
+		return fmt.Sprintf("%d%s", len(v), strings.Join(items, ""))
+	default:
+		js, _ := json.MarshalIndent(v, "", "  ")
+		return string(js)
+	}
+}
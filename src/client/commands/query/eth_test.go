@@ -0,0 +1,31 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockArg(t *testing.T) {
+	n, err := blockArg("1000")
+	require.NoError(t, err)
+	assert.Equal(t, "0x3e8", n)
+
+	n, err = blockArg("latest")
+	require.NoError(t, err)
+	assert.Equal(t, "latest", n)
+
+	_, err = blockArg("-1")
+	assert.Error(t, err)
+	_, err = blockArg("0x10")
+	assert.Error(t, err)
+}
+
+func TestText(t *testing.T) {
+	assert.Equal(t, "1000", text("gasUsed", "0x3e8"))
+	assert.Equal(t, "0x3e8", text("hash", "0x3e8"))
+	assert.Equal(t, "-", text("to", nil))
+	assert.Equal(t, "-", text("logs", []interface{}{}))
+	assert.Equal(t, "2\n  0x01\n  0x02", text("transactions", []interface{}{"0x01", "0x02"}))
+}
@@ -0,0 +1,77 @@
+package query
+
+import (
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/dora/ultron/ledger"
+)
+
+// nolint
+const (
+	FlagFormat = "format"
+	FlagOut    = "out"
+)
+
+// CmdQueryLedger exports the double-entry ledger of a range of blocks.
+var CmdQueryLedger = &cobra.Command{
+	Use:   "ledger <from> [to]",
+	Short: "Export the double-entry ledger of blocks from to to, debits and credits per account, for audits",
+	Args:  cobra.RangeArgs(1, 2),
+	RunE:  cmdQueryLedger,
+}
+
+func init() {
+	CmdQueryLedger.Flags().String(FlagFormat, ledger.FormatCSV, "format of the export (csv|parquet)")
+	CmdQueryLedger.Flags().String(FlagOut, "-", "file to write the export to")
+}
+
+func cmdQueryLedger(cmd *cobra.Command, args []string) error {
+	from, err := strconv.ParseUint(args[0], 10, 64)
+	if err != nil {
+		return errors.Errorf("invalid block number %s", args[0])
+	}
+	to := from
+	if len(args) == 2 {
+		if to, err = strconv.ParseUint(args[1], 10, 64); err != nil {
+			return errors.Errorf("invalid block number %s", args[1])
+		}
+	}
+	if to < from {
+		return errors.New("the last block comes before the first one")
+	}
+
+	var out io.Writer = os.Stdout
+	if file := viper.GetString(FlagOut); file != "-" {
+		f, err := os.Create(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close() // nolint: errcheck
+		out = f
+	}
+	w, err := ledger.NewWriter(viper.GetString(FlagFormat), out)
+	if err != nil {
+		return err
+	}
+
+	for n := from; n <= to; n++ {
+		var postings []ledger.Posting
+		if err := call(&postings, "ultron_ledger", hexutil.Uint64(n)); err != nil {
+			return errors.Wrapf(err, "block %d", n)
+		}
+		for _, p := range postings {
+			if err := w.Write(p); err != nil {
+				return err
+			}
+		}
+	}
+	return w.Close()
+}
@@ -62,11 +62,16 @@ func GetSigner() common.Address {
 // eg. if you already set the middleware layers in your code, or want to
 // output in another format.
 func DoTx(tx sdk.Tx) (err error) {
+	// only write it out, `tx sign` signs it later
+	if wrote, err := PrepareTx(tx); err != nil || wrote {
+		return err
+	}
+
 	address := viper.GetString(FlagAddress)
 	from := common.HexToAddress(address)
 
 	prompt := fmt.Sprintf("Please enter passphrase for %s: ", address)
-	passphrase, err := getPassword(prompt)
+	passphrase, err := GetPassword(prompt)
 	if err != nil {
 		return err
 	}
@@ -98,19 +103,39 @@ func DoTx(tx sdk.Tx) (err error) {
 	}
 }
 
-func wrapAndSign(tx sdk.Tx, from common.Address, passphrase string) (hexutil.Bytes, error) {
+// wrap returns the unsigned ethereum tx carrying tx: a contract creation
+// with the json of tx as data, no value and no gas, module txs don't run
+// in the evm.
+func wrap(tx sdk.Tx, nonce uint64) (*types.Transaction, error) {
 	data, err := json.Marshal(tx)
 	if err != nil {
 		return nil, err
 	}
 
-	ethTx := types.NewContractCreation(
-		getNonce(from),
+	return types.NewContractCreation(
+		nonce,
 		big.NewInt(0),
 		big.NewInt(0),
 		big.NewInt(0),
 		data,
-	)
+	), nil
+}
+
+// EstimateFee returns the wei the sender of tx pays for it, the gas limit
+// times the gas price of the ethereum tx DoTx sends.
+func EstimateFee(tx sdk.Tx) (*big.Int, error) {
+	ethTx, err := wrap(tx, 0)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).Mul(ethTx.Gas(), ethTx.GasPrice()), nil
+}
+
+func wrapAndSign(tx sdk.Tx, from common.Address, passphrase string) (hexutil.Bytes, error) {
+	ethTx, err := wrap(tx, getNonce(from))
+	if err != nil {
+		return nil, err
+	}
 
 	am, _, _ := commons.MakeAccountManager()
 	_, err = commons.UnlockAccount(am, from, passphrase, nil)
@@ -143,7 +168,7 @@ func getNonce(addr common.Address) uint64 {
 
 	var nonce uint64
 	//get nonce
-	client, err := ethclient.Dial(getEthUrl())
+	client, err := ethclient.Dial(GetEthURL())
 	if err != nil {
 		fmt.Errorf(err.Error())
 	} else {
@@ -157,7 +182,8 @@ func getNonce(addr common.Address) uint64 {
 	return nonce
 }
 
-func getEthUrl() string {
+// GetEthURL returns the ethereum rpc endpoint of the node set by --node.
+func GetEthURL() string {
 	node := viper.GetString(commands.NodeFlag)
 	u, _ := url.Parse(node)
 	return fmt.Sprintf("http://%s:%d", u.Hostname(), 8545)
@@ -298,7 +324,7 @@ func OutputTxSync(res *ctypes.ResultBroadcastTx) error {
 
 func signTx(tx ttypes.Signable, address string) error {
 	prompt := fmt.Sprintf("Please enter passphrase for %s: ", address)
-	pass, err := getPassword(prompt)
+	pass, err := GetPassword(prompt)
 	if err != nil {
 		return err
 	}
@@ -352,7 +378,9 @@ func stdinPassword() (string, error) {
 	return strings.TrimSpace(pass), nil
 }
 
-func getPassword(prompt string) (pass string, err error) {
+// GetPassword asks for a passphrase on the terminal, or reads the next line
+// of stdin when it isn't one.
+func GetPassword(prompt string) (pass string, err error) {
 	if inputIsTty() {
 		pass, err = speakeasy.Ask(prompt)
 	} else {
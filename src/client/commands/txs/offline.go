@@ -0,0 +1,162 @@
+package txs
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// nolint
+const (
+	FlagOffline = "offline"
+	FlagOut     = "out"
+)
+
+// SignedTx is what `tx sign` writes and `tx broadcast` reads: a tx signed
+// with the local keystore, ready to be posted by any node.
+type SignedTx struct {
+	From  common.Address `json:"from"`
+	Nonce uint64         `json:"nonce"`
+	Hash  common.Hash    `json:"hash"`
+	Tx    hexutil.Bytes  `json:"tx"` // rlp encoded ethereum tx
+}
+
+// nolint
+var (
+	SignCmd = &cobra.Command{
+		Use:   "sign [tx.json]",
+		Short: "Sign a tx prepared with --prepare and write it out for broadcast",
+		Long: `Sign a tx prepared with --prepare and write it out for broadcast.
+
+With --offline no node is contacted, so it can run on an air-gapped machine,
+--nonce is required then. The tx is read from stdin when no file is given.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: cmdSignTx,
+	}
+
+	BroadcastCmd = &cobra.Command{
+		Use:   "broadcast <signed.json>",
+		Short: "Post a tx written by `tx sign` to the node",
+		Args:  cobra.ExactArgs(1),
+		RunE:  cmdBroadcastTx,
+	}
+)
+
+func init() {
+	SignCmd.Flags().Bool(FlagOffline, false, "don't contact a node, --nonce must be set")
+	SignCmd.Flags().String(FlagOut, "-", "file to write the signed tx to")
+
+	RootCmd.AddCommand(SignCmd, BroadcastCmd)
+}
+
+func cmdSignTx(cmd *cobra.Command, args []string) error {
+	var in string
+	if len(args) > 0 {
+		in = args[0]
+	}
+	raw, err := readInput(in)
+	if err != nil {
+		return err
+	}
+
+	var tx sdk.Tx
+	if err = json.Unmarshal(raw, &tx); err != nil {
+		return errors.WithStack(err)
+	}
+	if err = tx.ValidateBasic(); err != nil {
+		return err
+	}
+
+	address := viper.GetString(FlagAddress)
+	if address == "" {
+		return errors.New("--address is required to sign tx")
+	}
+	if !common.IsHexAddress(address) {
+		return errors.Errorf("invalid address %s", address)
+	}
+	if viper.GetBool(FlagOffline) && viper.GetInt(FlagNonce) < 0 {
+		return errors.New("--nonce is required to sign offline")
+	}
+	from := common.HexToAddress(address)
+
+	prompt := fmt.Sprintf("Please enter passphrase for %s: ", address)
+	passphrase, err := GetPassword(prompt)
+	if err != nil {
+		return err
+	}
+	txBytes, err := wrapAndSign(tx, from, passphrase)
+	if err != nil {
+		return err
+	}
+
+	signed, err := decodeSignedTx(txBytes)
+	if err != nil {
+		return err
+	}
+	js, err := json.MarshalIndent(signed, "", "  ")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return writeOutput(viper.GetString(FlagOut), append(js, '\n'))
+}
+
+func cmdBroadcastTx(cmd *cobra.Command, args []string) error {
+	raw, err := readInput(args[0])
+	if err != nil {
+		return err
+	}
+
+	var signed SignedTx
+	if err = json.Unmarshal(raw, &signed); err != nil {
+		return errors.WithStack(err)
+	}
+	// check the file wasn't mangled on its way from the signing machine
+	decoded, err := decodeSignedTx(signed.Tx)
+	if err != nil {
+		return err
+	}
+	if decoded.From != signed.From || decoded.Nonce != signed.Nonce {
+		return errors.Errorf("tx is signed by %s with nonce %d, not by %s with nonce %d",
+			decoded.From.Hex(), decoded.Nonce, signed.From.Hex(), signed.Nonce)
+	}
+
+	if viper.GetString(FlagType) == "commit" {
+		bres, err := broadcastTxCommit(signed.Tx)
+		if err != nil {
+			return err
+		}
+		return OutputTx(bres)
+	}
+	bres, err := broadcastTxSync(signed.Tx)
+	if err != nil {
+		return err
+	}
+	return OutputTxSync(bres)
+}
+
+// decodeSignedTx reads back the sender and nonce of the rlp encoded tx.
+func decodeSignedTx(txBytes []byte) (*SignedTx, error) {
+	var ethTx types.Transaction
+	if err := rlp.DecodeBytes(txBytes, &ethTx); err != nil {
+		return nil, errors.Wrap(err, "decoding signed tx")
+	}
+	from, err := types.Sender(types.NewEIP155Signer(ethTx.ChainId()), &ethTx)
+	if err != nil {
+		return nil, errors.Wrap(err, "recovering tx sender")
+	}
+	return &SignedTx{
+		From:  from,
+		Nonce: ethTx.Nonce(),
+		Hash:  ethTx.Hash(),
+		Tx:    txBytes,
+	}, nil
+}
@@ -30,12 +30,17 @@ var RootCmd = &cobra.Command{
 
 func init() {
 	RootCmd.PersistentFlags().String(FlagName, "", "name to sign the tx")
-	RootCmd.PersistentFlags().String(FlagAddress, "", "account address to sign the tx")
 	RootCmd.PersistentFlags().Bool(FlagNoSign, false, "don't add a signature")
-	RootCmd.PersistentFlags().String(FlagPrepare, "", "file to store prepared tx")
 	RootCmd.Flags().String(FlagIn, "", "file with tx in json format")
-	RootCmd.PersistentFlags().String(FlagType, "commit", "type(sync|commit) of broadcast tx to tendermint")
-	RootCmd.PersistentFlags().Int(FlagNonce, -1, "Sequence number for this transaction")
+	AddSignFlags(RootCmd)
+}
+
+// AddSignFlags adds the flags DoTx reads to cmd and its subcommands.
+func AddSignFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().String(FlagAddress, "", "account address to sign the tx")
+	cmd.PersistentFlags().String(FlagPrepare, "", "file to store prepared tx")
+	cmd.PersistentFlags().String(FlagType, "commit", "type(sync|commit) of broadcast tx to tendermint")
+	cmd.PersistentFlags().Int(FlagNonce, -1, "Sequence number for this transaction")
 }
 
 func doRawTx(cmd *cobra.Command, args []string) error {
@@ -3,6 +3,7 @@ package main
 import (
 	"github.com/spf13/cobra"
 
+	querycmd "github.com/dora/ultron/client/commands/query"
 	txcmd "github.com/dora/ultron/client/commands/txs"
 	stakecmd "github.com/dora/ultron/modules/stake/commands"
 	"github.com/cosmos/cosmos-sdk/client/commands"
@@ -22,6 +23,11 @@ func prepareClientCommands() {
 	commands.AddBasicFlags(clientCmd)
 
 	query.RootCmd.AddCommand(
+		querycmd.CmdQueryAccount,
+		querycmd.CmdQueryBlock,
+		querycmd.CmdQueryTx,
+		querycmd.CmdQueryReceipt,
+		querycmd.CmdQueryLedger,
 		stakecmd.CmdQueryValidator,
 		stakecmd.CmdQueryValidators,
 		stakecmd.CmdQueryDelegator,
@@ -35,6 +41,8 @@ func prepareClientCommands() {
 		stakecmd.CmdActivateCandidacy,
 		stakecmd.CmdDelegate,
 		stakecmd.CmdWithdraw,
+		stakecmd.CmdRedelegate,
+		stakecmd.CmdUnjail,
 	)
 
 	clientCmd.AddCommand(
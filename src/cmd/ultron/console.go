@@ -1,29 +1,39 @@
 package main
 
 import (
+	"path/filepath"
+
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 
 	"github.com/ethereum/go-ethereum/cmd/utils"
 	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/tendermint/tmlibs/cli"
 
+	emtUtils "github.com/dora/ultron/backend/cmd/utils"
 	"github.com/dora/ultron/console"
 )
 
 var (
 	attachCmd = &cobra.Command{
 		RunE:  remoteConsole,
-		Use:   "attach",
-		Short: "Start an interactive JavaScript environment (connect to node)",
+		Use:   "attach [url]",
+		Short: "Start an interactive JavaScript environment (connect to node, the ipc socket of the home by default)",
 	}
 )
 
 func remoteConsole(cmd *cobra.Command, args []string) error {
-	if len(args) != 1 || len(args[0]) == 0 {
-		return errors.New("missing url")
+	// the ipc socket of the node in the home by default
+	endpoint := filepath.Join(viper.GetString(cli.HomeFlag), emtUtils.IPCFile)
+	if len(args) > 1 {
+		return errors.New("too many arguments")
+	}
+	if len(args) == 1 && len(args[0]) > 0 {
+		endpoint = args[0]
 	}
 
-	client, err := rpc.Dial(args[0])
+	client, err := rpc.Dial(endpoint)
 	if err != nil {
 		utils.Fatalf("Unable to attach to remote node: %v", err)
 	}
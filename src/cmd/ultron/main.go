@@ -7,6 +7,7 @@ import (
 
 	"github.com/tendermint/tmlibs/cli"
 
+	keyscmd "github.com/dora/ultron/client/commands/keys"
 	basecmd "github.com/dora/ultron/node/commands"
 	"github.com/cosmos/cosmos-sdk/client/commands/auto"
 )
@@ -31,11 +32,24 @@ func main() {
 	// add commands
 	prepareNodeCommands()
 	prepareClientCommands()
+	prepareTxCommands()
+	prepareSignerCommand()
 
 	UltronCmd.AddCommand(
 		nodeCmd,
+		basecmd.GetRunSupervisedCmd(),
+		basecmd.GetBenchCmd(),
+		basecmd.GetBackupCmd(),
+		basecmd.GetDBCmd(),
+		basecmd.GetDebugCmd(),
+		basecmd.GetCompatCmd(),
+		basecmd.GetVersionCmd(),
 		attachCmd,
+		schemaCmd,
 		clientCmd,
+		txCmd,
+		signerCmd,
+		keyscmd.RootCmd,
 
 		lineBreak,
 		auto.AutoCompleteCmd,
@@ -0,0 +1,6 @@
+package main
+
+// Tx handler plugins are linked in with a blank import, their init registers
+// them with app.RegisterTxHandler:
+//
+//	import _ "github.com/example/mymodule"
@@ -0,0 +1,24 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dora/ultron/backend"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "rpc-schema",
+	Short: "Print the OpenRPC document of the ultron_* methods, to generate clients from",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		js, err := json.MarshalIndent(backend.OpenRPC(), "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(js))
+		return nil
+	},
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dora/ultron/signer"
+	"github.com/dora/ultron/ultronclient"
+)
+
+// nolint
+const (
+	FlagSignerAddr = "laddr"
+	FlagKeystore   = "keystore"
+	FlagUnlock     = "unlock"
+	FlagPassword   = "password"
+	FlagTokensFile = "tokens_file"
+	FlagTLSCert    = "tls_cert"
+	FlagTLSKey     = "tls_key"
+)
+
+// signerCmd runs the signing service of package signer, which RPC nodes
+// point at with signer_url.
+var signerCmd = &cobra.Command{
+	Use:   "signer",
+	Short: "Serve the signing of txs and typed data with the keys of a keystore, apart from the nodes",
+	Args:  cobra.NoArgs,
+	RunE:  runSigner,
+}
+
+func prepareSignerCommand() {
+	signerCmd.Flags().String(FlagSignerAddr, "127.0.0.1:8550", "Address to serve the signing API on")
+	signerCmd.Flags().String(FlagKeystore, "", "Directory of the keystore")
+	signerCmd.Flags().String(FlagUnlock, "", "Comma separated accounts to sign for, all those of the keystore when empty")
+	signerCmd.Flags().String(FlagPassword, "", "File of the passphrases, one a line in the order of the accounts, the last one for the rest")
+	signerCmd.Flags().String(FlagTokensFile, "", "File of the tokens the clients authenticate with, one a line")
+	signerCmd.Flags().String(FlagTLSCert, "", "Certificate to serve the API over https with")
+	signerCmd.Flags().String(FlagTLSKey, "", "Key of the certificate")
+}
+
+func runSigner(cmd *cobra.Command, args []string) error {
+	if viper.GetString(FlagKeystore) == "" {
+		return fmt.Errorf("no keystore, see --%s", FlagKeystore)
+	}
+	tokens, err := readLines(viper.GetString(FlagTokensFile))
+	if err != nil {
+		return err
+	}
+	if len(tokens) == 0 {
+		return fmt.Errorf("no token, see --%s", FlagTokensFile)
+	}
+	passwords, err := readLines(viper.GetString(FlagPassword))
+	if err != nil {
+		return err
+	}
+	if len(passwords) == 0 {
+		return fmt.Errorf("no passphrase, see --%s", FlagPassword)
+	}
+
+	ks := keystore.NewKeyStore(viper.GetString(FlagKeystore), keystore.StandardScryptN, keystore.StandardScryptP)
+	var accounts []common.Address
+	for _, a := range strings.Split(viper.GetString(FlagUnlock), ",") {
+		if a = strings.TrimSpace(a); a == "" {
+			continue
+		}
+		if !common.IsHexAddress(a) {
+			return fmt.Errorf("invalid account %s", a)
+		}
+		accounts = append(accounts, common.HexToAddress(a))
+	}
+	if len(accounts) == 0 {
+		for _, a := range ks.Accounts() {
+			accounts = append(accounts, a.Address)
+		}
+	}
+
+	unlock := ultronclient.KeystoreUnlocker(ks)
+	keys := make([]*ecdsa.PrivateKey, len(accounts))
+	for i, a := range accounts {
+		password := passwords[len(passwords)-1]
+		if i < len(passwords) {
+			password = passwords[i]
+		}
+		if keys[i], err = unlock(a, password); err != nil {
+			return fmt.Errorf("unlocking %s: %v", a.Hex(), err)
+		}
+		log.Info("Unlocked account", "address", a.Hex())
+	}
+
+	addr := viper.GetString(FlagSignerAddr)
+	server := &http.Server{Addr: addr, Handler: signer.NewServer(keys, tokens)}
+	log.Info("Serving the signing API", "addr", addr, "accounts", len(keys))
+	if cert := viper.GetString(FlagTLSCert); cert != "" {
+		return server.ListenAndServeTLS(cert, viper.GetString(FlagTLSKey))
+	}
+	return server.ListenAndServe()
+}
+
+// readLines returns the lines of file that aren't blank, none if file is
+// empty
+func readLines(file string) ([]string, error) {
+	if file == "" {
+		return nil, nil
+	}
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if line = strings.TrimRight(line, "\r"); strings.TrimSpace(line) != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
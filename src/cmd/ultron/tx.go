@@ -0,0 +1,228 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/client/commands"
+	"github.com/ethereum/go-ethereum/common"
+
+	txcmd "github.com/dora/ultron/client/commands/txs"
+	"github.com/dora/ultron/modules/community"
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/modules/stake"
+	"github.com/dora/ultron/modules/upgrade"
+	"github.com/dora/ultron/utils"
+)
+
+// nolint
+const (
+	FlagInfo   = "info"
+	FlagReason = "reason"
+)
+
+// txCmd sends the module txs, signed with an account of the keystore, see
+// --address. Amounts take a unit: 100wei, 20gwei or 1.5ether, plain
+// numbers are wei.
+var txCmd = &cobra.Command{
+	Use:   "tx",
+	Short: "Send staking and governance txs",
+	Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+}
+
+var stakingCmd = &cobra.Command{
+	Use:   "staking",
+	Short: "Delegate to validators and take delegations back",
+	Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+}
+
+var govCmd = &cobra.Command{
+	Use:   "gov",
+	Short: "Governance txs, sent by the params authority",
+	Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+}
+
+var submitProposalCmd = &cobra.Command{
+	Use:   "submit-proposal",
+	Short: "Enact a proposal: change params, schedule an upgrade or spend from the community pool",
+	Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+}
+
+func prepareTxCommands() {
+	commands.AddBasicFlags(txCmd)
+	txcmd.AddSignFlags(txCmd)
+
+	stakingCmd.AddCommand(
+		&cobra.Command{
+			Use:   "delegate <validator> <amount>",
+			Short: "Delegate amount to the validator",
+			Args:  cobra.ExactArgs(2),
+			RunE:  cmdDelegate,
+		},
+		&cobra.Command{
+			Use:   "unbond <validator> <amount>",
+			Short: "Withdraw amount delegated to the validator",
+			Args:  cobra.ExactArgs(2),
+			RunE:  cmdUnbond,
+		},
+		&cobra.Command{
+			Use:   "redelegate <from-validator> <to-validator> <amount>",
+			Short: "Move amount delegated to a validator to another one without unbonding it",
+			Args:  cobra.ExactArgs(3),
+			RunE:  cmdRedelegate,
+		},
+	)
+
+	upgradeCmd := &cobra.Command{
+		Use:   "upgrade <name> <height>",
+		Short: "Schedule the upgrade name at height, replacing the one scheduled",
+		Args:  cobra.ExactArgs(2),
+		RunE:  cmdProposeUpgrade,
+	}
+	upgradeCmd.Flags().String(FlagInfo, "", "where to get the binary of the upgrade")
+
+	spendCmd := &cobra.Command{
+		Use:   "community-spend <recipient> <amount> <height>",
+		Short: "Pay amount out of the community pool to recipient at height",
+		Args:  cobra.ExactArgs(3),
+		RunE:  cmdProposeSpend,
+	}
+	spendCmd.Flags().String(FlagReason, "", "why the spend is made")
+
+	submitProposalCmd.AddCommand(
+		&cobra.Command{
+			Use:   "param-change <key=value>...",
+			Short: "Change chain params at once, either all of them or none",
+			Args:  cobra.MinimumNArgs(1),
+			RunE:  cmdProposeParamChange,
+		},
+		upgradeCmd,
+		spendCmd,
+	)
+	govCmd.AddCommand(submitProposalCmd)
+
+	txCmd.AddCommand(stakingCmd, govCmd)
+}
+
+// doTx checks tx, prints its fee and signs and sends it with txcmd.DoTx.
+func doTx(tx sdk.Tx) error {
+	if err := tx.ValidateBasic(); err != nil {
+		return err
+	}
+	fee, err := txcmd.EstimateFee(tx)
+	if err != nil {
+		return err
+	}
+	// stderr, stdout gets the result of the tx
+	fmt.Fprintf(os.Stderr, "Fee: %s wei\n", fee)
+	return txcmd.DoTx(tx)
+}
+
+func parseAddress(str string) (common.Address, error) {
+	if !common.IsHexAddress(str) {
+		return common.Address{}, fmt.Errorf("invalid address %q", str)
+	}
+	return common.HexToAddress(str), nil
+}
+
+// parseAmount parses a positive amount, see utils.ParseAmount.
+func parseAmount(str string) (string, error) {
+	amount, err := utils.ParseAmount(str)
+	if err != nil {
+		return "", err
+	}
+	if amount.Sign() == 0 {
+		return "", fmt.Errorf("amount must be positive")
+	}
+	return amount.String(), nil
+}
+
+func parseHeight(str string) (int64, error) {
+	height, err := strconv.ParseInt(str, 10, 64)
+	if err != nil || height <= 0 {
+		return 0, fmt.Errorf("invalid height %q", str)
+	}
+	return height, nil
+}
+
+func cmdDelegate(cmd *cobra.Command, args []string) error {
+	validator, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(args[1])
+	if err != nil {
+		return err
+	}
+	return doTx(stake.NewTxDelegate(validator, amount))
+}
+
+func cmdUnbond(cmd *cobra.Command, args []string) error {
+	validator, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(args[1])
+	if err != nil {
+		return err
+	}
+	return doTx(stake.NewTxWithdraw(validator, amount))
+}
+
+func cmdRedelegate(cmd *cobra.Command, args []string) error {
+	from, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	to, err := parseAddress(args[1])
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(args[2])
+	if err != nil {
+		return err
+	}
+	return doTx(stake.NewTxRedelegate(from, to, amount))
+}
+
+func cmdProposeParamChange(cmd *cobra.Command, args []string) error {
+	changes := make([]params.ParamChange, len(args))
+	for i, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid param change %q, want key=value", arg)
+		}
+		changes[i] = params.ParamChange{Key: kv[0], Value: kv[1]}
+	}
+	return doTx(params.NewTxChangeParams(changes...))
+}
+
+func cmdProposeUpgrade(cmd *cobra.Command, args []string) error {
+	height, err := parseHeight(args[1])
+	if err != nil {
+		return err
+	}
+	return doTx(upgrade.NewTxSchedule(args[0], height, viper.GetString(FlagInfo)))
+}
+
+func cmdProposeSpend(cmd *cobra.Command, args []string) error {
+	recipient, err := parseAddress(args[0])
+	if err != nil {
+		return err
+	}
+	amount, err := parseAmount(args[1])
+	if err != nil {
+		return err
+	}
+	height, err := parseHeight(args[2])
+	if err != nil {
+		return err
+	}
+	return doTx(community.NewTxSpend(recipient, amount, height, viper.GetString(FlagReason)))
+}
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/dora/ultron/addressbook"
 	"github.com/dora/ultron/const"
 	"github.com/dora/ultron/backend/ethereum"
 	"github.com/ethereum/go-ethereum/accounts"
@@ -43,8 +44,13 @@ func MakeAccountManager() (*accounts.Manager, string, error) {
 	return accounts.NewManager(backends...), ephemeral, nil
 }
 
-// fetchKeystore retrives the encrypted keystore from the account manager.
-func fetchKeystore(am *accounts.Manager) *keystore.KeyStore {
+// OpenAddressBook returns the watch-only accounts kept next to the keystore.
+func OpenAddressBook() *addressbook.Book {
+	return addressbook.Open(filepath.Join(emHome, addressbook.File))
+}
+
+// FetchKeystore retrives the encrypted keystore from the account manager.
+func FetchKeystore(am *accounts.Manager) *keystore.KeyStore {
 	return am.Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
 }
 
@@ -58,7 +64,7 @@ func UnlockAccount(am *accounts.Manager, addr common.Address, password string, d
 	} else {
 		d = time.Duration(*duration) * time.Second
 	}
-	err := fetchKeystore(am).TimedUnlock(accounts.Account{Address: addr}, password, d)
+	err := FetchKeystore(am).TimedUnlock(accounts.Account{Address: addr}, password, d)
 	return err == nil, err
 }
 
@@ -67,6 +73,13 @@ func Transfer(from, to common.Address, amount *big.Int) error {
 	return nil
 }
 
+// TransferAll moves the whole balance of from, as it is when the block is
+// committed.
+func TransferAll(from, to common.Address) error {
+	ethereum.SubmitRemittance(ethereum.Remittance{From: from, To: to})
+	return nil
+}
+
 func GetBalance(ethereum *eth.Ethereum, addr common.Address) (*big.Int, error) {
 	state, err := ethereum.BlockChain().State()
 	if err != nil {
@@ -0,0 +1,123 @@
+// Package compat guards the determinism of the node across releases. A
+// record is the blocks a node ran, its tendermint databases, along with the
+// genesis of the chain and the app hash it committed each block with. A
+// later binary runs the blocks of the record again from genesis and must
+// commit every one of them with the same app hash.
+package compat
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Version is the version of the records written.
+const Version = 1
+
+// files of a record, next to the tendermint databases under data
+const (
+	ManifestFile   = "compat.json"
+	GenesisFile    = "genesis.json"     // of tendermint and the app
+	EthGenesisFile = "eth-genesis.json" // of ethereum
+)
+
+// Manifest describes a record.
+type Manifest struct {
+	Version  int       `json:"version"`
+	Release  string    `json:"release"` // of the binary recording the blocks
+	Recorded time.Time `json:"recorded"`
+	ChainID  string    `json:"chainId"`
+	Height   int64     `json:"height"` // of the last block recorded
+
+	// the app hash of every block, the one of height h at h-1
+	AppHashes []hexutil.Bytes `json:"appHashes"`
+}
+
+// AppHash returns the app hash the block of height was committed with.
+func (m *Manifest) AppHash(height int64) []byte {
+	if height < 1 || height > int64(len(m.AppHashes)) {
+		return nil
+	}
+	return m.AppHashes[height-1]
+}
+
+// WriteManifest writes m into the record dir.
+func WriteManifest(dir string, m *Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, ManifestFile), data, 0644)
+}
+
+// ReadManifest reads the manifest of the record dir.
+func ReadManifest(dir string) (*Manifest, error) {
+	file := filepath.Join(dir, ManifestFile)
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	if m.Version == 0 || m.Version > Version {
+		return nil, fmt.Errorf("%s: unknown version %d", file, m.Version)
+	}
+	if int64(len(m.AppHashes)) != m.Height {
+		return nil, fmt.Errorf("%s: %d app hashes for %d blocks", file, len(m.AppHashes), m.Height)
+	}
+	return &m, nil
+}
+
+// Mismatch is a block committed with another app hash than the recorded one.
+type Mismatch struct {
+	Height  int64
+	Release string // that recorded it
+	Want    []byte
+	Got     []byte
+}
+
+func (m *Mismatch) Error() string {
+	return fmt.Sprintf("block %d committed with app hash %X, %s committed it with %X", m.Height, m.Got, m.Release, m.Want)
+}
+
+// Checker checks the app hashes of the blocks run again from genesis
+// against those of a manifest.
+type Checker struct {
+	m       *Manifest
+	checked int64
+}
+
+// NewChecker returns a checker of the blocks of m.
+func NewChecker(m *Manifest) *Checker {
+	return &Checker{m: m}
+}
+
+// Commit checks the app hash of the next block committed, the blocks past
+// the ones recorded aren't checked.
+func (c *Checker) Commit(appHash []byte) error {
+	if c.checked >= c.m.Height {
+		return nil
+	}
+	c.checked++
+	if want := c.m.AppHash(c.checked); !bytes.Equal(want, appHash) {
+		return &Mismatch{Height: c.checked, Release: c.m.Release, Want: want, Got: appHash}
+	}
+	return nil
+}
+
+// Checked returns the height of the last block checked.
+func (c *Checker) Checked() int64 {
+	return c.checked
+}
+
+// Done tells whether all the blocks recorded were checked.
+func (c *Checker) Done() bool {
+	return c.checked == c.m.Height
+}
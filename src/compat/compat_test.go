@@ -0,0 +1,52 @@
+package compat
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compat")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	m := &Manifest{Version: Version, Release: "0.9.0", ChainID: "test", Height: 2,
+		AppHashes: []hexutil.Bytes{{0xaa}, {0xbb}}}
+	require.NoError(t, WriteManifest(dir, m))
+	read, err := ReadManifest(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xbb}, read.AppHash(2))
+	assert.Nil(t, read.AppHash(3))
+
+	m.Height = 3
+	require.NoError(t, WriteManifest(dir, m))
+	_, err = ReadManifest(dir)
+	assert.Error(t, err)
+}
+
+func TestChecker(t *testing.T) {
+	m := &Manifest{Release: "0.9.0", Height: 2, AppHashes: []hexutil.Bytes{{0xaa}, {0xbb}}}
+
+	c := NewChecker(m)
+	require.NoError(t, c.Commit([]byte{0xaa}))
+	assert.False(t, c.Done())
+	require.NoError(t, c.Commit([]byte{0xbb}))
+	assert.True(t, c.Done())
+	// blocks past the record aren't checked
+	assert.NoError(t, c.Commit([]byte{0xcc}))
+	assert.Equal(t, int64(2), c.Checked())
+
+	c = NewChecker(m)
+	require.NoError(t, c.Commit([]byte{0xaa}))
+	err := c.Commit([]byte{0xcc})
+	require.Error(t, err)
+	mismatch, ok := err.(*Mismatch)
+	require.True(t, ok)
+	assert.Equal(t, int64(2), mismatch.Height)
+	assert.Equal(t, []byte{0xbb}, mismatch.Want)
+}
@@ -0,0 +1,73 @@
+package compat
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ExportGenesis returns the ethereum genesis the chaindata db was set up
+// with, its allocation read back from the state of the genesis block.
+func ExportGenesis(db ethdb.Database) (*core.Genesis, error) {
+	hash := core.GetCanonicalHash(db, 0)
+	header := core.GetHeader(db, hash, 0)
+	if header == nil {
+		return nil, fmt.Errorf("no genesis block")
+	}
+	config, err := core.GetChainConfig(db, hash)
+	if err != nil {
+		return nil, fmt.Errorf("chain config of the genesis: %v", err)
+	}
+	statedb, err := state.New(header.Root, state.NewDatabase(db))
+	if err != nil {
+		return nil, fmt.Errorf("state of the genesis: %v", err)
+	}
+	alloc, err := dumpAlloc(statedb.RawDump())
+	if err != nil {
+		return nil, err
+	}
+	return &core.Genesis{
+		Config:     config,
+		Nonce:      header.Nonce.Uint64(),
+		Timestamp:  header.Time.Uint64(),
+		ExtraData:  header.Extra,
+		GasLimit:   header.GasLimit.Uint64(),
+		Difficulty: header.Difficulty,
+		Mixhash:    header.MixDigest,
+		Coinbase:   header.Coinbase,
+		Alloc:      alloc,
+	}, nil
+}
+
+// dumpAlloc turns the dump of a state into the allocation setting it up
+func dumpAlloc(dump state.Dump) (core.GenesisAlloc, error) {
+	alloc := make(core.GenesisAlloc, len(dump.Accounts))
+	for key, acc := range dump.Accounts {
+		if len(common.FromHex(key)) != common.AddressLength {
+			return nil, fmt.Errorf("no preimage of the genesis account %s", key)
+		}
+		balance, ok := new(big.Int).SetString(acc.Balance, 10)
+		if !ok {
+			return nil, fmt.Errorf("balance %q of the genesis account %s", acc.Balance, key)
+		}
+		account := core.GenesisAccount{Balance: balance, Nonce: acc.Nonce, Code: common.FromHex(acc.Code)}
+		for slot, value := range acc.Storage {
+			// the values are kept rlp encoded in the storage trie
+			var content []byte
+			if err := rlp.DecodeBytes(common.FromHex(value), &content); err != nil {
+				return nil, fmt.Errorf("storage %s of the genesis account %s: %v", slot, key, err)
+			}
+			if account.Storage == nil {
+				account.Storage = make(map[common.Hash]common.Hash)
+			}
+			account.Storage[common.HexToHash(slot)] = common.BytesToHash(content)
+		}
+		alloc[common.HexToAddress(key)] = account
+	}
+	return alloc, nil
+}
@@ -19,17 +19,24 @@ package web3ext
 
 var Modules = map[string]string{
 	"admin":      Admin_JS,
+	"bank":       Bank_JS,
 	"chequebook": Chequebook_JS,
 	"clique":     Clique_JS,
 	"debug":      Debug_JS,
 	"eth":        Eth_JS,
+	"evm":        EVM_JS,
 	"miner":      Miner_JS,
 	"net":        Net_JS,
 	"personal":   Personal_JS,
 	"rpc":        RPC_JS,
 	"shh":        Shh_JS,
+	"stake":      Stake_JS,
+	"params":     Params_JS,
+	"emergency":  Emergency_JS,
+	"community":  Community_JS,
 	"swarmfs":    SWARMFS_JS,
 	"txpool":     TxPool_JS,
+	"ultron":     Ultron_JS,
 }
 
 const Chequebook_JS = `
@@ -162,6 +169,27 @@ web3._extend({
 		new web3._extend.Method({
 			name: 'stopWS',
 			call: 'admin_stopWS'
+		}),
+		new web3._extend.Method({
+			name: 'banPeer',
+			call: 'admin_banPeer',
+			params: 3,
+			inputFormatter: [null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'unbanPeer',
+			call: 'admin_unbanPeer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'dumpMempool',
+			call: 'admin_dumpMempool',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'loadMempool',
+			call: 'admin_loadMempool',
+			params: 1
 		})
 	],
 	properties:
@@ -177,6 +205,10 @@ web3._extend({
 		new web3._extend.Property({
 			name: 'datadir',
 			getter: 'admin_datadir'
+		}),
+		new web3._extend.Property({
+			name: 'bannedPeers',
+			getter: 'admin_bannedPeers'
 		})
 	]
 });
@@ -350,6 +382,32 @@ web3._extend({
 			call: 'debug_storageRangeAt',
 			params: 5,
 		}),
+		new web3._extend.Method({
+			name: 'verifyStateCleanup',
+			call: 'debug_verifyStateCleanup',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'gasAudit',
+			call: 'debug_gasAudit',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'queryStats',
+			call: 'debug_queryStats',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'storePruning',
+			call: 'debug_storePruning',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'resetQueryStats',
+			call: 'debug_resetQueryStats',
+			params: 0
+		}),
 	],
 	properties: []
 });
@@ -384,6 +442,17 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'getBlockReceipts',
+			call: 'eth_getBlockReceipts',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionReceipts',
+			call: 'eth_getTransactionReceipts',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransaction',
 			call: 'eth_getRawTransactionByHash',
@@ -496,6 +565,34 @@ web3._extend({
 			name: 'deriveAccount',
 			call: 'personal_deriveAccount',
 			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'signTransaction',
+			call: 'personal_signTransaction',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'signTypedData',
+			call: 'personal_signTypedData',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'ecRecoverTypedData',
+			call: 'personal_ecRecoverTypedData',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'watchAddress',
+			call: 'personal_watchAddress',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'unwatchAddress',
+			call: 'personal_unwatchAddress',
+			params: 1
 		})
 	],
 	properties:
@@ -503,6 +600,10 @@ web3._extend({
 		new web3._extend.Property({
 			name: 'listWallets',
 			getter: 'personal_listWallets'
+		}),
+		new web3._extend.Property({
+			name: 'watchedAccounts',
+			getter: 'personal_watchedAccounts'
 		})
 	]
 })
@@ -695,3 +796,268 @@ web3._extend({
 	]
 });
 `
+
+const Ultron_JS = `
+web3._extend({
+	property: 'ultron',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'mineBlock',
+			call: 'ultron_mineBlock',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal],
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
+		new web3._extend.Method({
+			name: 'setNextBlockTimestamp',
+			call: 'ultron_setNextBlockTimestamp',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'increaseTime',
+			call: 'ultron_increaseTime',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'resolveName',
+			call: 'ultron_resolveName',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'upgrade',
+			call: 'ultron_upgrade',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'chainRules',
+			call: 'ultron_chainRules',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'proposerEarnings',
+			call: 'ultron_proposerEarnings',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'schema',
+			call: 'ultron_schema',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionProof',
+			call: 'ultron_getTransactionProof',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'finalizedBlock',
+			call: 'ultron_finalizedBlock',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'ledger',
+			call: 'ultron_ledger',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'createAccessList',
+			call: 'ultron_createAccessList',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'orderingViolations',
+			call: 'ultron_orderingViolations',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+		new web3._extend.Method({
+			name: 'supply',
+			call: 'ultron_supply',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'nodeInfo',
+			call: 'ultron_nodeInfo',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'broadcastTransaction',
+			call: 'ultron_broadcastTransaction',
+			params: 3
+		}),
+		new web3._extend.Method({
+			name: 'getBlocks',
+			call: 'ultron_getBlocks',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+	]
+});
+`
+
+const Bank_JS = `
+web3._extend({
+	property: 'bank',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'getAsset',
+			call: 'bank_getAsset',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getBalance',
+			call: 'bank_getBalance',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputAddressFormatter],
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
+	]
+});
+`
+
+const EVM_JS = `
+web3._extend({
+	property: 'evm',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'snapshot',
+			call: 'evm_snapshot',
+			params: 0,
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
+		new web3._extend.Method({
+			name: 'revert',
+			call: 'evm_revert',
+			params: 1,
+			inputFormatter: [web3._extend.utils.fromDecimal]
+		}),
+	]
+});
+`
+
+const Stake_JS = `
+web3._extend({
+	property: 'stake',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'projectedAPR',
+			call: 'stake_projectedAPR',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'pendingUnbondings',
+			call: 'stake_pendingUnbondings',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'redelegations',
+			call: 'stake_redelegations',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'downtime',
+			call: 'stake_downtime',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'validators',
+			call: 'stake_validators',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'validatorsAt',
+			call: 'stake_validatorsAt',
+			params: 3,
+			inputFormatter: [null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'epoch',
+			call: 'stake_epoch',
+			params: 1,
+			inputFormatter: [null]
+		}),
+		new web3._extend.Method({
+			name: 'minter',
+			call: 'stake_minter',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'performance',
+			call: 'stake_performance',
+			params: 0
+		}),
+	]
+});
+`
+
+const Params_JS = `
+web3._extend({
+	property: 'params',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'get',
+			call: 'params_get',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'list',
+			call: 'params_list',
+			params: 0
+		}),
+	]
+});
+`
+
+const Emergency_JS = `
+web3._extend({
+	property: 'emergency',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'pauses',
+			call: 'emergency_pauses',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'proposals',
+			call: 'emergency_proposals',
+			params: 0
+		}),
+	]
+});
+`
+
+const Community_JS = `
+web3._extend({
+	property: 'community',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'pool',
+			call: 'community_pool',
+			params: 0,
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
+		new web3._extend.Method({
+			name: 'spends',
+			call: 'community_spends',
+			params: 0
+		}),
+	]
+});
+`
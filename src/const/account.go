@@ -5,6 +5,11 @@ import (
 )
 
 var (
-	MintAccount  = common.HexToAddress("0000000000000000000000000000000000000000")
-	StakeAccount = common.HexToAddress("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF")
+	MintAccount       = common.HexToAddress("0000000000000000000000000000000000000000")
+	StakeAccount      = common.HexToAddress("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF")
+	FeeReserveAccount = common.HexToAddress("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFE") // swaps fee tokens for ether
+	RentAccount       = common.HexToAddress("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFD") // holds storage deposits
+	SystemAccount     = common.HexToAddress("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFC") // sends the begin and end block calls
+	BurnAccount       = common.HexToAddress("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFB") // ether sent to it is destroyed at the end of the block
+	CommunityAccount  = common.HexToAddress("FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFA") // holds the community pool
 )
\ No newline at end of file
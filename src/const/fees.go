@@ -0,0 +1,6 @@
+package constant
+
+// BaseGasPrice is the gas price txs are expected to pay at least, in wei.
+// The fee at this price is shared among the validators, the part of the gas
+// price above it tips the block proposer.
+const BaseGasPrice = 2e9 // 2 Gwei
@@ -1,5 +1,15 @@
 package constant
 
 const (
-	ModuleNameStake = "stake"
-)
\ No newline at end of file
+	ModuleNameStake     = "stake"
+	ModuleNameOracle    = "oracle"
+	ModuleNameNames     = "names"
+	ModuleNameBank      = "bank"
+	ModuleNameRecovery  = "recovery"
+	ModuleNameRent      = "rent"
+	ModuleNameWasm      = "wasm"
+	ModuleNameParams    = "params"
+	ModuleNameEmergency = "emergency"
+	ModuleNameUpgrade   = "upgrade"
+	ModuleNameCommunity = "community"
+)
@@ -0,0 +1,106 @@
+// Package dbverify checks the databases of a stopped node: the ethereum
+// state trie of the head block, with the storage tries and the code of
+// its accounts, and the iavl tree of the app state at its latest version.
+// Every trie node read is hashed again against the hash referring to it,
+// and every key of the app state is proven against its root, so a flipped
+// bit is found as well as a missing node. What is corrupt is reported as
+// ranges of keys, along with how to repair it.
+package dbverify
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Databases, as the issues name them
+const (
+	StateDB = "state" // the ethereum state trie, in the chaindata
+	StoreDB = "store" // the iavl tree of the app, in merkleeyes.db
+)
+
+// Issue is a corrupt part of a database.
+type Issue struct {
+	DB    string `json:"db"`
+	Trie  string `json:"trie,omitempty"` // in the state, the trie it is in
+	Range string `json:"range"`          // of the keys affected
+	Error string `json:"error"`
+}
+
+func (i Issue) String() string {
+	where := i.DB
+	if i.Trie != "" {
+		where += " " + i.Trie
+	}
+	return fmt.Sprintf("%s, keys %s: %s", where, i.Range, i.Error)
+}
+
+// Report is what a verification found.
+type Report struct {
+	Block     uint64 `json:"block"`     // head of the chaindata
+	StateRoot string `json:"stateRoot"` // of the head
+	Nodes     int    `json:"nodes"`     // state trie nodes hashed
+	Accounts  int    `json:"accounts"`
+	Slots     int    `json:"slots"` // storage slots
+	Codes     int    `json:"codes"`
+
+	StoreVersion int64  `json:"storeVersion"`
+	StoreRoot    string `json:"storeRoot"`
+	StoreKeys    int    `json:"storeKeys"`
+
+	Issues []Issue `json:"issues"`
+	seen   map[Issue]bool
+}
+
+func (r *Report) add(issue Issue) {
+	if r.seen == nil {
+		r.seen = make(map[Issue]bool)
+	}
+	if !r.seen[issue] {
+		r.seen[issue] = true
+		r.Issues = append(r.Issues, issue)
+	}
+}
+
+// OK tells if no issue was found.
+func (r *Report) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Suggestions returns how to repair the issues of r.
+func (r *Report) Suggestions() []string {
+	var state, store bool
+	for _, issue := range r.Issues {
+		state = state || issue.DB == StateDB
+		store = store || issue.DB == StoreDB
+	}
+	var s []string
+	if state {
+		s = append(s, fmt.Sprintf("The state of block %d is damaged: restore a backup taken before the damage "+
+			"(ultron backup restore), or sync the node again from an empty home. The node only recovers by "+
+			"itself, rewinding at start, when the root of the head state is what is missing.", r.Block))
+	}
+	if store {
+		s = append(s, "The app state is damaged and the other nodes can't send it: restore a backup "+
+			"(ultron backup restore), or remove data/ and ultron/chaindata/ and sync the chain from the start.")
+	}
+	if state || store {
+		s = append(s, "Check the disk (smartctl, the kernel log) before restoring: a failing disk damages "+
+			"the restored databases too.")
+	}
+	return s
+}
+
+// nibbleRange returns the range of the hex keys starting with the nibbles
+// of path
+func nibbleRange(path []byte) string {
+	var b bytes.Buffer
+	for _, n := range path {
+		if n < 16 {
+			b.WriteByte("0123456789abcdef"[n])
+		}
+	}
+	if b.Len() == 0 {
+		return "all"
+	}
+	return "0x" + b.String() + "…"
+}
@@ -0,0 +1,136 @@
+package dbverify
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var (
+	emptyRoot     = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	emptyCodeHash = crypto.Keccak256(nil)
+)
+
+// VerifyHeadState verifies the state of the head block of the chaindata
+// db, see VerifyState.
+func VerifyHeadState(db ethdb.Database, report *Report) error {
+	hash := core.GetHeadBlockHash(db)
+	if hash == (common.Hash{}) {
+		return fmt.Errorf("no head block")
+	}
+	number := core.GetBlockNumber(db, hash)
+	header := core.GetHeader(db, hash, number)
+	if header == nil {
+		return fmt.Errorf("no header of the head block %x", hash)
+	}
+	report.Block = number
+	VerifyState(db, header.Root, report)
+	return nil
+}
+
+// VerifyState walks the state trie of root in db, the storage tries and
+// the code of its accounts, hashing every node again. A missing subtree is
+// reported and skipped, the walk going on with the keys after it.
+func VerifyState(db ethdb.Database, root common.Hash, report *Report) {
+	report.StateRoot = root.Hex()
+	walkTrie(db, root, "accounts", report, func(key, blob []byte) {
+		report.Accounts++
+		var account state.Account
+		if err := rlp.DecodeBytes(blob, &account); err != nil {
+			report.add(Issue{DB: StateDB, Trie: "accounts", Range: fmt.Sprintf("%x", key), Error: err.Error()})
+			return
+		}
+		if account.Root != emptyRoot {
+			walkTrie(db, account.Root, fmt.Sprintf("storage of %x", key), report, func(key, blob []byte) {
+				report.Slots++
+			})
+		}
+		if !bytes.Equal(account.CodeHash, emptyCodeHash) {
+			report.Codes++
+			code, err := db.Get(account.CodeHash)
+			switch {
+			case err != nil:
+				report.add(Issue{DB: StateDB, Trie: "code", Range: fmt.Sprintf("%x", key), Error: "code missing"})
+			case !bytes.Equal(crypto.Keccak256(code), account.CodeHash):
+				report.add(Issue{DB: StateDB, Trie: "code", Range: fmt.Sprintf("%x", key), Error: "code corrupt"})
+			}
+		}
+	})
+}
+
+// walkTrie calls leaf with the key and the value of the leaves of the trie
+// of root, reporting its corrupt nodes as the trie name
+func walkTrie(db ethdb.Database, root common.Hash, name string, report *Report, leaf func(key, blob []byte)) {
+	var start, lastMissing []byte
+	for {
+		tr, err := trie.New(root, db)
+		if err != nil {
+			report.add(Issue{DB: StateDB, Trie: name, Range: "all", Error: err.Error()})
+			return
+		}
+		it := tr.NodeIterator(start)
+		for it.Next(true) {
+			if hash := it.Hash(); hash != (common.Hash{}) {
+				report.Nodes++
+				if blob, err := db.Get(hash[:]); err == nil && crypto.Keccak256Hash(blob) != hash {
+					report.add(Issue{DB: StateDB, Trie: name, Range: nibbleRange(it.Path()),
+						Error: fmt.Sprintf("node %x corrupt", hash)})
+				}
+			}
+			if it.Leaf() {
+				leaf(nibblesToKey(it.Path()), it.LeafBlob())
+			}
+		}
+		err = it.Error()
+		if err == nil {
+			return
+		}
+		missing, ok := err.(*trie.MissingNodeError)
+		if !ok || bytes.Equal(missing.Path, lastMissing) {
+			report.add(Issue{DB: StateDB, Trie: name, Range: nibbleRange(it.Path()) + " and after", Error: err.Error()})
+			return
+		}
+		report.add(Issue{DB: StateDB, Trie: name, Range: nibbleRange(missing.Path),
+			Error: fmt.Sprintf("node %x missing", missing.NodeHash)})
+		// on with the keys after the subtree missing
+		lastMissing = missing.Path
+		if start = nextKey(missing.Path); start == nil {
+			return
+		}
+	}
+}
+
+// nibblesToKey packs the nibbles of the path of a leaf into its key
+func nibblesToKey(path []byte) []byte {
+	if n := len(path); n > 0 && path[n-1] == 16 {
+		path = path[:n-1]
+	}
+	key := make([]byte, (len(path)+1)/2)
+	for i, n := range path {
+		key[i/2] |= n << (4 * uint(1-i%2))
+	}
+	return key
+}
+
+// nextKey returns the first key after the ones starting with the nibbles
+// of path, nil if there is none
+func nextKey(path []byte) []byte {
+	next := append([]byte{}, path...)
+	if n := len(next); n > 0 && next[n-1] == 16 {
+		next = next[:n-1]
+	}
+	for i := len(next) - 1; i >= 0; i-- {
+		if next[i] < 15 {
+			next[i]++
+			return nibblesToKey(next[:i+1])
+		}
+	}
+	return nil
+}
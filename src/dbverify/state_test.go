@@ -0,0 +1,55 @@
+package dbverify
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNextKey(t *testing.T) {
+	assert.Equal(t, []byte{0x12, 0x34}, nibblesToKey([]byte{1, 2, 3, 4, 16}))
+	assert.Equal(t, []byte{0x13}, nextKey([]byte{1, 2}))
+	assert.Equal(t, []byte{0x20}, nextKey([]byte{1, 15}))
+	assert.Equal(t, []byte{0x20}, nextKey([]byte{1, 15, 15}))
+	assert.Nil(t, nextKey([]byte{15, 15}))
+	assert.Equal(t, "0x1f…", nibbleRange([]byte{1, 15}))
+}
+
+func TestWalkTrie(t *testing.T) {
+	db, err := ethdb.NewMemDatabase()
+	require.NoError(t, err)
+	tr, err := trie.New(common.Hash{}, db)
+	require.NoError(t, err)
+	for i := 0; i < 200; i++ {
+		tr.Update(crypto.Keccak256([]byte{byte(i)}), bytes.Repeat([]byte{byte(i)}, 40))
+	}
+	root, err := tr.Commit()
+	require.NoError(t, err)
+
+	report := new(Report)
+	leaves := 0
+	walkTrie(db, root, "test", report, func(key, blob []byte) { leaves++ })
+	assert.True(t, report.OK())
+	assert.Equal(t, 200, leaves)
+
+	// a node dropped, the walk goes on past it
+	for _, key := range db.Keys() {
+		if !bytes.Equal(key, root[:]) {
+			require.NoError(t, db.Delete(key))
+			break
+		}
+	}
+	report = new(Report)
+	leaves = 0
+	walkTrie(db, root, "test", report, func(key, blob []byte) { leaves++ })
+	require.Len(t, report.Issues, 1)
+	assert.Equal(t, StateDB, report.Issues[0].DB)
+	assert.True(t, leaves > 0 && leaves < 200)
+	assert.NotEmpty(t, report.Suggestions())
+}
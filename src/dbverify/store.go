@@ -0,0 +1,83 @@
+package dbverify
+
+import (
+	"bytes"
+	"fmt"
+
+	sm "github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/ethdb"
+
+	"github.com/dora/ultron/backend/ethereum"
+)
+
+// VerifyStore proves every key of the latest version of the app state st
+// against its root. The keys failing are reported in ranges of keys in a
+// row. A node missing stops the walk, the iavl tree panicking on it.
+func VerifyStore(st *sm.State, report *Report) {
+	tree := st.Committed()
+	height := st.LatestHeight()
+	root := st.LatestHash()
+	report.StoreVersion = height
+	report.StoreRoot = fmt.Sprintf("%X", root)
+
+	var from, to, last []byte
+	var firstErr error
+	flush := func() {
+		if from != nil {
+			report.add(Issue{DB: StoreDB, Range: fmt.Sprintf("%x to %x", from, to), Error: firstErr.Error()})
+			from, to, firstErr = nil, nil, nil
+		}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			flush()
+			report.add(Issue{DB: StoreDB, Range: fmt.Sprintf("after %x", last), Error: fmt.Sprint(r)})
+		}
+	}()
+	tree.Tree.Iterate(func(key, value []byte) bool {
+		report.StoreKeys++
+		last = key
+		if err := proveKey(tree, key, value, root, height); err != nil {
+			if from == nil {
+				from, firstErr = key, err
+			}
+			to = key
+			return false
+		}
+		flush()
+		return false
+	})
+	flush()
+}
+
+// proveKey checks value is what the tree holds under key at height, and
+// the proof of it leads to root
+func proveKey(tree *sm.Bonsai, key, value, root []byte, height int64) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+	proven, proof, err := tree.GetVersionedWithProof(key, height)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(proven, value) {
+		return fmt.Errorf("value differs from the one proven")
+	}
+	return proof.Verify(key, value, root)
+}
+
+// VerifyBlockHash checks the app state holds the hash of the head block
+// of the chaindata db, when it holds hashes of blocks at all.
+func VerifyBlockHash(db ethdb.Database, st *sm.State, report *Report) {
+	hash := core.GetHeadBlockHash(db)
+	number := core.GetBlockNumber(db, hash)
+	stored := st.Committed().Get(ethereum.BlockHashKey(number))
+	if stored != nil && common.BytesToHash(stored) != hash {
+		report.add(Issue{DB: StoreDB, Range: fmt.Sprintf("%x", ethereum.BlockHashKey(number)),
+			Error: fmt.Sprintf("holds %x as the hash of block %d, the chaindata %x", stored, number, hash)})
+	}
+}
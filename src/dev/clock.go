@@ -0,0 +1,62 @@
+package dev
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Clock shifts the block time of a dev chain away from the consensus time,
+// so time dependent contracts can be tested without waiting. Times are unix
+// seconds.
+type Clock struct {
+	mtx    sync.Mutex
+	offset int64  // added to the consensus time
+	next   uint64 // time of the next block, 0 when unset
+	last   uint64 // time of the last block
+}
+
+// NewClock returns a clock following the consensus time.
+func NewClock() *Clock {
+	return &Clock{}
+}
+
+// BlockTime returns the time of the block the consensus made at t. A time
+// set for the next block moves the clock there for good. Block times never
+// go backwards.
+func (c *Clock) BlockTime(t uint64) uint64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.next != 0 {
+		c.offset = int64(c.next) - int64(t)
+		c.next = 0
+	}
+	blockTime := uint64(int64(t) + c.offset)
+	if blockTime < c.last {
+		blockTime = c.last
+	}
+	c.last = blockTime
+	return blockTime
+}
+
+// SetNextBlockTimestamp makes the next block carry time t.
+func (c *Clock) SetNextBlockTimestamp(t uint64) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if t <= c.last {
+		return fmt.Errorf("timestamp %d is not after the last block time %d", t, c.last)
+	}
+	c.next = t
+	return nil
+}
+
+// IncreaseTime moves the clock forward by seconds and returns the total
+// shift from the consensus time.
+func (c *Clock) IncreaseTime(seconds uint64) int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.offset += int64(seconds)
+	return c.offset
+}
@@ -0,0 +1,24 @@
+package dev
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock(t *testing.T) {
+	c := NewClock()
+	assert.Equal(t, uint64(100), c.BlockTime(100))
+
+	assert.Equal(t, int64(60), c.IncreaseTime(60))
+	assert.Equal(t, uint64(161), c.BlockTime(101))
+
+	assert.NotNil(t, c.SetNextBlockTimestamp(161))
+	assert.Nil(t, c.SetNextBlockTimestamp(1000))
+	assert.Equal(t, uint64(1000), c.BlockTime(102))
+	// later blocks go on from the set time
+	assert.Equal(t, uint64(1001), c.BlockTime(103))
+
+	// consensus time going back doesn't move blocks back
+	assert.Equal(t, uint64(1001), c.BlockTime(90))
+}
@@ -0,0 +1,122 @@
+// Package dev holds the tooling of dev chains, nodes run by tests and dev
+// consoles that want to drive the chain instead of following consensus.
+package dev
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Miner holds block production until blocks are asked for. The consensus
+// waits in Committed after every block, so a requested block takes in what
+// the mempool holds at that moment and nothing depends on consensus timeouts.
+type Miner struct {
+	mtx       sync.Mutex
+	cond      *sync.Cond
+	permits   uint64
+	commits   uint64
+	waiting   bool // the consensus waits in Committed
+	stopped   bool
+	replaying bool // see Replay
+}
+
+// NewMiner returns a miner holding the next block.
+func NewMiner() *Miner {
+	m := &Miner{}
+	m.cond = sync.NewCond(&m.mtx)
+	return m
+}
+
+// Committed is called after every block commit, it blocks until the next
+// block is asked for.
+func (m *Miner) Committed() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.commits++
+	m.cond.Broadcast()
+	for m.permits == 0 && !m.stopped && !m.replaying {
+		m.waiting = true
+		m.cond.Wait()
+	}
+	m.waiting = false
+	if m.permits > 0 {
+		m.permits--
+	}
+}
+
+// Replay lets the commits through until done is called, for the blocks
+// the node replays to the app in its handshake as it starts. Holding them
+// would keep the node from starting.
+func (m *Miner) Replay() (done func()) {
+	m.mtx.Lock()
+	m.replaying = true
+	m.mtx.Unlock()
+	return func() {
+		m.mtx.Lock()
+		m.replaying = false
+		m.mtx.Unlock()
+	}
+}
+
+// Mine asks for n blocks and waits until they are committed.
+func (m *Miner) Mine(n uint64, timeout time.Duration) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if m.stopped {
+		return fmt.Errorf("miner stopped")
+	}
+	target := m.commits + n
+	m.permits += n
+	m.cond.Broadcast()
+
+	expired := false
+	timer := time.AfterFunc(timeout, func() {
+		m.mtx.Lock()
+		expired = true
+		m.cond.Broadcast()
+		m.mtx.Unlock()
+	})
+	defer timer.Stop()
+
+	for m.commits < target && !m.stopped && !expired {
+		m.cond.Wait()
+	}
+	if m.commits < target {
+		return fmt.Errorf("mined %d of %d blocks in %v", n-(target-m.commits), n, timeout)
+	}
+	return nil
+}
+
+// Hold runs fn while the consensus waits for the next block, so fn sees and
+// changes the chain between two blocks.
+func (m *Miner) Hold(fn func() error) error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	for !m.waiting && !m.stopped {
+		m.cond.Wait()
+	}
+	if m.stopped {
+		return fmt.Errorf("miner stopped")
+	}
+	return fn()
+}
+
+// Commits returns the number of blocks committed so far.
+func (m *Miner) Commits() uint64 {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.commits
+}
+
+// Stop lets the consensus run freely again, it must be called before the
+// node stops.
+func (m *Miner) Stop() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.stopped = true
+	m.cond.Broadcast()
+}
@@ -0,0 +1,84 @@
+package dev
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// consensus commits blocks as fast as the miner lets it
+func runConsensus(m *Miner, done chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		default:
+		}
+		m.Committed()
+	}
+}
+
+func TestMinerHoldsBlocks(t *testing.T) {
+	m := NewMiner()
+	done := make(chan struct{})
+	go runConsensus(m, done)
+	defer func() {
+		close(done)
+		m.Stop()
+	}()
+
+	// the first block is committed, then the consensus waits
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, uint64(1), m.Commits())
+
+	assert.Nil(t, m.Mine(3, time.Second))
+	assert.Equal(t, uint64(4), m.Commits())
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, uint64(4), m.Commits())
+
+	// a hold waits for the blocks in flight
+	go m.Mine(2, time.Second)
+	assert.Nil(t, m.Hold(func() error {
+		assert.True(t, m.commits == 4 || m.commits == 6)
+		return nil
+	}))
+}
+
+func TestMinerReplay(t *testing.T) {
+	m := NewMiner()
+
+	// the replayed blocks don't wait
+	replayed := m.Replay()
+	for i := 0; i < 3; i++ {
+		m.Committed()
+	}
+	replayed()
+	assert.Equal(t, uint64(3), m.Commits())
+
+	done := make(chan struct{})
+	go func() {
+		m.Committed()
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("not held after the replay")
+	case <-time.After(50 * time.Millisecond):
+	}
+	m.Stop()
+	<-done
+	assert.Equal(t, uint64(4), m.Commits())
+}
+
+func TestMinerTimeout(t *testing.T) {
+	m := NewMiner()
+	assert.NotNil(t, m.Mine(1, 10*time.Millisecond))
+
+	m.Stop()
+	assert.NotNil(t, m.Mine(1, time.Second))
+	// a stopped miner no longer holds the consensus
+	m.Committed()
+	assert.Equal(t, uint64(1), m.Commits())
+}
@@ -0,0 +1,11 @@
+package dev
+
+// Snapshotter takes and restores snapshots of the chain state. It is called
+// between two blocks only, see Miner.Hold.
+type Snapshotter interface {
+	// Snapshot records the current state and returns its id.
+	Snapshot() (uint64, error)
+	// Revert restores the state of snapshot id, dropping it and the later
+	// snapshots. It reports false for an unknown id.
+	Revert(id uint64) (bool, error)
+}
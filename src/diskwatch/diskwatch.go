@@ -0,0 +1,171 @@
+// Package diskwatch follows the growth of the databases of a node, the
+// ethereum chaindata and the merkleeyes app state, so a node filling its
+// disk, say in a long benchmark, is caught before it stops mid-run. Each
+// time the databases grew by a set amount they are compacted, giving back
+// the space of the app state versions pruned since and of overwritten
+// keys; and an alert fires when the disk would fill up within a set time
+// at the rate they grow.
+package diskwatch
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// rateSamples is how many samples the growth rate is measured over
+const rateSamples = 60
+
+// Database is a database of the node on disk.
+type Database struct {
+	Name    string
+	Dir     string
+	Compact func() error // nil if it can't be compacted
+}
+
+// Config sets when the watcher steps in.
+type Config struct {
+	Interval      time.Duration // between measures
+	CompactGrowth uint64        // bytes the databases grow by between compactions, 0 to never compact
+	AlertHorizon  time.Duration // alert when the disk fills up within it, 0 for no alert
+}
+
+type sample struct {
+	at   time.Time
+	size uint64
+}
+
+// Watcher measures the databases every interval.
+type Watcher struct {
+	conf  Config
+	dbs   []Database
+	alert func(format string, args ...interface{})
+
+	samples   []sample
+	compacted uint64 // size after the last compaction
+	alerted   bool
+	quit      chan struct{}
+	stopOnce  sync.Once
+}
+
+// NewWatcher creates a watcher of dbs calling alert when the disk is about
+// to fill up.
+func NewWatcher(conf Config, dbs []Database, alert func(format string, args ...interface{})) *Watcher {
+	return &Watcher{conf: conf, dbs: dbs, alert: alert, quit: make(chan struct{})}
+}
+
+// Start measures in the background until Stop.
+func (w *Watcher) Start() {
+	go func() {
+		ticker := time.NewTicker(w.conf.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				w.Check(now)
+			case <-w.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the measures.
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.quit) })
+}
+
+// Check measures the databases as of now, compacting them and alerting as
+// configured. It is called by Start, and by tests.
+func (w *Watcher) Check(now time.Time) {
+	size := w.size()
+	if w.compacted == 0 {
+		w.compacted = size
+	}
+	if w.conf.CompactGrowth > 0 && size >= w.compacted+w.conf.CompactGrowth {
+		w.compact()
+		after := w.size()
+		log.Info("Compacted the databases", "before", size>>20, "after", after>>20, "unit", "MB")
+		size, w.compacted = after, after
+	}
+
+	w.samples = append(w.samples, sample{now, size})
+	if len(w.samples) > rateSamples {
+		w.samples = w.samples[1:]
+	}
+	rate := w.Rate()
+	if w.conf.AlertHorizon <= 0 || rate <= 0 || len(w.dbs) == 0 {
+		return
+	}
+	free, err := freeDisk(w.dbs[0].Dir)
+	if err != nil {
+		return
+	}
+	full := float64(free) / rate // seconds
+	filling := full < w.conf.AlertHorizon.Seconds()
+	if filling && !w.alerted {
+		w.alert("the databases take %d MB and grow by %.1f MB an hour, the %d MB left fill up in %s",
+			size>>20, rate*3600/(1<<20), free>>20, time.Duration(full)*time.Second)
+	}
+	w.alerted = filling
+}
+
+// Rate returns the bytes a second the databases grew by over the last
+// samples, compactions aside.
+func (w *Watcher) Rate() float64 {
+	if len(w.samples) < 2 {
+		return 0
+	}
+	first, last := w.samples[0], w.samples[len(w.samples)-1]
+	if last.size <= first.size || !last.at.After(first.at) {
+		return 0
+	}
+	return float64(last.size-first.size) / last.at.Sub(first.at).Seconds()
+}
+
+func (w *Watcher) compact() {
+	for _, db := range w.dbs {
+		if db.Compact == nil {
+			continue
+		}
+		if err := db.Compact(); err != nil {
+			log.Error("Failed to compact database", "name", db.Name, "err", err)
+		}
+	}
+	// a compaction isn't growth
+	w.samples = w.samples[:0]
+}
+
+// size returns the bytes the databases take
+func (w *Watcher) size() uint64 {
+	var total uint64
+	for _, db := range w.dbs {
+		total += dirSize(db.Dir)
+	}
+	return total
+}
+
+// dirSize sums the sizes of the files under dir
+func dirSize(dir string) uint64 {
+	var size uint64
+	filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error { // nolint: errcheck
+		if err == nil && info.Mode().IsRegular() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	return size
+}
+
+// freeDisk returns the bytes free for unprivileged users on the disk of dir
+func freeDisk(dir string) (uint64, error) {
+	var fs syscall.Statfs_t
+	if err := syscall.Statfs(dir, &fs); err != nil {
+		return 0, err
+	}
+	return fs.Bavail * uint64(fs.Bsize), nil
+}
@@ -0,0 +1,55 @@
+package diskwatch
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatcher(t *testing.T) {
+	dir, err := ioutil.TempDir("", "diskwatch")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+
+	write := func(name string, size int) {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), make([]byte, size), 0600))
+	}
+	compactions := 0
+	db := Database{Name: "db", Dir: dir, Compact: func() error {
+		compactions++
+		return os.Remove(filepath.Join(dir, "garbage"))
+	}}
+	var alerts []string
+	w := NewWatcher(Config{CompactGrowth: 1000, AlertHorizon: 1 << 62}, []Database{db}, func(format string, args ...interface{}) {
+		alerts = append(alerts, fmt.Sprintf(format, args...))
+	})
+	start := time.Now()
+
+	write("data", 100)
+	w.Check(start)
+	assert.Zero(t, w.Rate())
+
+	// 500 bytes a second
+	write("garbage", 500)
+	w.Check(start.Add(time.Second))
+	assert.InDelta(t, 500.0, w.Rate(), 0.001)
+	assert.Zero(t, compactions)
+	require.Len(t, alerts, 1)
+
+	// compacted once grown by 1000, alerting only once
+	write("data", 700)
+	w.Check(start.Add(2 * time.Second))
+	assert.Equal(t, 1, compactions)
+	assert.Equal(t, uint64(700), w.compacted)
+	assert.Zero(t, w.Rate())
+	write("data", 800)
+	w.Check(start.Add(3 * time.Second))
+	assert.Equal(t, 1, compactions)
+	assert.Len(t, alerts, 1)
+}
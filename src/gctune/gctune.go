@@ -0,0 +1,63 @@
+// Package gctune sets up the garbage collector of the node. Blocks of
+// thousands of txs allocate a lot in little time, and with the defaults of
+// the runtime the collector runs again and again while the heap is small,
+// each run showing up as jitter in the block times. A higher GC percent
+// spaces the runs out, a ballast raises the heap the next run is paced on
+// without taking memory, and a soft memory limit makes the collector run
+// harder as the heap gets near it rather than the node running out of
+// memory.
+package gctune
+
+import (
+	"os"
+	"runtime/debug"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Config sets up the collector.
+type Config struct {
+	GCPercent int    // as GOGC, -1 turns the collector off
+	Ballast   uint64 // bytes
+	SoftLimit uint64 // bytes, 0 for no limit
+}
+
+// Validator is the profile of a validator: it only runs blocks, so the
+// collector may wait for the heap to triple before running.
+func Validator() Config {
+	return Config{GCPercent: 200, Ballast: 1 << 30}
+}
+
+// RPC is the profile of a replica serving RPC: the queries allocate in
+// bursts the node can't foresee, so the collector runs as usual on a
+// smaller ballast.
+func RPC() Config {
+	return Config{GCPercent: 100, Ballast: 256 << 20}
+}
+
+var (
+	// ballast is never read, it only has to stay reachable. Its pages are
+	// never touched, so it takes no memory.
+	ballast []byte
+	once    sync.Once
+)
+
+// Apply sets up the collector of the process as conf sets. Only the first
+// call counts, the collector being one for all the nodes of a process. A
+// GOGC of the environment wins over the GC percent of conf.
+func Apply(conf Config) {
+	once.Do(func() {
+		if os.Getenv("GOGC") == "" {
+			debug.SetGCPercent(conf.GCPercent)
+		}
+		if conf.Ballast > 0 {
+			ballast = make([]byte, conf.Ballast)
+		}
+		if conf.SoftLimit > 0 {
+			setSoftLimit(conf.SoftLimit)
+		}
+		log.Info("Tuned the garbage collector", "gcpercent", conf.GCPercent, "ballast", conf.Ballast>>20,
+			"softlimit", conf.SoftLimit>>20, "unit", "MB")
+	})
+}
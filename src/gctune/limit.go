@@ -0,0 +1,10 @@
+//go:build go1.19
+// +build go1.19
+
+package gctune
+
+import "runtime/debug"
+
+func setSoftLimit(limit uint64) {
+	debug.SetMemoryLimit(int64(limit))
+}
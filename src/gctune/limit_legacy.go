@@ -0,0 +1,28 @@
+//go:build !go1.19
+// +build !go1.19
+
+package gctune
+
+import (
+	"runtime"
+	"runtime/debug"
+	"time"
+)
+
+// limitInterval is how often the heap is measured against the soft limit
+// by runtimes without one
+const limitInterval = time.Second
+
+// setSoftLimit stands in for the soft memory limit of go 1.19: it collects
+// and gives the memory back to the OS whenever the heap went over limit.
+func setSoftLimit(limit uint64) {
+	go func() {
+		var stats runtime.MemStats
+		for range time.Tick(limitInterval) {
+			runtime.ReadMemStats(&stats)
+			if stats.HeapAlloc > limit {
+				debug.FreeOSMemory()
+			}
+		}
+	}()
+}
@@ -0,0 +1,133 @@
+package genesis
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/pkg/errors"
+)
+
+// Alloc is a bulk allocation of balances read from a file, e.g. the buyers
+// of a token sale, to merge into the alloc of the ethereum genesis.
+type Alloc struct {
+	Balances map[common.Address]*big.Int
+	Total    *big.Int
+}
+
+// ReadAllocFile reads the allocation of path, a csv file of address,balance
+// rows or a json object of "address": balance, by its extension. Balances
+// are in wei, decimal or 0x prefixed hex. Rows are read one by one so files
+// of millions of them fit in memory; a malformed address or balance, or an
+// address listed twice, fails with its row.
+func ReadAllocFile(path string) (*Alloc, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close() // nolint: errcheck
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ReadAllocCSV(f)
+	case ".json":
+		return ReadAllocJSON(f)
+	}
+	return nil, errors.Errorf("unknown allocation format of %s, want .csv or .json", path)
+}
+
+func newAlloc() *Alloc {
+	return &Alloc{Balances: make(map[common.Address]*big.Int), Total: new(big.Int)}
+}
+
+func (a *Alloc) add(address, balance string) error {
+	if !common.IsHexAddress(address) {
+		return errors.Errorf("invalid address %q", address)
+	}
+	balance = strings.TrimSpace(balance)
+	wei, ok := math.ParseBig256(balance)
+	if !ok || balance == "" || wei.Sign() < 0 {
+		return errors.Errorf("invalid balance %q", balance)
+	}
+	addr := common.HexToAddress(address)
+	if _, ok := a.Balances[addr]; ok {
+		return errors.Errorf("%s listed twice", addr.Hex())
+	}
+	a.Balances[addr] = wei
+	a.Total.Add(a.Total, wei)
+	return nil
+}
+
+// ReadAllocCSV reads address,balance rows, under an optional header row.
+func ReadAllocCSV(r io.Reader) (*Alloc, error) {
+	alloc := newAlloc()
+	rows := csv.NewReader(r)
+	rows.FieldsPerRecord = 2
+	rows.TrimLeadingSpace = true
+	for line := 1; ; line++ {
+		row, err := rows.Read()
+		if err == io.EOF {
+			return alloc, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := math.ParseBig256(row[1]); line == 1 && !ok && !common.IsHexAddress(row[0]) {
+			continue // header
+		}
+		if err := alloc.add(row[0], row[1]); err != nil {
+			return nil, errors.Wrapf(err, "line %d", line)
+		}
+	}
+}
+
+// ReadAllocJSON reads an object of "address": balance, the balances being
+// strings or numbers.
+func ReadAllocJSON(r io.Reader) (*Alloc, error) {
+	alloc := newAlloc()
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, errors.New("allocation is no json object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		address := tok.(string) // keys of objects are strings
+		var balance interface{}
+		if err := dec.Decode(&balance); err != nil {
+			return nil, errors.Wrap(err, address)
+		}
+		if err := alloc.add(address, fmt.Sprint(balance)); err != nil {
+			return nil, errors.Wrap(err, address)
+		}
+	}
+	_, err := dec.Token()
+	return alloc, err
+}
+
+// MergeInto adds the balances to genesis, failing if genesis allocates
+// any of their accounts already.
+func (a *Alloc) MergeInto(genesis core.GenesisAlloc) error {
+	for addr := range a.Balances {
+		if _, ok := genesis[addr]; ok {
+			return errors.Errorf("%s already allocated by the genesis", addr.Hex())
+		}
+	}
+	for addr, balance := range a.Balances {
+		genesis[addr] = core.GenesisAccount{Balance: balance}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package genesis
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	addr1 = "0xedac2dfcfe06f30920219221eccc79a300a8d7e1"
+	addr2 = "0x4806202cd62b03be5f6681827d5329409c1e0cdd"
+)
+
+func TestReadAllocCSV(t *testing.T) {
+	alloc, err := ReadAllocCSV(strings.NewReader("address,balance\n" + addr1 + ",100\n" + addr2 + ", 0x10\n"))
+	require.NoError(t, err)
+	assert.Len(t, alloc.Balances, 2)
+	assert.Equal(t, big.NewInt(16), alloc.Balances[common.HexToAddress(addr2)])
+	assert.Equal(t, big.NewInt(116), alloc.Total)
+
+	_, err = ReadAllocCSV(strings.NewReader(addr1 + ",1\n" + addr1 + ",2\n"))
+	assert.Error(t, err, "listed twice")
+	_, err = ReadAllocCSV(strings.NewReader(addr1 + ",-1\n"))
+	assert.Error(t, err, "negative")
+	_, err = ReadAllocCSV(strings.NewReader("0x12,1\n" + addr1 + ",x\n"))
+	assert.Error(t, err, "not a header")
+}
+
+func TestReadAllocJSON(t *testing.T) {
+	alloc, err := ReadAllocJSON(strings.NewReader(`{"` + addr1 + `": "100", "` + addr2 + `": 5}`))
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(105), alloc.Total)
+
+	_, err = ReadAllocJSON(strings.NewReader(`{"` + addr1 + `": {"balance": "1"}}`))
+	assert.Error(t, err)
+
+	genesis := core.GenesisAlloc{common.HexToAddress(addr2): {Balance: big.NewInt(1)}}
+	assert.Error(t, alloc.MergeInto(genesis), "already allocated")
+	delete(genesis, common.HexToAddress(addr2))
+	require.NoError(t, alloc.MergeInto(genesis))
+	assert.Equal(t, big.NewInt(100), genesis[common.HexToAddress(addr1)].Balance)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 
 	cmn "github.com/tendermint/tmlibs/common"
+	"sort"
 	"strconv"
 
 	"github.com/dora/ultron/const"
@@ -67,12 +68,71 @@ func GetOptions(path string) ([]Option, error) {
 	res = append(res, Option{constant.ModuleNameStake, "reserve_requirement_ratio", genDoc.ReserveRequirementRatio})
 	res = append(res, Option{constant.ModuleNameStake, "enable_hybrid_election", strconv.FormatBool(genDoc.EnableHybridElection)})
 	res = append(res, Option{constant.ModuleNameStake, "ticket_price", strconv.FormatUint(genDoc.TicketPrice, 10)})
+	if genDoc.UnbondingBlocks != nil {
+		res = append(res, Option{constant.ModuleNameStake, "unbonding_blocks", strconv.FormatInt(*genDoc.UnbondingBlocks, 10)})
+	}
+	if genDoc.EpochBlocks > 0 {
+		res = append(res, Option{constant.ModuleNameStake, "epoch_blocks", strconv.FormatInt(genDoc.EpochBlocks, 10)})
+	}
 
 	// set validators
 	for _, val := range validators {
 		res = append(res, Option{constant.ModuleNameStake, "validator", string(val)})
 	}
 
+	// set oracle feeders
+	if genDoc.OracleVotePeriod > 0 {
+		res = append(res, Option{constant.ModuleNameOracle, "vote_period", strconv.FormatInt(genDoc.OracleVotePeriod, 10)})
+	}
+	for _, feeder := range genDoc.OracleFeeders {
+		res = append(res, Option{constant.ModuleNameOracle, "feeder", feeder})
+	}
+
+	// set tokens accepted for gas
+	for _, denom := range genDoc.FeeTokens {
+		res = append(res, Option{constant.ModuleNameBank, "fee_token", denom})
+	}
+
+	// set storage rent
+	if genDoc.StorageDeposit != "" {
+		res = append(res, Option{constant.ModuleNameRent, "deposit_per_slot", genDoc.StorageDeposit})
+	}
+	if genDoc.StorageRent != "" {
+		res = append(res, Option{constant.ModuleNameRent, "rent_per_slot", genDoc.StorageRent})
+	}
+	if genDoc.StorageRentPeriod > 0 {
+		res = append(res, Option{constant.ModuleNameRent, "rent_period", strconv.FormatInt(genDoc.StorageRentPeriod, 10)})
+	}
+
+	// set wasm runtime
+	if genDoc.WasmEnabled {
+		res = append(res, Option{constant.ModuleNameWasm, "enabled", "true"})
+	}
+	if genDoc.WasmMaxGas > 0 {
+		res = append(res, Option{constant.ModuleNameWasm, "max_gas", strconv.FormatUint(genDoc.WasmMaxGas, 10)})
+	}
+
+	// set chain params
+	if genDoc.ParamsAuthority != "" {
+		res = append(res, Option{constant.ModuleNameParams, "authority", genDoc.ParamsAuthority})
+	}
+	keys := make([]string, 0, len(genDoc.Params))
+	for key := range genDoc.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		res = append(res, Option{constant.ModuleNameParams, key, genDoc.Params[key]})
+	}
+
+	// set emergency council
+	for _, member := range genDoc.EmergencyCouncil {
+		res = append(res, Option{constant.ModuleNameEmergency, "member", member})
+	}
+	if genDoc.EmergencyThreshold > 0 {
+		res = append(res, Option{constant.ModuleNameEmergency, "threshold", strconv.FormatUint(uint64(genDoc.EmergencyThreshold), 10)})
+	}
+
 	return res, nil
 }
 
@@ -90,6 +150,20 @@ type FullDoc struct {
 	ReserveRequirementRatio string            `json:"reserve_requirement_ratio"`
 	EnableHybridElection    bool              `json:"enable_hybrid_election"`
 	TicketPrice             uint64            `json:"ticket_price"`
+	UnbondingBlocks         *int64            `json:"unbonding_blocks,omitempty"` // 0 pays withdrawals out right away
+	EpochBlocks             int64             `json:"epoch_blocks,omitempty"`     // blocks between validator set updates
+	OracleVotePeriod        int64             `json:"oracle_vote_period,omitempty"`
+	OracleFeeders           []string          `json:"oracle_feeders,omitempty"`
+	FeeTokens               []string          `json:"fee_tokens,omitempty"`
+	StorageDeposit          string            `json:"storage_deposit,omitempty"`     // wei per slot
+	StorageRent             string            `json:"storage_rent,omitempty"`        // wei per slot and block
+	StorageRentPeriod       int64             `json:"storage_rent_period,omitempty"` // blocks between charges
+	WasmEnabled             bool              `json:"wasm_enabled,omitempty"`
+	WasmMaxGas              uint64            `json:"wasm_max_gas,omitempty"`     // gas of one wasm tx
+	ParamsAuthority         string            `json:"params_authority,omitempty"` // account allowed to change the params
+	Params                  map[string]string `json:"params,omitempty"`           // by param key
+	EmergencyCouncil        []string          `json:"emergency_council,omitempty"`
+	EmergencyThreshold      uint16            `json:"emergency_threshold,omitempty"` // council approvals to pause txs
 }
 
 // Doc - All genesis values
@@ -0,0 +1,222 @@
+// Package hashdump reports what a node computed for a block whose app hash
+// the other validators disagree on: the state root after every tx of the
+// ethereum block run again, the accounts the block changed, the keys of the
+// app state it changed and what the app wrote of it to its wal. The reports
+// of two nodes are compared to find where they diverged first.
+package hashdump
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/dora/ultron/appwal"
+)
+
+// Version is the version of the reports written.
+const Version = 1
+
+// Report is what a node computed for a block.
+type Report struct {
+	Version  int           `json:"version"`
+	Node     string        `json:"node"` // moniker
+	Created  time.Time     `json:"created"`
+	Height   int64         `json:"height"`
+	AppHash  hexutil.Bytes `json:"appHash"`            // this node committed the block with
+	Proposed hexutil.Bytes `json:"proposed,omitempty"` // the block of the next height carries
+
+	Block      uint64      `json:"block"` // the ethereum block
+	BlockHash  common.Hash `json:"blockHash"`
+	ParentRoot common.Hash `json:"parentRoot"`
+	Root       common.Hash `json:"root"`
+	Txs        []Tx        `json:"txs"`
+	Accounts   []Account   `json:"accounts"` // changed by the block
+	Store      []StoreKey  `json:"store"`    // app state keys the block set
+
+	WAL []appwal.Record `json:"wal,omitempty"` // of the block
+
+	// what couldn't be dumped, the report goes without it
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Tx is a tx of the ethereum block run again on the state of its parent.
+type Tx struct {
+	Index   int              `json:"index"`
+	Hash    common.Hash      `json:"hash"`
+	Root    common.Hash      `json:"root"` // of the state after it
+	Gas     *big.Int         `json:"gas"`
+	Error   string           `json:"error,omitempty"`
+	Touched []common.Address `json:"touched"` // sender, recipient, contract created and log emitters
+}
+
+// Account is an account as the block left it.
+type Account struct {
+	Key      common.Hash     `json:"key"`               // in the state trie
+	Address  *common.Address `json:"address,omitempty"` // when its preimage is known
+	Deleted  bool            `json:"deleted,omitempty"`
+	Nonce    uint64          `json:"nonce"`
+	Balance  *big.Int        `json:"balance"`
+	Root     common.Hash     `json:"root"` // of its storage
+	CodeHash common.Hash     `json:"codeHash"`
+}
+
+// StoreKey is a key of the app state the block set.
+type StoreKey struct {
+	Key       hexutil.Bytes `json:"key"`
+	Module    string        `json:"module,omitempty"` // registered the prefix of the key, see substore
+	ValueHash common.Hash   `json:"valueHash"`        // keccak256 of the value
+	Size      int           `json:"size"`
+}
+
+// Write writes r to file as json.
+func Write(file string, r *Report) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, data, 0644)
+}
+
+// Read reads the report of file.
+func Read(file string) (*Report, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var r Report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("%s: %v", file, err)
+	}
+	if r.Version == 0 || r.Version > Version {
+		return nil, fmt.Errorf("%s: unknown version %d", file, r.Version)
+	}
+	return &r, nil
+}
+
+// Difference is something two reports disagree on.
+type Difference struct {
+	What string `json:"what"`
+	A    string `json:"a"`
+	B    string `json:"b"`
+}
+
+func (d Difference) String() string {
+	return fmt.Sprintf("%s: %s / %s", d.What, d.A, d.B)
+}
+
+// Compare returns what a and b disagree on, the first tx they diverged at
+// before the accounts and the keys of the app state differing.
+func Compare(a, b *Report) []Difference {
+	var diffs []Difference
+	add := func(what string, x, y interface{}) {
+		diffs = append(diffs, Difference{What: what, A: fmt.Sprint(x), B: fmt.Sprint(y)})
+	}
+	if a.Height != b.Height {
+		add("height", a.Height, b.Height)
+		return diffs
+	}
+	if !bytes.Equal(a.AppHash, b.AppHash) {
+		add("app hash", a.AppHash, b.AppHash)
+	}
+	if a.Block != b.Block || a.BlockHash != b.BlockHash {
+		add("ethereum block", fmt.Sprintf("%d %s", a.Block, a.BlockHash.Hex()), fmt.Sprintf("%d %s", b.Block, b.BlockHash.Hex()))
+	}
+	if a.ParentRoot != b.ParentRoot {
+		add("state root of the parent block", a.ParentRoot.Hex(), b.ParentRoot.Hex())
+	}
+	if a.Root != b.Root {
+		add("state root", a.Root.Hex(), b.Root.Hex())
+	}
+
+	// the txs up to the first whose root differs
+	for i := 0; i < len(a.Txs) || i < len(b.Txs); i++ {
+		if x, y := txString(a.Txs, i), txString(b.Txs, i); x != y {
+			add(fmt.Sprintf("tx %d, first to diverge", i), x, y)
+			break
+		}
+	}
+
+	accounts := make(map[common.Hash][2]*Account)
+	for i := range a.Accounts {
+		accounts[a.Accounts[i].Key] = [2]*Account{&a.Accounts[i], nil}
+	}
+	for i := range b.Accounts {
+		pair := accounts[b.Accounts[i].Key]
+		pair[1] = &b.Accounts[i]
+		accounts[b.Accounts[i].Key] = pair
+	}
+	var keys []common.Hash
+	for key, pair := range accounts {
+		if accountString(pair[0]) != accountString(pair[1]) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i][:], keys[j][:]) < 0 })
+	for _, key := range keys {
+		pair := accounts[key]
+		what := "account " + key.Hex()
+		for _, acc := range pair {
+			if acc != nil && acc.Address != nil {
+				what = "account " + acc.Address.Hex()
+			}
+		}
+		add(what, accountString(pair[0]), accountString(pair[1]))
+	}
+
+	store := make(map[string][2]string)
+	for _, k := range a.Store {
+		store[string(k.Key)] = [2]string{storeString(k), "unchanged"}
+	}
+	for _, k := range b.Store {
+		pair, ok := store[string(k.Key)]
+		if !ok {
+			pair[0] = "unchanged"
+		}
+		pair[1] = storeString(k)
+		store[string(k.Key)] = pair
+	}
+	var skeys []string
+	for key, pair := range store {
+		if pair[0] != pair[1] {
+			skeys = append(skeys, key)
+		}
+	}
+	sort.Strings(skeys)
+	for _, key := range skeys {
+		add(fmt.Sprintf("app state key %x", key), store[key][0], store[key][1])
+	}
+	return diffs
+}
+
+func txString(txs []Tx, i int) string {
+	if i >= len(txs) {
+		return "none"
+	}
+	tx := txs[i]
+	s := fmt.Sprintf("%s root %s gas %v", tx.Hash.Hex(), tx.Root.Hex(), tx.Gas)
+	if tx.Error != "" {
+		s += " error " + tx.Error
+	}
+	return s
+}
+
+func accountString(acc *Account) string {
+	switch {
+	case acc == nil:
+		return "unchanged"
+	case acc.Deleted:
+		return "deleted"
+	}
+	return fmt.Sprintf("nonce %d balance %v storage %s code %s", acc.Nonce, acc.Balance, acc.Root.Hex(), acc.CodeHash.Hex())
+}
+
+func storeString(k StoreKey) string {
+	return fmt.Sprintf("value %s (%d bytes)", k.ValueHash.Hex(), k.Size)
+}
@@ -0,0 +1,74 @@
+package hashdump
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	alice := common.HexToAddress("0x0a")
+	report := func(root2 string, balance int64, value string) *Report {
+		return &Report{
+			Version: Version,
+			Height:  10,
+			AppHash: []byte{0x01},
+			Root:    common.HexToHash(root2),
+			Txs: []Tx{
+				{Index: 0, Hash: common.HexToHash("0x01"), Root: common.HexToHash("0x11"), Gas: big.NewInt(21000)},
+				{Index: 1, Hash: common.HexToHash("0x02"), Root: common.HexToHash(root2), Gas: big.NewInt(21000)},
+			},
+			Accounts: []Account{{Key: common.HexToHash("0xaa"), Address: &alice, Balance: big.NewInt(balance)}},
+			Store:    []StoreKey{{Key: []byte("k"), ValueHash: common.HexToHash(value), Size: 1}},
+		}
+	}
+
+	a := report("0x22", 5, "0x05")
+	assert.Empty(t, Compare(a, report("0x22", 5, "0x05")))
+
+	b := report("0x23", 6, "0x06")
+	b.AppHash = []byte{0x02}
+	b.Store = append(b.Store, StoreKey{Key: []byte("l")})
+	diffs := Compare(a, b)
+	var what []string
+	for _, d := range diffs {
+		what = append(what, d.What)
+	}
+	assert.Equal(t, []string{
+		"app hash",
+		"state root",
+		"tx 1, first to diverge",
+		"account " + alice.Hex(),
+		"app state key 6b",
+		"app state key 6c",
+	}, what)
+	assert.Equal(t, "unchanged", diffs[5].A)
+
+	b.Height = 11
+	assert.Equal(t, []Difference{{What: "height", A: "10", B: "11"}}, Compare(a, b))
+}
+
+func TestWriteRead(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hashdump")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "dump.json")
+
+	r := &Report{Version: Version, Node: "val0", Height: 3, Txs: []Tx{{Hash: common.HexToHash("0x01"), Gas: big.NewInt(1)}}}
+	require.NoError(t, Write(file, r))
+	read, err := Read(file)
+	require.NoError(t, err)
+	assert.Equal(t, "val0", read.Node)
+	assert.Equal(t, r.Txs[0].Hash, read.Txs[0].Hash)
+
+	r.Version = Version + 1
+	require.NoError(t, Write(file, r))
+	_, err = Read(file)
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package hashdump
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// secureKeyPrefix is where the chaindata keeps the preimages of the keys
+// of the state trie, the addresses of the accounts
+var secureKeyPrefix = []byte("secure-key-")
+
+// ChangedAccounts returns the accounts of the state of root that differ
+// from the ones of the state of parent, deleted ones included.
+func ChangedAccounts(db ethdb.Database, parent, root common.Hash) ([]Account, error) {
+	before, err := trie.New(parent, db)
+	if err != nil {
+		return nil, err
+	}
+	after, err := trie.New(root, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []Account
+	changed, _ := trie.NewDifferenceIterator(before.NodeIterator(nil), after.NodeIterator(nil))
+	for changed.Next(true) {
+		if !changed.Leaf() {
+			continue
+		}
+		var acc state.Account
+		if err := rlp.DecodeBytes(changed.LeafBlob(), &acc); err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, Account{Key: common.BytesToHash(leafKey(changed.Path())), Nonce: acc.Nonce,
+			Balance: acc.Balance, Root: acc.Root, CodeHash: common.BytesToHash(acc.CodeHash)})
+	}
+	if err := changed.Error(); err != nil {
+		return nil, err
+	}
+	removed, _ := trie.NewDifferenceIterator(after.NodeIterator(nil), before.NodeIterator(nil))
+	for removed.Next(true) {
+		if !removed.Leaf() {
+			continue
+		}
+		key := leafKey(removed.Path())
+		blob, err := after.TryGet(key)
+		if err != nil {
+			return nil, err
+		}
+		if blob == nil {
+			accounts = append(accounts, Account{Key: common.BytesToHash(key), Deleted: true})
+		}
+	}
+	if err := removed.Error(); err != nil {
+		return nil, err
+	}
+
+	for i := range accounts {
+		if preimage, err := db.Get(append(append([]byte{}, secureKeyPrefix...), accounts[i].Key[:]...)); err == nil &&
+			len(preimage) == common.AddressLength {
+			addr := common.BytesToAddress(preimage)
+			accounts[i].Address = &addr
+		}
+	}
+	sort.Slice(accounts, func(i, j int) bool { return bytes.Compare(accounts[i].Key[:], accounts[j].Key[:]) < 0 })
+	return accounts, nil
+}
+
+// leafKey packs the nibbles of the path of a leaf into its key
+func leafKey(path []byte) []byte {
+	if n := len(path); n > 0 && path[n-1] == 16 {
+		path = path[:n-1]
+	}
+	key := make([]byte, len(path)/2)
+	for i := 0; i+1 < len(path); i += 2 {
+		key[i/2] = path[i]<<4 | path[i+1]
+	}
+	return key
+}
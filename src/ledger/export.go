@@ -0,0 +1,84 @@
+package ledger
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// formats of the exports
+const (
+	FormatCSV     = "csv"
+	FormatParquet = "parquet"
+)
+
+// columns of the exports, one row per posting
+var columns = []string{"block", "entry", "tx", "kind", "account", "debit", "credit"}
+
+// Writer writes postings to an export.
+type Writer interface {
+	Write(p Posting) error
+	// Close writes what is left, it doesn't close the underlying writer.
+	Close() error
+}
+
+// NewWriter creates a writer of the export format to w.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return NewCSVWriter(w), nil
+	case FormatParquet:
+		return NewParquetWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unknown ledger format %q, use %s or %s", format, FormatCSV, FormatParquet)
+	}
+}
+
+// row returns the fields of p in the order of columns, amounts in decimal
+// wei.
+func row(p Posting) []string {
+	return []string{
+		strconv.FormatUint(p.Block, 10),
+		strconv.FormatUint(p.Entry, 10),
+		p.Tx,
+		p.Kind,
+		p.Account,
+		p.Debit.ToInt().String(),
+		p.Credit.ToInt().String(),
+	}
+}
+
+// CSVWriter writes postings as CSV with a header row.
+type CSVWriter struct {
+	w      *csv.Writer
+	header bool
+}
+
+// NewCSVWriter creates a CSV writer to w.
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w)}
+}
+
+// Write implements Writer.
+func (w *CSVWriter) Write(p Posting) error {
+	if !w.header {
+		if err := w.w.Write(columns); err != nil {
+			return err
+		}
+		w.header = true
+	}
+	return w.w.Write(row(p))
+}
+
+// Close implements Writer.
+func (w *CSVWriter) Close() error {
+	if !w.header {
+		if err := w.w.Write(columns); err != nil {
+			return err
+		}
+		w.header = true
+	}
+	w.w.Flush()
+	return w.w.Error()
+}
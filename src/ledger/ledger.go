@@ -0,0 +1,111 @@
+// Package ledger keeps the double-entry ledger of the chain for audits.
+// Every change of a balance is a posting of a journal entry whose postings
+// balance: a credit adds wei to the balance of an account, a debit takes
+// wei from it, and the credits of an entry add up to its debits.
+//
+// Besides the accounts of the chain, postings go to FeesAccount, for the
+// base fees leaving the sender, and to ChainAccount, for the coins issued
+// or burned by the chain, such as the block awards.
+package ledger
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// kinds of entries
+const (
+	KindFee      = "fee"      // gas fee of a tx, its tip to the proposer and its base fee to FeesAccount
+	KindTransfer = "transfer" // value sent by a tx
+	KindState    = "state"    // changes no tx explains: contract transfers, awards, module txs
+)
+
+// accounts not on chain
+const (
+	FeesAccount  = "fees"
+	ChainAccount = "chain"
+)
+
+// Posting is the debit or credit of an account in an entry.
+type Posting struct {
+	Block   uint64       `json:"block"`
+	Entry   uint64       `json:"entry"` // index of the entry in the block
+	Tx      string       `json:"tx,omitempty"`
+	Kind    string       `json:"kind"`
+	Account string       `json:"account"`
+	Debit   *hexutil.Big `json:"debit"`
+	Credit  *hexutil.Big `json:"credit"`
+}
+
+// Line is the change of the balance of an account, negative for a debit.
+type Line struct {
+	Account string
+	Amount  *big.Int
+}
+
+// Journal collects the entries of a block.
+type Journal struct {
+	block    uint64
+	entries  uint64
+	Postings []Posting
+}
+
+// NewJournal creates the journal of block.
+func NewJournal(block uint64) *Journal {
+	return &Journal{block: block}
+}
+
+// Add adds the entry of kind for tx made of lines, which must balance. The
+// lines of zero are left out and an entry without lines isn't added.
+func (j *Journal) Add(kind, tx string, lines ...Line) error {
+	sum := new(big.Int)
+	var postings []Posting
+	for _, l := range lines {
+		if l.Amount.Sign() == 0 {
+			continue
+		}
+		sum.Add(sum, l.Amount)
+		p := Posting{
+			Block:   j.block,
+			Entry:   j.entries,
+			Tx:      tx,
+			Kind:    kind,
+			Account: l.Account,
+			Debit:   new(hexutil.Big),
+			Credit:  new(hexutil.Big),
+		}
+		if l.Amount.Sign() > 0 {
+			p.Credit = (*hexutil.Big)(new(big.Int).Set(l.Amount))
+		} else {
+			p.Debit = (*hexutil.Big)(new(big.Int).Neg(l.Amount))
+		}
+		postings = append(postings, p)
+	}
+	if sum.Sign() != 0 {
+		return fmt.Errorf("%s entry of block %d is off by %s wei", kind, j.block, sum)
+	}
+	if len(postings) == 0 {
+		return nil
+	}
+	j.entries++
+	j.Postings = append(j.Postings, postings...)
+	return nil
+}
+
+// Balances returns the change of the balance of each account over the
+// postings.
+func Balances(postings []Posting) map[string]*big.Int {
+	balances := make(map[string]*big.Int)
+	for _, p := range postings {
+		b, ok := balances[p.Account]
+		if !ok {
+			b = new(big.Int)
+			balances[p.Account] = b
+		}
+		b.Add(b, p.Credit.ToInt())
+		b.Sub(b, p.Debit.ToInt())
+	}
+	return balances
+}
@@ -0,0 +1,63 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal(t *testing.T) {
+	j := NewJournal(7)
+	require.NoError(t, j.Add(KindFee, "0xab",
+		Line{"a", big.NewInt(-30)}, Line{"b", big.NewInt(10)}, Line{FeesAccount, big.NewInt(20)}))
+	require.NoError(t, j.Add(KindTransfer, "0xab", Line{"a", new(big.Int)}), "nothing to add")
+	assert.Error(t, j.Add(KindTransfer, "0xcd", Line{"a", big.NewInt(-5)}), "doesn't balance")
+	require.NoError(t, j.Add(KindTransfer, "0xcd", Line{"a", big.NewInt(-5)}, Line{"c", big.NewInt(5)}))
+
+	require.Len(t, j.Postings, 5)
+	assert.Equal(t, uint64(0), j.Postings[2].Entry)
+	assert.Equal(t, uint64(1), j.Postings[3].Entry)
+	assert.Equal(t, "30", j.Postings[0].Debit.ToInt().String())
+	assert.Equal(t, "20", j.Postings[2].Credit.ToInt().String())
+
+	balances := Balances(j.Postings)
+	assert.Equal(t, "-35", balances["a"].String())
+	assert.Equal(t, "5", balances["c"].String())
+}
+
+func TestExport(t *testing.T) {
+	j := NewJournal(7)
+	require.NoError(t, j.Add(KindState, "", Line{"a", big.NewInt(3)}, Line{ChainAccount, big.NewInt(-3)}))
+
+	var csv bytes.Buffer
+	w, err := NewWriter(FormatCSV, &csv)
+	require.NoError(t, err)
+	for _, p := range j.Postings {
+		require.NoError(t, w.Write(p))
+	}
+	require.NoError(t, w.Close())
+	assert.Equal(t, "block,entry,tx,kind,account,debit,credit\n"+
+		"7,0,,state,a,0,3\n"+
+		"7,0,,state,chain,3,0\n", csv.String())
+
+	var parquet bytes.Buffer
+	w, err = NewWriter(FormatParquet, &parquet)
+	require.NoError(t, err)
+	for _, p := range j.Postings {
+		require.NoError(t, w.Write(p))
+	}
+	require.NoError(t, w.Close())
+	b := parquet.Bytes()
+	assert.Equal(t, parquetMagic, string(b[:4]))
+	assert.Equal(t, parquetMagic, string(b[len(b)-4:]))
+	footer := int(binary.LittleEndian.Uint32(b[len(b)-8:]))
+	assert.True(t, footer > 0 && footer < len(b)-12)
+	assert.Contains(t, string(b[len(b)-8-footer:]), "account")
+
+	_, err = NewWriter("xls", &parquet)
+	assert.Error(t, err)
+}
@@ -0,0 +1,282 @@
+package ledger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"strconv"
+)
+
+// rowGroupSize is the postings of a row group of a parquet export, they are
+// kept in memory until the group is written.
+const rowGroupSize = 65536
+
+const parquetMagic = "PAR1"
+
+// parquet types, encodings and thrift compact protocol types, see
+// parquet-format/src/main/thrift/parquet.thrift
+const (
+	typeInt64     = 2
+	typeByteArray = 6
+
+	repetitionRequired = 0
+	convertedUTF8      = 0
+	encodingPlain      = 0
+	encodingRLE        = 3
+	pageData           = 0
+	codecUncompressed  = 0
+
+	ctI32    = 5
+	ctI64    = 6
+	ctBinary = 8
+	ctList   = 9
+	ctStruct = 12
+)
+
+// column types, in the order of columns
+var columnTypes = []int32{typeInt64, typeInt64, typeByteArray, typeByteArray, typeByteArray, typeByteArray, typeByteArray}
+
+type columnChunk struct {
+	offset int64 // of the data page
+	size   int64 // with the page header
+}
+
+type rowGroup struct {
+	columns []columnChunk
+	rows    int64
+}
+
+// ParquetWriter writes postings as a parquet file: one column per field,
+// all required, plain encoded and uncompressed, the amounts as decimal
+// strings as they don't fit an int64.
+type ParquetWriter struct {
+	w       io.Writer
+	offset  int64
+	rows    []Posting
+	groups  []rowGroup
+	started bool
+}
+
+// NewParquetWriter creates a parquet writer to w, the file is complete once
+// it is closed.
+func NewParquetWriter(w io.Writer) *ParquetWriter {
+	return &ParquetWriter{w: w}
+}
+
+// Write implements Writer.
+func (w *ParquetWriter) Write(p Posting) error {
+	w.rows = append(w.rows, p)
+	if len(w.rows) >= rowGroupSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// Close implements Writer, writing the footer.
+func (w *ParquetWriter) Close() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	if err := w.start(); err != nil {
+		return err
+	}
+	meta := w.metadata()
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(meta)))
+	return w.write(append(append(meta, size[:]...), parquetMagic...))
+}
+
+func (w *ParquetWriter) write(b []byte) error {
+	n, err := w.w.Write(b)
+	w.offset += int64(n)
+	return err
+}
+
+func (w *ParquetWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	return w.write([]byte(parquetMagic))
+}
+
+// flush writes the rows kept as a row group, a page per column.
+func (w *ParquetWriter) flush() error {
+	if len(w.rows) == 0 {
+		return nil
+	}
+	if err := w.start(); err != nil {
+		return err
+	}
+	group := rowGroup{rows: int64(len(w.rows))}
+	for c := range columns {
+		var page bytes.Buffer
+		for _, p := range w.rows {
+			field := row(p)[c]
+			if columnTypes[c] == typeInt64 {
+				n, _ := strconv.ParseInt(field, 10, 64)
+				binary.Write(&page, binary.LittleEndian, n) // nolint: errcheck
+			} else {
+				binary.Write(&page, binary.LittleEndian, uint32(len(field))) // nolint: errcheck
+				page.WriteString(field)
+			}
+		}
+		header := pageHeader(len(w.rows), page.Len())
+		chunk := columnChunk{offset: w.offset, size: int64(len(header) + page.Len())}
+		if err := w.write(append(header, page.Bytes()...)); err != nil {
+			return err
+		}
+		group.columns = append(group.columns, chunk)
+	}
+	w.groups = append(w.groups, group)
+	w.rows = w.rows[:0]
+	return nil
+}
+
+func pageHeader(values, size int) []byte {
+	var t thrift
+	t.begin()
+	t.i32(1, pageData)
+	t.i32(2, int32(size))
+	t.i32(3, int32(size))
+	t.structField(5) // data page header
+	t.i32(1, int32(values))
+	t.i32(2, encodingPlain)
+	t.i32(3, encodingRLE)
+	t.i32(4, encodingRLE)
+	t.end()
+	t.end()
+	return t.buf.Bytes()
+}
+
+// metadata returns the FileMetaData of the file.
+func (w *ParquetWriter) metadata() []byte {
+	var rows int64
+	for _, g := range w.groups {
+		rows += g.rows
+	}
+
+	var t thrift
+	t.begin()
+	t.i32(1, 1) // version
+	t.list(2, ctStruct, len(columns)+1)
+	t.begin()
+	t.str(4, "schema")
+	t.i32(5, int32(len(columns)))
+	t.end()
+	for c, name := range columns {
+		t.begin()
+		t.i32(1, columnTypes[c])
+		t.i32(3, repetitionRequired)
+		t.str(4, name)
+		if columnTypes[c] == typeByteArray {
+			t.i32(6, convertedUTF8)
+		}
+		t.end()
+	}
+	t.i64(3, rows)
+	t.list(4, ctStruct, len(w.groups))
+	for _, g := range w.groups {
+		t.begin()
+		t.list(1, ctStruct, len(g.columns))
+		var total int64
+		for c, chunk := range g.columns {
+			total += chunk.size
+			t.begin()
+			t.i64(2, chunk.offset)
+			t.structField(3) // column metadata
+			t.i32(1, columnTypes[c])
+			t.list(2, ctI32, 2)
+			t.zigzag(encodingPlain)
+			t.zigzag(encodingRLE)
+			t.list(3, ctBinary, 1)
+			t.binary(columns[c])
+			t.i32(4, codecUncompressed)
+			t.i64(5, g.rows)
+			t.i64(6, chunk.size)
+			t.i64(7, chunk.size)
+			t.i64(9, chunk.offset)
+			t.end()
+			t.end()
+		}
+		t.i64(2, total)
+		t.i64(3, g.rows)
+		t.end()
+	}
+	t.str(6, "ultron")
+	t.end()
+	return t.buf.Bytes()
+}
+
+// thrift writes structs in the thrift compact protocol.
+type thrift struct {
+	buf  bytes.Buffer
+	last []int16 // id of the last field of each struct being written
+}
+
+func (t *thrift) begin() {
+	t.last = append(t.last, 0)
+}
+
+func (t *thrift) end() {
+	t.buf.WriteByte(0)
+	t.last = t.last[:len(t.last)-1]
+}
+
+func (t *thrift) field(id int16, typ byte) {
+	last := &t.last[len(t.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		t.buf.WriteByte(byte(delta)<<4 | typ)
+	} else {
+		t.buf.WriteByte(typ)
+		t.zigzag(int64(id))
+	}
+	*last = id
+}
+
+func (t *thrift) varint(v uint64) {
+	var b [binary.MaxVarintLen64]byte
+	t.buf.Write(b[:binary.PutUvarint(b[:], v)])
+}
+
+func (t *thrift) zigzag(v int64) {
+	t.varint(uint64(v<<1) ^ uint64(v>>63))
+}
+
+func (t *thrift) binary(s string) {
+	t.varint(uint64(len(s)))
+	t.buf.WriteString(s)
+}
+
+func (t *thrift) i32(id int16, v int32) {
+	t.field(id, ctI32)
+	t.zigzag(int64(v))
+}
+
+func (t *thrift) i64(id int16, v int64) {
+	t.field(id, ctI64)
+	t.zigzag(v)
+}
+
+func (t *thrift) str(id int16, s string) {
+	t.field(id, ctBinary)
+	t.binary(s)
+}
+
+// structField starts the struct of field id, end it with end.
+func (t *thrift) structField(id int16) {
+	t.field(id, ctStruct)
+	t.begin()
+}
+
+// list starts the list of field id of n elements of type elem, which are
+// written next.
+func (t *thrift) list(id int16, elem byte, n int) {
+	t.field(id, ctList)
+	if n < 15 {
+		t.buf.WriteByte(byte(n)<<4 | elem)
+	} else {
+		t.buf.WriteByte(0xf0 | elem)
+		t.varint(uint64(n))
+	}
+}
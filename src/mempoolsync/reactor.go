@@ -0,0 +1,217 @@
+// Package mempoolsync lets a node that just (re)started pull the txs
+// pending in the mempools of its peers, instead of waiting for them to be
+// broadcast again. Right after start the Reactor asks its first peers for
+// a snapshot of their mempool and checks the txs into its own.
+package mempoolsync
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tendermint/go-wire"
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/p2p/conn"
+
+	"github.com/dora/ultron/peerversion"
+)
+
+// Channel is the p2p channel of the snapshots, unused by tendermint.
+const Channel = byte(0x70)
+
+const (
+	maxMsgSize       = 8 * 1024 * 1024 // snapshots are cut below it
+	maxSnapshotBytes = maxMsgSize - 1024
+
+	syncWindow = 5 * time.Minute // snapshots are only asked for that long after start
+	syncPeers  = 3               // peers asked for a snapshot
+	serveEvery = time.Minute     // a peer gets one snapshot that often
+)
+
+// Mempool is the mempool of the node.
+type Mempool interface {
+	// Pending returns the txs waiting for a block.
+	Pending() ([][]byte, error)
+	// Submit checks tx into the mempool.
+	Submit(tx []byte) error
+}
+
+// Reactor exchanges mempool snapshots on Channel.
+type Reactor struct {
+	p2p.BaseReactor
+
+	mtx     sync.Mutex
+	mempool Mempool
+	started time.Time
+	asked   int
+	served  map[p2p.ID]time.Time
+}
+
+// NewReactor creates the reactor, SetMempool must be called before the
+// switch starts.
+func NewReactor() *Reactor {
+	r := &Reactor{served: make(map[p2p.ID]time.Time)}
+	r.BaseReactor = *p2p.NewBaseReactor("MempoolSyncReactor", r)
+	return r
+}
+
+// SetMempool sets the mempool snapshots are read from and checked into.
+func (r *Reactor) SetMempool(mempool Mempool) {
+	r.mtx.Lock()
+	r.mempool = mempool
+	r.mtx.Unlock()
+}
+
+// OnStart implements cmn.Service.
+func (r *Reactor) OnStart() error {
+	r.mtx.Lock()
+	r.started = time.Now()
+	r.mtx.Unlock()
+	return r.BaseReactor.OnStart()
+}
+
+// GetChannels implements p2p.Reactor.
+func (r *Reactor) GetChannels() []*conn.ChannelDescriptor {
+	return []*conn.ChannelDescriptor{{
+		ID:                  Channel,
+		Priority:            1,
+		SendQueueCapacity:   2,
+		RecvMessageCapacity: maxMsgSize,
+	}}
+}
+
+// AddPeer implements p2p.Reactor, asking the first peers after start for a
+// snapshot. Peers not announcing the reactor would drop the node on the
+// request, they are left alone.
+func (r *Reactor) AddPeer(peer p2p.Peer) {
+	if !peerversion.PeerHas(peer, peerversion.MempoolSync) {
+		return
+	}
+	r.mtx.Lock()
+	ask := r.asked < syncPeers && time.Since(r.started) < syncWindow
+	if ask {
+		r.asked++
+	}
+	r.mtx.Unlock()
+
+	if ask {
+		r.Logger.Info("Asking for the mempool", "peer", peer.ID())
+		peer.Send(Channel, struct{ SyncMessage }{&snapshotRequest{}})
+	}
+}
+
+// RemovePeer implements p2p.Reactor.
+func (r *Reactor) RemovePeer(peer p2p.Peer, reason interface{}) {
+	r.mtx.Lock()
+	delete(r.served, peer.ID())
+	r.mtx.Unlock()
+}
+
+// Receive implements p2p.Reactor.
+func (r *Reactor) Receive(chID byte, src p2p.Peer, msgBytes []byte) {
+	msg, err := DecodeMessage(msgBytes)
+	if err != nil {
+		r.Logger.Error("Error decoding message", "src", src, "chId", chID, "err", err)
+		r.Switch.StopPeerForError(src, err)
+		return
+	}
+
+	switch msg := msg.(type) {
+	case *snapshotRequest:
+		r.serve(src)
+	case *snapshotResponse:
+		go r.check(src, msg.Txs)
+	}
+}
+
+// serve sends the pending txs to peer, at most once every serveEvery
+func (r *Reactor) serve(peer p2p.Peer) {
+	r.mtx.Lock()
+	mempool := r.mempool
+	last, ok := r.served[peer.ID()]
+	if mempool == nil || (ok && time.Since(last) < serveEvery) {
+		r.mtx.Unlock()
+		return
+	}
+	r.served[peer.ID()] = time.Now()
+	r.mtx.Unlock()
+
+	txs, err := mempool.Pending()
+	if err != nil {
+		r.Logger.Error("Failed to read the mempool", "err", err)
+		return
+	}
+	snapshot := &snapshotResponse{Txs: limit(txs, maxSnapshotBytes)}
+	peer.Send(Channel, struct{ SyncMessage }{snapshot})
+}
+
+// check submits the txs of the snapshot of peer
+func (r *Reactor) check(peer p2p.Peer, txs [][]byte) {
+	r.mtx.Lock()
+	mempool := r.mempool
+	r.mtx.Unlock()
+	if mempool == nil {
+		return
+	}
+
+	added := 0
+	for _, tx := range txs {
+		// known and invalid txs are refused, either is fine
+		if err := mempool.Submit(tx); err == nil {
+			added++
+		}
+	}
+	r.Logger.Info("Synced the mempool", "peer", peer.ID(), "txs", len(txs), "added", added)
+}
+
+// limit keeps the first txs which fit into max bytes
+func limit(txs [][]byte, max int) [][]byte {
+	size := 0
+	for i, tx := range txs {
+		size += len(tx) + 8
+		if size > max {
+			return txs[:i]
+		}
+	}
+	return txs
+}
+
+//-----------------------------------------------------------------------------
+// Messages
+
+const (
+	msgTypeSnapshotRequest  = byte(0x01)
+	msgTypeSnapshotResponse = byte(0x02)
+)
+
+// SyncMessage is a message sent on Channel.
+type SyncMessage interface{}
+
+var _ = wire.RegisterInterface(
+	struct{ SyncMessage }{},
+	wire.ConcreteType{&snapshotRequest{}, msgTypeSnapshotRequest},
+	wire.ConcreteType{&snapshotResponse{}, msgTypeSnapshotResponse},
+)
+
+// DecodeMessage decodes a message received on Channel.
+func DecodeMessage(bz []byte) (msg SyncMessage, err error) {
+	n := new(int)
+	r := bytes.NewReader(bz)
+	msg = wire.ReadBinary(struct{ SyncMessage }{}, r, maxMsgSize, n, &err).(struct{ SyncMessage }).SyncMessage
+	return
+}
+
+type snapshotRequest struct{}
+
+func (m *snapshotRequest) String() string {
+	return "[SnapshotRequest]"
+}
+
+type snapshotResponse struct {
+	Txs [][]byte
+}
+
+func (m *snapshotResponse) String() string {
+	return fmt.Sprintf("[SnapshotResponse %d txs]", len(m.Txs))
+}
@@ -0,0 +1,29 @@
+package mempoolsync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-wire"
+)
+
+func TestMessages(t *testing.T) {
+	txs := [][]byte{[]byte("tx1"), []byte("tx2")}
+	msg, err := DecodeMessage(wire.BinaryBytes(struct{ SyncMessage }{&snapshotResponse{Txs: txs}}))
+	assert.Nil(t, err)
+	assert.Equal(t, &snapshotResponse{Txs: txs}, msg)
+
+	msg, err = DecodeMessage(wire.BinaryBytes(struct{ SyncMessage }{&snapshotRequest{}}))
+	assert.Nil(t, err)
+	assert.IsType(t, &snapshotRequest{}, msg)
+
+	_, err = DecodeMessage([]byte{0xff})
+	assert.NotNil(t, err)
+}
+
+func TestLimit(t *testing.T) {
+	txs := [][]byte{make([]byte, 10), make([]byte, 10), make([]byte, 10)}
+	assert.Len(t, limit(txs, 100), 3)
+	assert.Len(t, limit(txs, 40), 2)
+	assert.Len(t, limit(txs, 5), 0)
+}
@@ -0,0 +1,88 @@
+package bank
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dora/ultron/types"
+)
+
+func TestDeliverTx(t *testing.T) {
+	store := state.NewMemKVStore()
+	issuer := common.HexToAddress("0x01")
+	other := common.HexToAddress("0x02")
+	ctx := types.NewContext("test", 1, nil)
+	ctx.WithSigners(issuer)
+	h := &BankTxHandler{}
+
+	_, err := h.DeliverTx(ctx, store, NewTxIssue("gold", 2, "1000", true))
+	assert.Nil(t, err)
+	_, err = h.DeliverTx(ctx, store, NewTxIssue("gold", 2, "1000", true))
+	assert.NotNil(t, err, "issued twice")
+	assert.Equal(t, big.NewInt(1000), loadBalance(store, "gold", issuer))
+
+	_, err = h.DeliverTx(ctx, store, NewTxTransfer("gold", other, "400"))
+	assert.Nil(t, err)
+	_, err = h.DeliverTx(ctx, store, NewTxTransfer("gold", other, "601"))
+	assert.NotNil(t, err, "more than the balance")
+	assert.Equal(t, big.NewInt(600), loadBalance(store, "gold", issuer))
+	assert.Equal(t, big.NewInt(400), loadBalance(store, "gold", other))
+
+	_, err = h.DeliverTx(ctx, store, NewTxBurn("gold", "100"))
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewInt(500), loadBalance(store, "gold", issuer))
+	assert.Equal(t, "900", loadAsset(store, "gold").Supply)
+
+	_, err = h.DeliverTx(ctx, store, NewTxTransfer("silver", other, "1"))
+	assert.NotNil(t, err, "asset not issued")
+}
+
+// a proposer may include a malformed tx, it must fail instead of panicking
+func TestDeliverTxBadAmount(t *testing.T) {
+	store := state.NewMemKVStore()
+	ctx := types.NewContext("test", 1, nil)
+	ctx.WithSigners(common.HexToAddress("0x01"))
+	h := &BankTxHandler{}
+
+	for _, amount := range []string{"", "abc", "0", "-5", "1.5"} {
+		_, err := h.DeliverTx(ctx, store, NewTxIssue("gold", 0, amount, true))
+		assert.NotNil(t, err, amount)
+		_, err = h.DeliverTx(ctx, store, NewTxTransfer("gold", common.Address{}, amount))
+		assert.NotNil(t, err, amount)
+		_, err = h.DeliverTx(ctx, store, NewTxBurn("gold", amount))
+		assert.NotNil(t, err, amount)
+		_, err = h.DeliverTx(ctx, store, NewTxMint("gold", common.Address{}, amount))
+		assert.NotNil(t, err, amount)
+
+		// apply must not rely on the tx having been validated
+		assert.NotPanics(t, func() {
+			err = h.apply(store, common.Address{}, NewTxIssue("gold", 0, amount, true))
+		}, amount)
+		assert.NotNil(t, err, amount)
+	}
+	assert.Nil(t, loadAsset(store, "gold"))
+}
+
+func TestMigrate(t *testing.T) {
+	store := state.NewMemKVStore()
+	lost := common.HexToAddress("0x01")
+	newKey := common.HexToAddress("0x02")
+	ctx := types.NewContext("test", 1, nil)
+	ctx.WithSigners(lost)
+	h := &BankTxHandler{}
+	_, err := h.DeliverTx(ctx, store, NewTxIssue("gold", 0, "100", true))
+	assert.Nil(t, err)
+	saveBalance(store, "gold", newKey, big.NewInt(5))
+	saveFeeDenom(store, lost, "gold")
+
+	migrate(store, lost, newKey)
+	assert.Equal(t, 0, loadBalance(store, "gold", lost).Sign())
+	assert.Equal(t, big.NewInt(105), loadBalance(store, "gold", newKey))
+	assert.Equal(t, newKey, loadAsset(store, "gold").Issuer)
+	assert.Equal(t, "", FeeDenom(store, lost))
+	assert.Equal(t, "gold", FeeDenom(store, newKey))
+}
@@ -0,0 +1,52 @@
+// nolint
+package bank
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/errors"
+)
+
+var (
+	errBadDenom          = fmt.Errorf("Denom must be 3 to 16 lowercase letters and digits")
+	errBadAmount         = fmt.Errorf("Amount must be > 0")
+	errAssetExists       = fmt.Errorf("Asset already issued")
+	errAssetNotFound     = fmt.Errorf("Asset not issued")
+	errNotIssuer         = fmt.Errorf("Only the issuer can mint")
+	errNotMintable       = fmt.Errorf("Asset is not mintable")
+	errInsufficientFunds = fmt.Errorf("Insufficient funds")
+	errMissingSignature  = fmt.Errorf("Missing signature")
+	errNotFeeToken       = fmt.Errorf("Denom is not accepted for gas")
+	errNoFeeRate         = fmt.Errorf("The oracle has no rate for the fee token")
+)
+
+func ErrBadDenom() error {
+	return errors.WithCode(errBadDenom, errors.CodeTypeBaseInvalidInput)
+}
+func ErrBadAmount() error {
+	return errors.WithCode(errBadAmount, errors.CodeTypeBaseInvalidInput)
+}
+func ErrAssetExists() error {
+	return errors.WithCode(errAssetExists, errors.CodeTypeBaseInvalidInput)
+}
+func ErrAssetNotFound() error {
+	return errors.WithCode(errAssetNotFound, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNotIssuer() error {
+	return errors.WithCode(errNotIssuer, errors.CodeTypeUnauthorized)
+}
+func ErrNotMintable() error {
+	return errors.WithCode(errNotMintable, errors.CodeTypeUnauthorized)
+}
+func ErrInsufficientFunds() error {
+	return errors.WithCode(errInsufficientFunds, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNotFeeToken() error {
+	return errors.WithCode(errNotFeeToken, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNoFeeRate() error {
+	return errors.WithCode(errNoFeeRate, errors.CodeTypeBaseInvalidInput)
+}
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSignature, errors.CodeTypeUnauthorized)
+}
@@ -0,0 +1,61 @@
+package bank
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/oracle"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FeeTokens returns the tokens of denom worth cost wei. The oracle rate of
+// a fee token is its price in wei per base unit, so the tokens are
+// cost / rate, rounded up.
+func FeeTokens(store state.SimpleDB, denom string, cost *big.Int) (*big.Int, error) {
+	if !loadParams(store).isFeeToken(denom) {
+		return nil, ErrNotFeeToken()
+	}
+	rate, ok := oracle.LoadRate(store, denom)
+	if !ok || rate.Value().Sign() <= 0 {
+		return nil, ErrNoFeeRate()
+	}
+
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(oracle.RateDecimals), nil)
+	tokens := new(big.Int).Mul(cost, scale)
+	tokens.Add(tokens, new(big.Int).Sub(rate.Value(), big.NewInt(1)))
+	return tokens.Quo(tokens, rate.Value()), nil
+}
+
+// CanPayFees checks addr holds the tokens of denom worth cost wei.
+func CanPayFees(store state.SimpleDB, addr common.Address, denom string, cost *big.Int) error {
+	_, _, err := feeTokens(store, addr, denom, cost)
+	return err
+}
+
+// PayFees moves the tokens of denom worth cost wei from addr to the fee
+// reserve, which pays the ether of the gas in exchange.
+func PayFees(store state.SimpleDB, addr common.Address, denom string, cost *big.Int) error {
+	tokens, balance, err := feeTokens(store, addr, denom, cost)
+	if err != nil {
+		return err
+	}
+	reserve := constant.FeeReserveAccount
+	saveBalance(store, denom, addr, balance.Sub(balance, tokens))
+	saveBalance(store, denom, reserve, new(big.Int).Add(loadBalance(store, denom, reserve), tokens))
+	return nil
+}
+
+// feeTokens returns the tokens of denom worth cost wei and the balance of
+// addr, which must hold them.
+func feeTokens(store state.SimpleDB, addr common.Address, denom string, cost *big.Int) (tokens, balance *big.Int, err error) {
+	tokens, err = FeeTokens(store, denom, cost)
+	if err != nil {
+		return nil, nil, err
+	}
+	balance = loadBalance(store, denom, addr)
+	if balance.Cmp(tokens) < 0 {
+		return nil, nil, ErrInsufficientFunds()
+	}
+	return tokens, balance, nil
+}
@@ -0,0 +1,149 @@
+package bank
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type BankTxHandler struct {
+}
+
+// InitState - set genesis parameters for the bank
+func (h *BankTxHandler) InitState(key, value string, store state.SimpleDB) error {
+	params := loadParams(store)
+	switch key {
+	case "fee_token":
+		if !denomRegexp.MatchString(value) {
+			return ErrBadDenom()
+		}
+		if !params.isFeeToken(value) {
+			params.FeeTokens = append(params.FeeTokens, value)
+		}
+	default:
+		return errors.ErrUnknownKey(key)
+	}
+
+	saveParams(store, params)
+	return nil
+}
+
+// CheckTx checks if the tx is properly structured and allowed
+func (h *BankTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+
+	sender, err := h.getTxSender(ctx)
+	if err != nil {
+		return res, err
+	}
+	return res, h.apply(store, sender, tx)
+}
+
+// DeliverTx executes the tx if valid
+func (h *BankTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.DeliverResult, err error) {
+	// a proposer may include txs that never went through CheckTx
+	err = tx.ValidateBasic()
+	if err != nil {
+		return
+	}
+
+	sender, err := h.getTxSender(ctx)
+	if err != nil {
+		return
+	}
+	return res, h.apply(store, sender, tx)
+}
+
+// apply runs tx against store, the check store for CheckTx
+func (h *BankTxHandler) apply(store state.SimpleDB, sender common.Address, tx sdk.Tx) error {
+	switch _tx := tx.Unwrap().(type) {
+	case TxIssue:
+		if loadAsset(store, _tx.Denom) != nil {
+			return ErrAssetExists()
+		}
+		supply, err := parseAmount(_tx.Supply)
+		if err != nil {
+			return err
+		}
+		saveAsset(store, &Asset{
+			Denom:    _tx.Denom,
+			Issuer:   sender,
+			Decimals: _tx.Decimals,
+			Supply:   supply.String(),
+			Mintable: _tx.Mintable,
+		})
+		saveBalance(store, _tx.Denom, sender, supply)
+	case TxMint:
+		asset := loadAsset(store, _tx.Denom)
+		if asset == nil {
+			return ErrAssetNotFound()
+		}
+		if asset.Issuer != sender {
+			return ErrNotIssuer()
+		}
+		if !asset.Mintable {
+			return ErrNotMintable()
+		}
+		amount, err := parseAmount(_tx.Amount)
+		if err != nil {
+			return err
+		}
+		asset.Supply = new(big.Int).Add(asset.SupplyValue(), amount).String()
+		saveAsset(store, asset)
+		saveBalance(store, _tx.Denom, _tx.To, new(big.Int).Add(loadBalance(store, _tx.Denom, _tx.To), amount))
+	case TxBurn:
+		asset := loadAsset(store, _tx.Denom)
+		if asset == nil {
+			return ErrAssetNotFound()
+		}
+		amount, err := parseAmount(_tx.Amount)
+		if err != nil {
+			return err
+		}
+		balance := loadBalance(store, _tx.Denom, sender)
+		if balance.Cmp(amount) < 0 {
+			return ErrInsufficientFunds()
+		}
+		asset.Supply = new(big.Int).Sub(asset.SupplyValue(), amount).String()
+		saveAsset(store, asset)
+		saveBalance(store, _tx.Denom, sender, balance.Sub(balance, amount))
+	case TxTransfer:
+		if loadAsset(store, _tx.Denom) == nil {
+			return ErrAssetNotFound()
+		}
+		amount, err := parseAmount(_tx.Amount)
+		if err != nil {
+			return err
+		}
+		balance := loadBalance(store, _tx.Denom, sender)
+		if balance.Cmp(amount) < 0 {
+			return ErrInsufficientFunds()
+		}
+		saveBalance(store, _tx.Denom, sender, balance.Sub(balance, amount))
+		saveBalance(store, _tx.Denom, _tx.To, new(big.Int).Add(loadBalance(store, _tx.Denom, _tx.To), amount))
+	case TxFeeToken:
+		if _tx.Denom != "" && !loadParams(store).isFeeToken(_tx.Denom) {
+			return ErrNotFeeToken()
+		}
+		saveFeeDenom(store, sender, _tx.Denom)
+	default:
+		return errors.ErrUnknownTxType(tx)
+	}
+	return nil
+}
+
+// get the sender from the ctx
+func (h *BankTxHandler) getTxSender(ctx types.Context) (sender common.Address, err error) {
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		return sender, ErrMissingSignature()
+	}
+	return senders[0], nil
+}
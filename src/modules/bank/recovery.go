@@ -0,0 +1,39 @@
+package bank
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/recovery"
+	"github.com/dora/ultron/substore"
+)
+
+func init() {
+	recovery.RegisterMigration(constant.ModuleNameBank, migrate)
+}
+
+// migrate moves the balances, the issuer rights and the fee token of the
+// recovered account from to its new key to.
+func migrate(store state.SimpleDB, from, to common.Address) {
+	for _, m := range substore.New(store, AssetPrefix).List(nil, nil, 0) {
+		asset, err := ParseAsset(m.Value)
+		if err != nil {
+			panic(err) // This error should never occur big problem if does
+		}
+		if balance := loadBalance(store, asset.Denom, from); balance.Sign() > 0 {
+			saveBalance(store, asset.Denom, to, new(big.Int).Add(loadBalance(store, asset.Denom, to), balance))
+			saveBalance(store, asset.Denom, from, new(big.Int))
+		}
+		if asset.Issuer == from {
+			asset.Issuer = to
+			saveAsset(store, asset)
+		}
+	}
+	if denom := FeeDenom(store, from); denom != "" {
+		saveFeeDenom(store, to, denom)
+		saveFeeDenom(store, from, "")
+	}
+}
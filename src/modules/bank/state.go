@@ -0,0 +1,110 @@
+package bank
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	// Keys for store prefixes
+	AssetPrefix   = []byte{0x07} // assets: prefix|denom
+	BalancePrefix = []byte{0x08} // balances: prefix|denom|0x00|address
+	ParamKey      = []byte{0x09} // key for the bank parameters
+	FeePrefix     = []byte{0x0a} // fee tokens of accounts: prefix|address
+)
+
+func init() {
+	substore.Register("bank", AssetPrefix, BalancePrefix, ParamKey, FeePrefix)
+}
+
+// load/save the bank params
+func loadParams(store state.SimpleDB) (params Params) {
+	b := store.Get(ParamKey)
+	if b == nil {
+		return Params{}
+	}
+
+	err := wire.ReadBinaryBytes(b, &params)
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+
+	return
+}
+func saveParams(store state.SimpleDB, params Params) {
+	b := wire.BinaryBytes(params)
+	store.Set(ParamKey, b)
+}
+
+// AssetKey is the store key of the asset denom, for the "/key" query.
+func AssetKey(denom string) []byte {
+	return append(append([]byte{}, AssetPrefix...), denom...)
+}
+
+// BalanceKey is the store key of the balance of addr in denom, for the
+// "/key" query. The value is the big endian balance.
+func BalanceKey(denom string, addr common.Address) []byte {
+	key := append(append([]byte{}, BalancePrefix...), denom...)
+	key = append(key, 0x00)
+	return append(key, addr.Bytes()...)
+}
+
+// ParseAsset decodes an asset read from the store.
+func ParseAsset(b []byte) (*Asset, error) {
+	asset := new(Asset)
+	if err := wire.ReadBinaryBytes(b, asset); err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+func loadAsset(store state.SimpleDB, denom string) *Asset {
+	b := store.Get(AssetKey(denom))
+	if b == nil {
+		return nil
+	}
+	asset, err := ParseAsset(b)
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return asset
+}
+
+func saveAsset(store state.SimpleDB, asset *Asset) {
+	store.Set(AssetKey(asset.Denom), wire.BinaryBytes(*asset))
+}
+
+func feeKey(addr common.Address) []byte {
+	return append(append([]byte{}, FeePrefix...), addr.Bytes()...)
+}
+
+// FeeDenom returns the token addr pays gas in, empty for ether.
+func FeeDenom(store state.SimpleDB, addr common.Address) string {
+	return string(store.Get(feeKey(addr)))
+}
+
+func saveFeeDenom(store state.SimpleDB, addr common.Address, denom string) {
+	if denom == "" {
+		store.Remove(feeKey(addr))
+		return
+	}
+	store.Set(feeKey(addr), []byte(denom))
+}
+
+func loadBalance(store state.SimpleDB, denom string, addr common.Address) *big.Int {
+	return new(big.Int).SetBytes(store.Get(BalanceKey(denom, addr)))
+}
+
+func saveBalance(store state.SimpleDB, denom string, addr common.Address, balance *big.Int) {
+	if balance.Sign() == 0 {
+		store.Remove(BalanceKey(denom, addr))
+		return
+	}
+	store.Set(BalanceKey(denom, addr), balance.Bytes())
+}
@@ -0,0 +1,153 @@
+package bank
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/dora/ultron/const"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nolint
+const (
+	ByteTxIssue    = 0x73
+	ByteTxMint     = 0x74
+	ByteTxBurn     = 0x75
+	ByteTxTransfer = 0x76
+	ByteTxFeeToken = 0x77
+	TypeTxIssue    = constant.ModuleNameBank + "/issue"
+	TypeTxMint     = constant.ModuleNameBank + "/mint"
+	TypeTxBurn     = constant.ModuleNameBank + "/burn"
+	TypeTxTransfer = constant.ModuleNameBank + "/transfer"
+	TypeTxFeeToken = constant.ModuleNameBank + "/feeToken"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxIssue{}, TypeTxIssue, ByteTxIssue)
+	sdk.TxMapper.RegisterImplementation(TxMint{}, TypeTxMint, ByteTxMint)
+	sdk.TxMapper.RegisterImplementation(TxBurn{}, TypeTxBurn, ByteTxBurn)
+	sdk.TxMapper.RegisterImplementation(TxTransfer{}, TypeTxTransfer, ByteTxTransfer)
+	sdk.TxMapper.RegisterImplementation(TxFeeToken{}, TypeTxFeeToken, ByteTxFeeToken)
+}
+
+// Verify interface at compile time
+var _, _, _, _, _ sdk.TxInner = TxIssue{}, TxMint{}, TxBurn{}, TxTransfer{}, TxFeeToken{}
+
+// TxIssue creates a new denomination, its supply is credited to the sender
+type TxIssue struct {
+	Denom    string `json:"denom"`
+	Decimals uint8  `json:"decimals"`
+	Supply   string `json:"supply"`
+	Mintable bool   `json:"mintable"`
+}
+
+func (tx TxIssue) ValidateBasic() error {
+	if !denomRegexp.MatchString(tx.Denom) {
+		return ErrBadDenom()
+	}
+	_, err := parseAmount(tx.Supply)
+	return err
+}
+
+func NewTxIssue(denom string, decimals uint8, supply string, mintable bool) sdk.Tx {
+	return TxIssue{
+		Denom:    denom,
+		Decimals: decimals,
+		Supply:   supply,
+		Mintable: mintable,
+	}.Wrap()
+}
+
+func (tx TxIssue) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxMint credits new units of a mintable denomination, only its issuer may
+type TxMint struct {
+	Denom  string         `json:"denom"`
+	To     common.Address `json:"to"`
+	Amount string         `json:"amount"`
+}
+
+func (tx TxMint) ValidateBasic() error {
+	if !denomRegexp.MatchString(tx.Denom) {
+		return ErrBadDenom()
+	}
+	_, err := parseAmount(tx.Amount)
+	return err
+}
+
+func NewTxMint(denom string, to common.Address, amount string) sdk.Tx {
+	return TxMint{
+		Denom:  denom,
+		To:     to,
+		Amount: amount,
+	}.Wrap()
+}
+
+func (tx TxMint) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxBurn destroys units held by the sender
+type TxBurn struct {
+	Denom  string `json:"denom"`
+	Amount string `json:"amount"`
+}
+
+func (tx TxBurn) ValidateBasic() error {
+	if !denomRegexp.MatchString(tx.Denom) {
+		return ErrBadDenom()
+	}
+	_, err := parseAmount(tx.Amount)
+	return err
+}
+
+func NewTxBurn(denom string, amount string) sdk.Tx {
+	return TxBurn{
+		Denom:  denom,
+		Amount: amount,
+	}.Wrap()
+}
+
+func (tx TxBurn) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxTransfer moves units from the sender to another account
+type TxTransfer struct {
+	Denom  string         `json:"denom"`
+	To     common.Address `json:"to"`
+	Amount string         `json:"amount"`
+}
+
+func (tx TxTransfer) ValidateBasic() error {
+	if !denomRegexp.MatchString(tx.Denom) {
+		return ErrBadDenom()
+	}
+	_, err := parseAmount(tx.Amount)
+	return err
+}
+
+func NewTxTransfer(denom string, to common.Address, amount string) sdk.Tx {
+	return TxTransfer{
+		Denom:  denom,
+		To:     to,
+		Amount: amount,
+	}.Wrap()
+}
+
+func (tx TxTransfer) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxFeeToken makes the sender pay the gas of its ethereum txs in a fee
+// token, or in ether again if Denom is empty.
+type TxFeeToken struct {
+	Denom string `json:"denom"`
+}
+
+func (tx TxFeeToken) ValidateBasic() error {
+	if tx.Denom != "" && !denomRegexp.MatchString(tx.Denom) {
+		return ErrBadDenom()
+	}
+	return nil
+}
+
+func NewTxFeeToken(denom string) sdk.Tx {
+	return TxFeeToken{
+		Denom: denom,
+	}.Wrap()
+}
+
+func (tx TxFeeToken) Wrap() sdk.Tx { return sdk.Tx{tx} }
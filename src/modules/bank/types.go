@@ -0,0 +1,52 @@
+// Package bank keeps native assets next to the ether balances: an account
+// issues a denomination, may mint more of it later if it is mintable, and
+// holders transfer and burn it. Assets and balances live in the app store,
+// not in the EVM, so they don't cost contract gas to move.
+package bank
+
+import (
+	"math/big"
+	"regexp"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var denomRegexp = regexp.MustCompile(`^[a-z][a-z0-9]{2,15}$`)
+
+// Params defines the settings of the bank
+type Params struct {
+	FeeTokens []string `json:"fee_tokens"` // denoms accepted for gas
+}
+
+func (p Params) isFeeToken(denom string) bool {
+	for _, token := range p.FeeTokens {
+		if token == denom {
+			return true
+		}
+	}
+	return false
+}
+
+// Asset is an issued denomination
+type Asset struct {
+	Denom    string         `json:"denom"`
+	Issuer   common.Address `json:"issuer"` // account allowed to mint
+	Decimals uint8          `json:"decimals"`
+	Supply   string         `json:"supply"` // total supply in base units
+	Mintable bool           `json:"mintable"`
+}
+
+// SupplyValue returns the total supply of the asset.
+func (a Asset) SupplyValue() *big.Int {
+	supply, _ := new(big.Int).SetString(a.Supply, 10)
+	return supply
+}
+
+// parseAmount reads a positive amount of base units
+func parseAmount(s string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, ErrBadAmount()
+	}
+	return amount, nil
+}
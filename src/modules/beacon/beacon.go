@@ -0,0 +1,145 @@
+// Package beacon keeps the VRF seeds of the last blocks for contracts. The
+// seed of a block comes from the VRF proof of its proposer, so unlike the
+// block hash it can't be ground by the proposer. Contracts read it through
+// the precompile at BeaconAddress.
+package beacon
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/state"
+
+	"github.com/dora/ultron/modules/upgrade"
+	"github.com/dora/ultron/substore"
+)
+
+// History is the number of blocks whose seed stays available, as for
+// BLOCKHASH.
+const History = 256
+
+// SeedPrefix is the store prefix of the seeds: prefix|height
+var SeedPrefix = []byte{0x05}
+
+// SeedsUpgrade is the upgrade from which on the seeds are kept in the store
+// and the precompile answers. Both change the app hash and the results of
+// the blocks, so the chain switches at the height it is scheduled at.
+const SeedsUpgrade = "beacon-seeds"
+
+func init() {
+	substore.Register("beacon", SeedPrefix)
+	// nothing to migrate, the blocks before it have no seed
+	upgrade.RegisterHandler(SeedsUpgrade, func(state.SimpleDB) error { return nil })
+}
+
+func seedKey(height int64) []byte {
+	key := make([]byte, len(SeedPrefix)+8)
+	copy(key, SeedPrefix)
+	binary.BigEndian.PutUint64(key[len(SeedPrefix):], uint64(height))
+	return key
+}
+
+// Seeds are the seeds of the last blocks of a node, kept in memory for the
+// precompile. Every node has its own, see Use.
+type Seeds struct {
+	mtx     sync.RWMutex
+	seeds   map[int64][]byte
+	current int64 // height of the block being run
+	enabled bool  // SeedsUpgrade is done
+}
+
+// NewSeeds returns empty seeds, see Load.
+func NewSeeds() *Seeds {
+	return &Seeds{seeds: make(map[int64][]byte)}
+}
+
+// Set records the seed of the block at height, it is called when the block
+// begins so its txs see it. A nil seed, for a block whose proposer gave
+// none, leaves the block without one. It does nothing before SeedsUpgrade.
+func (s *Seeds) Set(store state.SimpleDB, height int64, seed []byte) {
+	if !upgrade.Done(store, SeedsUpgrade) {
+		return
+	}
+	if seed != nil {
+		store.Set(seedKey(height), seed)
+	}
+	store.Remove(seedKey(height - History))
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if seed != nil {
+		s.seeds[height] = seed
+	}
+	delete(s.seeds, height-History)
+	s.current = height
+	s.enabled = true
+}
+
+// Load reads the seeds kept in store, it must be called when the app starts
+// and after the store is rewritten. height is the last block committed.
+func (s *Seeds) Load(store state.SimpleDB, height int64) {
+	loaded := make(map[int64][]byte)
+	for _, m := range substore.New(store, SeedPrefix).List(nil, nil, 0) {
+		loaded[int64(binary.BigEndian.Uint64(m.Key))] = m.Value
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.seeds = loaded
+	s.current = height
+	s.enabled = upgrade.Done(store, SeedsUpgrade)
+}
+
+// Get returns the seed of the block at height, 0 for the block being run,
+// and false if it is unknown or too old.
+func (s *Seeds) Get(height int64) ([]byte, bool) {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	if height == 0 {
+		height = s.current
+	}
+	seed, ok := s.seeds[height]
+	return seed, ok
+}
+
+var (
+	useMtx sync.Mutex // held from Use to the release
+
+	activeMtx sync.RWMutex
+	active    = NewSeeds() // read by the precompile
+)
+
+// Use points the precompile at seeds until the returned release func is
+// called. Nodes sharing a process hold it around the blocks they run so
+// each one reads its own seeds, see stake.UseDatabase. Calls outside of a
+// block, such as eth_call, read the seeds of the node that ran the last one.
+func Use(seeds *Seeds) (release func()) {
+	useMtx.Lock()
+	setActive(seeds)
+	return useMtx.Unlock
+}
+
+func setActive(seeds *Seeds) {
+	activeMtx.Lock()
+	active = seeds
+	activeMtx.Unlock()
+}
+
+// Enabled tells if the precompile answers, from SeedsUpgrade on.
+func (s *Seeds) Enabled() bool {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+	return s.enabled
+}
+
+// GetSeed returns the seed of the block at height of the seeds in use, see
+// Seeds.Get.
+func GetSeed(height int64) ([]byte, bool) {
+	return activeSeeds().Get(height)
+}
+
+func activeSeeds() *Seeds {
+	activeMtx.RLock()
+	defer activeMtx.RUnlock()
+	return active
+}
@@ -0,0 +1,108 @@
+package beacon
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+func input(height int64, salt byte) []byte {
+	return append(math.PaddedBigBytes(big.NewInt(height), 32), common.LeftPadBytes([]byte{salt}, 32)...)
+}
+
+// upgraded returns a store past SeedsUpgrade
+func upgraded() state.SimpleDB {
+	store := state.NewMemKVStore()
+	store.Set(upgrade.DoneKey(SeedsUpgrade), []byte{1})
+	return store
+}
+
+func TestSeedOfBlockBeingRun(t *testing.T) {
+	store := upgraded()
+	seeds := NewSeeds()
+	defer Use(seeds)()
+	c := &seedPrecompile{}
+	seeds.Set(store, 5, []byte("five"))
+
+	out, err := c.Run(input(0, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, crypto.Keccak256([]byte("five"), common.LeftPadBytes([]byte{1}, 32)), out)
+
+	// the proposer of 6 gave no seed, 6 must not read the seed of 5
+	seeds.Set(store, 6, nil)
+	_, err = c.Run(input(0, 1))
+	assert.Equal(t, ErrNoSeed, err)
+	_, err = c.Run(input(6, 1))
+	assert.Equal(t, ErrNoSeed, err)
+	out2, err := c.Run(input(5, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, out, out2)
+
+	_, err = c.Run(input(7, 1))
+	assert.Equal(t, ErrNoSeed, err, "future block")
+
+	// reloaded after 6 was committed
+	seeds.Load(store, 6)
+	_, err = c.Run(input(0, 1))
+	assert.Equal(t, ErrNoSeed, err)
+	_, ok := GetSeed(5)
+	assert.True(t, ok)
+}
+
+func TestSeedHistory(t *testing.T) {
+	store := upgraded()
+	seeds := NewSeeds()
+	seeds.Set(store, 1, []byte("one"))
+	seeds.Set(store, 1+History, []byte("later"))
+
+	_, ok := seeds.Get(1)
+	assert.False(t, ok)
+	assert.Nil(t, store.Get(seedKey(1)))
+	seed, ok := seeds.Get(0)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("later"), seed)
+}
+
+func TestSeedsOfEachNode(t *testing.T) {
+	node1, node2 := NewSeeds(), NewSeeds()
+	node1.Set(upgraded(), 5, []byte("five"))
+	node2.Set(upgraded(), 3, []byte("three"))
+
+	release := Use(node1)
+	seed, ok := GetSeed(0)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("five"), seed)
+	release()
+
+	defer Use(node2)()
+	seed, _ = GetSeed(0)
+	assert.Equal(t, []byte("three"), seed)
+}
+
+func TestSeedsBeforeUpgrade(t *testing.T) {
+	store := state.NewMemKVStore()
+	seeds := NewSeeds()
+	defer Use(seeds)()
+	c := &seedPrecompile{}
+	seeds.Set(store, 5, []byte("five"))
+
+	// nothing kept, the precompile runs as an account without code
+	assert.Nil(t, store.Get(seedKey(5)))
+	assert.Equal(t, uint64(0), c.RequiredGas(input(0, 1)))
+	out, err := c.Run(input(0, 1))
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+
+	store.Set(upgrade.DoneKey(SeedsUpgrade), []byte{1})
+	seeds.Set(store, 6, []byte("six"))
+	assert.Equal(t, uint64(seedGas), c.RequiredGas(input(0, 1)))
+	_, err = c.Run(input(0, 1))
+	assert.Nil(t, err)
+}
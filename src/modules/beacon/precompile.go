@@ -0,0 +1,57 @@
+package beacon
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BeaconAddress is the precompile contracts call for randomness. The input
+// is the abi encoded (uint256 height, bytes32 salt), a zero height being the
+// block being run, the output is keccak256(seed, salt). The call fails if
+// the block has no seed or it is no longer kept. Contracts should commit to
+// a future height and mix in a salt of their own, e.g. the id of a draw:
+//
+//	(bool ok, bytes memory out) = BEACON.staticcall(abi.encode(drawHeight, drawId));
+//	bytes32 random = abi.decode(out, (bytes32));
+var BeaconAddress = common.HexToAddress("0x0000000000000000000000000000000000000101")
+
+const seedGas = 3000
+
+// ErrNoSeed is returned by the precompile for a block without a seed
+var ErrNoSeed = errors.New("no seed for the block")
+
+func init() {
+	vm.PrecompiledContractsHomestead[BeaconAddress] = &seedPrecompile{}
+	vm.PrecompiledContractsByzantium[BeaconAddress] = &seedPrecompile{}
+}
+
+// seedPrecompile costs nothing and returns nothing before SeedsUpgrade, as
+// the account without code at its address did.
+type seedPrecompile struct{}
+
+func (c *seedPrecompile) RequiredGas(input []byte) uint64 {
+	if !activeSeeds().Enabled() {
+		return 0
+	}
+	return seedGas
+}
+
+func (c *seedPrecompile) Run(input []byte) ([]byte, error) {
+	if !activeSeeds().Enabled() {
+		return nil, nil
+	}
+	input = common.RightPadBytes(input, 64)
+	height := new(big.Int).SetBytes(input[:32])
+	if height.BitLen() > 63 {
+		return nil, ErrNoSeed
+	}
+	seed, ok := GetSeed(height.Int64())
+	if !ok {
+		return nil, ErrNoSeed
+	}
+	return crypto.Keccak256(seed, input[32:64]), nil
+}
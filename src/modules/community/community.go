@@ -0,0 +1,88 @@
+package community
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+
+	"github.com/dora/ultron/commons"
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/params"
+)
+
+// TaxRate is the key of the share of the block award going to the pool.
+const TaxRate = "community.tax"
+
+func init() {
+	params.Register(params.Param{
+		Key:     TaxRate,
+		Type:    params.TypeDecimal,
+		Default: "0", // off until governance sets it, so running chains replay the same
+		Min:     "0",
+		Max:     "1",
+		Doc:     "share of the fees and the minted award of each block going to the community pool",
+	})
+}
+
+// Tax mints the community tax of amount, a part of the block award, into
+// the pool and returns what is left of amount for the validators.
+func Tax(store state.SimpleDB, amount *big.Int) *big.Int {
+	tax := taxOf(amount)
+	if tax.Sign() > 0 {
+		commons.Transfer(constant.MintAccount, constant.CommunityAccount, tax)
+		pool := Pool(store)
+		savePool(store, pool.Add(pool, tax))
+	}
+	return new(big.Int).Sub(amount, tax)
+}
+
+// AfterTax returns what the community tax leaves of amount.
+func AfterTax(amount *big.Int) *big.Int {
+	return new(big.Int).Sub(amount, taxOf(amount))
+}
+
+func taxOf(amount *big.Int) *big.Int {
+	rate := params.Rat(TaxRate)
+	tax := new(big.Int).Mul(amount, rate.Num())
+	return tax.Quo(tax, rate.Denom())
+}
+
+// EndBlock pays out the spends due at height, in the order they were
+// proposed. A spend the pool can't cover is dropped. It returns what became
+// of the spends.
+func EndBlock(store state.SimpleDB, height int64) []Event {
+	spends := loadSpends(store)
+	if len(spends) == 0 {
+		return nil
+	}
+	pool := Pool(store)
+	var events []Event
+	pending := spends[:0]
+	for _, spend := range spends {
+		if spend.Height > height {
+			pending = append(pending, spend)
+			continue
+		}
+		ev := Event{Spend: spend}
+		if amount := spend.AmountValue(); amount.Cmp(pool) <= 0 {
+			commons.Transfer(constant.CommunityAccount, spend.Recipient, amount)
+			pool.Sub(pool, amount)
+			ev.Paid = true
+		}
+		events = append(events, ev)
+	}
+	if len(events) > 0 {
+		savePool(store, pool)
+		saveSpends(store, pending)
+	}
+	return events
+}
+
+// parseAmount reads a positive amount of wei
+func parseAmount(s string) (*big.Int, error) {
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok || amount.Sign() <= 0 {
+		return nil, ErrBadAmount()
+	}
+	return amount, nil
+}
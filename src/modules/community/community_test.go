@@ -0,0 +1,54 @@
+package community
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dora/ultron/modules/params"
+)
+
+func TestTax(t *testing.T) {
+	store := state.NewMemKVStore()
+	params.Load(store)
+	assert.Equal(t, big.NewInt(1000), Tax(store, big.NewInt(1000)), "no tax by default")
+	assert.Equal(t, 0, Pool(store).Sign())
+
+	store.Set(params.ValueKey(TaxRate), []byte("0.02"))
+	params.Load(store)
+	assert.Equal(t, big.NewInt(980), Tax(store, big.NewInt(1000)))
+	assert.Equal(t, big.NewInt(49), Tax(store, big.NewInt(50)))
+	assert.Equal(t, big.NewInt(21), Pool(store))
+	assert.Equal(t, big.NewInt(98), AfterTax(big.NewInt(100)))
+}
+
+func TestEndBlock(t *testing.T) {
+	store := state.NewMemKVStore()
+	savePool(store, big.NewInt(100))
+	recipient := common.HexToAddress("0x7eff122b94897ea5b0e2a9abf47b86337fafebdc")
+	saveSpends(store, []Spend{
+		{ID: 1, Recipient: recipient, Amount: "60", Height: 10},
+		{ID: 2, Recipient: recipient, Amount: "60", Height: 10},
+		{ID: 3, Recipient: recipient, Amount: "30", Height: 11},
+	})
+
+	assert.Empty(t, EndBlock(store, 9))
+	events := EndBlock(store, 10)
+	require.Len(t, events, 2)
+	assert.True(t, events[0].Paid)
+	assert.False(t, events[1].Paid, "the pool is short of the second spend")
+	assert.Equal(t, big.NewInt(40), Pool(store))
+
+	spends := loadSpends(store)
+	require.Len(t, spends, 1)
+	assert.Equal(t, uint64(3), spends[0].ID)
+	events = EndBlock(store, 11)
+	require.Len(t, events, 1)
+	assert.True(t, events[0].Paid)
+	assert.Equal(t, big.NewInt(10), Pool(store))
+	assert.Empty(t, loadSpends(store))
+}
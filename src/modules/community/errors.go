@@ -0,0 +1,35 @@
+// nolint
+package community
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/errors"
+)
+
+var (
+	errNoRecipient      = fmt.Errorf("Spend needs a recipient")
+	errBadAmount        = fmt.Errorf("Amount must be a positive number of wei")
+	errBadHeight        = fmt.Errorf("Spend height must be after the current block")
+	errNotAuthority     = fmt.Errorf("Sender is not the params authority")
+	errMissingSignature = fmt.Errorf("Missing signature")
+)
+
+func ErrNoRecipient() error {
+	return errors.WithCode(errNoRecipient, errors.CodeTypeBaseInvalidInput)
+}
+func ErrBadAmount() error {
+	return errors.WithCode(errBadAmount, errors.CodeTypeBaseInvalidInput)
+}
+func ErrBadHeight() error {
+	return errors.WithCode(errBadHeight, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNoSpend(id uint64) error {
+	return errors.WithCode(fmt.Errorf("No pending spend %d", id), errors.CodeTypeBaseInvalidInput)
+}
+func ErrNotAuthority() error {
+	return errors.WithCode(errNotAuthority, errors.CodeTypeUnauthorized)
+}
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSignature, errors.CodeTypeUnauthorized)
+}
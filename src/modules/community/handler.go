@@ -0,0 +1,79 @@
+package community
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/types"
+)
+
+type CommunityTxHandler struct {
+}
+
+// InitState - the community pool has no genesis parameters
+func (h *CommunityTxHandler) InitState(key, value string, store state.SimpleDB) error {
+	return errors.ErrUnknownKey(key)
+}
+
+// CheckTx checks if the tx is properly structured and sent by the authority
+func (h *CommunityTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+	return res, h.apply(ctx, store, tx, false)
+}
+
+// DeliverTx proposes or cancels the spend
+func (h *CommunityTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.DeliverResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+	return res, h.apply(ctx, store, tx, true)
+}
+
+// apply checks tx against store, executing it if deliver
+func (h *CommunityTxHandler) apply(ctx types.Context, store state.SimpleDB, tx sdk.Tx, deliver bool) error {
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		return ErrMissingSignature()
+	}
+	authority, ok := params.Authority(store)
+	if !ok || senders[0] != authority {
+		return ErrNotAuthority()
+	}
+
+	switch _tx := tx.Unwrap().(type) {
+	case TxSpend:
+		if _tx.Height <= ctx.BlockHeight() {
+			return ErrBadHeight()
+		}
+		if deliver {
+			spend := Spend{
+				ID:        nextSpendID(store),
+				Recipient: _tx.Recipient,
+				Amount:    _tx.Amount,
+				Height:    _tx.Height,
+				Reason:    _tx.Reason,
+			}
+			saveSpends(store, append(loadSpends(store), spend))
+		}
+	case TxCancelSpend:
+		spends := loadSpends(store)
+		i := 0
+		for i < len(spends) && spends[i].ID != _tx.ID {
+			i++
+		}
+		if i == len(spends) {
+			return ErrNoSpend(_tx.ID)
+		}
+		if deliver {
+			saveSpends(store, append(spends[:i], spends[i+1:]...))
+		}
+	default:
+		return errors.ErrUnknownTxType(tx)
+	}
+	return nil
+}
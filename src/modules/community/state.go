@@ -0,0 +1,72 @@
+package community
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	// Keys for store prefixes
+	PoolKey    = []byte{0x25} // wei in the pool, big endian
+	SpendsKey  = []byte{0x26} // pending spends, by id
+	SpendIDKey = []byte{0x27} // id of the last spend proposed
+)
+
+func init() {
+	substore.Register("community", PoolKey, SpendsKey, SpendIDKey)
+}
+
+// Pool returns the wei in the community pool.
+func Pool(store state.SimpleDB) *big.Int {
+	return new(big.Int).SetBytes(store.Get(PoolKey))
+}
+
+func savePool(store state.SimpleDB, pool *big.Int) {
+	store.Set(PoolKey, pool.Bytes())
+}
+
+// ParseSpends reads the pending spends as kept in store.
+func ParseSpends(b []byte) ([]Spend, error) {
+	var spends []Spend
+	if len(b) == 0 {
+		return spends, nil
+	}
+	if err := wire.ReadBinaryBytes(b, &spends); err != nil {
+		return nil, err
+	}
+	return spends, nil
+}
+
+func loadSpends(store state.SimpleDB) []Spend {
+	spends, err := ParseSpends(store.Get(SpendsKey))
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return spends
+}
+
+func saveSpends(store state.SimpleDB, spends []Spend) {
+	if len(spends) == 0 {
+		store.Remove(SpendsKey)
+		return
+	}
+	store.Set(SpendsKey, wire.BinaryBytes(spends))
+}
+
+func nextSpendID(store state.SimpleDB) uint64 {
+	var id uint64
+	if b := store.Get(SpendIDKey); b != nil {
+		id = binary.BigEndian.Uint64(b)
+	}
+	id++
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	store.Set(SpendIDKey, b)
+	return id
+}
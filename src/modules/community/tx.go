@@ -0,0 +1,74 @@
+package community
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/dora/ultron/const"
+)
+
+// nolint
+const (
+	ByteTxSpend       = 0x84
+	ByteTxCancelSpend = 0x85
+	TypeTxSpend       = constant.ModuleNameCommunity + "/spend"
+	TypeTxCancelSpend = constant.ModuleNameCommunity + "/cancel_spend"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxSpend{}, TypeTxSpend, ByteTxSpend)
+	sdk.TxMapper.RegisterImplementation(TxCancelSpend{}, TypeTxCancelSpend, ByteTxCancelSpend)
+}
+
+// Verify interface at compile time
+var _, _ sdk.TxInner = TxSpend{}, TxCancelSpend{}
+
+// TxSpend proposes paying Amount wei out of the pool to Recipient at Height.
+// It can only be sent by the params authority.
+type TxSpend struct {
+	Recipient common.Address `json:"recipient"`
+	Amount    string         `json:"amount"`
+	Height    int64          `json:"height"`
+	Reason    string         `json:"reason,omitempty"`
+}
+
+func (tx TxSpend) ValidateBasic() error {
+	if tx.Recipient == (common.Address{}) {
+		return ErrNoRecipient()
+	}
+	if _, err := parseAmount(tx.Amount); err != nil {
+		return err
+	}
+	if tx.Height <= 0 {
+		return ErrBadHeight()
+	}
+	return nil
+}
+
+func NewTxSpend(recipient common.Address, amount string, height int64, reason string) sdk.Tx {
+	return TxSpend{
+		Recipient: recipient,
+		Amount:    amount,
+		Height:    height,
+		Reason:    reason,
+	}.Wrap()
+}
+
+func (tx TxSpend) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxCancelSpend drops the pending spend ID, sent by the params authority.
+type TxCancelSpend struct {
+	ID uint64 `json:"id"`
+}
+
+func (tx TxCancelSpend) ValidateBasic() error {
+	return nil
+}
+
+func NewTxCancelSpend(id uint64) sdk.Tx {
+	return TxCancelSpend{
+		ID: id,
+	}.Wrap()
+}
+
+func (tx TxCancelSpend) Wrap() sdk.Tx { return sdk.Tx{tx} }
@@ -0,0 +1,36 @@
+// Package community keeps a community pool funded by a tax on the block
+// award: the community.tax share of the fees and of the minted award goes
+// to the pool instead of the validators. The ether is held by
+// constant.CommunityAccount and the pool balance in the store. Only the
+// params authority can spend it, by proposing a spend to a recipient that is
+// paid out at the end of the block at the height of the proposal, if the
+// pool holds the amount then.
+package community
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Spend is a payment out of the pool waiting for its height.
+type Spend struct {
+	ID        uint64         `json:"id"`
+	Recipient common.Address `json:"recipient"`
+	Amount    string         `json:"amount"` // wei
+	Height    int64          `json:"height"` // of the block it is paid out at
+	Reason    string         `json:"reason,omitempty"`
+}
+
+// AmountValue returns the amount of the spend.
+func (s Spend) AmountValue() *big.Int {
+	amount, _ := new(big.Int).SetString(s.Amount, 10)
+	return amount
+}
+
+// Event is posted on the event mux of the node when a spend was paid out,
+// or dropped as the pool was short of it.
+type Event struct {
+	Spend
+	Paid bool `json:"paid"`
+}
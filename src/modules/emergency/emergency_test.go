@@ -0,0 +1,42 @@
+package emergency
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dora/ultron/types"
+)
+
+func TestPause(t *testing.T) {
+	store := state.NewMemKVStore()
+	h := &EmergencyTxHandler{}
+	members := []common.Address{{1}, {2}, {3}}
+	for _, m := range members {
+		require.Nil(t, h.InitState("member", m.Hex(), store))
+	}
+	require.Nil(t, h.InitState("threshold", "2", store))
+
+	deliver := func(sender common.Address, height int64, tx TxPause) error {
+		ctx := types.NewContext("test", height, nil)
+		ctx.WithSigners(sender)
+		_, err := h.DeliverTx(ctx, store, tx.Wrap())
+		return err
+	}
+	pause := TxPause{Class: ClassDeploy, Blocks: 10}
+
+	assert.NotNil(t, deliver(common.Address{4}, 1, pause))
+	require.Nil(t, deliver(members[0], 1, pause))
+	require.Nil(t, deliver(members[0], 1, pause))
+	assert.Nil(t, CheckClass(store, ClassDeploy, 1), "one approval")
+
+	require.Nil(t, deliver(members[1], 2, pause))
+	assert.NotNil(t, CheckClass(store, ClassDeploy, 2))
+	assert.NotNil(t, CheckClass(store, ClassDeploy, 11))
+	assert.Nil(t, CheckClass(store, ClassDeploy, 12), "pause ran out")
+	assert.Nil(t, CheckClass(store, ClassWithdraw, 2))
+	assert.Nil(t, loadProposal(store, ClassDeploy))
+}
@@ -0,0 +1,41 @@
+// nolint
+package emergency
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/errors"
+)
+
+var (
+	errNoCouncil        = fmt.Errorf("No emergency council set")
+	errNotMember        = fmt.Errorf("Sender is not a member of the emergency council")
+	errBadThreshold     = fmt.Errorf("Threshold must be between 1 and the number of members")
+	errNotPaused        = fmt.Errorf("Class is not paused")
+	errMissingSignature = fmt.Errorf("Missing signature")
+)
+
+func ErrUnknownClass(class string) error {
+	return errors.WithCode(fmt.Errorf("Unknown tx class %s", class), errors.CodeTypeBaseInvalidInput)
+}
+func ErrBadBlocks(max int64) error {
+	return errors.WithCode(fmt.Errorf("Blocks must be between 1 and %d", max), errors.CodeTypeBaseInvalidInput)
+}
+func ErrPaused(class string, until int64) error {
+	return errors.WithCode(fmt.Errorf("Txs of class %s are paused until block %d", class, until), errors.CodeTypeUnauthorized)
+}
+func ErrNoCouncil() error {
+	return errors.WithCode(errNoCouncil, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNotMember() error {
+	return errors.WithCode(errNotMember, errors.CodeTypeUnauthorized)
+}
+func ErrBadThreshold() error {
+	return errors.WithCode(errBadThreshold, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNotPaused() error {
+	return errors.WithCode(errNotPaused, errors.CodeTypeBaseInvalidInput)
+}
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSignature, errors.CodeTypeUnauthorized)
+}
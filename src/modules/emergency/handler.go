@@ -0,0 +1,139 @@
+package emergency
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type EmergencyTxHandler struct {
+}
+
+// InitState - add a council member or set the threshold of the council
+func (h *EmergencyTxHandler) InitState(key, value string, store state.SimpleDB) error {
+	council := loadCouncil(store)
+	if council == nil {
+		council = new(Council)
+	}
+
+	switch key {
+	case "member":
+		if !common.IsHexAddress(value) {
+			return fmt.Errorf("input must be an address: %s", value)
+		}
+		council.Members = append(council.Members, common.HexToAddress(value))
+	case "threshold":
+		threshold, err := strconv.ParseUint(value, 10, 16)
+		if err != nil {
+			return fmt.Errorf("input must be a positive integer, Error: %v", err)
+		}
+		if threshold == 0 {
+			return ErrBadThreshold()
+		}
+		council.Threshold = uint16(threshold)
+	default:
+		return errors.ErrUnknownKey(key)
+	}
+	saveCouncil(store, *council)
+	return nil
+}
+
+// CheckTx checks if the tx is properly structured and allowed
+func (h *EmergencyTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+
+	sender, err := h.getTxSender(ctx)
+	if err != nil {
+		return res, err
+	}
+	return res, h.apply(ctx, store, sender, tx, false)
+}
+
+// DeliverTx executes the tx if valid
+func (h *EmergencyTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.DeliverResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+
+	sender, err := h.getTxSender(ctx)
+	if err != nil {
+		return
+	}
+	return res, h.apply(ctx, store, sender, tx, true)
+}
+
+// apply checks tx against store, executing it if deliver
+func (h *EmergencyTxHandler) apply(ctx types.Context, store state.SimpleDB, sender common.Address, tx sdk.Tx, deliver bool) error {
+	council := loadCouncil(store)
+	if council == nil || len(council.Members) == 0 {
+		return ErrNoCouncil()
+	}
+	if council.Threshold == 0 || int(council.Threshold) > len(council.Members) {
+		return ErrBadThreshold()
+	}
+	if !council.isMember(sender) {
+		return ErrNotMember()
+	}
+
+	var class string
+	var blocks int64
+	switch _tx := tx.Unwrap().(type) {
+	case TxPause:
+		class, blocks = _tx.Class, _tx.Blocks
+	case TxResume:
+		if Paused(store, _tx.Class, ctx.BlockHeight()) == nil {
+			return ErrNotPaused()
+		}
+		class = _tx.Class
+	default:
+		return errors.ErrUnknownTxType(tx)
+	}
+	if !deliver {
+		return nil
+	}
+
+	proposal := loadProposal(store, class)
+	if proposal == nil || proposal.Blocks != blocks {
+		proposal = &Proposal{Class: class, Blocks: blocks}
+	}
+	if !proposal.approved(sender) {
+		proposal.Approvals = append(proposal.Approvals, sender)
+	}
+	if len(proposal.Approvals) < int(council.Threshold) {
+		saveProposal(store, proposal, class)
+		return nil
+	}
+
+	// approved, pause or resume right away
+	ev := Event{Pause: Pause{Class: class, Height: ctx.BlockHeight()}, Approvals: proposal.Approvals}
+	if blocks > 0 {
+		ev.Until = ctx.BlockHeight() + blocks
+		savePause(store, &ev.Pause, class)
+	} else {
+		savePause(store, nil, class)
+	}
+	saveProposal(store, nil, class)
+	// posted aside, a slow subscriber must not hold the block
+	if ctx.Ethereum() != nil {
+		go ctx.Ethereum().EventMux().Post(ev) // nolint: errcheck
+	}
+	return nil
+}
+
+// get the sender from the ctx
+func (h *EmergencyTxHandler) getTxSender(ctx types.Context) (sender common.Address, err error) {
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		return sender, ErrMissingSignature()
+	}
+	return senders[0], nil
+}
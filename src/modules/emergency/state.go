@@ -0,0 +1,123 @@
+package emergency
+
+import (
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	// Keys for store prefixes
+	CouncilKey     = []byte{0x1d} // the council
+	PausePrefix    = []byte{0x1e} // pauses: prefix|class
+	ProposalPrefix = []byte{0x1f} // pending proposals: prefix|class
+)
+
+func init() {
+	substore.Register("emergency", CouncilKey, PausePrefix, ProposalPrefix)
+}
+
+// PauseKey is the store key of the pause of class.
+func PauseKey(class string) []byte {
+	return append(append([]byte{}, PausePrefix...), []byte(class)...)
+}
+
+// ProposalKey is the store key of the pending proposal of class.
+func ProposalKey(class string) []byte {
+	return append(append([]byte{}, ProposalPrefix...), []byte(class)...)
+}
+
+func loadCouncil(store state.SimpleDB) *Council {
+	b := store.Get(CouncilKey)
+	if b == nil {
+		return nil
+	}
+	council := new(Council)
+	if err := wire.ReadBinaryBytes(b, council); err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return council
+}
+
+func saveCouncil(store state.SimpleDB, council Council) {
+	store.Set(CouncilKey, wire.BinaryBytes(council))
+}
+
+// ParsePause reads a pause as kept in store, nil if there is none.
+func ParsePause(b []byte) (*Pause, error) {
+	if b == nil {
+		return nil, nil
+	}
+	pause := new(Pause)
+	if err := wire.ReadBinaryBytes(b, pause); err != nil {
+		return nil, err
+	}
+	return pause, nil
+}
+
+func loadPause(store state.SimpleDB, class string) *Pause {
+	pause, err := ParsePause(store.Get(PauseKey(class)))
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return pause
+}
+
+func savePause(store state.SimpleDB, pause *Pause, class string) {
+	if pause == nil {
+		store.Remove(PauseKey(class))
+		return
+	}
+	store.Set(PauseKey(class), wire.BinaryBytes(*pause))
+}
+
+// ParseProposal reads a proposal as kept in store, nil if there is none.
+func ParseProposal(b []byte) (*Proposal, error) {
+	if b == nil {
+		return nil, nil
+	}
+	proposal := new(Proposal)
+	if err := wire.ReadBinaryBytes(b, proposal); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+func loadProposal(store state.SimpleDB, class string) *Proposal {
+	proposal, err := ParseProposal(store.Get(ProposalKey(class)))
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return proposal
+}
+
+func saveProposal(store state.SimpleDB, proposal *Proposal, class string) {
+	if proposal == nil {
+		store.Remove(ProposalKey(class))
+		return
+	}
+	store.Set(ProposalKey(class), wire.BinaryBytes(*proposal))
+}
+
+// Paused returns the pause of class in effect at height, nil if the class
+// isn't paused.
+func Paused(store state.SimpleDB, class string, height int64) *Pause {
+	pause := loadPause(store, class)
+	if pause == nil || height >= pause.Until {
+		return nil
+	}
+	return pause
+}
+
+// CheckClass rejects the txs of class while it is paused.
+func CheckClass(store state.SimpleDB, class string, height int64) error {
+	if class == "" {
+		return nil
+	}
+	if pause := Paused(store, class, height); pause != nil {
+		return ErrPaused(class, pause.Until)
+	}
+	return nil
+}
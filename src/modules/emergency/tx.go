@@ -0,0 +1,86 @@
+package emergency
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/params"
+)
+
+// nolint
+const (
+	ByteTxPause  = 0x80
+	ByteTxResume = 0x81
+	TypeTxPause  = constant.ModuleNameEmergency + "/pause"
+	TypeTxResume = constant.ModuleNameEmergency + "/resume"
+
+	MaxPauseBlocks = "emergency.max_pause_blocks"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxPause{}, TypeTxPause, ByteTxPause)
+	sdk.TxMapper.RegisterImplementation(TxResume{}, TypeTxResume, ByteTxResume)
+
+	params.Register(params.Param{
+		Key:     MaxPauseBlocks,
+		Type:    params.TypeInteger,
+		Default: "60480",
+		Min:     "1",
+		Doc:     "longest the emergency council may pause a class of txs for, in blocks",
+	})
+}
+
+// Verify interface at compile time
+var _, _ sdk.TxInner = TxPause{}, TxResume{}
+
+// TxPause approves pausing the txs of Class for Blocks, by the council
+// member sending it. A pause with other terms replaces the pending one.
+type TxPause struct {
+	Class  string `json:"class"`
+	Blocks int64  `json:"blocks"`
+}
+
+func (tx TxPause) ValidateBasic() error {
+	if err := checkClass(tx.Class); err != nil {
+		return err
+	}
+	if max := params.Int64(MaxPauseBlocks); tx.Blocks < 1 || tx.Blocks > max {
+		return ErrBadBlocks(max)
+	}
+	return nil
+}
+
+func NewTxPause(class string, blocks int64) sdk.Tx {
+	return TxPause{
+		Class:  class,
+		Blocks: blocks,
+	}.Wrap()
+}
+
+func (tx TxPause) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxResume approves lifting the pause of Class before it runs out.
+type TxResume struct {
+	Class string `json:"class"`
+}
+
+func (tx TxResume) ValidateBasic() error {
+	return checkClass(tx.Class)
+}
+
+func NewTxResume(class string) sdk.Tx {
+	return TxResume{
+		Class: class,
+	}.Wrap()
+}
+
+func (tx TxResume) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+func checkClass(class string) error {
+	for _, c := range Classes() {
+		if c == class {
+			return nil
+		}
+	}
+	return ErrUnknownClass(class)
+}
@@ -0,0 +1,104 @@
+// Package emergency lets a council of accounts pause classes of txs, such as
+// contract deployment or bridge withdrawals, while an incident is dealt with.
+// A pause or an early resume takes effect once a threshold of the council
+// sent the same tx, the council acting as a multisig. Paused txs are rejected
+// from the tx pool and from blocks, everything else keeps running, consensus
+// included. A pause lifts by itself after the blocks it was approved for.
+package emergency
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nolint
+const (
+	ClassDeploy   = "deploy"   // contract creation, evm and wasm
+	ClassWithdraw = "withdraw" // funds leaving stake, or the chain through a bridge
+)
+
+var (
+	classesMtx sync.RWMutex
+	classes    = map[string]string{} // class by sdk tx kind
+)
+
+// RegisterClass adds the sdk tx kinds, e.g. "wasm/deploy", to class. Modules
+// register their kinds from init, a bridge its withdrawals as ClassWithdraw.
+func RegisterClass(class string, kinds ...string) {
+	classesMtx.Lock()
+	defer classesMtx.Unlock()
+	for _, kind := range kinds {
+		classes[kind] = class
+	}
+}
+
+// ClassOf returns the class of the sdk tx kind, "" if it has none.
+func ClassOf(kind string) string {
+	classesMtx.RLock()
+	defer classesMtx.RUnlock()
+	return classes[kind]
+}
+
+// Classes returns the classes that can be paused, sorted.
+func Classes() []string {
+	classesMtx.RLock()
+	defer classesMtx.RUnlock()
+	seen := map[string]bool{ClassDeploy: true}
+	for _, class := range classes {
+		seen[class] = true
+	}
+	all := make([]string, 0, len(seen))
+	for class := range seen {
+		all = append(all, class)
+	}
+	sort.Strings(all)
+	return all
+}
+
+// Council is the multisig allowed to pause txs.
+type Council struct {
+	Members   []common.Address `json:"members"`
+	Threshold uint16           `json:"threshold"` // approvals needed
+}
+
+func (c Council) isMember(addr common.Address) bool {
+	for _, member := range c.Members {
+		if member == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Pause is a class of txs rejected until the block at Until.
+type Pause struct {
+	Class  string `json:"class"`
+	Height int64  `json:"height"` // block it took effect at
+	Until  int64  `json:"until"`
+}
+
+// Proposal is a pause or resume of a class waiting for approvals, Blocks is
+// 0 for a resume.
+type Proposal struct {
+	Class     string           `json:"class"`
+	Blocks    int64            `json:"blocks"`
+	Approvals []common.Address `json:"approvals"`
+}
+
+func (p Proposal) approved(addr common.Address) bool {
+	for _, approval := range p.Approvals {
+		if approval == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is posted on the event mux of the node when a class is paused or
+// resumed early, a resume has Until 0.
+type Event struct {
+	Pause
+	Approvals []common.Address `json:"approvals"`
+}
@@ -0,0 +1,143 @@
+// Package mint inflates the ether supply along a curve steered by the
+// bonded ratio: every block the yearly inflation moves toward the maximum
+// while less than the goal share of the supply is bonded, and toward the
+// minimum while more is, the faster the further the ratio is from the goal,
+// at mint.inflation_rate_change a year with nothing bonded. The provision of
+// the block joins the fees in the block award. All the terms are parameters,
+// so the params authority adjusts the curve.
+package mint
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+
+	"github.com/dora/ultron/modules/params"
+)
+
+// Keys of the mint parameters
+const (
+	Enabled             = "mint.enabled"
+	InflationMin        = "mint.inflation_min"
+	InflationMax        = "mint.inflation_max"
+	InflationRateChange = "mint.inflation_rate_change"
+	GoalBonded          = "mint.goal_bonded"
+	BlocksPerYear       = "mint.blocks_per_year"
+)
+
+// decimals the inflation is rounded to, so its fraction doesn't grow with
+// every block
+const precision = 18
+
+func init() {
+	params.Register(params.Param{
+		Key:     Enabled,
+		Type:    params.TypeBool,
+		Default: "false",
+		Doc:     "whether the block award is minted along the inflation curve, else at distribution.inflation_rate",
+	})
+	params.Register(params.Param{
+		Key:     InflationMin,
+		Type:    params.TypeDecimal,
+		Default: "0.05",
+		Min:     "0",
+		Max:     "1",
+		Doc:     "lowest yearly inflation",
+	})
+	params.Register(params.Param{
+		Key:     InflationMax,
+		Type:    params.TypeDecimal,
+		Default: "0.2",
+		Min:     "0",
+		Max:     "1",
+		Doc:     "highest yearly inflation",
+	})
+	params.Register(params.Param{
+		Key:     InflationRateChange,
+		Type:    params.TypeDecimal,
+		Default: "0.13",
+		Min:     "0",
+		Max:     "1",
+		Doc:     "most the yearly inflation moves in a year",
+	})
+	params.Register(params.Param{
+		Key:     GoalBonded,
+		Type:    params.TypeDecimal,
+		Default: "0.67",
+		Min:     "0.01",
+		Max:     "1",
+		Doc:     "share of the supply the inflation aims to have bonded",
+	})
+	params.Register(params.Param{
+		Key:     BlocksPerYear,
+		Type:    params.TypeInteger,
+		Default: "3153600",
+		Min:     "1",
+		Doc:     "blocks expected in a year, the inflation and provisions are spread over",
+	})
+}
+
+// Minter is the state of the inflation after the last block.
+type Minter struct {
+	Inflation        string `json:"inflation"`         // yearly, decimal
+	AnnualProvisions string `json:"annual_provisions"` // wei the inflation mints in a year
+	BlockProvision   string `json:"block_provision"`   // wei minted by the last block
+}
+
+// BeginBlock moves the inflation for a block with bonded of supply wei
+// bonded, and returns the wei the block mints into its award, nil unless
+// the mint is enabled. The inflation starts at its minimum.
+func BeginBlock(store state.SimpleDB, bonded, supply *big.Int) *big.Int {
+	if !params.Bool(Enabled) {
+		return nil
+	}
+	inflation := params.Rat(InflationMin)
+	if minter := loadMinter(store); minter != nil {
+		inflation, _ = new(big.Rat).SetString(minter.Inflation)
+	}
+	ratio := new(big.Rat)
+	if supply.Sign() > 0 {
+		ratio.SetFrac(bonded, supply)
+	}
+	inflation = nextInflation(inflation, ratio)
+
+	blocks := params.BigInt(BlocksPerYear)
+	annual := new(big.Int).Mul(supply, inflation.Num())
+	annual.Quo(annual, inflation.Denom())
+	provision := new(big.Int).Quo(annual, blocks)
+	saveMinter(store, Minter{
+		Inflation:        inflation.FloatString(precision),
+		AnnualProvisions: annual.String(),
+		BlockProvision:   provision.String(),
+	})
+	return provision
+}
+
+// BlockProvision returns the wei the last block minted, nil unless the mint
+// is enabled.
+func BlockProvision(store state.SimpleDB) *big.Int {
+	minter := loadMinter(store)
+	if !params.Bool(Enabled) || minter == nil {
+		return nil
+	}
+	provision, _ := new(big.Int).SetString(minter.BlockProvision, 10)
+	return provision
+}
+
+// nextInflation moves inflation by a block at the bonded ratio, rounded to
+// precision and held within its bounds.
+func nextInflation(inflation, ratio *big.Rat) *big.Rat {
+	change := new(big.Rat).Quo(ratio, params.Rat(GoalBonded))
+	change.Sub(big.NewRat(1, 1), change)
+	change.Mul(change, params.Rat(InflationRateChange))
+	change.Quo(change, new(big.Rat).SetInt(params.BigInt(BlocksPerYear)))
+
+	next, _ := new(big.Rat).SetString(new(big.Rat).Add(inflation, change).FloatString(precision))
+	if max := params.Rat(InflationMax); next.Cmp(max) > 0 {
+		next = max
+	}
+	if min := params.Rat(InflationMin); next.Cmp(min) < 0 {
+		next = min
+	}
+	return next
+}
@@ -0,0 +1,54 @@
+package mint
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dora/ultron/modules/params"
+)
+
+func TestNextInflation(t *testing.T) {
+	params.Load(state.NewMemKVStore())
+	start := big.NewRat(1, 10)
+
+	// nothing bonded, up by the rate change over the blocks of a year
+	next := nextInflation(start, new(big.Rat))
+	assert.Equal(t, "0.100000041222729579", next.FloatString(precision))
+
+	// at the goal it stays
+	assert.Equal(t, 0, nextInflation(start, params.Rat(GoalBonded)).Cmp(start))
+
+	// all bonded, down
+	assert.True(t, nextInflation(start, big.NewRat(1, 1)).Cmp(start) < 0)
+
+	// held within its bounds
+	assert.Equal(t, 0, nextInflation(params.Rat(InflationMax), new(big.Rat)).Cmp(params.Rat(InflationMax)))
+	assert.Equal(t, 0, nextInflation(params.Rat(InflationMin), big.NewRat(1, 1)).Cmp(params.Rat(InflationMin)))
+}
+
+func TestBeginBlock(t *testing.T) {
+	store := state.NewMemKVStore()
+	params.Load(store)
+	supply := new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+	assert.Nil(t, BeginBlock(store, new(big.Int), supply))
+	assert.Nil(t, BlockProvision(store))
+
+	store.Set(params.ValueKey(Enabled), []byte("true"))
+	params.Load(store)
+	defer params.Load(state.NewMemKVStore())
+
+	provision := BeginBlock(store, new(big.Int), supply)
+	require.NotNil(t, provision)
+	assert.Equal(t, provision, BlockProvision(store))
+
+	// a year's worth of blocks mints the yearly inflation of the supply
+	minter := loadMinter(store)
+	annual, _ := new(big.Int).SetString(minter.AnnualProvisions, 10)
+	assert.Equal(t, new(big.Int).Quo(annual, params.BigInt(BlocksPerYear)), provision)
+	inflation, _ := new(big.Rat).SetString(minter.Inflation)
+	assert.True(t, inflation.Cmp(params.Rat(InflationMin)) > 0, minter.Inflation)
+}
@@ -0,0 +1,41 @@
+package mint
+
+import (
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	MinterKey = []byte{0x24} // the minter
+)
+
+func init() {
+	substore.Register("mint", MinterKey)
+}
+
+// ParseMinter reads a minter as kept in store, nil if there is none.
+func ParseMinter(b []byte) (*Minter, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	minter := new(Minter)
+	if err := wire.ReadBinaryBytes(b, minter); err != nil {
+		return nil, err
+	}
+	return minter, nil
+}
+
+func loadMinter(store state.SimpleDB) *Minter {
+	minter, err := ParseMinter(store.Get(MinterKey))
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return minter
+}
+
+func saveMinter(store state.SimpleDB, minter Minter) {
+	store.Set(MinterKey, wire.BinaryBytes(minter))
+}
@@ -0,0 +1,32 @@
+// nolint
+package names
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/errors"
+)
+
+var (
+	errBadName          = fmt.Errorf("Name must be lowercase labels ending with %s", Suffix)
+	errNameTaken        = fmt.Errorf("Name is already registered")
+	errNameNotFound     = fmt.Errorf("Name is not registered")
+	errNotOwner         = fmt.Errorf("Sender does not own the name")
+	errMissingSignature = fmt.Errorf("Missing signature")
+)
+
+func ErrBadName() error {
+	return errors.WithCode(errBadName, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNameTaken() error {
+	return errors.WithCode(errNameTaken, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNameNotFound() error {
+	return errors.WithCode(errNameNotFound, errors.CodeTypeBaseUnknownAddress)
+}
+func ErrNotOwner() error {
+	return errors.WithCode(errNotOwner, errors.CodeTypeUnauthorized)
+}
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSignature, errors.CodeTypeUnauthorized)
+}
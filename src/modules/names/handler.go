@@ -0,0 +1,81 @@
+package names
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type NamesTxHandler struct {
+}
+
+// InitState - the registry has no genesis parameters
+func (h *NamesTxHandler) InitState(key, value string, store state.SimpleDB) error {
+	return errors.ErrUnknownKey(key)
+}
+
+// CheckTx checks if the tx is properly structured and allowed
+func (h *NamesTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+
+	sender, err := h.getTxSender(ctx)
+	if err != nil {
+		return res, err
+	}
+	return res, h.apply(store, sender, tx, false)
+}
+
+// DeliverTx executes the tx if valid
+func (h *NamesTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.DeliverResult, err error) {
+	sender, err := h.getTxSender(ctx)
+	if err != nil {
+		return
+	}
+	return res, h.apply(store, sender, tx, true)
+}
+
+// apply checks tx against the registry, saving the changed record if save
+func (h *NamesTxHandler) apply(store state.SimpleDB, sender common.Address, tx sdk.Tx, save bool) error {
+	var record *Record
+	switch _tx := tx.Unwrap().(type) {
+	case TxRegisterName:
+		if loadRecord(store, _tx.Name) != nil {
+			return ErrNameTaken()
+		}
+		record = &Record{Name: _tx.Name, Owner: sender, Target: _tx.Target}
+		if record.Target == (common.Address{}) {
+			record.Target = sender
+		}
+	case TxTransferName:
+		record = loadRecord(store, _tx.Name)
+		if record == nil {
+			return ErrNameNotFound()
+		}
+		if record.Owner != sender {
+			return ErrNotOwner()
+		}
+		record.Owner = _tx.Owner
+		record.Target = _tx.Owner
+	default:
+		return errors.ErrUnknownTxType(tx)
+	}
+
+	if save {
+		saveRecord(store, record)
+	}
+	return nil
+}
+
+// get the sender from the ctx
+func (h *NamesTxHandler) getTxSender(ctx types.Context) (sender common.Address, err error) {
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		return sender, ErrMissingSignature()
+	}
+	return senders[0], nil
+}
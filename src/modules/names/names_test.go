@@ -0,0 +1,17 @@
+package names
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsName(t *testing.T) {
+	for _, name := range []string{"alice.ultron", "pay.alice.ultron", "a-1.ultron"} {
+		assert.True(t, IsName(name), name)
+	}
+	for _, name := range []string{"", ".ultron", "alice", "Alice.ultron", "-a.ultron", "a..ultron",
+		"0x7eff122b94897ea5b0e2a9abf47b86337fafebdc"} {
+		assert.False(t, IsName(name), name)
+	}
+}
@@ -0,0 +1,45 @@
+package names
+
+import (
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/substore"
+)
+
+// NamePrefix is the store prefix of the records: prefix|name
+var NamePrefix = []byte{0x06}
+
+func init() {
+	substore.Register("names", NamePrefix)
+}
+
+// NameKey is the store key of the record of name, for the "/key" query.
+func NameKey(name string) []byte {
+	return append(append([]byte{}, NamePrefix...), name...)
+}
+
+// ParseRecord decodes a record read from the store.
+func ParseRecord(b []byte) (*Record, error) {
+	record := new(Record)
+	if err := wire.ReadBinaryBytes(b, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+func loadRecord(store state.SimpleDB, name string) *Record {
+	b := store.Get(NameKey(name))
+	if b == nil {
+		return nil
+	}
+	record, err := ParseRecord(b)
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return record
+}
+
+func saveRecord(store state.SimpleDB, record *Record) {
+	store.Set(NameKey(record.Name), wire.BinaryBytes(*record))
+}
@@ -0,0 +1,69 @@
+package names
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/dora/ultron/const"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nolint
+const (
+	ByteTxRegisterName = 0x71
+	ByteTxTransferName = 0x72
+	TypeTxRegisterName = constant.ModuleNameNames + "/register"
+	TypeTxTransferName = constant.ModuleNameNames + "/transfer"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxRegisterName{}, TypeTxRegisterName, ByteTxRegisterName)
+	sdk.TxMapper.RegisterImplementation(TxTransferName{}, TypeTxTransferName, ByteTxTransferName)
+}
+
+// Verify interface at compile time
+var _, _ sdk.TxInner = TxRegisterName{}, TxTransferName{}
+
+// TxRegisterName registers a free name for the sender, resolving to target
+// or to the sender if target is empty.
+type TxRegisterName struct {
+	Name   string         `json:"name"`
+	Target common.Address `json:"target"`
+}
+
+func (tx TxRegisterName) ValidateBasic() error {
+	if !IsName(tx.Name) {
+		return ErrBadName()
+	}
+	return nil
+}
+
+func NewTxRegisterName(name string, target common.Address) sdk.Tx {
+	return TxRegisterName{
+		Name:   name,
+		Target: target,
+	}.Wrap()
+}
+
+func (tx TxRegisterName) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxTransferName hands a name of the sender over to a new owner, the name
+// then resolves to the new owner.
+type TxTransferName struct {
+	Name  string         `json:"name"`
+	Owner common.Address `json:"owner"`
+}
+
+func (tx TxTransferName) ValidateBasic() error {
+	if !IsName(tx.Name) {
+		return ErrBadName()
+	}
+	return nil
+}
+
+func NewTxTransferName(name string, owner common.Address) sdk.Tx {
+	return TxTransferName{
+		Name:  name,
+		Owner: owner,
+	}.Wrap()
+}
+
+func (tx TxTransferName) Wrap() sdk.Tx { return sdk.Tx{tx} }
@@ -0,0 +1,36 @@
+// Package names keeps the registry of human readable addresses: a name like
+// "alice.ultron" is owned by the account that registered it and resolves to
+// a target address, which transfers may use instead of the hex address.
+package names
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Suffix ends every name
+const Suffix = ".ultron"
+
+var labelRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,62}[a-z0-9])?$`)
+
+// Record is a registered name
+type Record struct {
+	Name   string         `json:"name"`
+	Owner  common.Address `json:"owner"`  // account allowed to change the record
+	Target common.Address `json:"target"` // address the name resolves to
+}
+
+// IsName tells whether s is a valid name, as opposed to a hex address.
+func IsName(s string) bool {
+	if !strings.HasSuffix(s, Suffix) {
+		return false
+	}
+	for _, label := range strings.Split(strings.TrimSuffix(s, Suffix), ".") {
+		if !labelRegexp.MatchString(label) {
+			return false
+		}
+	}
+	return true
+}
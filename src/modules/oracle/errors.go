@@ -0,0 +1,28 @@
+// nolint
+package oracle
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/errors"
+)
+
+var (
+	errNotFeeder     = fmt.Errorf("Sender is not a whitelisted feeder")
+	errBadDenom      = fmt.Errorf("Denom must be 1 to 32 characters")
+	errBadRate       = fmt.Errorf("Rate must be a positive decimal")
+	errMissingSigner = fmt.Errorf("Missing signature")
+)
+
+func ErrNotFeeder() error {
+	return errors.WithCode(errNotFeeder, errors.CodeTypeUnauthorized)
+}
+func ErrBadDenom() error {
+	return errors.WithCode(errBadDenom, errors.CodeTypeBaseInvalidInput)
+}
+func ErrBadRate() error {
+	return errors.WithCode(errBadRate, errors.CodeTypeBaseInvalidInput)
+}
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSigner, errors.CodeTypeUnauthorized)
+}
@@ -0,0 +1,121 @@
+package oracle
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type OracleTxHandler struct {
+}
+
+// InitState - set genesis parameters for the oracle
+func (h *OracleTxHandler) InitState(key, value string, store state.SimpleDB) error {
+	params := loadParams(store)
+	switch key {
+	case "vote_period":
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || i <= 0 {
+			return fmt.Errorf("input must be a positive integer, Error: %v", err)
+		}
+		params.VotePeriod = i
+	case "feeder":
+		if !common.IsHexAddress(value) {
+			return fmt.Errorf("input must be an address: %s", value)
+		}
+		feeder := common.HexToAddress(value)
+		if !params.isFeeder(feeder) {
+			params.Feeders = append(params.Feeders, feeder)
+		}
+	default:
+		return errors.ErrUnknownKey(key)
+	}
+
+	saveParams(store, params)
+	return nil
+}
+
+// CheckTx checks if the tx is properly structured and sent by a feeder
+func (h *OracleTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+
+	_, err = h.getFeeder(ctx, store)
+	if err != nil {
+		return res, err
+	}
+
+	switch tx.Unwrap().(type) {
+	case TxPriceVote:
+		return res, nil
+	}
+
+	return res, errors.ErrUnknownTxType(tx)
+}
+
+// DeliverTx records the vote for the current window
+func (h *OracleTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.DeliverResult, err error) {
+	feeder, err := h.getFeeder(ctx, store)
+	if err != nil {
+		return
+	}
+
+	switch _tx := tx.Unwrap().(type) {
+	case TxPriceVote:
+		rate, err := parseRate(_tx.Rate)
+		if err != nil {
+			return res, err
+		}
+		saveVote(store, _tx.Denom, feeder, rate)
+		return res, nil
+	}
+
+	return res, errors.ErrUnknownTxType(tx)
+}
+
+// get the sender from the ctx and ensure it is a whitelisted feeder
+func (h *OracleTxHandler) getFeeder(ctx types.Context, store state.SimpleDB) (feeder common.Address, err error) {
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		return feeder, ErrMissingSignature()
+	}
+	if !loadParams(store).isFeeder(senders[0]) {
+		return feeder, ErrNotFeeder()
+	}
+	return senders[0], nil
+}
+
+// EndBlock closes the voting window every VotePeriod blocks: the median of
+// the votes of each denom becomes its rate, in store and in rates, and the
+// votes are cleared.
+func EndBlock(store state.SimpleDB, height int64, rates *Rates) {
+	params := loadParams(store)
+	if height%params.VotePeriod != 0 {
+		return
+	}
+
+	votes := popVotes(store)
+	denoms := make([]string, 0, len(votes))
+	for denom := range votes {
+		denoms = append(denoms, denom)
+	}
+	sort.Strings(denoms)
+
+	for _, denom := range denoms {
+		rate := Rate{
+			Denom:  denom,
+			Rate:   median(votes[denom]).String(),
+			Height: height,
+		}
+		saveRate(store, rate)
+		rates.set(rate)
+	}
+}
@@ -0,0 +1,60 @@
+package oracle
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+func bigs(vals ...int64) []*big.Int {
+	res := make([]*big.Int, len(vals))
+	for i, v := range vals {
+		res[i] = big.NewInt(v)
+	}
+	return res
+}
+
+func TestMedian(t *testing.T) {
+	assert.Equal(t, int64(5), median(bigs(9, 5, 1)).Int64())
+	assert.Equal(t, int64(4), median(bigs(8, 1, 3, 5)).Int64())
+	assert.Equal(t, int64(7), median(bigs(7)).Int64())
+}
+
+func TestParseRate(t *testing.T) {
+	rate, err := parseRate("1.5")
+	assert.Nil(t, err)
+	assert.Equal(t, "1500000000000000000", rate.String())
+
+	for _, bad := range []string{"", "abc", "0", "-1", "0.0000000000000000001"} {
+		_, err := parseRate(bad)
+		assert.NotNil(t, err, bad)
+	}
+}
+
+func TestRatePrecompileUpgrade(t *testing.T) {
+	store := state.NewMemKVStore()
+	rates := NewRates()
+	defer Use(rates)()
+	rates.set(Rate{Denom: "usd", Rate: "1500", Height: 7})
+	c := &ratePrecompile{}
+	in := []byte("usd")
+
+	// before the upgrade it runs as an account without code
+	rates.BeginBlock(store)
+	assert.Equal(t, uint64(0), c.RequiredGas(in))
+	out, err := c.Run(in)
+	assert.Nil(t, err)
+	assert.Nil(t, out)
+
+	store.Set(upgrade.DoneKey(PrecompileUpgrade), []byte{1})
+	rates.BeginBlock(store)
+	assert.Equal(t, uint64(rateGas), c.RequiredGas(in))
+	out, err = c.Run(in)
+	assert.Nil(t, err)
+	assert.Equal(t, append(math.PaddedBigBytes(big.NewInt(1500), 32), math.PaddedBigBytes(big.NewInt(7), 32)...), out)
+}
@@ -0,0 +1,61 @@
+package oracle
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// OracleAddress is the precompile contracts call for rates. The input is the
+// denom left aligned in a bytes32, the output the abi encoded
+// (uint256 rate, uint256 height), both zero for unknown denoms:
+//
+//	(bool ok, bytes memory out) = ORACLE.staticcall(abi.encodePacked(bytes32("usd")));
+//	(uint256 rate, uint256 height) = abi.decode(out, (uint256, uint256));
+var OracleAddress = common.HexToAddress("0x0000000000000000000000000000000000000100")
+
+const rateGas = 3000
+
+// PrecompileUpgrade is the upgrade from which on the precompile at
+// OracleAddress answers. It changes the results of the calls to it, so the
+// chain switches at the height it is scheduled at.
+const PrecompileUpgrade = "oracle-precompile"
+
+func init() {
+	// nothing to migrate, the rates are kept from the oracle txs on
+	upgrade.RegisterHandler(PrecompileUpgrade, func(state.SimpleDB) error { return nil })
+
+	vm.PrecompiledContractsHomestead[OracleAddress] = &ratePrecompile{}
+	vm.PrecompiledContractsByzantium[OracleAddress] = &ratePrecompile{}
+}
+
+// ratePrecompile costs nothing and returns nothing before PrecompileUpgrade,
+// as the account without code at its address did.
+type ratePrecompile struct{}
+
+func (c *ratePrecompile) RequiredGas(input []byte) uint64 {
+	if !activeRates().Enabled() {
+		return 0
+	}
+	return rateGas
+}
+
+func (c *ratePrecompile) Run(input []byte) ([]byte, error) {
+	if !activeRates().Enabled() {
+		return nil, nil
+	}
+	denom := bytes.TrimRight(common.RightPadBytes(input, 32)[:32], "\x00")
+
+	out := make([]byte, 64)
+	if rate, ok := GetRate(string(denom)); ok {
+		copy(out[:32], math.PaddedBigBytes(rate.Value(), 32))
+		copy(out[32:], math.PaddedBigBytes(big.NewInt(rate.Height), 32))
+	}
+	return out, nil
+}
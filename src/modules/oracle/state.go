@@ -0,0 +1,196 @@
+package oracle
+
+import (
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/modules/upgrade"
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	// Keys for store prefixes
+	ParamKey   = []byte{0x02} // key for the oracle parameters
+	VotePrefix = []byte{0x03} // votes of the window: prefix|denom|0x00|feeder
+	RatePrefix = []byte{0x04} // rates: prefix|denom
+)
+
+func init() {
+	substore.Register("oracle", ParamKey, VotePrefix, RatePrefix)
+}
+
+// load/save the oracle params
+func loadParams(store state.SimpleDB) (params Params) {
+	b := store.Get(ParamKey)
+	if b == nil {
+		return defaultParams()
+	}
+
+	err := wire.ReadBinaryBytes(b, &params)
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+
+	return
+}
+func saveParams(store state.SimpleDB, params Params) {
+	b := wire.BinaryBytes(params)
+	store.Set(ParamKey, b)
+}
+
+func voteKey(denom string, feeder common.Address) []byte {
+	key := append(append([]byte{}, VotePrefix...), denom...)
+	key = append(key, 0x00)
+	return append(key, feeder.Bytes()...)
+}
+
+func saveVote(store state.SimpleDB, denom string, feeder common.Address, rate *big.Int) {
+	store.Set(voteKey(denom, feeder), rate.Bytes())
+}
+
+// popVotes removes the votes of the window and returns them by denom
+func popVotes(store state.SimpleDB) map[string][]*big.Int {
+	votes := make(map[string][]*big.Int)
+	window := substore.New(store, VotePrefix)
+	for _, m := range window.List(nil, nil, 0) {
+		window.Remove(m.Key)
+		denom := m.Key[:len(m.Key)-common.AddressLength-1]
+		votes[string(denom)] = append(votes[string(denom)], new(big.Int).SetBytes(m.Value))
+	}
+	return votes
+}
+
+func saveRate(store state.SimpleDB, rate Rate) {
+	key := append(append([]byte{}, RatePrefix...), rate.Denom...)
+	store.Set(key, wire.BinaryBytes(rate))
+}
+
+// median of the votes, the mean of the middle two for an even count
+func median(votes []*big.Int) *big.Int {
+	sort.Sort(byRate(votes))
+	mid := len(votes) / 2
+	if len(votes)%2 == 1 {
+		return new(big.Int).Set(votes[mid])
+	}
+	sum := new(big.Int).Add(votes[mid-1], votes[mid])
+	return sum.Rsh(sum, 1)
+}
+
+type byRate []*big.Int
+
+func (a byRate) Len() int           { return len(a) }
+func (a byRate) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
+func (a byRate) Less(i, j int) bool { return a[i].Cmp(a[j]) < 0 }
+
+//---------------------------------------------------------------------
+
+// LoadRate returns the rate of denom kept in store, false if no window
+// decided it yet.
+func LoadRate(store state.SimpleDB, denom string) (Rate, bool) {
+	var rate Rate
+	b := store.Get(append(append([]byte{}, RatePrefix...), denom...))
+	if b == nil {
+		return rate, false
+	}
+	if err := wire.ReadBinaryBytes(b, &rate); err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return rate, true
+}
+
+// Rates are the rates of the last closed windows of a node, kept in memory
+// for the precompile. Every node has its own, see Use.
+type Rates struct {
+	mtx     sync.RWMutex
+	rates   map[string]Rate
+	enabled bool // PrecompileUpgrade is done
+}
+
+// NewRates returns empty rates, see Load.
+func NewRates() *Rates {
+	return &Rates{rates: make(map[string]Rate)}
+}
+
+// Load reads the rates kept in store, it must be called when the app starts
+// and after the store is rewritten.
+func (r *Rates) Load(store state.SimpleDB) {
+	loaded := make(map[string]Rate)
+	for _, m := range substore.New(store, RatePrefix).List(nil, nil, 0) {
+		var rate Rate
+		if err := wire.ReadBinaryBytes(m.Value, &rate); err != nil {
+			panic(err)
+		}
+		loaded[rate.Denom] = rate
+	}
+
+	r.mtx.Lock()
+	r.rates = loaded
+	r.enabled = upgrade.Done(store, PrecompileUpgrade)
+	r.mtx.Unlock()
+}
+
+// BeginBlock turns the precompile on for the txs of the block beginning once
+// PrecompileUpgrade is done.
+func (r *Rates) BeginBlock(store state.SimpleDB) {
+	enabled := upgrade.Done(store, PrecompileUpgrade)
+	r.mtx.Lock()
+	r.enabled = enabled
+	r.mtx.Unlock()
+}
+
+// Enabled tells if the precompile answers, from PrecompileUpgrade on.
+func (r *Rates) Enabled() bool {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.enabled
+}
+
+// Get returns the rate of denom, false if no window decided it yet.
+func (r *Rates) Get(denom string) (Rate, bool) {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	rate, ok := r.rates[denom]
+	return rate, ok
+}
+
+func (r *Rates) set(rate Rate) {
+	r.mtx.Lock()
+	r.rates[rate.Denom] = rate
+	r.mtx.Unlock()
+}
+
+var (
+	useMtx sync.Mutex // held from Use to the release
+
+	activeMtx sync.RWMutex
+	active    = NewRates() // read by the precompile
+)
+
+// Use points the precompile at rates until the returned release func is
+// called. Nodes sharing a process hold it around the blocks they run so
+// each one reads its own rates, see stake.UseDatabase. Calls outside of a
+// block, such as eth_call, read the rates of the node that ran the last one.
+func Use(rates *Rates) (release func()) {
+	useMtx.Lock()
+	activeMtx.Lock()
+	active = rates
+	activeMtx.Unlock()
+	return useMtx.Unlock
+}
+
+// GetRate returns the rate of denom of the rates in use, see Rates.Get.
+func GetRate(denom string) (Rate, bool) {
+	return activeRates().Get(denom)
+}
+
+func activeRates() *Rates {
+	activeMtx.RLock()
+	defer activeMtx.RUnlock()
+	return active
+}
@@ -0,0 +1,62 @@
+package oracle
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/dora/ultron/const"
+)
+
+// nolint
+const (
+	ByteTxPriceVote = 0x70
+	TypeTxPriceVote = constant.ModuleNameOracle + "/priceVote"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxPriceVote{}, TypeTxPriceVote, ByteTxPriceVote)
+}
+
+// Verify interface at compile time
+var _ sdk.TxInner = TxPriceVote{}
+
+// TxPriceVote is the vote of a feeder for the rate of denom in the current
+// voting window, a later vote of the same feeder replaces it.
+type TxPriceVote struct {
+	Denom string `json:"denom"`
+	Rate  string `json:"rate"` // decimal, e.g. "1.0042"
+}
+
+func (tx TxPriceVote) ValidateBasic() error {
+	if len(tx.Denom) == 0 || len(tx.Denom) > 32 {
+		return ErrBadDenom()
+	}
+	if _, err := parseRate(tx.Rate); err != nil {
+		return err
+	}
+	return nil
+}
+
+func NewTxPriceVote(denom, rate string) sdk.Tx {
+	return TxPriceVote{
+		Denom: denom,
+		Rate:  rate,
+	}.Wrap()
+}
+
+func (tx TxPriceVote) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// parseRate turns a decimal into the integer kept on chain, digits beyond
+// RateDecimals are dropped.
+func parseRate(s string) (*big.Int, error) {
+	r, ok := new(big.Rat).SetString(s)
+	if !ok || r.Sign() <= 0 {
+		return nil, ErrBadRate()
+	}
+	r.Mul(r, new(big.Rat).SetInt(rateUnit))
+	rate := new(big.Int).Quo(r.Num(), r.Denom())
+	if rate.Sign() <= 0 {
+		return nil, ErrBadRate()
+	}
+	return rate, nil
+}
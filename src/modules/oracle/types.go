@@ -0,0 +1,51 @@
+// Package oracle keeps on-chain exchange rates. Whitelisted feeders vote the
+// rate of each denom during a voting window of VotePeriod blocks; at the end
+// of the window the median of the votes becomes the rate, which contracts
+// read through the precompile at OracleAddress.
+package oracle
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// RateDecimals is the number of decimals of the rates kept on chain, rates
+// are integers scaled by 10^RateDecimals.
+const RateDecimals = 18
+
+var rateUnit = new(big.Int).Exp(big.NewInt(10), big.NewInt(RateDecimals), nil)
+
+// Params defines the settings of the oracle
+type Params struct {
+	VotePeriod int64            `json:"vote_period"` // blocks of a voting window
+	Feeders    []common.Address `json:"feeders"`     // accounts allowed to vote
+}
+
+func defaultParams() Params {
+	return Params{
+		VotePeriod: 10,
+	}
+}
+
+func (p Params) isFeeder(addr common.Address) bool {
+	for _, feeder := range p.Feeders {
+		if feeder == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Rate is the exchange rate of a denom decided by the last voting window
+type Rate struct {
+	Denom  string `json:"denom"`
+	Rate   string `json:"rate"`   // integer scaled by 10^RateDecimals
+	Height int64  `json:"height"` // block the window closed at
+}
+
+// Value returns the scaled rate.
+func (r Rate) Value() *big.Int {
+	value, _ := new(big.Int).SetString(r.Rate, 10)
+	return value
+}
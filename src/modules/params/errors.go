@@ -0,0 +1,36 @@
+// nolint
+package params
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/errors"
+)
+
+var (
+	errNotAuthority  = fmt.Errorf("Sender is not the params authority")
+	errNoChanges     = fmt.Errorf("No params to change")
+	errMissingSigner = fmt.Errorf("Missing signature")
+)
+
+func ErrUnknownParam(key string) error {
+	return errors.WithCode(fmt.Errorf("Unknown param %s", key), errors.CodeTypeBaseInvalidInput)
+}
+func ErrLocal(key string) error {
+	return errors.WithCode(fmt.Errorf("Param %s is a node setting, it can't change on chain", key), errors.CodeTypeBaseInvalidInput)
+}
+func ErrNotLocal(key string) error {
+	return errors.WithCode(fmt.Errorf("Param %s can only change on chain", key), errors.CodeTypeBaseInvalidInput)
+}
+func ErrBadValue(key, reason string) error {
+	return errors.WithCode(fmt.Errorf("Invalid value of %s: %s", key, reason), errors.CodeTypeBaseInvalidInput)
+}
+func ErrNotAuthority() error {
+	return errors.WithCode(errNotAuthority, errors.CodeTypeUnauthorized)
+}
+func ErrNoChanges() error {
+	return errors.WithCode(errNoChanges, errors.CodeTypeBaseInvalidInput)
+}
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSigner, errors.CodeTypeUnauthorized)
+}
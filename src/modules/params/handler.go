@@ -0,0 +1,92 @@
+package params
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type ParamsTxHandler struct {
+}
+
+// InitState - set the params authority or the genesis value of a param
+func (h *ParamsTxHandler) InitState(key, value string, store state.SimpleDB) error {
+	if key == "authority" {
+		if !common.IsHexAddress(value) {
+			return fmt.Errorf("input must be an address: %s", value)
+		}
+		saveAuthority(store, common.HexToAddress(value))
+		return nil
+	}
+
+	if _, err := setValue(store, key, value); err != nil {
+		return err
+	}
+	set(key, value)
+	return nil
+}
+
+// CheckTx checks if the tx is properly structured and sent by the authority
+func (h *ParamsTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+
+	err = h.checkAuthority(ctx, store)
+	if err != nil {
+		return res, err
+	}
+
+	switch tx.Unwrap().(type) {
+	case TxChangeParams:
+		return res, nil
+	}
+	return res, errors.ErrUnknownTxType(tx)
+}
+
+// DeliverTx changes the params in store and posts a Change for each of
+// them. The cache modules read isn't touched mid-block: the new values take
+// effect once the block is committed and the app loads them, see Load.
+func (h *ParamsTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.DeliverResult, err error) {
+	err = h.checkAuthority(ctx, store)
+	if err != nil {
+		return
+	}
+
+	switch _tx := tx.Unwrap().(type) {
+	case TxChangeParams:
+		// validate them all before any is written
+		if err := _tx.ValidateBasic(); err != nil {
+			return res, err
+		}
+		for _, c := range _tx.Changes {
+			old, err := setValue(store, c.Key, c.Value)
+			if err != nil {
+				return res, err
+			}
+			if ctx.Ethereum() != nil {
+				ctx.Ethereum().EventMux().Post(Change{Key: c.Key, Old: old, New: c.Value, Height: ctx.BlockHeight()}) // nolint: errcheck
+			}
+		}
+		return res, nil
+	}
+	return res, errors.ErrUnknownTxType(tx)
+}
+
+// ensure the single signer of the tx is the params authority
+func (h *ParamsTxHandler) checkAuthority(ctx types.Context, store state.SimpleDB) error {
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		return ErrMissingSignature()
+	}
+	authority, ok := Authority(store)
+	if !ok || senders[0] != authority {
+		return ErrNotAuthority()
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package params
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dora/ultron/types"
+)
+
+func TestValidate(t *testing.T) {
+	p, ok := Lookup(InflationRate)
+	require.True(t, ok)
+	assert.Nil(t, p.Validate("7"))
+	assert.NotNil(t, p.Validate("7.5"))
+	assert.NotNil(t, p.Validate("-1"))
+	assert.NotNil(t, p.Validate("101"))
+
+	p, _ = Lookup(MaxValidatorShare)
+	assert.Nil(t, p.Validate("0.25"))
+	assert.NotNil(t, p.Validate("1.5"))
+	assert.NotNil(t, p.Validate("a quarter"))
+}
+
+func TestSetValue(t *testing.T) {
+	store := state.NewMemKVStore()
+
+	old, err := setValue(store, InflationRate, "7")
+	require.Nil(t, err)
+	assert.Equal(t, "5", old)
+	old, err = setValue(store, InflationRate, "8")
+	require.Nil(t, err)
+	assert.Equal(t, "7", old)
+
+	_, err = setValue(store, StoreCacheSize, "100")
+	assert.NotNil(t, err)
+	_, err = setValue(store, "no.such_param", "1")
+	assert.NotNil(t, err)
+
+	Load(store)
+	assert.Equal(t, int64(8), Int64(InflationRate))
+	assert.Equal(t, "0.1", Get(MaxValidatorShare))
+
+	require.Nil(t, SetLocal(StoreCacheSize, "100"))
+	Load(store)
+	assert.Equal(t, int64(100), Int64(StoreCacheSize))
+}
+
+func TestDeliverTxDefersCache(t *testing.T) {
+	store := state.NewMemKVStore()
+	authority := common.HexToAddress("0x01")
+	saveAuthority(store, authority)
+	Load(store)
+	defer Load(state.NewMemKVStore())
+
+	ctx := types.NewContext("test", 1, nil)
+	ctx.WithSigners(authority)
+	h := &ParamsTxHandler{}
+	_, err := h.DeliverTx(ctx, store, NewTxChangeParams(ParamChange{Key: InflationRate, Value: "9"}))
+	require.Nil(t, err)
+
+	// the block still runs with the value it started with
+	assert.Equal(t, int64(5), Int64(InflationRate))
+	Load(store)
+	assert.Equal(t, int64(9), Int64(InflationRate))
+}
+
+func TestBigInt(t *testing.T) {
+	store := state.NewMemKVStore()
+	defer Load(state.NewMemKVStore())
+
+	// 100 ether, above the int64 range
+	_, err := setValue(store, MinAccountBalance, "100000000000000000000")
+	require.Nil(t, err)
+	Load(store)
+	want, _ := new(big.Int).SetString("100000000000000000000", 10)
+	assert.Equal(t, want, BigInt(MinAccountBalance))
+
+	p, _ := Lookup(MinAccountBalance)
+	assert.NotNil(t, p.Validate("1e20"))
+	assert.NotNil(t, p.Validate("-100000000000000000000"))
+}
@@ -0,0 +1,130 @@
+package params
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/dora/ultron/const"
+)
+
+// Keys of the parameters of the builtin modules
+const (
+	MinGasPrice       = "txpool.min_gas_price"
+	MinAccountBalance = "account.min_balance"
+	StoreCacheSize    = "store.cache_size"
+	InflationRate     = "distribution.inflation_rate"
+	MaxValidatorShare = "distribution.max_validator_share"
+	DowntimeWindow    = "stake.downtime_window"
+	MaxMissedBlocks   = "stake.max_missed_blocks"
+	JailBlocks        = "stake.jail_blocks"
+)
+
+var (
+	registryMtx sync.RWMutex
+	registry    = make(map[string]Param)
+)
+
+func init() {
+	Register(Param{
+		Key:     MinGasPrice,
+		Type:    TypeInteger,
+		Default: strconv.FormatInt(constant.BaseGasPrice, 10),
+		Min:     "0",
+		Doc:     "gas price in wei txs pay at least, the part of the fee above it goes to the block proposer",
+	})
+	Register(Param{
+		Key:     MinAccountBalance,
+		Type:    TypeInteger,
+		Default: "0",
+		Min:     "0",
+		Doc:     "wei a transfer must bring to an account not in the state yet, 0 to accept any",
+	})
+	Register(Param{
+		Key:     StoreCacheSize,
+		Type:    TypeInteger,
+		Default: "10000",
+		Min:     "0",
+		Doc:     "nodes cached by the app store",
+		Local:   true,
+	})
+	Register(Param{
+		Key:     InflationRate,
+		Type:    TypeInteger,
+		Default: "5",
+		Min:     "0",
+		Max:     "100",
+		Doc:     "yearly inflation in percent paid out as block awards",
+	})
+	Register(Param{
+		Key:     MaxValidatorShare,
+		Type:    TypeDecimal,
+		Default: "0.1",
+		Min:     "0",
+		Max:     "1",
+		Doc:     "largest share of the block award a validator gets in the first round",
+	})
+	Register(Param{
+		Key:     DowntimeWindow,
+		Type:    TypeInteger,
+		Default: "1080",
+		Min:     "1",
+		Doc:     "blocks missed blocks are counted over",
+	})
+	Register(Param{
+		Key:     MaxMissedBlocks,
+		Type:    TypeInteger,
+		Default: "540",
+		Min:     "0",
+		Doc:     "blocks a validator may miss in a window before it is jailed",
+	})
+	Register(Param{
+		Key:     JailBlocks,
+		Type:    TypeInteger,
+		Default: "360",
+		Min:     "0",
+		Doc:     "blocks a jailed validator waits before it can unjail",
+	})
+}
+
+// Register adds a parameter, modules call it from init. It panics if the
+// key is taken or the default doesn't validate.
+func Register(p Param) {
+	if err := p.Validate(p.Default); err != nil {
+		panic(fmt.Sprintf("param %s: %v", p.Key, err))
+	}
+
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+	if _, ok := registry[p.Key]; ok {
+		panic(fmt.Sprintf("param %s already registered", p.Key))
+	}
+	registry[p.Key] = p
+}
+
+// Lookup returns the parameter registered as key.
+func Lookup(key string) (Param, bool) {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	p, ok := registry[key]
+	return p, ok
+}
+
+// All returns the registered parameters sorted by key.
+func All() []Param {
+	registryMtx.RLock()
+	defer registryMtx.RUnlock()
+	all := make([]Param, 0, len(registry))
+	for _, p := range registry {
+		all = append(all, p)
+	}
+	sort.Sort(byKey(all))
+	return all
+}
+
+type byKey []Param
+
+func (ps byKey) Len() int           { return len(ps) }
+func (ps byKey) Swap(i, j int)      { ps[i], ps[j] = ps[j], ps[i] }
+func (ps byKey) Less(i, j int) bool { return ps[i].Key < ps[j].Key }
@@ -0,0 +1,170 @@
+package params
+
+import (
+	"math/big"
+	"strconv"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	// Keys for store prefixes
+	ValuePrefix  = []byte{0x1b} // values set on chain, by param key
+	AuthorityKey = []byte{0x1c} // account allowed to change the params
+)
+
+func init() {
+	substore.Register("params", ValuePrefix, AuthorityKey)
+}
+
+var (
+	valuesMtx sync.RWMutex
+	values    = make(map[string]string) // set on chain or locally
+)
+
+// ValueKey is the store key of the value of the parameter key.
+func ValueKey(key string) []byte {
+	return append(ValuePrefix, []byte(key)...)
+}
+
+// Load caches the values kept in store, it must be called when the app
+// starts and after the store is rewritten. Local values are kept.
+func Load(store state.SimpleDB) {
+	loaded := make(map[string]string)
+	for _, p := range All() {
+		if p.Local {
+			if value, ok := get(p.Key); ok {
+				loaded[p.Key] = value
+			}
+			continue
+		}
+		if b := store.Get(ValueKey(p.Key)); b != nil {
+			loaded[p.Key] = string(b)
+		}
+	}
+
+	valuesMtx.Lock()
+	values = loaded
+	valuesMtx.Unlock()
+}
+
+// SetLocal sets the local parameter key of this node.
+func SetLocal(key, value string) error {
+	p, ok := Lookup(key)
+	if !ok {
+		return ErrUnknownParam(key)
+	}
+	if !p.Local {
+		return ErrNotLocal(key)
+	}
+	if err := p.Validate(value); err != nil {
+		return err
+	}
+	set(key, value)
+	return nil
+}
+
+// Get returns the value of the parameter key, its default if it was never
+// set.
+func Get(key string) string {
+	if value, ok := get(key); ok {
+		return value
+	}
+	p, ok := Lookup(key)
+	if !ok {
+		panic("unknown param " + key)
+	}
+	return p.Default
+}
+
+// Int64 returns the value of the integer parameter key, which must fit in
+// an int64: block counts and the like. Amounts of wei are read with BigInt.
+func Int64(key string) int64 {
+	i, _ := strconv.ParseInt(Get(key), 10, 64)
+	return i
+}
+
+// BigInt returns the value of the integer parameter key, of any size.
+func BigInt(key string) *big.Int {
+	i, _ := new(big.Int).SetString(Get(key), 10)
+	return i
+}
+
+// Rat returns the value of the decimal parameter key.
+func Rat(key string) *big.Rat {
+	r, _ := new(big.Rat).SetString(Get(key))
+	return r
+}
+
+// Float64 returns the value of the decimal parameter key as a float64.
+// Consensus code builds its big.Floats from it with big.NewFloat, so they
+// keep the 53-bit precision of the constants the params replaced.
+func Float64(key string) float64 {
+	f, _ := strconv.ParseFloat(Get(key), 64)
+	return f
+}
+
+// Bool returns the value of the boolean parameter key.
+func Bool(key string) bool {
+	b, _ := strconv.ParseBool(Get(key))
+	return b
+}
+
+// Address returns the value of the address parameter key.
+func Address(key string) common.Address {
+	return common.HexToAddress(Get(key))
+}
+
+func get(key string) (string, bool) {
+	valuesMtx.RLock()
+	defer valuesMtx.RUnlock()
+	value, ok := values[key]
+	return value, ok
+}
+
+func set(key, value string) {
+	valuesMtx.Lock()
+	values[key] = value
+	valuesMtx.Unlock()
+}
+
+// setValue validates value and keeps it in store as the value of key,
+// returning the value it replaces.
+func setValue(store state.SimpleDB, key, value string) (old string, err error) {
+	p, ok := Lookup(key)
+	if !ok {
+		return "", ErrUnknownParam(key)
+	}
+	if p.Local {
+		return "", ErrLocal(key)
+	}
+	if err := p.Validate(value); err != nil {
+		return "", err
+	}
+
+	old = p.Default
+	if b := store.Get(ValueKey(key)); b != nil {
+		old = string(b)
+	}
+	store.Set(ValueKey(key), []byte(value))
+	return old, nil
+}
+
+// Authority returns the account allowed to change the params, false if
+// there is none. Other governance txs, such as upgrades, are sent by it too.
+func Authority(store state.SimpleDB) (common.Address, bool) {
+	b := store.Get(AuthorityKey)
+	if b == nil {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(b), true
+}
+
+func saveAuthority(store state.SimpleDB, authority common.Address) {
+	store.Set(AuthorityKey, authority.Bytes())
+}
@@ -0,0 +1,58 @@
+package params
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/dora/ultron/const"
+)
+
+// nolint
+const (
+	ByteTxChangeParams = 0x7f
+	TypeTxChangeParams = constant.ModuleNameParams + "/change"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxChangeParams{}, TypeTxChangeParams, ByteTxChangeParams)
+}
+
+// Verify interface at compile time
+var _ sdk.TxInner = TxChangeParams{}
+
+// ParamChange sets the parameter Key to Value.
+type ParamChange struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// TxChangeParams changes parameters at once, it can only be sent by the
+// params authority. Either all changes apply or none.
+type TxChangeParams struct {
+	Changes []ParamChange `json:"changes"`
+}
+
+func (tx TxChangeParams) ValidateBasic() error {
+	if len(tx.Changes) == 0 {
+		return ErrNoChanges()
+	}
+	for _, c := range tx.Changes {
+		p, ok := Lookup(c.Key)
+		if !ok {
+			return ErrUnknownParam(c.Key)
+		}
+		if p.Local {
+			return ErrLocal(c.Key)
+		}
+		if err := p.Validate(c.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func NewTxChangeParams(changes ...ParamChange) sdk.Tx {
+	return TxChangeParams{
+		Changes: changes,
+	}.Wrap()
+}
+
+func (tx TxChangeParams) Wrap() sdk.Tx { return sdk.Tx{tx} }
@@ -0,0 +1,124 @@
+// Package params keeps the tunable parameters of the chain in one place.
+// Each parameter is registered with its type, default and bounds, which make
+// up a JSON schema its values are validated against. Values changed through
+// genesis or by the params authority are kept in the store and cached, so
+// the txpool, staking and distribution read them without a store at hand.
+package params
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Type is the JSON schema type of the values of a parameter.
+type Type string
+
+// nolint
+const (
+	TypeInteger Type = "integer" // of any size, wei amounts included
+	TypeDecimal Type = "decimal" // exact decimal, e.g. "0.1"
+	TypeBool    Type = "boolean"
+	TypeAddress Type = "address"
+)
+
+// Param describes a parameter. Min and Max bound integer and decimal
+// values when set.
+type Param struct {
+	Key     string `json:"key"`
+	Type    Type   `json:"type"`
+	Default string `json:"default"`
+	Min     string `json:"min,omitempty"`
+	Max     string `json:"max,omitempty"`
+	Doc     string `json:"doc"`
+
+	// Local parameters are node settings needed before the chain state is
+	// opened. They can't change on chain, config.toml sets them under
+	// [params].
+	Local bool `json:"local,omitempty"`
+}
+
+// Schema returns the JSON schema of the values of p.
+func (p Param) Schema() map[string]interface{} {
+	schema := map[string]interface{}{
+		"title":       p.Key,
+		"description": p.Doc,
+		"default":     p.Default,
+	}
+	switch p.Type {
+	case TypeInteger:
+		schema["type"] = "string"
+		schema["pattern"] = `^-?[0-9]+$`
+	case TypeDecimal:
+		schema["type"] = "string"
+		schema["pattern"] = `^-?[0-9]+(\.[0-9]+)?$`
+	case TypeBool:
+		schema["type"] = "string"
+		schema["enum"] = []string{"true", "false"}
+	case TypeAddress:
+		schema["type"] = "string"
+		schema["pattern"] = `^0x[0-9a-fA-F]{40}$`
+	}
+	if p.Min != "" {
+		schema["minimum"] = p.Min
+	}
+	if p.Max != "" {
+		schema["maximum"] = p.Max
+	}
+	return schema
+}
+
+// Validate checks value against the schema of p.
+func (p Param) Validate(value string) error {
+	switch p.Type {
+	case TypeInteger, TypeDecimal:
+		r, ok := new(big.Rat).SetString(value)
+		if !ok || (p.Type == TypeInteger && !isInteger(value)) {
+			return ErrBadValue(p.Key, fmt.Sprintf("%q is not %s %s", value, article(p.Type), p.Type))
+		}
+		if p.Min != "" {
+			min, _ := new(big.Rat).SetString(p.Min)
+			if r.Cmp(min) < 0 {
+				return ErrBadValue(p.Key, fmt.Sprintf("%s is below the minimum %s", value, p.Min))
+			}
+		}
+		if p.Max != "" {
+			max, _ := new(big.Rat).SetString(p.Max)
+			if r.Cmp(max) > 0 {
+				return ErrBadValue(p.Key, fmt.Sprintf("%s is above the maximum %s", value, p.Max))
+			}
+		}
+	case TypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return ErrBadValue(p.Key, fmt.Sprintf("%q is not a boolean", value))
+		}
+	case TypeAddress:
+		if !common.IsHexAddress(value) {
+			return ErrBadValue(p.Key, fmt.Sprintf("%q is not an address", value))
+		}
+	}
+	return nil
+}
+
+func isInteger(value string) bool {
+	_, ok := new(big.Int).SetString(value, 10)
+	return ok
+}
+
+func article(t Type) string {
+	if t == TypeInteger {
+		return "an"
+	}
+	return "a"
+}
+
+// Change is posted on the event mux of the node when a parameter changes
+// on chain.
+type Change struct {
+	Key    string `json:"key"`
+	Old    string `json:"old"`
+	New    string `json:"new"`
+	Height int64  `json:"height"`
+}
@@ -0,0 +1,48 @@
+// nolint
+package recovery
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/errors"
+)
+
+var (
+	errBadThreshold     = fmt.Errorf("Threshold must be between 1 and the number of guardians")
+	errBadDelay         = fmt.Errorf("Delay must be >= 0")
+	errNoGuardians      = fmt.Errorf("Account has no guardians")
+	errNotGuardian      = fmt.Errorf("Sender is not a guardian of the account")
+	errNoRecovery       = fmt.Errorf("No recovery pending for the account")
+	errKeyMismatch      = fmt.Errorf("Recovery pending for another key")
+	errNotReady         = fmt.Errorf("Recovery lacks approvals or is still delayed")
+	errKeyFrozen        = fmt.Errorf("Key was rotated by a recovery")
+	errMissingSignature = fmt.Errorf("Missing signature")
+)
+
+func ErrBadThreshold() error {
+	return errors.WithCode(errBadThreshold, errors.CodeTypeBaseInvalidInput)
+}
+func ErrBadDelay() error {
+	return errors.WithCode(errBadDelay, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNoGuardians() error {
+	return errors.WithCode(errNoGuardians, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNotGuardian() error {
+	return errors.WithCode(errNotGuardian, errors.CodeTypeUnauthorized)
+}
+func ErrNoRecovery() error {
+	return errors.WithCode(errNoRecovery, errors.CodeTypeBaseInvalidInput)
+}
+func ErrKeyMismatch() error {
+	return errors.WithCode(errKeyMismatch, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNotReady() error {
+	return errors.WithCode(errNotReady, errors.CodeTypeBaseInvalidInput)
+}
+func ErrKeyFrozen() error {
+	return errors.WithCode(errKeyFrozen, errors.CodeTypeUnauthorized)
+}
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSignature, errors.CodeTypeUnauthorized)
+}
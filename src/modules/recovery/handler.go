@@ -0,0 +1,122 @@
+package recovery
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/commons"
+	"github.com/dora/ultron/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type RecoveryTxHandler struct {
+}
+
+// InitState - recovery has no genesis parameters
+func (h *RecoveryTxHandler) InitState(key, value string, store state.SimpleDB) error {
+	return errors.ErrUnknownKey(key)
+}
+
+// CheckTx checks if the tx is properly structured and allowed
+func (h *RecoveryTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+
+	sender, err := h.getTxSender(ctx)
+	if err != nil {
+		return res, err
+	}
+	return res, h.apply(ctx, store, sender, tx, false)
+}
+
+// DeliverTx executes the tx if valid
+func (h *RecoveryTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.DeliverResult, err error) {
+	sender, err := h.getTxSender(ctx)
+	if err != nil {
+		return
+	}
+	return res, h.apply(ctx, store, sender, tx, true)
+}
+
+// apply checks tx against store, executing it if deliver
+func (h *RecoveryTxHandler) apply(ctx types.Context, store state.SimpleDB, sender common.Address, tx sdk.Tx, deliver bool) error {
+	switch _tx := tx.Unwrap().(type) {
+	case TxSetGuardians:
+		if deliver {
+			saveGuardians(store, sender, _tx.Guardians)
+			saveRecovery(store, sender, nil)
+		}
+	case TxProposeRecovery:
+		guardians := loadGuardians(store, _tx.Account)
+		if guardians == nil {
+			return ErrNoGuardians()
+		}
+		if !guardians.isGuardian(sender) {
+			return ErrNotGuardian()
+		}
+		if deliver {
+			saveRecovery(store, _tx.Account, &Recovery{
+				NewKey:    _tx.NewKey,
+				Approvals: []common.Address{sender},
+				Height:    ctx.BlockHeight(),
+			})
+		}
+	case TxApproveRecovery:
+		guardians := loadGuardians(store, _tx.Account)
+		if guardians == nil {
+			return ErrNoGuardians()
+		}
+		if !guardians.isGuardian(sender) {
+			return ErrNotGuardian()
+		}
+		recovery := loadRecovery(store, _tx.Account)
+		if recovery == nil {
+			return ErrNoRecovery()
+		}
+		if recovery.NewKey != _tx.NewKey {
+			return ErrKeyMismatch()
+		}
+		if deliver && !recovery.approved(sender) {
+			recovery.Approvals = append(recovery.Approvals, sender)
+			saveRecovery(store, _tx.Account, recovery)
+		}
+	case TxExecuteRecovery:
+		guardians := loadGuardians(store, _tx.Account)
+		recovery := loadRecovery(store, _tx.Account)
+		if guardians == nil || recovery == nil {
+			return ErrNoRecovery()
+		}
+		if len(recovery.Approvals) < int(guardians.Threshold) ||
+			ctx.BlockHeight() < recovery.Height+guardians.Delay {
+			return ErrNotReady()
+		}
+		if deliver {
+			store.Set(key(RotatedPrefix, _tx.Account), recovery.NewKey.Bytes())
+			saveRecovery(store, _tx.Account, nil)
+			saveGuardians(store, _tx.Account, Guardians{})
+			migrate(store, _tx.Account, recovery.NewKey)
+			commons.TransferAll(_tx.Account, recovery.NewKey)
+		}
+	case TxCancelRecovery:
+		if loadRecovery(store, sender) == nil {
+			return ErrNoRecovery()
+		}
+		if deliver {
+			saveRecovery(store, sender, nil)
+		}
+	default:
+		return errors.ErrUnknownTxType(tx)
+	}
+	return nil
+}
+
+// get the sender from the ctx
+func (h *RecoveryTxHandler) getTxSender(ctx types.Context) (sender common.Address, err error) {
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		return sender, ErrMissingSignature()
+	}
+	return senders[0], nil
+}
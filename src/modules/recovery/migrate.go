@@ -0,0 +1,40 @@
+package recovery
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Migration moves what a module keeps for the account from over to to, the
+// new key it was recovered to.
+type Migration func(store state.SimpleDB, from, to common.Address)
+
+var (
+	migrationsMtx sync.RWMutex
+	migrations    = make(map[string]Migration)
+)
+
+// RegisterMigration makes the recovery of an account move what module keeps
+// for it too. Modules register from init.
+func RegisterMigration(module string, migration Migration) {
+	migrationsMtx.Lock()
+	defer migrationsMtx.Unlock()
+	migrations[module] = migration
+}
+
+// migrate runs the migrations of all the modules, by module name
+func migrate(store state.SimpleDB, from, to common.Address) {
+	migrationsMtx.RLock()
+	defer migrationsMtx.RUnlock()
+	modules := make([]string, 0, len(migrations))
+	for module := range migrations {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		migrations[module](store, from, to)
+	}
+}
@@ -0,0 +1,56 @@
+package recovery
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dora/ultron/types"
+)
+
+func signedBy(addr common.Address) types.Context {
+	ctx := types.NewContext("test", 5, nil)
+	ctx.WithSigners(addr)
+	return ctx
+}
+
+func TestExecuteRecoveryMigrates(t *testing.T) {
+	var migrated [2]common.Address
+	RegisterMigration("test", func(store state.SimpleDB, from, to common.Address) {
+		migrated = [2]common.Address{from, to}
+	})
+	defer func() {
+		migrationsMtx.Lock()
+		delete(migrations, "test")
+		migrationsMtx.Unlock()
+	}()
+
+	store := state.NewMemKVStore()
+	account := common.HexToAddress("0x01")
+	newKey := common.HexToAddress("0x02")
+	guardians := []common.Address{common.HexToAddress("0x03"), common.HexToAddress("0x04")}
+	h := &RecoveryTxHandler{}
+
+	_, err := h.DeliverTx(signedBy(account), store, NewTxSetGuardians(guardians, 2, 0))
+	require.Nil(t, err)
+	_, err = h.DeliverTx(signedBy(guardians[0]), store, NewTxProposeRecovery(account, newKey))
+	require.Nil(t, err)
+	_, err = h.DeliverTx(signedBy(newKey), store, NewTxExecuteRecovery(account))
+	assert.NotNil(t, err, "one approval of two")
+	assert.Equal(t, [2]common.Address{}, migrated)
+
+	_, err = h.DeliverTx(signedBy(guardians[1]), store, NewTxApproveRecovery(account, newKey))
+	require.Nil(t, err)
+	_, err = h.DeliverTx(signedBy(newKey), store, NewTxExecuteRecovery(account))
+	require.Nil(t, err)
+
+	assert.Equal(t, [2]common.Address{account, newKey}, migrated)
+	key, ok := RotatedKey(store, account)
+	assert.True(t, ok)
+	assert.Equal(t, newKey, key)
+	assert.NotNil(t, CheckSigner(store, account))
+	assert.Nil(t, CheckSigner(store, newKey))
+}
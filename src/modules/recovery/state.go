@@ -0,0 +1,83 @@
+package recovery
+
+import (
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	// Keys for store prefixes
+	GuardiansPrefix = []byte{0x0b} // guardians: prefix|account
+	RecoveryPrefix  = []byte{0x0c} // pending recoveries: prefix|account
+	RotatedPrefix   = []byte{0x0d} // new keys of recovered accounts: prefix|account
+)
+
+func init() {
+	substore.Register("recovery", GuardiansPrefix, RecoveryPrefix, RotatedPrefix)
+}
+
+func key(prefix []byte, addr common.Address) []byte {
+	return append(append([]byte{}, prefix...), addr.Bytes()...)
+}
+
+func loadGuardians(store state.SimpleDB, account common.Address) *Guardians {
+	b := store.Get(key(GuardiansPrefix, account))
+	if b == nil {
+		return nil
+	}
+	guardians := new(Guardians)
+	if err := wire.ReadBinaryBytes(b, guardians); err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return guardians
+}
+
+func saveGuardians(store state.SimpleDB, account common.Address, guardians Guardians) {
+	if len(guardians.Guardians) == 0 {
+		store.Remove(key(GuardiansPrefix, account))
+		return
+	}
+	store.Set(key(GuardiansPrefix, account), wire.BinaryBytes(guardians))
+}
+
+func loadRecovery(store state.SimpleDB, account common.Address) *Recovery {
+	b := store.Get(key(RecoveryPrefix, account))
+	if b == nil {
+		return nil
+	}
+	recovery := new(Recovery)
+	if err := wire.ReadBinaryBytes(b, recovery); err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return recovery
+}
+
+func saveRecovery(store state.SimpleDB, account common.Address, recovery *Recovery) {
+	if recovery == nil {
+		store.Remove(key(RecoveryPrefix, account))
+		return
+	}
+	store.Set(key(RecoveryPrefix, account), wire.BinaryBytes(*recovery))
+}
+
+// RotatedKey returns the key that replaced the key of account, false if
+// account was never recovered. Txs signed by a rotated key are rejected.
+func RotatedKey(store state.SimpleDB, account common.Address) (common.Address, bool) {
+	b := store.Get(key(RotatedPrefix, account))
+	if b == nil {
+		return common.Address{}, false
+	}
+	return common.BytesToAddress(b), true
+}
+
+// CheckSigner rejects the txs of an account whose key was rotated
+func CheckSigner(store state.SimpleDB, signer common.Address) error {
+	if _, ok := RotatedKey(store, signer); ok {
+		return ErrKeyFrozen()
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package recovery
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/dora/ultron/const"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// nolint
+const (
+	ByteTxSetGuardians    = 0x78
+	ByteTxProposeRecovery = 0x79
+	ByteTxApproveRecovery = 0x7a
+	ByteTxExecuteRecovery = 0x7b
+	ByteTxCancelRecovery  = 0x7c
+	TypeTxSetGuardians    = constant.ModuleNameRecovery + "/setGuardians"
+	TypeTxProposeRecovery = constant.ModuleNameRecovery + "/propose"
+	TypeTxApproveRecovery = constant.ModuleNameRecovery + "/approve"
+	TypeTxExecuteRecovery = constant.ModuleNameRecovery + "/execute"
+	TypeTxCancelRecovery  = constant.ModuleNameRecovery + "/cancel"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxSetGuardians{}, TypeTxSetGuardians, ByteTxSetGuardians)
+	sdk.TxMapper.RegisterImplementation(TxProposeRecovery{}, TypeTxProposeRecovery, ByteTxProposeRecovery)
+	sdk.TxMapper.RegisterImplementation(TxApproveRecovery{}, TypeTxApproveRecovery, ByteTxApproveRecovery)
+	sdk.TxMapper.RegisterImplementation(TxExecuteRecovery{}, TypeTxExecuteRecovery, ByteTxExecuteRecovery)
+	sdk.TxMapper.RegisterImplementation(TxCancelRecovery{}, TypeTxCancelRecovery, ByteTxCancelRecovery)
+}
+
+// Verify interface at compile time
+var _, _, _, _, _ sdk.TxInner = TxSetGuardians{}, TxProposeRecovery{}, TxApproveRecovery{}, TxExecuteRecovery{}, TxCancelRecovery{}
+
+// TxSetGuardians replaces the guardians of the sender, no guardians turns
+// recovery off. It cancels a pending recovery.
+type TxSetGuardians struct {
+	Guardians
+}
+
+func (tx TxSetGuardians) ValidateBasic() error {
+	if len(tx.Guardians.Guardians) > 0 &&
+		(tx.Threshold == 0 || int(tx.Threshold) > len(tx.Guardians.Guardians)) {
+		return ErrBadThreshold()
+	}
+	if tx.Delay < 0 {
+		return ErrBadDelay()
+	}
+	return nil
+}
+
+func NewTxSetGuardians(guardians []common.Address, threshold uint16, delay int64) sdk.Tx {
+	return TxSetGuardians{Guardians{
+		Guardians: guardians,
+		Threshold: threshold,
+		Delay:     delay,
+	}}.Wrap()
+}
+
+func (tx TxSetGuardians) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxProposeRecovery starts the recovery of Account to NewKey, approved by
+// the guardian sending it. It replaces a pending recovery.
+type TxProposeRecovery struct {
+	Account common.Address `json:"account"`
+	NewKey  common.Address `json:"new_key"`
+}
+
+func (tx TxProposeRecovery) ValidateBasic() error {
+	return nil
+}
+
+func NewTxProposeRecovery(account, newKey common.Address) sdk.Tx {
+	return TxProposeRecovery{
+		Account: account,
+		NewKey:  newKey,
+	}.Wrap()
+}
+
+func (tx TxProposeRecovery) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxApproveRecovery adds the approval of the guardian sending it, NewKey
+// must match the pending recovery.
+type TxApproveRecovery struct {
+	Account common.Address `json:"account"`
+	NewKey  common.Address `json:"new_key"`
+}
+
+func (tx TxApproveRecovery) ValidateBasic() error {
+	return nil
+}
+
+func NewTxApproveRecovery(account, newKey common.Address) sdk.Tx {
+	return TxApproveRecovery{
+		Account: account,
+		NewKey:  newKey,
+	}.Wrap()
+}
+
+func (tx TxApproveRecovery) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxExecuteRecovery rotates the key of Account once the pending recovery
+// has its approvals and delay, anyone may send it.
+type TxExecuteRecovery struct {
+	Account common.Address `json:"account"`
+}
+
+func (tx TxExecuteRecovery) ValidateBasic() error {
+	return nil
+}
+
+func NewTxExecuteRecovery(account common.Address) sdk.Tx {
+	return TxExecuteRecovery{
+		Account: account,
+	}.Wrap()
+}
+
+func (tx TxExecuteRecovery) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxCancelRecovery drops the pending recovery of the sender.
+type TxCancelRecovery struct{}
+
+func (tx TxCancelRecovery) ValidateBasic() error {
+	return nil
+}
+
+func NewTxCancelRecovery() sdk.Tx {
+	return TxCancelRecovery{}.Wrap()
+}
+
+func (tx TxCancelRecovery) Wrap() sdk.Tx { return sdk.Tx{tx} }
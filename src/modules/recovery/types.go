@@ -0,0 +1,45 @@
+// Package recovery lets guardians recover an account whose key is lost. The
+// account names its guardians; once a threshold of them approve a new key
+// and a delay has passed, the recovery is executed: the old key is frozen,
+// its txs are rejected from then on, and the ether of the account moves to
+// the new key when the block is committed. What other modules keep for the
+// account, such as bank balances and delegations, moves right away, see
+// RegisterMigration. The delay leaves the owner time to cancel a recovery it
+// did not ask for.
+package recovery
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Guardians of an account
+type Guardians struct {
+	Guardians []common.Address `json:"guardians"`
+	Threshold uint16           `json:"threshold"` // approvals needed
+	Delay     int64            `json:"delay"`     // blocks between proposal and execution
+}
+
+func (g Guardians) isGuardian(addr common.Address) bool {
+	for _, guardian := range g.Guardians {
+		if guardian == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// Recovery is a pending rotation of the key of an account
+type Recovery struct {
+	NewKey    common.Address   `json:"new_key"`
+	Approvals []common.Address `json:"approvals"`
+	Height    int64            `json:"height"` // block it was proposed at
+}
+
+func (r Recovery) approved(addr common.Address) bool {
+	for _, approval := range r.Approvals {
+		if approval == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,55 @@
+package rent
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/types"
+)
+
+// RentTxHandler takes the genesis parameters of storage rent, which has no
+// txs of its own.
+type RentTxHandler struct {
+}
+
+// InitState - set genesis parameters for storage rent
+func (h *RentTxHandler) InitState(key, value string, store state.SimpleDB) error {
+	params := loadParams(store)
+	switch key {
+	case "deposit_per_slot", "rent_per_slot":
+		amount, ok := new(big.Int).SetString(value, 10)
+		if !ok || amount.Sign() < 0 {
+			return fmt.Errorf("input must be a wei amount: %s", value)
+		}
+		if key == "deposit_per_slot" {
+			params.DepositPerSlot = amount.String()
+		} else {
+			params.RentPerSlot = amount.String()
+		}
+	case "rent_period":
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || i <= 0 {
+			return fmt.Errorf("input must be a positive integer, Error: %v", err)
+		}
+		params.RentPeriod = i
+	default:
+		return errors.ErrUnknownKey(key)
+	}
+
+	saveParams(store, params)
+	return nil
+}
+
+// CheckTx - rent has no txs
+func (h *RentTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	return res, errors.ErrUnknownTxType(tx)
+}
+
+// DeliverTx - rent has no txs
+func (h *RentTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.DeliverResult, err error) {
+	return res, errors.ErrUnknownTxType(tx)
+}
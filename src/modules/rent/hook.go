@@ -0,0 +1,35 @@
+package rent
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Tracker collects the accounts the txs of a block call or create. Those
+// without code are dropped when the block ends, see EndBlock. The contracts
+// they call in turn are recounted when a tx calls them directly.
+type Tracker struct {
+	touched map[common.Address]struct{}
+}
+
+// NewTracker creates a tracker with no contracts touched.
+func NewTracker() *Tracker {
+	return &Tracker{touched: make(map[common.Address]struct{})}
+}
+
+// Executed records the contract tx sent by from called or created.
+func (t *Tracker) Executed(tx *ethTypes.Transaction, from common.Address) {
+	if tx.To() != nil {
+		t.touched[*tx.To()] = struct{}{}
+	} else {
+		t.touched[crypto.CreateAddress(from, tx.Nonce())] = struct{}{}
+	}
+}
+
+// take returns the contracts touched since the last call
+func (t *Tracker) take() map[common.Address]struct{} {
+	touched := t.touched
+	t.touched = make(map[common.Address]struct{})
+	return touched
+}
@@ -0,0 +1,212 @@
+package rent
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/substore"
+	"github.com/dora/ultron/types"
+	"github.com/ethereum/go-ethereum/common"
+	ethState "github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+var emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+// Ledger moves ether in the state of the block being run, and tells the
+// contracts from the other accounts, see backend.Backend.
+type Ledger interface {
+	Balance(addr common.Address) *big.Int
+	Transfer(from, to common.Address, amount *big.Int) error
+	HasCode(addr common.Address) bool
+}
+
+// EndBlock recounts the slots of the contracts touched by the previous
+// block in the state of root it committed, settling their deposits on
+// ledger, then saves the contracts tracker saw in this block for the next
+// one. Every RentPeriod blocks it also charges the rent of all contracts.
+// While rent is off it only empties tracker.
+//
+// A contract whose deposit or rent can't be moved is marked, see
+// FailedPrefix, and skipped: its record is left as it was and it is retried
+// later. Its error is returned for the app to log, the block goes on.
+func EndBlock(store state.SimpleDB, height int64, tracker *Tracker, db ethState.Database, root common.Hash, ledger Ledger) (failed []error) {
+	seen := tracker.take()
+	params := loadParams(store)
+	if !params.enabled() {
+		return nil
+	}
+
+	touched := popTouched(store)
+	// in order, the shape of the store tree depends on it
+	contracts := make([]common.Address, 0, len(seen))
+	for contract := range seen {
+		if ledger.HasCode(contract) {
+			contracts = append(contracts, contract)
+		}
+	}
+	sort.Sort(types.ByAll(contracts))
+	for _, contract := range contracts {
+		store.Set(key(TouchedPrefix, contract), []byte{1})
+	}
+
+	for _, contract := range touched {
+		record := loadRecord(store, contract)
+		if record.LastCharged == 0 {
+			record.LastCharged = height
+		}
+		if err := recount(&record, db, root, contract); err != nil {
+			// the state the last block committed must be there
+			panic(err)
+		}
+		if err := settleDeposit(&record, contract, params.deposit(), ledger); err != nil {
+			failed = append(failed, err)
+			markFailed(store, contract, height)
+			// recount it with the next block
+			store.Set(key(TouchedPrefix, contract), []byte{1})
+			continue
+		}
+		clearFailed(store, contract)
+		saveRecord(store, contract, record)
+	}
+
+	if height%params.RentPeriod == 0 {
+		failed = append(failed, chargeRent(store, height, params.rent(), ledger)...)
+	}
+	return failed
+}
+
+// settleDeposit takes the deposit of new slots from contract, as far as its
+// balance goes, and gives back the deposit of the deleted ones. record is
+// left as it was if the transfer fails.
+func settleDeposit(record *Record, contract common.Address, perSlot *big.Int, ledger Ledger) error {
+	want := new(big.Int).Mul(perSlot, big.NewInt(record.Slots))
+	held := record.depositValue()
+	switch diff := new(big.Int).Sub(want, held); diff.Sign() {
+	case 1:
+		if balance := ledger.Balance(contract); diff.Cmp(balance) > 0 {
+			diff.Set(balance)
+		}
+		if err := ledger.Transfer(contract, constant.RentAccount, diff); err != nil {
+			return fmt.Errorf("taking the deposit of %s: %v", contract.Hex(), err)
+		}
+		held.Add(held, diff)
+	case -1:
+		diff.Neg(diff)
+		if err := ledger.Transfer(constant.RentAccount, contract, diff); err != nil {
+			return fmt.Errorf("giving back the deposit of %s: %v", contract.Hex(), err)
+		}
+		held.Sub(held, diff)
+	}
+	record.Deposit = held.String()
+	return nil
+}
+
+// chargeRent burns the rent of every contract since it was last charged,
+// as far as its balance goes. A contract it fails to charge is marked and
+// charged again, from the same block on, the next time.
+func chargeRent(store state.SimpleDB, height int64, perSlot *big.Int, ledger Ledger) (failed []error) {
+	if perSlot.Sign() == 0 {
+		return nil
+	}
+	for _, m := range substore.New(store, RecordPrefix).List(nil, nil, 0) {
+		contract := common.BytesToAddress(m.Key)
+		record := loadRecord(store, contract)
+		due := new(big.Int).Mul(perSlot, big.NewInt(record.Slots*(height-record.LastCharged)))
+		if balance := ledger.Balance(contract); due.Cmp(balance) > 0 {
+			due.Set(balance)
+		}
+		if due.Sign() > 0 {
+			if err := ledger.Transfer(contract, constant.BurnAccount, due); err != nil {
+				failed = append(failed, fmt.Errorf("charging the rent of %s: %v", contract.Hex(), err))
+				markFailed(store, contract, height)
+				continue
+			}
+		}
+		clearFailed(store, contract)
+		record.LastCharged = height
+		saveRecord(store, contract, record)
+	}
+	return failed
+}
+
+// recount brings the slots of record to those contract holds in the state
+// of root. Only the storage nodes that changed since the root record was
+// counted at are walked, the whole storage the first time.
+func recount(record *Record, db ethState.Database, root common.Hash, contract common.Address) error {
+	from := record.Root
+	if from == (common.Hash{}) {
+		from = emptyRoot
+		record.Slots = 0
+	}
+	to, err := storageRoot(db, root, contract)
+	if err != nil {
+		return err
+	}
+	change, err := slotsChange(db, contract, from, to)
+	if err != nil {
+		return err
+	}
+	record.Slots += change
+	record.Root = to
+	return nil
+}
+
+// storageRoot returns the root of the storage of contract in the state of
+// root, the empty root if the account is gone.
+func storageRoot(db ethState.Database, root common.Hash, contract common.Address) (common.Hash, error) {
+	accounts, err := db.OpenTrie(root)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	blob, err := accounts.TryGet(contract.Bytes())
+	if err != nil || blob == nil {
+		return emptyRoot, err
+	}
+	var account ethState.Account
+	if err := rlp.DecodeBytes(blob, &account); err != nil {
+		return common.Hash{}, err
+	}
+	return account.Root, nil
+}
+
+// slotsChange returns the slots the storage of contract at to holds less
+// those it holds at from. The slots set at either are leaves only one of
+// the tries has, a changed slot being a leaf of both.
+func slotsChange(db ethState.Database, contract common.Address, from, to common.Hash) (int64, error) {
+	if from == to {
+		return 0, nil
+	}
+	addrHash := crypto.Keccak256Hash(contract.Bytes())
+	before, err := db.OpenStorageTrie(addrHash, from)
+	if err != nil {
+		return 0, err
+	}
+	after, err := db.OpenStorageTrie(addrHash, to)
+	if err != nil {
+		return 0, err
+	}
+
+	var change int64
+	added, _ := trie.NewDifferenceIterator(before.NodeIterator(nil), after.NodeIterator(nil))
+	for added.Next(true) {
+		if added.Leaf() {
+			change++
+		}
+	}
+	if err := added.Error(); err != nil {
+		return 0, err
+	}
+	removed, _ := trie.NewDifferenceIterator(after.NodeIterator(nil), before.NodeIterator(nil))
+	for removed.Next(true) {
+		if removed.Leaf() {
+			change--
+		}
+	}
+	return change, removed.Error()
+}
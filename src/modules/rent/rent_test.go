@@ -0,0 +1,136 @@
+package rent
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	ethState "github.com/ethereum/go-ethereum/core/state"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dora/ultron/substore"
+)
+
+// ledger holds ether enough for everything, moving it fails if broken
+type ledger struct {
+	broken bool
+}
+
+func (l *ledger) Balance(addr common.Address) *big.Int {
+	return big.NewInt(1e18)
+}
+
+func (l *ledger) Transfer(from, to common.Address, amount *big.Int) error {
+	if l.broken {
+		return fmt.Errorf("broken")
+	}
+	return nil
+}
+
+// the accounts from 0x1 to 0xf are contracts
+func (l *ledger) HasCode(addr common.Address) bool {
+	return addr.Big().Cmp(big.NewInt(0x10)) < 0
+}
+
+func TestEndBlockSkipsFailures(t *testing.T) {
+	memdb, err := ethdb.NewMemDatabase()
+	require.Nil(t, err)
+	db := ethState.NewDatabase(memdb)
+
+	store := state.NewMemKVStore()
+	saveParams(store, Params{DepositPerSlot: "10", RentPerSlot: "1", RentPeriod: 2})
+	contract := common.HexToAddress("0x1")
+	other := common.HexToAddress("0x2")
+	// contract deleted its slots, other still holds one
+	saveRecord(store, contract, Record{Slots: 2, Deposit: "20", LastCharged: 1})
+	saveRecord(store, other, Record{Slots: 1, Deposit: "10", LastCharged: 1})
+	store.Set(key(TouchedPrefix, contract), []byte{1})
+
+	// the block goes on, the contracts are marked and left as they were
+	l := &ledger{broken: true}
+	failed := EndBlock(store, 4, NewTracker(), db, common.Hash{}, l)
+	assert.Len(t, failed, 3)
+	assert.NotNil(t, store.Get(FailedKey(contract)))
+	assert.NotNil(t, store.Get(FailedKey(other)))
+	assert.Equal(t, "20", loadRecord(store, contract).Deposit)
+	assert.Equal(t, int64(1), loadRecord(store, other).LastCharged)
+	assert.NotNil(t, store.Get(key(TouchedPrefix, contract)), "recounted with the next block")
+
+	// and retried later
+	l.broken = false
+	assert.Empty(t, EndBlock(store, 6, NewTracker(), db, common.Hash{}, l))
+	assert.Nil(t, store.Get(FailedKey(contract)))
+	assert.Nil(t, store.Get(FailedKey(other)))
+	assert.Nil(t, store.Get(key(RecordPrefix, contract)))
+	assert.Equal(t, int64(6), loadRecord(store, other).LastCharged)
+}
+
+func TestEndBlockOff(t *testing.T) {
+	memdb, err := ethdb.NewMemDatabase()
+	require.Nil(t, err)
+	store := state.NewMemKVStore()
+	tracker := NewTracker()
+	tracker.Executed(ethTypes.NewTransaction(0, common.HexToAddress("0x1"), big.NewInt(0), big.NewInt(21000), big.NewInt(1), nil), common.Address{})
+
+	// nothing kept while rent is off
+	assert.Empty(t, EndBlock(store, 1, tracker, ethState.NewDatabase(memdb), common.Hash{}, &ledger{}))
+	assert.Empty(t, store.List(nil, nil, 0))
+	assert.Empty(t, tracker.take())
+
+	// the accounts without code are not recounted
+	saveParams(store, Params{DepositPerSlot: "10", RentPerSlot: "0", RentPeriod: 2})
+	for _, to := range []string{"0x1", "0x7eff122b94897ea5b0e2a9abf47b86337fafebdc"} {
+		tracker.Executed(ethTypes.NewTransaction(0, common.HexToAddress(to), big.NewInt(0), big.NewInt(21000), big.NewInt(1), nil), common.Address{})
+	}
+	assert.Empty(t, EndBlock(store, 2, tracker, ethState.NewDatabase(memdb), common.Hash{}, &ledger{}))
+	touched := substore.New(store, TouchedPrefix).List(nil, nil, 0)
+	require.Len(t, touched, 1)
+	assert.Equal(t, common.HexToAddress("0x1"), common.BytesToAddress(touched[0].Key))
+}
+
+func TestRecount(t *testing.T) {
+	memdb, err := ethdb.NewMemDatabase()
+	require.Nil(t, err)
+	contract := common.HexToAddress("0x1")
+	commit := func(parent common.Hash, f func(*ethState.StateDB)) common.Hash {
+		statedb, err := ethState.New(parent, ethState.NewDatabase(memdb))
+		require.Nil(t, err)
+		f(statedb)
+		root, err := statedb.CommitTo(memdb, false)
+		require.Nil(t, err)
+		return root
+	}
+	slot := func(i int64) common.Hash { return common.BigToHash(big.NewInt(i)) }
+
+	root1 := commit(common.Hash{}, func(s *ethState.StateDB) {
+		s.SetCode(contract, []byte{0x00})
+		for i := int64(1); i <= 3; i++ {
+			s.SetState(contract, slot(i), slot(i))
+		}
+	})
+	// one slot set, one deleted, one changed
+	root2 := commit(root1, func(s *ethState.StateDB) {
+		s.SetState(contract, slot(4), slot(4))
+		s.SetState(contract, slot(1), common.Hash{})
+		s.SetState(contract, slot(2), slot(20))
+	})
+	root3 := commit(root2, func(s *ethState.StateDB) {
+		s.Suicide(contract)
+	})
+
+	db := ethState.NewDatabase(memdb)
+	var record Record
+	require.Nil(t, recount(&record, db, root1, contract))
+	assert.Equal(t, int64(3), record.Slots)
+	assert.NotEqual(t, emptyRoot, record.Root)
+	require.Nil(t, recount(&record, db, root2, contract))
+	assert.Equal(t, int64(3), record.Slots)
+	require.Nil(t, recount(&record, db, root3, contract))
+	assert.Equal(t, int64(0), record.Slots)
+	assert.Equal(t, emptyRoot, record.Root)
+}
@@ -0,0 +1,95 @@
+package rent
+
+import (
+	"encoding/binary"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	// Keys for store prefixes
+	ParamKey      = []byte{0x0e} // key for the rent parameters
+	RecordPrefix  = []byte{0x0f} // records: prefix|contract
+	TouchedPrefix = []byte{0x10} // contracts to recount: prefix|contract
+	FailedPrefix  = []byte{0x2b} // contracts rent failed to settle for: prefix|contract, the height
+)
+
+func init() {
+	substore.Register("rent", ParamKey, RecordPrefix, TouchedPrefix, FailedPrefix)
+}
+
+// load/save the rent params
+func loadParams(store state.SimpleDB) (params Params) {
+	b := store.Get(ParamKey)
+	if b == nil {
+		return defaultParams()
+	}
+
+	err := wire.ReadBinaryBytes(b, &params)
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+
+	return
+}
+func saveParams(store state.SimpleDB, params Params) {
+	b := wire.BinaryBytes(params)
+	store.Set(ParamKey, b)
+}
+
+func key(prefix []byte, addr common.Address) []byte {
+	return append(append([]byte{}, prefix...), addr.Bytes()...)
+}
+
+func loadRecord(store state.SimpleDB, contract common.Address) Record {
+	b := store.Get(key(RecordPrefix, contract))
+	if b == nil {
+		return Record{Deposit: "0"}
+	}
+	var record Record
+	if err := wire.ReadBinaryBytes(b, &record); err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return record
+}
+
+func saveRecord(store state.SimpleDB, contract common.Address, record Record) {
+	if record.Slots == 0 && record.depositValue().Sign() == 0 {
+		store.Remove(key(RecordPrefix, contract))
+		return
+	}
+	store.Set(key(RecordPrefix, contract), wire.BinaryBytes(record))
+}
+
+// popTouched removes the contracts to recount from store and returns them
+func popTouched(store state.SimpleDB) []common.Address {
+	touched := substore.New(store, TouchedPrefix)
+	models := touched.List(nil, nil, 0)
+	contracts := make([]common.Address, len(models))
+	for i, m := range models {
+		touched.Remove(m.Key)
+		contracts[i] = common.BytesToAddress(m.Key)
+	}
+	return contracts
+}
+
+// FailedKey is the store key of the height rent last failed to settle for
+// contract at, for the "/key" query. The value is big endian.
+func FailedKey(contract common.Address) []byte {
+	return key(FailedPrefix, contract)
+}
+
+func markFailed(store state.SimpleDB, contract common.Address, height int64) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(height))
+	store.Set(FailedKey(contract), b)
+}
+
+func clearFailed(store state.SimpleDB, contract common.Address) {
+	store.Remove(FailedKey(contract))
+}
@@ -0,0 +1,58 @@
+// Package rent charges contracts for the storage slots they keep. A
+// contract pays a deposit for every slot it holds, given back when the slot
+// is deleted, and a rent per slot and block, burnt every RentPeriod blocks.
+// Both are off unless set in the genesis.
+//
+// Slots are counted in the committed state: the contracts touched by a
+// block are recounted when the next block ends, so deposits lag one block.
+// A record keeps the storage root its slots were counted at, a recount
+// walks the storage nodes changed since only.
+package rent
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Params defines the settings of storage rent
+type Params struct {
+	DepositPerSlot string `json:"deposit_per_slot"` // wei held per slot
+	RentPerSlot    string `json:"rent_per_slot"`    // wei burnt per slot and block
+	RentPeriod     int64  `json:"rent_period"`      // blocks between rent charges
+}
+
+func defaultParams() Params {
+	return Params{
+		DepositPerSlot: "0",
+		RentPerSlot:    "0",
+		RentPeriod:     1000,
+	}
+}
+
+func (p Params) deposit() *big.Int {
+	deposit, _ := new(big.Int).SetString(p.DepositPerSlot, 10)
+	return deposit
+}
+
+func (p Params) rent() *big.Int {
+	rent, _ := new(big.Int).SetString(p.RentPerSlot, 10)
+	return rent
+}
+
+func (p Params) enabled() bool {
+	return p.deposit().Sign() > 0 || p.rent().Sign() > 0
+}
+
+// Record is the storage accounting of a contract
+type Record struct {
+	Slots       int64       `json:"slots"`
+	Deposit     string      `json:"deposit"`      // wei held for the slots
+	LastCharged int64       `json:"last_charged"` // block rent was charged up to
+	Root        common.Hash `json:"root"`         // of the storage the slots were counted in
+}
+
+func (r Record) depositValue() *big.Int {
+	deposit, _ := new(big.Int).SetString(r.Deposit, 10)
+	return deposit
+}
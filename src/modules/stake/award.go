@@ -7,6 +7,7 @@ import (
 
 	"github.com/dora/ultron/commons"
 	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/params"
 	"github.com/dora/ultron/utils"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/tendermint/go-crypto"
@@ -16,6 +17,7 @@ type awardCalculator struct {
 	height          int64
 	validators      Validators
 	transactionFees *big.Int
+	provision       *big.Int // minted by the mint module, nil to inflate at the inflation rate
 }
 
 type validator struct {
@@ -33,14 +35,22 @@ type delegator struct {
 }
 
 const (
-	inflationRate       = 5
 	yearlyBlockNumber   = 365 * 24 * 3600 / 10
 	basicMintableAmount = "1000000000000000000000000000"
 )
 
-func NewAwardCalculator(height int64, validators Validators, transactionFees *big.Int) *awardCalculator {
+// NewAwardCalculator pays out the provision the mint module minted for the
+// block and the transaction fees, or the award of the inflation rate param
+// when provision is nil.
+func NewAwardCalculator(height int64, validators Validators, transactionFees, provision *big.Int) *awardCalculator {
 	fmt.Printf("new award calculator, height: %d, transaction fees: %d\n", height, transactionFees)
-	return &awardCalculator{height, validators, transactionFees}
+	return &awardCalculator{height, validators, transactionFees, provision}
+}
+
+// InflationAward returns the block award at height minted at the inflation
+// rate param, when the mint module is off.
+func InflationAward(height int64) *big.Int {
+	return awardCalculator{height: height}.getTotalBlockAward()
 }
 
 func (ac awardCalculator) getMintableAmount() (result *big.Int) {
@@ -51,6 +61,7 @@ func (ac awardCalculator) getMintableAmount() (result *big.Int) {
 	}
 
 	year := ac.height / yearlyBlockNumber
+	inflationRate := params.Int64(params.InflationRate)
 	pow := big.NewFloat(math.Pow(float64(1+inflationRate/100), float64(year)))
 	new(big.Float).Mul(base, pow).Int(result)
 	fmt.Printf("year: %d, mintable amount: %v\n", year, result)
@@ -58,9 +69,12 @@ func (ac awardCalculator) getMintableAmount() (result *big.Int) {
 }
 
 func (ac awardCalculator) getTotalBlockAward() (result *big.Int) {
+	if ac.provision != nil {
+		return new(big.Int).Set(ac.provision)
+	}
 	blocks := big.NewInt(yearlyBlockNumber)
 	result = new(big.Int)
-	result.Mul(ac.getMintableAmount(), big.NewInt(inflationRate))
+	result.Mul(ac.getMintableAmount(), params.BigInt(params.InflationRate))
 	result.Div(result, big.NewInt(100))
 	result.Div(result, blocks)
 	fmt.Printf("yearly block number: %d, total block award: %v\n", blocks, result)
@@ -120,8 +134,8 @@ func award(val validator, totalShares *big.Int, ac awardCalculator, remaining *b
 	x := new(big.Float).SetInt(val.shares)
 	y := new(big.Float).SetInt(totalShares)
 	val.sharesPercentage = new(big.Float).Quo(x, y)
-	if !again && val.sharesPercentage.Cmp(big.NewFloat(0.1)) > 0 {
-		val.sharesPercentage = big.NewFloat(0.1)
+	if maxShare := maxValidatorShare(); !again && val.sharesPercentage.Cmp(maxShare) > 0 {
+		val.sharesPercentage = maxShare
 	}
 
 	fmt.Printf("val.shares: %f, totalShares: %f, percentage: %f\n", x, y, val.sharesPercentage)
@@ -145,6 +159,12 @@ func award(val validator, totalShares *big.Int, ac awardCalculator, remaining *b
 	return
 }
 
+// maxValidatorShare returns the cap on the share of the award a validator
+// gets, as precise as the 0.1 it replaced so awards replay the same.
+func maxValidatorShare() *big.Float {
+	return big.NewFloat(params.Float64(params.MaxValidatorShare))
+}
+
 func (ac awardCalculator) getBlockAwardForValidator(val validator) (result *big.Int) {
 	blockAward := new(big.Int)
 	blockAward.Add(ac.getTotalBlockAward(), ac.transactionFees)
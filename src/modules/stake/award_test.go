@@ -0,0 +1,23 @@
+package stake
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaxValidatorShare(t *testing.T) {
+	// the cap replaced big.NewFloat(0.1), it must round the same
+	assert.Equal(t, 0, maxValidatorShare().Cmp(big.NewFloat(0.1)))
+
+	ac := awardCalculator{}
+	capped := validator{sharesPercentage: maxValidatorShare()}
+	old := validator{sharesPercentage: big.NewFloat(0.1)}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		award := new(big.Int).Rand(r, new(big.Int).Exp(big.NewInt(10), big.NewInt(24), nil))
+		assert.Equal(t, ac.getAwardForValidator(old, award), ac.getAwardForValidator(capped, award))
+	}
+}
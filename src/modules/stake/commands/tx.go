@@ -39,16 +39,18 @@ The stake/slot/cancel tx is to cancel all remianing amounts from an unaccepted s
 
 // nolint
 const (
-	FlagPubKey           = "pubkey"
-	FlagAmount           = "amount"
-	FlagMaxAmount        = "max-amount"
-	FlagCompRate         = "comp-rate"
-	FlagAddress          = "address"
-	FlagValidatorAddress = "validator-address"
-	FlagWebsite          = "website"
-	FlagLocation         = "location"
-	FlagDetails          = "details"
-	FlagVerified         = "verified"
+	FlagPubKey            = "pubkey"
+	FlagAmount            = "amount"
+	FlagMaxAmount         = "max-amount"
+	FlagCompRate          = "comp-rate"
+	FlagMaxCompRateChange = "max-comp-rate-change"
+	FlagAddress           = "address"
+	FlagValidatorAddress  = "validator-address"
+	FlagWebsite           = "website"
+	FlagLocation          = "location"
+	FlagDetails           = "details"
+	FlagVerified          = "verified"
+	FlagToValidator       = "to-validator-address"
 )
 
 // nolint
@@ -88,6 +90,16 @@ var (
 		Short: "Withdraw coins from a validator/candidate",
 		RunE:  cmdWithdraw,
 	}
+	CmdUnjail = &cobra.Command{
+		Use:   "unjail",
+		Short: "Allows a validator jailed for downtime to rejoin once the jail time is over",
+		RunE:  cmdUnjail,
+	}
+	CmdRedelegate = &cobra.Command{
+		Use:   "redelegate",
+		Short: "Move delegated coins to another validator/candidate without unbonding them",
+		RunE:  cmdRedelegate,
+	}
 )
 
 func init() {
@@ -107,6 +119,10 @@ func init() {
 
 	fsCompRate := flag.NewFlagSet("", flag.ContinueOnError)
 	fsCompRate.String(FlagCompRate, "0", "The compensation percentage of block awards to be distributed to the validator")
+	fsCompRate.String(FlagMaxCompRateChange, "0.01", "The max change of the comp-rate a day")
+
+	fsNewCompRate := flag.NewFlagSet("", flag.ContinueOnError)
+	fsNewCompRate.String(FlagCompRate, "", "optional new compensation percentage, changed at most once a day")
 
 	fsAddr := flag.NewFlagSet("", flag.ContinueOnError)
 	fsAddr.String(FlagAddress, "", "Account address")
@@ -123,6 +139,7 @@ func init() {
 	CmdDeclareCandidacy.Flags().AddFlagSet(fsCompRate)
 
 	CmdUpdateCandidacy.Flags().AddFlagSet(fsCandidate)
+	CmdUpdateCandidacy.Flags().AddFlagSet(fsNewCompRate)
 
 	CmdVerifyCandidacy.Flags().AddFlagSet(fsValidatorAddress)
 	CmdVerifyCandidacy.Flags().AddFlagSet(fsVerified)
@@ -132,6 +149,13 @@ func init() {
 
 	CmdWithdraw.Flags().AddFlagSet(fsValidatorAddress)
 	CmdWithdraw.Flags().AddFlagSet(fsAmount)
+
+	fsToValidator := flag.NewFlagSet("", flag.ContinueOnError)
+	fsToValidator.String(FlagToValidator, "", "validator address to move the coins to")
+
+	CmdRedelegate.Flags().AddFlagSet(fsValidatorAddress)
+	CmdRedelegate.Flags().AddFlagSet(fsToValidator)
+	CmdRedelegate.Flags().AddFlagSet(fsAmount)
 }
 
 func cmdDeclareCandidacy(cmd *cobra.Command, args []string) error {
@@ -159,7 +183,13 @@ func cmdDeclareCandidacy(cmd *cobra.Command, args []string) error {
 		Details:  viper.GetString(FlagDetails),
 	}
 
-	tx := stake.NewTxDeclareCandidacy(pk, maxAmount, compRate, description)
+	maxCompRateChange := viper.GetString(FlagMaxCompRateChange)
+	fMaxCompRateChange := utils.ParseFloat(maxCompRateChange)
+	if fMaxCompRateChange <= 0 || fMaxCompRateChange > 1 {
+		return fmt.Errorf("max-comp-rate-change must between 0 and 1")
+	}
+
+	tx := stake.NewTxDeclareCandidacy(pk, maxAmount, compRate, maxCompRateChange, description)
 	return txcmd.DoTx(tx)
 }
 
@@ -179,7 +209,15 @@ func cmdUpdateCandidacy(cmd *cobra.Command, args []string) error {
 		Details:  viper.GetString(FlagDetails),
 	}
 
-	tx := stake.NewTxUpdateCandidacy(maxAmount, description)
+	compRate := viper.GetString(FlagCompRate)
+	if compRate != "" {
+		fCompRate := utils.ParseFloat(compRate)
+		if fCompRate <= 0 || fCompRate >= 1 {
+			return fmt.Errorf("comp-rate must between 0 and 1")
+		}
+	}
+
+	tx := stake.NewTxUpdateCandidacy(maxAmount, compRate, description)
 	return txcmd.DoTx(tx)
 }
 
@@ -204,6 +242,11 @@ func cmdActivateCandidacy(cmd *cobra.Command, args []string) error {
 	return txcmd.DoTx(tx)
 }
 
+func cmdUnjail(cmd *cobra.Command, args []string) error {
+	tx := stake.NewTxUnjail()
+	return txcmd.DoTx(tx)
+}
+
 func cmdDelegate(cmd *cobra.Command, args []string) error {
 	amount := viper.GetString(FlagAmount)
 	v := new(big.Int)
@@ -237,3 +280,21 @@ func cmdWithdraw(cmd *cobra.Command, args []string) error {
 	tx := stake.NewTxWithdraw(validatorAddress, amount)
 	return txcmd.DoTx(tx)
 }
+
+func cmdRedelegate(cmd *cobra.Command, args []string) error {
+	from := viper.GetString(FlagValidatorAddress)
+	to := viper.GetString(FlagToValidator)
+	if from == "" || to == "" {
+		return fmt.Errorf("please enter validator addresses using --validator-address and --to-validator-address")
+	}
+
+	amount := viper.GetString(FlagAmount)
+	v := new(big.Int)
+	_, ok := v.SetString(amount, 10)
+	if !ok || v.Cmp(big.NewInt(0)) <= 0 {
+		return fmt.Errorf("amount must be positive interger")
+	}
+
+	tx := stake.NewTxRedelegate(common.HexToAddress(from), common.HexToAddress(to), amount)
+	return txcmd.DoTx(tx)
+}
@@ -0,0 +1,166 @@
+package stake
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/modules/upgrade"
+	"github.com/dora/ultron/utils"
+)
+
+// CommissionUpgrade is the upgrade from which on candidates may change their
+// comp rate, within the max change they declared and once a day. Before it
+// the comp rate of updateCandidacy is ignored and no limits are kept, as the
+// binaries without them do.
+const CommissionUpgrade = "commission-limits"
+
+func init() {
+	// nothing to migrate, candidates declared before it get the default limits
+	upgrade.RegisterHandler(CommissionUpgrade, func(state.SimpleDB) error { return nil })
+}
+
+const (
+	// blocksPerDay bounds how often a candidate may change its commission
+	blocksPerDay = 24 * 3600 / 10
+
+	defaultMaxCompRateChange = "0.01"
+)
+
+// nolint
+var (
+	CommissionPrefix = []byte{0x15} // commission limits of the candidates, by pubkey
+)
+
+// Commission holds how far and when a candidate last moved its comp rate,
+// the rate itself is kept with the candidate.
+type Commission struct {
+	MaxChange string `json:"max_change"` // largest change of the rate a day
+	ChangedAt int64  `json:"changed_at"` // height the rate was last set
+}
+
+func commissionKey(pubKey string) []byte {
+	return append(CommissionPrefix, []byte(pubKey)...)
+}
+
+func loadCommission(store state.SimpleDB, pubKey string) (commission Commission) {
+	b := store.Get(commissionKey(pubKey))
+	if b == nil {
+		return Commission{MaxChange: defaultMaxCompRateChange}
+	}
+
+	err := wire.ReadBinaryBytes(b, &commission)
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return
+}
+
+// commissionLimited tells if comp rates may change, see CommissionUpgrade.
+func commissionLimited(store state.SimpleDB) bool {
+	return upgrade.Done(store, CommissionUpgrade)
+}
+
+func saveCommission(store state.SimpleDB, pubKey string, commission Commission) {
+	store.Set(commissionKey(pubKey), wire.BinaryBytes(commission))
+}
+
+// parseRate reads a rate between 0 and 1.
+func parseRate(rate string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok {
+		return nil, ErrBadCompRate()
+	}
+	if r.Sign() < 0 {
+		return nil, ErrCommissionNegative()
+	}
+	if r.Cmp(big.NewRat(1, 1)) > 0 {
+		return nil, ErrCommissionHuge()
+	}
+	return r, nil
+}
+
+// checkCompRateChange makes sure the candidate may move its comp rate to
+// rate at height: by no more than the max change it declared, and once a day.
+func checkCompRateChange(store state.SimpleDB, candidate *Candidate, rate string, height int64) error {
+	commission := loadCommission(store, utils.PubKeyString(candidate.PubKey))
+	if height < commission.ChangedAt+blocksPerDay {
+		return ErrCommissionChangeTooSoon()
+	}
+
+	to, err := parseRate(rate)
+	if err != nil {
+		return err
+	}
+	from, ok := new(big.Rat).SetString(candidate.CompRate)
+	if !ok {
+		from = new(big.Rat)
+	}
+	maxChange, _ := new(big.Rat).SetString(commission.MaxChange)
+	diff := new(big.Rat).Sub(to, from)
+	if diff.Abs(diff).Cmp(maxChange) > 0 {
+		return ErrCommissionChangeTooBig()
+	}
+	return nil
+}
+
+//_________________________________________________________________________
+
+// Projection is the yearly return ProjectedAPR expects of a candidate.
+type Projection struct {
+	Validator        common.Address `json:"validator"`
+	CompRate         string         `json:"comp_rate"`
+	SharesPercentage string         `json:"shares_percentage"` // of the block award, capped by a param
+	DelegatorsAward  string         `json:"delegators_award"`  // yearly, their comp rate of the award
+	APR              string         `json:"apr"`
+}
+
+// ProjectedAPR estimates the yearly return of the shares delegated to the
+// candidate at height: the block award it would earn signing every block of
+// the coming year with the current validator set, times the comp rate its
+// delegators get, over its shares. Transaction fees and the second round of
+// AwardAll are left out. provision is the minted award of a block, after
+// the community tax.
+func ProjectedAPR(candidate *Candidate, height int64, provision *big.Int) Projection {
+	p := Projection{
+		Validator:        candidate.OwnerAddress,
+		CompRate:         candidate.CompRate,
+		SharesPercentage: "0",
+		DelegatorsAward:  "0",
+		APR:              "0",
+	}
+
+	candidates := GetCandidates()
+	candidates.Sort()
+	totalShares := new(big.Int)
+	elected := false
+	for _, val := range candidates.Validators() {
+		totalShares.Add(totalShares, utils.ParseInt(val.Shares))
+		if val.OwnerAddress == candidate.OwnerAddress {
+			elected = true
+		}
+	}
+	shares := candidate.ParseShares()
+	if !elected || shares.Sign() == 0 {
+		return p
+	}
+
+	percentage := new(big.Float).Quo(new(big.Float).SetInt(shares), new(big.Float).SetInt(totalShares))
+	if maxShare := maxValidatorShare(); percentage.Cmp(maxShare) > 0 {
+		percentage = maxShare
+	}
+
+	ac := awardCalculator{height: height, provision: provision}
+	award := new(big.Float).SetInt(ac.getTotalBlockAward())
+	award.Mul(award, big.NewFloat(yearlyBlockNumber))
+	award.Mul(award, percentage)
+	award.Mul(award, big.NewFloat(candidate.ParseCompRate()))
+	delegatorsAward, _ := award.Int(nil)
+
+	p.SharesPercentage = percentage.Text('f', 6)
+	p.DelegatorsAward = delegatorsAward.String()
+	p.APR = new(big.Float).Quo(award, new(big.Float).SetInt(shares)).Text('f', 6)
+	return p
+}
@@ -0,0 +1,52 @@
+package stake
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-crypto"
+
+	"github.com/dora/ultron/modules/upgrade"
+	"github.com/dora/ultron/utils"
+)
+
+func TestCompRateChange(t *testing.T) {
+	store := state.NewMemKVStore()
+	candidate := &Candidate{
+		PubKey:   crypto.GenPrivKeyEd25519().PubKey(),
+		CompRate: "0.2",
+	}
+	store.Set(upgrade.DoneKey(CommissionUpgrade), []byte{1})
+	d := deliver{store: store, height: 100}
+	d.saveCommission(candidate, "0.05")
+
+	// once a day
+	assert.Error(t, checkCompRateChange(store, candidate, "0.21", 100+blocksPerDay-1))
+	height := int64(100 + blocksPerDay)
+	assert.NoError(t, checkCompRateChange(store, candidate, "0.25", height))
+	assert.NoError(t, checkCompRateChange(store, candidate, "0.15", height))
+
+	// within the max change, and between 0 and 1
+	assert.Error(t, checkCompRateChange(store, candidate, "0.26", height))
+	assert.Error(t, checkCompRateChange(store, candidate, "0.14", height))
+	candidate.CompRate = "0.98"
+	assert.Error(t, checkCompRateChange(store, candidate, "1.01", height))
+	assert.Error(t, checkCompRateChange(store, candidate, "high", height))
+}
+
+func TestCommissionBeforeUpgrade(t *testing.T) {
+	store := state.NewMemKVStore()
+	candidate := &Candidate{
+		PubKey:   crypto.GenPrivKeyEd25519().PubKey(),
+		CompRate: "0.2",
+	}
+	d := deliver{store: store, height: 100}
+	d.saveCommission(candidate, "0.05")
+	assert.Nil(t, store.Get(commissionKey(utils.PubKeyString(candidate.PubKey))))
+	assert.False(t, commissionLimited(store))
+
+	store.Set(upgrade.DoneKey(CommissionUpgrade), []byte{1})
+	d.saveCommission(candidate, "0.05")
+	assert.Equal(t, Commission{MaxChange: "0.05", ChangedAt: 100}, loadCommission(store, utils.PubKeyString(candidate.PubKey)))
+}
@@ -2,7 +2,9 @@ package stake
 
 import (
 	"database/sql"
+	"io/ioutil"
 	"path"
+	"sync"
 
 	"github.com/dora/ultron/utils"
 	"github.com/ethereum/go-ethereum/common"
@@ -12,11 +14,57 @@ import (
 	"github.com/dora/ultron/const"
 )
 
-func getDb() *sql.DB {
-	rootDir := viper.GetString(cli.HomeFlag)
-	stakeDbPath := path.Join(rootDir, "data", constant.DatabaseName)
+var (
+	dbMtx sync.Mutex // held from UseDatabase to the release
+
+	pathMtx sync.RWMutex
+	dbPath  string // set by UseDatabase, the home flag decides otherwise
+)
+
+// UseDatabase points the stake module at the sqlite db under path until
+// the returned release func is called. Nodes sharing a process hold it
+// around every stake call so each one reads and writes its own db.
+func UseDatabase(path string) (release func()) {
+	dbMtx.Lock()
+	setDBPath(path)
+	return func() {
+		setDBPath("")
+		dbMtx.Unlock()
+	}
+}
+
+func setDBPath(path string) {
+	pathMtx.Lock()
+	dbPath = path
+	pathMtx.Unlock()
+}
 
-	db, err := sql.Open("sqlite3", stakeDbPath)
+// stakeDBPath returns the db the stake calls use
+func stakeDBPath() string {
+	pathMtx.RLock()
+	stakeDbPath := dbPath
+	pathMtx.RUnlock()
+	if stakeDbPath == "" {
+		rootDir := viper.GetString(cli.HomeFlag)
+		stakeDbPath = path.Join(rootDir, "data", constant.DatabaseName)
+	}
+	return stakeDbPath
+}
+
+// DumpDatabase returns the content of the stake db, LoadDatabase replaces
+// it. Every stake call closes its connection, so the file is whole between
+// two calls.
+func DumpDatabase() ([]byte, error) {
+	return ioutil.ReadFile(stakeDBPath())
+}
+
+// LoadDatabase replaces the stake db with data taken by DumpDatabase
+func LoadDatabase(data []byte) error {
+	return ioutil.WriteFile(stakeDBPath(), data, 0644)
+}
+
+func getDb() *sql.DB {
+	db, err := sql.Open("sqlite3", stakeDBPath())
 	if err != nil {
 		panic(err)
 	}
@@ -0,0 +1,50 @@
+package stake
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseDatabase(t *testing.T) {
+	home := stakeDBPath()
+	release := UseDatabase("/node1/stake.db")
+	assert.Equal(t, "/node1/stake.db", stakeDBPath())
+
+	// read while another node waits for the db
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer UseDatabase("/node2/stake.db")()
+		assert.Equal(t, "/node2/stake.db", stakeDBPath())
+	}()
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, "/node1/stake.db", stakeDBPath())
+	}
+	release()
+	wg.Wait()
+	assert.Equal(t, home, stakeDBPath())
+}
+
+func TestDumpDatabase(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stake")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "stake.db")
+	require.Nil(t, ioutil.WriteFile(file, []byte("before"), 0644))
+	defer UseDatabase(file)()
+
+	data, err := DumpDatabase()
+	require.Nil(t, err)
+	require.Nil(t, ioutil.WriteFile(file, []byte("after"), 0644))
+	require.Nil(t, LoadDatabase(data))
+	content, err := ioutil.ReadFile(file)
+	require.Nil(t, err)
+	assert.Equal(t, "before", string(content))
+}
@@ -0,0 +1,125 @@
+package stake
+
+import (
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/modules/upgrade"
+	"github.com/dora/ultron/utils"
+)
+
+// DowntimeUpgrade is the upgrade from which on the missed blocks of the
+// validators are counted and the ones missing too many are jailed. It
+// changes the app hash and the validator set, so the chain switches at the
+// height it is scheduled at.
+const DowntimeUpgrade = "downtime-jail"
+
+func init() {
+	// nothing to migrate, the counters start from the upgrade on
+	upgrade.RegisterHandler(DowntimeUpgrade, func(state.SimpleDB) error { return nil })
+}
+
+// nolint
+var (
+	DowntimePrefix = []byte{0x19} // missed block counters of the validators, by owner address
+)
+
+// Downtime counts the blocks a validator missed and tells if it is jailed.
+type Downtime struct {
+	WindowStart  int64 `json:"window_start"`  // first block of the current window
+	MissedBlocks int64 `json:"missed_blocks"` // in the current window
+	TotalMissed  int64 `json:"total_missed"`
+	Jailed       bool  `json:"jailed"`
+	JailedUntil  int64 `json:"jailed_until"` // first block it can unjail at
+	JailCount    int64 `json:"jail_count"`
+}
+
+// JailEvent is posted on the event mux of the node when a validator is
+// jailed for missing too many blocks.
+type JailEvent struct {
+	Validator    common.Address `json:"validator"`
+	PubKey       string         `json:"pub_key"`
+	Height       int64          `json:"height"`
+	MissedBlocks int64          `json:"missed_blocks"`
+	JailedUntil  int64          `json:"jailed_until"`
+}
+
+// DowntimeKey is the store key of the downtime of the validator owned by
+// owner.
+func DowntimeKey(owner common.Address) []byte {
+	return append(DowntimePrefix, owner.Bytes()...)
+}
+
+// ParseDowntime decodes a downtime stored under DowntimeKey.
+func ParseDowntime(value []byte) (downtime Downtime, err error) {
+	if len(value) == 0 {
+		return
+	}
+	err = wire.ReadBinaryBytes(value, &downtime)
+	return
+}
+
+func loadDowntime(store state.SimpleDB, owner common.Address) Downtime {
+	downtime, err := ParseDowntime(store.Get(DowntimeKey(owner)))
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return downtime
+}
+
+func saveDowntime(store state.SimpleDB, owner common.Address, downtime Downtime) {
+	store.Set(DowntimeKey(owner), wire.BinaryBytes(downtime))
+}
+
+// TrackDowntime counts whether candidate signed the block before height. It
+// jails the candidate and returns the event when it missed more than the
+// stake.max_missed_blocks param in its window, the caller has to take it out
+// of the validator set. Nothing is counted before DowntimeUpgrade.
+func TrackDowntime(store state.SimpleDB, candidate *Candidate, height int64, signed bool) *JailEvent {
+	if !upgrade.Done(store, DowntimeUpgrade) {
+		return nil
+	}
+	downtime := loadDowntime(store, candidate.OwnerAddress)
+	if downtime.Jailed {
+		return nil
+	}
+	newWindow := height >= downtime.WindowStart+params.Int64(params.DowntimeWindow)
+	if newWindow {
+		downtime.WindowStart = height
+		downtime.MissedBlocks = 0
+	}
+	if signed {
+		if newWindow {
+			saveDowntime(store, candidate.OwnerAddress, downtime)
+		}
+		return nil
+	}
+
+	downtime.MissedBlocks++
+	downtime.TotalMissed++
+	if downtime.MissedBlocks <= params.Int64(params.MaxMissedBlocks) {
+		saveDowntime(store, candidate.OwnerAddress, downtime)
+		return nil
+	}
+
+	downtime.Jailed = true
+	downtime.JailedUntil = height + params.Int64(params.JailBlocks)
+	downtime.JailCount++
+	saveDowntime(store, candidate.OwnerAddress, downtime)
+
+	// deactivates the candidate
+	PunishAbsentValidator(candidate.PubKey)
+	candidate = GetCandidateByAddress(candidate.OwnerAddress)
+	candidate.VotingPower = 0
+	updateCandidate(candidate)
+
+	return &JailEvent{
+		Validator:    candidate.OwnerAddress,
+		PubKey:       utils.PubKeyString(candidate.PubKey),
+		Height:       height,
+		MissedBlocks: downtime.MissedBlocks,
+		JailedUntil:  downtime.JailedUntil,
+	}
+}
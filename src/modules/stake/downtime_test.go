@@ -0,0 +1,44 @@
+package stake
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+func TestDowntimeWindow(t *testing.T) {
+	store := state.NewMemKVStore()
+	store.Set(upgrade.DoneKey(DowntimeUpgrade), []byte{1})
+	candidate := &Candidate{OwnerAddress: common.HexToAddress("0x1")}
+	window, maxMissed := params.Int64(params.DowntimeWindow), params.Int64(params.MaxMissedBlocks)
+
+	for h := int64(1); h <= maxMissed; h++ {
+		assert.Nil(t, TrackDowntime(store, candidate, h, false))
+	}
+	assert.Nil(t, TrackDowntime(store, candidate, maxMissed+1, true))
+	downtime := loadDowntime(store, candidate.OwnerAddress)
+	assert.Equal(t, maxMissed, downtime.MissedBlocks)
+	assert.False(t, downtime.Jailed)
+
+	// the count starts over with the next window
+	assert.Nil(t, TrackDowntime(store, candidate, window, false))
+	downtime = loadDowntime(store, candidate.OwnerAddress)
+	assert.Equal(t, window, downtime.WindowStart)
+	assert.Equal(t, int64(1), downtime.MissedBlocks)
+	assert.Equal(t, maxMissed+1, downtime.TotalMissed)
+}
+
+func TestDowntimeBeforeUpgrade(t *testing.T) {
+	store := state.NewMemKVStore()
+	candidate := &Candidate{OwnerAddress: common.HexToAddress("0x1")}
+
+	for h := int64(1); h <= params.Int64(params.MaxMissedBlocks)+1; h++ {
+		assert.Nil(t, TrackDowntime(store, candidate, h, false))
+	}
+	assert.Nil(t, store.Get(DowntimeKey(candidate.OwnerAddress)))
+}
@@ -0,0 +1,55 @@
+package stake
+
+import (
+	"encoding/binary"
+
+	"github.com/cosmos/cosmos-sdk/state"
+)
+
+// nolint
+var (
+	EpochBlocksKey = []byte{0x2a} // blocks between validator set updates, kept apart from the Params
+)
+
+// ParseEpochBlocks decodes the epoch length stored under EpochBlocksKey, 0
+// if the validator set may change after every block.
+func ParseEpochBlocks(value []byte) int64 {
+	if len(value) != 8 {
+		return 0
+	}
+	return int64(binary.BigEndian.Uint64(value))
+}
+
+// loadEpochBlocks returns the epoch length. It isn't a field of the Params,
+// which would break the decoding of the Params stored before.
+func loadEpochBlocks(store state.SimpleDB) int64 {
+	return ParseEpochBlocks(store.Get(EpochBlocksKey))
+}
+
+func saveEpochBlocks(store state.SimpleDB, blocks int64) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(blocks))
+	store.Set(EpochBlocksKey, b)
+}
+
+// EpochEnds tells if the validator set may change at the end of the block
+// at height.
+func EpochEnds(store state.SimpleDB, height int64) bool {
+	return epochEnds(loadEpochBlocks(store), height)
+}
+
+// epochEnds tells if the block at height is the last of its epoch when
+// epochs last blocks.
+func epochEnds(blocks, height int64) bool {
+	return blocks <= 0 || height%blocks == 0
+}
+
+// EpochOf returns the number and the first and last blocks of the epoch of
+// the block at height when epochs last blocks.
+func EpochOf(blocks, height int64) (number, first, last int64) {
+	if blocks <= 0 || height < 1 {
+		return height, height, height
+	}
+	number = (height - 1) / blocks
+	return number, number*blocks + 1, (number + 1) * blocks
+}
@@ -0,0 +1,44 @@
+package stake
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/go-wire"
+)
+
+func TestEpoch(t *testing.T) {
+	store := state.NewMemKVStore()
+	assert.True(t, EpochEnds(store, 7))
+
+	saveEpochBlocks(store, 10)
+	assert.False(t, EpochEnds(store, 9))
+	assert.True(t, EpochEnds(store, 10))
+
+	for h, want := range map[int64][3]int64{1: {0, 1, 10}, 10: {0, 1, 10}, 11: {1, 11, 20}, 25: {2, 21, 30}} {
+		number, first, last := EpochOf(loadEpochBlocks(store), h)
+		assert.Equal(t, want, [3]int64{number, first, last}, "height %d", h)
+	}
+}
+
+// the params stored by the chains running before still decode
+func TestParamsDecodeStored(t *testing.T) {
+	stored := struct {
+		StakeAccount            common.Address
+		MaxVals                 uint16
+		Validators              string
+		ReserveRequirementRatio string
+		EnableHybridElection    bool
+		TicketPrice             uint64
+	}{common.HexToAddress("0x1"), 7, "", "0.2", true, 5}
+	store := state.NewMemKVStore()
+	store.Set(ParamKey, wire.BinaryBytes(stored))
+
+	params := loadParams(store)
+	assert.Equal(t, uint16(7), params.MaxVals)
+	assert.Equal(t, uint64(5), params.TicketPrice)
+	assert.Equal(t, int64(defaultUnbondingBlocks), loadUnbondingBlocks(store))
+	assert.Equal(t, int64(0), loadEpochBlocks(store))
+}
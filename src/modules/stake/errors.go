@@ -13,6 +13,9 @@ var (
 	errNoBondingAcct      = fmt.Errorf("No bond account for this (address, validator) pair")
 	errCommissionNegative = fmt.Errorf("Commission must be positive")
 	errCommissionHuge     = fmt.Errorf("Commission cannot be more than 100%")
+	errBadCompRate        = fmt.Errorf("Commission must be a decimal between 0 and 1")
+	errCommissionTooSoon  = fmt.Errorf("Commission can only be changed once a day")
+	errCommissionTooBig   = fmt.Errorf("Commission change exceeds the declared max change")
 
 	errBadValidatorAddr                = fmt.Errorf("Validator does not exist for that address")
 	errCandidateExistsAddr             = fmt.Errorf("Candidate already exist, cannot re-declare candidacy")
@@ -28,6 +31,11 @@ var (
 	errDelegationNotExists             = fmt.Errorf("no corresponding delegation exists")
 	errInvalidWithdrawalAmount         = fmt.Errorf("invalid withdrawal amount")
 	errCandidateWithdrawalDisallowed   = fmt.Errorf("candidate can't withdraw the reserved reservation fund")
+	errRedelegateToSelf                = fmt.Errorf("cannot redelegate to the same validator")
+	errRedelegationPending             = fmt.Errorf("stake redelegated to the validator hasn't matured yet")
+	errNoUnbondingQueue                = fmt.Errorf("redelegation needs the unbonding queue upgrade")
+	errNotJailed                       = fmt.Errorf("validator is not jailed")
+	errJailed                          = fmt.Errorf("validator is jailed, unjail it once the jail time is over")
 
 	invalidInput = errors.CodeTypeBaseInvalidInput
 )
@@ -79,3 +87,43 @@ func ErrInvalidWithdrawalAmount() error {
 func ErrCandidateWithdrawalDisallowed() error {
 	return errors.WithCode(errCandidateWithdrawalDisallowed, errors.CodeTypeBaseInvalidOutput)
 }
+
+func ErrBadCompRate() error {
+	return errors.WithCode(errBadCompRate, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrCommissionNegative() error {
+	return errors.WithCode(errCommissionNegative, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrCommissionHuge() error {
+	return errors.WithCode(errCommissionHuge, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrCommissionChangeTooSoon() error {
+	return errors.WithCode(errCommissionTooSoon, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrCommissionChangeTooBig() error {
+	return errors.WithCode(errCommissionTooBig, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrRedelegateToSelf() error {
+	return errors.WithCode(errRedelegateToSelf, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrRedelegationPending() error {
+	return errors.WithCode(errRedelegationPending, errors.CodeTypeBaseInvalidOutput)
+}
+
+func ErrNoUnbondingQueue() error {
+	return errors.WithCode(errNoUnbondingQueue, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrNotJailed() error {
+	return errors.WithCode(errNotJailed, errors.CodeTypeBaseInvalidInput)
+}
+
+func ErrJailed() error {
+	return errors.WithCode(errJailed, errors.CodeTypeBaseInvalidInput)
+}
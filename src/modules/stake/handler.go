@@ -35,6 +35,8 @@ type delegatedProofOfStake interface {
 	activateCandidacy(TxActivateCandidacy) error
 	delegate(TxDelegate) error
 	withdraw(TxWithdraw) error
+	redelegate(TxRedelegate) error
+	unjail(TxUnjail) error
 }
 
 type StakeTxHandler struct {
@@ -71,6 +73,18 @@ func (h *StakeTxHandler) InitState(key, value string, store state.SimpleDB) erro
 				return fmt.Errorf("input must be uint64, Error: %v", err.Error())
 		}
 		params.TicketPrice = u
+	case "unbonding_blocks":
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || i < 0 {
+			return fmt.Errorf("input must be non-negative integer, Error: %v", err)
+		}
+		saveUnbondingBlocks(store, i)
+	case "epoch_blocks":
+		i, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || i < 0 {
+			return fmt.Errorf("input must be non-negative integer, Error: %v", err)
+		}
+		saveEpochBlocks(store, i)
 	case "validator":
 		h.setValidator(value, store)
 	default:
@@ -105,7 +119,11 @@ func (h *StakeTxHandler) setValidator(value string, store state.SimpleDB) error
 		params: params,
 	}
 
-	tx := TxDeclareCandidacy{val.PubKey, utils.ToWei(val.MaxAmount).String(), val.CompRate, Description{}}
+	tx := TxDeclareCandidacy{
+		PubKey:    val.PubKey,
+		MaxAmount: utils.ToWei(val.MaxAmount).String(),
+		CompRate:  val.CompRate,
+	}
 	return deliverer.declareGenesisCandidacy(tx, val.Power)
 }
 
@@ -128,6 +146,7 @@ func (h *StakeTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk
 		sender:   sender,
 		params:   params,
 		ethereum: ctx.Ethereum(),
+		height:   ctx.BlockHeight(),
 	}
 
 	switch txInner := tx.Unwrap().(type) {
@@ -145,6 +164,10 @@ func (h *StakeTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk
 		return res, checker.delegate(txInner)
 	case TxWithdraw:
 		return res, checker.withdraw(txInner)
+	case TxRedelegate:
+		return res, checker.redelegate(txInner)
+	case TxUnjail:
+		return res, checker.unjail(txInner)
 	}
 
 	return res, errors.ErrUnknownTxType(tx)
@@ -163,6 +186,7 @@ func (h *StakeTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx s
 		sender:   sender,
 		params:   params,
 		ethereum: ctx.Ethereum(),
+		height:   ctx.BlockHeight(),
 	}
 
 	// Run the transaction
@@ -181,6 +205,10 @@ func (h *StakeTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx s
 		return res, deliverer.delegate(_tx)
 	case TxWithdraw:
 		return res, deliverer.withdraw(_tx)
+	case TxRedelegate:
+		return res, deliverer.redelegate(_tx)
+	case TxUnjail:
+		return res, deliverer.unjail(_tx)
 	}
 
 	return
@@ -202,6 +230,7 @@ type check struct {
 	sender   common.Address
 	params   Params
 	ethereum *eth.Ethereum
+	height   int64
 }
 
 var _ delegatedProofOfStake = check{} // enforce interface at compile time
@@ -262,6 +291,10 @@ func (c check) updateCandidacy(tx TxUpdateCandidacy) error {
 		}
 	}
 
+	if commissionLimited(c.store) && tx.CompRate != "" && tx.CompRate != candidate.CompRate {
+		return checkCompRateChange(c.store, candidate, tx.CompRate, c.height)
+	}
+
 	return nil
 }
 
@@ -301,6 +334,10 @@ func (c check) activateCandidacy(tx TxActivateCandidacy) error {
 		return fmt.Errorf("already activated")
 	}
 
+	if loadDowntime(c.store, c.sender).Jailed {
+		return ErrJailed()
+	}
+
 	return nil
 }
 
@@ -356,6 +393,62 @@ func (c check) withdraw(tx TxWithdraw) error {
 	return nil
 }
 
+func (c check) redelegate(tx TxRedelegate) error {
+	if !unbondingQueued(c.store) {
+		return ErrNoUnbondingQueue()
+	}
+	from := GetCandidateByAddress(tx.FromValidator)
+	if from == nil {
+		return ErrBadValidatorAddr()
+	}
+	to := GetCandidateByAddress(tx.ToValidator)
+	if to == nil {
+		return ErrNoCandidateForAddress()
+	}
+
+	amount, ok := new(big.Int).SetString(tx.Amount, 10)
+	if !ok || amount.Sign() <= 0 {
+		return ErrBadAmount()
+	}
+
+	d := GetDelegation(c.sender, from.PubKey)
+	if d == nil {
+		return ErrDelegationNotExists()
+	}
+	if amount.Cmp(d.Shares()) > 0 {
+		return ErrInvalidWithdrawalAmount()
+	}
+
+	// stake can't hop on before its last redelegation matured
+	if redelegatedTo(c.store, c.sender, tx.FromValidator) {
+		return ErrRedelegationPending()
+	}
+
+	x := new(big.Int).Add(to.ParseShares(), amount)
+	if x.Cmp(to.ParseMaxShares()) > 0 {
+		return ErrReachMaxAmount()
+	}
+
+	return nil
+}
+
+func (c check) unjail(tx TxUnjail) error {
+	candidate := GetCandidateByAddress(c.sender)
+	if candidate == nil {
+		return ErrNoCandidateForAddress()
+	}
+
+	downtime := loadDowntime(c.store, c.sender)
+	if !downtime.Jailed {
+		return ErrNotJailed()
+	}
+	if c.height < downtime.JailedUntil {
+		return ErrJailed()
+	}
+
+	return nil
+}
+
 //_____________________________________________________________________
 
 type deliver struct {
@@ -363,6 +456,7 @@ type deliver struct {
 	sender   common.Address
 	params   Params
 	ethereum *eth.Ethereum
+	height   int64
 }
 
 var _ delegatedProofOfStake = deliver{} // enforce interface at compile time
@@ -373,6 +467,7 @@ func (d deliver) declareCandidacy(tx TxDeclareCandidacy) error {
 	// create and save the empty candidate
 	candidate := NewCandidate(tx.PubKey, d.sender, "0", 0, tx.MaxAmount, tx.CompRate, tx.Description, "N", "Y")
 	SaveCandidate(candidate)
+	d.saveCommission(candidate, tx.MaxCompRateChange)
 
 	// delegate a part of the max staked amount
 	amount := tx.ReserveRequirement(d.params.ReserveRequirementRatio)
@@ -384,6 +479,7 @@ func (d deliver) declareGenesisCandidacy(tx TxDeclareCandidacy, votingPower int6
 	// create and save the empty candidate
 	candidate := NewCandidate(tx.PubKey, d.sender, "0", votingPower, tx.MaxAmount, tx.CompRate, tx.Description, "N", "Y")
 	SaveCandidate(candidate)
+	d.saveCommission(candidate, tx.MaxCompRateChange)
 
 	// delegate a part of the max staked amount
 	amount := new(big.Int).Mul(big.NewInt(votingPower), big.NewInt(1e18))
@@ -391,6 +487,22 @@ func (d deliver) declareGenesisCandidacy(tx TxDeclareCandidacy, votingPower int6
 	return d.delegate(txDelegate)
 }
 
+// saveCommission starts the commission limits of a new candidate, the comp
+// rate may change a day after it is declared. Nothing is kept before
+// CommissionUpgrade.
+func (d deliver) saveCommission(candidate *Candidate, maxChange string) {
+	if !commissionLimited(d.store) {
+		return
+	}
+	if maxChange == "" {
+		maxChange = defaultMaxCompRateChange
+	}
+	saveCommission(d.store, utils.PubKeyString(candidate.PubKey), Commission{
+		MaxChange: maxChange,
+		ChangedAt: d.height,
+	})
+}
+
 func (d deliver) updateCandidacy(tx TxUpdateCandidacy) error {
 	// create and save the empty candidate
 	candidate := GetCandidateByAddress(d.sender)
@@ -398,6 +510,16 @@ func (d deliver) updateCandidacy(tx TxUpdateCandidacy) error {
 		return ErrNoCandidateForAddress()
 	}
 
+	// The comp rate moves within the declared max change, once a day, from
+	// CommissionUpgrade on
+	changeCompRate := commissionLimited(d.store) && tx.CompRate != "" && tx.CompRate != candidate.CompRate
+	if changeCompRate {
+		err := checkCompRateChange(d.store, candidate, tx.CompRate, d.height)
+		if err != nil {
+			return err
+		}
+	}
+
 	// If the max amount is updated, the 10% of self-staking will be re-computed,
 	// and the different will be charged
 	if tx.MaxAmount != "" {
@@ -424,6 +546,13 @@ func (d deliver) updateCandidacy(tx TxUpdateCandidacy) error {
 		}
 	}
 
+	if changeCompRate {
+		candidate.CompRate = tx.CompRate
+		commission := loadCommission(d.store, utils.PubKeyString(candidate.PubKey))
+		commission.ChangedAt = d.height
+		saveCommission(d.store, utils.PubKeyString(candidate.PubKey), commission)
+	}
+
 	// If other information was updated, set the verified status to false
 	if candidate.Description != tx.Description {
 		candidate.Verified = "N"
@@ -443,11 +572,11 @@ func (d deliver) withdrawCandidacy(tx TxWithdrawCandidacy) error {
 		return ErrNoCandidateForAddress()
 	}
 
-	// All staked tokens will be distributed back to delegator addresses.
-	// Self-staked tokens will be refunded back to the validator address.
+	// All staked tokens will be distributed back to delegator addresses once
+	// they unbond. Self-staked tokens will be refunded back to the validator address.
 	delegations := GetDelegationsByPubKey(candidate.PubKey)
 	for _, delegation := range delegations {
-		err := commons.Transfer(d.params.StakeAccount, delegation.DelegatorAddress, delegation.Shares())
+		err := queueUnbonding(d.store, d.params, delegation.DelegatorAddress, validatorAddress, delegation.Shares(), d.height)
 		if err != nil {
 			return err
 		}
@@ -481,6 +610,10 @@ func (d deliver) activateCandidacy(tx TxActivateCandidacy) error {
 		return fmt.Errorf("cannot activate non-exsits candidacy")
 	}
 
+	if loadDowntime(d.store, d.sender).Jailed {
+		return ErrJailed()
+	}
+
 	candidate.Active = "Y"
 	candidate.UpdatedAt = utils.Now()
 	updateCandidate(candidate)
@@ -502,6 +635,13 @@ func (d deliver) delegate(tx TxDelegate) error {
 		return err
 	}
 
+	d.bond(candidate, delegateAmount)
+	return nil
+}
+
+// bond adds amount, already held by the stake account, to the stake of the
+// sender in candidate
+func (d deliver) bond(candidate *Candidate, delegateAmount *big.Int) {
 	// create or update delegation
 	now := utils.Now()
 	delegation := GetDelegation(d.sender, candidate.PubKey)
@@ -509,7 +649,7 @@ func (d deliver) delegate(tx TxDelegate) error {
 		delegation = &Delegation{
 			DelegatorAddress: d.sender,
 			PubKey:           candidate.PubKey,
-			DelegateAmount:   tx.Amount,
+			DelegateAmount:   delegateAmount.String(),
 			AwardAmount:      "0",
 			WithdrawAmount:   "0",
 			SlashAmount:      "0",
@@ -528,7 +668,6 @@ func (d deliver) delegate(tx TxDelegate) error {
 	delegateHistory := &DelegateHistory{0, d.sender, candidate.PubKey, delegateAmount, "delegate", now}
 	updateCandidate(candidate)
 	saveDelegateHistory(delegateHistory)
-	return nil
 }
 
 func (d deliver) withdraw(tx TxWithdraw) error {
@@ -543,6 +682,17 @@ func (d deliver) withdraw(tx TxWithdraw) error {
 		return ErrInvalidWithdrawalAmount()
 	}
 
+	err := d.unbond(candidate, amount)
+	if err != nil {
+		return err
+	}
+
+	// transfer coins back to account once they unbonded
+	return queueUnbonding(d.store, d.params, d.sender, candidate.OwnerAddress, amount, d.height)
+}
+
+// unbond takes amount off the stake of the sender in candidate
+func (d deliver) unbond(candidate *Candidate, amount *big.Int) error {
 	delegation := GetDelegation(d.sender, candidate.PubKey)
 
 	// candidates can't withdraw the reserved reservation fund
@@ -576,9 +726,56 @@ func (d deliver) withdraw(tx TxWithdraw) error {
 
 	delegateHistory := &DelegateHistory{0, d.sender, candidate.PubKey, amount, "withdraw", now}
 	saveDelegateHistory(delegateHistory)
+	return nil
+}
 
-	// transfer coins back to account
-	return commons.Transfer(d.params.StakeAccount, d.sender, amount)
+func (d deliver) redelegate(tx TxRedelegate) error {
+	checker := check{
+		store:    d.store,
+		sender:   d.sender,
+		params:   d.params,
+		ethereum: d.ethereum,
+		height:   d.height,
+	}
+	if err := checker.redelegate(tx); err != nil {
+		return err
+	}
+
+	// the coins stay with the stake account
+	amount := utils.ParseInt(tx.Amount)
+	err := d.unbond(GetCandidateByAddress(tx.FromValidator), amount)
+	if err != nil {
+		return err
+	}
+	d.bond(GetCandidateByAddress(tx.ToValidator), amount)
+
+	recordRedelegation(d.store, d.params, d.sender, tx.FromValidator, tx.ToValidator, amount, d.height)
+	return nil
+}
+
+func (d deliver) unjail(tx TxUnjail) error {
+	checker := check{
+		store:  d.store,
+		sender: d.sender,
+		params: d.params,
+		height: d.height,
+	}
+	if err := checker.unjail(tx); err != nil {
+		return err
+	}
+
+	// missed blocks count afresh
+	downtime := loadDowntime(d.store, d.sender)
+	downtime.Jailed = false
+	downtime.WindowStart = d.height
+	downtime.MissedBlocks = 0
+	saveDowntime(d.store, d.sender, downtime)
+
+	candidate := GetCandidateByAddress(d.sender)
+	candidate.Active = "Y"
+	candidate.UpdatedAt = utils.Now()
+	updateCandidate(candidate)
+	return nil
 }
 
 func checkBalance(ethereum *eth.Ethereum, addr common.Address, amount *big.Int) error {
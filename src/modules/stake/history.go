@@ -0,0 +1,114 @@
+package stake
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	abci "github.com/tendermint/abci/types"
+)
+
+// ValidatorPower is a member of the tendermint validator set.
+type ValidatorPower struct {
+	PubKey hexutil.Bytes `json:"pub_key"` // go-wire encoded, as tendermint has it
+	Power  int64         `json:"power"`
+}
+
+// ValidatorSet is the tendermint validator set the updates of the block at
+// Height left, sorted by power.
+type ValidatorSet struct {
+	Height     int64            `json:"height"`
+	Validators []ValidatorPower `json:"validators"`
+}
+
+var _ sort.Interface = byPower{}
+
+type byPower []ValidatorPower
+
+func (vs byPower) Len() int      { return len(vs) }
+func (vs byPower) Swap(i, j int) { vs[i], vs[j] = vs[j], vs[i] }
+func (vs byPower) Less(i, j int) bool {
+	if vs[i].Power != vs[j].Power {
+		return vs[i].Power > vs[j].Power
+	}
+	return bytes.Compare(vs[i].PubKey, vs[j].PubKey) < 0
+}
+
+// ValidatorSetAt returns the validator set as of the block at height, false
+// if there was none yet. The sets are kept in the stake db, out of the app
+// state.
+func ValidatorSetAt(height int64) (set ValidatorSet, ok bool) {
+	db := getDb()
+	defer db.Close()
+	stmt, err := db.Prepare("select height, validators from validator_sets where height <= ? order by height desc limit 1")
+	if err != nil {
+		panic(err)
+	}
+	defer stmt.Close()
+
+	var validators string
+	err = stmt.QueryRow(height).Scan(&set.Height, &validators)
+	switch {
+	case err == sql.ErrNoRows:
+		return set, false
+	case err != nil:
+		panic(err)
+	}
+	if err := json.Unmarshal([]byte(validators), &set.Validators); err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return set, true
+}
+
+// SaveValidatorChanges applies the validator updates of the block at height
+// to the last validator set, and keeps the result for ValidatorSetAt. A
+// block run again replaces the set it saved.
+func SaveValidatorChanges(height int64, changes []*abci.Validator) {
+	if len(changes) == 0 {
+		return
+	}
+
+	last, _ := ValidatorSetAt(height)
+	powers := make(map[string]int64, len(last.Validators))
+	for _, v := range last.Validators {
+		powers[string(v.PubKey)] = v.Power
+	}
+	for _, c := range changes {
+		if c.Power == 0 {
+			delete(powers, string(c.PubKey))
+		} else {
+			powers[string(c.PubKey)] = c.Power
+		}
+	}
+
+	set := ValidatorSet{Height: height, Validators: make([]ValidatorPower, 0, len(powers))}
+	for pk, power := range powers {
+		set.Validators = append(set.Validators, ValidatorPower{PubKey: []byte(pk), Power: power})
+	}
+	sort.Sort(byPower(set.Validators))
+	validators, err := json.Marshal(set.Validators)
+	if err != nil {
+		panic(err)
+	}
+
+	db := getDb()
+	defer db.Close()
+	tx, err := db.Begin()
+	if err != nil {
+		panic(err)
+	}
+	defer tx.Commit()
+
+	stmt, err := tx.Prepare("insert or replace into validator_sets(height, validators) values(?, ?)")
+	if err != nil {
+		panic(err)
+	}
+	defer stmt.Close()
+
+	_, err = stmt.Exec(height, string(validators))
+	if err != nil {
+		panic(err)
+	}
+}
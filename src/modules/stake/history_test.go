@@ -0,0 +1,50 @@
+package stake
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	abci "github.com/tendermint/abci/types"
+)
+
+func TestValidatorSetAt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "stake")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	defer UseDatabase(path.Join(dir, "stake.db"))()
+	db := getDb()
+	_, err = db.Exec("create table validator_sets(height integer not null primary key, validators text not null)")
+	db.Close()
+	require.Nil(t, err)
+	a, b := []byte{1, 0xa}, []byte{1, 0xb}
+
+	_, ok := ValidatorSetAt(0)
+	assert.False(t, ok)
+
+	SaveValidatorChanges(0, []*abci.Validator{{PubKey: a, Power: 10}})
+	SaveValidatorChanges(5, []*abci.Validator{{PubKey: b, Power: 20}})
+	SaveValidatorChanges(9, []*abci.Validator{{PubKey: a, Power: 0}})
+
+	set, ok := ValidatorSetAt(4)
+	require.True(t, ok)
+	assert.Equal(t, int64(0), set.Height)
+	assert.Len(t, set.Validators, 1)
+
+	set, _ = ValidatorSetAt(8)
+	assert.Equal(t, int64(5), set.Height)
+	require.Len(t, set.Validators, 2)
+	assert.Equal(t, int64(20), set.Validators[0].Power)
+
+	set, _ = ValidatorSetAt(100)
+	assert.Equal(t, []ValidatorPower{{PubKey: b, Power: 20}}, set.Validators)
+
+	// a block run again replaces its set
+	SaveValidatorChanges(9, []*abci.Validator{{PubKey: a, Power: 0}})
+	set, _ = ValidatorSetAt(9)
+	assert.Equal(t, []ValidatorPower{{PubKey: b, Power: 20}}, set.Validators)
+}
@@ -0,0 +1,146 @@
+package stake
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// PerformanceUpgrade is the upgrade from which on the blocks of the
+// validators are counted, see Performance. The counters are in the app
+// state, so the chain switches at the height it is scheduled at.
+const PerformanceUpgrade = "validator-performance"
+
+func init() {
+	// nothing to migrate, the counters start from the upgrade on
+	upgrade.RegisterHandler(PerformanceUpgrade, func(state.SimpleDB) error { return nil })
+}
+
+// nolint
+var (
+	PerformancePrefix = []byte{0x28} // block production counters of the validators, by owner address
+)
+
+// Performance counts the blocks a validator signed, missed and proposed
+// since it first validated, for validator rankings. The counters only grow,
+// rates over a span of blocks are the differences of two reads.
+type Performance struct {
+	Since    int64 `json:"since"` // first block counted
+	Signed   int64 `json:"signed"`
+	Missed   int64 `json:"missed"`
+	Proposed int64 `json:"proposed"`
+
+	// of the blocks it proposed
+	ProposedGasUsed  int64 `json:"proposed_gas_used"`
+	ProposedGasLimit int64 `json:"proposed_gas_limit"`
+}
+
+// ProposalRate is the share of the blocks it was a validator of that it
+// proposed.
+func (p Performance) ProposalRate() float64 {
+	if p.Signed+p.Missed == 0 {
+		return 0
+	}
+	return float64(p.Proposed) / float64(p.Signed+p.Missed)
+}
+
+// MissRate is the share of the blocks it was a validator of that it didn't
+// sign.
+func (p Performance) MissRate() float64 {
+	if p.Signed+p.Missed == 0 {
+		return 0
+	}
+	return float64(p.Missed) / float64(p.Signed+p.Missed)
+}
+
+// Fullness is the gas used of the blocks it proposed over their gas limit.
+func (p Performance) Fullness() float64 {
+	if p.ProposedGasLimit == 0 {
+		return 0
+	}
+	return float64(p.ProposedGasUsed) / float64(p.ProposedGasLimit)
+}
+
+// PerformanceReport is the performance of a validator with its rates.
+type PerformanceReport struct {
+	Validator common.Address `json:"validator"`
+	Performance
+	ProposalRate float64 `json:"proposal_rate"`
+	MissRate     float64 `json:"miss_rate"`
+	Fullness     float64 `json:"fullness"`
+}
+
+// NewPerformanceReport reports perf of the validator owned by owner.
+func NewPerformanceReport(owner common.Address, perf Performance) PerformanceReport {
+	return PerformanceReport{
+		Validator:    owner,
+		Performance:  perf,
+		ProposalRate: perf.ProposalRate(),
+		MissRate:     perf.MissRate(),
+		Fullness:     perf.Fullness(),
+	}
+}
+
+// PerformanceKey is the store key of the performance of the validator owned
+// by owner.
+func PerformanceKey(owner common.Address) []byte {
+	return append(PerformancePrefix, owner.Bytes()...)
+}
+
+// ParsePerformance decodes a performance stored under PerformanceKey.
+func ParsePerformance(value []byte) (perf Performance, err error) {
+	if len(value) == 0 {
+		return
+	}
+	err = wire.ReadBinaryBytes(value, &perf)
+	return
+}
+
+// LoadPerformance returns the performance of the validator owned by owner.
+func LoadPerformance(store state.SimpleDB, owner common.Address) Performance {
+	perf, err := ParsePerformance(store.Get(PerformanceKey(owner)))
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return perf
+}
+
+// updatePerformance applies update to the performance of owner, once
+// PerformanceUpgrade is done.
+func updatePerformance(store state.SimpleDB, owner common.Address, height int64, update func(*Performance)) {
+	if !upgrade.Done(store, PerformanceUpgrade) {
+		return
+	}
+	perf := LoadPerformance(store, owner)
+	if perf.Since == 0 {
+		perf.Since = height
+	}
+	update(&perf)
+	store.Set(PerformanceKey(owner), wire.BinaryBytes(perf))
+}
+
+// TrackSignature counts whether the validator owned by owner signed the
+// block before height.
+func TrackSignature(store state.SimpleDB, owner common.Address, height int64, signed bool) {
+	updatePerformance(store, owner, height, func(perf *Performance) {
+		if signed {
+			perf.Signed++
+		} else {
+			perf.Missed++
+		}
+	})
+}
+
+// TrackProposal counts the block at height proposed by the validator owned
+// by owner, with the gas it used out of its limit.
+func TrackProposal(store state.SimpleDB, owner common.Address, height int64, gasUsed, gasLimit *big.Int) {
+	updatePerformance(store, owner, height, func(perf *Performance) {
+		perf.Proposed++
+		perf.ProposedGasUsed += gasUsed.Int64()
+		perf.ProposedGasLimit += gasLimit.Int64()
+	})
+}
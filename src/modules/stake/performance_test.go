@@ -0,0 +1,36 @@
+package stake
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+func TestPerformance(t *testing.T) {
+	store := state.NewMemKVStore()
+	owner := common.HexToAddress("0x1")
+	TrackSignature(store, owner, 4, true)
+	assert.Nil(t, store.Get(PerformanceKey(owner)), "counted before the upgrade")
+	store.Set(upgrade.DoneKey(PerformanceUpgrade), []byte{1})
+
+	for h := int64(5); h < 9; h++ {
+		TrackSignature(store, owner, h, h != 6)
+	}
+	TrackProposal(store, owner, 7, big.NewInt(300), big.NewInt(1000))
+	TrackProposal(store, owner, 8, big.NewInt(500), big.NewInt(1000))
+
+	perf := LoadPerformance(store, owner)
+	assert.Equal(t, Performance{Since: 5, Signed: 3, Missed: 1, Proposed: 2,
+		ProposedGasUsed: 800, ProposedGasLimit: 2000}, perf)
+	assert.Equal(t, 0.5, perf.ProposalRate())
+	assert.Equal(t, 0.25, perf.MissRate())
+	assert.Equal(t, 0.4, perf.Fullness())
+
+	// nothing counted yet
+	assert.Equal(t, 0.0, LoadPerformance(store, common.HexToAddress("0x2")).ProposalRate())
+}
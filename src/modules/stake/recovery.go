@@ -0,0 +1,53 @@
+package stake
+
+import (
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/dora/ultron/const"
+	"github.com/dora/ultron/modules/recovery"
+	"github.com/dora/ultron/utils"
+)
+
+func init() {
+	recovery.RegisterMigration(constant.ModuleNameStake, migrate)
+}
+
+// migrate moves the delegations of the recovered account from to its new key
+// to, with the stake it has unbonding and redelegated. A candidacy stays
+// with the owner address it was declared by.
+func migrate(store state.SimpleDB, from, to common.Address) {
+	now := utils.Now()
+	for _, d := range GetDelegationsByDelegator(from) {
+		existing := GetDelegation(to, d.PubKey)
+		if existing == nil {
+			d.DelegatorAddress = to
+			d.UpdatedAt = now
+			UpdateDelegatorAddress(d, from)
+			continue
+		}
+		existing.AddDelegateAmount(d.ParseDelegateAmount())
+		existing.AddAwardAmount(d.ParseAwardAmount())
+		existing.AddWithdrawAmount(d.ParseWithdrawAmount())
+		existing.AddSlashAmount(d.ParseSlashAmount())
+		existing.UpdatedAt = now
+		UpdateDelegation(existing)
+		RemoveDelegation(d)
+	}
+
+	// the entries mature for to at the same heights
+	if unbondings := loadUnbondings(store, from); len(unbondings) > 0 {
+		for _, u := range unbondings {
+			scheduleMaturity(store, u.CompletionHeight, to)
+		}
+		saveUnbondings(store, to, append(loadUnbondings(store, to), unbondings...))
+		saveUnbondings(store, from, nil)
+	}
+	if redelegations := loadRedelegations(store, from); len(redelegations) > 0 {
+		for _, r := range redelegations {
+			scheduleMaturity(store, r.CompletionHeight, to)
+		}
+		saveRedelegations(store, to, append(loadRedelegations(store, to), redelegations...))
+		saveRedelegations(store, from, nil)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"github.com/tendermint/go-wire"
 
 	"github.com/cosmos/cosmos-sdk/state"
+
+	"github.com/dora/ultron/substore"
 )
 
 // nolint
@@ -12,6 +14,11 @@ var (
 	ParamKey = []byte{0x01} // key for global parameters relating to staking
 )
 
+func init() {
+	substore.Register("stake", ParamKey, CommissionPrefix, UnbondingPrefix, RedelegationPrefix, maturityPrefix,
+		DowntimePrefix, PerformancePrefix, UnbondingBlocksKey, EpochBlocksKey)
+}
+
 //---------------------------------------------------------------------
 
 // load/save the global staking params
@@ -21,6 +21,8 @@ const (
 	ByteTxActivateCandidacy = 0x59
 	ByteTxDelegate          = 0x60
 	ByteTxWithdraw          = 0x61
+	ByteTxRedelegate        = 0x62
+	ByteTxUnjail            = 0x63
 	TypeTxDeclareCandidacy  = stakingModuleName + "/declareCandidacy"
 	TypeTxUpdateCandidacy   = stakingModuleName + "/updateCandidacy"
 	TypeTxVerifyCandidacy   = stakingModuleName + "/verifyCandidacy"
@@ -28,6 +30,8 @@ const (
 	TypeTxActivateCandidacy = stakingModuleName + "/activateCandidacy"
 	TypeTxDelegate          = stakingModuleName + "/delegate"
 	TypeTxWithdraw          = stakingModuleName + "/withdraw"
+	TypeTxRedelegate        = stakingModuleName + "/redelegate"
+	TypeTxUnjail            = stakingModuleName + "/unjail"
 )
 
 func init() {
@@ -38,15 +42,20 @@ func init() {
 	sdk.TxMapper.RegisterImplementation(TxActivateCandidacy{}, TypeTxActivateCandidacy, ByteTxActivateCandidacy)
 	sdk.TxMapper.RegisterImplementation(TxDelegate{}, TypeTxDelegate, ByteTxDelegate)
 	sdk.TxMapper.RegisterImplementation(TxWithdraw{}, TypeTxWithdraw, ByteTxWithdraw)
+	sdk.TxMapper.RegisterImplementation(TxRedelegate{}, TypeTxRedelegate, ByteTxRedelegate)
+	sdk.TxMapper.RegisterImplementation(TxUnjail{}, TypeTxUnjail, ByteTxUnjail)
 }
 
 //Verify interface at compile time
-var _, _, _, _, _, _ sdk.TxInner = &TxDeclareCandidacy{}, &TxUpdateCandidacy{}, &TxWithdrawCandidacy{}, TxVerifyCandidacy{}, &TxDelegate{}, &TxWithdraw{}
+var _, _, _, _, _, _, _, _ sdk.TxInner = &TxDeclareCandidacy{}, &TxUpdateCandidacy{}, &TxWithdrawCandidacy{}, TxVerifyCandidacy{}, &TxDelegate{}, &TxWithdraw{}, &TxRedelegate{}, &TxUnjail{}
 
 type TxDeclareCandidacy struct {
 	PubKey    crypto.PubKey `json:"pub_key"`
 	MaxAmount string        `json:"max_amount"`
 	CompRate  string        `json:"comp_rate"`
+	// MaxCompRateChange bounds how much CompRate may change a day,
+	// 0.01 if empty
+	MaxCompRateChange string `json:"max_comp_rate_change,omitempty"`
 	Description
 }
 
@@ -55,6 +64,15 @@ func (tx TxDeclareCandidacy) ValidateBasic() error {
 		return errCandidateEmpty
 	}
 
+	if _, err := parseRate(tx.CompRate); err != nil {
+		return err
+	}
+	if tx.MaxCompRateChange != "" {
+		if _, err := parseRate(tx.MaxCompRateChange); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -68,12 +86,13 @@ func (tx TxDeclareCandidacy) ReserveRequirement(ratio string) (result *big.Int)
 	return
 }
 
-func NewTxDeclareCandidacy(pubKey crypto.PubKey, maxAmount, compRate string, descrpition Description) sdk.Tx {
+func NewTxDeclareCandidacy(pubKey crypto.PubKey, maxAmount, compRate, maxCompRateChange string, descrpition Description) sdk.Tx {
 	return TxDeclareCandidacy{
-		PubKey:      pubKey,
-		MaxAmount:   maxAmount,
-		CompRate:    compRate,
-		Description: descrpition,
+		PubKey:            pubKey,
+		MaxAmount:         maxAmount,
+		CompRate:          compRate,
+		MaxCompRateChange: maxCompRateChange,
+		Description:       descrpition,
 	}.Wrap()
 }
 
@@ -81,16 +100,23 @@ func (tx TxDeclareCandidacy) Wrap() sdk.Tx { return sdk.Tx{tx} }
 
 type TxUpdateCandidacy struct {
 	MaxAmount string `json:"max_amount"`
+	CompRate  string `json:"comp_rate,omitempty"` // unchanged if empty
 	Description
 }
 
 func (tx TxUpdateCandidacy) ValidateBasic() error {
+	if tx.CompRate != "" {
+		if _, err := parseRate(tx.CompRate); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func NewTxUpdateCandidacy(maxAmount string, description Description) sdk.Tx {
+func NewTxUpdateCandidacy(maxAmount, compRate string, description Description) sdk.Tx {
 	return TxUpdateCandidacy{
 		MaxAmount:   maxAmount,
+		CompRate:    compRate,
 		Description: description,
 	}.Wrap()
 }
@@ -181,3 +207,41 @@ func NewTxWithdraw(validatorAddress common.Address, amount string) sdk.Tx {
 }
 
 func (tx TxWithdraw) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxRedelegate moves stake from one validator to another without waiting
+// for it to unbond
+type TxRedelegate struct {
+	FromValidator common.Address `json:"from_validator"`
+	ToValidator   common.Address `json:"to_validator"`
+	Amount        string         `json:"amount"`
+}
+
+func (tx TxRedelegate) ValidateBasic() error {
+	if tx.FromValidator == tx.ToValidator {
+		return ErrRedelegateToSelf()
+	}
+	return nil
+}
+
+func NewTxRedelegate(fromValidator, toValidator common.Address, amount string) sdk.Tx {
+	return TxRedelegate{
+		FromValidator: fromValidator,
+		ToValidator:   toValidator,
+		Amount:        amount,
+	}.Wrap()
+}
+
+func (tx TxRedelegate) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxUnjail lets a validator jailed for downtime back into the election
+type TxUnjail struct{}
+
+func (tx TxUnjail) ValidateBasic() error {
+	return nil
+}
+
+func NewTxUnjail() sdk.Tx {
+	return TxUnjail{}.Wrap()
+}
+
+func (tx TxUnjail) Wrap() sdk.Tx { return sdk.Tx{tx} }
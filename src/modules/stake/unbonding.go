@@ -0,0 +1,265 @@
+package stake
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/commons"
+	"github.com/dora/ultron/modules/upgrade"
+	"github.com/dora/ultron/utils"
+)
+
+// UnbondingUpgrade is the upgrade from which on withdrawn stake is queued
+// until it unbonds, and stake can be redelegated. Before it, withdrawals are
+// paid out right away, as the binaries without the queue do.
+const UnbondingUpgrade = "unbonding-queue"
+
+func init() {
+	// nothing to migrate, only the withdrawals after it are queued
+	upgrade.RegisterHandler(UnbondingUpgrade, func(state.SimpleDB) error { return nil })
+}
+
+// nolint
+var (
+	UnbondingPrefix    = []byte{0x16} // unbondings of a delegator
+	RedelegationPrefix = []byte{0x17} // redelegations of a delegator
+	maturityPrefix     = []byte{0x18} // delegators with entries maturing at a height
+	UnbondingBlocksKey = []byte{0x29} // blocks withdrawn stake stays locked, kept apart from the Params
+)
+
+const defaultUnbondingBlocks = 21 * blocksPerDay
+
+// Unbonding is stake withdrawn from a validator, paid back to the delegator
+// once it matures.
+type Unbonding struct {
+	Validator        common.Address `json:"validator"`
+	Amount           string         `json:"amount"`
+	CreationHeight   int64          `json:"creation_height"`
+	CompletionHeight int64          `json:"completion_height"`
+}
+
+// Redelegation is stake moved from one validator to another without
+// unbonding. It can't move on from the new validator until it matures.
+type Redelegation struct {
+	From             common.Address `json:"from"`
+	To               common.Address `json:"to"`
+	Amount           string         `json:"amount"`
+	CreationHeight   int64          `json:"creation_height"`
+	CompletionHeight int64          `json:"completion_height"`
+}
+
+// UnbondingKey is the store key of the unbondings of delegator.
+func UnbondingKey(delegator common.Address) []byte {
+	return append(UnbondingPrefix, delegator.Bytes()...)
+}
+
+// RedelegationKey is the store key of the redelegations of delegator.
+func RedelegationKey(delegator common.Address) []byte {
+	return append(RedelegationPrefix, delegator.Bytes()...)
+}
+
+// loadUnbondingBlocks returns the unbonding period. It isn't a field of the
+// Params, which would break the decoding of the Params stored before.
+func loadUnbondingBlocks(store state.SimpleDB) int64 {
+	b := store.Get(UnbondingBlocksKey)
+	if b == nil {
+		return defaultUnbondingBlocks
+	}
+	return int64(binary.BigEndian.Uint64(b))
+}
+
+// unbondingQueued tells if withdrawals are queued, see UnbondingUpgrade.
+func unbondingQueued(store state.SimpleDB) bool {
+	return upgrade.Done(store, UnbondingUpgrade)
+}
+
+func saveUnbondingBlocks(store state.SimpleDB, blocks int64) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(blocks))
+	store.Set(UnbondingBlocksKey, b)
+}
+
+func maturityKey(height int64) []byte {
+	key := make([]byte, len(maturityPrefix)+8)
+	copy(key, maturityPrefix)
+	binary.BigEndian.PutUint64(key[len(maturityPrefix):], uint64(height))
+	return key
+}
+
+// ParseUnbondings decodes the unbondings stored under UnbondingKey.
+func ParseUnbondings(value []byte) (unbondings []Unbonding, err error) {
+	if len(value) == 0 {
+		return []Unbonding{}, nil
+	}
+	err = wire.ReadBinaryBytes(value, &unbondings)
+	return
+}
+
+// ParseRedelegations decodes the redelegations stored under RedelegationKey.
+func ParseRedelegations(value []byte) (redelegations []Redelegation, err error) {
+	if len(value) == 0 {
+		return []Redelegation{}, nil
+	}
+	err = wire.ReadBinaryBytes(value, &redelegations)
+	return
+}
+
+func loadUnbondings(store state.SimpleDB, delegator common.Address) []Unbonding {
+	unbondings, err := ParseUnbondings(store.Get(UnbondingKey(delegator)))
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return unbondings
+}
+
+func saveUnbondings(store state.SimpleDB, delegator common.Address, unbondings []Unbonding) {
+	if len(unbondings) == 0 {
+		store.Remove(UnbondingKey(delegator))
+		return
+	}
+	store.Set(UnbondingKey(delegator), wire.BinaryBytes(unbondings))
+}
+
+func loadRedelegations(store state.SimpleDB, delegator common.Address) []Redelegation {
+	redelegations, err := ParseRedelegations(store.Get(RedelegationKey(delegator)))
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return redelegations
+}
+
+func saveRedelegations(store state.SimpleDB, delegator common.Address, redelegations []Redelegation) {
+	if len(redelegations) == 0 {
+		store.Remove(RedelegationKey(delegator))
+		return
+	}
+	store.Set(RedelegationKey(delegator), wire.BinaryBytes(redelegations))
+}
+
+// scheduleMaturity makes EndBlock look at the entries of delegator at height.
+func scheduleMaturity(store state.SimpleDB, height int64, delegator common.Address) {
+	var delegators []common.Address
+	key := maturityKey(height)
+	if b := store.Get(key); b != nil {
+		if err := wire.ReadBinaryBytes(b, &delegators); err != nil {
+			panic(err)
+		}
+	}
+	for _, d := range delegators {
+		if d == delegator {
+			return
+		}
+	}
+	store.Set(key, wire.BinaryBytes(append(delegators, delegator)))
+}
+
+// queueUnbonding pays amount bonded to validator back to delegator once the
+// unbonding period is over, right away if there is none or UnbondingUpgrade
+// isn't done yet.
+func queueUnbonding(store state.SimpleDB, params Params, delegator, validator common.Address, amount *big.Int, height int64) error {
+	if !unbondingQueued(store) {
+		return commons.Transfer(params.StakeAccount, delegator, amount)
+	}
+	blocks := loadUnbondingBlocks(store)
+	if blocks <= 0 {
+		return commons.Transfer(params.StakeAccount, delegator, amount)
+	}
+
+	completion := height + blocks
+	unbondings := append(loadUnbondings(store, delegator), Unbonding{
+		Validator:        validator,
+		Amount:           amount.String(),
+		CreationHeight:   height,
+		CompletionHeight: completion,
+	})
+	saveUnbondings(store, delegator, unbondings)
+	scheduleMaturity(store, completion, delegator)
+	return nil
+}
+
+// recordRedelegation keeps the redelegation of amount from one validator to
+// another until the unbonding period is over.
+func recordRedelegation(store state.SimpleDB, params Params, delegator, from, to common.Address, amount *big.Int, height int64) {
+	blocks := loadUnbondingBlocks(store)
+	if blocks <= 0 || !unbondingQueued(store) {
+		return
+	}
+
+	completion := height + blocks
+	redelegations := append(loadRedelegations(store, delegator), Redelegation{
+		From:             from,
+		To:               to,
+		Amount:           amount.String(),
+		CreationHeight:   height,
+		CompletionHeight: completion,
+	})
+	saveRedelegations(store, delegator, redelegations)
+	scheduleMaturity(store, completion, delegator)
+}
+
+// redelegatedTo tells if delegator has stake redelegated to validator that
+// hasn't matured yet.
+func redelegatedTo(store state.SimpleDB, delegator, validator common.Address) bool {
+	for _, r := range loadRedelegations(store, delegator) {
+		if r.To == validator {
+			return true
+		}
+	}
+	return false
+}
+
+// Ledger moves ether in the state of the block being run, see
+// backend.Backend.
+type Ledger interface {
+	Transfer(from, to common.Address, amount *big.Int) error
+}
+
+// EndBlock pays out the unbondings maturing at height on ledger, and drops
+// the redelegations that do. The payouts are made right away rather than
+// queued as remittances, so one the stake account can't cover fails here:
+// the unbonding is kept and retried with the next block, its error
+// returned for the app to log. The block goes on.
+func EndBlock(store state.SimpleDB, height int64, ledger Ledger) (failed []error) {
+	key := maturityKey(height)
+	b := store.Get(key)
+	if b == nil {
+		return nil
+	}
+	var delegators []common.Address
+	if err := wire.ReadBinaryBytes(b, &delegators); err != nil {
+		panic(err)
+	}
+
+	params := loadParams(store)
+	for _, delegator := range delegators {
+		var unbondings []Unbonding
+		for _, u := range loadUnbondings(store, delegator) {
+			if u.CompletionHeight > height {
+				unbondings = append(unbondings, u)
+				continue
+			}
+			if err := ledger.Transfer(params.StakeAccount, delegator, utils.ParseInt(u.Amount)); err != nil {
+				failed = append(failed, fmt.Errorf("unbonding of %s to %s: %v", u.Amount, delegator.Hex(), err))
+				unbondings = append(unbondings, u)
+				// pay it out with the next block
+				scheduleMaturity(store, height+1, delegator)
+			}
+		}
+		saveUnbondings(store, delegator, unbondings)
+
+		var redelegations []Redelegation
+		for _, r := range loadRedelegations(store, delegator) {
+			if r.CompletionHeight > height {
+				redelegations = append(redelegations, r)
+			}
+		}
+		saveRedelegations(store, delegator, redelegations)
+	}
+	store.Remove(key)
+	return failed
+}
@@ -0,0 +1,110 @@
+package stake
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// ledger holds balance in the stake account, transfers beyond it fail
+type ledger struct {
+	balance *big.Int
+	paid    map[common.Address]*big.Int
+}
+
+func newLedger(balance int64) *ledger {
+	return &ledger{balance: big.NewInt(balance), paid: make(map[common.Address]*big.Int)}
+}
+
+func (l *ledger) Transfer(from, to common.Address, amount *big.Int) error {
+	if l.balance.Cmp(amount) < 0 {
+		return fmt.Errorf("insufficient balance")
+	}
+	l.balance.Sub(l.balance, amount)
+	if l.paid[to] == nil {
+		l.paid[to] = new(big.Int)
+	}
+	l.paid[to].Add(l.paid[to], amount)
+	return nil
+}
+
+func TestUnbondingMatures(t *testing.T) {
+	store := state.NewMemKVStore()
+	params := defaultParams()
+	saveParams(store, params)
+	saveUnbondingBlocks(store, 10)
+	store.Set(upgrade.DoneKey(UnbondingUpgrade), []byte{1})
+
+	delegator := common.HexToAddress("0x1")
+	validator := common.HexToAddress("0x2")
+	require.NoError(t, queueUnbonding(store, params, delegator, validator, big.NewInt(100), 5))
+	require.NoError(t, queueUnbonding(store, params, delegator, validator, big.NewInt(50), 7))
+	recordRedelegation(store, params, delegator, validator, common.HexToAddress("0x3"), big.NewInt(20), 5)
+	assert.True(t, redelegatedTo(store, delegator, common.HexToAddress("0x3")))
+
+	l := newLedger(1000)
+	assert.Empty(t, EndBlock(store, 14, l))
+	assert.Len(t, loadUnbondings(store, delegator), 2)
+
+	assert.Empty(t, EndBlock(store, 15, l))
+	assert.Equal(t, big.NewInt(100), l.paid[delegator])
+	unbondings := loadUnbondings(store, delegator)
+	require.Len(t, unbondings, 1)
+	assert.Equal(t, "50", unbondings[0].Amount)
+	assert.Equal(t, int64(17), unbondings[0].CompletionHeight)
+	assert.False(t, redelegatedTo(store, delegator, common.HexToAddress("0x3")))
+
+	assert.Empty(t, EndBlock(store, 17, l))
+	assert.Nil(t, store.Get(UnbondingKey(delegator)))
+	assert.Equal(t, big.NewInt(150), l.paid[delegator])
+}
+
+func TestUnbondingKeptUntilPaid(t *testing.T) {
+	store := state.NewMemKVStore()
+	params := defaultParams()
+	saveParams(store, params)
+	saveUnbondingBlocks(store, 10)
+	store.Set(upgrade.DoneKey(UnbondingUpgrade), []byte{1})
+
+	delegator := common.HexToAddress("0x1")
+	require.NoError(t, queueUnbonding(store, params, delegator, common.HexToAddress("0x2"), big.NewInt(100), 5))
+
+	// the stake account is short of it, it is retried with the next block
+	l := newLedger(50)
+	assert.Len(t, EndBlock(store, 15, l), 1)
+	assert.Len(t, loadUnbondings(store, delegator), 1)
+	assert.Nil(t, l.paid[delegator])
+
+	l.balance.SetInt64(100)
+	assert.Empty(t, EndBlock(store, 16, l))
+	assert.Nil(t, store.Get(UnbondingKey(delegator)))
+	assert.Equal(t, big.NewInt(100), l.paid[delegator])
+}
+
+func TestUnbondingBeforeUpgrade(t *testing.T) {
+	store := state.NewMemKVStore()
+	params := defaultParams()
+	saveParams(store, params)
+	saveUnbondingBlocks(store, 10)
+
+	delegator := common.HexToAddress("0x1")
+	validator := common.HexToAddress("0x2")
+	require.NoError(t, queueUnbonding(store, params, delegator, validator, big.NewInt(100), 5))
+	recordRedelegation(store, params, delegator, validator, common.HexToAddress("0x3"), big.NewInt(20), 5)
+	assert.Nil(t, store.Get(UnbondingKey(delegator)))
+	assert.Nil(t, store.Get(RedelegationKey(delegator)))
+}
+
+func TestUnbondingBlocksDefault(t *testing.T) {
+	store := state.NewMemKVStore()
+	assert.Equal(t, int64(defaultUnbondingBlocks), loadUnbondingBlocks(store))
+	saveUnbondingBlocks(store, 0)
+	assert.Equal(t, int64(0), loadUnbondingBlocks(store))
+}
@@ -0,0 +1,68 @@
+// Package supply keeps the totals of the ether minted and burnt since
+// TotalsUpgrade in the app store, from which the total supply is the
+// genesis allocation plus the minted minus the burnt ether. Chains started
+// with the upgrade done count from genesis.
+package supply
+
+import (
+	"math/big"
+
+	"github.com/cosmos/cosmos-sdk/state"
+
+	"github.com/dora/ultron/modules/upgrade"
+	"github.com/dora/ultron/substore"
+)
+
+// TotalsUpgrade is the upgrade from which on the minted and burnt ether is
+// counted. The totals start at zero, the ether minted and burnt before it
+// is left out, as the binaries without them keep no totals.
+const TotalsUpgrade = "supply-totals"
+
+// nolint
+var (
+	// Keys of the totals, for the "/key" query. The values are big endian
+	// wei.
+	MintedKey = []byte{0x23, 'm'} // block rewards and awards
+	BurnedKey = []byte{0x23, 'b'} // sent to the burn account, base fees, rent and slashes
+)
+
+func init() {
+	substore.Register("supply", MintedKey, BurnedKey)
+	// nothing to seed, see TotalsUpgrade
+	upgrade.RegisterHandler(TotalsUpgrade, func(state.SimpleDB) error { return nil })
+}
+
+// Add adds the ether a block minted and burnt to the totals, once
+// TotalsUpgrade is done.
+func Add(store state.SimpleDB, minted, burned *big.Int) {
+	if !upgrade.Done(store, TotalsUpgrade) {
+		return
+	}
+	add(store, MintedKey, minted)
+	add(store, BurnedKey, burned)
+}
+
+// Total returns the ether supply, genesis being the ether the genesis
+// allocated.
+func Total(store state.SimpleDB, genesis *big.Int) *big.Int {
+	total := new(big.Int).Add(genesis, Minted(store))
+	return total.Sub(total, Burned(store))
+}
+
+// Minted returns the ether minted since TotalsUpgrade.
+func Minted(store state.SimpleDB) *big.Int {
+	return new(big.Int).SetBytes(store.Get(MintedKey))
+}
+
+// Burned returns the ether burnt since TotalsUpgrade.
+func Burned(store state.SimpleDB) *big.Int {
+	return new(big.Int).SetBytes(store.Get(BurnedKey))
+}
+
+func add(store state.SimpleDB, key []byte, amount *big.Int) {
+	if amount == nil || amount.Sign() == 0 {
+		return
+	}
+	total := new(big.Int).SetBytes(store.Get(key))
+	store.Set(key, total.Add(total, amount).Bytes())
+}
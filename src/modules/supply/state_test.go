@@ -0,0 +1,29 @@
+package supply
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+func TestAdd(t *testing.T) {
+	store := state.NewMemKVStore()
+	genesis := big.NewInt(1000)
+
+	// nothing is counted before the upgrade
+	Add(store, big.NewInt(10), big.NewInt(3))
+	assert.Nil(t, store.Get(MintedKey))
+	assert.Nil(t, store.Get(BurnedKey))
+	assert.Equal(t, genesis, Total(store, genesis))
+
+	store.Set(upgrade.DoneKey(TotalsUpgrade), []byte{1})
+	Add(store, big.NewInt(10), big.NewInt(3))
+	Add(store, big.NewInt(5), nil)
+	assert.Equal(t, big.NewInt(15), Minted(store))
+	assert.Equal(t, big.NewInt(3), Burned(store))
+	assert.Equal(t, big.NewInt(1012), Total(store, genesis))
+}
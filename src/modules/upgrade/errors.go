@@ -0,0 +1,36 @@
+// nolint
+package upgrade
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/errors"
+)
+
+var (
+	errNoName           = fmt.Errorf("Upgrade needs a name")
+	errBadHeight        = fmt.Errorf("Upgrade height must be after the current block")
+	errDone             = fmt.Errorf("Upgrade was already done")
+	errNoPlan           = fmt.Errorf("No upgrade scheduled")
+	errNotAuthority     = fmt.Errorf("Sender is not the params authority")
+	errMissingSignature = fmt.Errorf("Missing signature")
+)
+
+func ErrNoName() error {
+	return errors.WithCode(errNoName, errors.CodeTypeBaseInvalidInput)
+}
+func ErrBadHeight() error {
+	return errors.WithCode(errBadHeight, errors.CodeTypeBaseInvalidInput)
+}
+func ErrDone() error {
+	return errors.WithCode(errDone, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNoPlan() error {
+	return errors.WithCode(errNoPlan, errors.CodeTypeBaseInvalidInput)
+}
+func ErrNotAuthority() error {
+	return errors.WithCode(errNotAuthority, errors.CodeTypeUnauthorized)
+}
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSignature, errors.CodeTypeUnauthorized)
+}
@@ -0,0 +1,71 @@
+package upgrade
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/types"
+)
+
+type UpgradeTxHandler struct {
+}
+
+// InitState - upgrade has no genesis parameters
+func (h *UpgradeTxHandler) InitState(key, value string, store state.SimpleDB) error {
+	return errors.ErrUnknownKey(key)
+}
+
+// CheckTx checks if the tx is properly structured and sent by the authority
+func (h *UpgradeTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+	return res, h.apply(ctx, store, tx, false)
+}
+
+// DeliverTx schedules or cancels the upgrade
+func (h *UpgradeTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.DeliverResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+	return res, h.apply(ctx, store, tx, true)
+}
+
+// apply checks tx against store, executing it if deliver
+func (h *UpgradeTxHandler) apply(ctx types.Context, store state.SimpleDB, tx sdk.Tx, deliver bool) error {
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		return ErrMissingSignature()
+	}
+	authority, ok := params.Authority(store)
+	if !ok || senders[0] != authority {
+		return ErrNotAuthority()
+	}
+
+	switch _tx := tx.Unwrap().(type) {
+	case TxSchedule:
+		if _tx.Height <= ctx.BlockHeight() {
+			return ErrBadHeight()
+		}
+		if Done(store, _tx.Name) {
+			return ErrDone()
+		}
+		if deliver {
+			plan := _tx.Plan
+			savePlan(store, &plan)
+		}
+	case TxCancel:
+		if loadPlan(store) == nil {
+			return ErrNoPlan()
+		}
+		if deliver {
+			savePlan(store, nil)
+		}
+	default:
+		return errors.ErrUnknownTxType(tx)
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package upgrade
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	// Keys for store prefixes
+	PlanKey    = []byte{0x20} // the scheduled upgrade
+	DonePrefix = []byte{0x21} // heights of the upgrades done: prefix|name
+)
+
+func init() {
+	substore.Register("upgrade", PlanKey, DonePrefix)
+}
+
+// DoneKey is the store key of the height the upgrade name was done at.
+func DoneKey(name string) []byte {
+	return append(append([]byte{}, DonePrefix...), []byte(name)...)
+}
+
+// ParsePlan reads a plan as kept in store, nil if there is none.
+func ParsePlan(b []byte) (*Plan, error) {
+	if b == nil {
+		return nil, nil
+	}
+	plan := new(Plan)
+	if err := wire.ReadBinaryBytes(b, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+func loadPlan(store state.SimpleDB) *Plan {
+	plan, err := ParsePlan(store.Get(PlanKey))
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return plan
+}
+
+func savePlan(store state.SimpleDB, plan *Plan) {
+	if plan == nil {
+		store.Remove(PlanKey)
+		return
+	}
+	store.Set(PlanKey, wire.BinaryBytes(*plan))
+}
+
+// Done tells if the upgrade name was done, code depending on it checks it
+// to switch on the new behavior.
+func Done(store state.SimpleDB, name string) bool {
+	return store.Get(DoneKey(name)) != nil
+}
+
+func markDone(store state.SimpleDB, name string, height int64) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(height))
+	store.Set(DoneKey(name), b)
+}
+
+// BeginBlock runs the upgrade scheduled at height, if this binary knows it.
+// It returns the plan when the upgrade needs another binary, the node must
+// then stop before the block is run.
+func BeginBlock(store state.SimpleDB, height int64) (*Plan, error) {
+	plan := loadPlan(store)
+	if plan == nil || plan.Height != height {
+		return nil, nil
+	}
+	h, ok := handler(plan.Name)
+	if !ok {
+		return plan, nil
+	}
+	if err := h(store); err != nil {
+		return nil, fmt.Errorf("upgrade %s failed: %v", plan.Name, err)
+	}
+	markDone(store, plan.Name, height)
+	savePlan(store, nil)
+	return nil, nil
+}
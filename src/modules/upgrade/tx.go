@@ -0,0 +1,62 @@
+package upgrade
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+
+	"github.com/dora/ultron/const"
+)
+
+// nolint
+const (
+	ByteTxSchedule = 0x82
+	ByteTxCancel   = 0x83
+	TypeTxSchedule = constant.ModuleNameUpgrade + "/schedule"
+	TypeTxCancel   = constant.ModuleNameUpgrade + "/cancel"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxSchedule{}, TypeTxSchedule, ByteTxSchedule)
+	sdk.TxMapper.RegisterImplementation(TxCancel{}, TypeTxCancel, ByteTxCancel)
+}
+
+// Verify interface at compile time
+var _, _ sdk.TxInner = TxSchedule{}, TxCancel{}
+
+// TxSchedule schedules the upgrade Plan, replacing the one scheduled. It can
+// only be sent by the params authority.
+type TxSchedule struct {
+	Plan
+}
+
+func (tx TxSchedule) ValidateBasic() error {
+	if tx.Name == "" {
+		return ErrNoName()
+	}
+	if tx.Height <= 0 {
+		return ErrBadHeight()
+	}
+	return nil
+}
+
+func NewTxSchedule(name string, height int64, info string) sdk.Tx {
+	return TxSchedule{Plan{
+		Name:   name,
+		Height: height,
+		Info:   info,
+	}}.Wrap()
+}
+
+func (tx TxSchedule) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// TxCancel drops the scheduled upgrade, sent by the params authority.
+type TxCancel struct{}
+
+func (tx TxCancel) ValidateBasic() error {
+	return nil
+}
+
+func NewTxCancel() sdk.Tx {
+	return TxCancel{}.Wrap()
+}
+
+func (tx TxCancel) Wrap() sdk.Tx { return sdk.Tx{tx} }
@@ -0,0 +1,80 @@
+// Package upgrade schedules the chain upgrades that need a new binary. The
+// params authority schedules an upgrade by name and height. When the block
+// at that height begins, a binary that knows the upgrade, because a handler
+// was registered under its name, migrates the state and goes on; any other
+// binary writes the plan to the upgrade info file and exits with ExitCode,
+// which tells a supervisor, see `ultron run-supervised`, to swap in the
+// binary of the upgrade and start it again.
+package upgrade
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/state"
+)
+
+const (
+	// ExitCode is the exit code of a node stopped for an upgrade it lacks.
+	ExitCode = 10
+
+	// InfoFile is the file, in the data directory of the node, the plan of
+	// the upgrade is written to before exiting.
+	InfoFile = "upgrade-info.json"
+)
+
+// Plan is an upgrade scheduled at Height.
+type Plan struct {
+	Name   string `json:"name"`
+	Height int64  `json:"height"`
+	Info   string `json:"info,omitempty"` // e.g. where to download the binary
+}
+
+// Handler migrates the state of the app to the upgrade it was registered for.
+type Handler func(store state.SimpleDB) error
+
+var (
+	handlersMtx sync.RWMutex
+	handlers    = make(map[string]Handler)
+)
+
+// RegisterHandler makes this binary know the upgrade name, handler runs at
+// its height. The binaries of upgrades register from init.
+func RegisterHandler(name string, handler Handler) {
+	handlersMtx.Lock()
+	defer handlersMtx.Unlock()
+	handlers[name] = handler
+}
+
+// Known tells if this binary has the upgrade name.
+func Known(name string) bool {
+	_, ok := handler(name)
+	return ok
+}
+
+func handler(name string) (Handler, bool) {
+	handlersMtx.RLock()
+	defer handlersMtx.RUnlock()
+	h, ok := handlers[name]
+	return h, ok
+}
+
+// WriteInfoFile writes plan to file for the supervisor.
+func WriteInfoFile(file string, plan Plan) error {
+	b, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0644)
+}
+
+// ReadInfoFile reads the plan written to file by WriteInfoFile.
+func ReadInfoFile(file string) (plan Plan, err error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return plan, err
+	}
+	err = json.Unmarshal(b, &plan)
+	return plan, err
+}
@@ -0,0 +1,36 @@
+package upgrade
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeginBlock(t *testing.T) {
+	store := state.NewMemKVStore()
+	savePlan(store, &Plan{Name: "v2", Height: 10})
+
+	// a binary without the upgrade stops at its height
+	plan, err := BeginBlock(store, 9)
+	require.Nil(t, err)
+	assert.Nil(t, plan)
+	plan, err = BeginBlock(store, 10)
+	require.Nil(t, err)
+	require.NotNil(t, plan)
+	assert.Equal(t, "v2", plan.Name)
+
+	// the binary of the upgrade runs it and goes on
+	ran := false
+	RegisterHandler("v2", func(store state.SimpleDB) error {
+		ran = true
+		return nil
+	})
+	plan, err = BeginBlock(store, 10)
+	require.Nil(t, err)
+	assert.Nil(t, plan)
+	assert.True(t, ran)
+	assert.True(t, Done(store, "v2"))
+	assert.Nil(t, loadPlan(store))
+}
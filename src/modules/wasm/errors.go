@@ -0,0 +1,47 @@
+// nolint
+package wasm
+
+import (
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/errors"
+)
+
+var (
+	errDisabled         = fmt.Errorf("The wasm runtime is not enabled")
+	errBadCode          = fmt.Errorf("Code format has no runtime")
+	errCodeTooLarge     = fmt.Errorf("Code is too large")
+	errBadGas           = fmt.Errorf("Gas must be positive and within the limit")
+	errBadMethod        = fmt.Errorf("Method must be an exported function")
+	errContractNotFound = fmt.Errorf("Contract not found")
+	errContractExists   = fmt.Errorf("Contract already deployed")
+	errMissingSignature = fmt.Errorf("Missing signature")
+)
+
+func ErrDisabled() error {
+	return errors.WithCode(errDisabled, errors.CodeTypeUnauthorized)
+}
+func ErrBadCode() error {
+	return errors.WithCode(errBadCode, errors.CodeTypeBaseInvalidInput)
+}
+func ErrCodeTooLarge() error {
+	return errors.WithCode(errCodeTooLarge, errors.CodeTypeBaseInvalidInput)
+}
+func ErrBadGas() error {
+	return errors.WithCode(errBadGas, errors.CodeTypeBaseInvalidInput)
+}
+func ErrBadMethod() error {
+	return errors.WithCode(errBadMethod, errors.CodeTypeBaseInvalidInput)
+}
+func ErrContractNotFound() error {
+	return errors.WithCode(errContractNotFound, errors.CodeTypeBaseUnknownAddress)
+}
+func ErrContractExists() error {
+	return errors.WithCode(errContractExists, errors.CodeTypeBaseInvalidInput)
+}
+func ErrMissingSignature() error {
+	return errors.WithCode(errMissingSignature, errors.CodeTypeUnauthorized)
+}
+func ErrExecution(err error) error {
+	return errors.WithCode(fmt.Errorf("Execution failed: %v", err), errors.CodeTypeBaseInvalidInput)
+}
@@ -0,0 +1,170 @@
+package wasm
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/cosmos/cosmos-sdk/errors"
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/dora/ultron/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+type WasmTxHandler struct {
+}
+
+// InitState - set genesis parameters for the wasm runtime
+func (h *WasmTxHandler) InitState(key, value string, store state.SimpleDB) error {
+	params := loadParams(store)
+	switch key {
+	case "enabled":
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("input must be a boolean, Error: %v", err)
+		}
+		params.Enabled = enabled
+	case "max_code_size":
+		i, err := strconv.Atoi(value)
+		if err != nil || i <= 0 {
+			return fmt.Errorf("input must be a positive integer, Error: %v", err)
+		}
+		params.MaxCodeSize = i
+	case "max_gas":
+		i, err := strconv.ParseUint(value, 10, 64)
+		if err != nil || i == 0 {
+			return fmt.Errorf("input must be a positive integer, Error: %v", err)
+		}
+		params.MaxGas = i
+	default:
+		return errors.ErrUnknownKey(key)
+	}
+
+	saveParams(store, params)
+	return nil
+}
+
+// CheckTx checks if the tx is properly structured and allowed, contracts
+// are not run
+func (h *WasmTxHandler) CheckTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.CheckResult, err error) {
+	err = tx.ValidateBasic()
+	if err != nil {
+		return res, err
+	}
+
+	_, err = h.getTxSender(ctx)
+	if err != nil {
+		return res, err
+	}
+
+	params := loadParams(store)
+	switch _tx := tx.Unwrap().(type) {
+	case TxDeploy:
+		return res, checkDeploy(params, _tx)
+	case TxExecute:
+		if _tx.Gas > params.MaxGas {
+			return res, ErrBadGas()
+		}
+		if !params.Enabled {
+			return res, ErrDisabled()
+		}
+		if loadContract(store, _tx.Contract) == nil {
+			return res, ErrContractNotFound()
+		}
+		return res, nil
+	}
+	return res, errors.ErrUnknownTxType(tx)
+}
+
+// DeliverTx executes the tx if valid. The gas used is returned even when
+// the contract fails, the sender pays it, see GasLimit.
+func (h *WasmTxHandler) DeliverTx(ctx types.Context, store state.SimpleDB, tx sdk.Tx) (res sdk.DeliverResult, err error) {
+	// a proposer may include txs CheckTx refused, a gas of 0 would even
+	// leave the contract unmetered
+	if err = tx.ValidateBasic(); err != nil {
+		return
+	}
+	sender, err := h.getTxSender(ctx)
+	if err != nil {
+		return
+	}
+
+	params := loadParams(store)
+	switch _tx := tx.Unwrap().(type) {
+	case TxDeploy:
+		if err = checkDeploy(params, _tx); err != nil {
+			return
+		}
+		contract := &Contract{
+			Address: contractAddress(store, sender),
+			Creator: sender,
+			Code:    _tx.Code,
+		}
+		if loadContract(store, contract.Address) != nil {
+			return res, ErrContractExists()
+		}
+		c := &call{caller: sender, input: _tx.Input, storage: newStorage(store, contract.Address)}
+		if res.GasUsed, err = run(contract, initMethod, c, _tx.Gas); err != nil {
+			return
+		}
+		saveContract(store, contract)
+		countDeploy(store, sender)
+		res.Data = contract.Address.Bytes()
+	case TxExecute:
+		if !params.Enabled {
+			return res, ErrDisabled()
+		}
+		if _tx.Gas > params.MaxGas {
+			return res, ErrBadGas()
+		}
+		contract := loadContract(store, _tx.Contract)
+		if contract == nil {
+			return res, ErrContractNotFound()
+		}
+		c := &call{caller: sender, input: _tx.Input, storage: newStorage(store, contract.Address)}
+		if res.GasUsed, err = run(contract, _tx.Method, c, _tx.Gas); err != nil {
+			return
+		}
+		res.Data = c.output
+	default:
+		err = errors.ErrUnknownTxType(tx)
+	}
+	return
+}
+
+func checkDeploy(params Params, tx TxDeploy) error {
+	if !params.Enabled {
+		return ErrDisabled()
+	}
+	if len(tx.Code) > params.MaxCodeSize {
+		return ErrCodeTooLarge()
+	}
+	if tx.Gas > params.MaxGas {
+		return ErrBadGas()
+	}
+	return nil
+}
+
+// run calls method of contract with the runtime of its code format, keeping
+// the storage writes only if the call succeeds.
+func run(contract *Contract, method string, c *call, gas uint64) (uint64, error) {
+	rt, err := runtimeFor(contract.Code)
+	if err != nil {
+		return 0, err
+	}
+	used, err := rt(contract.Code, method, c, gas)
+	if err != nil {
+		return used, ErrExecution(err)
+	}
+	c.storage.commit()
+	return used, nil
+}
+
+// get the sender from the ctx
+func (h *WasmTxHandler) getTxSender(ctx types.Context) (sender common.Address, err error) {
+	senders := ctx.GetSigners()
+	if len(senders) != 1 {
+		return sender, ErrMissingSignature()
+	}
+	return senders[0], nil
+}
@@ -0,0 +1,42 @@
+package wasm
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// initMethod is the export run when the contract is deployed
+const initMethod = "init"
+
+var wasmMagic = []byte("\x00asm")
+
+// call is what a contract sees of the tx calling it
+type call struct {
+	caller  common.Address
+	input   []byte
+	storage *storage
+	output  []byte // set by the contract
+}
+
+// runtime runs the export method of code for c, within gas. It returns the
+// gas used.
+type runtime func(code []byte, method string, c *call, gas uint64) (uint64, error)
+
+// runtimes by the magic header of the code format they run
+var runtimes = []struct {
+	magic []byte
+	run   runtime
+}{
+	{wasmMagic, runWasm},
+}
+
+// runtimeFor picks the runtime of the code format of code.
+func runtimeFor(code []byte) (runtime, error) {
+	for _, r := range runtimes {
+		if bytes.HasPrefix(code, r.magic) {
+			return r.run, nil
+		}
+	}
+	return nil, ErrBadCode()
+}
@@ -0,0 +1,152 @@
+package wasm
+
+import (
+	"encoding/binary"
+	"sort"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/tendermint/go-wire"
+
+	"github.com/dora/ultron/substore"
+)
+
+// nolint
+var (
+	// Keys for store prefixes
+	ParamKey       = []byte{0x11} // key for the wasm parameters
+	ContractPrefix = []byte{0x12} // contracts: prefix|address
+	StoragePrefix  = []byte{0x13} // storage of contracts: prefix|address|key
+	DeployPrefix   = []byte{0x14} // deploys of creators: prefix|address
+)
+
+func init() {
+	substore.Register("wasm", ParamKey, ContractPrefix, StoragePrefix, DeployPrefix)
+}
+
+// load/save the wasm params
+func loadParams(store state.SimpleDB) (params Params) {
+	b := store.Get(ParamKey)
+	if b == nil {
+		return defaultParams()
+	}
+
+	err := wire.ReadBinaryBytes(b, &params)
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+
+	return
+}
+func saveParams(store state.SimpleDB, params Params) {
+	b := wire.BinaryBytes(params)
+	store.Set(ParamKey, b)
+}
+
+// ContractKey is the store key of the contract at addr, for the "/key"
+// query.
+func ContractKey(addr common.Address) []byte {
+	return append(append([]byte{}, ContractPrefix...), addr.Bytes()...)
+}
+
+// StorageKey is the store key of the slot key of the contract at addr, for
+// the "/key" query. The value is the raw slot value.
+func StorageKey(addr common.Address, key []byte) []byte {
+	return append(append(append([]byte{}, StoragePrefix...), addr.Bytes()...), key...)
+}
+
+// ParseContract decodes a contract read from the store.
+func ParseContract(b []byte) (*Contract, error) {
+	contract := new(Contract)
+	if err := wire.ReadBinaryBytes(b, contract); err != nil {
+		return nil, err
+	}
+	return contract, nil
+}
+
+func loadContract(store state.SimpleDB, addr common.Address) *Contract {
+	b := store.Get(ContractKey(addr))
+	if b == nil {
+		return nil
+	}
+	contract, err := ParseContract(b)
+	if err != nil {
+		panic(err) // This error should never occur big problem if does
+	}
+	return contract
+}
+
+func saveContract(store state.SimpleDB, contract *Contract) {
+	store.Set(ContractKey(contract.Address), wire.BinaryBytes(*contract))
+}
+
+// contractAddress returns the address of the next contract creator deploys.
+// The addresses can't clash with the ones of EVM contracts, which hash the
+// rlp of creator and nonce.
+func contractAddress(store state.SimpleDB, creator common.Address) common.Address {
+	return common.BytesToAddress(crypto.Keccak256([]byte("wasm"), creator.Bytes(), loadDeploys(store, creator))[12:])
+}
+
+// countDeploy moves creator on to its next contract address.
+func countDeploy(store state.SimpleDB, creator common.Address) {
+	deploys := loadDeploys(store, creator)
+	binary.BigEndian.PutUint64(deploys, binary.BigEndian.Uint64(deploys)+1)
+	store.Set(deployKey(creator), deploys)
+}
+
+// loadDeploys returns the big endian count of contracts creator deployed.
+func loadDeploys(store state.SimpleDB, creator common.Address) []byte {
+	deploys := make([]byte, 8)
+	if b := store.Get(deployKey(creator)); b != nil {
+		copy(deploys, b)
+	}
+	return deploys
+}
+
+func deployKey(creator common.Address) []byte {
+	return append(append([]byte{}, DeployPrefix...), creator.Bytes()...)
+}
+
+// storage holds the writes of one call on top of the store, so a failed
+// call leaves the store untouched.
+type storage struct {
+	store  state.SimpleDB
+	addr   common.Address
+	writes map[string][]byte // nil value for deleted slots
+}
+
+func newStorage(store state.SimpleDB, addr common.Address) *storage {
+	return &storage{store: store, addr: addr, writes: make(map[string][]byte)}
+}
+
+func (s *storage) get(key []byte) []byte {
+	if value, ok := s.writes[string(key)]; ok {
+		return value
+	}
+	return s.store.Get(StorageKey(s.addr, key))
+}
+
+func (s *storage) set(key, value []byte) {
+	if len(value) == 0 {
+		value = nil
+	}
+	s.writes[string(key)] = append([]byte(nil), value...)
+}
+
+// commit writes the slots to the store, in key order as the tree shape
+// depends on it.
+func (s *storage) commit() {
+	keys := make([]string, 0, len(s.writes))
+	for key := range s.writes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value := s.writes[key]; value == nil {
+			s.store.Remove(StorageKey(s.addr, []byte(key)))
+		} else {
+			s.store.Set(StorageKey(s.addr, []byte(key)), value)
+		}
+	}
+}
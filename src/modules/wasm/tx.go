@@ -0,0 +1,88 @@
+package wasm
+
+import (
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/dora/ultron/const"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// nolint
+const (
+	ByteTxDeploy  = 0x7d
+	ByteTxExecute = 0x7e
+	TypeTxDeploy  = constant.ModuleNameWasm + "/deploy"
+	TypeTxExecute = constant.ModuleNameWasm + "/execute"
+)
+
+func init() {
+	sdk.TxMapper.RegisterImplementation(TxDeploy{}, TypeTxDeploy, ByteTxDeploy)
+	sdk.TxMapper.RegisterImplementation(TxExecute{}, TypeTxExecute, ByteTxExecute)
+}
+
+// Verify interface at compile time
+var _, _ sdk.TxInner = TxDeploy{}, TxExecute{}
+
+// TxDeploy deploys code as a new contract of the sender, running its init
+// export with input.
+type TxDeploy struct {
+	Code  hexutil.Bytes `json:"code"`
+	Input hexutil.Bytes `json:"input"`
+	Gas   uint64        `json:"gas"`
+}
+
+func (tx TxDeploy) ValidateBasic() error {
+	if _, err := runtimeFor(tx.Code); err != nil {
+		return err
+	}
+	if tx.Gas == 0 {
+		return ErrBadGas()
+	}
+	return nil
+}
+
+func NewTxDeploy(code, input []byte, gas uint64) sdk.Tx {
+	return TxDeploy{
+		Code:  code,
+		Input: input,
+		Gas:   gas,
+	}.Wrap()
+}
+
+func (tx TxDeploy) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// GasLimit is the most gas the tx may use, the sender must hold its worth.
+func (tx TxDeploy) GasLimit() uint64 { return tx.Gas }
+
+// TxExecute calls the export method of a contract with input.
+type TxExecute struct {
+	Contract common.Address `json:"contract"`
+	Method   string         `json:"method"`
+	Input    hexutil.Bytes  `json:"input"`
+	Gas      uint64         `json:"gas"`
+}
+
+func (tx TxExecute) ValidateBasic() error {
+	// init runs once, when the contract is deployed
+	if tx.Method == "" || tx.Method == initMethod {
+		return ErrBadMethod()
+	}
+	if tx.Gas == 0 {
+		return ErrBadGas()
+	}
+	return nil
+}
+
+func NewTxExecute(contract common.Address, method string, input []byte, gas uint64) sdk.Tx {
+	return TxExecute{
+		Contract: contract,
+		Method:   method,
+		Input:    input,
+		Gas:      gas,
+	}.Wrap()
+}
+
+func (tx TxExecute) Wrap() sdk.Tx { return sdk.Tx{tx} }
+
+// GasLimit is the most gas the tx may use, the sender must hold its worth.
+func (tx TxExecute) GasLimit() uint64 { return tx.Gas }
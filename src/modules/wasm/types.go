@@ -0,0 +1,36 @@
+// Package wasm runs WebAssembly contracts next to the EVM. Contracts are
+// deployed and called with txs of their own, not with Ethereum txs, and keep
+// their code and storage in the app store. The runtime is off unless the
+// genesis enables it.
+//
+// A contract exports its memory and the functions txs call, none of which
+// take parameters. It reaches the chain through the host functions of the
+// "env" module, see hostFunctions. Deploying runs the export "init" if
+// there is one.
+package wasm
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Params defines the settings of the wasm runtime
+type Params struct {
+	Enabled     bool   `json:"enabled"`
+	MaxCodeSize int    `json:"max_code_size"` // bytes of a contract
+	MaxGas      uint64 `json:"max_gas"`       // gas of one tx
+}
+
+func defaultParams() Params {
+	return Params{
+		Enabled:     false,
+		MaxCodeSize: 512 * 1024,
+		MaxGas:      10000000,
+	}
+}
+
+// Contract is a deployed contract
+type Contract struct {
+	Address common.Address `json:"address"`
+	Creator common.Address `json:"creator"`
+	Code    []byte         `json:"code"`
+}
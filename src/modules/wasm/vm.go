@@ -0,0 +1,148 @@
+package wasm
+
+import (
+	"fmt"
+
+	"github.com/perlin-network/life/compiler"
+	"github.com/perlin-network/life/exec"
+)
+
+// gas of the host functions, on top of one per instruction
+const (
+	gasStorageRead  = 200
+	gasStorageWrite = 5000
+	gasPerByte      = 3 // of the data host functions copy
+)
+
+// limits of the storage slots
+const (
+	maxKeySize   = 64
+	maxValueSize = 16 * 1024
+)
+
+var vmConfig = exec.VMConfig{
+	DefaultMemoryPages:   16,
+	MaxMemoryPages:       256, // 16 MiB
+	DefaultTableSize:     65536,
+	MaxCallStackDepth:    512,
+	DisableFloatingPoint: true, // float results may differ between nodes
+}
+
+func runWasm(code []byte, method string, c *call, gas uint64) (uint64, error) {
+	config := vmConfig
+	config.GasLimit = gas
+	vm, err := exec.NewVirtualMachine(code, config, &resolver{c}, &compiler.SimpleGasPolicy{GasPerInstruction: 1})
+	if err != nil {
+		return 0, err
+	}
+	entry, ok := vm.GetFunctionExport(method)
+	if !ok {
+		if method == initMethod {
+			return 0, nil
+		}
+		return 0, ErrBadMethod()
+	}
+	_, err = vm.Run(entry)
+	return vm.Gas, err
+}
+
+// resolver links the imports of contracts to the host functions
+type resolver struct {
+	c *call
+}
+
+func (r *resolver) ResolveFunc(module, field string) exec.FunctionImport {
+	fn, ok := hostFunctions[field]
+	if module != "env" || !ok {
+		panic(fmt.Errorf("unknown import %s.%s", module, field))
+	}
+	return func(vm *exec.VirtualMachine) int64 {
+		return fn(vm, r.c)
+	}
+}
+
+func (r *resolver) ResolveGlobal(module, field string) int64 {
+	panic(fmt.Errorf("unknown global %s.%s", module, field))
+}
+
+// hostFunctions are the imports of the "env" module a contract may use.
+// Pointers and lengths are i32.
+var hostFunctions = map[string]func(vm *exec.VirtualMachine, c *call) int64{
+	// input_size() i32
+	"input_size": func(vm *exec.VirtualMachine, c *call) int64 {
+		return int64(len(c.input))
+	},
+	// input_read(ptr)
+	"input_read": func(vm *exec.VirtualMachine, c *call) int64 {
+		charge(vm, gasPerByte*uint64(len(c.input)))
+		copy(memory(vm, param(vm, 0), int64(len(c.input))), c.input)
+		return 0
+	},
+	// caller(ptr), the 20 bytes of the sender address
+	"caller": func(vm *exec.VirtualMachine, c *call) int64 {
+		copy(memory(vm, param(vm, 0), 20), c.caller.Bytes())
+		return 0
+	},
+	// storage_read(key_ptr, key_len, value_ptr, value_cap) i32, the length
+	// of the value or -1 for an empty slot. At most value_cap bytes are
+	// copied.
+	"storage_read": func(vm *exec.VirtualMachine, c *call) int64 {
+		charge(vm, gasStorageRead)
+		value := c.storage.get(key(vm, param(vm, 0), param(vm, 1)))
+		if value == nil {
+			return -1
+		}
+		n := param(vm, 3)
+		if n > int64(len(value)) {
+			n = int64(len(value))
+		}
+		charge(vm, gasPerByte*uint64(n))
+		copy(memory(vm, param(vm, 2), n), value)
+		return int64(len(value))
+	},
+	// storage_write(key_ptr, key_len, value_ptr, value_len), an empty
+	// value clears the slot
+	"storage_write": func(vm *exec.VirtualMachine, c *call) int64 {
+		size := param(vm, 3)
+		if size > maxValueSize {
+			panic(fmt.Errorf("value of %d bytes is over %d", size, maxValueSize))
+		}
+		charge(vm, gasStorageWrite+gasPerByte*uint64(size))
+		c.storage.set(key(vm, param(vm, 0), param(vm, 1)), memory(vm, param(vm, 2), size))
+		return 0
+	},
+	// return(ptr, len) sets the output of the call
+	"return": func(vm *exec.VirtualMachine, c *call) int64 {
+		size := param(vm, 1)
+		charge(vm, gasPerByte*uint64(size))
+		c.output = append([]byte(nil), memory(vm, param(vm, 0), size)...)
+		return 0
+	},
+}
+
+// param returns the i-th i32 parameter of the host function being called.
+func param(vm *exec.VirtualMachine, i int) int64 {
+	return int64(uint32(vm.GetCurrentFrame().Locals[i]))
+}
+
+// memory returns size bytes of the memory of vm at ptr. Out of bounds
+// accesses abort the call.
+func memory(vm *exec.VirtualMachine, ptr, size int64) []byte {
+	if ptr+size > int64(len(vm.Memory)) {
+		panic(fmt.Errorf("memory access at %d of %d bytes is out of bounds", ptr, size))
+	}
+	return vm.Memory[ptr : ptr+size]
+}
+
+func key(vm *exec.VirtualMachine, ptr, size int64) []byte {
+	if size == 0 || size > maxKeySize {
+		panic(fmt.Errorf("key of %d bytes, must be 1 to %d", size, maxKeySize))
+	}
+	return append([]byte(nil), memory(vm, ptr, size)...)
+}
+
+// charge adds gas to the gas used by vm, the limit is checked at the next
+// instruction.
+func charge(vm *exec.VirtualMachine, gas uint64) {
+	vm.Gas += gas
+}
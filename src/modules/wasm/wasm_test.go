@@ -0,0 +1,92 @@
+package wasm
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/dora/ultron/types"
+)
+
+// setCode exports "set", which writes "v" to the slot "k"
+var setCode = []byte{
+	0x00, 0x61, 0x73, 0x6d, 0x01, 0x00, 0x00, 0x00,
+	// types: (i32 i32 i32 i32) -> (), () -> ()
+	0x01, 0x0b, 0x02, 0x60, 0x04, 0x7f, 0x7f, 0x7f, 0x7f, 0x00, 0x60, 0x00, 0x00,
+	// import env.storage_write
+	0x02, 0x15, 0x01, 0x03, 'e', 'n', 'v', 0x0d,
+	's', 't', 'o', 'r', 'a', 'g', 'e', '_', 'w', 'r', 'i', 't', 'e', 0x00, 0x00,
+	// functions, memory
+	0x03, 0x02, 0x01, 0x01,
+	0x05, 0x03, 0x01, 0x00, 0x01,
+	// exports "memory", "set"
+	0x07, 0x10, 0x02, 0x06, 'm', 'e', 'm', 'o', 'r', 'y', 0x02, 0x00, 0x03, 's', 'e', 't', 0x00, 0x01,
+	// set: storage_write(0, 1, 1, 1)
+	0x0a, 0x0e, 0x01, 0x0c, 0x00, 0x41, 0x00, 0x41, 0x01, 0x41, 0x01, 0x41, 0x01, 0x10, 0x00, 0x0b,
+	// data "kv" at 0
+	0x0b, 0x08, 0x01, 0x00, 0x41, 0x00, 0x0b, 0x02, 'k', 'v',
+}
+
+func TestRuntimeFor(t *testing.T) {
+	_, err := runtimeFor(setCode)
+	assert.Nil(t, err)
+	_, err = runtimeFor(common.Hex2Bytes("6080604052"))
+	assert.NotNil(t, err, "evm code")
+}
+
+func TestRun(t *testing.T) {
+	store := state.NewMemKVStore()
+	contract := &Contract{Address: common.HexToAddress("0x01"), Code: setCode}
+
+	c := &call{storage: newStorage(store, contract.Address)}
+	_, err := run(contract, initMethod, c, 1000)
+	assert.Nil(t, err, "no init export")
+
+	c = &call{storage: newStorage(store, contract.Address)}
+	_, err = run(contract, "set", c, 1)
+	assert.NotNil(t, err, "out of gas")
+	assert.Nil(t, store.Get(StorageKey(contract.Address, []byte("k"))))
+
+	c = &call{storage: newStorage(store, contract.Address)}
+	used, err := run(contract, "set", c, 100000)
+	assert.Nil(t, err)
+	assert.True(t, used > gasStorageWrite)
+	assert.Equal(t, []byte("v"), store.Get(StorageKey(contract.Address, []byte("k"))))
+
+	c = &call{storage: newStorage(store, contract.Address)}
+	_, err = run(contract, "get", c, 100000)
+	assert.NotNil(t, err, "no such export")
+}
+
+func TestDeliverTx(t *testing.T) {
+	store := state.NewMemKVStore()
+	params := defaultParams()
+	params.Enabled = true
+	saveParams(store, params)
+	ctx := types.NewContext("test", 1, nil)
+	sender := common.HexToAddress("0x02")
+	ctx.WithSigners(sender)
+	h := &WasmTxHandler{}
+
+	// a gas of 0 would run the contract unmetered
+	_, err := h.DeliverTx(ctx, store, NewTxDeploy(setCode, nil, 0))
+	assert.NotNil(t, err)
+	res, err := h.DeliverTx(ctx, store, NewTxDeploy(setCode, nil, 1000))
+	assert.Nil(t, err)
+	contract := common.BytesToAddress(res.Data)
+
+	_, err = h.DeliverTx(ctx, store, NewTxExecute(contract, "set", nil, 0))
+	assert.NotNil(t, err)
+	_, err = h.DeliverTx(ctx, store, NewTxExecute(contract, initMethod, nil, 1000))
+	assert.NotNil(t, err, "init runs at deployment only")
+
+	// a failed call still reports its gas, the sender pays it
+	res, err = h.DeliverTx(ctx, store, NewTxExecute(contract, "set", nil, 1))
+	assert.NotNil(t, err)
+	assert.True(t, res.GasUsed > 0)
+	res, err = h.DeliverTx(ctx, store, NewTxExecute(contract, "set", nil, 100000))
+	assert.Nil(t, err)
+	assert.True(t, res.GasUsed > gasStorageWrite)
+}
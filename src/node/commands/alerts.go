@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/event"
+	tmcfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/node"
+
+	"github.com/dora/ultron/alerts"
+	"github.com/dora/ultron/backend"
+	"github.com/dora/ultron/modules/stake"
+	emtConfig "github.com/dora/ultron/node/config"
+	"github.com/dora/ultron/utils"
+)
+
+// nodeAlerts watches a node for the webhooks of the [alerts] config
+type nodeAlerts struct {
+	notifier *alerts.Notifier
+	monitor  *alerts.Monitor
+	jailings event.Subscription
+}
+
+// startAlerts watches tmNode and posts its trouble to the webhooks of conf,
+// along with the jailing of its validator. It returns nil with no webhook.
+func startAlerts(conf emtConfig.AlertsConfig, cfg *tmcfg.Config, tmNode *node.Node, b *backend.Backend) *nodeAlerts {
+	var hooks []alerts.Webhook
+	if conf.WebhookURL != "" {
+		hooks = append(hooks, alerts.HTTPWebhook{URL: conf.WebhookURL})
+	}
+	if conf.SlackWebhookURL != "" {
+		hooks = append(hooks, alerts.SlackWebhook{URL: conf.SlackWebhookURL})
+	}
+	if conf.PagerDutyRoutingKey != "" {
+		hooks = append(hooks, alerts.PagerDutyWebhook{RoutingKey: conf.PagerDutyRoutingKey})
+	}
+	if len(hooks) == 0 || conf.CheckInterval <= 0 {
+		return nil
+	}
+
+	notifier := alerts.NewNotifier(cfg.Moniker, hooks...)
+	monitor := alerts.NewMonitor(alerts.Config{
+		Interval:     conf.CheckInterval,
+		StallTimeout: conf.StallTimeout,
+		MinPeers:     conf.MinPeers,
+		MinFreeDisk:  conf.MinFreeDisk << 20,
+		Dir:          cfg.RootDir,
+	}, alertedNode{tmNode}, notifier)
+	monitor.Start()
+
+	own := utils.PubKeyString(tmNode.PrivValidator().GetPubKey())
+	jailings := make(chan stake.JailEvent)
+	sub := b.SubscribeJailings(jailings)
+	go func() {
+		for {
+			select {
+			case ev := <-jailings:
+				if ev.PubKey == own {
+					notifier.Fire(alerts.ValidatorJailed, ev.Height, "validator %s jailed for missing %d blocks, until block %d",
+						ev.Validator.Hex(), ev.MissedBlocks, ev.JailedUntil)
+				}
+			case <-sub.Err():
+				return
+			}
+		}
+	}()
+	return &nodeAlerts{notifier: notifier, monitor: monitor, jailings: sub}
+}
+
+func (a *nodeAlerts) stop() {
+	a.monitor.Stop()
+	a.jailings.Unsubscribe()
+}
+
+// alertedNode reads what alerts.Monitor checks of a tendermint node
+type alertedNode struct {
+	n *node.Node
+}
+
+func (a alertedNode) Height() int64 {
+	return a.n.BlockStore().Height()
+}
+
+func (a alertedNode) Peers() int {
+	return a.n.Switch().Peers().Size()
+}
+
+// AppHashMismatch compares the app hash of the block proposed for the next
+// height with the one this node committed to. Tendermint refuses such a
+// block without telling the app, the node stalls.
+func (a alertedNode) AppHashMismatch() bool {
+	_, mismatch := proposedAppHash(a.n)
+	return mismatch
+}
+
+// proposedAppHash returns the app hash of the block proposed for the next
+// height of n, and whether it isn't the one n committed to
+func proposedAppHash(n *node.Node) ([]byte, bool) {
+	cs := n.ConsensusState()
+	state := cs.GetState()
+	block := cs.GetRoundState().ProposalBlock
+	if block == nil || block.Height != state.LastBlockHeight+1 {
+		return nil, false
+	}
+	return block.AppHash, !bytes.Equal(block.AppHash, state.AppHash)
+}
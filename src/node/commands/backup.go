@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/tendermint/tmlibs/cli"
+
+	"github.com/dora/ultron/app"
+	"github.com/dora/ultron/appwal"
+	emtUtils "github.com/dora/ultron/backend/cmd/utils"
+	"github.com/dora/ultron/backup"
+)
+
+var (
+	BackupTargetFlag   = "target"
+	BackupIPCFlag      = "ipc"
+	BackupIDFlag       = "id"
+	BackupParallelFlag = "parallel"
+)
+
+// backupDirs are the directories of a home backed up: the tendermint and
+// app databases, and the chaindata of ethereum
+var backupDirs = []string{"data", "ultron/chaindata"}
+
+// restoreMarker is in a home being restored, a restore cut short can be run
+// again into it
+const restoreMarker = ".restoring"
+
+// skipBackup leaves the keys of the validator out of the backups, a node
+// restored with an old signing state could sign twice, and the write ahead
+// logs of tendermint and of the app, only good for the node that wrote them
+func skipBackup(rel string) bool {
+	base := path.Base(rel)
+	return strings.HasPrefix(base, "priv_validator") || base == "cs.wal" || base == "mempool.wal" ||
+		strings.HasPrefix(base, appwal.File)
+}
+
+// homeCheckpointer checkpoints the home rootDir of a node running baseApp,
+// of a stopped node when baseApp is nil
+func homeCheckpointer(rootDir string, baseApp *app.BaseApp) *backup.Checkpointer {
+	c := &backup.Checkpointer{Root: rootDir, Dirs: backupDirs, Skip: skipBackup}
+	if baseApp != nil {
+		c.Pause = baseApp.PauseBlocks
+		c.Height = baseApp.CommittedHeight
+	}
+	return c
+}
+
+// newCheckpointDir returns a new directory of rootDir for a checkpoint
+func newCheckpointDir(rootDir string) string {
+	return filepath.Join(rootDir, fmt.Sprintf("backup-checkpoint-%d", time.Now().UnixNano()))
+}
+
+// GetBackupCmd - initialize the backup command and its subcommands
+func GetBackupCmd() *cobra.Command {
+	backupCmd := &cobra.Command{
+		Use:   "backup",
+		Short: "Back the databases of a node up to a bucket and restore them",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+	}
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Back the home up, through the ipc socket of the node while it runs",
+		RunE:  backupCreateCmd,
+	}
+	createCmd.Flags().String(BackupIPCFlag, "", "Ipc socket of the running node, the one of the home by default; the node must be stopped when there is none")
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore a backup into a fresh home",
+		RunE:  backupRestoreCmd,
+	}
+	restoreCmd.Flags().String(BackupIDFlag, "latest", "Backup to restore, see backup list")
+
+	listCmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the backups of the target",
+		RunE:  backupListCmd,
+	}
+
+	for _, cmd := range []*cobra.Command{createCmd, restoreCmd, listCmd} {
+		cmd.Flags().String(BackupTargetFlag, "", "Where the backups are: s3://bucket/prefix, gs://bucket/prefix or file:///dir")
+	}
+	for _, cmd := range []*cobra.Command{createCmd, restoreCmd} {
+		cmd.Flags().Int(BackupParallelFlag, 8, "Files transferred at a time")
+	}
+	backupCmd.AddCommand(createCmd, restoreCmd, listCmd)
+	return backupCmd
+}
+
+func openBackupTarget() (backup.Store, error) {
+	target := viper.GetString(BackupTargetFlag)
+	if target == "" {
+		return nil, fmt.Errorf("no target, see --%s", BackupTargetFlag)
+	}
+	return backup.Open(target)
+}
+
+func backupCreateCmd(cmd *cobra.Command, args []string) error {
+	rootDir := viper.GetString(cli.HomeFlag)
+	store, err := openBackupTarget()
+	if err != nil {
+		return err
+	}
+
+	endpoint := viper.GetString(BackupIPCFlag)
+	if endpoint == "" {
+		endpoint = filepath.Join(rootDir, emtUtils.IPCFile)
+		if _, err := os.Stat(endpoint); os.IsNotExist(err) {
+			endpoint = ""
+		}
+	}
+	var cp *backup.Checkpoint
+	if endpoint != "" {
+		client, err := rpc.Dial(endpoint)
+		if err != nil {
+			return fmt.Errorf("attaching to the node: %v", err)
+		}
+		err = client.Call(&cp, "admin_checkpoint")
+		client.Close()
+		if err != nil {
+			return fmt.Errorf("checkpoint: %v", err)
+		}
+	} else {
+		if cp, err = homeCheckpointer(rootDir, nil).Checkpoint(newCheckpointDir(rootDir)); err != nil {
+			return fmt.Errorf("checkpoint: %v", err)
+		}
+	}
+	defer os.RemoveAll(cp.Dir) // nolint: errcheck
+
+	logger.Info("Uploading checkpoint", "height", cp.Height, "dir", cp.Dir)
+	m, stats, err := backup.Create(store, cp, viper.GetInt(BackupParallelFlag))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("backup %s of height %d: %d files, %d uploaded (%d MB) of %d MB\n", m.ID, m.Height,
+		stats.Files, stats.Transferred, stats.Bytes>>20, m.Size()>>20)
+	return nil
+}
+
+func backupRestoreCmd(cmd *cobra.Command, args []string) error {
+	rootDir := viper.GetString(cli.HomeFlag)
+	store, err := openBackupTarget()
+	if err != nil {
+		return err
+	}
+	marker := filepath.Join(rootDir, restoreMarker)
+	if _, err := os.Stat(marker); os.IsNotExist(err) {
+		for _, dir := range backupDirs {
+			// the stale journals of a db restored over would be replayed
+			if entries, _ := filepath.Glob(filepath.Join(rootDir, dir, "*")); len(entries) > 0 {
+				return fmt.Errorf("%s isn't empty, restore into a fresh home", filepath.Join(rootDir, dir))
+			}
+		}
+	}
+
+	m, err := backup.Load(store, viper.GetString(BackupIDFlag))
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(rootDir, 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(marker)
+	if err != nil {
+		return err
+	}
+	f.Close() // nolint: errcheck
+
+	logger.Info("Restoring backup", "id", m.ID, "height", m.Height, "files", len(m.Files))
+	stats, err := backup.Restore(store, m, rootDir, viper.GetInt(BackupParallelFlag))
+	if err != nil {
+		return fmt.Errorf("%v, run the restore again to resume it", err)
+	}
+	if err := os.Remove(marker); err != nil {
+		return err
+	}
+	fmt.Printf("restored backup %s of height %d: %d files, %d downloaded (%d MB)\n", m.ID, m.Height,
+		stats.Files, stats.Transferred, stats.Bytes>>20)
+	return nil
+}
+
+func backupListCmd(cmd *cobra.Command, args []string) error {
+	store, err := openBackupTarget()
+	if err != nil {
+		return err
+	}
+	ids, err := backup.List(store)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		m, err := backup.Load(store, id)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\theight %d\t%d files\t%d MB\n", m.ID, m.Height, len(m.Files), m.Size()>>20)
+	}
+	return nil
+}
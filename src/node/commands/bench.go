@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/cosmos/cosmos-sdk/version"
+	"github.com/tendermint/tmlibs/cli"
+
+	"github.com/dora/ultron/bench"
+)
+
+var (
+	DurationFlag      = "duration"
+	ProfileFlag       = "profile"
+	RatiosFlag        = "ratios"
+	AccountsFlag      = "accounts"
+	ClientsFlag       = "clients"
+	MaxMemGrowthFlag  = "max_mem_growth"
+	MaxDiskGrowthFlag = "max_disk_growth"
+)
+
+// GetBenchCmd - initialize the bench command and its subcommands
+func GetBenchCmd() *cobra.Command {
+	benchCmd := &cobra.Command{
+		Use:   "bench",
+		Short: "Run benchmarks against a local full node",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+	}
+
+	soakCmd := &cobra.Command{
+		Use:   "soak",
+		Short: "Start a full node and keep it under load, failing on invariant violations",
+		RunE:  soakCmd,
+	}
+	soakCmd.Flags().Duration(DurationFlag, 24*time.Hour, "How long to keep the load running")
+	soakCmd.Flags().String(ProfileFlag, "mixed", "Workload profile: transfer, contract or mixed")
+	soakCmd.Flags().String(RatiosFlag, "", "Override the profile ratios, e.g. deploy=1,deposit=3,withdraw=1")
+	soakCmd.Flags().Int(AccountsFlag, genesisAccounts, "Number of test accounts sending txs, loaded from "+accountInfoDB)
+	soakCmd.Flags().Int(ClientsFlag, 8, "Number of rpc clients used to broadcast txs")
+	soakCmd.Flags().String(BenchReportFlag, "", "Write per-round results to this file (.json or .csv)")
+	soakCmd.Flags().Uint64(MaxMemGrowthFlag, 0, "Fail when the heap grows by more than this many MB (0 to disable)")
+	soakCmd.Flags().Uint64(MaxDiskGrowthFlag, 0, "Fail when the home dir grows by more than this many MB (0 to disable)")
+	soakCmd.Flags().String(DerivedAccountsFlag, "", "Derive the test accounts from this seed instead of loading them, see bench accounts")
+
+	benchCmd.AddCommand(soakCmd, benchAccountsCmd(), benchTxHashCmd())
+	return benchCmd
+}
+
+func soakCmd(cmd *cobra.Command, args []string) error {
+	rootDir := viper.GetString(cli.HomeFlag)
+	profile, err := bench.ParseProfile(viper.GetString(ProfileFlag), viper.GetString(RatiosFlag))
+	if err != nil {
+		return err
+	}
+
+	cmdName := cmd.Root().Name()
+	appName := fmt.Sprintf("%s v%v", cmdName, version.Version)
+	storeApp, err := newStoreApp(appName, config, logger.With("module", "app"))
+	if err != nil {
+		return err
+	}
+
+	srvs, err := startServices(rootDir, storeApp)
+	if err != nil {
+		return errors.Errorf("Error in start services: %v\n", err)
+	}
+	defer srvs.tmNode.Stop()
+
+	runner, err := newSoakRunner(srvs, rootDir, soakConfig{
+		Duration:      viper.GetDuration(DurationFlag),
+		Profile:       profile,
+		Accounts:      viper.GetInt(AccountsFlag),
+		Clients:       viper.GetInt(ClientsFlag),
+		ReportFile:    viper.GetString(BenchReportFlag),
+		MaxMemGrowth:  uint64(viper.GetInt64(MaxMemGrowthFlag)) << 20,
+		MaxDiskGrowth: uint64(viper.GetInt64(MaxDiskGrowthFlag)) << 20,
+	})
+	if err != nil {
+		return err
+	}
+	return runner.Run()
+}
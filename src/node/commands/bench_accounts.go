@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/bench"
+)
+
+var (
+	DerivedAccountsFlag = "derived_accounts"
+	BalanceFlag         = "balance"
+)
+
+// derivedAccounts returns the first n accounts of seed, see bench.Accounts.
+func derivedAccounts(seed string, n int) []*TestAccount {
+	derived := bench.NewAccounts(seed)
+	accounts := make([]*TestAccount, n)
+	for i := range accounts {
+		key := derived.Key(i)
+		accounts[i] = &TestAccount{Address: crypto.PubkeyToAddress(key.PublicKey), key: key}
+	}
+	return accounts
+}
+
+// signTx signs tx for acc, with its key if it is derived, else through the
+// keystore
+func signTx(s *Services, acc *TestAccount, tx *types.Transaction) *types.Transaction {
+	if acc.key == nil {
+		return makeTransaction(s, &acc.Address, acc.PassPhrase, tx)
+	}
+	chainID := big.NewInt((int64)(config.EMConfig.EthChainId))
+	signed, _ := types.SignTx(tx, types.NewEIP155Signer(chainID), acc.key)
+	if signed != nil {
+		ethereum.RememberTx(signed)
+	}
+	return signed
+}
+
+func benchAccountsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "accounts <seed>",
+		Short: "Print the genesis alloc funding the test accounts derived from seed, see --" + DerivedAccountsFlag,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			balance, ok := new(big.Int).SetString(viper.GetString(BalanceFlag), 10)
+			if !ok {
+				return fmt.Errorf("invalid balance %s", viper.GetString(BalanceFlag))
+			}
+			alloc := bench.NewAccounts(args[0]).Alloc(viper.GetInt(AccountsFlag), balance)
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(alloc)
+		},
+	}
+	cmd.Flags().Int(AccountsFlag, genesisAccounts, "Number of test accounts to fund")
+	cmd.Flags().String(BalanceFlag, "10000000000000000000000000000000000", "Wei each account gets")
+	return cmd
+}
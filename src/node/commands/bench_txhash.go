@@ -0,0 +1,70 @@
+package commands
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/dora/ultron/backend/ethereum"
+)
+
+// benchTxHashCmd compares what CheckTx spends on the hash and the sender of
+// a tx decoded from its bytes with a tx signed on the node, which carries
+// them, see ethereum.RememberTx.
+func benchTxHashCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "txhash",
+		Short: "Measure the hash and sender of a signed tx, decoded or remembered from its signing",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			key, err := crypto.GenerateKey()
+			if err != nil {
+				return err
+			}
+			signer := types.NewEIP155Signer(big.NewInt((int64)(config.EMConfig.EthChainId)))
+			tx, err := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(1), big.NewInt(21000), gasprice, nil), signer, key)
+			if err != nil {
+				return err
+			}
+			txBytes, err := rlp.EncodeToBytes(tx)
+			if err != nil {
+				return err
+			}
+
+			check := func(b *testing.B, txBytes []byte) {
+				tx, err := ethereum.RecallTx(txBytes)
+				if err != nil {
+					b.Fatal(err)
+				}
+				tx.Hash()
+				if _, err := tx.From(signer, false); err != nil {
+					b.Fatal(err)
+				}
+			}
+			decoded := testing.Benchmark(func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					check(b, txBytes)
+				}
+			})
+			remembered := testing.Benchmark(func(b *testing.B) {
+				b.ReportAllocs()
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					ethereum.RememberTx(tx)
+					b.StartTimer()
+					check(b, txBytes)
+				}
+			})
+			fmt.Printf("decoded    %s %s\n", decoded, decoded.MemString())
+			fmt.Printf("remembered %s %s\n", remembered, remembered.MemString())
+			return nil
+		},
+	}
+}
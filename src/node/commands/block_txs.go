@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"sync/atomic"
+
+	bc "github.com/tendermint/tendermint/blockchain"
+	"github.com/tendermint/tendermint/node"
+	dbm "github.com/tendermint/tmlibs/db"
+)
+
+// blockTxs reads the txs of the blocks tendermint saved, for the app to
+// prefetch them, see app.SetBlockTxs. It reads the block store tendermint
+// opens, caught by dbProvider as the node is created, so that the blocks
+// replayed in the handshake are read too.
+type blockTxs struct {
+	store atomic.Value // *bc.BlockStore
+}
+
+// dbProvider opens the databases of tendermint as node.DefaultDBProvider
+// does.
+func (b *blockTxs) dbProvider(ctx *node.DBContext) (dbm.DB, error) {
+	db, err := node.DefaultDBProvider(ctx)
+	if err == nil && ctx.ID == "blockstore" {
+		b.store.Store(bc.NewBlockStore(db))
+	}
+	return db, err
+}
+
+// txs implements app.BlockTxs.
+func (b *blockTxs) txs(height int64) ([][]byte, bool) {
+	store, _ := b.store.Load().(*bc.BlockStore)
+	// the height of this store is the one it was opened at, the seen
+	// commit is saved once the rest of the block is
+	if store == nil || store.LoadSeenCommit(height) == nil {
+		return nil, false
+	}
+	block := store.LoadBlock(height)
+	if block == nil {
+		return nil, false
+	}
+	txs := make([][]byte, len(block.Data.Txs))
+	for i, tx := range block.Data.Txs {
+		txs[i] = tx
+	}
+	return txs, true
+}
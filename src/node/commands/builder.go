@@ -0,0 +1,112 @@
+package commands
+
+import (
+	"fmt"
+
+	tmlog "github.com/tendermint/tmlibs/log"
+
+	emtConfig "github.com/dora/ultron/node/config"
+)
+
+// NodeBuilder starts a node from options instead of the viper settings of
+// the command line, so ultron can be embedded in other Go programs:
+//
+//	srv, err := commands.NewNodeBuilder(
+//		commands.WithHomeDir(dir),
+//		commands.WithDBBackend("memdb"),
+//	).Build()
+type NodeBuilder struct {
+	homeDir   string
+	chainID   string
+	genesis   string
+	dbBackend string
+	logger    tmlog.Logger
+	configure []func(conf *emtConfig.UltronConfig)
+}
+
+// Option sets up a NodeBuilder.
+type Option func(b *NodeBuilder)
+
+// NewNodeBuilder returns a builder with opts applied.
+func NewNodeBuilder(opts ...Option) *NodeBuilder {
+	b := &NodeBuilder{
+		chainID: "ultron",
+		logger:  logger,
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
+}
+
+// WithHomeDir sets the home of the node, it is initialized when empty.
+func WithHomeDir(dir string) Option {
+	return func(b *NodeBuilder) {
+		b.homeDir = dir
+	}
+}
+
+// WithChainID sets the tendermint chain id of a new home.
+func WithChainID(chainID string) Option {
+	return func(b *NodeBuilder) {
+		b.chainID = chainID
+	}
+}
+
+// WithGenesis sets the ethereum genesis file of a new home.
+func WithGenesis(path string) Option {
+	return func(b *NodeBuilder) {
+		b.genesis = path
+	}
+}
+
+// WithDBBackend sets the database backend, leveldb or memdb.
+func WithDBBackend(backend string) Option {
+	return func(b *NodeBuilder) {
+		b.dbBackend = backend
+	}
+}
+
+// WithRPC serves the ethereum rpc apis, e.g. "eth,net,web3", on addr:port.
+func WithRPC(addr string, port uint, apis string) Option {
+	return WithConfig(func(conf *emtConfig.UltronConfig) {
+		conf.EMConfig.RPCEnabledFlag = true
+		conf.EMConfig.RPCListenAddrFlag = addr
+		conf.EMConfig.RPCPortFlag = port
+		conf.EMConfig.RPCApiFlag = apis
+	})
+}
+
+// WithLogger sets the logger of the node.
+func WithLogger(logger tmlog.Logger) Option {
+	return func(b *NodeBuilder) {
+		b.logger = logger
+	}
+}
+
+// WithConfig adjusts the config read from the home before the node starts.
+func WithConfig(fn func(conf *emtConfig.UltronConfig)) Option {
+	return func(b *NodeBuilder) {
+		b.configure = append(b.configure, fn)
+	}
+}
+
+// Build initializes the home if needed and starts the node.
+func (b *NodeBuilder) Build() (*Services, error) {
+	if b.homeDir == "" {
+		return nil, fmt.Errorf("no home dir, see WithHomeDir")
+	}
+
+	conf, err := initHome(b.homeDir, b.chainID, b.genesis)
+	if err != nil {
+		return nil, err
+	}
+	if b.dbBackend != "" {
+		conf.TMConfig.DBBackend = b.dbBackend
+	}
+	for _, fn := range b.configure {
+		fn(conf)
+	}
+
+	return newNode(conf, b.logger)
+}
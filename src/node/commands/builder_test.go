@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	cmn "github.com/tendermint/tmlibs/common"
+
+	emtConfig "github.com/dora/ultron/node/config"
+)
+
+func TestBuildFails(t *testing.T) {
+	_, err := NewNodeBuilder().Build()
+	assert.NotNil(t, err, "no home dir")
+
+	dir, err := ioutil.TempDir("", "builder")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	// the error comes back instead of the process exiting
+	bad := filepath.Join(dir, "bad.json")
+	require.Nil(t, ioutil.WriteFile(bad, []byte("{not json"), 0644))
+	_, err = NewNodeBuilder(WithHomeDir(dir), WithGenesis(bad)).Build()
+	assert.NotNil(t, err)
+	conf, err := emtConfig.LoadConfig(dir)
+	require.Nil(t, err)
+	assert.False(t, cmn.FileExists(conf.TMConfig.GenesisFile()), "left uninitialized")
+}
+
+func TestInitHome(t *testing.T) {
+	dir, err := ioutil.TempDir("", "builder")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	bad := filepath.Join(dir, "bad.json")
+	require.Nil(t, ioutil.WriteFile(bad, []byte("{not json"), 0644))
+	_, err = initHome(dir, "ultron-test", bad)
+	assert.NotNil(t, err)
+
+	// a failed init is redone
+	conf, err := initHome(dir, "ultron-test", "")
+	require.Nil(t, err)
+	assert.True(t, cmn.FileExists(conf.TMConfig.GenesisFile()))
+	assert.True(t, cmn.FileExists(filepath.Join(dir, "keystore")))
+	_, err = initHome(dir, "ultron-test", "")
+	assert.Nil(t, err)
+}
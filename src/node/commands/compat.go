@@ -0,0 +1,304 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethdb"
+	abcicli "github.com/tendermint/abci/client"
+	abcitypes "github.com/tendermint/abci/types"
+	bc "github.com/tendermint/tendermint/blockchain"
+	"github.com/tendermint/tendermint/proxy"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+	"github.com/tendermint/tmlibs/cli"
+	dbm "github.com/tendermint/tmlibs/db"
+
+	emtUtils "github.com/dora/ultron/backend/cmd/utils"
+	"github.com/dora/ultron/backup"
+	"github.com/dora/ultron/compat"
+	emtConfig "github.com/dora/ultron/node/config"
+	"github.com/dora/ultron/version"
+)
+
+var (
+	CompatKeepFlag = "keep"
+)
+
+// compatDirs are the tendermint databases of a home recorded, the blocks
+// and the state of the last one
+var compatDirs = []string{"data/blockstore.db", "data/state.db"}
+
+// GetCompatCmd - initialize the compat command and its subcommands
+func GetCompatCmd() *cobra.Command {
+	compatCmd := &cobra.Command{
+		Use:   "compat",
+		Short: "Check that this binary commits the blocks recorded from previous releases with the same app hashes",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+	}
+
+	recordCmd := &cobra.Command{
+		Use:   "record <dir>",
+		Short: "Record the blocks of the stopped node of the home into dir, with the app hashes they were committed with",
+		Args:  cobra.ExactArgs(1),
+		RunE:  compatRecordCmd,
+	}
+
+	replayCmd := &cobra.Command{
+		Use:   "replay <dir>",
+		Short: "Run the blocks recorded in dir again from genesis, failing at the first one committed with another app hash",
+		Args:  cobra.ExactArgs(1),
+		RunE:  compatReplayCmd,
+	}
+	replayCmd.Flags().Bool(CompatKeepFlag, false, "Keep the home the blocks are run in, to look into a mismatch")
+
+	compatCmd.AddCommand(recordCmd, replayCmd)
+	return compatCmd
+}
+
+func compatRecordCmd(cmd *cobra.Command, args []string) error {
+	rootDir, dir := viper.GetString(cli.HomeFlag), args[0]
+	c := &backup.Checkpointer{Root: rootDir, Dirs: compatDirs}
+	if _, err := c.Checkpoint(dir); err != nil {
+		return err
+	}
+	m, err := recordCompat(rootDir, dir)
+	if err != nil {
+		os.RemoveAll(dir) // nolint: errcheck
+		return err
+	}
+	fmt.Printf("recorded %d blocks of %s into %s\n", m.Height, m.ChainID, dir)
+	return nil
+}
+
+// recordCompat writes the genesis and the manifest of the blocks copied
+// from rootDir into dir
+func recordCompat(rootDir, dir string) (*compat.Manifest, error) {
+	genesis, err := ioutil.ReadFile(config.TMConfig.GenesisFile())
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, compat.GenesisFile), genesis, 0644); err != nil {
+		return nil, err
+	}
+
+	chainDb, err := ethdb.NewLDBDatabase(filepath.Join(rootDir, "ultron/chaindata"), 0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("opening the chaindata, is the node stopped? %v", err)
+	}
+	ethGenesis, err := compat.ExportGenesis(chainDb)
+	chainDb.Close()
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(ethGenesis, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, compat.EthGenesisFile), data, 0644); err != nil {
+		return nil, err
+	}
+
+	m := &compat.Manifest{
+		Version:  compat.Version,
+		Release:  version.Version,
+		Recorded: time.Now().UTC(),
+	}
+	if err := readAppHashes(filepath.Join(dir, "data"), m); err != nil {
+		return nil, err
+	}
+	return m, compat.WriteManifest(dir, m)
+}
+
+// readAppHashes fills m with the app hashes of the blocks of the tendermint
+// databases of dataDir, each one carried by the block after it but the last
+// one, kept in the state
+func readAppHashes(dataDir string, m *compat.Manifest) error {
+	stateDB := dbm.NewDB("state", dbm.LevelDBBackendStr, dataDir)
+	defer stateDB.Close()
+	blockDB := dbm.NewDB("blockstore", dbm.LevelDBBackendStr, dataDir)
+	defer blockDB.Close()
+
+	state := sm.LoadState(stateDB)
+	store := bc.NewBlockStore(blockDB)
+	m.ChainID, m.Height = state.ChainID, state.LastBlockHeight
+	if m.Height == 0 {
+		return errors.New("no block to record")
+	}
+	if store.Height() < m.Height {
+		return fmt.Errorf("the blocks stop at %d, the state is at %d", store.Height(), m.Height)
+	}
+	for height := int64(2); height <= m.Height; height++ {
+		meta := store.LoadBlockMeta(height)
+		if meta == nil {
+			return fmt.Errorf("no block %d", height)
+		}
+		m.AppHashes = append(m.AppHashes, hexutil.Bytes(meta.Header.AppHash))
+	}
+	m.AppHashes = append(m.AppHashes, hexutil.Bytes(state.AppHash))
+	return nil
+}
+
+func compatReplayCmd(cmd *cobra.Command, args []string) error {
+	dir := args[0]
+	m, err := compat.ReadManifest(dir)
+	if err != nil {
+		return err
+	}
+	base, err := ioutil.TempDir("", "ultron-compat")
+	if err != nil {
+		return err
+	}
+	if viper.GetBool(CompatKeepFlag) {
+		fmt.Println("running the blocks in", base)
+	} else {
+		defer os.RemoveAll(base) // nolint: errcheck
+	}
+
+	// a fresh home with the blocks and the state of the last one, which
+	// tendermint runs from genesis again as the app has none of them
+	rootDir := filepath.Join(base, "home")
+	c := &backup.Checkpointer{Root: dir, Dirs: []string{"data"}}
+	if _, err := c.Checkpoint(rootDir); err != nil {
+		return err
+	}
+	conf, err := compatConfig(dir, rootDir, m)
+	if err != nil {
+		return err
+	}
+	ctx, err := newEmtContext(conf)
+	if err != nil {
+		return err
+	}
+	if err := initEthermintAt(ctx, conf, filepath.Join(dir, compat.EthGenesisFile), nil); err != nil {
+		return err
+	}
+	genDoc, err := GenesisDocFromFile(conf.TMConfig.GenesisFile())
+	if err != nil {
+		return err
+	}
+	storeApp, err := newStoreApp("ultron", conf, logger.With("module", "app"))
+	if err != nil {
+		return err
+	}
+
+	checker := compat.NewChecker(m)
+	wrap := func(c proxy.ClientCreator) proxy.ClientCreator {
+		return compatClientCreator{c, checker, genesisValidators(genDoc)}
+	}
+	logger.Info("Running the recorded blocks", "blocks", m.Height, "release", m.Release)
+	srvs, err := newServices(serviceOptions{
+		ctx:      ctx,
+		tmConf:   &conf.TMConfig,
+		conf:     conf,
+		rootDir:  rootDir,
+		storeApp: storeApp,
+		wrapApp:  wrap,
+		logger:   logger,
+	})
+	if err != nil {
+		return err
+	}
+	srvs.Stop()
+
+	if !checker.Done() {
+		return fmt.Errorf("ran %d of the %d blocks recorded", checker.Checked(), m.Height)
+	}
+	fmt.Printf("%d blocks recorded by %s committed with the same app hashes\n", m.Height, m.Release)
+	return nil
+}
+
+// compatConfig returns the config of the home rootDir running the blocks
+// recorded in dir: the settings of the recording node the app hash depends
+// on, and nothing listening but on the loopback
+func compatConfig(dir, rootDir string, m *compat.Manifest) (*emtConfig.UltronConfig, error) {
+	conf, err := emtConfig.LoadConfig(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	genesis, err := ioutil.ReadFile(filepath.Join(dir, compat.GenesisFile))
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(conf.TMConfig.GenesisFile(), genesis, 0644); err != nil {
+		return nil, err
+	}
+	ethGenesis, err := emtUtils.ParseGenesisOrDefault(filepath.Join(dir, compat.EthGenesisFile))
+	if err != nil {
+		return nil, err
+	}
+
+	conf.EMConfig.EthChainId = uint(ethGenesis.Config.ChainId.Uint64())
+	conf.EMConfig.RPCEnabledFlag = false
+	conf.EMConfig.WSEnabledFlag = false
+	conf.EMConfig.IPCPath = ""
+	conf.EMConfig.ValidatorMetricsAddr = ""
+	conf.TMConfig.RPC.ListenAddress = "tcp://127.0.0.1:0"
+	conf.TMConfig.P2P.ListenAddress = "tcp://127.0.0.1:0"
+	conf.P2PConfig.MempoolSync = false
+	conf.DiskConfig.WatchInterval = 0
+	conf.BaseConfig.Replica = false
+	conf.TestConfig.DevMode = false
+	conf.TestConfig.ManualMining = false
+	return conf, nil
+}
+
+// genesisValidators returns the validators of the genesis as tendermint
+// gives them to the app
+func genesisValidators(genDoc *GenesisDoc) []*abcitypes.Validator {
+	vals := make([]*types.Validator, len(genDoc.Validators))
+	for i, v := range genDoc.Validators {
+		vals[i] = types.NewValidator(v.PubKey, v.Power)
+	}
+	return types.TM2PB.Validators(types.NewValidatorSet(vals))
+}
+
+// compatClientCreator checks the app hash of every block committed against
+// the recorded one, failing the start of the node at the first mismatch.
+// Tendermint starts the app of a home without it with the validators of its
+// last state, the genesis ones are given instead.
+type compatClientCreator struct {
+	proxy.ClientCreator
+	checker    *compat.Checker
+	validators []*abcitypes.Validator
+}
+
+func (c compatClientCreator) NewABCIClient() (abcicli.Client, error) {
+	client, err := c.ClientCreator.NewABCIClient()
+	if err != nil {
+		return nil, err
+	}
+	return compatClient{client, c.checker, c.validators}, nil
+}
+
+type compatClient struct {
+	abcicli.Client
+	checker    *compat.Checker
+	validators []*abcitypes.Validator
+}
+
+func (c compatClient) InitChainSync(req abcitypes.RequestInitChain) (*abcitypes.ResponseInitChain, error) {
+	req.Validators = c.validators
+	return c.Client.InitChainSync(req)
+}
+
+func (c compatClient) CommitSync() (*abcitypes.ResponseCommit, error) {
+	res, err := c.Client.CommitSync()
+	if err != nil {
+		return res, err
+	}
+	if err := c.checker.Commit(res.Data); err != nil {
+		logger.Error("App hash differs from the recorded one", "err", err)
+		return res, err
+	}
+	return res, nil
+}
@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth"
+
+	"github.com/dora/ultron/const"
+)
+
+/**
+ * Test Smart Contract Contents:
+ *
+ *  	pragma solidity ^0.4.16;
+ *
+ *  	contract CharityBank {
+ *  	    address public owner;
+ *  	    uint256 public fund;
+ *
+ *  	    constructor() public {owner = msg.sender; }
+ *
+ *  	    function close() public { if (msg.sender == owner) selfdestruct(owner); }
+ *
+ *  	    function deposit() payable public {
+ *  	        require(msg.value > 0);
+ *  	        fund += msg.value;
+ *  	    }
+ *
+ *  	    function withdraw(uint256 amount) public {
+ *  	        require(amount < fund);
+ *  	        fund -= amount;
+ *  	        address people = msg.sender;
+ *  	        people.transfer(amount);
+ *  	    }
+ *  	}
+**/
+// compiled code
+var compiledContract = "608060405234801561001057600080fd5b50336000806101000a81548173ffff" +
+	"ffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffff" +
+	"ffffffffffffffffffffffff1602179055506102bb806100606000396000f300" +
+	"60806040526004361061006d576000357c010000000000000000000000000000" +
+	"0000000000000000000000000000900463ffffffff1680632e1a7d4d14610072" +
+	"57806343d726d61461009f5780638da5cb5b146100b6578063b60d4288146101" +
+	"0d578063d0e30db014610138575b600080fd5b34801561007e57600080fd5b50" +
+	"61009d60048036038101908080359060200190929190505050610142565b005b" +
+	"3480156100ab57600080fd5b506100b46101b2565b005b3480156100c2576000" +
+	"80fd5b506100cb610243565b604051808273ffffffffffffffffffffffffffff" +
+	"ffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260" +
+	"200191505060405180910390f35b34801561011957600080fd5b506101226102" +
+	"68565b6040518082815260200191505060405180910390f35b61014061026e56" +
+	"5b005b60006001548210151561015457600080fd5b8160016000828254039250" +
+	"50819055503390508073ffffffffffffffffffffffffffffffffffffffff1661" +
+	"08fc839081150290604051600060405180830381858888f19350505050158015" +
+	"6101ad573d6000803e3d6000fd5b505050565b6000809054906101000a900473" +
+	"ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffff" +
+	"ffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffff" +
+	"ff161415610241576000809054906101000a900473ffffffffffffffffffffff" +
+	"ffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16" +
+	"ff5b565b6000809054906101000a900473ffffffffffffffffffffffffffffff" +
+	"ffffffffff1681565b60015481565b60003411151561027d57600080fd5b3460" +
+	"01600082825401925050819055505600a165627a7a72305820a20d1041740fd7" +
+	"e0fb9760f42ce8da0d175635f604134a859ca0ccfb327193580029"
+
+// function hash
+var (
+	deposit  = "d0e30db0" //: "deposit()",
+	withdraw = "2e1a7d4d" //: "withdraw(uint256)"
+)
+
+func newContract(nonce uint64, gaslimit *big.Int, key *ecdsa.PrivateKey, contractStr string) *types.Transaction {
+	contractData := common.Hex2Bytes(contractStr)
+
+	contract, _ :=
+		types.SignTx(
+			types.NewContractCreation(nonce, big.NewInt(0), gaslimit, gasprice, contractData),
+			types.NewEIP155Signer(constant.ChainId),
+			key)
+	return contract
+}
+
+func getContractAddress(txHash common.Hash, eth *eth.Ethereum) (common.Address, error) {
+	receipt, err := getTransactionReceipt(txHash, eth)
+	if (err != nil || receipt.ContractAddress == common.Address{}) {
+		return common.Address{}, fmt.Errorf("Contract address not found for transaction" + txHash.Hex())
+	}
+	return receipt.ContractAddress, nil
+}
+
+func callContract(nonce uint64, gaslimit *big.Int, key *ecdsa.PrivateKey, contract common.Address, callCode string, amount *big.Int, args []byte) *types.Transaction {
+	callData := append(common.Hex2Bytes(callCode), args...)
+
+	contractCallTx, _ :=
+		types.SignTx(
+			types.NewTransaction(nonce, contract, amount, gaslimit, gasprice, callData),
+			types.NewEIP155Signer(constant.ChainId),
+			key)
+	return contractCallTx
+}
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/tendermint/tmlibs/cli"
+
+	"github.com/dora/ultron/app"
+	"github.com/dora/ultron/appwal"
+	"github.com/dora/ultron/dbverify"
+)
+
+var (
+	VerifyJSONFlag = "json"
+	WALHeightFlag  = "height"
+)
+
+// verifyCacheSize is the cache of the databases verified, in MB for the
+// chaindata and in nodes for the app state
+const verifyCacheSize = 256
+
+// GetDBCmd - initialize the db command and its subcommands
+func GetDBCmd() *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Check the databases of a stopped node",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+	}
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the hashes and the references of the state trie and of the app state at the latest height",
+		RunE:  dbVerifyCmd,
+	}
+	verifyCmd.Flags().Bool(VerifyJSONFlag, false, "Print the report as json")
+
+	walCmd := &cobra.Command{
+		Use:   "wal",
+		Short: "Print the records of the app wal, the txs delivered and the app hashes of the last blocks, as json lines",
+		RunE:  dbWALCmd,
+	}
+	walCmd.Flags().Int64(WALHeightFlag, 0, "Only print the records of this block")
+
+	dbCmd.AddCommand(verifyCmd, walCmd)
+	return dbCmd
+}
+
+func dbVerifyCmd(cmd *cobra.Command, args []string) error {
+	rootDir := viper.GetString(cli.HomeFlag)
+	report := new(dbverify.Report)
+
+	chainDb, err := ethdb.NewLDBDatabase(filepath.Join(rootDir, "ultron/chaindata"), verifyCacheSize, 256)
+	if err != nil {
+		return fmt.Errorf("opening the chaindata, is the node stopped? %v", err)
+	}
+	defer chainDb.Close()
+	st, storeDb, err := app.LoadState(path.Join(rootDir, "data", "merkleeyes.db"), verifyCacheSize*1000)
+	if err != nil {
+		return fmt.Errorf("opening the app state, is the node stopped? %v", err)
+	}
+	defer storeDb.Close()
+
+	logger.Info("Verifying the state trie")
+	if err := dbverify.VerifyHeadState(chainDb, report); err != nil {
+		return err
+	}
+	logger.Info("Verifying the app state", "version", st.LatestHeight())
+	dbverify.VerifyStore(st, report)
+	dbverify.VerifyBlockHash(chainDb, st, report)
+
+	if viper.GetBool(VerifyJSONFlag) {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+	} else {
+		fmt.Printf("state of block %d, root %s: %d nodes, %d accounts, %d storage slots, %d codes\n",
+			report.Block, report.StateRoot, report.Nodes, report.Accounts, report.Slots, report.Codes)
+		fmt.Printf("app state version %d, root %s: %d keys\n", report.StoreVersion, report.StoreRoot, report.StoreKeys)
+		for _, issue := range report.Issues {
+			fmt.Println("corrupt:", issue)
+		}
+		for _, s := range report.Suggestions() {
+			fmt.Println(s)
+		}
+	}
+	if !report.OK() {
+		return fmt.Errorf("%d corrupt ranges", len(report.Issues))
+	}
+	return nil
+}
+
+func dbWALCmd(cmd *cobra.Command, args []string) error {
+	rootDir := viper.GetString(cli.HomeFlag)
+	records, err := appwal.Read(path.Join(rootDir, "data", appwal.File))
+	if err != nil {
+		return err
+	}
+	height := viper.GetInt64(WALHeightFlag)
+	enc := json.NewEncoder(os.Stdout)
+	for _, r := range records {
+		if height != 0 && r.Height != height {
+			continue
+		}
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
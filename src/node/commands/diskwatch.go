@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/tendermint/tendermint/node"
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/dora/ultron/alerts"
+	"github.com/dora/ultron/app"
+	"github.com/dora/ultron/backend"
+	emtUtils "github.com/dora/ultron/backend/cmd/utils"
+	"github.com/dora/ultron/diskwatch"
+	emtConfig "github.com/dora/ultron/node/config"
+)
+
+// startDiskWatch watches the growth of the chaindata and of the app state
+// as [disk] sets, posting the disk filling up to the webhooks of alerts if
+// any. It returns nil when the watch is off.
+func startDiskWatch(ctx *cli.Context, conf emtConfig.DiskConfig, rootDir string, storeApp *app.StoreApp,
+	b *backend.Backend, tmNode *node.Node, nodeAlerts *nodeAlerts) *diskwatch.Watcher {
+	if conf.WatchInterval <= 0 {
+		return nil
+	}
+
+	chainDb := diskwatch.Database{
+		Name: "chaindata",
+		Dir:  filepath.Join(emtUtils.MakeDataDir(ctx), "ultron/chaindata"),
+	}
+	if ldb, ok := b.Ethereum().ChainDb().(*ethdb.LDBDatabase); ok {
+		chainDb.Compact = func() error { return ldb.LDB().CompactRange(util.Range{}) }
+	}
+	dbs := []diskwatch.Database{
+		chainDb,
+		{Name: "merkleeyes", Dir: path.Join(rootDir, "data", "merkleeyes.db"), Compact: storeApp.CompactDB},
+	}
+
+	alert := func(format string, args ...interface{}) {
+		log.Warn("Disk filling up: " + fmt.Sprintf(format, args...))
+	}
+	if nodeAlerts != nil {
+		alert = func(format string, args ...interface{}) {
+			nodeAlerts.notifier.Fire(alerts.DiskFilling, tmNode.BlockStore().Height(), format, args...)
+		}
+	}
+
+	w := diskwatch.NewWatcher(diskwatch.Config{
+		Interval:      conf.WatchInterval,
+		CompactGrowth: conf.CompactGrowth << 20,
+		AlertHorizon:  conf.AlertHorizon,
+	}, dbs, alert)
+	w.Start()
+	return w
+}
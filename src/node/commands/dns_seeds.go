@@ -0,0 +1,101 @@
+package commands
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/tendermint/tendermint/p2p"
+	tmlog "github.com/tendermint/tmlibs/log"
+
+	emtConfig "github.com/dora/ultron/node/config"
+)
+
+// nolint
+var (
+	DNSSeedsFlag = "p2p.dns-seeds"
+)
+
+// dnsSeeder resolves the seeds listed in the TXT records of domains, so the
+// seeds of a network can move without its nodes being reconfigured. Each
+// record lists seeds as "id@host:port", separated by commas or spaces.
+type dnsSeeder struct {
+	domains []string
+	refresh time.Duration
+	seen    map[string]bool
+	quit    chan struct{}
+	logger  tmlog.Logger
+}
+
+// newDNSSeeder returns the seeder of conf, nil if it has no domains.
+func newDNSSeeder(conf emtConfig.P2PConfig, logger tmlog.Logger) *dnsSeeder {
+	domains := splitList(conf.DNSSeeds)
+	if len(domains) == 0 {
+		return nil
+	}
+	return &dnsSeeder{
+		domains: domains,
+		refresh: conf.DNSSeedsRefresh,
+		seen:    make(map[string]bool),
+		quit:    make(chan struct{}),
+		logger:  logger.With("module", "dns-seeds"),
+	}
+}
+
+// resolve returns the seeds of the domains not resolved before. A domain
+// failing to resolve is logged and skipped.
+func (s *dnsSeeder) resolve() []string {
+	var seeds []string
+	for _, domain := range s.domains {
+		records, err := net.LookupTXT(domain)
+		if err != nil {
+			s.logger.Error("Failed to resolve dns seeds", "domain", domain, "err", err)
+			continue
+		}
+		for _, record := range records {
+			for _, seed := range splitList(record) {
+				if !s.seen[seed] {
+					s.seen[seed] = true
+					seeds = append(seeds, seed)
+				}
+			}
+		}
+	}
+	return seeds
+}
+
+// run resolves the domains again every refresh, dialing the new seeds with
+// sw, until stop.
+func (s *dnsSeeder) run(sw *p2p.Switch) {
+	if s.refresh <= 0 {
+		return
+	}
+	ticker := time.NewTicker(s.refresh)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			seeds := s.resolve()
+			if len(seeds) == 0 {
+				continue
+			}
+			s.logger.Info("Dialing new dns seeds", "seeds", seeds)
+			if err := sw.DialPeersAsync(nil, seeds, false); err != nil {
+				s.logger.Error("Failed to dial dns seeds", "err", err)
+			}
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+func (s *dnsSeeder) stop() {
+	close(s.quit)
+}
+
+// splitList splits a list separated by commas or spaces
+func splitList(list string) []string {
+	return strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
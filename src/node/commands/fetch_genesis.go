@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cmn "github.com/tendermint/tmlibs/common"
+)
+
+// nolint
+var (
+	GenesisURLFlag      = "genesis-url"
+	GenesisChecksumFlag = "genesis-checksum"
+)
+
+const (
+	genesisFetchTimeout = 2 * time.Minute
+	maxGenesisSize      = 256 << 20
+)
+
+// fetchGenesis downloads the genesis of the network at url to file, unless
+// file already has it. checksum is the hex sha256 of the genesis, as the
+// network publishes it. A genesis not matching checksum is only replaced on
+// the first start, e.g. the one written by init, never once the node has
+// blocks of its chain.
+func fetchGenesis(file, url, checksum string, firstStart bool) error {
+	want, err := parseChecksum(checksum)
+	if err != nil {
+		return err
+	}
+
+	if b, err := ioutil.ReadFile(file); err == nil {
+		if sum := sha256.Sum256(b); hex.EncodeToString(sum[:]) == want {
+			return nil
+		}
+		if !firstStart {
+			return errors.Errorf("%s doesn't match the checksum of %s, and the node already has blocks", file, url)
+		}
+	}
+
+	logger.Info("Downloading genesis", "url", url)
+	b, err := download(url)
+	if err != nil {
+		return errors.Wrap(err, "downloading genesis")
+	}
+	if sum := sha256.Sum256(b); hex.EncodeToString(sum[:]) != want {
+		return errors.Errorf("genesis at %s has checksum %x, expected %s", url, sum, want)
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return errors.Wrapf(err, "genesis at %s", url)
+	}
+	if err := cmn.WriteFileAtomic(file, b, 0644); err != nil {
+		return err
+	}
+	logger.Info("Wrote genesis", "path", file, "sha256", want)
+	return nil
+}
+
+// parseChecksum reads a hex sha256, optionally prefixed with "sha256:"
+func parseChecksum(checksum string) (string, error) {
+	checksum = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(checksum), "sha256:"))
+	if checksum == "" {
+		return "", errors.Errorf("--%s needs --%s, the sha256 of the genesis", GenesisURLFlag, GenesisChecksumFlag)
+	}
+	if b, err := hex.DecodeString(checksum); err != nil || len(b) != sha256.Size {
+		return "", errors.Errorf("--%s must be a hex sha256: %s", GenesisChecksumFlag, checksum)
+	}
+	return checksum, nil
+}
+
+func download(url string) ([]byte, error) {
+	client := &http.Client{Timeout: genesisFetchTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s: %s", url, resp.Status)
+	}
+	b, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxGenesisSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > maxGenesisSize {
+		return nil, errors.Errorf("%s: genesis larger than %d bytes", url, maxGenesisSize)
+	}
+	return b, nil
+}
@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchGenesis(t *testing.T) {
+	genesis := []byte(`{"chain_id":"ultron-test"}`)
+	sum := sha256.Sum256(genesis)
+	checksum := hex.EncodeToString(sum[:])
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(genesis) // nolint: errcheck
+	}))
+	defer srv.Close()
+
+	dir, err := ioutil.TempDir("", "genesis")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	file := path.Join(dir, "genesis.json")
+
+	assert.NotNil(t, fetchGenesis(file, srv.URL, "", true), "no checksum")
+	assert.NotNil(t, fetchGenesis(file, srv.URL, hex.EncodeToString(make([]byte, 32)), true), "bad checksum")
+
+	// the default genesis of init is replaced on the first start only
+	require.Nil(t, ioutil.WriteFile(file, []byte(`{}`), 0644))
+	assert.NotNil(t, fetchGenesis(file, srv.URL, checksum, false))
+	require.Nil(t, fetchGenesis(file, srv.URL, "sha256:"+checksum, true))
+	b, err := ioutil.ReadFile(file)
+	require.Nil(t, err)
+	assert.Equal(t, genesis, b)
+
+	// and kept once it matches
+	srv.Close()
+	assert.Nil(t, fetchGenesis(file, srv.URL, checksum, false))
+}
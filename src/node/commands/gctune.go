@@ -0,0 +1,45 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/dora/ultron/gctune"
+	emtConfig "github.com/dora/ultron/node/config"
+)
+
+// tuneGC sets up the garbage collector as [gc] sets, on the defaults of
+// the profile, which follows replica unless set.
+func tuneGC(conf emtConfig.GCConfig, replica bool) error {
+	var tune gctune.Config
+	switch conf.Profile {
+	case "validator":
+		tune = gctune.Validator()
+	case "rpc":
+		tune = gctune.RPC()
+	case "":
+		tune = gctune.Validator()
+		if replica {
+			tune = gctune.RPC()
+		}
+	default:
+		return fmt.Errorf("unknown gc profile %q", conf.Profile)
+	}
+
+	if conf.GCPercent != 0 {
+		tune.GCPercent = conf.GCPercent
+	}
+	switch {
+	case conf.Ballast > 0:
+		tune.Ballast = uint64(conf.Ballast) << 20
+	case conf.Ballast < 0:
+		tune.Ballast = 0
+	}
+	switch {
+	case conf.MemoryLimit > 0:
+		tune.SoftLimit = uint64(conf.MemoryLimit) << 20
+	case conf.MemoryLimit < 0:
+		tune.SoftLimit = 0
+	}
+	gctune.Apply(tune)
+	return nil
+}
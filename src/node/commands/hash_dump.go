@@ -0,0 +1,114 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/tendermint/tendermint/node"
+
+	"github.com/dora/ultron/app"
+	"github.com/dora/ultron/hashdump"
+)
+
+// hashDumpInterval is how often the block proposed next is checked for an
+// app hash the node disagrees with
+const hashDumpInterval = 5 * time.Second
+
+// appHashWatch dumps the last block of a node once the block proposed for
+// the next height carries another app hash, see hashdump
+type appHashWatch struct {
+	quit chan struct{}
+}
+
+// watchAppHash watches tmNode, dumping the blocks of baseApp to rootDir
+func watchAppHash(rootDir, moniker string, tmNode *node.Node, baseApp *app.BaseApp) *appHashWatch {
+	w := &appHashWatch{quit: make(chan struct{})}
+	go func() {
+		ticker := time.NewTicker(hashDumpInterval)
+		defer ticker.Stop()
+		var dumped int64
+		for {
+			select {
+			case <-ticker.C:
+			case <-w.quit:
+				return
+			}
+			proposed, mismatch := proposedAppHash(tmNode)
+			if !mismatch {
+				continue
+			}
+			var r *hashdump.Report
+			baseApp.PauseBlocks(func() error { // nolint: errcheck
+				if baseApp.CommittedHeight() != dumped {
+					r = baseApp.DumpBlock(proposed)
+				}
+				return nil
+			})
+			if r == nil {
+				continue
+			}
+			dumped = r.Height
+			r.Node = moniker
+			file := filepath.Join(rootDir, fmt.Sprintf("apphash-dump-%d.json", r.Height))
+			if err := hashdump.Write(file, r); err != nil {
+				logger.Error("Failed to write the app hash dump", "height", r.Height, "err", err)
+				continue
+			}
+			logger.Error("App hash of the block proposed differs from ours, dumped our last block: compare it "+
+				"with the dump of a node agreeing with the proposal, see ultron debug compare-dumps",
+				"height", r.Height, "app_hash", fmt.Sprintf("%X", r.AppHash), "proposed", fmt.Sprintf("%X", proposed),
+				"file", file)
+		}
+	}()
+	return w
+}
+
+func (w *appHashWatch) stop() {
+	close(w.quit)
+}
+
+// GetDebugCmd - initialize the debug command and its subcommands
+func GetDebugCmd() *cobra.Command {
+	debugCmd := &cobra.Command{
+		Use:   "debug",
+		Short: "Look into the trouble of a node",
+		Run:   func(cmd *cobra.Command, args []string) { cmd.Help() },
+	}
+	compareCmd := &cobra.Command{
+		Use:   "compare-dumps <dump> <dump>",
+		Short: "Compare the app hash dumps of two nodes, written as their app hashes diverged",
+		Args:  cobra.ExactArgs(2),
+		RunE:  debugCompareDumpsCmd,
+	}
+	debugCmd.AddCommand(compareCmd)
+	return debugCmd
+}
+
+func debugCompareDumpsCmd(cmd *cobra.Command, args []string) error {
+	a, err := hashdump.Read(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := hashdump.Read(args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Printf("a: %s of %s, height %d\nb: %s of %s, height %d\n", args[0], a.Node, a.Height, args[1], b.Node, b.Height)
+	for _, r := range []*hashdump.Report{a, b} {
+		for _, e := range r.Errors {
+			fmt.Fprintf(os.Stderr, "%s: incomplete: %s\n", r.Node, e)
+		}
+	}
+	diffs := hashdump.Compare(a, b)
+	if len(diffs) == 0 {
+		fmt.Println("no difference")
+		return nil
+	}
+	for _, d := range diffs {
+		fmt.Println(d)
+	}
+	return nil
+}
@@ -2,14 +2,17 @@ package commands
 
 import (
 	"encoding/json"
+	"fmt"
 	"math/big"
 	"os"
 	"path/filepath"
 
+	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"gopkg.in/urfave/cli.v1"
 
-	ethUtils "github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
@@ -17,12 +20,16 @@ import (
 	cmn "github.com/tendermint/tmlibs/common"
 
 	emtUtils "github.com/dora/ultron/backend/cmd/utils"
+	ultronGenesis "github.com/dora/ultron/genesis"
+	emtConfig "github.com/dora/ultron/node/config"
 )
 
 var (
 	FlagChainID = "chain-id"
 	DockerMode  = "docker"
 	DockerNodeID = "docker-id"
+	FlagAlloc      = "alloc"
+	FlagAllocTotal = "alloc-total"
 )
 
 var InitCmd = GetInitCmd()
@@ -36,6 +43,8 @@ func GetInitCmd() *cobra.Command {
 	initCmd.Flags().String(FlagChainID, "local", "Chain ID")
 	initCmd.Flags().Int(DockerMode, 0, "Docker Cluster Size")
 	initCmd.Flags().Int(DockerNodeID, 0, "Docker Cluster ID, A int ID Less Equal DockerMode")
+	initCmd.Flags().String(FlagAlloc, "", "Add the balances of this .csv (address,balance rows) or .json file to the genesis alloc")
+	initCmd.Flags().String(FlagAllocTotal, "", "Wei the balances of --alloc must add up to")
 	return initCmd
 }
 
@@ -139,29 +148,148 @@ func initTendermintDockerMode(dockerCluster int, dockerID int) {
 	}
 }
 
+// InitTendermintNetwork gives every home a private validator and writes one
+// genesis listing all of them, the validators being paid to the default
+// accounts. Like the docker mode but for freshly generated keys.
+func InitTendermintNetwork(chainID string, confs []*emtConfig.UltronConfig) error {
+	if len(confs) > len(defaultAccounts) {
+		return fmt.Errorf("at most %d validators are supported, got %d", len(defaultAccounts), len(confs))
+	}
+
+	genDoc := GenesisDoc{
+		ChainID:                 chainID,
+		MaxVals:                 uint16(len(confs)),
+		ReserveRequirementRatio: "0.1",
+	}
+
+	for i, conf := range confs {
+		if err := cmn.EnsureDir(conf.TMConfig.RootDir, 0700); err != nil {
+			return err
+		}
+		privValidator := types.LoadOrGenPrivValidatorFS(conf.TMConfig.PrivValidatorFile())
+		genDoc.Validators = append(genDoc.Validators, GenesisValidator{
+			PubKey:    privValidator.GetPubKey(),
+			Power:     1000,
+			Address:   defaultAccounts[i],
+			Cut:       "0.5",
+			MaxAmount: 10000,
+		})
+	}
+
+	for _, conf := range confs {
+		if err := genDoc.SaveAs(conf.TMConfig.GenesisFile()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// InitHome initializes rootDir as a single validator node, like
+// `ultron node init` but without reading viper. An initialized home is
+// left as is.
+func InitHome(rootDir, chainID string) (*emtConfig.UltronConfig, error) {
+	return initHome(rootDir, chainID, "")
+}
+
+// initHome is InitHome writing the ethereum genesis at genesisPath, the
+// default one when empty. The tendermint genesis is written last, a home
+// failing to initialize is initialized again by the next call.
+func initHome(rootDir, chainID, genesisPath string) (*emtConfig.UltronConfig, error) {
+	conf, err := emtConfig.LoadConfig(rootDir)
+	if err != nil {
+		return nil, err
+	}
+	if cmn.FileExists(conf.TMConfig.GenesisFile()) {
+		return conf, nil
+	}
+
+	ctx, err := newEmtContext(conf)
+	if err != nil {
+		return nil, err
+	}
+	if err := initEthermintAt(ctx, conf, genesisPath, nil); err != nil {
+		return nil, err
+	}
+	if err := InitTendermintNetwork(chainID, []*emtConfig.UltronConfig{conf}); err != nil {
+		return nil, err
+	}
+	return conf, nil
+}
+
 func initEthermint(args []string) error {
 	genesisPath := ""
 	if len(args) > 0 {
 		genesisPath = args[0]
 	}
+	alloc, err := readAlloc(viper.GetString(FlagAlloc), viper.GetString(FlagAllocTotal))
+	if err != nil {
+		return err
+	}
+	return initEthermintAt(context, config, genesisPath, alloc)
+}
+
+// readAlloc reads the allocation of file, nil if file is empty, checking
+// that its balances add up to total if given
+func readAlloc(file, total string) (*ultronGenesis.Alloc, error) {
+	if file == "" {
+		return nil, nil
+	}
+	alloc, err := ultronGenesis.ReadAllocFile(file)
+	if err != nil {
+		return nil, errors.Wrap(err, file)
+	}
+	log.Info("Read genesis allocation", "file", file, "accounts", len(alloc.Balances), "total", alloc.Total)
+	if total == "" {
+		return alloc, nil
+	}
+	want, ok := math.ParseBig256(total)
+	if !ok {
+		return nil, errors.Errorf("invalid total %s", total)
+	}
+	if alloc.Total.Cmp(want) != 0 {
+		return nil, errors.Errorf("balances of %s add up to %v wei, not %v", file, alloc.Total, want)
+	}
+	return alloc, nil
+}
+
+// InitEthermint writes the default ethereum genesis block and keystore
+// under the home of conf
+func InitEthermint(conf *emtConfig.UltronConfig) error {
+	ctx, err := newEmtContext(conf)
+	if err != nil {
+		return err
+	}
+	return initEthermintAt(ctx, conf, "", nil)
+}
+
+func initEthermintAt(ctx *cli.Context, conf *emtConfig.UltronConfig, genesisPath string, alloc *ultronGenesis.Alloc) error {
 	genesis, err := emtUtils.ParseGenesisOrDefault(genesisPath)
 	if err != nil {
-		ethUtils.Fatalf("genesisJSON err: %v", err)
+		return errors.Wrap(err, "genesisJSON err")
 	}
 	// override ethermint's chain_id
-	genesis.Config.ChainId = new(big.Int).SetUint64(uint64(config.EMConfig.EthChainId))
+	genesis.Config.ChainId = new(big.Int).SetUint64(uint64(conf.EMConfig.EthChainId))
+	if alloc != nil {
+		if genesis.Alloc == nil {
+			genesis.Alloc = make(core.GenesisAlloc)
+		}
+		if err := alloc.MergeInto(genesis.Alloc); err != nil {
+			return err
+		}
+	}
 
-	ethermintDataDir := emtUtils.MakeDataDir(context)
+	ethermintDataDir := emtUtils.MakeDataDir(ctx)
 
 	chainDb, err := ethdb.NewLDBDatabase(filepath.Join(ethermintDataDir,
 		"ultron/chaindata"), 0, 0)
 	if err != nil {
-		ethUtils.Fatalf("could not open database: %v", err)
+		return errors.Wrap(err, "could not open database")
 	}
+	defer chainDb.Close()
 
 	_, hash, err := core.SetupGenesisBlock(chainDb, genesis)
 	if err != nil {
-		ethUtils.Fatalf("failed to write genesis block: %v", err)
+		return errors.Wrap(err, "failed to write genesis block")
 	}
 
 	log.Info("successfully wrote genesis block and/or chain rule set", "hash", hash)
@@ -172,7 +300,7 @@ func initEthermint(args []string) error {
 	// $ cp -r $GOPATH/src/github.com/tendermint/ethermint/setup/keystore $(DATADIR)
 	keystoreDir := filepath.Join(ethermintDataDir, "keystore")
 	if err := os.MkdirAll(keystoreDir, 0777); err != nil {
-		ethUtils.Fatalf("mkdirAll keyStoreDir: %v", err)
+		return errors.Wrap(err, "mkdirAll keyStoreDir")
 	}
 
 	for filename, content := range keystoreFilesMap {
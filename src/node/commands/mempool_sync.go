@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/tendermint/tendermint/node"
+	rpcClient "github.com/tendermint/tendermint/rpc/client"
+	tmTypes "github.com/tendermint/tendermint/types"
+
+	"github.com/dora/ultron/mempoolsync"
+)
+
+// localMempool is the tendermint mempool of the node, reached over the
+// in-process rpc client.
+type localMempool struct {
+	client *rpcClient.Local
+}
+
+var _ mempoolsync.Mempool = localMempool{}
+
+func newLocalMempool(n *node.Node) localMempool {
+	return localMempool{rpcClient.NewLocal(n)}
+}
+
+func (m localMempool) Pending() ([][]byte, error) {
+	res, err := m.client.UnconfirmedTxs()
+	if err != nil {
+		return nil, err
+	}
+	txs := make([][]byte, len(res.Txs))
+	for i, tx := range res.Txs {
+		txs[i] = tx
+	}
+	return txs, nil
+}
+
+func (m localMempool) Submit(tx []byte) error {
+	res, err := m.client.BroadcastTxSync(tx, tmTypes.RawTx)
+	if err != nil {
+		return err
+	}
+	if res.Code != 0 {
+		return fmt.Errorf("tx refused: %s", res.Log)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package commands
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/p2p/nat"
+	"github.com/pkg/errors"
+	tmcfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/p2p"
+	tmlog "github.com/tendermint/tmlibs/log"
+
+	emtConfig "github.com/dora/ultron/node/config"
+)
+
+// nolint
+var (
+	NATFlag             = "p2p.nat"
+	ExternalAddressFlag = "p2p.external-address"
+)
+
+// natTraversal maps the p2p port on the NAT gateway, UPnP or NAT-PMP, and
+// works out the address peers should dial, so a validator run at home can
+// be dialed.
+type natTraversal struct {
+	external string // host:port advertised to the peers, "" to keep the listen address
+	quit     chan struct{}
+}
+
+// newNATTraversal starts mapping the p2p port of cfg as conf asks. The
+// external address of conf wins over the one the gateway reports.
+func newNATTraversal(cfg *tmcfg.Config, conf emtConfig.P2PConfig, logger tmlog.Logger) (*natTraversal, error) {
+	t := &natTraversal{external: conf.ExternalAddress, quit: make(chan struct{})}
+	if t.external != "" {
+		if _, _, err := net.SplitHostPort(t.external); err != nil {
+			return nil, errors.Wrap(err, "p2p external address")
+		}
+	}
+
+	m, err := nat.Parse(conf.NAT)
+	if err != nil {
+		return nil, errors.Wrap(err, "p2p nat")
+	}
+	if m == nil {
+		return t, nil
+	}
+
+	port, err := listenPort(cfg.P2P.ListenAddress)
+	if err != nil {
+		return nil, err
+	}
+	// we map the port ourselves, with NAT-PMP too
+	cfg.P2P.SkipUPNP = true
+	go nat.Map(m, t.quit, "tcp", port, port, "ultron p2p")
+
+	if t.external == "" {
+		ip, err := m.ExternalIP()
+		if err != nil {
+			logger.Error("Failed to get the external ip, peers may not dial in", "nat", m, "err", err)
+			return t, nil
+		}
+		t.external = net.JoinHostPort(ip.String(), strconv.Itoa(port))
+	}
+	logger.Info("Mapped p2p port", "nat", m, "external", t.external)
+	return t, nil
+}
+
+// advertise makes sw tell the peers to dial the external address.
+func (t *natTraversal) advertise(sw *p2p.Switch) {
+	if t.external == "" {
+		return
+	}
+	nodeInfo := sw.NodeInfo()
+	nodeInfo.ListenAddr = t.external
+	sw.SetNodeInfo(nodeInfo)
+}
+
+// stop removes the port mapping.
+func (t *natTraversal) stop() {
+	close(t.quit)
+}
+
+// listenPort returns the port of a listen address such as tcp://0.0.0.0:46656
+func listenPort(laddr string) (int, error) {
+	if i := strings.Index(laddr, "://"); i >= 0 {
+		laddr = laddr[i+3:]
+	}
+	_, port, err := net.SplitHostPort(laddr)
+	if err != nil {
+		return 0, errors.Wrap(err, "p2p listen address")
+	}
+	return strconv.Atoi(port)
+}
@@ -12,14 +12,19 @@ import (
 	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/spf13/viper"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/eth"
+	abci "github.com/tendermint/abci/types"
 
 	rpcClient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/bench"
 )
 
 var (
@@ -28,6 +33,8 @@ var (
 	gaslimit        = big.NewInt(5e6)
 	genesisAccounts = 128
     accountInfoDB   = "simple-test-info.json" // a file to save some test info
+
+	inclusionTimeout = 50 * time.Second
 )
 
 func nextPower2(v int) int {
@@ -46,6 +53,8 @@ type TestAccount struct {
 	Balance    *big.Int       `json:"balance"`
 	PassPhrase string         `json:"password"`
 	Url        string         `json:"path"`
+
+	key *ecdsa.PrivateKey // of derived accounts, see derivedAccounts
 }
 
 func loadTestAccountsFromFile(rootDir, testDB string) ([]*TestAccount, bool) {
@@ -71,6 +80,13 @@ func initAccountsForPtxTest(srv *Services, rootDir string, n int) ([]*TestAccoun
 		fmt.Println("Init account should be power of 2, round to ", m)
 	}
 
+	// derived accounts are funded at genesis, see benchAccountsCmd
+	if seed := viper.GetString(DerivedAccountsFlag); seed != "" {
+		testAccounts := derivedAccounts(seed, n)
+		_, err := updateTestAccountBalance(srv, testAccounts)
+		return testAccounts, err
+	}
+
 	// init n accounts
 	testAccounts, ok := loadTestAccountsFromFile(rootDir, accountInfoDB)
 	if ok && len(testAccounts) >= n {
@@ -118,16 +134,18 @@ func transaction(nonce uint64, gaslimit *big.Int, key *ecdsa.PrivateKey, to comm
 	return tx
 }
 
-func makeTransaction(s *Services, from *common.Address, passwd string, tx *types.Transaction) *types.Transaction {
-	// Look up the wallet containing the requested signer
-	am := s.backend.Ethereum().AccountManager()
-
-	account := accounts.Account{Address: *from}
-
-	wallet, _ := am.Find(account)
+// resolveTo accepts a hex address or a registered name like "alice.ultron"
+func resolveTo(s *Services, to string) (common.Address, error) {
+	return s.backend.ResolveAddress(to)
+}
 
+func makeTransaction(s *Services, from *common.Address, passwd string, tx *types.Transaction) *types.Transaction {
+	// the key is unlocked once for all the txs of the account, see keySessions
 	chainID := big.NewInt((int64)(config.EMConfig.EthChainId))
-	signed, _ := wallet.SignTxWithPassphrase(account, passwd, tx, chainID)
+	signed, _ := s.keySessions.Signer(*from, passwd).SignTx(tx, chainID)
+	if signed != nil {
+		ethereum.RememberTx(signed)
+	}
 	return signed
 }
 
@@ -157,6 +175,43 @@ func waitTxsAsync(srv *Services, queuedTxHash []common.Hash) error {
 	return nil
 }
 
+// recordInclusion follows the chain head and records, for each tx of
+// queuedTxHash, the latency between sentAt and the block holding it.
+func recordInclusion(srv *Services, queuedTxHash []common.Hash, sentAt time.Time, rec *bench.Recorder) error {
+	heads := make(chan core.ChainHeadEvent, 16)
+	sub := srv.SubscribeChainHead(heads)
+	defer sub.Unsubscribe()
+
+	pending := make(map[common.Hash]bool, len(queuedTxHash))
+	for _, hash := range queuedTxHash {
+		// txs may have been included before the subscription
+		if _, err := getTransactionReceipt(hash, srv.backend.Ethereum()); err == nil {
+			rec.Included(time.Since(sentAt))
+			continue
+		}
+		pending[hash] = true
+	}
+
+	timeout := time.After(inclusionTimeout - time.Since(sentAt))
+	for len(pending) > 0 {
+		select {
+		case head := <-heads:
+			for _, tx := range head.Block.Transactions() {
+				if pending[tx.Hash()] {
+					rec.Included(time.Since(sentAt))
+					delete(pending, tx.Hash())
+				}
+			}
+		case err := <-sub.Err():
+			return err
+		case <-timeout:
+			return fmt.Errorf("ERROR: %d txs not included after %v", len(pending), inclusionTimeout)
+		}
+	}
+
+	return nil
+}
+
 func prepareTXs(srv *Services, txCnt, accOffset int, accounts []*TestAccount) (txsBytes [][]byte, txs types.Transactions, queuedTxHash []common.Hash, err error) {
 	pool := srv.backend.Ethereum().TxPool()
 	state := pool.State()
@@ -166,7 +221,7 @@ func prepareTXs(srv *Services, txCnt, accOffset int, accounts []*TestAccount) (t
 		nonce := state.GetNonce(accounts[i].Address)
 		key, _ := crypto.GenerateKey()
 		tx := transaction(nonce, gaslimit, key, accounts[(i + accOffset) % txCnt].Address, defaultAmount)
-		signedTx := makeTransaction(srv, &accounts[i].Address, accounts[i].PassPhrase, tx)
+		signedTx := signTx(srv, accounts[i], tx)
 		txs = append(txs, signedTx)
 		buf := new(bytes.Buffer)
 		signedTx.EncodeRLP(buf)
@@ -194,7 +249,7 @@ func prepareTXsAsync(srv *Services, txCnt int, accounts []*TestAccount) (chan ty
 				key, _ := crypto.GenerateKey()
 				tx := transaction(nonce, gaslimit, key, accounts[(i + accOffset) % txCnt].Address, defaultAmount)
 				// fmt.Println("i", i, "&accounts[i].Address", &accounts[i].Address, "accounts[i].PassPhrase", accounts[i].PassPhrase)
-				signedTx := makeTransaction(srv, &accounts[i].Address, accounts[i].PassPhrase, tx)
+				signedTx := signTx(srv, accounts[i], tx)
 				txs = append(txs, signedTx)
 			}
 
@@ -221,7 +276,27 @@ func createRemoteClientConnections(N int) []*rpcClient.HTTP {
 	return httpClients
 }
 
-func addTxsToHTTPClientAsync(httpClients []*rpcClient.HTTP, txs [][]byte) *sync.WaitGroup {
+// broadcastTx sends one tx. Without a recorder the tx is fired async; with
+// one it goes through BroadcastTxSync so the CheckTx verdict can be counted.
+func broadcastTx(remote *rpcClient.HTTP, tx []byte, rec *bench.Recorder) {
+	if rec == nil {
+		if _, err := remote.BroadcastTxAsync(tx); err != nil {
+			fmt.Println("ERROR: BroadcastTxAsync error:", err)
+		}
+		return
+	}
+
+	rec.Submitted(1)
+	res, err := remote.BroadcastTxSync(tx)
+	if err != nil {
+		fmt.Println("ERROR: BroadcastTxSync error:", err)
+		rec.Rejected(1)
+	} else if res.Code != abci.CodeTypeOK {
+		rec.Rejected(1)
+	}
+}
+
+func addTxsToHTTPClientAsync(httpClients []*rpcClient.HTTP, txs [][]byte, rec *bench.Recorder) *sync.WaitGroup {
 	remoteClientCnt := len(httpClients)
 	wg := sync.WaitGroup{}
 	txCntFromRemote := len(txs) / remoteClientCnt
@@ -236,10 +311,7 @@ func addTxsToHTTPClientAsync(httpClients []*rpcClient.HTTP, txs [][]byte) *sync.
 		go func () {
 			// frmAddr, _ :=tx.From(pool.Signer())
 			for j := 0; j < len(txsFromRemote); j++ {
-				_, err := remote.BroadcastTxAsync(txsFromRemote[j])
-				if (err != nil) {
-					fmt.Println("ERROR: BroadcastTxAsync error:", err)
-				}
+				broadcastTx(remote, txsFromRemote[j], rec)
 			}
 
 			wg.Done()
@@ -251,10 +323,7 @@ func addTxsToHTTPClientAsync(httpClients []*rpcClient.HTTP, txs [][]byte) *sync.
 		// handle rest if exist
 		txsFromRemote := txs[i:]
 		for j := 0; j < len(txsFromRemote); j++ {
-			_, err := httpClients[0].BroadcastTxAsync(txsFromRemote[j])
-			if (err != nil) {
-				fmt.Println("ERROR: BroadcastTxAsync error:", err)
-			}
+			broadcastTx(httpClients[0], txsFromRemote[j], rec)
 		}
 		wg.Done()
 	}()
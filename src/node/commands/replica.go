@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"path"
+
+	tmcfg "github.com/tendermint/tendermint/config"
+
+	"github.com/dora/ultron/app"
+)
+
+// replicaPrivValidator is the key of replicas, generated apart from the one
+// of the home so a replica never signs for a validator
+const replicaPrivValidator = "replica_priv_validator.json"
+
+// setReplica turns the node into a read-only replica: it follows the blocks
+// like any full node, but takes no txs and gossips none, so its resources
+// go to the RPC queries it serves.
+func setReplica(cfg *tmcfg.Config, ultronApp *app.BaseApp) {
+	cfg.Mempool.Broadcast = false
+	cfg.Mempool.Recheck = false
+	cfg.PrivValidator = path.Join("data", replicaPrivValidator)
+	ultronApp.SetReplica(true)
+}
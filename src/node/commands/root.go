@@ -12,6 +12,7 @@ import (
 	ethUtils "github.com/ethereum/go-ethereum/cmd/utils"
 	tmcli "github.com/tendermint/tmlibs/cli"
 	tmflags "github.com/tendermint/tmlibs/cli/flags"
+	dbm "github.com/tendermint/tmlibs/db"
 	"github.com/tendermint/tmlibs/log"
 
 	emtUtils "github.com/dora/ultron/backend/cmd/utils"
@@ -88,6 +89,7 @@ var (
 		ethUtils.RPCCORSDomainFlag,
 		ethUtils.RPCApiFlag,
 		ethUtils.IPCDisabledFlag,
+		ethUtils.IPCPathFlag,
 		ethUtils.WSEnabledFlag,
 		ethUtils.WSListenAddrFlag,
 		ethUtils.WSPortFlag,
@@ -103,11 +105,28 @@ var (
 		emtUtils.VerbosityFlag,
 		emtUtils.ConfigFileFlag,
 		emtUtils.WithTendermintFlag,
+		emtUtils.MemDBFlag,
+		emtUtils.FilterDirFlag,
+		emtUtils.RPCMetricsFlag,
+		emtUtils.RPCSlowQueryFlag,
+		emtUtils.ValidatorMetricsAddrFlag,
+		emtUtils.RPCLogsBlockRangeFlag,
+		emtUtils.RPCCallGasCapFlag,
+		emtUtils.RPCCallTimeoutFlag,
+		emtUtils.RPCDumpEntriesFlag,
+		emtUtils.RPCCommitTimeoutFlag,
+		emtUtils.SignerURLFlag,
+		emtUtils.SignerTokenFlag,
 	}
 )
 
-func setupEmtContext() error {
-	// create a new context to invoke ethermint
+func setupEmtContext() (err error) {
+	context, err = newEmtContext(config)
+	return err
+}
+
+// newEmtContext creates a new context to invoke ethermint with the settings of conf
+func newEmtContext(conf *emtConfig.UltronConfig) (*cli.Context, error) {
 	a := cli.NewApp()
 	a.Name = "ultron"
 	a.Flags = []cli.Flag{}
@@ -117,34 +136,54 @@ func setupEmtContext() error {
 
 	set, err := flagSet(a.Name, a.Flags)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	context = cli.NewContext(a, set, nil)
-
-	context.GlobalSet(ethUtils.DataDirFlag.Name, config.BaseConfig.RootDir)
-	context.GlobalSet(ethUtils.NetworkIdFlag.Name, strconv.Itoa(int(config.EMConfig.EthChainId)))
-	context.GlobalSet(emtUtils.VerbosityFlag.Name, strconv.Itoa(int(config.EMConfig.VerbosityFlag)))
-
-	context.GlobalSet(emtUtils.TendermintAddrFlag.Name, config.TMConfig.RPC.ListenAddress)
-
-	context.GlobalSet(emtUtils.ABCIAddrFlag.Name, config.EMConfig.ABCIAddr)
-	context.GlobalSet(emtUtils.ABCIProtocolFlag.Name, config.EMConfig.ABCIProtocol)
-
-	context.GlobalSet(ethUtils.RPCEnabledFlag.Name, strconv.FormatBool(config.EMConfig.RPCEnabledFlag))
-	context.GlobalSet(ethUtils.RPCApiFlag.Name, config.EMConfig.RPCApiFlag)
-
-	context.GlobalSet(ethUtils.RPCListenAddrFlag.Name, config.EMConfig.RPCListenAddrFlag)
-	context.GlobalSet(ethUtils.RPCPortFlag.Name, strconv.Itoa(int(config.EMConfig.RPCPortFlag)))
-	context.GlobalSet(ethUtils.RPCCORSDomainFlag.Name, config.EMConfig.RPCCORSDomainFlag)
+	ctx := cli.NewContext(a, set, nil)
+
+	ctx.GlobalSet(ethUtils.DataDirFlag.Name, conf.BaseConfig.RootDir)
+	ctx.GlobalSet(ethUtils.NetworkIdFlag.Name, strconv.Itoa(int(conf.EMConfig.EthChainId)))
+	ctx.GlobalSet(emtUtils.VerbosityFlag.Name, strconv.Itoa(int(conf.EMConfig.VerbosityFlag)))
+
+	ctx.GlobalSet(emtUtils.TendermintAddrFlag.Name, conf.TMConfig.RPC.ListenAddress)
+
+	ctx.GlobalSet(emtUtils.ABCIAddrFlag.Name, conf.EMConfig.ABCIAddr)
+	ctx.GlobalSet(emtUtils.ABCIProtocolFlag.Name, conf.EMConfig.ABCIProtocol)
+	ctx.GlobalSet(emtUtils.MemDBFlag.Name, strconv.FormatBool(conf.TMConfig.DBBackend == dbm.MemDBBackendStr))
+	ctx.GlobalSet(emtUtils.FilterDirFlag.Name, conf.EMConfig.FilterDir)
+	ctx.GlobalSet(emtUtils.RPCMetricsFlag.Name, strconv.FormatBool(conf.EMConfig.RPCMetrics))
+	ctx.GlobalSet(emtUtils.RPCSlowQueryFlag.Name, conf.EMConfig.RPCSlowQuery.String())
+	ctx.GlobalSet(emtUtils.ValidatorMetricsAddrFlag.Name, conf.EMConfig.ValidatorMetricsAddr)
+	ctx.GlobalSet(emtUtils.RPCLogsBlockRangeFlag.Name, strconv.FormatUint(conf.EMConfig.RPCLogsBlockRange, 10))
+	ctx.GlobalSet(emtUtils.RPCCallGasCapFlag.Name, strconv.FormatUint(conf.EMConfig.RPCCallGasCap, 10))
+	ctx.GlobalSet(emtUtils.RPCCallTimeoutFlag.Name, conf.EMConfig.RPCCallTimeout.String())
+	ctx.GlobalSet(emtUtils.RPCDumpEntriesFlag.Name, strconv.FormatUint(conf.EMConfig.RPCDumpEntries, 10))
+	ctx.GlobalSet(emtUtils.RPCCommitTimeoutFlag.Name, conf.EMConfig.RPCCommitTimeout.String())
+	ctx.GlobalSet(emtUtils.SignerURLFlag.Name, conf.EMConfig.SignerURL)
+	ctx.GlobalSet(emtUtils.SignerTokenFlag.Name, conf.EMConfig.SignerToken)
+
+	ctx.GlobalSet(ethUtils.RPCEnabledFlag.Name, strconv.FormatBool(conf.EMConfig.RPCEnabledFlag))
+	ctx.GlobalSet(ethUtils.RPCApiFlag.Name, conf.EMConfig.RPCApiFlag)
+
+	ctx.GlobalSet(ethUtils.RPCListenAddrFlag.Name, conf.EMConfig.RPCListenAddrFlag)
+	ctx.GlobalSet(ethUtils.RPCPortFlag.Name, strconv.Itoa(int(conf.EMConfig.RPCPortFlag)))
+	ctx.GlobalSet(ethUtils.RPCCORSDomainFlag.Name, conf.EMConfig.RPCCORSDomainFlag)
+
+	if conf.EMConfig.IPCPath == "" {
+		ctx.GlobalSet(ethUtils.IPCDisabledFlag.Name, "true")
+	} else {
+		ctx.GlobalSet(ethUtils.IPCPathFlag.Name, conf.EMConfig.IPCPath)
+	}
 
-	context.GlobalSet(ethUtils.WSEnabledFlag.Name, strconv.FormatBool(config.EMConfig.WSEnabledFlag))
-	context.GlobalSet(ethUtils.WSApiFlag.Name, config.EMConfig.WSApiFlag)
+	ctx.GlobalSet(ethUtils.WSEnabledFlag.Name, strconv.FormatBool(conf.EMConfig.WSEnabledFlag))
+	ctx.GlobalSet(ethUtils.WSApiFlag.Name, conf.EMConfig.WSApiFlag)
+	ctx.GlobalSet(ethUtils.WSListenAddrFlag.Name, conf.EMConfig.WSListenAddrFlag)
+	ctx.GlobalSet(ethUtils.WSPortFlag.Name, strconv.Itoa(int(conf.EMConfig.WSPortFlag)))
 
-	if err := emtUtils.Setup(context); err != nil {
-		return err
+	if err := emtUtils.Setup(ctx); err != nil {
+		return nil, err
 	}
-	return nil
+	return ctx, nil
 }
 
 func flagSet(name string, flags []cli.Flag) (*flag.FlagSet, error) {
@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+
+	"gopkg.in/urfave/cli.v1"
+
+	ethUtils "github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/log"
+
+	emtUtils "github.com/dora/ultron/backend/cmd/utils"
+	"github.com/dora/ultron/rpcmetrics"
+)
+
+// newRPCMetricsProxy takes the http rpc address over when rpc_metrics is on,
+// moving the go-ethereum server to a loopback port, and passes the requests
+// on through rpcmetrics.Handler. It returns nil when the metrics are off.
+func newRPCMetricsProxy(ctx *cli.Context) (*http.Server, error) {
+	if !ctx.GlobalBool(ethUtils.RPCEnabledFlag.Name) || !ctx.GlobalBool(emtUtils.RPCMetricsFlag.Name) {
+		return nil, nil
+	}
+
+	addr := net.JoinHostPort(ctx.GlobalString(ethUtils.RPCListenAddrFlag.Name),
+		strconv.Itoa(ctx.GlobalInt(ethUtils.RPCPortFlag.Name)))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	// a free loopback port for go-ethereum
+	inner, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		listener.Close() // nolint: errcheck
+		return nil, err
+	}
+	port := inner.Addr().(*net.TCPAddr).Port
+	inner.Close()                                                // nolint: errcheck
+	ctx.GlobalSet(ethUtils.RPCListenAddrFlag.Name, "127.0.0.1")  // nolint: errcheck
+	ctx.GlobalSet(ethUtils.RPCPortFlag.Name, strconv.Itoa(port)) // nolint: errcheck
+
+	target := &url.URL{Scheme: "http", Host: net.JoinHostPort("127.0.0.1", strconv.Itoa(port))}
+	srv := &http.Server{
+		Handler: rpcmetrics.Handler(httputil.NewSingleHostReverseProxy(target),
+			ctx.GlobalDuration(emtUtils.RPCSlowQueryFlag.Name)),
+	}
+	go func() {
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error("RPC metrics proxy stopped", "err", err)
+		}
+	}()
+	log.Info("Counting RPC requests", "addr", addr, "server", target.Host)
+	return srv, nil
+}
@@ -2,80 +2,425 @@ package commands
 
 import (
 	"fmt"
-	"os"
+	"net/http"
+	"path"
 	"strings"
+	"time"
 
 	"gopkg.in/urfave/cli.v1"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	ethUtils "github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/console"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
+	abcicli "github.com/tendermint/abci/client"
 	abcitypes "github.com/tendermint/abci/types"
-	tcmd "github.com/tendermint/tendermint/cmd/tendermint/commands"
+	tmcfg "github.com/tendermint/tendermint/config"
 	"github.com/tendermint/tendermint/node"
 	"github.com/tendermint/tendermint/proxy"
 	"github.com/tendermint/tendermint/types"
+	dbm "github.com/tendermint/tmlibs/db"
+	tmlog "github.com/tendermint/tmlibs/log"
 
 	"github.com/dora/ultron/backend"
 	"github.com/dora/ultron/app"
 	emtUtils "github.com/dora/ultron/backend/cmd/utils"
 	"github.com/dora/ultron/backend/ethereum"
+	"github.com/dora/ultron/addressbook"
+	"github.com/dora/ultron/backup"
+	"github.com/dora/ultron/banlist"
+	"github.com/dora/ultron/dev"
+	"github.com/dora/ultron/diskwatch"
+	"github.com/dora/ultron/mempoolsync"
+	"github.com/dora/ultron/modules/emergency"
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/modules/stake"
+	emtConfig "github.com/dora/ultron/node/config"
+	"github.com/dora/ultron/ordering"
+	"github.com/dora/ultron/peerversion"
+	"github.com/dora/ultron/storeprune"
+	"github.com/dora/ultron/ultronclient"
 )
 
 type Services struct {
 	backend *backend.Backend
 	tmNode  *node.Node
+	emNode  *ethereum.Node
+	miner   *dev.Miner
+
+	dnsSeeder *dnsSeeder
+	nat       *natTraversal
+	rpcProxy  *http.Server
+	metrics   *http.Server
+	alerts    *nodeAlerts
+	diskWatch *diskwatch.Watcher
+	pruner    *storeprune.Pruner
+	hashWatch *appHashWatch
+
+	keySessions *ultronclient.Sessions // of the keystore, signing the test txs
+}
+
+// NewServices starts a full node with conf instead of the global viper
+// settings, so several nodes can share a process as long as their homes
+// and ports differ. The home must have been initialized, see InitHome.
+// With db_backend = "memdb" the app state is kept in memory.
+func NewServices(conf *emtConfig.UltronConfig) (*Services, error) {
+	return newNode(conf, logger)
+}
+
+func newNode(conf *emtConfig.UltronConfig, logger tmlog.Logger) (*Services, error) {
+	if err := tuneGC(conf.GCConfig, conf.BaseConfig.Replica); err != nil {
+		return nil, err
+	}
+	ctx, err := newEmtContext(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	rootDir := conf.BaseConfig.RootDir
+	storeApp, err := newStoreApp("ultron", conf, logger.With("module", "app", "home", rootDir))
+	if err != nil {
+		return nil, err
+	}
+
+	return newServices(serviceOptions{
+		ctx:      ctx,
+		tmConf:   &conf.TMConfig,
+		conf:     conf,
+		rootDir:  rootDir,
+		storeApp: storeApp,
+		logger:   logger,
+	})
+}
+
+// newStoreApp opens the app state under the home of conf, or in memory
+// with db_backend = "memdb"
+func newStoreApp(appName string, conf *emtConfig.UltronConfig, logger tmlog.Logger) (*app.StoreApp, error) {
+	rootDir := conf.BaseConfig.RootDir
+	if conf.TMConfig.DBBackend == dbm.MemDBBackendStr {
+		return app.NewMemStoreApp(appName, rootDir, logger)
+	}
+	dbName, cacheSize := path.Join(rootDir, "data", "merkleeyes.db"), int(params.Int64(params.StoreCacheSize))
+	if conf.BaseConfig.Archive {
+		return app.NewArchiveStoreApp(appName, dbName, cacheSize, logger)
+	}
+	// pruned in the background, see startStorePruner
+	history := conf.DiskConfig.RetainVersions
+	if conf.DiskConfig.PruneInterval > 0 {
+		history = 0
+	}
+	return app.NewStoreAppWithHistory(appName, dbName, cacheSize, history, logger)
+}
+
+// SubscribeNewTxs delivers the txs entering the tx pool to ch, so embedders
+// can follow the pool without polling it.
+func (s *Services) SubscribeNewTxs(ch chan<- core.TxPreEvent) event.Subscription {
+	return s.backend.SubscribeNewTxs(ch)
+}
+
+// SubscribeChainHead delivers every new head block to ch.
+func (s *Services) SubscribeChainHead(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return s.backend.SubscribeChainHead(ch)
+}
+
+// SubscribeDroppedTxs delivers the txs dropped from the tx pool after
+// tendermint rejected them to ch.
+func (s *Services) SubscribeDroppedTxs(ch chan<- backend.TxDroppedEvent) event.Subscription {
+	return s.backend.SubscribeDroppedTxs(ch)
+}
+
+// SubscribeJailings delivers the validators jailed for missing too many
+// blocks to ch, for operators to be alerted.
+func (s *Services) SubscribeJailings(ch chan<- stake.JailEvent) event.Subscription {
+	return s.backend.SubscribeJailings(ch)
+}
+
+// SubscribeParamChanges delivers the params changed on chain to ch.
+func (s *Services) SubscribeParamChanges(ch chan<- params.Change) event.Subscription {
+	return s.backend.SubscribeParamChanges(ch)
+}
+
+// SubscribeEmergencies delivers the tx classes paused and resumed by the
+// emergency council to ch, for operators to be alerted.
+func (s *Services) SubscribeEmergencies(ch chan<- emergency.Event) event.Subscription {
+	return s.backend.SubscribeEmergencies(ch)
+}
+
+// Backend returns the ethereum backend of the node
+func (s *Services) Backend() *backend.Backend {
+	return s.backend
+}
+
+// TMNode returns the tendermint node
+func (s *Services) TMNode() *node.Node {
+	return s.tmNode
+}
+
+// Stop shuts down the tendermint and the ethereum node
+func (s *Services) Stop() {
+	if s.miner != nil {
+		s.miner.Stop()
+	}
+	if s.dnsSeeder != nil {
+		s.dnsSeeder.stop()
+	}
+	s.nat.stop()
+	if s.rpcProxy != nil {
+		s.rpcProxy.Close() // nolint: errcheck
+	}
+	if s.metrics != nil {
+		s.metrics.Close() // nolint: errcheck
+	}
+	if s.alerts != nil {
+		s.alerts.stop()
+	}
+	if s.diskWatch != nil {
+		s.diskWatch.Stop()
+	}
+	if s.pruner != nil {
+		s.pruner.Stop()
+	}
+	s.hashWatch.stop()
+	s.tmNode.Stop()
+	s.tmNode.Wait()
+	if s.emNode != nil {
+		s.emNode.Stop()
+	}
 }
 
 func startServices(rootDir string, storeApp *app.StoreApp) (*Services, error) {
+	if err := tuneGC(config.GCConfig, config.BaseConfig.Replica); err != nil {
+		return nil, err
+	}
+	return newServices(serviceOptions{
+		ctx:      context,
+		tmConf:   &config.TMConfig,
+		conf:     config,
+		rootDir:  rootDir,
+		storeApp: storeApp,
+		logger:   logger,
+	})
+}
+
+// newMiner returns the miner holding block production when manual mining
+// is on, nil otherwise
+func newMiner(conf *emtConfig.UltronConfig) *dev.Miner {
+	if !conf.TestConfig.ManualMining {
+		return nil
+	}
+	return dev.NewMiner()
+}
+
+// serviceOptions are what newServices starts a node with.
+type serviceOptions struct {
+	ctx      *cli.Context
+	tmConf   *tmcfg.Config
+	conf     *emtConfig.UltronConfig // the settings of the node next to tendermint's
+	rootDir  string
+	storeApp *app.StoreApp
+	wrapApp  func(proxy.ClientCreator) proxy.ClientCreator // wraps the abci app, if set
+	logger   tmlog.Logger
+}
+
+func newServices(o serviceOptions) (_ *Services, err error) {
+	ctx, cfg, rootDir, storeApp, logger := o.ctx, o.tmConf, o.rootDir, o.storeApp, o.logger
+	p2pConf, mempoolConf := o.conf.P2PConfig, o.conf.MempoolConfig
+	miner, replica := newMiner(o.conf), o.conf.BaseConfig.Replica
+
+	// stop what was started if a later step fails, latest first
+	var cleanups []func()
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}()
+
+	// put the metrics in front of the rpc server before it listens
+	rpcProxy, err := newRPCMetricsProxy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if rpcProxy != nil {
+		cleanups = append(cleanups, func() { rpcProxy.Close() }) // nolint: errcheck
+	}
+
 	// Setup the go-ethereum node and start it
-	emNode := emtUtils.MakeFullNode(context)
-	startNode(context, emNode)
+	emNode, err := emtUtils.MakeFullNode(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cleanups = append(cleanups, func() { emNode.Stop() }) // nolint: errcheck
+	if err := startNode(ctx, emNode); err != nil {
+		return nil, err
+	}
 
 	// Fetch the registered service of this type
 	var backend *backend.Backend
 	if err := emNode.Service(&backend); err != nil {
-		ethUtils.Fatalf("ethereum backend service not running: %v", err)
+		return nil, fmt.Errorf("ethereum backend service not running: %v", err)
 	}
 
 	// In-proc RPC connection so ABCI.Query can be forwarded over the ethereum rpc
 	rpcClient, err := emNode.Attach()
 	if err != nil {
-		ethUtils.Fatalf("Failed to attach to the inproc geth: %v", err)
+		return nil, fmt.Errorf("Failed to attach to the inproc geth: %v", err)
 	}
 
 	// Create the ABCI app
 	ethApp, err := app.NewEthermintApplication(backend, rpcClient, nil)
 	if err != nil {
-		log.Warn(err.Error())
-		os.Exit(1)
+		return nil, err
 	}
 	ethApp.SetLogger(emtUtils.EthermintLogger().With("module", "vm"))
 
 	// Create Basecoin app
 	basecoinApp, err := createBaseCoinApp(rootDir, storeApp, ethApp, backend.Ethereum())
 	if err != nil {
-		log.Warn(err.Error())
-		os.Exit(1)
+		return nil, err
+	}
+
+	// the txs of the blocks are checked ahead of their run
+	blocks := new(blockTxs)
+	basecoinApp.SetBlockTxs(blocks.txs)
+
+	var papp proxy.ClientCreator = proxy.NewLocalClientCreator(basecoinApp)
+	if miner != nil {
+		// blocks follow each other right away once the miner lets them
+		cfg.Consensus.CreateEmptyBlocks = true
+		cfg.Consensus.SkipTimeoutCommit = true
+		papp = minerClientCreator{papp, miner}
+		backend.SetMiner(miner)
+	}
+	if o.wrapApp != nil {
+		papp = o.wrapApp(papp)
+	}
+	if o.conf.TestConfig.DevMode {
+		backend.SetClock(dev.NewClock())
+		backend.SetSnapshotter(basecoinApp)
+	}
+	if replica {
+		setReplica(cfg, basecoinApp)
+	}
+	backend.SetCheckpointer(func() (*backup.Checkpoint, error) {
+		return homeCheckpointer(rootDir, basecoinApp).Checkpoint(newCheckpointDir(rootDir))
+	})
+	basecoinApp.SetLaneReserve(cfg.Consensus.MaxBlockSizeTxs, mempoolConf.SystemReserve)
+	if mempoolConf.OrderingAudit {
+		auditor := ordering.NewAuditor(mempoolConf.OrderingTolerance)
+		basecoinApp.SetOrderingAuditor(auditor)
+		backend.SetOrderingAuditor(auditor)
+	}
+
+	// map the p2p port on the NAT gateway before tendermint listens on it
+	natTraversal, err := newNATTraversal(cfg, p2pConf, logger)
+	if err != nil {
+		return nil, err
+	}
+	cleanups = append(cleanups, natTraversal.stop)
+
+	// the seeds of the dns seeds join the configured ones
+	seeder := newDNSSeeder(p2pConf, logger)
+	if seeder != nil {
+		cfg.P2P.Seeds = strings.Join(append(splitList(cfg.P2P.Seeds), seeder.resolve()...), ",")
+	}
+
+	// banned peers are refused from the first connection on
+	bans, err := banlist.Load(path.Join(rootDir, "data", banlist.File))
+	if err != nil {
+		return nil, err
 	}
 
 	// Create & start tendermint node
-	tmNode, err := startTendermint(basecoinApp)
+	prepare := []func(*node.Node){func(n *node.Node) { bans.Attach(n.Switch()) }}
+	if miner != nil {
+		// the handshake replaying the stored blocks is over once the node
+		// is created
+		replayed := miner.Replay()
+		prepare = append(prepare, func(*node.Node) { replayed() })
+	}
+	var caps []string
+	if p2pConf.MempoolSync && !replica {
+		caps = append(caps, peerversion.MempoolSync)
+		prepare = append(prepare, func(n *node.Node) {
+			reactor := mempoolsync.NewReactor()
+			reactor.SetLogger(logger.With("module", "mempoolsync"))
+			reactor.SetMempool(newLocalMempool(n))
+			n.Switch().AddReactor("MEMPOOLSYNC", reactor)
+		})
+	}
+	if o.conf.TestConfig.CompactBlock {
+		caps = append(caps, peerversion.CompactBlocks)
+	}
+	if storeApp.Archive() {
+		caps = append(caps, peerversion.Archive)
+	}
+	version := peerversion.Local(caps...)
+	prepare = append(prepare, func(n *node.Node) {
+		reactor := peerversion.NewReactor(version)
+		reactor.SetLogger(logger.With("module", "peerversion"))
+		n.Switch().AddReactor("PEERVERSION", reactor)
+	})
+	tmNode, err := startTendermint(cfg, papp, blocks.dbProvider, logger, prepare...)
 	if err != nil {
-		log.Warn(err.Error())
-		os.Exit(1)
+		return nil, err
 	}
+	cleanups = append(cleanups, func() {
+		tmNode.Stop() // nolint: errcheck
+		tmNode.Wait()
+	})
 	backend.SetTMNode(tmNode)
+	backend.SetBanList(bans)
+	backend.SetAddressBook(addressbook.Open(path.Join(rootDir, addressbook.File)))
+	// tendermint sets the node info as it starts
+	nodeInfo := tmNode.Switch().NodeInfo()
+	peerversion.Announce(&nodeInfo, version)
+	tmNode.Switch().SetNodeInfo(nodeInfo)
+	natTraversal.advertise(tmNode.Switch())
+	if seeder != nil {
+		go seeder.run(tmNode.Switch())
+		cleanups = append(cleanups, seeder.stop)
+	}
 
-	return &Services{backend, tmNode}, nil
+	// the metrics are read from the app, served once tendermint runs it
+	metrics, err := newValidatorMetricsServer(ctx, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts := startAlerts(o.conf.AlertsConfig, cfg, tmNode, backend)
+	diskWatch := startDiskWatch(ctx, o.conf.DiskConfig, rootDir, storeApp, backend, tmNode, alerts)
+	pruner := startStorePruner(o.conf.DiskConfig, rootDir, basecoinApp, backend)
+	hashWatch := watchAppHash(rootDir, cfg.Moniker, tmNode, basecoinApp)
+
+	return &Services{backend: backend, tmNode: tmNode, emNode: emNode, miner: miner,
+		dnsSeeder: seeder, nat: natTraversal, rpcProxy: rpcProxy, metrics: metrics,
+		alerts: alerts, diskWatch: diskWatch, pruner: pruner, hashWatch: hashWatch, keySessions: newKeySessions(emNode)}, nil
+}
+
+// key sessions of the keystore: a bench signing thousands of txs decrypts
+// each key once rather than for every tx
+const (
+	keySessionTTL  = 30 * time.Minute
+	maxKeySessions = 1 << 14
+)
+
+func newKeySessions(stack *ethereum.Node) *ultronclient.Sessions {
+	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
+	return ultronclient.NewSessions(ultronclient.KeystoreUnlocker(ks), keySessionTTL, maxKeySessions)
 }
 
 // startNode copies the logic from go-ethereum
-func startNode(ctx *cli.Context, stack *ethereum.Node) {
-	emtUtils.StartNode(stack)
+func startNode(ctx *cli.Context, stack *ethereum.Node) error {
+	if err := emtUtils.StartNode(stack); err != nil {
+		return err
+	}
 
 	// Unlock any account specifically requested
 	ks := stack.AccountManager().Backends(keystore.KeyStoreType)[0].(*keystore.KeyStore)
@@ -95,7 +440,8 @@ func startNode(ctx *cli.Context, stack *ethereum.Node) {
 		// Create an chain state reader for self-derivation
 		rpcClient, err := stack.Attach()
 		if err != nil {
-			ethUtils.Fatalf("Failed to attach to self: %v", err)
+			log.Error("Failed to attach to self", "err", err)
+			return
 		}
 		stateReader := ethclient.NewClient(rpcClient)
 
@@ -125,6 +471,7 @@ func startNode(ctx *cli.Context, stack *ethereum.Node) {
 			}
 		}
 	}()
+	return nil
 }
 
 // tries unlocking the specified account a few times.
@@ -218,16 +565,39 @@ func ambiguousAddrRecovery(ks *keystore.KeyStore, err *keystore.AmbiguousAddrErr
 	return *match
 }
 
-func startTendermint(basecoinApp abcitypes.Application) (*node.Node, error) {
-	cfg, err := tcmd.ParseConfig()
+// minerClientCreator makes the consensus wait for the miner after every
+// commit. The wait happens outside the app lock, so CheckTx and queries are
+// served meanwhile.
+type minerClientCreator struct {
+	proxy.ClientCreator
+	miner *dev.Miner
+}
+
+func (c minerClientCreator) NewABCIClient() (abcicli.Client, error) {
+	client, err := c.ClientCreator.NewABCIClient()
 	if err != nil {
 		return nil, err
 	}
+	return minerClient{client, c.miner}, nil
+}
+
+type minerClient struct {
+	abcicli.Client
+	miner *dev.Miner
+}
 
-	var papp proxy.ClientCreator
-	if basecoinApp != nil {
-		papp = proxy.NewLocalClientCreator(basecoinApp)
-	} else {
+func (c minerClient) CommitSync() (*abcitypes.ResponseCommit, error) {
+	res, err := c.Client.CommitSync()
+	if err == nil {
+		c.miner.Committed()
+	}
+	return res, err
+}
+
+// startTendermint creates the tendermint node, runs prepare on it and starts it
+func startTendermint(cfg *tmcfg.Config, papp proxy.ClientCreator, dbProvider node.DBProvider, logger tmlog.Logger,
+	prepare ...func(*node.Node)) (*node.Node, error) {
+	if papp == nil {
 		papp = proxy.DefaultClientCreator(cfg.ProxyApp, cfg.ABCI, cfg.DBDir())
 	}
 
@@ -236,11 +606,14 @@ func startTendermint(basecoinApp abcitypes.Application) (*node.Node, error) {
 		types.LoadOrGenPrivValidatorFS(cfg.PrivValidatorFile()),
 		papp,
 		node.DefaultGenesisDocProviderFunc(cfg),
-		node.DefaultDBProvider,
+		dbProvider,
 		logger.With("module", "node"))
 	if err != nil {
 		return nil, err
 	}
+	for _, p := range prepare {
+		p(n)
+	}
 
 	err = n.Start()
 	if err != nil {
@@ -444,7 +444,7 @@ func deployContract(srv *Services, contractName string, contractHex string) (com
 }
 
 func TestBasicTokenContract(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 	defer srv.tmNode.Stop()
 
 	contractAddr, err := deployContract(srv, "DoraToken", doraTokenContract)
@@ -2,7 +2,6 @@ package commands
 
 import (
 	"bytes"
-	"crypto/ecdsa"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -16,16 +15,20 @@ import (
 	"testing"
 	"time"
 
-	"github.com/dora/ultron/app"
+	"github.com/dora/ultron/backend"
+	"github.com/dora/ultron/bench"
+	"github.com/dora/ultron/modules/names"
+	emtConfig "github.com/dora/ultron/node/config"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/eth"
 	"github.com/spf13/viper"
 	"github.com/tendermint/tmlibs/cli"
+	dbm "github.com/tendermint/tmlibs/db"
 )
 
 type TestService struct {
@@ -35,18 +38,33 @@ type TestService struct {
 }
 
 var (
-	rootDir         = "/tmp/.ultron"          //init folder first
+	rootDir         = ""                      // a fresh temp home unless -home is set
+	ephemeralHome   = false
 	to              = common.HexToAddress("0x4806202cd62b03be5f6681827d5329409c1e0cdd")
 	from            = common.HexToAddress("0x70ade99ba1966cab6584e90220b94154d4b58eb1")
 
 	// Define args flags.
 	pAccountNum = flag.Int("testAccountNumber", genesisAccounts,  "Generate account number.")
 	pTxScale = flag.Int("testTxScale", genesisAccounts * 2, "Scale of txs")
-	pRootDir = flag.String("home", rootDir, "Scale of txs")
+	pRootDir = flag.String("home", rootDir, "Home of the test node, empty for a fresh temp home")
+	pMemDB = flag.Bool("memdb", false, "Keep the test node databases in memory")
+	pManualMining = flag.Bool("manualMining", false, "Make blocks only when a test mines them")
+	pDevMode = flag.Bool("dev", false, "Run the test node as a dev chain")
+	pBenchReport = flag.String("benchReport", "", "Write benchmark results to this file (.json or .csv)")
+	pSoakDuration = flag.Duration("soakDuration", time.Minute, "Duration of the soak in TestLoopAddBasicTx")
+	pBenchProfile = flag.String("benchProfile", "mixed", "Workload profile: transfer, contract or mixed")
+	pBenchRatios = flag.String("benchRatios", "", "Override the profile ratios, e.g. deploy=1,deposit=3,withdraw=1")
 
 	// define large scale account num and tx scale
 	accountNum = genesisAccounts
 	txScale = genesisAccounts
+	benchReport = ""
+	soakDuration = time.Minute
+	benchProfile = "mixed"
+	benchRatios = ""
+	memDB = false
+	manualMining = false
+	devMode = false
 )
 
 func parseFlags() {
@@ -54,6 +72,31 @@ func parseFlags() {
 	txScale = *pTxScale
 	accountNum = *pAccountNum
 	rootDir = *pRootDir
+	benchReport = *pBenchReport
+	soakDuration = *pSoakDuration
+	benchProfile = *pBenchProfile
+	benchRatios = *pBenchRatios
+	memDB = *pMemDB
+	manualMining = *pManualMining
+	devMode = *pDevMode
+}
+
+// newBenchSession returns a session writing to -benchReport and fed with
+// the commit timings of srv.
+func newBenchSession(srv *Services) *bench.Session {
+	session := bench.NewSession(benchReport)
+	srv.backend.SetCommitObserver(session.Committed)
+	return session
+}
+
+func endBenchRound(t *testing.T, session *bench.Session) {
+	res, err := session.End()
+	checkErrs(t, err)
+	if res != nil {
+		t.Logf("%s: tps %.2f, p50/p95/p99 %.1f/%.1f/%.1f ms, reject rate %.4f, commit avg %.1f ms",
+			res.Name, res.TPS, res.LatencyP50Ms, res.LatencyP95Ms, res.LatencyP99Ms,
+			res.RejectRate, res.CommitAvgMs)
+	}
 }
 
 func SetupTestConfig(homeDir string) bool {
@@ -76,118 +119,62 @@ func SetupTestConfig(homeDir string) bool {
 	return true
 }
 
+// NewTestService starts the node shared by the tests. Without -home it
+// runs in a fresh temp home, so concurrent runs don't share state.
 func NewTestService() (*Services, error) {
 	parseFlags()
-	SetupTestConfig(rootDir)
-	preRunSetup(nil, nil)
-
-	// cmdName := "test"
-	appName := "test"
-	storeApp, err := app.NewStoreApp(
-		appName,
-		path.Join(rootDir, "data", "merkleeyes.db"),
-		EyesCacheSize,
-		logger.With("module", "app"))
+	if rootDir == "" {
+		home, err := ioutil.TempDir("", "ultron-test")
+		if err != nil {
+			return nil, err
+		}
+		rootDir, ephemeralHome = home, true
+	}
+	return newTestServiceAt(rootDir, func(conf *emtConfig.UltronConfig) {
+		if memDB {
+			conf.TMConfig.DBBackend = dbm.MemDBBackendStr
+		}
+		conf.TestConfig.ManualMining = manualMining
+		conf.TestConfig.DevMode = devMode
+	})
+}
+
+// newTestServiceAt initializes home if needed and starts a node in it, with
+// the config adjusted by setup.
+func newTestServiceAt(home string, setup func(conf *emtConfig.UltronConfig)) (*Services, error) {
+	SetupTestConfig(home)
+	conf, err := InitHome(home, "ultron-test")
 	if err != nil {
 		return nil, err
 	}
+	setup(conf)
+	if err := preRunSetup(nil, nil); err != nil {
+		return nil, err
+	}
+	config = conf
 
-	return startServices(rootDir, storeApp)
-}
-
-/**
- * Test Smart Contract Contents:
- *
- *  	pragma solidity ^0.4.16;
- *
- *  	contract CharityBank {
- *  	    address public owner;
- *  	    uint256 public fund;
- *
- *  	    constructor() public {owner = msg.sender; }
- *
- *  	    function close() public { if (msg.sender == owner) selfdestruct(owner); }
- *
- *  	    function deposit() payable public {
- *  	        require(msg.value > 0);
- *  	        fund += msg.value;
- *  	    }
- *
- *  	    function withdraw(uint256 amount) public {
- *  	        require(amount < fund);
- *  	        fund -= amount;
- *  	        address people = msg.sender;
- *  	        people.transfer(amount);
- *  	    }
- *  	}
-**/
-// compiled code
-var compiledContract = "608060405234801561001057600080fd5b50336000806101000a81548173ffff" +
-	"ffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffff" +
-	"ffffffffffffffffffffffff1602179055506102bb806100606000396000f300" +
-	"60806040526004361061006d576000357c010000000000000000000000000000" +
-	"0000000000000000000000000000900463ffffffff1680632e1a7d4d14610072" +
-	"57806343d726d61461009f5780638da5cb5b146100b6578063b60d4288146101" +
-	"0d578063d0e30db014610138575b600080fd5b34801561007e57600080fd5b50" +
-	"61009d60048036038101908080359060200190929190505050610142565b005b" +
-	"3480156100ab57600080fd5b506100b46101b2565b005b3480156100c2576000" +
-	"80fd5b506100cb610243565b604051808273ffffffffffffffffffffffffffff" +
-	"ffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16815260" +
-	"200191505060405180910390f35b34801561011957600080fd5b506101226102" +
-	"68565b6040518082815260200191505060405180910390f35b61014061026e56" +
-	"5b005b60006001548210151561015457600080fd5b8160016000828254039250" +
-	"50819055503390508073ffffffffffffffffffffffffffffffffffffffff1661" +
-	"08fc839081150290604051600060405180830381858888f19350505050158015" +
-	"6101ad573d6000803e3d6000fd5b505050565b6000809054906101000a900473" +
-	"ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffff" +
-	"ffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffff" +
-	"ff161415610241576000809054906101000a900473ffffffffffffffffffffff" +
-	"ffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff16" +
-	"ff5b565b6000809054906101000a900473ffffffffffffffffffffffffffffff" +
-	"ffffffffff1681565b60015481565b60003411151561027d57600080fd5b3460" +
-	"01600082825401925050819055505600a165627a7a72305820a20d1041740fd7" +
-	"e0fb9760f42ce8da0d175635f604134a859ca0ccfb327193580029"
-
-// function hash
-var (
-	close    = "43d726d6" //: "close()",
-	deposit  = "d0e30db0" //: "deposit()",
-	found    = "b60d4288" //: "fund()",
-	withdraw = "2e1a7d4d" //: "withdraw(uint256)"
-)
-
-func newContract(nonce uint64, gaslimit *big.Int, key *ecdsa.PrivateKey, contractStr string) *types.Transaction {
-	contractData := common.Hex2Bytes(contractStr)
-
-	contract, _ :=
-		types.SignTx(
-			types.NewContractCreation(nonce, big.NewInt(0), gaslimit, gasprice, contractData),
-			types.HomesteadSigner{},
-			key)
-	return contract
+	return NewServices(conf)
 }
 
-func getContractAddress(txHash common.Hash, eth *eth.Ethereum) (common.Address, error) {
-	receipt, err := getTransactionReceipt(txHash, eth)
-	if (err != nil || receipt.ContractAddress == common.Address{}) {
-		return common.Address{}, fmt.Errorf("Contract address not found for transaction" + txHash.Hex())
+func TestMain(m *testing.M) {
+	code := m.Run()
+	if ephemeralHome {
+		if testSrv != nil {
+			testSrv.Stop()
+		}
+		os.RemoveAll(rootDir)
 	}
-	return receipt.ContractAddress, nil
+	os.Exit(code)
 }
 
-func callContract(nonce uint64, gaslimit *big.Int, key *ecdsa.PrivateKey, contract common.Address, callCode string, amount *big.Int, args []byte) *types.Transaction {
-	callData := append(common.Hex2Bytes(callCode), args...)
-
-	contractCallTx, _ :=
-		types.SignTx(
-			types.NewTransaction(nonce, contract, amount, gaslimit, gasprice, callData),
-			types.HomesteadSigner{},
-			key)
-	return contractCallTx
-}
+// function hash
+var (
+	closeSelector = "43d726d6" //: "close()",
+	found         = "b60d4288" //: "fund()",
+)
 
 func BenchmarkBasicTxHash(t *testing.B) {
-	srv := initSrv
+	srv := testService(t)
 	// defer srv.tmNode.Stop()
 	key, _ := crypto.GenerateKey()
 	tx := transaction(0, gaslimit, key, to, defaultAmount)
@@ -215,12 +202,25 @@ func newAccount(s *Services, password string) (*TestAccount, error) {
 }
 
 var (
-	initSrv, _ = NewTestService()
-	// initSrv = new(Services)
+	testSrvOnce sync.Once
+	testSrv     *Services
+	testSrvErr  error
 )
 
+// testService returns the node the tests share, started by the first test
+// needing it.
+func testService(tb testing.TB) *Services {
+	testSrvOnce.Do(func() {
+		testSrv, testSrvErr = NewTestService()
+	})
+	if testSrvErr != nil {
+		tb.Fatal(testSrvErr)
+	}
+	return testSrv
+}
+
 func BenchmarkSignBasicTx(t *testing.B) {
-	srv := initSrv
+	srv := testService(t)
 	// defer srv.tmNode.Stop()
 
 	t.ResetTimer()
@@ -233,7 +233,7 @@ func BenchmarkSignBasicTx(t *testing.B) {
 }
 
 func BenchmarkAddBasicTx(t *testing.B) {
-	srv := initSrv
+	srv := testService(t)
 
 	accounts, err := initAccountsForPtxTest(srv, rootDir, t.N)
 	if err != nil {
@@ -287,7 +287,7 @@ func addTxsToPoolAsync(t *testing.T, pool *core.TxPool, txs types.Transactions)
 }
 
 func TestAdd4KBasicTx(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 	txCnt := 4096
 	accounts, err := initAccountsForPtxTest(srv, rootDir, txCnt)
 	if err != nil {
@@ -328,72 +328,183 @@ func TestAdd4KBasicTx(t *testing.T) {
 	// 	}
 	// }
 	// wg := addTxsToPoolAsync(t, pool, txs)
-	wg := addTxsToHTTPClientAsync(httpClients, txsBytes)
+	session := newBenchSession(srv)
+	rec := session.Begin("add-4k-basic-tx")
+	// the txs go async unless a report counts the CheckTx rejects
+	var checked *bench.Recorder
+	if benchReport != "" {
+		checked = rec
+	}
+	wg := addTxsToHTTPClientAsync(httpClients, txsBytes, checked)
 	wg.Wait()
 	end := time.Now()
 	t.Log("End time:", end)
 	t.Log("Add ", txCnt, " tx costs :", end.Sub(start))
 	fmt.Println("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!! Add ", txCnt, " tx in", remoteClientCnt, "costs :", end.Sub(start))
 
-	for idx, hash := range queuedTxHash {
-		if err := wait(hash, srv.backend.Ethereum()); err != nil {
-			t.Error("Meet error:", err, "Idx :=", idx)
-		}
+	if err := recordInclusion(srv, queuedTxHash, start, rec); err != nil {
+		t.Error("Meet error:", err)
 	}
+	endBenchRound(t, session)
 
 	// time.Sleep(5 * time.Second)
 }
 
+// TestLoopAddBasicTx runs a short soak against the test node, use
+// `ultron bench soak` for long runs.
 func TestLoopAddBasicTx(t *testing.T) {
-	srv := initSrv
-	txCnt := 4096
-	accounts, err := initAccountsForPtxTest(srv, rootDir, txCnt)
+	profile, err := bench.ParseProfile(benchProfile, benchRatios)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	remoteClientCnt := 1
-	httpClients := createRemoteClientConnections(remoteClientCnt)
-	fmt.Println("!!!!!!!!!!!!!!!!!! create", len(httpClients), "remote clients.")
+	srv := testService(t)
+	runner, err := newSoakRunner(srv, rootDir, soakConfig{
+		Duration:   soakDuration,
+		Profile:    profile,
+		Accounts:   accountNum,
+		Clients:    1,
+		ReportFile: benchReport,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkErrs(t, runner.Run())
+}
 
-	txsCh, _ := prepareTXsAsync(srv, txCnt, accounts)
+func TestMineBlock(t *testing.T) {
+	if !manualMining {
+		t.Skip("needs -manualMining")
+	}
+	srv := testService(t)
+	api := backend.NewPublicDevAPI(srv.backend)
 
-	
-	for i := 0; i < 2; i++ {
-		// txsBytes, _, queuedTxHash, err := prepareTXs(txCnt, offset++, accounts)
-		start := time.Now()
-		fmt.Println("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!! Start time:", start)
-		t.Log("Start time:", start)
-		
-		queuedTxHash := []common.Hash{}
-		txsBytes := [][]byte{}
-		select {
-		case txs := <-txsCh :
-			fmt.Println("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!! Tx Received!")
-			for _, signedTx := range txs {
-				buf := new(bytes.Buffer)
-				signedTx.EncodeRLP(buf)
-				txsBytes = append(txsBytes, buf.Bytes())
-				queuedTxHash = append(queuedTxHash, signedTx.Hash())
-				// fmt.Println("****************************** Send Tx", signedTx.Hash().Hex())
-			}
-			start = time.Now()
-			fmt.Println("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!! ", len(txsBytes), "Tx Received!")
-			wg := addTxsToHTTPClientAsync(httpClients, txsBytes)
-			wg.Wait()
-		}
+	nonce := srv.backend.Ethereum().TxPool().State().GetNonce(from)
+	key, _ := crypto.GenerateKey()
+	signedTx := makeTransaction(srv, &from, "dora.io", transaction(nonce, gaslimit, key, to, defaultAmount))
+	buf := new(bytes.Buffer)
+	checkErrs(t, signedTx.EncodeRLP(buf))
+	res, err := createRemoteClientConnections(1)[0].BroadcastTxSync(buf.Bytes())
+	checkErrs(t, err)
+	if res.Code != 0 {
+		t.Fatalf("CheckTx rejected the tx: %s", res.Log)
+	}
 
-		end := time.Now()
-		t.Log("End time:", end)
-		t.Log("Add ", txCnt, " tx costs :", end.Sub(start))
-		fmt.Println("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!! Add ", txCnt, " tx in", remoteClientCnt, "costs :", end.Sub(start))
-	
-		checkErrs(t, waitTxsAsync(srv, queuedTxHash))
+	before := srv.backend.Ethereum().BlockChain().CurrentBlock().NumberU64()
+	n := hexutil.Uint64(2)
+	number, err := api.MineBlock(&n)
+	checkErrs(t, err)
+	if uint64(number) != before+2 {
+		t.Fatalf("mined up to block %d, expect %d", number, before+2)
+	}
+	if _, err := getTransactionReceipt(signedTx.Hash(), srv.backend.Ethereum()); err != nil {
+		t.Fatal("tx not included in the mined block: ", err)
+	}
+}
+
+func TestIncreaseTime(t *testing.T) {
+	if !manualMining || !devMode {
+		t.Skip("needs -manualMining -dev")
+	}
+	srv := testService(t)
+	api := backend.NewPublicDevAPI(srv.backend)
+	chain := srv.backend.Ethereum().BlockChain()
+
+	before := chain.CurrentBlock().Time().Uint64()
+	_, err := api.IncreaseTime(3600)
+	checkErrs(t, err)
+	_, err = api.MineBlock(nil)
+	checkErrs(t, err)
+	if after := chain.CurrentBlock().Time().Uint64(); after < before+3600 {
+		t.Fatalf("block time %d, expect at least %d", after, before+3600)
+	}
+
+	next := chain.CurrentBlock().Time().Uint64() + 86400
+	checkErrs(t, api.SetNextBlockTimestamp(hexutil.Uint64(next)))
+	_, err = api.MineBlock(nil)
+	checkErrs(t, err)
+	if after := chain.CurrentBlock().Time().Uint64(); after != next {
+		t.Fatalf("block time %d, expect %d", after, next)
+	}
+}
+
+func TestSnapshotRevert(t *testing.T) {
+	if !manualMining || !devMode {
+		t.Skip("needs -manualMining -dev")
+	}
+	srv := testService(t)
+	api := backend.NewPublicDevAPI(srv.backend)
+	evm := backend.NewPublicEVMAPI(srv.backend)
+	chain := srv.backend.Ethereum().BlockChain()
+
+	id, err := evm.Snapshot()
+	checkErrs(t, err)
+	number := chain.CurrentBlock().NumberU64()
+	balance := srv.backend.Ethereum().TxPool().State().GetBalance(to)
+
+	_, err = simpleTransfer(srv, from, "dora.io", to, defaultAmount, false)
+	checkErrs(t, err)
+	n := hexutil.Uint64(2)
+	_, err = api.MineBlock(&n)
+	checkErrs(t, err)
+
+	ok, err := evm.Revert(id)
+	checkErrs(t, err)
+	if !ok {
+		t.Fatal("snapshot not found")
+	}
+	if chain.CurrentBlock().NumberU64() != number {
+		t.Fatalf("head at block %d, expect %d", chain.CurrentBlock().NumberU64(), number)
+	}
+	state, err := chain.State()
+	checkErrs(t, err)
+	if state.GetBalance(to).Cmp(balance) != 0 {
+		t.Fatalf("balance %v, expect %v", state.GetBalance(to), balance)
+	}
+
+	// a snapshot is gone once reverted to
+	ok, err = evm.Revert(id)
+	checkErrs(t, err)
+	if ok {
+		t.Fatal("reverted twice to the same snapshot")
+	}
+}
+
+func TestNameService(t *testing.T) {
+	if !manualMining {
+		t.Skip("needs -manualMining")
+	}
+	srv := testService(t)
+	api := backend.NewPublicDevAPI(srv.backend)
+
+	// module txs are contract creations of zero value and gas
+	data, err := json.Marshal(names.NewTxRegisterName("test.ultron", to))
+	checkErrs(t, err)
+	nonce := srv.backend.Ethereum().TxPool().State().GetNonce(from)
+	signedTx := makeTransaction(srv, &from, "dora.io",
+		types.NewContractCreation(nonce, big.NewInt(0), big.NewInt(0), big.NewInt(0), data))
+	buf := new(bytes.Buffer)
+	checkErrs(t, signedTx.EncodeRLP(buf))
+	res, err := createRemoteClientConnections(1)[0].BroadcastTxSync(buf.Bytes())
+	checkErrs(t, err)
+	if res.Code != 0 {
+		t.Fatalf("CheckTx rejected the tx: %s", res.Log)
+	}
+	_, err = api.MineBlock(nil)
+	checkErrs(t, err)
+
+	target, err := resolveTo(srv, "test.ultron")
+	checkErrs(t, err)
+	if target != to {
+		t.Fatalf("test.ultron resolves to %x, expect %x", target, to)
+	}
+	if _, err := resolveTo(srv, "nobody.ultron"); err == nil {
+		t.Fatal("resolved a name never registered")
 	}
 }
 
 func BenchmarkNewAccount(t *testing.B) {
-	srv := initSrv
+	srv := testService(t)
 	// defer srv.tmNode.Stop()
 
 	t.ResetTimer()
@@ -406,7 +517,7 @@ func BenchmarkNewAccount(t *testing.B) {
 }
 
 func TestGenerateExtendedGenesis(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 	// defer srv.tmNode.Stop()
 	var extendGenesisBlob = []byte(`
 	{
@@ -455,7 +566,7 @@ func TestGenerateExtendedGenesis(t *testing.T) {
 }
 
 func TestGenerateLargeScaleTxs(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 	// defer srv.tmNode.Stop()
 
 	accounts, err := initAccountsForPtxTest(srv, rootDir, accountNum)
@@ -481,16 +592,18 @@ func TestGenerateLargeScaleTxs(t *testing.T) {
 		}
 	}
 
-	writeJSON(queuedTx, "queued-txs.json", 0)
+	if err := bench.WriteTxFile(path.Join(rootDir, "queued-txs.json"), uint64(config.EMConfig.EthChainId), queuedTx); err != nil {
+		t.Fatal(err)
+	}
 }
 
 func TestReplayLargeScaleTxs(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 	pool := srv.backend.Ethereum().TxPool()
 	// defer srv.tmNode.Stop()
-	queuedTx, ok := loadLargeScaleTxsFile("queued-txs.json")
-	if !ok {
-		t.Fatal("ERROR: loadLargeScaleTxsFile failed")
+	queuedTx, err := bench.ReadTxFile(path.Join(rootDir, "queued-txs.json"), uint64(config.EMConfig.EthChainId))
+	if err != nil {
+		t.Fatal(err)
 	}
 
 	queuedTxHash := []common.Hash{}
@@ -521,7 +634,7 @@ func TestReplayLargeScaleTxs(t *testing.T) {
 }
 
 func TestBasicTx(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 	defer srv.tmNode.Stop()
 
 	pool := srv.backend.Ethereum().TxPool()
@@ -568,22 +681,6 @@ func initAccountPool(s *Services, n int, offset int) []*TestAccount {
 	return accounts
 }
 
-func loadLargeScaleTxsFile(testDB string) (types.Transactions, bool) {
-	dbName := path.Join(rootDir, testDB)
-	dat, err := ioutil.ReadFile(dbName)
-	if err != nil {
-		return nil, false
-	}
-
-	txs := types.Transactions{}
-	err = json.Unmarshal(dat, &txs)
-	if err != nil {
-		return nil, false
-	}
-
-	return txs, true
-}
-
 func writeJSON(testAccounts interface{}, testDB string, flag int) bool {
 	dbName := path.Join(rootDir, testDB)
 	dbFile, err := os.Create(dbName)
@@ -696,7 +793,7 @@ func fastTransferInitialFund(srv *Services, accounts []*TestAccount, initFund *b
 }
 
 func TestBasicPTX(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 	defer srv.tmNode.Stop()
 
 	accounts, err := initAccountsForPtxTest(srv, rootDir, 8)
@@ -748,7 +845,7 @@ func TestBasicPTX(t *testing.T) {
 }
 
 func TestBasicContract(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 	defer srv.tmNode.Stop()
 
 	pool := srv.backend.Ethereum().TxPool()
@@ -825,7 +922,7 @@ func TestBasicContract(t *testing.T) {
 	// step 4. undeploy smart contract.
 	key, _ = crypto.GenerateKey()
 	nonceFrom++
-	tx = callContract(nonceFrom, gaslimit, key, contractAddr, close, nil, nil)
+	tx = callContract(nonceFrom, gaslimit, key, contractAddr, closeSelector, nil, nil)
 	signedTx = makeTransaction(srv, &from, "dora.io", tx)
 	if err := pool.AddRemote(signedTx); err != nil {
 		t.Error("Meet error", err)
@@ -838,10 +935,16 @@ func TestBasicContract(t *testing.T) {
 
 	newState = pool.State()
 	t.Log("After trans balance: ", newState.GetBalance(from), newState.GetBalance(to))
+	cleanup, err := backend.NewPrivateDebugAPI(srv.backend).VerifyStateCleanup(&contractAddr)
+	checkErrs(t, err)
+	if len(cleanup.Contracts) != 1 || !cleanup.Verified {
+		t.Fatalf("storage of closed contract not released: %+v", cleanup)
+	}
+	t.Log("Reclaimed bytes: ", cleanup.ReclaimedBytes)
 }
 
 func TestStateDBCommit(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 
 	testAccounts, ok := loadTestAccountsFromFile(rootDir, accountInfoDB)
 	if !ok {
@@ -875,7 +978,7 @@ func TestStateDBCommit(t *testing.T) {
 }
 
 func BenchmarkCommit(b *testing.B) {
-	srv := initSrv
+	srv := testService(b)
 
 	testAccounts, ok := loadTestAccountsFromFile(rootDir, accountInfoDB)
 	if !ok {
@@ -931,7 +1034,7 @@ func stateDBIntermediateRoot(srv *Services, txNum int) ([]common.Hash, error) {
 }
 
 func TestTrieHash(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 
 	txNum := 26000
 	start := time.Now()
@@ -950,7 +1053,7 @@ func TestTrieHash(t *testing.T) {
 }
 
 func Test4KSimpleTx(t *testing.T) {
-	srv := initSrv
+	srv := testService(t)
 	txCnt := 4000
 
 	pool := srv.backend.Ethereum().TxPool()
@@ -997,15 +1100,29 @@ func TestReject4KRemoteCheckTx(t *testing.T) {
 		txsBytes = append(txsBytes, fakeTxBytes)
 	}
 
-	start := time.Now()
-	fmt.Println("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!! Start time:", start)
-	t.Log("Start time:", start)
-	wg := addTxsToHTTPClientAsync(httpClients, txsBytes)
-	wg.Wait()
-	end := time.Now()
-	t.Log("End time:", end)
-	t.Log("Add ", txCnt, " tx costs :", end.Sub(start))
-	fmt.Println("Add ", txCnt, " tx costs :", end.Sub(start))
+	session := newBenchSession(testService(t))
+	// fired async first, then sync so the rejects are counted
+	for _, wait := range []bool{false, true} {
+		start := time.Now()
+		t.Log("Start time:", start, "sync:", wait)
+		var rec, checked *bench.Recorder
+		if wait {
+			rec = session.Begin("reject-remote-checktx")
+			checked = rec
+		} else {
+			rec = session.Begin("reject-remote-checktx-async")
+		}
+		wg := addTxsToHTTPClientAsync(httpClients, txsBytes, checked)
+		wg.Wait()
+		end := time.Now()
+		t.Log("End time:", end)
+		t.Log("Add ", txCnt, " tx costs :", end.Sub(start))
+		fmt.Println("Add ", txCnt, " tx costs :", end.Sub(start))
+		if res := rec.Result(); wait && (res.Submitted != txCnt || res.Rejected != txCnt) {
+			t.Errorf("%d of %d txs rejected, all expected", res.Rejected, res.Submitted)
+		}
+		endBenchRound(t, session)
+	}
 
 	// time.Sleep(5 * time.Second)
 }
\ No newline at end of file
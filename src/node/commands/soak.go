@@ -0,0 +1,257 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	rpcClient "github.com/tendermint/tendermint/rpc/client"
+
+	"github.com/dora/ultron/bench"
+)
+
+// soakConfig holds the knobs of a soak run.
+type soakConfig struct {
+	Duration      time.Duration
+	Profile       bench.Profile
+	Accounts      int
+	Clients       int
+	ReportFile    string
+	MaxMemGrowth  uint64 // bytes, 0 disables the check
+	MaxDiskGrowth uint64 // bytes, 0 disables the check
+}
+
+// soakRunner drives rounds of txs shaped by a workload profile against a running node and checks
+// a few invariants after every round:
+//   - the chain keeps producing blocks
+//   - every submitted tx is accepted and included
+//   - every sender's nonce advances by exactly one per round
+//   - value is conserved: the funds of all test accounts and deployed
+//     contracts only shrink by the fees paid
+//   - heap and disk usage stay within the configured growth
+type soakRunner struct {
+	cfg       soakConfig
+	srv       *Services
+	rootDir   string
+	accounts  []*TestAccount
+	clients   []*rpcClient.HTTP
+	session   *bench.Session
+	contracts []common.Address
+
+	baseHeap uint64
+	baseDisk uint64
+}
+
+func newSoakRunner(srv *Services, rootDir string, cfg soakConfig) (*soakRunner, error) {
+	accounts, err := initAccountsForPtxTest(srv, rootDir, cfg.Accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &soakRunner{
+		cfg:      cfg,
+		srv:      srv,
+		rootDir:  rootDir,
+		accounts: accounts,
+		clients:  createRemoteClientConnections(cfg.Clients),
+		session:  bench.NewSession(cfg.ReportFile),
+	}
+	srv.backend.SetCommitObserver(r.session.Committed)
+	return r, nil
+}
+
+// Run keeps sending rounds until the duration elapses. It returns the first
+// invariant violation it meets.
+func (r *soakRunner) Run() error {
+	start := time.Now()
+	for round := 0; time.Since(start) < r.cfg.Duration; round++ {
+		if err := r.round(round); err != nil {
+			return fmt.Errorf("soak round %d: %v", round, err)
+		}
+		if err := r.checkUsage(round); err != nil {
+			return fmt.Errorf("soak round %d: %v", round, err)
+		}
+	}
+
+	logger.Info("Soak test finished", "duration", time.Since(start), "rounds", len(r.session.Results()))
+	return nil
+}
+
+type soakSnapshot struct {
+	height uint64
+	funds  *big.Int
+	nonces []uint64
+}
+
+func (r *soakRunner) snapshot() (*soakSnapshot, error) {
+	chain := r.srv.backend.Ethereum().BlockChain()
+	state, err := chain.State()
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &soakSnapshot{
+		height: chain.CurrentBlock().NumberU64(),
+		funds:  big.NewInt(0),
+	}
+	for _, acc := range r.accounts {
+		snap.funds.Add(snap.funds, state.GetBalance(acc.Address))
+		snap.nonces = append(snap.nonces, state.GetNonce(acc.Address))
+	}
+	for _, contract := range r.contracts {
+		snap.funds.Add(snap.funds, state.GetBalance(contract))
+	}
+	return snap, nil
+}
+
+// buildTx signs the tx of the i-th account. Contract calls are turned into
+// deploys until the first contract exists.
+func (r *soakRunner) buildTx(round, i int, nonce uint64) (*types.Transaction, bench.TxKind) {
+	acc := r.accounts[i]
+	key, _ := crypto.GenerateKey()
+
+	kind := r.cfg.Profile.Kind(round*len(r.accounts) + i)
+	if kind.NeedsContract() && len(r.contracts) == 0 {
+		kind = bench.Deploy
+	}
+	var tx *types.Transaction
+	switch kind {
+	case bench.Deploy:
+		tx = newContract(nonce, gaslimit, key, compiledContract)
+	case bench.Deposit:
+		contract := r.contracts[i%len(r.contracts)]
+		tx = callContract(nonce, gaslimit, key, contract, deposit, defaultAmount, nil)
+	case bench.Withdraw:
+		contract := r.contracts[i%len(r.contracts)]
+		tx = callContract(nonce, gaslimit, key, contract, withdraw, nil, common.LeftPadBytes(defaultAmount.Bytes(), 32))
+	default:
+		to := r.accounts[(i+1)%len(r.accounts)].Address
+		tx = transaction(nonce, gaslimit, key, to, defaultAmount)
+	}
+	return signTx(r.srv, acc, tx), kind
+}
+
+func (r *soakRunner) round(round int) error {
+	before, err := r.snapshot()
+	if err != nil {
+		return err
+	}
+
+	txs := types.Transactions{}
+	kinds := []bench.TxKind{}
+	txsBytes := [][]byte{}
+	hashes := []common.Hash{}
+	for i := range r.accounts {
+		signedTx, kind := r.buildTx(round, i, before.nonces[i])
+		if signedTx == nil {
+			return fmt.Errorf("failed to sign tx for %s", r.accounts[i].Address.Hex())
+		}
+		buf := new(bytes.Buffer)
+		signedTx.EncodeRLP(buf)
+		txs = append(txs, signedTx)
+		kinds = append(kinds, kind)
+		txsBytes = append(txsBytes, buf.Bytes())
+		hashes = append(hashes, signedTx.Hash())
+	}
+
+	rec := r.session.Begin(fmt.Sprintf("soak-%s-%d", r.cfg.Profile.Name, round))
+	sentAt := time.Now()
+	addTxsToHTTPClientAsync(r.clients, txsBytes, rec).Wait()
+	inclusionErr := recordInclusion(r.srv, hashes, sentAt, rec)
+	res, err := r.session.End()
+	if err != nil {
+		logger.Error("Write soak report failed", "err", err)
+	}
+	if res.Rejected > 0 {
+		return fmt.Errorf("%d of %d valid txs rejected by CheckTx", res.Rejected, res.Submitted)
+	}
+	if inclusionErr != nil {
+		return inclusionErr
+	}
+
+	// collect fees and new contracts from the receipts
+	fees := big.NewInt(0)
+	for i, signedTx := range txs {
+		receipt, err := getTransactionReceipt(signedTx.Hash(), r.srv.backend.Ethereum())
+		if err != nil {
+			return err
+		}
+		fees.Add(fees, new(big.Int).Mul(receipt.GasUsed, signedTx.GasPrice()))
+		if kinds[i] == bench.Deploy && (receipt.ContractAddress != common.Address{}) {
+			r.contracts = append(r.contracts, receipt.ContractAddress)
+		}
+	}
+
+	after, err := r.snapshot()
+	if err != nil {
+		return err
+	}
+	if after.height <= before.height {
+		return fmt.Errorf("chain stalled at height %d", after.height)
+	}
+	for i := range r.accounts {
+		if after.nonces[i] != before.nonces[i]+1 {
+			return fmt.Errorf("nonce of %s moved from %d to %d, expect +1",
+				r.accounts[i].Address.Hex(), before.nonces[i], after.nonces[i])
+		}
+	}
+	expect := new(big.Int).Sub(before.funds, fees)
+	if after.funds.Cmp(expect) != 0 {
+		return fmt.Errorf("value not conserved: funds %v, expect %v (before %v, fees %v)",
+			after.funds, expect, before.funds, fees)
+	}
+
+	logger.Info("Soak round done", "round", round, "height", after.height,
+		"tps", res.TPS, "p99_ms", res.LatencyP99Ms, "contracts", len(r.contracts))
+	return nil
+}
+
+// checkUsage compares heap and disk usage against the first round.
+func (r *soakRunner) checkUsage(round int) error {
+	runtime.GC()
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	disk, err := dirSize(r.rootDir)
+	if err != nil {
+		return err
+	}
+
+	logger.Info("Soak resource usage", "round", round, "heap", mem.HeapAlloc, "disk", disk)
+	if round == 0 {
+		r.baseHeap, r.baseDisk = mem.HeapAlloc, disk
+		return nil
+	}
+
+	if r.cfg.MaxMemGrowth > 0 && mem.HeapAlloc > r.baseHeap+r.cfg.MaxMemGrowth {
+		return fmt.Errorf("heap grew from %d to %d bytes, limit %d", r.baseHeap, mem.HeapAlloc, r.cfg.MaxMemGrowth)
+	}
+	if r.cfg.MaxDiskGrowth > 0 && disk > r.baseDisk+r.cfg.MaxDiskGrowth {
+		return fmt.Errorf("disk grew from %d to %d bytes, limit %d", r.baseDisk, disk, r.cfg.MaxDiskGrowth)
+	}
+	return nil
+}
+
+func dirSize(dir string) (uint64, error) {
+	var size uint64
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			// files come and go while the node compacts its dbs
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !info.IsDir() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	return size, err
+}
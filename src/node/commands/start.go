@@ -18,11 +18,21 @@ import (
 	cmn "github.com/tendermint/tmlibs/common"
 
 	"github.com/dora/ultron/app"
+	"github.com/dora/ultron/appwal"
+	"github.com/dora/ultron/bench"
 	"github.com/dora/ultron/genesis"
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/modules/upgrade"
 )
 
 var (
-	PlayFlag = "play"
+	PlayFlag         = "play"
+	BenchReportFlag  = "bench_report"
+	DBBackendFlag    = "db_backend"
+	ManualMiningFlag = "manual_mining"
+	DevFlag          = "dev"
+	ReplicaFlag      = "replica"
+	ArchiveFlag      = "archive"
 )
 
 // GetStartCmd - initialize a command as the start command with tick
@@ -34,33 +44,83 @@ func GetStartCmd() *cobra.Command {
 	}
 
 	startCmd.Flags().String(PlayFlag, "true", "Play test scripts")
+	startCmd.Flags().String(BenchReportFlag, "", "Write play results to this file (.json or .csv)")
+	startCmd.Flags().String(DerivedAccountsFlag, "", "Derive the play accounts from this seed instead of loading them, see bench accounts")
+	startCmd.Flags().String(DBBackendFlag, "leveldb", "Database backend: leveldb | memdb, memdb keeps the chain in memory")
+	startCmd.Flags().Bool(ManualMiningFlag, false, "Make blocks only when asked with ultron_mineBlock")
+	startCmd.Flags().Bool(DevFlag, false, "Run as a dev chain, enabling time manipulation over RPC")
+	startCmd.Flags().Bool(ReplicaFlag, false, "Only follow the blocks to serve RPC queries, refusing txs")
+	startCmd.Flags().Bool(ArchiveFlag, false, "Keep the state of every height, for the queries of past heights")
+	startCmd.Flags().String(GenesisURLFlag, "", "Download the genesis of the network from this url on the first start")
+	startCmd.Flags().String(GenesisChecksumFlag, "", "Hex sha256 the genesis downloaded with --"+GenesisURLFlag+" must have")
+	startCmd.Flags().String(DNSSeedsFlag, "", "Comma separated domains whose TXT records list seeds, id@host:port")
+	startCmd.Flags().String(NATFlag, "", "Map the p2p port on the NAT gateway: none, any, upnp, pmp or extip:<IP>")
+	startCmd.Flags().String(ExternalAddressFlag, "", "host:port peers should dial this node at")
 
 	return startCmd
 }
 
-// nolint TODO: move to config file
-const EyesCacheSize = 10000
-
 //returns the start command which uses the tick
 func startCmd() func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		rootDir := viper.GetString(cli.HomeFlag)
+		if viper.GetBool(ManualMiningFlag) {
+			config.TestConfig.ManualMining = true
+		}
+		if viper.GetBool(DevFlag) {
+			config.TestConfig.DevMode = true
+		}
+		if viper.GetBool(ReplicaFlag) {
+			config.BaseConfig.Replica = true
+		}
+		if viper.GetBool(ArchiveFlag) {
+			config.BaseConfig.Archive = true
+		}
+		if err := setLocalParams(); err != nil {
+			return err
+		}
+		if seeds := viper.GetString(DNSSeedsFlag); seeds != "" {
+			config.P2PConfig.DNSSeeds = seeds
+		}
+		if mech := viper.GetString(NATFlag); mech != "" {
+			config.P2PConfig.NAT = mech
+		}
+		if addr := viper.GetString(ExternalAddressFlag); addr != "" {
+			config.P2PConfig.ExternalAddress = addr
+		}
 
 		cmdName := cmd.Root().Name()
 		appName := fmt.Sprintf("%s v%v", cmdName, version.Version)
-		storeApp, err := app.NewStoreApp(
-			appName,
-			path.Join(rootDir, "data", "merkleeyes.db"),
-			EyesCacheSize,
-			logger.With("module", "app"))
+		storeApp, err := newStoreApp(appName, config, logger.With("module", "app"))
 		if err != nil {
 			return err
 		}
+		if url := viper.GetString(GenesisURLFlag); url != "" {
+			err = fetchGenesis(config.TMConfig.GenesisFile(), url, viper.GetString(GenesisChecksumFlag),
+				storeApp.CommittedHeight() == 0)
+			if err != nil {
+				return err
+			}
+		}
 
 		return start(rootDir, storeApp)
 	}
 }
 
+// setLocalParams applies the local params set under [params] in config.toml.
+func setLocalParams() error {
+	for _, p := range params.All() {
+		key := "params." + p.Key
+		if !p.Local || !viper.IsSet(key) {
+			continue
+		}
+		if err := params.SetLocal(p.Key, viper.GetString(key)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func start(rootDir string, storeApp *app.StoreApp) error {
 	srvs, err := startServices(rootDir, storeApp)
 	if err != nil {
@@ -70,7 +130,7 @@ func start(rootDir string, storeApp *app.StoreApp) error {
 	mode := viper.GetString(PlayFlag)
 	switch mode {
 	case "loop" :
-		err = playLoopBasicTx(srvs, rootDir)
+		err = playLoopBasicTx(srvs, rootDir, viper.GetString(BenchReportFlag))
 	default:
 	}
 
@@ -82,7 +142,7 @@ func start(rootDir string, storeApp *app.StoreApp) error {
 	return nil
 }
 
-func playLoopBasicTx(srv *Services, rootDir string) error {
+func playLoopBasicTx(srv *Services, rootDir, reportFile string) error {
 	txCnt := 8192
 	accounts, err := initAccountsForPtxTest(srv, rootDir, txCnt)
 	if err != nil {
@@ -95,9 +155,16 @@ func playLoopBasicTx(srv *Services, rootDir string) error {
 
 	txsCh, _ := prepareTXsAsync(srv, txCnt, accounts)
 
+	var session *bench.Session
+	if reportFile != "" {
+		session = bench.NewSession(reportFile)
+		srv.backend.SetCommitObserver(session.Committed)
+	}
+
 	go func() {
 		tick := 0
 		for true {
+			var rec *bench.Recorder
 			start := time.Now()
 			fmt.Println("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!! Start time:", start)
 
@@ -112,16 +179,27 @@ func playLoopBasicTx(srv *Services, rootDir string) error {
 					txsBytes = append(txsBytes, buf.Bytes())
 					queuedTxHash = append(queuedTxHash, signedTx.Hash())
 				}
+				if session != nil {
+					rec = session.Begin(fmt.Sprintf("loop-%d", tick))
+				}
 				start = time.Now()
 				fmt.Println("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!! ", len(txsBytes), "Tx Received!")
-				wg := addTxsToHTTPClientAsync(httpClients, txsBytes)
+				wg := addTxsToHTTPClientAsync(httpClients, txsBytes, rec)
 				wg.Wait()
 			}
 	
 			end := time.Now()
 			fmt.Println("!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!!! Add ", txCnt, " tx in", remoteClientCnt, "costs :", end.Sub(start))
-		
-			if tick % 3 == 0 {
+
+			if session != nil {
+				// reporting needs every receipt, so each round waits for all its txs
+				if err := recordInclusion(srv, queuedTxHash, start, rec); err != nil {
+					fmt.Println("ERROR: recordInclusion meets error", err)
+				}
+				if _, err := session.End(); err != nil {
+					fmt.Println("ERROR: write bench report failed", err)
+				}
+			} else if tick % 3 == 0 {
 //			    time.Sleep(3 * time.Second)
 			    err = waitTxsAsync(srv, queuedTxHash[txCnt - 1:])
 			    if err != nil {
@@ -146,6 +224,12 @@ func createBaseCoinApp(rootDir string, storeApp *app.StoreApp, ethApp *app.Ether
 	if err != nil {
 		return nil, err
 	}
+	ultronApp.SetUpgradeInfoFile(path.Join(rootDir, "data", upgrade.InfoFile))
+	wal, err := appwal.Open(path.Join(rootDir, "data", appwal.File), appwal.DefaultMaxSize)
+	if err != nil {
+		return nil, errors.Errorf("Error opening the app wal: %v", err)
+	}
+	ultronApp.SetWAL(wal)
 	// if chain_id has not been set yet, load the genesis.
 	// else, assume it's been loaded
 	if ultronApp.GetChainID() == "" {
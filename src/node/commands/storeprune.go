@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"path"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dora/ultron/app"
+	"github.com/dora/ultron/backend"
+	emtConfig "github.com/dora/ultron/node/config"
+	"github.com/dora/ultron/storeprune"
+)
+
+// startStorePruner deletes the versions of the app state out of the
+// retention of [disk] every prune_interval, and compacts the db, between
+// two blocks. It returns nil when the versions are pruned as the blocks
+// commit, or never for an archive.
+func startStorePruner(conf emtConfig.DiskConfig, rootDir string, ultronApp *app.BaseApp, b *backend.Backend) *storeprune.Pruner {
+	if conf.PruneInterval <= 0 || ultronApp.Archive() {
+		return nil
+	}
+	if conf.RetainVersions < 1 {
+		log.Warn("Not pruning the app state, retain_versions must be at least 1", "retain_versions", conf.RetainVersions)
+		return nil
+	}
+
+	store := storeprune.Store{
+		Dir: path.Join(rootDir, "data", "merkleeyes.db"),
+		Prune: func(retain int64, max int) (n int, err error) {
+			err = ultronApp.PauseBlocks(func() error {
+				n, err = ultronApp.PruneVersions(retain, max)
+				return err
+			})
+			return n, err
+		},
+		Compact: func() error {
+			return ultronApp.PauseBlocks(ultronApp.CompactDB)
+		},
+	}
+	p := storeprune.NewPruner(storeprune.Config{
+		Retain:       conf.RetainVersions,
+		Interval:     conf.PruneInterval,
+		Batch:        conf.PruneBatch,
+		CompactAfter: conf.PruneCompactVersions,
+	}, store)
+	p.Start()
+	b.SetStorePruner(p)
+	return p
+}
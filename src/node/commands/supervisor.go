@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"path"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/tendermint/tmlibs/cli"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// binaries of the supervisor, under the home dir:
+//
+//	supervisor/genesis/bin/ultron         the binary the chain started with
+//	supervisor/upgrades/<name>/bin/ultron the binary of the upgrade name
+//	supervisor/current                    link to the dir of the running one
+const supervisorDir = "supervisor"
+
+// GetRunSupervisedCmd - initialize the command running the node under a
+// supervisor that swaps its binary at upgrades
+func GetRunSupervisedCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "run-supervised -- [ultron args]",
+		Short: "Run ultron with the args, switching to the binary of each upgrade the chain reaches",
+		Long: `Run ultron with the args, e.g. "ultron run-supervised -- node start".
+
+When the chain reaches an upgrade the binary lacks, the node exits and the
+binary of the upgrade, put at <home>/supervisor/upgrades/<name>/bin/ultron
+beforehand, is started with the same args. The chain starts with
+<home>/supervisor/genesis/bin/ultron, or this binary if there is none.`,
+		RunE: runSupervised,
+	}
+}
+
+func runSupervised(cmd *cobra.Command, args []string) error {
+	dir := path.Join(viper.GetString(cli.HomeFlag), supervisorDir)
+	infoFile := path.Join(viper.GetString(cli.HomeFlag), "data", upgrade.InfoFile)
+
+	for {
+		bin, err := currentBinary(dir)
+		if err != nil {
+			return err
+		}
+		logger.Info("Starting node", "binary", bin, "args", args)
+		code, err := runBinary(bin, args)
+		if err != nil {
+			return err
+		}
+		if code != upgrade.ExitCode {
+			os.Exit(code)
+		}
+
+		plan, err := upgrade.ReadInfoFile(infoFile)
+		if err != nil {
+			return errors.Wrap(err, "reading the upgrade info")
+		}
+		if err := switchBinary(dir, plan); err != nil {
+			return err
+		}
+		logger.Info("Switched binary for upgrade", "name", plan.Name, "height", plan.Height)
+	}
+}
+
+// currentBinary returns the binary the node runs with
+func currentBinary(dir string) (string, error) {
+	for _, bin := range []string{
+		path.Join(dir, "current", "bin", "ultron"),
+		path.Join(dir, "genesis", "bin", "ultron"),
+	} {
+		if _, err := os.Stat(bin); err == nil {
+			return bin, nil
+		}
+	}
+	return os.Executable()
+}
+
+// runBinary runs bin with args until it exits, returning its exit code.
+// The signals stopping the supervisor are passed on to it.
+func runBinary(bin string, args []string) (int, error) {
+	c := exec.Command(bin, args...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Start(); err != nil {
+		return 0, err
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigs)
+	go func() {
+		for sig := range sigs {
+			c.Process.Signal(sig) // nolint: errcheck
+		}
+	}()
+
+	err := c.Wait()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		if status, ok := exitErr.Sys().(syscall.WaitStatus); ok {
+			return status.ExitStatus(), nil
+		}
+	}
+	return 0, err
+}
+
+// switchBinary points the current link at the binary of the upgrade of plan
+func switchBinary(dir string, plan upgrade.Plan) error {
+	next := path.Join(dir, "upgrades", plan.Name)
+	if _, err := os.Stat(path.Join(next, "bin", "ultron")); err != nil {
+		return errors.Errorf("upgrade %s at height %d needs its binary at %s (%s)",
+			plan.Name, plan.Height, path.Join(next, "bin", "ultron"), plan.Info)
+	}
+
+	// replace the link at once, a crash leaves either binary in place
+	link := path.Join(dir, "current")
+	os.Remove(link + ".tmp") // nolint: errcheck
+	if err := os.Symlink(next, link+".tmp"); err != nil {
+		return err
+	}
+	return os.Rename(link+".tmp", link)
+}
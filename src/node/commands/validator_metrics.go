@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"net"
+	"net/http"
+
+	"gopkg.in/urfave/cli.v1"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/dora/ultron/backend"
+	emtUtils "github.com/dora/ultron/backend/cmd/utils"
+)
+
+// newValidatorMetricsServer serves the performance of the validators on
+// /metrics of validator_metrics_addr for prometheus. It returns nil when
+// the address isn't set.
+func newValidatorMetricsServer(ctx *cli.Context, b *backend.Backend) (*http.Server, error) {
+	addr := ctx.GlobalString(emtUtils.ValidatorMetricsAddrFlag.Name)
+	if addr == "" {
+		return nil, nil
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", b.ValidatorMetrics())
+	srv := &http.Server{Handler: mux}
+	go func() {
+		if err := srv.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Error("Validator metrics server stopped", "err", err)
+		}
+	}()
+	log.Info("Serving validator metrics", "addr", addr)
+	return srv, nil
+}
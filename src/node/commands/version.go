@@ -0,0 +1,25 @@
+package commands
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/spf13/cobra"
+
+	"github.com/dora/ultron/version"
+)
+
+// GetVersionCmd - initialize the version command
+func GetVersionCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "version",
+		Short: "Print the version, the commit and the protocol of this binary",
+		Run: func(cmd *cobra.Command, args []string) {
+			commit := version.Commit
+			if commit == "" {
+				commit = "unknown"
+			}
+			fmt.Printf("ultron %s\ncommit %s\nprotocol %s\ngo %s\n", version.Version, commit, version.Protocol, runtime.Version())
+		},
+	}
+}
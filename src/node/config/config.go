@@ -4,6 +4,7 @@ import (
 	"os"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 
@@ -19,18 +20,28 @@ const (
 
 var configContent	  = (*UltronConfig)(nil)
 type UltronConfig struct {
-	BaseConfig BaseConfig      `mapstructure:",squash"`
-	TMConfig   tmcfg.Config    `mapstructure:",squash"`
-	EMConfig   EthermintConfig `mapstructure:"vm"`
-	TestConfig TConfig         `mapstructure:"test"`
+	BaseConfig    BaseConfig      `mapstructure:",squash"`
+	TMConfig      tmcfg.Config    `mapstructure:",squash"`
+	EMConfig      EthermintConfig `mapstructure:"vm"`
+	TestConfig    TConfig         `mapstructure:"test"`
+	P2PConfig     P2PConfig       `mapstructure:"p2p"`     // next to the tendermint p2p settings
+	MempoolConfig MempoolConfig   `mapstructure:"mempool"` // next to the tendermint mempool settings
+	AlertsConfig  AlertsConfig    `mapstructure:"alerts"`
+	DiskConfig    DiskConfig      `mapstructure:"disk"`
+	GCConfig      GCConfig        `mapstructure:"gc"`
 }
 
 func DefaultConfig() *UltronConfig {
 	return &UltronConfig{
-		BaseConfig: DefaultBaseConfig(),
-		TMConfig:   *tmcfg.DefaultConfig(),
-		EMConfig:   DefaultEthermintConfig(),
-		TestConfig: DefaultTestConfig(),
+		BaseConfig:    DefaultBaseConfig(),
+		TMConfig:      *tmcfg.DefaultConfig(),
+		EMConfig:      DefaultEthermintConfig(),
+		TestConfig:    DefaultTestConfig(),
+		P2PConfig:     DefaultP2PConfig(),
+		MempoolConfig: DefaultMempoolConfig(),
+		AlertsConfig:  DefaultAlertsConfig(),
+		DiskConfig:    DefaultDiskConfig(),
+		GCConfig:      DefaultGCConfig(),
 	}
 }
 
@@ -38,6 +49,14 @@ type BaseConfig struct {
 	// The root directory for all data.
 	// This should be set in viper so it can unmarshal into this struct
 	RootDir string `mapstructure:"home"`
+
+	// Replica nodes follow the blocks without taking part in the tx
+	// gossip or signing, only to serve RPC queries
+	Replica bool `mapstructure:"replica"`
+
+	// Archive nodes keep the app state of every height, for the queries
+	// of past heights
+	Archive bool `mapstructure:"archive"`
 }
 
 func DefaultBaseConfig() BaseConfig {
@@ -54,11 +73,32 @@ type EthermintConfig struct {
 	RPCPortFlag       uint   `mapstructure:"rpcport"`
 	RPCCORSDomainFlag string `mapstructure:"rpccorsdomain"`
 	RPCApiFlag        string `mapstructure:"rpcapi"`
+	IPCPath           string `mapstructure:"ipcpath"` // unix socket of the full rpc, relative to the home, empty to disable it
 	WSEnabledFlag     bool   `mapstructure:"ws"`
 	WSListenAddrFlag  string `mapstructure:"wsaddr"`
 	WSPortFlag        uint   `mapstructure:"wsport"`
 	WSApiFlag         string `mapstructure:"wsapi"`
 	VerbosityFlag     uint   `mapstructure:"verbosity"`
+	FilterDir         string `mapstructure:"filter_dir"` // eth filters shared by the RPC frontends, in memory when empty
+
+	// count the http rpc requests by method, logging those taking
+	// RPCSlowQuery or longer
+	RPCMetrics   bool          `mapstructure:"rpc_metrics"`
+	RPCSlowQuery time.Duration `mapstructure:"rpc_slow_query"`
+
+	// serves the performance of the validators to prometheus, empty for none
+	ValidatorMetricsAddr string `mapstructure:"validator_metrics_addr"`
+
+	// limits of the expensive rpc requests, 0 for none
+	RPCLogsBlockRange uint64        `mapstructure:"rpc_logs_block_range"` // blocks of eth_getLogs
+	RPCCallGasCap     uint64        `mapstructure:"rpc_call_gas_cap"`     // gas of eth_call
+	RPCCallTimeout    time.Duration `mapstructure:"rpc_call_timeout"`     // execution time of eth_call
+	RPCDumpEntries    uint64        `mapstructure:"rpc_dump_entries"`     // accounts and slots of debug_dumpBlock
+	RPCCommitTimeout  time.Duration `mapstructure:"rpc_commit_timeout"`   // wait for the block of ultron_broadcastTransaction
+
+	// signing service of eth_sendTransaction, the keystore when empty
+	SignerURL   string `mapstructure:"signer_url"`
+	SignerToken string `mapstructure:"signer_token"`
 }
 
 type TConfig struct {
@@ -79,6 +119,116 @@ type TConfig struct {
 	DisablePtx             bool         `mapstructure:"disable_ptx"`
 	ReplayTxInMempool      uint         `mapstructure:"replay_tx_mempool"`  // 0: disable, 1:same tx 2:loop tx ...
 	ReplayNumEpoch         int          `mapstructure:"replay_num_epoch"`
+	ManualMining           bool         `mapstructure:"manual_mining"`	// blocks are made by ultron_mineBlock only
+	DevMode                bool         `mapstructure:"dev_mode"`	// enables the ultron_* dev chain endpoints
+	GasAudit               bool         `mapstructure:"gas_audit"`	// runs committed blocks again to check their gas, see debug_gasAudit
+}
+
+// P2PConfig holds the p2p settings tendermint doesn't have.
+type P2PConfig struct {
+	// comma separated domains whose TXT records list seeds, "id@host:port"
+	DNSSeeds        string        `mapstructure:"dns_seeds"`
+	DNSSeedsRefresh time.Duration `mapstructure:"dns_seeds_refresh"` // how often they are resolved again
+
+	// port mapping on the NAT gateway: "none", "any", "upnp", "pmp" or
+	// "extip:<IP>" to only advertise IP
+	NAT             string `mapstructure:"nat"`
+	ExternalAddress string `mapstructure:"external_address"` // host:port peers dial, overrides the one found by NAT
+
+	// pull the pending txs of the first peers after start, see mempoolsync
+	MempoolSync bool `mapstructure:"mempool_sync"`
+}
+
+func DefaultP2PConfig() P2PConfig {
+	return P2PConfig{
+		DNSSeedsRefresh: 30 * time.Minute,
+		NAT:             "none",
+		MempoolSync:     true,
+	}
+}
+
+// MempoolConfig holds the mempool settings tendermint doesn't have.
+type MempoolConfig struct {
+	// percent of max_block_size_txs kept for the txs of staking, governance
+	// and the oracle: user txs are refused once the rest are pending
+	SystemReserve uint `mapstructure:"system_reserve"`
+
+	// log the txs of blocks included after txs arriving later than them by
+	// more than the tolerance, see ordering
+	OrderingAudit     bool          `mapstructure:"ordering_audit"`
+	OrderingTolerance time.Duration `mapstructure:"ordering_tolerance"`
+}
+
+func DefaultMempoolConfig() MempoolConfig {
+	return MempoolConfig{
+		SystemReserve:     10,
+		OrderingTolerance: time.Second,
+	}
+}
+
+// AlertsConfig sets the webhooks the trouble of the node is posted to, see
+// package alerts. With no webhook the node isn't watched.
+type AlertsConfig struct {
+	WebhookURL          string `mapstructure:"webhook_url"` // gets the alerts as json
+	SlackWebhookURL     string `mapstructure:"slack_webhook_url"`
+	PagerDutyRoutingKey string `mapstructure:"pagerduty_routing_key"` // of an events API v2 integration
+
+	// when the alerts fire, 0 turns a check off
+	CheckInterval time.Duration `mapstructure:"check_interval"`
+	StallTimeout  time.Duration `mapstructure:"stall_timeout"` // without a new block
+	MinPeers      int           `mapstructure:"min_peers"`
+	MinFreeDisk   uint64        `mapstructure:"min_free_disk"` // MB free on the disk of the home
+}
+
+func DefaultAlertsConfig() AlertsConfig {
+	return AlertsConfig{
+		CheckInterval: 10 * time.Second,
+		StallTimeout:  2 * time.Minute,
+		MinPeers:      1,
+		MinFreeDisk:   1024,
+	}
+}
+
+// DiskConfig sets how the growth of the databases is watched, see package
+// diskwatch.
+type DiskConfig struct {
+	WatchInterval time.Duration `mapstructure:"watch_interval"` // 0 not to watch
+	CompactGrowth uint64        `mapstructure:"compact_growth"` // MB the databases grow by between compactions, 0 to never compact
+	AlertHorizon  time.Duration `mapstructure:"alert_horizon"`  // alert when the disk fills up within it, 0 for no alert
+
+	// versions of the app state kept, the older ones are deleted every
+	// prune_interval, prune_batch at a time, or as the blocks commit for a
+	// prune_interval of 0, see package storeprune. Archives keep them all.
+	RetainVersions       int64         `mapstructure:"retain_versions"`
+	PruneInterval        time.Duration `mapstructure:"prune_interval"`
+	PruneBatch           int           `mapstructure:"prune_batch"`
+	PruneCompactVersions int           `mapstructure:"prune_compact_versions"` // deleted between compactions of the app state, 0 to never compact
+}
+
+func DefaultDiskConfig() DiskConfig {
+	return DiskConfig{
+		WatchInterval: time.Minute,
+		CompactGrowth: 4096,
+		AlertHorizon:  6 * time.Hour,
+
+		RetainVersions:       10,
+		PruneInterval:        time.Minute,
+		PruneBatch:           1000,
+		PruneCompactVersions: 10000,
+	}
+}
+
+// GCConfig sets up the garbage collector, see package gctune. The settings
+// left 0 are those of the profile, -1 turns a setting off.
+type GCConfig struct {
+	Profile     string `mapstructure:"profile"`      // "validator" or "rpc", "" for rpc on a replica and validator otherwise
+	GCPercent   int    `mapstructure:"gc_percent"`   // as GOGC
+	Ballast     int    `mapstructure:"ballast"`      // MB
+	MemoryLimit int    `mapstructure:"memory_limit"` // MB the heap is kept under, ballast included
+}
+
+func DefaultGCConfig() GCConfig {
+	return GCConfig{}
 }
 
 func DefaultEthermintConfig() EthermintConfig {
@@ -90,11 +240,17 @@ func DefaultEthermintConfig() EthermintConfig {
 		RPCListenAddrFlag: node.DefaultHTTPHost,
 		RPCPortFlag:       node.DefaultHTTPPort,
 		RPCApiFlag:        "eth,net,web3,personal,admin",
+		IPCPath:           "ultron.ipc",
 		WSEnabledFlag:     true,
 		WSListenAddrFlag:  node.DefaultWSHost,
 		WSPortFlag:        node.DefaultWSPort,
 		WSApiFlag:         "",
 		VerbosityFlag:     3,
+		RPCLogsBlockRange: 10000,
+		RPCCallGasCap:     50000000,
+		RPCCallTimeout:    5 * time.Second,
+		RPCDumpEntries:    10000,
+		RPCCommitTimeout:  30 * time.Second,
 	}
 }
 
@@ -127,6 +283,34 @@ func ParseConfig() (*UltronConfig, error) {
 	return conf, err
 }
 
+// LoadConfig reads the config file under rootDir, writing the default one
+// first if missing. Unlike ParseConfig it leaves the global viper alone, so
+// every node of an in-process network can have its own config. The first
+// config loaded also answers ParseConfig if nothing was parsed yet, as the
+// test settings are process wide.
+func LoadConfig(rootDir string) (*UltronConfig, error) {
+	ensureRoot(rootDir)
+
+	v := viper.New()
+	v.SetConfigFile(path.Join(rootDir, configFile))
+	if err := v.ReadInConfig(); err != nil {
+		return nil, err
+	}
+	v.Set("home", rootDir)
+
+	conf := DefaultConfig()
+	if err := v.Unmarshal(conf); err != nil {
+		return nil, err
+	}
+	conf.EMConfig.RootDir = rootDir
+	conf.TMConfig.SetRoot(rootDir)
+
+	if configContent == nil {
+		configContent = conf
+	}
+	return conf, nil
+}
+
 func ensureRoot(rootDir string) {
 	if err := cmn.EnsureDir(rootDir, 0700); err != nil {
 		cmn.PanicSanity(err.Error())
@@ -155,6 +339,8 @@ moniker = "__MONIKER__"
 fast_sync = true
 db_backend = "leveldb"
 log_level = "state:info,*:error"
+replica = false
+archive = false
 
 [rpc]
 laddr = "tcp://0.0.0.0:46657"
@@ -162,14 +348,59 @@ laddr = "tcp://0.0.0.0:46657"
 [p2p]
 laddr = "tcp://0.0.0.0:46656"
 seeds = ""
+dns_seeds = ""
+nat = "none"
+external_address = ""
+mempool_sync = true
+
+[mempool]
+system_reserve = 10
+ordering_audit = false
+ordering_tolerance = "1s"
+
+[alerts]
+webhook_url = ""
+slack_webhook_url = ""
+pagerduty_routing_key = ""
+check_interval = "10s"
+stall_timeout = "2m"
+min_peers = 1
+min_free_disk = 1024
+
+[disk]
+watch_interval = "1m"
+compact_growth = 4096
+alert_horizon = "6h"
+retain_versions = 10
+prune_interval = "1m"
+prune_batch = 1000
+prune_compact_versions = 10000
+
+[gc]
+profile = ""
+gc_percent = 0
+ballast = 0
+memory_limit = 0
 
 [vm]
 rpc = true
 rpcapi = "eth,net,web3,personal,admin"
 rpcaddr = "0.0.0.0"
 rpcport = 8545
+ipcpath = "ultron.ipc"
 ws = false
 verbosity = 1
+filter_dir = ""
+rpc_metrics = false
+rpc_slow_query = "0s"
+validator_metrics_addr = ""
+rpc_logs_block_range = 10000
+rpc_call_gas_cap = 50000000
+rpc_call_timeout = "5s"
+rpc_dump_entries = 10000
+rpc_commit_timeout = "30s"
+signer_url = ""
+signer_token = ""
 
 
 [consensus]
@@ -191,6 +422,9 @@ ptx_cycle_period = 1100
 force_validator = true
 replay_tx_mempool = 0
 replay_num_epoch = 10000
+manual_mining = false
+dev_mode = false
+gas_audit = false
 `
 
 var defaultMoniker = getDefaultMoniker()
@@ -0,0 +1,163 @@
+// Package ordering audits the order of the txs of blocks against the order
+// they reached this node in. Tendermint reaps the mempool of the proposer in
+// arrival order, so a tx included before one that arrived well earlier here
+// was either late to reach the proposer or put ahead by it. A violation
+// alone proves nothing, the arrival times are this node's own, but the
+// violations of a proposer piling up across the validators that audit it
+// point at reordering.
+package ordering
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// History is the number of violations kept.
+const History = 1000
+
+// arrivalBlocks is how many blocks the arrival of a tx is kept for
+const arrivalBlocks = 100
+
+// Violation is a tx included after one that arrived later than it by more
+// than the tolerance.
+type Violation struct {
+	Block    uint64         `json:"block"`
+	Proposer hexutil.Bytes  `json:"proposer"` // validator address
+	Tx       common.Hash    `json:"tx"`
+	Ahead    common.Hash    `json:"ahead"` // included before Tx though it arrived later
+	Gap      hexutil.Uint64 `json:"gap"`   // milliseconds Ahead arrived after Tx
+}
+
+type arrival struct {
+	at     time.Time
+	height int64
+}
+
+type included struct {
+	hash   common.Hash
+	sender common.Address
+}
+
+// Auditor checks the blocks delivered against the arrival of their txs.
+type Auditor struct {
+	tolerance time.Duration
+
+	mtx        sync.Mutex
+	arrivals   map[common.Hash]arrival
+	height     int64
+	proposer   []byte
+	block      []included
+	violations []Violation
+}
+
+// NewAuditor creates an auditor letting txs be included out of their arrival
+// order by up to tolerance.
+func NewAuditor(tolerance time.Duration) *Auditor {
+	return &Auditor{
+		tolerance: tolerance,
+		arrivals:  make(map[common.Hash]arrival),
+	}
+}
+
+// Arrived records that tx hash passed CheckTx at, while block height was
+// built. Only the first arrival counts, rechecks don't move it.
+func (a *Auditor) Arrived(hash common.Hash, at time.Time, height int64) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if _, ok := a.arrivals[hash]; !ok {
+		a.arrivals[hash] = arrival{at, height}
+	}
+}
+
+// BeginBlock starts the audit of block height, proposed by proposer.
+func (a *Auditor) BeginBlock(height int64, proposer []byte) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.height = height
+	a.proposer = proposer
+	a.block = a.block[:0]
+}
+
+// Included records the next tx of the block, sent by sender. The txs of a
+// sender go in nonce order whatever their arrival, so they are not audited
+// against each other.
+func (a *Auditor) Included(hash common.Hash, sender common.Address) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	a.block = append(a.block, included{hash, sender})
+}
+
+// EndBlock audits the block and returns its violations: for each tx, the
+// latest arrival among the txs of other senders included before it. Txs
+// that didn't arrive here aren't audited.
+func (a *Auditor) EndBlock() []Violation {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+
+	// the two latest arrivals so far, of different senders
+	var first, second *included
+	var firstAt, secondAt time.Time
+	var found []Violation
+	for i := range a.block {
+		tx := &a.block[i]
+		at, ok := a.arrivals[tx.hash]
+		delete(a.arrivals, tx.hash)
+		if !ok {
+			continue
+		}
+
+		ahead, aheadAt := first, firstAt
+		if first != nil && first.sender == tx.sender {
+			ahead, aheadAt = second, secondAt
+		}
+		if ahead != nil && aheadAt.Sub(at.at) > a.tolerance {
+			found = append(found, Violation{
+				Block:    uint64(a.height),
+				Proposer: a.proposer,
+				Tx:       tx.hash,
+				Ahead:    ahead.hash,
+				Gap:      hexutil.Uint64(aheadAt.Sub(at.at) / time.Millisecond),
+			})
+		}
+
+		switch {
+		case first == nil || at.at.After(firstAt):
+			if first != nil && first.sender != tx.sender {
+				second, secondAt = first, firstAt
+			}
+			first, firstAt = tx, at.at
+		case tx.sender != first.sender && (second == nil || at.at.After(secondAt)):
+			second, secondAt = tx, at.at
+		}
+	}
+	a.block = a.block[:0]
+
+	// txs the mempool dropped never get included
+	for hash, arrival := range a.arrivals {
+		if arrival.height+arrivalBlocks < a.height {
+			delete(a.arrivals, hash)
+		}
+	}
+
+	a.violations = append(a.violations, found...)
+	if len(a.violations) > History {
+		a.violations = append([]Violation(nil), a.violations[len(a.violations)-History:]...)
+	}
+	return found
+}
+
+// Violations returns the violations kept found since block from.
+func (a *Auditor) Violations(from uint64) []Violation {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	var since []Violation
+	for _, v := range a.violations {
+		if v.Block >= from {
+			since = append(since, v)
+		}
+	}
+	return since
+}
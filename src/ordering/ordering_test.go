@@ -0,0 +1,40 @@
+package ordering
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditor(t *testing.T) {
+	a := NewAuditor(100 * time.Millisecond)
+	start := time.Unix(1000, 0)
+	hash := func(i int) common.Hash { return common.BytesToHash([]byte{byte(i)}) }
+	alice, bob := common.HexToAddress("0xa"), common.HexToAddress("0xb")
+
+	a.Arrived(hash(1), start, 1)
+	a.Arrived(hash(2), start.Add(50*time.Millisecond), 1)
+	a.Arrived(hash(3), start.Add(time.Second), 1)
+	a.Arrived(hash(4), start.Add(2*time.Second), 1)
+	a.Arrived(hash(1), start.Add(3*time.Second), 1) // rechecked
+
+	a.BeginBlock(2, []byte{0x01})
+	a.Included(hash(2), bob)   // within the tolerance
+	a.Included(hash(4), alice) // own nonce order
+	a.Included(hash(3), alice)
+	a.Included(hash(1), bob)
+	a.Included(hash(5), bob) // didn't arrive here
+	found := a.EndBlock()
+
+	require.Len(t, found, 1)
+	assert.Equal(t, hash(1), found[0].Tx)
+	assert.Equal(t, hash(4), found[0].Ahead)
+	assert.EqualValues(t, 2000, found[0].Gap)
+	assert.EqualValues(t, 2, found[0].Block)
+
+	assert.Len(t, a.Violations(2), 1)
+	assert.Empty(t, a.Violations(3))
+}
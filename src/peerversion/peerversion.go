@@ -0,0 +1,175 @@
+// Package peerversion exchanges the version of ultron the nodes run in the
+// p2p handshake, in the other entries of their node info, and stops the
+// peers speaking another major of the protocol. Peers announcing no version
+// ran a release before it and are kept.
+//
+// The optional reactors a node runs are announced along as capabilities. A
+// node only sends the messages of one to the peers having it, so that a
+// network of mixed versions goes without them rather than dropping peers on
+// messages they don't know.
+package peerversion
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/tendermint/tendermint/p2p"
+	"github.com/tendermint/tendermint/p2p/conn"
+
+	"github.com/dora/ultron/version"
+)
+
+// keys of the node info entries
+const (
+	versionKey  = "ultron_version"
+	commitKey   = "ultron_commit"
+	protocolKey = "ultron_protocol"
+	capsKey     = "ultron_capabilities" // comma separated
+)
+
+// capabilities
+const (
+	MempoolSync   = "mempoolsync"   // see mempoolsync
+	CompactBlocks = "compactblocks" // blocks gossiped as tx hashes, tm_compact_block
+	Archive       = "archive"       // keeps the state of every height, no reactor
+)
+
+// Info is the version of a node.
+type Info struct {
+	Version  string `json:"version"`
+	Commit   string `json:"commit,omitempty"`
+	Protocol string `json:"protocol"`
+
+	Capabilities []string `json:"capabilities"`
+}
+
+// Local returns the version of this binary running the reactors of caps.
+func Local(caps ...string) Info {
+	return Info{Version: version.Version, Commit: version.Commit, Protocol: version.Protocol, Capabilities: caps}
+}
+
+// Has tells whether the node has capability.
+func (info Info) Has(capability string) bool {
+	for _, c := range info.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
+
+// PeerHas tells whether peer announced capability.
+func PeerHas(peer p2p.Peer, capability string) bool {
+	info, _ := FromNodeInfo(peer.NodeInfo())
+	return info.Has(capability)
+}
+
+// Announce adds the entries of info to the node info, replacing those
+// there already.
+func Announce(nodeInfo *p2p.NodeInfo, info Info) {
+	var other []string
+	for _, entry := range nodeInfo.Other {
+		if key := entryKey(entry); !isOurs(key) {
+			other = append(other, entry)
+		}
+	}
+	other = append(other, versionKey+"="+info.Version, protocolKey+"="+info.Protocol)
+	if info.Commit != "" {
+		other = append(other, commitKey+"="+info.Commit)
+	}
+	if len(info.Capabilities) > 0 {
+		other = append(other, capsKey+"="+strings.Join(info.Capabilities, ","))
+	}
+	nodeInfo.Other = other
+}
+
+// FromNodeInfo returns the version a peer announced, false if it announced
+// none.
+func FromNodeInfo(nodeInfo p2p.NodeInfo) (Info, bool) {
+	var info Info
+	for _, entry := range nodeInfo.Other {
+		value := entry[strings.Index(entry, "=")+1:]
+		switch entryKey(entry) {
+		case versionKey:
+			info.Version = value
+		case commitKey:
+			info.Commit = value
+		case protocolKey:
+			info.Protocol = value
+		case capsKey:
+			if value != "" {
+				info.Capabilities = strings.Split(value, ",")
+			}
+		}
+	}
+	return info, info.Protocol != ""
+}
+
+func isOurs(key string) bool {
+	return key == versionKey || key == commitKey || key == protocolKey || key == capsKey
+}
+
+func entryKey(entry string) string {
+	if i := strings.Index(entry, "="); i >= 0 {
+		return entry[:i]
+	}
+	return entry
+}
+
+// Compatible returns why a node of protocol remote can't peer with one of
+// protocol local, nil if it can.
+func Compatible(local, remote string) error {
+	l, err := major(local)
+	if err != nil {
+		return err
+	}
+	r, err := major(remote)
+	if err != nil {
+		return err
+	}
+	if l != r {
+		return fmt.Errorf("protocol %s is incompatible with ours, %s", remote, local)
+	}
+	return nil
+}
+
+func major(protocol string) (int, error) {
+	n, err := strconv.Atoi(strings.SplitN(protocol, ".", 2)[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid protocol version %q", protocol)
+	}
+	return n, nil
+}
+
+// Reactor stops the peers of another protocol major as they are added. It
+// has no channel, the versions travel in the handshake.
+type Reactor struct {
+	p2p.BaseReactor
+	local Info
+}
+
+// NewReactor creates the reactor of a node announcing local, see Announce.
+func NewReactor(local Info) *Reactor {
+	r := &Reactor{local: local}
+	r.BaseReactor = *p2p.NewBaseReactor("PeerVersionReactor", r)
+	return r
+}
+
+// GetChannels implements p2p.Reactor.
+func (r *Reactor) GetChannels() []*conn.ChannelDescriptor {
+	return nil
+}
+
+// AddPeer implements p2p.Reactor.
+func (r *Reactor) AddPeer(peer p2p.Peer) {
+	info, ok := FromNodeInfo(peer.NodeInfo())
+	if !ok {
+		r.Logger.Debug("Peer announced no version", "peer", peer.ID())
+		return
+	}
+	if err := Compatible(r.local.Protocol, info.Protocol); err != nil {
+		r.Logger.Info("Stopping the peer of another protocol", "peer", peer.ID(), "version", info.Version, "err", err)
+		r.Switch.StopPeerForError(peer, err)
+	}
+}
@@ -0,0 +1,32 @@
+package peerversion
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tendermint/tendermint/p2p"
+)
+
+func TestAnnounce(t *testing.T) {
+	nodeInfo := p2p.NodeInfo{Other: []string{"tx_index=on", "ultron_version=0.0.9"}}
+	local := Info{Version: "0.1.0", Commit: "abcdef12", Protocol: "1.2", Capabilities: []string{MempoolSync, "statestream"}}
+	Announce(&nodeInfo, local)
+	assert.Equal(t, []string{"tx_index=on", "ultron_version=0.1.0", "ultron_protocol=1.2", "ultron_commit=abcdef12",
+		"ultron_capabilities=mempoolsync,statestream"}, nodeInfo.Other)
+
+	info, ok := FromNodeInfo(nodeInfo)
+	assert.True(t, ok)
+	assert.Equal(t, local, info)
+	assert.True(t, info.Has(MempoolSync))
+	assert.False(t, info.Has(CompactBlocks))
+
+	info, ok = FromNodeInfo(p2p.NodeInfo{Other: []string{"tx_index=on"}})
+	assert.False(t, ok)
+	assert.False(t, info.Has(MempoolSync))
+}
+
+func TestCompatible(t *testing.T) {
+	assert.NoError(t, Compatible("1.0", "1.3"))
+	assert.Error(t, Compatible("1.0", "2.0"))
+	assert.Error(t, Compatible("1.0", "x"))
+}
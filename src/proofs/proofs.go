@@ -0,0 +1,117 @@
+// Package proofs links a transaction and its receipt to the app hash the
+// validators sign, so they can be checked without running a node:
+//
+//	receipt --trie proof--> ethereum header --hash--> store key
+//	  --iavl proof--> app hash --tendermint commit--> validator set
+//
+// Verify checks the ethereum half. The store proof is checked against the
+// app hash of the tendermint header at StoreHeight+1 by a light client
+// that trusts the validator set, as for any other proven query.
+package proofs
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+)
+
+// TransactionProof proves a transaction and its receipt were in a block
+// committed by the chain.
+type TransactionProof struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	TxIndex     hexutil.Uint   `json:"transactionIndex"`
+
+	Header       hexutil.Bytes   `json:"header"`       // rlp, hashes to BlockHash
+	Tx           hexutil.Bytes   `json:"transaction"`  // rlp
+	Receipt      hexutil.Bytes   `json:"receipt"`      // rlp, consensus fields
+	TxProof      []hexutil.Bytes `json:"txProof"`      // trie nodes from the tx root of Header
+	ReceiptProof []hexutil.Bytes `json:"receiptProof"` // trie nodes from the receipt root of Header
+
+	// the store of the app keeps BlockHash under StoreKey, StoreProof proves
+	// it against the app hash after block StoreHeight, which the tendermint
+	// header at StoreHeight+1 carries and Commit signs
+	StoreKey    hexutil.Bytes        `json:"storeKey"`
+	StoreProof  hexutil.Bytes        `json:"storeProof"` // go-wire encoded iavl proof
+	StoreHeight int64                `json:"storeHeight"`
+	Commit      *ctypes.ResultCommit `json:"commit,omitempty"`
+}
+
+// proofList collects the trie nodes of a proof in order.
+type proofList []hexutil.Bytes
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, common.CopyBytes(value))
+	return nil
+}
+
+// Prove returns the rlp of the item at index of list, and the trie nodes
+// proving it against the root of list.
+func Prove(list types.DerivableList, index int) (hexutil.Bytes, []hexutil.Bytes, error) {
+	t := new(trie.Trie)
+	for i := 0; i < list.Len(); i++ {
+		t.Update(indexKey(i), list.GetRlp(i))
+	}
+	var nodes proofList
+	if err := t.Prove(indexKey(index), 0, &nodes); err != nil {
+		return nil, nil, err
+	}
+	return list.GetRlp(index), nodes, nil
+}
+
+func indexKey(i int) []byte {
+	key, _ := rlp.EncodeToBytes(uint(i))
+	return key
+}
+
+// Verify checks the header of p hashes to its block hash and its tx and
+// receipt are in the block, returning the receipt.
+func Verify(p *TransactionProof) (*types.Receipt, error) {
+	if crypto.Keccak256Hash(p.Header) != p.BlockHash {
+		return nil, fmt.Errorf("header doesn't hash to block %s", p.BlockHash.Hex())
+	}
+	var header types.Header
+	if err := rlp.DecodeBytes(p.Header, &header); err != nil {
+		return nil, fmt.Errorf("decoding header: %v", err)
+	}
+	if header.Number.Uint64() != uint64(p.BlockNumber) {
+		return nil, fmt.Errorf("header is of block %d, not %d", header.Number, p.BlockNumber)
+	}
+
+	key := indexKey(int(p.TxIndex))
+	if err := verifyItem(header.TxHash, key, p.TxProof, p.Tx); err != nil {
+		return nil, fmt.Errorf("tx: %v", err)
+	}
+	if err := verifyItem(header.ReceiptHash, key, p.ReceiptProof, p.Receipt); err != nil {
+		return nil, fmt.Errorf("receipt: %v", err)
+	}
+
+	receipt := new(types.Receipt)
+	if err := rlp.DecodeBytes(p.Receipt, receipt); err != nil {
+		return nil, fmt.Errorf("decoding receipt: %v", err)
+	}
+	return receipt, nil
+}
+
+func verifyItem(root common.Hash, key []byte, nodes []hexutil.Bytes, item []byte) error {
+	db, _ := ethdb.NewMemDatabase()
+	for _, n := range nodes {
+		db.Put(crypto.Keccak256(n), n)
+	}
+	value, err, _ := trie.VerifyProof(root, key, db)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(value, item) {
+		return fmt.Errorf("proof doesn't match")
+	}
+	return nil
+}
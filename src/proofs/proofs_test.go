@@ -0,0 +1,67 @@
+package proofs
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProveAndVerify(t *testing.T) {
+	var txs types.Transactions
+	var receipts types.Receipts
+	for i := 0; i < 20; i++ {
+		to := common.BigToAddress(big.NewInt(int64(i)))
+		txs = append(txs, types.NewTransaction(uint64(i), to, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil))
+		receipt := types.NewReceipt([]byte{}, big.NewInt(int64(21000*(i+1))))
+		receipt.Logs = []*types.Log{}
+		receipts = append(receipts, receipt)
+	}
+	header := &types.Header{
+		Number:      big.NewInt(7),
+		Difficulty:  big.NewInt(1),
+		GasLimit:    big.NewInt(1),
+		GasUsed:     big.NewInt(1),
+		Time:        big.NewInt(1),
+		TxHash:      types.DeriveSha(txs),
+		ReceiptHash: types.DeriveSha(receipts),
+	}
+	headerRlp, err := rlp.EncodeToBytes(header)
+	require.Nil(t, err)
+
+	p := &TransactionProof{
+		BlockNumber: 7,
+		BlockHash:   header.Hash(),
+		TxIndex:     13,
+		Header:      headerRlp,
+	}
+	p.Tx, p.TxProof, err = Prove(txs, 13)
+	require.Nil(t, err)
+	p.Receipt, p.ReceiptProof, err = Prove(receipts, 13)
+	require.Nil(t, err)
+
+	receipt, err := Verify(p)
+	require.Nil(t, err)
+	assert.Equal(t, receipts[13].CumulativeGasUsed, receipt.CumulativeGasUsed)
+
+	// another item of the block doesn't pass for the proven one
+	other := *p
+	other.Receipt, _, _ = Prove(receipts, 12)
+	_, err = Verify(&other)
+	assert.NotNil(t, err)
+
+	other = *p
+	other.BlockHash = common.Hash{1}
+	_, err = Verify(&other)
+	assert.NotNil(t, err)
+
+	other = *p
+	other.TxProof = []hexutil.Bytes{p.TxProof[0]}
+	_, err = Verify(&other)
+	assert.NotNil(t, err)
+}
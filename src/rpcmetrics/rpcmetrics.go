@@ -0,0 +1,175 @@
+// Package rpcmetrics counts the RPC requests of the node by method, with
+// their latencies and response sizes, and logs the slow ones. Handler wraps
+// the HTTP endpoint of the RPC server, the stats are read over
+// debug_queryStats.
+package rpcmetrics
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxRequestSize is the largest body the methods are read from, larger ones
+// are counted as "unknown"
+const maxRequestSize = 5 * 1024 * 1024
+
+// maxLoggedParams is how much of the params of a slow request is logged
+const maxLoggedParams = 256
+
+// MethodStats sums up the requests of a method.
+type MethodStats struct {
+	Method  string  `json:"method"`
+	Calls   uint64  `json:"calls"`
+	Errors  uint64  `json:"errors"` // answered with an http error
+	TotalMs float64 `json:"totalMs"`
+	AvgMs   float64 `json:"avgMs"`
+	MaxMs   float64 `json:"maxMs"`
+	Bytes   uint64  `json:"bytes"` // of the responses
+}
+
+var (
+	mtx   sync.Mutex
+	stats = make(map[string]*MethodStats)
+)
+
+// Record counts a request of method.
+func Record(method string, d time.Duration, bytes int, failed bool) {
+	ms := float64(d) / float64(time.Millisecond)
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	s, ok := stats[method]
+	if !ok {
+		s = &MethodStats{Method: method}
+		stats[method] = s
+	}
+	s.Calls++
+	if failed {
+		s.Errors++
+	}
+	s.TotalMs += ms
+	if ms > s.MaxMs {
+		s.MaxMs = ms
+	}
+	s.Bytes += uint64(bytes)
+}
+
+// Stats returns the stats of the methods, the slowest in total first.
+func Stats() []MethodStats {
+	mtx.Lock()
+	defer mtx.Unlock()
+	all := make([]MethodStats, 0, len(stats))
+	for _, s := range stats {
+		copied := *s
+		copied.AvgMs = s.TotalMs / float64(s.Calls)
+		all = append(all, copied)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].TotalMs > all[j].TotalMs })
+	return all
+}
+
+// Reset clears the stats.
+func Reset() {
+	mtx.Lock()
+	stats = make(map[string]*MethodStats)
+	mtx.Unlock()
+}
+
+//----------------------------------------------------------------------
+// HTTP
+
+type request struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// Handler records the requests next serves, logging those taking slow or
+// longer when slow isn't 0. The latency of a batch is split evenly between
+// its requests.
+func Handler(next http.Handler, slow time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		reqs := readRequests(r)
+		rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+		next.ServeHTTP(rw, r)
+		elapsed := time.Since(start)
+
+		each := elapsed / time.Duration(len(reqs))
+		for _, req := range reqs {
+			Record(req.Method, each, rw.bytes/len(reqs), rw.status >= http.StatusBadRequest)
+		}
+		if slow > 0 && elapsed >= slow {
+			for _, req := range reqs {
+				params := string(req.Params)
+				if len(params) > maxLoggedParams {
+					params = params[:maxLoggedParams] + "..."
+				}
+				log.Warn("Slow RPC request", "method", req.Method, "params", params, "elapsed", elapsed,
+					"batch", len(reqs), "remote", r.RemoteAddr)
+			}
+		}
+	})
+}
+
+// readRequests reads the methods of the request or batch of r, putting its
+// body back for the server
+func readRequests(r *http.Request) []request {
+	unknown := []request{{Method: "unknown"}}
+	if r.ContentLength > maxRequestSize {
+		return unknown
+	}
+	orig := r.Body
+	body, err := ioutil.ReadAll(io.LimitReader(orig, maxRequestSize+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), orig), orig}
+	if err != nil || len(body) > maxRequestSize {
+		return unknown
+	}
+
+	body = bytes.TrimLeft(body, " \t\r\n")
+	if len(body) > 0 && body[0] == '[' {
+		var batch []request
+		if json.Unmarshal(body, &batch) != nil || len(batch) == 0 {
+			return unknown
+		}
+		return batch
+	}
+	var req request
+	if json.Unmarshal(body, &req) != nil {
+		return unknown
+	}
+	return []request{req}
+}
+
+// responseWriter keeps the status and size of a response
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
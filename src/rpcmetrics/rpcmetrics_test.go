@@ -0,0 +1,37 @@
+package rpcmetrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandler(t *testing.T) {
+	Reset()
+	defer Reset()
+
+	var served string
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		served = string(b)
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"0x1"}`))
+	}), 0)
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	assert.Equal(t, body, served)
+
+	batch := `[{"id":1,"method":"eth_getLogs","params":[{}]},{"id":2,"method":"eth_blockNumber"}]`
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/", strings.NewReader(batch)))
+	assert.Equal(t, batch, served)
+
+	calls := make(map[string]uint64)
+	for _, s := range Stats() {
+		calls[s.Method] = s.Calls
+	}
+	assert.Equal(t, map[string]uint64{"eth_blockNumber": 2, "eth_getLogs": 1}, calls)
+}
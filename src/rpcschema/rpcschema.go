@@ -0,0 +1,321 @@
+// Package rpcschema describes RPC services in an OpenRPC document, with the
+// JSON schemas of their parameters and results read from the Go types, so
+// client libraries can be generated from it. Methods are found the way the
+// go-ethereum rpc server finds them.
+package rpcschema
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// OpenRPCVersion is the version of the OpenRPC specification of Document.
+const OpenRPCVersion = "1.2.6"
+
+// Schema is a JSON schema.
+type Schema map[string]interface{}
+
+// Document is an OpenRPC document.
+type Document struct {
+	OpenRPC    string     `json:"openrpc"`
+	Info       Info       `json:"info"`
+	Methods    []Method   `json:"methods"`
+	Components Components `json:"components"`
+}
+
+// Info describes the API of a Document.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// Method is an RPC method.
+type Method struct {
+	Name   string              `json:"name"`
+	Params []ContentDescriptor `json:"params"`
+	Result *ContentDescriptor  `json:"result,omitempty"`
+}
+
+// ContentDescriptor describes a parameter or the result of a method.
+type ContentDescriptor struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Schema   Schema `json:"schema"`
+}
+
+// Describer is implemented by the types telling their own JSON schema,
+// such as parameters parsing more than their Go type says.
+type Describer interface {
+	JSONSchema() Schema
+}
+
+// Components holds the schemas of the named types methods refer to.
+type Components struct {
+	Schemas map[string]Schema `json:"schemas"`
+}
+
+var (
+	contextType   = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType     = reflect.TypeOf((*error)(nil)).Elem()
+	subscribeType = reflect.TypeOf((*rpc.Subscription)(nil))
+	textType      = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonType      = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	describerType = reflect.TypeOf((*Describer)(nil)).Elem()
+)
+
+// Quantity is the schema of hex encoded quantities.
+var Quantity = Schema{"type": "string", "pattern": "^0x([1-9a-f][0-9a-f]*|0)$", "title": "hex encoded quantity"}
+
+var (
+	quantity = Quantity
+	hexBytes = Schema{"type": "string", "pattern": "^0x([0-9a-fA-F]{2})*$", "title": "hex encoded bytes"}
+
+	// schemas of the types encoding themselves
+	knownTypes = map[reflect.Type]Schema{
+		reflect.TypeOf(common.Address{}):  {"type": "string", "pattern": "^0x[0-9a-fA-F]{40}$", "title": "address"},
+		reflect.TypeOf(common.Hash{}):     {"type": "string", "pattern": "^0x[0-9a-fA-F]{64}$", "title": "hash"},
+		reflect.TypeOf(hexutil.Big{}):     quantity,
+		reflect.TypeOf(hexutil.Uint64(0)): quantity,
+		reflect.TypeOf(hexutil.Uint(0)):   quantity,
+		reflect.TypeOf(hexutil.Bytes{}):   hexBytes,
+		reflect.TypeOf(big.Int{}):         {"type": "integer"},
+		reflect.TypeOf(time.Time{}):       {"type": "string", "format": "date-time"},
+		reflect.TypeOf(rpc.BlockNumber(0)): {"oneOf": []Schema{
+			quantity,
+			{"type": "string", "enum": []string{"earliest", "latest", "pending"}},
+		}},
+	}
+)
+
+// Generate returns the OpenRPC document of the methods of apis.
+func Generate(title, version string, apis []rpc.API) *Document {
+	g := &generator{schemas: make(map[string]Schema)}
+	doc := &Document{
+		OpenRPC: OpenRPCVersion,
+		Info:    Info{Title: title, Version: version},
+	}
+	for _, api := range apis {
+		doc.Methods = append(doc.Methods, g.methods(api.Namespace, reflect.TypeOf(api.Service))...)
+	}
+	sort.Slice(doc.Methods, func(i, j int) bool { return doc.Methods[i].Name < doc.Methods[j].Name })
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+type generator struct {
+	schemas map[string]Schema
+}
+
+// methods returns the RPC methods of the service of type t, skipping
+// subscriptions.
+func (g *generator) methods(namespace string, t reflect.Type) []Method {
+	var methods []Method
+	for i := 0; i < t.NumMethod(); i++ {
+		m := t.Method(i)
+		if m.PkgPath != "" {
+			continue
+		}
+		mt := m.Type
+
+		// the receiver, then the optional context
+		first := 1
+		if mt.NumIn() > 1 && mt.In(1) == contextType {
+			first = 2
+		}
+
+		var result reflect.Type
+		switch mt.NumOut() {
+		case 0:
+		case 1:
+			if mt.Out(0) != errorType {
+				result = mt.Out(0)
+			}
+		case 2:
+			if mt.Out(1) != errorType {
+				continue
+			}
+			result = mt.Out(0)
+		default:
+			continue
+		}
+		if result == subscribeType {
+			continue
+		}
+
+		method := Method{Name: namespace + "_" + lowerFirst(m.Name), Params: []ContentDescriptor{}}
+		// trailing pointers can be left out, as the rpc server fills in nil
+		optional := mt.NumIn()
+		for optional > first && mt.In(optional-1).Kind() == reflect.Ptr {
+			optional--
+		}
+		names := make(map[string]int)
+		for j := first; j < mt.NumIn(); j++ {
+			method.Params = append(method.Params, ContentDescriptor{
+				Name:     paramName(mt.In(j), names),
+				Required: j < optional,
+				Schema:   g.schema(mt.In(j)),
+			})
+		}
+		if result != nil {
+			method.Result = &ContentDescriptor{Name: "result", Schema: g.schema(result)}
+		}
+		methods = append(methods, method)
+	}
+	return methods
+}
+
+// schema returns the JSON schema of the values of type t as encoding/json
+// writes them, registering named structs in the components.
+func (g *generator) schema(t reflect.Type) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if s, ok := knownTypes[t]; ok {
+		return s
+	}
+	if t.Implements(describerType) {
+		return reflect.Zero(t).Interface().(Describer).JSONSchema()
+	}
+	if t.Implements(jsonType) || reflect.PtrTo(t).Implements(jsonType) {
+		// encodes itself, nothing more can be told
+		return Schema{}
+	}
+	if t.Implements(textType) || reflect.PtrTo(t).Implements(textType) {
+		return Schema{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return Schema{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		name := schemaName(t)
+		ref := Schema{"$ref": "#/components/schemas/" + name}
+		if _, ok := g.schemas[name]; !ok {
+			g.schemas[name] = Schema{} // placeholder for recursive types
+			g.schemas[name] = g.object(t)
+		}
+		return ref
+	default:
+		return Schema{}
+	}
+}
+
+// object returns the schema of the struct type t.
+func (g *generator) object(t reflect.Type) Schema {
+	properties := make(map[string]Schema)
+	var required []string
+	g.fields(t, properties, &required)
+	s := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s
+}
+
+func (g *generator) fields(t reflect.Type, properties map[string]Schema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts := tag, ""
+		if comma := strings.Index(tag, ","); comma >= 0 {
+			name, opts = tag[:comma], tag[comma:]
+		}
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				// embedded fields are encoded inline
+				g.fields(ft, properties, required)
+				continue
+			}
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		s := g.schema(f.Type)
+		if strings.Contains(opts, ",string") {
+			s = Schema{"type": "string"}
+		}
+		properties[name] = s
+		if !strings.Contains(opts, ",omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// schemaName names the schema of t after its package and name, e.g.
+// ethereum.Rules.
+func schemaName(t reflect.Type) string {
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	if pkg == "" {
+		return t.Name()
+	}
+	return pkg + "." + t.Name()
+}
+
+// paramName names a parameter after its type, as the Go types carry no
+// parameter names.
+func paramName(t reflect.Type, taken map[string]int) string {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	name := lowerFirst(t.Name())
+	if name == "" {
+		name = "param"
+	}
+	taken[name]++
+	if n := taken[name]; n > 1 {
+		return fmt.Sprintf("%s%d", name, n)
+	}
+	return name
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
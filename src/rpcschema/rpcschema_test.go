@@ -0,0 +1,77 @@
+package rpcschema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Inner struct {
+	Label string `json:"label"`
+}
+
+type Account struct {
+	Inner
+	Address common.Address `json:"address"`
+	Balance *hexutil.Big   `json:"balance,omitempty"`
+	Parent  *Account       `json:"parent"`
+	hidden  int
+}
+
+type testService struct{}
+
+func (s *testService) GetAccount(ctx context.Context, addr common.Address, number *rpc.BlockNumber) (*Account, error) {
+	return nil, nil
+}
+func (s *testService) Ping() error                                           { return nil }
+func (s *testService) Pair(a, b common.Hash) []common.Hash                   { return nil }
+func (s *testService) Events(ctx context.Context) (*rpc.Subscription, error) { return nil, nil }
+func (s *testService) notExported()                                          {}
+
+func TestGenerate(t *testing.T) {
+	doc := Generate("test", "1.0", []rpc.API{{Namespace: "test", Service: &testService{}}})
+	require.Len(t, doc.Methods, 3, "subscriptions are left out")
+
+	get := doc.Methods[0]
+	assert.Equal(t, "test_getAccount", get.Name)
+	require.Len(t, get.Params, 2)
+	assert.Equal(t, "address", get.Params[0].Name)
+	assert.True(t, get.Params[0].Required)
+	assert.Equal(t, "blockNumber", get.Params[1].Name)
+	assert.False(t, get.Params[1].Required)
+	assert.Equal(t, "#/components/schemas/rpcschema.Account", get.Result.Schema["$ref"])
+
+	account := doc.Components.Schemas["rpcschema.Account"]
+	properties := account["properties"].(map[string]Schema)
+	assert.Len(t, properties, 4, "embedded fields inline, unexported left out")
+	assert.Equal(t, "#/components/schemas/rpcschema.Account", properties["parent"]["$ref"])
+	assert.Equal(t, []string{"address", "label", "parent"}, account["required"])
+
+	pair := doc.Methods[1]
+	assert.Equal(t, "test_pair", pair.Name)
+	assert.Equal(t, "hash", pair.Params[0].Name)
+	assert.Equal(t, "hash2", pair.Params[1].Name)
+	assert.Equal(t, "array", pair.Result.Schema["type"])
+
+	assert.Equal(t, "test_ping", doc.Methods[2].Name)
+	assert.Nil(t, doc.Methods[2].Result)
+}
+
+type tag string
+
+func (tag) JSONSchema() Schema { return Schema{"enum": []string{"a", "b"}} }
+
+type describedService struct{}
+
+func (s *describedService) Pick(t tag) {}
+
+func TestDescriber(t *testing.T) {
+	doc := Generate("test", "1.0", []rpc.API{{Namespace: "test", Service: &describedService{}}})
+	require.Len(t, doc.Methods, 1)
+	assert.Equal(t, []string{"a", "b"}, doc.Methods[0].Params[0].Schema["enum"])
+}
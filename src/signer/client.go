@@ -0,0 +1,113 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/dora/ultron/ultronclient"
+)
+
+// clientTimeout is how long the signing service may take to answer
+const clientTimeout = 10 * time.Second
+
+// Client calls a signing service.
+type Client struct {
+	url   string
+	token string
+	http  http.Client
+}
+
+// NewClient creates a client of the service at url, showing token.
+func NewClient(url, token string) *Client {
+	return &Client{url: url, token: token, http: http.Client{Timeout: clientTimeout}}
+}
+
+// Accounts returns the accounts the service signs for.
+func (c *Client) Accounts() ([]common.Address, error) {
+	var accounts []common.Address
+	err := c.call(http.MethodGet, "/accounts", nil, &accounts)
+	return accounts, err
+}
+
+// SignTx has tx signed by from, for the chain chainID.
+func (c *Client) SignTx(from common.Address, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	txBytes, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	req := SignTxRequest{From: from, Tx: txBytes}
+	if chainID != nil {
+		req.ChainID = chainID.Uint64()
+	}
+	var res SignTxResponse
+	if err := c.call(http.MethodPost, "/sign_tx", req, &res); err != nil {
+		return nil, err
+	}
+	signed := new(types.Transaction)
+	if err := rlp.DecodeBytes(res.Raw, signed); err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// SignTypedData has data signed by from.
+func (c *Client) SignTypedData(from common.Address, data TypedData) ([]byte, error) {
+	var res SignTypedDataResponse
+	err := c.call(http.MethodPost, "/sign_typed_data", SignTypedDataRequest{From: from, Data: data}, &res)
+	return res.Signature, err
+}
+
+func (c *Client) call(method, path string, req, res interface{}) error {
+	var body bytes.Buffer
+	if req != nil {
+		if err := json.NewEncoder(&body).Encode(req); err != nil {
+			return err
+		}
+	}
+	httpReq, err := http.NewRequest(method, c.url+path, &body)
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+c.token)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpRes, err := c.http.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer httpRes.Body.Close() // nolint: errcheck
+
+	if httpRes.StatusCode != http.StatusOK {
+		var e errorResponse
+		if json.NewDecoder(httpRes.Body).Decode(&e) != nil || e.Error == "" {
+			e.Error = httpRes.Status
+		}
+		return fmt.Errorf("signer: %s", e.Error)
+	}
+	return json.NewDecoder(httpRes.Body).Decode(res)
+}
+
+type clientSigner struct {
+	client  *Client
+	account common.Address
+}
+
+// Signer signs the txs of account through the service.
+func (c *Client) Signer(account common.Address) ultronclient.Signer {
+	return clientSigner{c, account}
+}
+
+func (s clientSigner) Address() common.Address {
+	return s.account
+}
+
+func (s clientSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.client.SignTx(s.account, tx, chainID)
+}
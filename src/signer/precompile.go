@@ -0,0 +1,87 @@
+package signer
+
+import (
+	"math/big"
+	"sync/atomic"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// TypedDataAddress is the precompile contracts verify EIP-712 signatures
+// with. The input is the abi encoded
+// (bytes32 domainSeparator, bytes32 structHash, uint8 v, bytes32 r, bytes32 s),
+// the output the abi encoded address that signed the struct in the domain,
+// zero for an invalid signature, malleable ones with a high s included:
+//
+//	(bool ok, bytes memory out) = TYPED_DATA.staticcall(abi.encode(DOMAIN_SEPARATOR, structHash, v, r, s));
+//	address signer = abi.decode(out, (address));
+var TypedDataAddress = common.HexToAddress("0x0000000000000000000000000000000000000102")
+
+// a keccak of the digest and an ecrecover
+const typedDataGas = 3000 + 36
+
+// TypedDataUpgrade is the upgrade from which on the precompile at
+// TypedDataAddress answers. It changes the results of the calls to it, so
+// the chain switches at the height it is scheduled at.
+const TypedDataUpgrade = "typed-data-precompile"
+
+// typedDataEnabled is 1 once TypedDataUpgrade is done, see EnableTypedData
+var typedDataEnabled int32
+
+// EnableTypedData turns the precompile on or off. The app sets it from
+// TypedDataUpgrade when it starts and when each block begins. Calls outside
+// of a block, such as eth_call, see what the last block set.
+func EnableTypedData(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&typedDataEnabled, v)
+}
+
+func init() {
+	// nothing to migrate, only the calls to the precompile change
+	upgrade.RegisterHandler(TypedDataUpgrade, func(state.SimpleDB) error { return nil })
+	vm.PrecompiledContractsHomestead[TypedDataAddress] = &typedDataPrecompile{}
+	vm.PrecompiledContractsByzantium[TypedDataAddress] = &typedDataPrecompile{}
+}
+
+// typedDataPrecompile costs nothing and returns nothing before
+// TypedDataUpgrade, as the account without code at its address did.
+type typedDataPrecompile struct{}
+
+func (c *typedDataPrecompile) RequiredGas(input []byte) uint64 {
+	if atomic.LoadInt32(&typedDataEnabled) == 0 {
+		return 0
+	}
+	return typedDataGas
+}
+
+func (c *typedDataPrecompile) Run(input []byte) ([]byte, error) {
+	if atomic.LoadInt32(&typedDataEnabled) == 0 {
+		return nil, nil
+	}
+	input = common.RightPadBytes(input, 160)
+	out := make([]byte, 32)
+
+	v := new(big.Int).SetBytes(input[64:96])
+	r := new(big.Int).SetBytes(input[96:128])
+	s := new(big.Int).SetBytes(input[128:160])
+	if v.BitLen() > 8 || (v.Uint64() != 27 && v.Uint64() != 28) ||
+		!crypto.ValidateSignatureValues(byte(v.Uint64()-27), r, s, true) {
+		return out, nil
+	}
+	hash := digest(input[:32], input[32:64])
+	sig := append(append([]byte{}, input[96:160]...), byte(v.Uint64()-27))
+	pub, err := crypto.Ecrecover(hash[:], sig)
+	if err != nil {
+		return out, nil
+	}
+	copy(out[12:], crypto.Keccak256(pub[1:])[12:])
+	return out, nil
+}
@@ -0,0 +1,52 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedDataPrecompile(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	data := mail(t)
+	hash, err := data.Hash()
+	require.NoError(t, err)
+	sig, err := crypto.Sign(hash[:], key)
+	require.NoError(t, err)
+	sig[64] += 27
+
+	recovered, err := data.Recover(sig)
+	require.NoError(t, err)
+	assert.Equal(t, from, recovered)
+
+	domain, err := data.hashStruct(domainType, data.Domain)
+	require.NoError(t, err)
+	message, err := data.hashStruct(data.PrimaryType, data.Message)
+	require.NoError(t, err)
+	input := append(append(domain, message...), common.LeftPadBytes(sig[64:], 32)...)
+	input = append(input, sig[:64]...)
+
+	// before the upgrade it runs as an account without code
+	EnableTypedData(false)
+	assert.Equal(t, uint64(0), (&typedDataPrecompile{}).RequiredGas(input))
+	out, err := (&typedDataPrecompile{}).Run(input)
+	require.NoError(t, err)
+	assert.Nil(t, out)
+
+	EnableTypedData(true)
+	defer EnableTypedData(false)
+	out, err = (&typedDataPrecompile{}).Run(input)
+	require.NoError(t, err)
+	assert.Equal(t, common.LeftPadBytes(from.Bytes(), 32), out)
+
+	// zero for an invalid v
+	input[95] = 29
+	out, err = (&typedDataPrecompile{}).Run(input)
+	require.NoError(t, err)
+	assert.Equal(t, make([]byte, 32), out)
+}
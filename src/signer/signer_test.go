@@ -0,0 +1,54 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	srv := httptest.NewServer(NewServer([]*ecdsa.PrivateKey{key}, []string{"old", "new"}))
+	defer srv.Close()
+	client := NewClient(srv.URL, "new")
+
+	accounts, err := client.Accounts()
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{from}, accounts)
+
+	chainID := big.NewInt(188)
+	tx := types.NewTransaction(3, common.Address{1}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil)
+	signed, err := client.SignTx(from, tx, chainID)
+	require.NoError(t, err)
+	sender, err := types.Sender(types.NewEIP155Signer(chainID), signed)
+	require.NoError(t, err)
+	assert.Equal(t, from, sender)
+	assert.Equal(t, uint64(3), signed.Nonce())
+
+	data := mail(t)
+	sig, err := client.SignTypedData(from, data)
+	require.NoError(t, err)
+	require.Len(t, sig, 65)
+	hash, err := data.Hash()
+	require.NoError(t, err)
+	sig[64] -= 27
+	pub, err := crypto.SigToPub(hash[:], sig)
+	require.NoError(t, err)
+	assert.Equal(t, from, crypto.PubkeyToAddress(*pub))
+
+	// for its accounts only
+	_, err = client.SignTx(common.Address{2}, tx, chainID)
+	assert.Error(t, err)
+	// with a token
+	_, err = NewClient(srv.URL, "wrong").Accounts()
+	assert.Error(t, err)
+}
@@ -0,0 +1,280 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// TypedField is a member of a struct type of typed data.
+type TypedField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is structured data to sign as EIP-712 sets, as eth_signTypedData
+// of wallets takes it. Its types include EIP712Domain.
+type TypedData struct {
+	Types       map[string][]TypedField `json:"types"`
+	PrimaryType string                  `json:"primaryType"`
+	Domain      map[string]interface{}  `json:"domain"`
+	Message     map[string]interface{}  `json:"message"`
+}
+
+const domainType = "EIP712Domain"
+
+// Hash returns the hash signed for d:
+// keccak256("\x19\x01" ‖ hashStruct(domain) ‖ hashStruct(message)).
+func (d *TypedData) Hash() (common.Hash, error) {
+	if _, ok := d.Types[domainType]; !ok {
+		return common.Hash{}, fmt.Errorf("no %s type", domainType)
+	}
+	domain, err := d.hashStruct(domainType, d.Domain)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("domain: %v", err)
+	}
+	message, err := d.hashStruct(d.PrimaryType, d.Message)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("message: %v", err)
+	}
+	return digest(domain, message), nil
+}
+
+// digest returns the hash signed for the message of hashStruct message in
+// the domain of hashStruct domain
+func digest(domain, message []byte) common.Hash {
+	return crypto.Keccak256Hash([]byte{0x19, 0x01}, domain, message)
+}
+
+// Recover returns the account that signed d with sig, r ‖ s ‖ v with v
+// being 27 or 28 as wallets sign.
+func (d *TypedData) Recover(sig []byte) (common.Address, error) {
+	if len(sig) != 65 || (sig[64] != 27 && sig[64] != 28) {
+		return common.Address{}, fmt.Errorf("invalid signature")
+	}
+	hash, err := d.Hash()
+	if err != nil {
+		return common.Address{}, err
+	}
+	rsv := append([]byte{}, sig...)
+	rsv[64] -= 27
+	pub, err := crypto.SigToPub(hash[:], rsv)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+func (d *TypedData) hashStruct(name string, data map[string]interface{}) ([]byte, error) {
+	enc, err := d.encodeData(name, data)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(enc), nil
+}
+
+// encodeType returns the type of name along with the struct types it
+// refers to, in the alphabetical order
+func (d *TypedData) encodeType(name string) string {
+	deps := map[string]bool{}
+	d.dependencies(name, deps)
+	delete(deps, name)
+	sorted := make([]string, 0, len(deps))
+	for dep := range deps {
+		sorted = append(sorted, dep)
+	}
+	sort.Strings(sorted)
+
+	var buf bytes.Buffer
+	for _, t := range append([]string{name}, sorted...) {
+		buf.WriteString(t + "(")
+		for i, field := range d.Types[t] {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(field.Type + " " + field.Name)
+		}
+		buf.WriteString(")")
+	}
+	return buf.String()
+}
+
+func (d *TypedData) dependencies(name string, deps map[string]bool) {
+	name = strings.TrimSuffix(name, "[]")
+	if i := strings.Index(name, "["); i >= 0 {
+		name = name[:i]
+	}
+	if deps[name] {
+		return
+	}
+	fields, ok := d.Types[name]
+	if !ok {
+		return
+	}
+	deps[name] = true
+	for _, field := range fields {
+		d.dependencies(field.Type, deps)
+	}
+}
+
+func (d *TypedData) encodeData(name string, data map[string]interface{}) ([]byte, error) {
+	fields, ok := d.Types[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %s", name)
+	}
+	enc := crypto.Keccak256([]byte(d.encodeType(name)))
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s.%s missing", name, field.Name)
+		}
+		word, err := d.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", name, field.Name, err)
+		}
+		enc = append(enc, word...)
+	}
+	return enc, nil
+}
+
+var (
+	arrayType = regexp.MustCompile(`^(.*)\[([0-9]*)\]$`)
+	intType   = regexp.MustCompile(`^(u?)int([0-9]*)$`)
+	bytesType = regexp.MustCompile(`^bytes([0-9]+)$`)
+)
+
+// encodeValue returns the 32 bytes encoding value of typ
+func (d *TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	if m := arrayType.FindStringSubmatch(typ); m != nil {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("not an array")
+		}
+		if m[2] != "" && strconv.Itoa(len(items)) != m[2] {
+			return nil, fmt.Errorf("%d items instead of %s", len(items), m[2])
+		}
+		var enc []byte
+		for _, item := range items {
+			word, err := d.encodeValue(m[1], item)
+			if err != nil {
+				return nil, err
+			}
+			enc = append(enc, word...)
+		}
+		return crypto.Keccak256(enc), nil
+	}
+	if _, ok := d.Types[typ]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("not a %s", typ)
+		}
+		return d.hashStruct(typ, data)
+	}
+
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("not a string")
+		}
+		return crypto.Keccak256([]byte(s)), nil
+	case "bytes":
+		b, err := parseBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(b), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("not a bool")
+		}
+		if b {
+			return math.PaddedBigBytes(big.NewInt(1), 32), nil
+		}
+		return make([]byte, 32), nil
+	case "address":
+		s, ok := value.(string)
+		if !ok || !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("not an address")
+		}
+		return common.LeftPadBytes(common.HexToAddress(s).Bytes(), 32), nil
+	}
+
+	if m := bytesType.FindStringSubmatch(typ); m != nil {
+		size, _ := strconv.Atoi(m[1])
+		b, err := parseBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if size < 1 || size > 32 || len(b) != size {
+			return nil, fmt.Errorf("not a %s", typ)
+		}
+		return common.RightPadBytes(b, 32), nil
+	}
+	if m := intType.FindStringSubmatch(typ); m != nil {
+		bits := 256
+		if m[2] != "" {
+			bits, _ = strconv.Atoi(m[2])
+		}
+		n, err := parseInt(value)
+		if err != nil {
+			return nil, err
+		}
+		if bits < 8 || bits > 256 || bits%8 != 0 || !intFits(n, bits, m[1] == "u") {
+			return nil, fmt.Errorf("%s out of %s", n, typ)
+		}
+		return math.PaddedBigBytes(math.U256(new(big.Int).Set(n)), 32), nil
+	}
+	return nil, fmt.Errorf("unknown type %s", typ)
+}
+
+// intFits tells if n is an integer of bits
+func intFits(n *big.Int, bits int, unsigned bool) bool {
+	if unsigned {
+		return n.Sign() >= 0 && n.BitLen() <= bits
+	}
+	limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+	return n.Cmp(limit) < 0 && n.Cmp(new(big.Int).Neg(limit)) >= 0
+}
+
+func parseBytes(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("not hex bytes")
+	}
+	return hexutil.Decode(s)
+}
+
+// parseInt reads a json number, or a string of a decimal or 0x number
+func parseInt(value interface{}) (*big.Int, error) {
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case string:
+		s = v
+	default:
+		return nil, fmt.Errorf("not a number")
+	}
+	n, ok := math.ParseBig256(s)
+	if !ok {
+		// negative numbers are decimal
+		if n, ok = new(big.Int).SetString(s, 10); !ok {
+			return nil, fmt.Errorf("invalid number %s", s)
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,90 @@
+package signer
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// the example of EIP-712
+const mailJSON = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func mail(t *testing.T) TypedData {
+	var data TypedData
+	require.NoError(t, json.Unmarshal([]byte(mailJSON), &data))
+	return data
+}
+
+func TestTypedDataHash(t *testing.T) {
+	data := mail(t)
+	assert.Equal(t, "Mail(Person from,Person to,string contents)Person(string name,address wallet)", data.encodeType("Mail"))
+
+	hash, err := data.Hash()
+	require.NoError(t, err)
+	assert.Equal(t, "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", fmt.Sprintf("%x", hash[:]))
+
+	data.Message["contents"] = 1
+	_, err = data.Hash()
+	assert.Error(t, err)
+	delete(data.Message, "contents")
+	_, err = data.Hash()
+	assert.Error(t, err)
+}
+
+func TestEncodeValue(t *testing.T) {
+	data := mail(t)
+	for _, c := range []struct {
+		typ   string
+		value interface{}
+		ok    bool
+	}{
+		{"uint8", json.Number("255"), true},
+		{"uint8", json.Number("256"), false},
+		{"uint256", "-1", false},
+		{"int8", "-128", true},
+		{"int8", json.Number("128"), false},
+		{"bytes4", "0x01020304", true},
+		{"bytes4", "0x010203", false},
+		{"bool", true, true},
+		{"uint256[2]", []interface{}{"1", "0x2"}, true},
+		{"uint256[2]", []interface{}{"1"}, false},
+		{"Person[]", []interface{}{map[string]interface{}{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"}}, true},
+		{"float", 1.5, false},
+	} {
+		_, err := data.encodeValue(c.typ, c.value)
+		assert.Equal(t, c.ok, err == nil, "%s %v: %v", c.typ, c.value, err)
+	}
+}
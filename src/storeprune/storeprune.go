@@ -0,0 +1,157 @@
+// Package storeprune deletes the old versions of the app state in the
+// background. Each version of the IAVL tree of merkleeyes keeps the nodes
+// it replaced, orphaned, until the version is deleted. Deleting the versions
+// out of the retention in batches every interval, rather than one as every
+// block commits, keeps the commits short and also catches up with the
+// versions a node kept before, say while it ran as an archive.
+//
+// A deletion only frees the space of the orphans for leveldb, which gives
+// it back as it compacts: the store is compacted every CompactAfter
+// versions deleted, and the space it took back is reported in the stats.
+package storeprune
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Config sets what is kept and how fast the rest goes.
+type Config struct {
+	Retain       int64         // latest versions kept, at least 1
+	Interval     time.Duration // between two passes
+	Batch        int           // versions deleted in a pass at most
+	CompactAfter int           // versions deleted between two compactions, 0 to never compact
+}
+
+// Store is the app state pruned.
+type Store struct {
+	Dir string // of its database, measured for the stats
+
+	// Prune deletes up to max versions older than the retain latest ones,
+	// oldest first, and returns how many it deleted. It is called from the
+	// pruner goroutine and must hold the blocks while it runs.
+	Prune func(retain int64, max int) (int, error)
+
+	Compact func() error // nil if it can't be compacted
+}
+
+// Stats sums up the pruning since the node started.
+type Stats struct {
+	Retain    int64     `json:"retain"`
+	Passes    uint64    `json:"passes"`
+	Versions  uint64    `json:"versions"`  // deleted
+	PausedMs  float64   `json:"pausedMs"`  // the blocks waited for the deletions
+	Compacted uint64    `json:"compacted"` // compactions
+	Reclaimed uint64    `json:"reclaimed"` // bytes the compactions took back
+	Size      uint64    `json:"size"`      // bytes of the database at the last pass
+	LastPass  time.Time `json:"lastPass"`
+}
+
+// Pruner deletes the versions of a store every interval.
+type Pruner struct {
+	conf  Config
+	store Store
+
+	mtx     sync.Mutex
+	stats   Stats
+	pending int // versions deleted since the last compaction
+
+	quit     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPruner creates a pruner of store.
+func NewPruner(conf Config, store Store) *Pruner {
+	return &Pruner{conf: conf, store: store, stats: Stats{Retain: conf.Retain}, quit: make(chan struct{})}
+}
+
+// Start prunes in the background until Stop.
+func (p *Pruner) Start() {
+	go func() {
+		ticker := time.NewTicker(p.conf.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				p.Prune(now)
+			case <-p.quit:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the pruning.
+func (p *Pruner) Stop() {
+	p.stopOnce.Do(func() { close(p.quit) })
+}
+
+// Prune runs a pass as of now. It is called by Start, and by tests.
+func (p *Pruner) Prune(now time.Time) {
+	start := time.Now()
+	deleted, err := p.store.Prune(p.conf.Retain, p.conf.Batch)
+	paused := time.Since(start)
+	if err != nil {
+		log.Error("Failed to prune the app state", "err", err)
+	}
+
+	p.mtx.Lock()
+	p.stats.Passes++
+	p.stats.Versions += uint64(deleted)
+	p.stats.PausedMs += float64(paused) / float64(time.Millisecond)
+	p.stats.LastPass = now
+	p.pending += deleted
+	compact := p.store.Compact != nil && p.conf.CompactAfter > 0 && p.pending >= p.conf.CompactAfter
+	if compact {
+		p.pending = 0
+	}
+	p.mtx.Unlock()
+	if deleted > 0 {
+		log.Info("Pruned the app state", "versions", deleted, "paused", paused)
+	}
+
+	size := dirSize(p.store.Dir)
+	var reclaimed uint64
+	if compact {
+		if err := p.store.Compact(); err != nil {
+			log.Error("Failed to compact the app state", "err", err)
+		}
+		after := dirSize(p.store.Dir)
+		if after < size {
+			reclaimed = size - after
+		}
+		log.Info("Compacted the app state", "before", size>>20, "after", after>>20, "unit", "MB")
+		size = after
+	}
+
+	p.mtx.Lock()
+	p.stats.Size = size
+	if compact {
+		p.stats.Compacted++
+		p.stats.Reclaimed += reclaimed
+	}
+	p.mtx.Unlock()
+}
+
+// Stats returns the stats of the pruning so far.
+func (p *Pruner) Stats() Stats {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	return p.stats
+}
+
+// dirSize sums the sizes of the files under dir
+func dirSize(dir string) uint64 {
+	var size uint64
+	filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error { // nolint: errcheck
+		if err == nil && info.Mode().IsRegular() {
+			size += uint64(info.Size())
+		}
+		return nil
+	})
+	return size
+}
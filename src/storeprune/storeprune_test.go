@@ -0,0 +1,57 @@
+package storeprune
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPruner(t *testing.T) {
+	dir, err := ioutil.TempDir("", "storeprune")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir) // nolint: errcheck
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "data"), make([]byte, 100), 0600))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "orphans"), make([]byte, 400), 0600))
+
+	// versions 1 to 10, the last 3 kept
+	versions, latest := int64(1), int64(10)
+	store := Store{
+		Dir: dir,
+		Prune: func(retain int64, max int) (int, error) {
+			n := 0
+			for ; versions <= latest-retain && n < max; versions++ {
+				n++
+			}
+			return n, nil
+		},
+		Compact: func() error { return os.Remove(filepath.Join(dir, "orphans")) },
+	}
+	p := NewPruner(Config{Retain: 3, Batch: 4, CompactAfter: 6}, store)
+	now := time.Now()
+
+	p.Prune(now)
+	stats := p.Stats()
+	assert.Equal(t, uint64(4), stats.Versions)
+	assert.Zero(t, stats.Compacted)
+	assert.Equal(t, uint64(500), stats.Size)
+
+	// compacted once 6 versions are gone
+	p.Prune(now.Add(time.Second))
+	stats = p.Stats()
+	assert.Equal(t, uint64(7), stats.Versions)
+	assert.Equal(t, int64(8), versions)
+	assert.Equal(t, uint64(1), stats.Compacted)
+	assert.Equal(t, uint64(400), stats.Reclaimed)
+	assert.Equal(t, uint64(100), stats.Size)
+
+	p.Prune(now.Add(2 * time.Second))
+	stats = p.Stats()
+	assert.Equal(t, uint64(3), stats.Passes)
+	assert.Equal(t, uint64(7), stats.Versions)
+	assert.Equal(t, now.Add(2*time.Second), stats.LastPass)
+}
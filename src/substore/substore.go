@@ -0,0 +1,257 @@
+// Package substore gives each module of the app its part of the app state,
+// the keys under its prefixes. A sub-store reads, writes and lists the keys
+// of a prefix without it, so a module never sees the keys of another. The
+// modules register their prefixes as they are loaded, and two modules
+// taking overlapping prefixes panic at start instead of writing over each
+// other's keys.
+//
+// The modules share the one IAVL tree of the app state: a key of a
+// sub-store is proven against the app hash by the proof of its full key,
+// see Store.Key. The named sub-stores, staking/, gov/, bridge/ and params/,
+// group the modules by concern with a query path of their own, see Named.
+// They keep the keys of their modules, and so the versions and the pruning
+// of the app state.
+package substore
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cosmos/cosmos-sdk/state"
+)
+
+// Store is the part of a store under a prefix.
+type Store struct {
+	parent state.SimpleDB
+	prefix []byte
+}
+
+// New returns the part of parent under prefix.
+func New(parent state.SimpleDB, prefix []byte) Store {
+	return Store{parent: parent, prefix: prefix}
+}
+
+// Key returns the key of parent holding key, to query it with its proof.
+func (s Store) Key(key []byte) []byte {
+	return append(append(make([]byte, 0, len(s.prefix)+len(key)), s.prefix...), key...)
+}
+
+// Get returns the value of key, nil if unset.
+func (s Store) Get(key []byte) []byte {
+	return s.parent.Get(s.Key(key))
+}
+
+// Has tells whether key is set.
+func (s Store) Has(key []byte) bool {
+	return s.parent.Has(s.Key(key))
+}
+
+// Set sets key to value.
+func (s Store) Set(key, value []byte) {
+	s.parent.Set(s.Key(key), value)
+}
+
+// Remove unsets key.
+func (s Store) Remove(key []byte) {
+	s.parent.Remove(s.Key(key))
+}
+
+// List returns up to limit keys from start to end, end excluded, in order,
+// all of them for a limit of 0. A nil start is the first key of the store,
+// a nil end the last one.
+func (s Store) List(start, end []byte, limit int) []state.Model {
+	models := s.parent.List(s.Key(start), s.end(end), limit)
+	for i := range models {
+		models[i].Key = models[i].Key[len(s.prefix):]
+	}
+	return models
+}
+
+// First returns the first key from start to end, a model with a nil key
+// if there is none.
+func (s Store) First(start, end []byte) state.Model {
+	return s.strip(s.parent.First(s.Key(start), s.end(end)))
+}
+
+// Last returns the last key from start to end, a model with a nil key if
+// there is none.
+func (s Store) Last(start, end []byte) state.Model {
+	return s.strip(s.parent.Last(s.Key(start), s.end(end)))
+}
+
+func (s Store) end(end []byte) []byte {
+	if end == nil {
+		return prefixEnd(s.prefix)
+	}
+	return s.Key(end)
+}
+
+func (s Store) strip(m state.Model) state.Model {
+	if m.Key != nil {
+		m.Key = m.Key[len(s.prefix):]
+	}
+	return m
+}
+
+// prefixEnd returns the first key after the keys starting with prefix, nil
+// if there is none
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
+
+//---------------------------------------------------------------------
+
+var (
+	registryMtx sync.Mutex
+	registry    = make(map[string][][]byte) // prefixes by module
+)
+
+// Register takes prefixes for module, panicking if one of them is a prefix
+// of a key of another registered prefix: the keys of two modules would
+// mix.
+func Register(module string, prefixes ...[]byte) {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+	for i, prefix := range prefixes {
+		if len(prefix) == 0 {
+			panic(fmt.Sprintf("substore: empty prefix of module %s", module))
+		}
+		for owner, taken := range registry {
+			for _, t := range taken {
+				if overlap(prefix, t) {
+					panic(fmt.Sprintf("substore: prefix %x of module %s overlaps prefix %x of module %s",
+						prefix, module, t, owner))
+				}
+			}
+		}
+		for _, t := range prefixes[:i] {
+			if overlap(prefix, t) {
+				panic(fmt.Sprintf("substore: prefixes %x and %x of module %s overlap", t, prefix, module))
+			}
+		}
+	}
+	registry[module] = append(registry[module], prefixes...)
+}
+
+// Owner returns the module registered a prefix of key, false if none did.
+func Owner(key []byte) (string, bool) {
+	registryMtx.Lock()
+	defer registryMtx.Unlock()
+	for module, prefixes := range registry {
+		for _, prefix := range prefixes {
+			if bytes.HasPrefix(key, prefix) {
+				return module, true
+			}
+		}
+	}
+	return "", false
+}
+
+func overlap(a, b []byte) bool {
+	return bytes.HasPrefix(a, b) || bytes.HasPrefix(b, a)
+}
+
+//---------------------------------------------------------------------
+
+// the modules of the named sub-stores
+var names = map[string][]string{
+	"staking": {"stake", "mint", "supply"},
+	"gov":     {"upgrade", "emergency", "community", "recovery"},
+	"bridge":  {"blockhashes"},
+	"params":  {"params"},
+}
+
+// Named is the part of a store of the modules of a name, their keys kept
+// whole. Its keys are queried with their proofs at /<name>/key, see
+// OpenPath.
+type Named struct {
+	parent   state.SimpleDB
+	name     string
+	prefixes [][]byte // in order
+}
+
+// Open returns the sub-store named name of parent, false if there is none.
+func Open(parent state.SimpleDB, name string) (Named, bool) {
+	modules, ok := names[name]
+	if !ok {
+		return Named{}, false
+	}
+	registryMtx.Lock()
+	var prefixes [][]byte
+	for _, module := range modules {
+		prefixes = append(prefixes, registry[module]...)
+	}
+	registryMtx.Unlock()
+	sort.Slice(prefixes, func(i, j int) bool { return bytes.Compare(prefixes[i], prefixes[j]) < 0 })
+	return Named{parent: parent, name: name, prefixes: prefixes}, true
+}
+
+// OpenPath returns the sub-store of the query path /<name>/key, false if
+// path is none.
+func OpenPath(parent state.SimpleDB, path string) (Named, bool) {
+	if !strings.HasPrefix(path, "/") || !strings.HasSuffix(path, "/key") {
+		return Named{}, false
+	}
+	return Open(parent, strings.TrimSuffix(path[1:], "/key"))
+}
+
+// Name returns the name of the sub-store.
+func (n Named) Name() string {
+	return n.name
+}
+
+// Contains tells whether key is of the sub-store.
+func (n Named) Contains(key []byte) bool {
+	for _, prefix := range n.prefixes {
+		if bytes.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Get returns the value of key, nil if unset or not of the sub-store.
+func (n Named) Get(key []byte) []byte {
+	if !n.Contains(key) {
+		return nil
+	}
+	return n.parent.Get(key)
+}
+
+// List returns up to limit keys of the sub-store from start to end, end
+// excluded, in order, all of them for a limit of 0. A nil start is the
+// first key of the sub-store, a nil end the last one.
+func (n Named) List(start, end []byte, limit int) []state.Model {
+	var models []state.Model
+	for _, prefix := range n.prefixes {
+		from, to := prefix, prefixEnd(prefix)
+		if start != nil && bytes.Compare(start, from) > 0 {
+			from = start
+		}
+		if end != nil && (to == nil || bytes.Compare(end, to) < 0) {
+			to = end
+		}
+		if to != nil && bytes.Compare(from, to) >= 0 {
+			continue
+		}
+		left := 0
+		if limit > 0 {
+			left = limit - len(models)
+		}
+		models = append(models, n.parent.List(from, to, left)...)
+		if limit > 0 && len(models) >= limit {
+			break
+		}
+	}
+	return models
+}
@@ -0,0 +1,98 @@
+package substore
+
+import (
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	parent := state.NewMemKVStore()
+	parent.Set([]byte{0x01, 'z'}, []byte("before"))
+	parent.Set([]byte{0x03, 'a'}, []byte("after"))
+
+	s := New(parent, []byte{0x02})
+	s.Set([]byte("b"), []byte("2"))
+	s.Set([]byte("a"), []byte("1"))
+	s.Set([]byte("c"), []byte("3"))
+	assert.Equal(t, []byte("2"), parent.Get([]byte{0x02, 'b'}))
+	assert.Equal(t, []byte{0x02, 'b'}, s.Key([]byte("b")))
+	assert.True(t, s.Has([]byte("a")))
+	assert.Nil(t, s.Get([]byte("z")))
+
+	models := s.List(nil, nil, 0)
+	if assert.Len(t, models, 3) {
+		assert.Equal(t, []byte("a"), models[0].Key)
+		assert.Equal(t, []byte("c"), models[2].Key)
+	}
+	assert.Len(t, s.List([]byte("b"), nil, 0), 2)
+	assert.Len(t, s.List(nil, []byte("c"), 0), 2)
+	assert.Equal(t, []byte("a"), s.First(nil, nil).Key)
+	assert.Equal(t, []byte("c"), s.Last(nil, nil).Key)
+
+	s.Remove([]byte("a"))
+	assert.False(t, s.Has([]byte("a")))
+	assert.Nil(t, New(parent, []byte{0x04}).First(nil, nil).Key)
+}
+
+func TestPrefixEnd(t *testing.T) {
+	assert.Equal(t, []byte{0x03}, prefixEnd([]byte{0x02}))
+	assert.Equal(t, []byte{0x03}, prefixEnd([]byte{0x02, 0xff}))
+	assert.Nil(t, prefixEnd([]byte{0xff}))
+}
+
+func TestRegister(t *testing.T) {
+	Register("test-a", []byte{0xf0}, []byte{0xf1, 'x'})
+	Register("test-b", []byte{0xf1, 'y'})
+	assert.Panics(t, func() { Register("test-c", []byte{0xf0, 0x01}) })
+	assert.Panics(t, func() { Register("test-c", []byte{0xf1}) })
+	assert.Panics(t, func() { Register("test-c", []byte{0xf2}, []byte{0xf2}) })
+
+	module, ok := Owner([]byte{0xf1, 'y', 0x01})
+	assert.True(t, ok)
+	assert.Equal(t, "test-b", module)
+	_, ok = Owner([]byte{0xf3})
+	assert.False(t, ok)
+}
+
+func TestNamed(t *testing.T) {
+	Register("params", []byte{0xe0})
+	parent := state.NewMemKVStore()
+	parent.Set([]byte{0xe0, 'a'}, []byte("param"))
+	parent.Set([]byte{0xe1, 'a'}, []byte("other"))
+
+	n, ok := OpenPath(parent, "/params/key")
+	assert.True(t, ok)
+	assert.Equal(t, "params", n.Name())
+	assert.True(t, n.Contains([]byte{0xe0, 'b'}))
+	assert.False(t, n.Contains([]byte{0xe1, 'a'}))
+	assert.Equal(t, []byte("param"), n.Get([]byte{0xe0, 'a'}))
+	assert.Nil(t, n.Get([]byte{0xe1, 'a'}))
+	models := n.List(nil, nil, 0)
+	if assert.Len(t, models, 1) {
+		assert.Equal(t, []byte{0xe0, 'a'}, models[0].Key)
+	}
+
+	// the keys of several modules, in order
+	Register("mint", []byte{0xe4})
+	Register("stake", []byte{0xe2})
+	parent.Set([]byte{0xe4, 'm'}, []byte("minter"))
+	parent.Set([]byte{0xe2, 'b'}, []byte("b"))
+	parent.Set([]byte{0xe2, 'a'}, []byte("a"))
+	staking, ok := Open(parent, "staking")
+	assert.True(t, ok)
+	models = staking.List(nil, nil, 0)
+	if assert.Len(t, models, 3) {
+		assert.Equal(t, []byte{0xe2, 'a'}, models[0].Key)
+		assert.Equal(t, []byte{0xe4, 'm'}, models[2].Key)
+	}
+	assert.Len(t, staking.List(nil, nil, 2), 2)
+	assert.Len(t, staking.List([]byte{0xe2, 'b'}, nil, 0), 2)
+	assert.Len(t, staking.List(nil, []byte{0xe4}, 0), 2)
+
+	_, ok = OpenPath(parent, "/nothing/key")
+	assert.False(t, ok)
+	_, ok = OpenPath(parent, "/params")
+	assert.False(t, ok)
+}
@@ -0,0 +1,193 @@
+// Package network spins up ultron networks inside the test process.
+//
+// Every node gets its own home, ports and private validator while sharing
+// one genesis, so integration tests can run against a fresh multi-validator
+// chain instead of the node a previous test left behind:
+//
+//	net, err := network.New(network.DefaultConfig())
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer net.Cleanup()
+//	err = net.WaitForHeight(3, time.Minute)
+package network
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dora/ultron/node/commands"
+	emtConfig "github.com/dora/ultron/node/config"
+)
+
+// portsPerNode is the number of consecutive ports a node takes:
+// tendermint rpc, tendermint p2p, abci and ethereum rpc.
+const portsPerNode = 4
+
+// Config describes the network to create.
+type Config struct {
+	NumNodes      int           // number of validators, at most 4
+	ChainID       string        // tendermint chain id
+	BaseDir       string        // parent of the node homes, a temp dir if empty
+	BasePort      int           // first port, node i starts at BasePort+i*portsPerNode
+	TimeoutCommit time.Duration // consensus timeout_commit
+}
+
+// DefaultConfig returns a two node network on ports 36000 and up.
+func DefaultConfig() Config {
+	return Config{
+		NumNodes:      2,
+		ChainID:       "ultron-testnet",
+		BasePort:      36000,
+		TimeoutCommit: time.Second,
+	}
+}
+
+// Node is one validator of the network.
+type Node struct {
+	Index      int
+	Home       string
+	Config     *emtConfig.UltronConfig
+	Services   *commands.Services
+	RPCAddress string // tendermint rpc, tcp://host:port
+	EthRPCURL  string // ethereum json-rpc, http://host:port
+}
+
+// Network is a set of running nodes sharing one genesis.
+type Network struct {
+	Config Config
+	Nodes  []*Node
+
+	removeBaseDir bool
+}
+
+// New initializes the homes of all nodes and starts them.
+func New(cfg Config) (*Network, error) {
+	if cfg.NumNodes <= 0 {
+		return nil, fmt.Errorf("network needs at least one node")
+	}
+
+	net := &Network{Config: cfg}
+	if cfg.BaseDir == "" {
+		dir, err := ioutil.TempDir("", "ultron-network")
+		if err != nil {
+			return nil, err
+		}
+		net.Config.BaseDir = dir
+		net.removeBaseDir = true
+	}
+
+	confs := []*emtConfig.UltronConfig{}
+	for i := 0; i < cfg.NumNodes; i++ {
+		node, err := net.newNode(i)
+		if err != nil {
+			net.Cleanup()
+			return nil, err
+		}
+		net.Nodes = append(net.Nodes, node)
+		confs = append(confs, node.Config)
+	}
+
+	if err := commands.InitTendermintNetwork(cfg.ChainID, confs); err != nil {
+		net.Cleanup()
+		return nil, err
+	}
+
+	for _, node := range net.Nodes {
+		if err := commands.InitEthermint(node.Config); err != nil {
+			net.Cleanup()
+			return nil, err
+		}
+	}
+
+	for _, node := range net.Nodes {
+		srv, err := commands.NewServices(node.Config)
+		if err != nil {
+			net.Cleanup()
+			return nil, fmt.Errorf("start node %d: %v", node.Index, err)
+		}
+		node.Services = srv
+	}
+
+	return net, nil
+}
+
+func (net *Network) newNode(i int) (*Node, error) {
+	home := filepath.Join(net.Config.BaseDir, fmt.Sprintf("node%d", i))
+	conf, err := emtConfig.LoadConfig(home)
+	if err != nil {
+		return nil, err
+	}
+
+	port := net.Config.BasePort + i*portsPerNode
+	tmConf := &conf.TMConfig
+	tmConf.Moniker = fmt.Sprintf("node%d", i)
+	tmConf.RPC.ListenAddress = fmt.Sprintf("tcp://127.0.0.1:%d", port)
+	tmConf.P2P.ListenAddress = fmt.Sprintf("tcp://127.0.0.1:%d", port+1)
+	tmConf.P2P.AddrBookStrict = false
+	tmConf.Consensus.TimeoutCommit = int(net.Config.TimeoutCommit / time.Millisecond)
+
+	seeds := []string{}
+	for j := 0; j < net.Config.NumNodes; j++ {
+		if j != i {
+			seeds = append(seeds, fmt.Sprintf("127.0.0.1:%d", net.Config.BasePort+j*portsPerNode+1))
+		}
+	}
+	tmConf.P2P.Seeds = strings.Join(seeds, ",")
+
+	conf.EMConfig.ABCIAddr = fmt.Sprintf("tcp://127.0.0.1:%d", port+2)
+	conf.EMConfig.RPCListenAddrFlag = "127.0.0.1"
+	conf.EMConfig.RPCPortFlag = uint(port + 3)
+	conf.EMConfig.WSEnabledFlag = false
+
+	return &Node{
+		Index:      i,
+		Home:       home,
+		Config:     conf,
+		RPCAddress: tmConf.RPC.ListenAddress,
+		EthRPCURL:  fmt.Sprintf("http://127.0.0.1:%d", port+3),
+	}, nil
+}
+
+// Height returns the lowest block height among the nodes.
+func (net *Network) Height() int64 {
+	var min int64 = -1
+	for _, node := range net.Nodes {
+		if node.Services == nil {
+			return 0
+		}
+		h := node.Services.TMNode().BlockStore().Height()
+		if min < 0 || h < min {
+			min = h
+		}
+	}
+	return min
+}
+
+// WaitForHeight blocks until every node has committed height h.
+func (net *Network) WaitForHeight(h int64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for net.Height() < h {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("network stuck at height %d, waiting for %d", net.Height(), h)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return nil
+}
+
+// Cleanup stops all nodes and removes the homes it created.
+func (net *Network) Cleanup() {
+	for _, node := range net.Nodes {
+		if node.Services != nil {
+			node.Services.Stop()
+		}
+	}
+	if net.removeBaseDir {
+		os.RemoveAll(net.Config.BaseDir)
+	}
+}
@@ -0,0 +1,26 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetworkProducesBlocks(t *testing.T) {
+	net, err := New(DefaultConfig())
+	require.Nil(t, err)
+	defer net.Cleanup()
+
+	require.Nil(t, net.WaitForHeight(3, time.Minute))
+
+	// every validator committed the same block
+	hashes := map[string]bool{}
+	for _, node := range net.Nodes {
+		meta := node.Services.TMNode().BlockStore().LoadBlockMeta(3)
+		require.NotNil(t, meta)
+		hashes[string(meta.BlockID.Hash)] = true
+	}
+	assert.Equal(t, 1, len(hashes))
+}
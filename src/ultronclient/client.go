@@ -0,0 +1,267 @@
+// Package ultronclient is the Go client of an ultron node for integrators:
+// typed builders of transfers, contract calls, staking and governance txs,
+// signers, nonce management and broadcasting retried on transport errors
+// and stale nonces.
+//
+//	c, err := ultronclient.Dial("http://localhost:8545", "tcp://localhost:46657")
+//	signer := ultronclient.KeySigner(key)
+//	hash, err := c.Send(ctx, signer, ultronclient.Transfer(to, amount))
+//	receipt, err := c.WaitReceipt(ctx, hash)
+package ultronclient
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rlp"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+	tmTypes "github.com/tendermint/tendermint/types"
+
+	"github.com/dora/ultron/const"
+)
+
+// defaults of the retries of Client
+const (
+	defaultRetries    = 3
+	defaultRetryDelay = time.Second
+)
+
+// Signer signs the txs of an account.
+type Signer interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+type keySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// KeySigner signs with the private key.
+func KeySigner(key *ecdsa.PrivateKey) Signer {
+	return keySigner{key}
+}
+
+func (s keySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s keySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), s.key)
+}
+
+type keystoreSigner struct {
+	ks         *keystore.KeyStore
+	account    accounts.Account
+	passphrase string
+}
+
+// KeystoreSigner signs with the key of account kept in ks.
+func KeystoreSigner(ks *keystore.KeyStore, account common.Address, passphrase string) Signer {
+	return keystoreSigner{ks, accounts.Account{Address: account}, passphrase}
+}
+
+func (s keystoreSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s keystoreSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return s.ks.SignTxWithPassphrase(s.account, s.passphrase, tx, chainID)
+}
+
+// CheckError is the refusal of a tx by the node.
+type CheckError struct {
+	Code uint32
+	Log  string
+}
+
+func (e *CheckError) Error() string {
+	return fmt.Sprintf("tx refused (%d): %s", e.Code, e.Log)
+}
+
+func isNonceError(err error) bool {
+	e, ok := err.(*CheckError)
+	return ok && strings.Contains(strings.ToLower(e.Log), "nonce")
+}
+
+// Client sends txs to a node, reading the chain through its ethereum rpc
+// and broadcasting through tendermint, as the CLI does.
+type Client struct {
+	Eth    *ethclient.Client
+	node   rpcclient.Client
+	nonces *NonceManager
+
+	ChainID *big.Int
+	// a broadcast failing for the transport, or for a nonce the node
+	// refused, is tried again Retries times, waiting RetryDelay longer
+	// each time
+	Retries    int
+	RetryDelay time.Duration
+}
+
+// Dial connects to the ethereum rpc at ethURL and the tendermint rpc at
+// nodeURL of a node.
+func Dial(ethURL, nodeURL string) (*Client, error) {
+	eth, err := ethclient.Dial(ethURL)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(eth, rpcclient.NewHTTP(nodeURL, "/websocket")), nil
+}
+
+// NewClient creates a client over the given connections.
+func NewClient(eth *ethclient.Client, node rpcclient.Client) *Client {
+	return &Client{
+		Eth:        eth,
+		node:       node,
+		nonces:     NewNonceManager(eth.PendingNonceAt),
+		ChainID:    constant.ChainId,
+		Retries:    defaultRetries,
+		RetryDelay: defaultRetryDelay,
+	}
+}
+
+// Nonces returns the nonce manager of the client.
+func (c *Client) Nonces() *NonceManager {
+	return c.nonces
+}
+
+// Sign fills in the nonce, gas and gas price of tx left unset and signs it.
+func (c *Client) Sign(ctx context.Context, signer Signer, tx *Tx) (*types.Transaction, error) {
+	from := signer.Address()
+	nonce, err := c.nonces.Next(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+	signed, err := c.sign(ctx, signer, from, nonce, tx)
+	if err != nil {
+		c.nonces.Release(from, nonce)
+		return nil, err
+	}
+	return signed, nil
+}
+
+func (c *Client) sign(ctx context.Context, signer Signer, from common.Address, nonce uint64, tx *Tx) (*types.Transaction, error) {
+	if !tx.IsModule() {
+		filled := *tx
+		if filled.GasPrice == nil {
+			price, err := c.Eth.SuggestGasPrice(ctx)
+			if err != nil {
+				return nil, err
+			}
+			filled.GasPrice = price
+		}
+		if filled.Gas == nil {
+			gas, err := c.Eth.EstimateGas(ctx, ethereum.CallMsg{
+				From:     from,
+				To:       filled.To,
+				Value:    filled.Value,
+				Data:     filled.Data,
+				GasPrice: filled.GasPrice,
+			})
+			if err != nil {
+				return nil, err
+			}
+			filled.Gas = gas
+		}
+		tx = &filled
+	}
+
+	unsigned, err := tx.Transaction(nonce)
+	if err != nil {
+		return nil, err
+	}
+	return signer.SignTx(unsigned, c.ChainID)
+}
+
+// Send signs tx and broadcasts it, returning its hash once the node took
+// it in its mempool. A nonce the node refuses is read again from it and
+// the tx signed anew.
+func (c *Client) Send(ctx context.Context, signer Signer, tx *Tx) (common.Hash, error) {
+	from := signer.Address()
+	for attempt := 0; ; attempt++ {
+		signed, err := c.Sign(ctx, signer, tx)
+		if err != nil {
+			return common.Hash{}, err
+		}
+		err = c.Broadcast(ctx, signed)
+		if err == nil {
+			return signed.Hash(), nil
+		}
+		// the nonces handed out next may be off, whether it arrived or not
+		c.nonces.Reset(from)
+		if !isNonceError(err) || attempt >= c.Retries {
+			return common.Hash{}, err
+		}
+	}
+}
+
+// Broadcast posts the signed tx to the node, again on transport errors.
+// A tx the node already has counts as posted.
+func (c *Client) Broadcast(ctx context.Context, signed *types.Transaction) error {
+	raw, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return err
+	}
+	for try := 0; ; try++ {
+		res, err := c.node.BroadcastTxSync(raw, tmTypes.RawTx)
+		if err != nil {
+			if try >= c.Retries {
+				return err
+			}
+			select {
+			case <-time.After(time.Duration(try+1) * c.RetryDelay):
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if res.Code != 0 {
+			if strings.Contains(strings.ToLower(res.Log), "already known") {
+				return nil
+			}
+			return &CheckError{Code: uint32(res.Code), Log: res.Log}
+		}
+		return nil
+	}
+}
+
+// WaitReceipt polls the receipt of the tx hash until the tx is in a block
+// or ctx is done.
+func (c *Client) WaitReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		receipt, err := c.Eth.TransactionReceipt(ctx, hash)
+		if err == nil && receipt != nil {
+			return receipt, nil
+		}
+		if err != nil && err != ethereum.NotFound {
+			return nil, err
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// SendAndWait sends tx and waits for its receipt.
+func (c *Client) SendAndWait(ctx context.Context, signer Signer, tx *Tx) (*types.Receipt, error) {
+	hash, err := c.Send(ctx, signer, tx)
+	if err != nil {
+		return nil, err
+	}
+	return c.WaitReceipt(ctx, hash)
+}
@@ -0,0 +1,81 @@
+package ultronclient
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceManager(t *testing.T) {
+	alice := common.HexToAddress("0x1")
+	reads := 0
+	m := NewNonceManager(func(ctx context.Context, account common.Address) (uint64, error) {
+		reads++
+		return 5, nil
+	})
+	ctx := context.Background()
+
+	for i := uint64(0); i < 3; i++ {
+		nonce, err := m.Next(ctx, alice)
+		require.Nil(t, err)
+		assert.Equal(t, 5+i, nonce)
+	}
+	assert.Equal(t, 1, reads)
+
+	// only the last nonce handed out can be given back
+	m.Release(alice, 5)
+	nonce, _ := m.Next(ctx, alice)
+	assert.Equal(t, uint64(8), nonce)
+	m.Release(alice, 8)
+	nonce, _ = m.Next(ctx, alice)
+	assert.Equal(t, uint64(8), nonce)
+
+	m.Reset(alice)
+	nonce, _ = m.Next(ctx, alice)
+	assert.Equal(t, uint64(5), nonce)
+	assert.Equal(t, 2, reads)
+}
+
+func TestModuleTx(t *testing.T) {
+	validator := common.HexToAddress("0x2")
+	tx := Delegate(validator, big.NewInt(100))
+	require.True(t, tx.IsModule())
+
+	unsigned, err := tx.Transaction(7)
+	require.Nil(t, err)
+	assert.Nil(t, unsigned.To())
+	assert.Equal(t, uint64(7), unsigned.Nonce())
+	assert.Equal(t, 0, unsigned.Gas().Sign())
+	assert.Equal(t, 0, unsigned.GasPrice().Sign())
+
+	var decoded sdk.Tx
+	require.Nil(t, json.Unmarshal(unsigned.Data(), &decoded))
+	assert.NotNil(t, decoded.Unwrap())
+
+	_, err = ChangeParams().Transaction(0)
+	assert.NotNil(t, err, "no changes")
+}
+
+func TestKeySigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.Nil(t, err)
+	signer := KeySigner(key)
+
+	tx := Transfer(common.HexToAddress("0x3"), big.NewInt(1))
+	assert.False(t, tx.IsModule())
+	unsigned, err := tx.Transaction(0)
+	require.Nil(t, err)
+	assert.Equal(t, transferGas, unsigned.Gas())
+
+	chainID := big.NewInt(188)
+	signed, err := signer.SignTx(unsigned, chainID)
+	require.Nil(t, err)
+	assert.Equal(t, chainID, signed.ChainId())
+}
@@ -0,0 +1,60 @@
+package ultronclient
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceSource returns the next nonce of an account the node knows of,
+// counting its pending txs.
+type NonceSource func(ctx context.Context, account common.Address) (uint64, error)
+
+// NonceManager hands out the nonces of the accounts sending through a
+// client, so txs can be sent back to back without waiting for the pending
+// state of the node to catch up. It is safe for concurrent use.
+type NonceManager struct {
+	mtx    sync.Mutex
+	source NonceSource
+	next   map[common.Address]uint64
+}
+
+// NewNonceManager creates a nonce manager reading the first nonce of each
+// account from source.
+func NewNonceManager(source NonceSource) *NonceManager {
+	return &NonceManager{source: source, next: make(map[common.Address]uint64)}
+}
+
+// Next returns the nonce for the next tx of account.
+func (m *NonceManager) Next(ctx context.Context, account common.Address) (uint64, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	nonce, ok := m.next[account]
+	if !ok {
+		var err error
+		if nonce, err = m.source(ctx, account); err != nil {
+			return 0, err
+		}
+	}
+	m.next[account] = nonce + 1
+	return nonce, nil
+}
+
+// Release gives nonce back when its tx wasn't sent, so the next tx uses it
+// if no later nonce was handed out meanwhile.
+func (m *NonceManager) Release(account common.Address, nonce uint64) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.next[account] == nonce+1 {
+		m.next[account] = nonce
+	}
+}
+
+// Reset forgets the nonce of account, the next one is read from the node
+// again. It is called when the node refused a nonce.
+func (m *NonceManager) Reset(account common.Address) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	delete(m.next, account)
+}
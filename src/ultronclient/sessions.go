@@ -0,0 +1,195 @@
+package ultronclient
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"io/ioutil"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// KeyUnlocker decrypts the private key of account with its passphrase.
+type KeyUnlocker func(account common.Address, passphrase string) (*ecdsa.PrivateKey, error)
+
+// KeystoreUnlocker decrypts the keys kept in ks.
+func KeystoreUnlocker(ks *keystore.KeyStore) KeyUnlocker {
+	return func(account common.Address, passphrase string) (*ecdsa.PrivateKey, error) {
+		a, err := ks.Find(accounts.Account{Address: account})
+		if err != nil {
+			return nil, err
+		}
+		keyJSON, err := ioutil.ReadFile(a.URL.Path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := keystore.DecryptKey(keyJSON, passphrase)
+		if err != nil {
+			return nil, err
+		}
+		return key.PrivateKey, nil
+	}
+}
+
+// Sessions keeps the keys of the accounts unlocked for a while, so a
+// server signing many txs of the same accounts decrypts each key once
+// instead of paying for scrypt on every tx. A key is only handed out with
+// the passphrase it was unlocked with.
+//
+// Reading the unlocked keys takes no lock: they are in a map replaced as a
+// whole on each change. Unlocking an account that is being unlocked waits
+// for it instead of decrypting the key again. It is safe for concurrent
+// use.
+type Sessions struct {
+	unlock KeyUnlocker
+	ttl    time.Duration
+	max    int
+	salt   [16]byte
+	now    func() time.Time
+
+	sessions  atomic.Value // map[common.Address]*session
+	mtx       sync.Mutex   // held to replace sessions and by unlocking
+	unlocking map[common.Address]chan struct{}
+}
+
+type session struct {
+	key     *ecdsa.PrivateKey
+	auth    [sha256.Size]byte // of the passphrase
+	expires time.Time
+}
+
+// NewSessions creates sessions unlocking keys with unlock. A key stays
+// unlocked for ttl after it was unlocked, and at most max keys are, the
+// ones expiring first making room for the others; 0 for no bound.
+func NewSessions(unlock KeyUnlocker, ttl time.Duration, max int) *Sessions {
+	s := &Sessions{unlock: unlock, ttl: ttl, max: max, unlocking: make(map[common.Address]chan struct{}),
+		now: time.Now}
+	rand.Read(s.salt[:]) // nolint: errcheck
+	s.sessions.Store(make(map[common.Address]*session))
+	return s
+}
+
+func (s *Sessions) auth(passphrase string) [sha256.Size]byte {
+	return sha256.Sum256(append(s.salt[:], passphrase...))
+}
+
+// lookup returns the key of account unlocked with passphrase, if it still
+// is
+func (s *Sessions) lookup(account common.Address, passphrase string, now time.Time) *ecdsa.PrivateKey {
+	sess, ok := s.sessions.Load().(map[common.Address]*session)[account]
+	if !ok || (s.ttl > 0 && !now.Before(sess.expires)) {
+		return nil
+	}
+	auth := s.auth(passphrase)
+	if subtle.ConstantTimeCompare(sess.auth[:], auth[:]) != 1 {
+		return nil
+	}
+	return sess.key
+}
+
+// Key returns the private key of account, unlocking it with passphrase
+// unless it already is.
+func (s *Sessions) Key(account common.Address, passphrase string) (*ecdsa.PrivateKey, error) {
+	if key := s.lookup(account, passphrase, s.now()); key != nil {
+		return key, nil
+	}
+
+	s.mtx.Lock()
+	for {
+		if key := s.lookup(account, passphrase, s.now()); key != nil {
+			s.mtx.Unlock()
+			return key, nil
+		}
+		done, ok := s.unlocking[account]
+		if !ok {
+			break
+		}
+		s.mtx.Unlock()
+		<-done
+		s.mtx.Lock()
+	}
+	done := make(chan struct{})
+	s.unlocking[account] = done
+	s.mtx.Unlock()
+
+	key, err := s.unlock(account, passphrase)
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	delete(s.unlocking, account)
+	close(done)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	s.update(func(sessions map[common.Address]*session) {
+		for a, sess := range sessions {
+			if s.ttl > 0 && !now.Before(sess.expires) {
+				delete(sessions, a)
+			}
+		}
+		if s.max > 0 && len(sessions) >= s.max {
+			var first common.Address
+			found := false
+			for a, sess := range sessions {
+				if !found || sess.expires.Before(sessions[first].expires) {
+					first, found = a, true
+				}
+			}
+			delete(sessions, first)
+		}
+		sessions[account] = &session{key: key, auth: s.auth(passphrase), expires: now.Add(s.ttl)}
+	})
+	return key, nil
+}
+
+// Lock forgets the key of account.
+func (s *Sessions) Lock(account common.Address) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.update(func(sessions map[common.Address]*session) {
+		delete(sessions, account)
+	})
+}
+
+// update replaces the sessions by a copy changed by change, with s.mtx held
+func (s *Sessions) update(change func(map[common.Address]*session)) {
+	old := s.sessions.Load().(map[common.Address]*session)
+	sessions := make(map[common.Address]*session, len(old)+1)
+	for a, sess := range old {
+		sessions[a] = sess
+	}
+	change(sessions)
+	s.sessions.Store(sessions)
+}
+
+type sessionSigner struct {
+	sessions   *Sessions
+	account    common.Address
+	passphrase string
+}
+
+// Signer signs with the key of account, unlocked in s with passphrase.
+func (s *Sessions) Signer(account common.Address, passphrase string) Signer {
+	return sessionSigner{s, account, passphrase}
+}
+
+func (s sessionSigner) Address() common.Address {
+	return s.account
+}
+
+func (s sessionSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	key, err := s.sessions.Key(s.account, s.passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return types.SignTx(tx, types.NewEIP155Signer(chainID), key)
+}
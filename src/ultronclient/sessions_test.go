@@ -0,0 +1,75 @@
+package ultronclient
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessions(t *testing.T) {
+	alice, bob, carol := common.HexToAddress("0x1"), common.HexToAddress("0x2"), common.HexToAddress("0x3")
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	var mtx sync.Mutex
+	unlocks := 0
+	s := NewSessions(func(account common.Address, passphrase string) (*ecdsa.PrivateKey, error) {
+		mtx.Lock()
+		unlocks++
+		mtx.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		if passphrase != "secret" {
+			return nil, errors.New("could not decrypt key with given passphrase")
+		}
+		return key, nil
+	}, time.Minute, 2)
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	// unlocked once for all the signers at once
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			k, err := s.Key(alice, "secret")
+			assert.NoError(t, err)
+			assert.True(t, k == key)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, unlocks)
+
+	// only with its passphrase
+	_, err = s.Key(alice, "wrong")
+	assert.Error(t, err)
+	assert.Equal(t, 2, unlocks)
+	_, err = s.Key(alice, "secret")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, unlocks)
+
+	// the first to expire makes room
+	now = now.Add(time.Second)
+	s.Key(bob, "secret")   // nolint: errcheck
+	s.Key(carol, "secret") // nolint: errcheck
+	s.Key(bob, "secret")   // nolint: errcheck
+	assert.Equal(t, 4, unlocks)
+	s.Key(alice, "secret") // nolint: errcheck
+	assert.Equal(t, 5, unlocks)
+
+	// expired
+	now = now.Add(time.Minute)
+	s.Key(alice, "secret") // nolint: errcheck
+	assert.Equal(t, 6, unlocks)
+
+	s.Lock(alice)
+	s.Key(alice, "secret") // nolint: errcheck
+	assert.Equal(t, 7, unlocks)
+}
@@ -0,0 +1,148 @@
+package ultronclient
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/cosmos/cosmos-sdk"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/dora/ultron/modules/community"
+	"github.com/dora/ultron/modules/params"
+	"github.com/dora/ultron/modules/stake"
+	"github.com/dora/ultron/modules/upgrade"
+)
+
+// transferGas is the gas of a plain transfer
+var transferGas = big.NewInt(21000)
+
+// Tx is a transaction to build, Client.Send fills in what is left unset:
+// the nonce, the gas price and the gas limit.
+type Tx struct {
+	To       *common.Address
+	Value    *big.Int
+	Data     []byte
+	Gas      *big.Int
+	GasPrice *big.Int
+
+	// module txs carry the json of a module tx and no gas, value or
+	// recipient, they are handled by the modules, not the evm
+	module sdk.Tx
+}
+
+// Transfer sends amount, in wei, to to.
+func Transfer(to common.Address, amount *big.Int) *Tx {
+	return &Tx{To: &to, Value: amount, Gas: transferGas}
+}
+
+// CallContract calls contract with data, see Pack.
+func CallContract(contract common.Address, data []byte) *Tx {
+	return &Tx{To: &contract, Value: new(big.Int), Data: data}
+}
+
+// DeployContract creates a contract running the init code.
+func DeployContract(code []byte) *Tx {
+	return &Tx{Value: new(big.Int), Data: code}
+}
+
+// Pack encodes the call of method with args for the contract of the json
+// abi.
+func Pack(abiJSON, method string, args ...interface{}) ([]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Pack(method, args...)
+}
+
+// Module sends the module tx, such as the ones of stake or params.
+func Module(tx sdk.Tx) *Tx {
+	return &Tx{module: tx}
+}
+
+// Delegate stakes amount with the validator.
+func Delegate(validator common.Address, amount *big.Int) *Tx {
+	return Module(stake.NewTxDelegate(validator, amount.String()))
+}
+
+// Withdraw takes back amount delegated to the validator.
+func Withdraw(validator common.Address, amount *big.Int) *Tx {
+	return Module(stake.NewTxWithdraw(validator, amount.String()))
+}
+
+// Redelegate moves amount delegated to from over to to.
+func Redelegate(from, to common.Address, amount *big.Int) *Tx {
+	return Module(stake.NewTxRedelegate(from, to, amount.String()))
+}
+
+// Unjail lets the sending validator validate again after it was jailed.
+func Unjail() *Tx {
+	return Module(stake.NewTxUnjail())
+}
+
+// ChangeParams changes chain parameters, it must be sent by the params
+// authority.
+func ChangeParams(changes ...params.ParamChange) *Tx {
+	return Module(params.NewTxChangeParams(changes...))
+}
+
+// ScheduleUpgrade schedules the upgrade name at height, it must be sent by
+// the params authority.
+func ScheduleUpgrade(name string, height int64, info string) *Tx {
+	return Module(upgrade.NewTxSchedule(name, height, info))
+}
+
+// CancelUpgrade cancels the scheduled upgrade, it must be sent by the params
+// authority.
+func CancelUpgrade() *Tx {
+	return Module(upgrade.NewTxCancel())
+}
+
+// ProposeSpend pays amount, in wei, out of the community pool to recipient
+// at height, it must be sent by the params authority.
+func ProposeSpend(recipient common.Address, amount *big.Int, height int64, reason string) *Tx {
+	return Module(community.NewTxSpend(recipient, amount.String(), height, reason))
+}
+
+// CancelSpend drops the pending community spend id, it must be sent by the
+// params authority.
+func CancelSpend(id uint64) *Tx {
+	return Module(community.NewTxCancelSpend(id))
+}
+
+// IsModule tells whether tx is handled by a module.
+func (tx *Tx) IsModule() bool {
+	return tx.module.Unwrap() != nil
+}
+
+// Transaction returns the unsigned ethereum tx of tx with nonce. Gas and gas
+// price left unset are zero.
+func (tx *Tx) Transaction(nonce uint64) (*types.Transaction, error) {
+	if tx.IsModule() {
+		if err := tx.module.ValidateBasic(); err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(tx.module)
+		if err != nil {
+			return nil, err
+		}
+		zero := new(big.Int)
+		return types.NewContractCreation(nonce, zero, zero, zero, data), nil
+	}
+
+	value, gas, gasPrice := orZero(tx.Value), orZero(tx.Gas), orZero(tx.GasPrice)
+	if tx.To == nil {
+		return types.NewContractCreation(nonce, value, gas, gasPrice, tx.Data), nil
+	}
+	return types.NewTransaction(nonce, *tx.To, value, gas, gasPrice, tx.Data), nil
+}
+
+func orZero(i *big.Int) *big.Int {
+	if i == nil {
+		return new(big.Int)
+	}
+	return i
+}
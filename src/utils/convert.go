@@ -6,6 +6,7 @@ import (
 	"github.com/tendermint/go-crypto"
 	"math/big"
 	"strconv"
+	"strings"
 )
 
 func RemoveFromSlice(slice []interface{}, i int) []interface{} {
@@ -70,6 +71,31 @@ func ToWei(value int64) (result *big.Int) {
 	return
 }
 
+// amountUnits are the decimals of the units ParseAmount takes
+var amountUnits = map[string]int64{"wei": 0, "gwei": 9, "ether": 18}
+
+// ParseAmount parses an amount of wei, plain or in a unit: "1.5ether",
+// "20gwei", "100wei" or "100". It fails on negative amounts and on ones
+// smaller than a wei.
+func ParseAmount(str string) (*big.Int, error) {
+	str = strings.ToLower(strings.TrimSpace(str))
+	number := strings.TrimRightFunc(str, func(r rune) bool { return r >= 'a' && r <= 'z' })
+	decimals, ok := amountUnits[strings.TrimSpace(str[len(number):])]
+	if !ok && len(number) < len(str) {
+		return nil, fmt.Errorf("unknown unit of amount %q, want wei, gwei or ether", str)
+	}
+
+	value, ok := new(big.Rat).SetString(strings.TrimSpace(number))
+	if !ok || number == "" || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid amount %q", str)
+	}
+	value.Mul(value, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(decimals), nil)))
+	if !value.IsInt() {
+		return nil, fmt.Errorf("amount %q is a fraction of a wei", str)
+	}
+	return value.Num(), nil
+}
+
 func PubKeyString(pk crypto.PubKey) string {
 	switch pki := pk.PubKeyInner.(type) {
 	case crypto.PubKeyEd25519:
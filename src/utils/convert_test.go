@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"100", "100"},
+		{"100wei", "100"},
+		{"20gwei", "20000000000"},
+		{"1.5ether", "1500000000000000000"},
+		{" 2 Ether ", "2000000000000000000"},
+		{"0.000000001gwei", "1"},
+	}
+	for _, c := range cases {
+		amount, err := ParseAmount(c.in)
+		if assert.NoError(t, err, c.in) {
+			assert.Equal(t, c.want, amount.String(), c.in)
+		}
+	}
+
+	for _, in := range []string{"", "ether", "-1", "1.5", "0.1wei", "1eth"} {
+		_, err := ParseAmount(in)
+		assert.Error(t, err, in)
+	}
+}
@@ -7,4 +7,14 @@ const Fix = "0"
 var (
 	// Version is the current version
 	Version = "0.1.0"
+
+	// Commit is the git commit the binary was built from, set by make build
+	// with -ldflags "-X github.com/dora/ultron/version.Commit=<commit>"
+	Commit = ""
 )
+
+// Protocol versions the rules the blocks are run with and the messages the
+// nodes exchange, as <major>.<minor>. The major changes with the app hash
+// of the blocks or the messages peers must understand, nodes of different
+// majors don't peer, see peerversion.
+const Protocol = "1.0"